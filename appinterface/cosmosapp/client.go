@@ -56,6 +56,11 @@ type Client interface {
 	BlockInfo(height string) (*BlockInfo, error)
 }
 
+// TxClient queries the transactions, it is the only query needed to sync blocks
+type TxClient interface {
+	Tx(txHash string) (*model.Tx, error)
+}
+
 var ErrAccountNotFound = errors.New("account not found")
 var ErrAccountNoDelegation = errors.New("account has no delegation")
 var ErrProposalNotFound = errors.New("proposal not found")
@@ -0,0 +1,292 @@
+package event
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+)
+
+const DEFAULT_ARCHIVES_TABLE = "event_archives"
+
+// Archives table should have the following schema
+// | Field       | Data Type | Constraint  |
+// | ----------- | --------- | ----------- |
+// | from_height | INT64     | PRIMARY KEY |
+// | to_height   | INT64     | NOT NULL    |
+// | file        | VARCHAR   | NOT NULL    |
+// | event_count | INT64     | NOT NULL    |
+// | archived_at | INT64     | NOT NULL    |
+
+// EventsArchive is a partition of the events table covering heights within [FromHeight, ToHeight)
+// which has been moved to a gzip compressed JSON lines file
+type EventsArchive struct {
+	FromHeight int64
+	ToHeight   int64
+	File       string
+	EventCount int64
+	ArchivedAt utctime.UTCTime
+}
+
+type archivedEvent struct {
+	Id      int64           `json:"id"`
+	UUID    string          `json:"uuid"`
+	Height  int64           `json:"height"`
+	Name    string          `json:"name"`
+	Version int             `json:"version"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// ArchivePartition writes all events of the partition to a compressed file in the directory and
+// drops the partition. The archive is recorded before the partition is dropped, such that the
+// events are never lost when archiving stops half way. Archiving a partition again overwrites its
+// previous archive.
+func (store *RDbStore) ArchivePartition(partition EventsPartition, dir string) (*EventsArchive, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating events archive directory: %v", err)
+	}
+	file := filepath.Join(dir, fmt.Sprintf("%s_%d_%d.jsonl.gz", store.table, partition.FromHeight, partition.ToHeight))
+
+	eventCount, err := store.exportPartition(partition, file)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := EventsArchive{
+		FromHeight: partition.FromHeight,
+		ToHeight:   partition.ToHeight,
+		File:       file,
+		EventCount: eventCount,
+		ArchivedAt: utctime.Now(),
+	}
+	sql, args, err := store.rdbHandle.StmtBuilder.Insert(
+		store.archivesTable,
+	).Columns(
+		"from_height", "to_height", "file", "event_count", "archived_at",
+	).Values(
+		archive.FromHeight, archive.ToHeight, archive.File, archive.EventCount, archive.ArchivedAt.UnixNano(),
+	).Suffix(
+		"ON CONFLICT (from_height) DO UPDATE SET " +
+			"to_height = EXCLUDED.to_height, file = EXCLUDED.file, " +
+			"event_count = EXCLUDED.event_count, archived_at = EXCLUDED.archived_at",
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building events archive insertion SQL: %v", err)
+	}
+	if _, err = store.rdbHandle.Exec(sql, args...); err != nil {
+		return nil, fmt.Errorf("error executing events archive insertion SQL: %v", err)
+	}
+
+	if _, err = store.rdbHandle.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", partition.Table)); err != nil {
+		return nil, fmt.Errorf("error dropping events partition %s: %v", partition.Table, err)
+	}
+
+	return &archive, nil
+}
+
+// exportPartition writes all events of the partition ordered by height to the file. The file is
+// only replaced once completely written.
+func (store *RDbStore) exportPartition(partition EventsPartition, file string) (int64, error) {
+	sql, args, err := store.rdbHandle.StmtBuilder.Select(
+		"id", "uuid", "height", "name", "version", "payload",
+	).From(
+		partition.Table,
+	).OrderBy("height", "id").ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error building events partition selection SQL: %v", err)
+	}
+	rows, err := store.rdbHandle.Query(sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("error executing events partition selection SQL: %v", err)
+	}
+	defer rows.Close()
+
+	tmpFile := file + ".tmp"
+	f, err := os.Create(tmpFile)
+	if err != nil {
+		return 0, fmt.Errorf("error creating events archive file: %v", err)
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(tmpFile)
+	}()
+
+	gzipWriter := gzip.NewWriter(f)
+	encoder := json.NewEncoder(gzipWriter)
+	eventCount := int64(0)
+	for rows.Next() {
+		var (
+			event   archivedEvent
+			payload string
+		)
+		if err = rows.Scan(&event.Id, &event.UUID, &event.Height, &event.Name, &event.Version, &payload); err != nil {
+			return 0, fmt.Errorf("error scanning event row: %v", err)
+		}
+		event.Payload = json.RawMessage(payload)
+
+		if err = encoder.Encode(&event); err != nil {
+			return 0, fmt.Errorf("error writing event to archive file: %v", err)
+		}
+		eventCount += 1
+	}
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating events partition rows: %v", err)
+	}
+
+	if err = gzipWriter.Close(); err != nil {
+		return 0, fmt.Errorf("error flushing events archive file: %v", err)
+	}
+	if err = f.Sync(); err != nil {
+		return 0, fmt.Errorf("error syncing events archive file: %v", err)
+	}
+	if err = os.Rename(tmpFile, file); err != nil {
+		return 0, fmt.Errorf("error renaming events archive file: %v", err)
+	}
+
+	return eventCount, nil
+}
+
+// restoreArchives restores the archives overlapping [fromHeight, toHeight] back into the events
+// table. It returns whether any archive has been restored.
+func (store *RDbStore) restoreArchives(fromHeight int64, toHeight int64) (bool, error) {
+	store.restoreMutex.Lock()
+	defer store.restoreMutex.Unlock()
+
+	archives, err := store.getArchivesByHeightRange(fromHeight, toHeight)
+	if err != nil {
+		return false, err
+	}
+	for _, archive := range archives {
+		if err = store.restoreArchive(archive); err != nil {
+			return false, fmt.Errorf("error restoring events archive %s: %v", archive.File, err)
+		}
+	}
+
+	return len(archives) > 0, nil
+}
+
+func (store *RDbStore) getArchivesByHeightRange(fromHeight int64, toHeight int64) ([]EventsArchive, error) {
+	sql, args, err := store.rdbHandle.StmtBuilder.Select(
+		"from_height", "to_height", "file", "event_count", "archived_at",
+	).From(
+		store.archivesTable,
+	).Where(
+		"from_height <= ? AND to_height > ?", toHeight, fromHeight,
+	).OrderBy("from_height").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building events archives selection SQL: %v", err)
+	}
+
+	rows, err := store.rdbHandle.Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing events archives selection SQL: %v", err)
+	}
+	defer rows.Close()
+
+	archives := make([]EventsArchive, 0)
+	for rows.Next() {
+		var archive EventsArchive
+		var archivedAt int64
+		if err = rows.Scan(
+			&archive.FromHeight, &archive.ToHeight, &archive.File, &archive.EventCount, &archivedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning events archive row: %v", err)
+		}
+		archive.ArchivedAt = utctime.FromUnixNano(archivedAt)
+
+		archives = append(archives, archive)
+	}
+
+	return archives, nil
+}
+
+// restoreArchive inserts the archived events back into a new partition and removes the archive
+// record. Events already restored are skipped, such that a failed restoration is simply retried.
+// The archive file is kept for the next archiving of the partition.
+func (store *RDbStore) restoreArchive(archive EventsArchive) error {
+	partition := store.partitionOf(archive.FromHeight)
+	if err := store.createPartition(partition); err != nil {
+		return err
+	}
+
+	f, err := os.Open(archive.File)
+	if err != nil {
+		return fmt.Errorf("error opening archive file: %v", err)
+	}
+	defer f.Close()
+	gzipReader, err := gzip.NewReader(bufio.NewReader(f))
+	if err != nil {
+		return fmt.Errorf("error reading archive file: %v", err)
+	}
+	defer gzipReader.Close()
+
+	decoder := json.NewDecoder(gzipReader)
+	pendingEvents := make([]archivedEvent, 0)
+	for {
+		var event archivedEvent
+		err = decoder.Decode(&event)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error decoding archived event: %v", err)
+		}
+
+		pendingEvents = append(pendingEvents, event)
+		if len(pendingEvents) == 500 {
+			if err = store.insertArchivedEvents(pendingEvents); err != nil {
+				return err
+			}
+			pendingEvents = pendingEvents[:0]
+		}
+	}
+	if err = store.insertArchivedEvents(pendingEvents); err != nil {
+		return err
+	}
+
+	sql, args, err := store.rdbHandle.StmtBuilder.Delete(
+		store.archivesTable,
+	).Where(
+		"from_height = ?", archive.FromHeight,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building events archive deletion SQL: %v", err)
+	}
+	if _, err = store.rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing events archive deletion SQL: %v", err)
+	}
+
+	return nil
+}
+
+func (store *RDbStore) insertArchivedEvents(events []archivedEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	stmtBuilder := store.rdbHandle.StmtBuilder.Insert(
+		store.table,
+	).Columns(
+		"id", "uuid", "height", "name", "version", "payload",
+	)
+	for _, event := range events {
+		stmtBuilder = stmtBuilder.Values(
+			event.Id, event.UUID, event.Height, event.Name, event.Version, string(event.Payload),
+		)
+	}
+	sql, args, err := stmtBuilder.Suffix("ON CONFLICT DO NOTHING").ToSql()
+	if err != nil {
+		return fmt.Errorf("error building archived events insertion SQL: %v", err)
+	}
+	if _, err = store.rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing archived events insertion SQL: %v", err)
+	}
+
+	return nil
+}
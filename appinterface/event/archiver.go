@@ -0,0 +1,112 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+)
+
+const DEFAULT_ARCHIVER_RETAINED_HEIGHTS = EVENTS_PARTITION_SIZE
+const DEFAULT_ARCHIVER_INTERVAL = time.Hour
+
+// HandledHeightReporter reports the lowest last handled event height among the consumers of the
+// event store, nil when any of them has not handled an event yet
+type HandledHeightReporter interface {
+	GetLowestLastHandledEventHeight() (*int64, error)
+}
+
+type ArchiverConfig struct {
+	// Directory to write the archive files to
+	Dir string
+	// Number of heights kept in the event store behind the lowest last handled event height
+	RetainedHeights int64
+	Interval        time.Duration
+}
+
+// Archiver moves the partitions of the event store which have been passed by all the consumers to
+// compressed files. Dropping whole partitions also reclaims their disk space immediately, unlike
+// deleting the events.
+type Archiver struct {
+	logger applogger.Logger
+
+	store    *RDbStore
+	reporter HandledHeightReporter
+
+	config ArchiverConfig
+}
+
+func NewArchiver(
+	logger applogger.Logger,
+	store *RDbStore,
+	reporter HandledHeightReporter,
+	config ArchiverConfig,
+) *Archiver {
+	if config.RetainedHeights <= 0 {
+		config.RetainedHeights = DEFAULT_ARCHIVER_RETAINED_HEIGHTS
+	}
+	if config.Interval <= 0 {
+		config.Interval = DEFAULT_ARCHIVER_INTERVAL
+	}
+
+	return &Archiver{
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "EventsArchiver",
+		}),
+
+		store:    store,
+		reporter: reporter,
+
+		config: config,
+	}
+}
+
+// Run archives the passed partitions every interval until the context is done
+func (archiver *Archiver) Run(ctx context.Context) {
+	for {
+		if err := archiver.ArchivePassedPartitions(); err != nil {
+			archiver.logger.Errorf("error archiving events partitions: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(archiver.config.Interval):
+		}
+	}
+}
+
+// ArchivePassedPartitions archives all partitions whose heights are all below the lowest last
+// handled event height by more than the retained heights
+func (archiver *Archiver) ArchivePassedPartitions() error {
+	lowestHandledHeight, err := archiver.reporter.GetLowestLastHandledEventHeight()
+	if err != nil {
+		return fmt.Errorf("error getting lowest last handled event height: %v", err)
+	}
+	if lowestHandledHeight == nil {
+		return nil
+	}
+	archivableToHeight := *lowestHandledHeight - archiver.config.RetainedHeights + 1
+
+	partitions, err := archiver.store.ListPartitions()
+	if err != nil {
+		return err
+	}
+	for _, partition := range partitions {
+		if partition.ToHeight > archivableToHeight {
+			break
+		}
+
+		archive, err := archiver.store.ArchivePartition(partition, archiver.config.Dir)
+		if err != nil {
+			return fmt.Errorf("error archiving events partition %s: %v", partition.Table, err)
+		}
+		archiver.logger.Infof(
+			"successfully archived %d events from height %d to %d into %s",
+			archive.EventCount, archive.FromHeight, archive.ToHeight-1, archive.File,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package event
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EVENTS_PARTITION_SIZE is the number of heights covered by a partition of the events table. It
+// must match the partition size of the migration partitioning the existing events.
+const EVENTS_PARTITION_SIZE = int64(100000)
+
+// EventsPartition is a partition of the events table covering heights within [FromHeight, ToHeight)
+type EventsPartition struct {
+	Table      string
+	FromHeight int64
+	ToHeight   int64
+}
+
+func (store *RDbStore) partitionOf(height int64) EventsPartition {
+	fromHeight := height - height%EVENTS_PARTITION_SIZE
+	return EventsPartition{
+		Table:      fmt.Sprintf("%s_p%d", store.table, fromHeight),
+		FromHeight: fromHeight,
+		ToHeight:   fromHeight + EVENTS_PARTITION_SIZE,
+	}
+}
+
+// ensurePartition creates the partition of the height unless it has been ensured before. It runs
+// outside of the transaction inserting the events, such that a rolled back insertion never leaves
+// the partition cache out of sync with the database.
+func (store *RDbStore) ensurePartition(height int64) error {
+	store.partitionMutex.Lock()
+	defer store.partitionMutex.Unlock()
+
+	if height < store.partitionedToHeight && height >= store.partitionedFromHeight {
+		return nil
+	}
+
+	partition := store.partitionOf(height)
+	if err := store.createPartition(partition); err != nil {
+		return err
+	}
+	store.partitionedFromHeight = partition.FromHeight
+	store.partitionedToHeight = partition.ToHeight
+	return nil
+}
+
+func (store *RDbStore) createPartition(partition EventsPartition) error {
+	sql := fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM (%d) TO (%d)",
+		partition.Table, store.table, partition.FromHeight, partition.ToHeight,
+	)
+	if _, err := store.rdbHandle.Exec(sql); err != nil {
+		return fmt.Errorf("error creating events partition %s: %v", partition.Table, err)
+	}
+	return nil
+}
+
+// ListPartitions returns all the partitions of the events table in the database ordered by height
+func (store *RDbStore) ListPartitions() ([]EventsPartition, error) {
+	sql, args, err := store.rdbHandle.StmtBuilder.Select(
+		"child.relname",
+	).From(
+		"pg_inherits",
+	).Join(
+		"pg_class parent ON pg_inherits.inhparent = parent.oid",
+	).Join(
+		"pg_class child ON pg_inherits.inhrelid = child.oid",
+	).Where(
+		"parent.relname = ?", store.table,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building events partitions selection SQL: %v", err)
+	}
+
+	rows, err := store.rdbHandle.Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing events partitions selection SQL: %v", err)
+	}
+	defer rows.Close()
+
+	partitions := make([]EventsPartition, 0)
+	partitionPrefix := store.table + "_p"
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, fmt.Errorf("error scanning events partition row: %v", err)
+		}
+
+		if !strings.HasPrefix(table, partitionPrefix) {
+			continue
+		}
+		var fromHeight int64
+		if _, err := fmt.Sscanf(strings.TrimPrefix(table, partitionPrefix), "%d", &fromHeight); err != nil {
+			continue
+		}
+		partitions = append(partitions, store.partitionOf(fromHeight))
+	}
+
+	sort.Slice(partitions, func(i, j int) bool {
+		return partitions[i].FromHeight < partitions[j].FromHeight
+	})
+	return partitions, nil
+}
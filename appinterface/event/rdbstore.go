@@ -3,6 +3,7 @@ package event
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	sq "github.com/Masterminds/squirrel"
 
@@ -20,6 +21,8 @@ const DEFAULT_TABLE = "events"
 // | name    | VARCHAR   | NOT NULL    |
 // | version | INT64     | NOT NULL    |
 // | payload | JSONB     | NOT NULL    |
+// The table is partitioned by ranges of EVENTS_PARTITION_SIZE heights. Partitions passed by all
+// projections may be archived to files, they are restored on demand when the events are read.
 
 var _ entity_event.Store = &RDbStore{}
 
@@ -28,7 +31,15 @@ type RDbStore struct {
 	rdbHandle *rdb.Handle
 	Registry  *entity_event.Registry
 
-	table string
+	table         string
+	archivesTable string
+
+	// Heights within [partitionedFromHeight, partitionedToHeight) have a partition to insert into
+	partitionMutex        sync.Mutex
+	partitionedFromHeight int64
+	partitionedToHeight   int64
+
+	restoreMutex sync.Mutex
 }
 
 func NewRDbStore(handle *rdb.Handle, registry *entity_event.Registry) *RDbStore {
@@ -36,7 +47,8 @@ func NewRDbStore(handle *rdb.Handle, registry *entity_event.Registry) *RDbStore
 		rdbHandle: handle,
 		Registry:  registry,
 
-		table: DEFAULT_TABLE,
+		table:         DEFAULT_TABLE,
+		archivesTable: DEFAULT_ARCHIVES_TABLE,
 	}
 }
 
@@ -75,10 +87,64 @@ func (store *RDbStore) GetAllByHeight(height int64) ([]entity_event.Event, error
 		return nil, fmt.Errorf("error building get all events by height selection SQL: %v", err)
 	}
 
-	rows, err := store.rdbHandle.Query(sql, args...)
+	events, err := store.queryEvents(sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error executing get all events by height selection SQL: %v", err)
 	}
+	if len(events) > 0 {
+		return events, nil
+	}
+
+	if restored, err := store.restoreArchives(height, height); err != nil {
+		return nil, err
+	} else if !restored {
+		return events, nil
+	}
+	if events, err = store.queryEvents(sql, args...); err != nil {
+		return nil, fmt.Errorf("error executing get all events by height selection SQL: %v", err)
+	}
+	return events, nil
+}
+
+// GetAllByHeightRange returns all events with height within [fromHeight, toHeight] ordered by height
+func (store *RDbStore) GetAllByHeightRange(fromHeight int64, toHeight int64) ([]entity_event.Event, error) {
+	sql, args, err := store.rdbHandle.StmtBuilder.Select(
+		"uuid", "height", "name", "version", "payload",
+	).From(
+		store.table,
+	).Where(
+		"height >= ? AND height <= ?", fromHeight, toHeight,
+	).OrderBy("height", "id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building get all events by height range selection SQL: %v", err)
+	}
+
+	events, err := store.queryEvents(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing get all events by height range selection SQL: %v", err)
+	}
+	// Archived partitions are always older than the stored ones
+	if len(events) > 0 && events[0].Height() == fromHeight {
+		return events, nil
+	}
+
+	if restored, err := store.restoreArchives(fromHeight, toHeight); err != nil {
+		return nil, err
+	} else if !restored {
+		return events, nil
+	}
+	if events, err = store.queryEvents(sql, args...); err != nil {
+		return nil, fmt.Errorf("error executing get all events by height range selection SQL: %v", err)
+	}
+	return events, nil
+}
+
+// queryEvents executes the events selection SQL and decodes the selected events
+func (store *RDbStore) queryEvents(sql string, args ...interface{}) ([]entity_event.Event, error) {
+	rows, err := store.rdbHandle.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
 	events := make([]entity_event.Event, 0)
@@ -95,7 +161,7 @@ func (store *RDbStore) GetAllByHeight(height int64) ([]entity_event.Event, error
 			if errors.Is(err, rdb.ErrNoRows) {
 				return nil, nil
 			} else {
-				return nil, fmt.Errorf("error executing get each event by height selection SQL: %v", err)
+				return nil, fmt.Errorf("error scanning event row: %v", err)
 			}
 		}
 
@@ -111,6 +177,10 @@ func (store *RDbStore) GetAllByHeight(height int64) ([]entity_event.Event, error
 }
 
 func (store *RDbStore) Insert(event entity_event.Event) error {
+	if err := store.ensurePartition(event.Height()); err != nil {
+		return err
+	}
+
 	encodedEvent, err := event.ToJSON()
 	if err != nil {
 		return fmt.Errorf("error encoding event to json: %v", err)
@@ -151,6 +221,11 @@ func (store *RDbStore) InsertAllWithRDbHandle(rdbHandle *rdb.Handle, events []en
 	if len(events) == 0 {
 		return nil
 	}
+	for _, event := range events {
+		if err := store.ensurePartition(event.Height()); err != nil {
+			return err
+		}
+	}
 
 	pendingRowCount := 0
 	var stmtBuilder sq.InsertBuilder
@@ -196,3 +271,26 @@ func (store *RDbStore) InsertAllWithRDbHandle(rdbHandle *rdb.Handle, events []en
 
 	return nil
 }
+
+// DeleteAllByHeightRangeWithRDbHandle removes all events with height within [fromHeight, toHeight]
+func (store *RDbStore) DeleteAllByHeightRangeWithRDbHandle(
+	rdbHandle *rdb.Handle,
+	fromHeight int64,
+	toHeight int64,
+) (int64, error) {
+	sql, args, err := rdbHandle.StmtBuilder.Delete(
+		store.table,
+	).Where(
+		"height >= ? AND height <= ?", fromHeight, toHeight,
+	).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error building events deletion SQL: %v", err)
+	}
+
+	execResult, err := rdbHandle.Exec(sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("error executing events deletion SQL: %v", err)
+	}
+
+	return execResult.RowsAffected(), nil
+}
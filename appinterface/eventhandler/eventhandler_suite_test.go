@@ -0,0 +1,13 @@
+package eventhandler_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestEventHandler(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "EventHandler Suite")
+}
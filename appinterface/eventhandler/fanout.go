@@ -0,0 +1,313 @@
+package eventhandler
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/AstraProtocol/astra-indexing/entity/event"
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/metric/prometheus"
+)
+
+var _ RollbackableHandler = &FanOutHandler{}
+
+// FanOutHandler hands the events of every synchronized block to all of its handlers in parallel,
+// such that blocks are fetched and parsed once for all of them. Every handler keeps its own last
+// handled height and only receives the heights above it. A DependentHandler receives a height after
+// the attached handlers it depends on have handled it, and fails it when any of them has failed.
+//
+// A handler failing to handle a height is detached, such that it does not hold back the others.
+// The detached handler is reported to `onDetach`, which is responsible to catch it up and add it
+// back with `Join`. `onDetach` is called while handling the events, so it must not block.
+type FanOutHandler struct {
+	logger applogger.Logger
+
+	mutex    sync.Mutex
+	handlers []Handler
+	// Last handled height of each handler, -1 when none is handled
+	handlerHeights map[string]int64
+	// Last height handed over to the handlers, nil when none is handed over
+	lastHandledHeight *int64
+
+	onDetach func(handler Handler, err error)
+}
+
+func NewFanOutHandler(
+	logger applogger.Logger,
+	lastHandledHeight *int64,
+	onDetach func(handler Handler, err error),
+) *FanOutHandler {
+	return &FanOutHandler{
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "FanOutHandler",
+		}),
+
+		handlers:       make([]Handler, 0),
+		handlerHeights: make(map[string]int64),
+
+		lastHandledHeight: lastHandledHeight,
+
+		onDetach: onDetach,
+	}
+}
+
+// Join adds the handler which has handled all heights up to `handledHeight`. It only joins when it
+// has not fallen behind the heights handed over so far, and reports whether it has joined.
+func (handler *FanOutHandler) Join(joiningHandler Handler, handledHeight *int64) bool {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	height := int64(-1)
+	if handledHeight != nil {
+		height = *handledHeight
+	}
+	if handler.lastHandledHeight != nil && height < *handler.lastHandledHeight {
+		return false
+	}
+
+	handler.handlers = append(handler.handlers, joiningHandler)
+	handler.handlerHeights[joiningHandler.Id()] = height
+	handler.logger.Infof("handler `%s` joined at height %d", joiningHandler.Id(), height)
+	return true
+}
+
+// Handlers returns the ids of the attached handlers
+func (handler *FanOutHandler) Handlers() []string {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	ids := make([]string, 0, len(handler.handlers))
+	for _, attachedHandler := range handler.handlers {
+		ids = append(ids, attachedHandler.Id())
+	}
+	return ids
+}
+
+func (handler *FanOutHandler) GetLastHandledEventHeight() (*int64, error) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	return handler.lastHandledHeight, nil
+}
+
+func (handler *FanOutHandler) HandleEvents(blockHeight int64, events []event.Event) error {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	errs := make([]error, len(handler.handlers))
+	pending := make(map[string]int)
+	for i, attachedHandler := range handler.handlers {
+		if handler.handlerHeights[attachedHandler.Id()] < blockHeight {
+			pending[attachedHandler.Id()] = i
+		}
+	}
+	// Handlers run in waves, a handler is only run after the attached handlers it depends on
+	for len(pending) > 0 {
+		ready := make([]int, 0, len(pending))
+		for _, i := range pending {
+			if dependencyErr := handler.dependencyError(handler.handlers[i], pending, errs); dependencyErr != nil {
+				errs[i] = dependencyErr
+				ready = append(ready, i)
+			} else if !hasPendingDependency(handler.handlers[i], pending) {
+				ready = append(ready, i)
+			}
+		}
+		if len(ready) == 0 {
+			// Dependency cycles are rejected on start, run the remaining handlers regardless
+			for _, i := range pending {
+				ready = append(ready, i)
+			}
+		}
+
+		var wg sync.WaitGroup
+		for _, i := range ready {
+			delete(pending, handler.handlers[i].Id())
+			if errs[i] != nil {
+				continue
+			}
+
+			wg.Add(1)
+			go func(i int, attachedHandler Handler) {
+				defer wg.Done()
+				errs[i] = attachedHandler.HandleEvents(blockHeight, events)
+			}(i, handler.handlers[i])
+		}
+		wg.Wait()
+	}
+
+	attachedHandlers := make([]Handler, 0, len(handler.handlers))
+	for i, attachedHandler := range handler.handlers {
+		if errs[i] != nil {
+			handler.detach(attachedHandler, errs[i])
+			continue
+		}
+
+		attachedHandlers = append(attachedHandlers, attachedHandler)
+		if handler.handlerHeights[attachedHandler.Id()] < blockHeight {
+			handler.handlerHeights[attachedHandler.Id()] = blockHeight
+			prometheus.RecordProjectionLatestHeight(attachedHandler.Id(), blockHeight)
+		}
+	}
+	handler.handlers = attachedHandlers
+	handler.lastHandledHeight = &blockHeight
+
+	return nil
+}
+
+// Rollback rolls back every attached handler which has handled heights within [fromHeight, toHeight].
+// A handler rewound further than `fromHeight - 1`, e.g. a projection opting in to be reset from the
+// genesis, is detached to catch up on its own.
+func (handler *FanOutHandler) Rollback(fromHeight int64, toHeight int64) error {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	rewoundHandlers := make(map[string]error)
+	for _, attachedHandler := range handler.handlers {
+		handlerHeight := handler.handlerHeights[attachedHandler.Id()]
+		if handlerHeight < fromHeight {
+			continue
+		}
+
+		rollbackableHandler, ok := attachedHandler.(RollbackableHandler)
+		if !ok {
+			return fmt.Errorf("handler `%s` does not support rollback", attachedHandler.Id())
+		}
+		if err := rollbackableHandler.Rollback(fromHeight, toHeight); err != nil {
+			return fmt.Errorf("error rolling back handler `%s`: %v", attachedHandler.Id(), err)
+		}
+
+		rewoundHeight, err := attachedHandler.GetLastHandledEventHeight()
+		if err != nil {
+			rewoundHandlers[attachedHandler.Id()] = fmt.Errorf("error getting last handled event height: %v", err)
+			continue
+		}
+		if rewoundHeight == nil || *rewoundHeight < fromHeight-1 {
+			rewoundHandlers[attachedHandler.Id()] = fmt.Errorf("handler is rewound to before height %d", fromHeight)
+			continue
+		}
+		handler.handlerHeights[attachedHandler.Id()] = fromHeight - 1
+	}
+	if len(rewoundHandlers) > 0 {
+		attachedHandlers := make([]Handler, 0, len(handler.handlers))
+		for _, attachedHandler := range handler.handlers {
+			if err, isRewound := rewoundHandlers[attachedHandler.Id()]; isRewound {
+				handler.detach(attachedHandler, err)
+				continue
+			}
+			attachedHandlers = append(attachedHandlers, attachedHandler)
+		}
+		handler.handlers = attachedHandlers
+	}
+	if handler.lastHandledHeight != nil && *handler.lastHandledHeight >= fromHeight {
+		if fromHeight <= 0 {
+			handler.lastHandledHeight = nil
+		} else {
+			lastHandledHeight := fromHeight - 1
+			handler.lastHandledHeight = &lastHandledHeight
+		}
+	}
+
+	return nil
+}
+
+func (handler *FanOutHandler) Id() string {
+	return "FanOutHandler"
+}
+
+// dependencyError returns an error when an attached handler the handler depends on has failed the
+// current height, the caller must hold the mutex
+func (handler *FanOutHandler) dependencyError(attachedHandler Handler, pending map[string]int, errs []error) error {
+	dependentHandler, ok := attachedHandler.(DependentHandler)
+	if !ok {
+		return nil
+	}
+	for _, dependencyId := range dependentHandler.GetDependencies() {
+		if _, isPending := pending[dependencyId]; isPending {
+			continue
+		}
+		for i, dependency := range handler.handlers {
+			if dependency.Id() == dependencyId && errs[i] != nil {
+				return fmt.Errorf("handler `%s` it depends on has failed", dependencyId)
+			}
+		}
+	}
+	return nil
+}
+
+func hasPendingDependency(attachedHandler Handler, pending map[string]int) bool {
+	dependentHandler, ok := attachedHandler.(DependentHandler)
+	if !ok {
+		return false
+	}
+	for _, dependencyId := range dependentHandler.GetDependencies() {
+		if _, isPending := pending[dependencyId]; isPending {
+			return true
+		}
+	}
+	return false
+}
+
+// detach removes the handler from the attached handlers, the caller must hold the mutex
+func (handler *FanOutHandler) detach(detachedHandler Handler, err error) {
+	delete(handler.handlerHeights, detachedHandler.Id())
+	handler.logger.Errorf("detaching handler `%s` after error: %v", detachedHandler.Id(), err)
+	if handler.onDetach != nil {
+		handler.onDetach(detachedHandler, err)
+	}
+}
+
+var _ RollbackableHandler = &CatchUpHandler{}
+
+// CatchUpHandler handles the events of a handler which is synchronized on its own until it catches
+// up with a FanOutHandler. After every handled height it tries to join the FanOutHandler, and calls
+// `onJoined` once joined. The synchronization must then stop handing over heights to it.
+type CatchUpHandler struct {
+	handler  Handler
+	fanOut   *FanOutHandler
+	onJoined func()
+
+	mutex  sync.Mutex
+	joined bool
+}
+
+func NewCatchUpHandler(handler Handler, fanOut *FanOutHandler, onJoined func()) *CatchUpHandler {
+	return &CatchUpHandler{
+		handler:  handler,
+		fanOut:   fanOut,
+		onJoined: onJoined,
+	}
+}
+
+func (handler *CatchUpHandler) GetLastHandledEventHeight() (*int64, error) {
+	return handler.handler.GetLastHandledEventHeight()
+}
+
+func (handler *CatchUpHandler) HandleEvents(blockHeight int64, events []event.Event) error {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if handler.joined {
+		return fmt.Errorf("handler `%s` has joined the fan-out handler", handler.Id())
+	}
+	if err := handler.handler.HandleEvents(blockHeight, events); err != nil {
+		return err
+	}
+
+	if handler.fanOut.Join(handler.handler, &blockHeight) {
+		handler.joined = true
+		handler.onJoined()
+	}
+	return nil
+}
+
+func (handler *CatchUpHandler) Rollback(fromHeight int64, toHeight int64) error {
+	rollbackableHandler, ok := handler.handler.(RollbackableHandler)
+	if !ok {
+		return fmt.Errorf("handler `%s` does not support rollback", handler.Id())
+	}
+	return rollbackableHandler.Rollback(fromHeight, toHeight)
+}
+
+func (handler *CatchUpHandler) Id() string {
+	return handler.handler.Id()
+}
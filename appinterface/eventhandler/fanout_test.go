@@ -0,0 +1,218 @@
+package eventhandler_test
+
+import (
+	"errors"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/eventhandler"
+	"github.com/AstraProtocol/astra-indexing/entity/event"
+	"github.com/AstraProtocol/astra-indexing/external/logger/test"
+	"github.com/AstraProtocol/astra-indexing/external/primptr"
+)
+
+var _ = Describe("FanOutHandler", func() {
+	It("should hand over every height to the handlers which have not handled it", func() {
+		fanOutHandler := eventhandler.NewFanOutHandler(test.NewFakeLogger(), primptr.Int64(9), nil)
+		laggingHandler := newFakeHandler("Lagging", nil)
+		leadingHandler := newFakeHandler("Leading", nil)
+		Expect(fanOutHandler.Join(laggingHandler, primptr.Int64(9))).To(BeTrue())
+		Expect(fanOutHandler.Join(leadingHandler, primptr.Int64(10))).To(BeTrue())
+
+		Expect(fanOutHandler.HandleEvents(10, []event.Event{})).To(Succeed())
+		Expect(fanOutHandler.HandleEvents(11, []event.Event{})).To(Succeed())
+
+		Expect(laggingHandler.HandledHeights()).To(Equal([]int64{10, 11}))
+		Expect(leadingHandler.HandledHeights()).To(Equal([]int64{11}))
+		Expect(fanOutHandler.GetLastHandledEventHeight()).To(Equal(primptr.Int64(11)))
+	})
+
+	It("should only let handlers join when they have not fallen behind", func() {
+		fanOutHandler := eventhandler.NewFanOutHandler(test.NewFakeLogger(), primptr.Int64(9), nil)
+
+		Expect(fanOutHandler.Join(newFakeHandler("Behind", nil), primptr.Int64(8))).To(BeFalse())
+		Expect(fanOutHandler.Join(newFakeHandler("New", nil), nil)).To(BeFalse())
+		Expect(fanOutHandler.Join(newFakeHandler("CaughtUp", nil), primptr.Int64(9))).To(BeTrue())
+		Expect(fanOutHandler.Handlers()).To(Equal([]string{"CaughtUp"}))
+	})
+
+	It("should detach failing handlers without holding back the others", func() {
+		detachedHandlers := make([]string, 0)
+		fanOutHandler := eventhandler.NewFanOutHandler(
+			test.NewFakeLogger(),
+			nil,
+			func(handler eventhandler.Handler, _ error) {
+				detachedHandlers = append(detachedHandlers, handler.Id())
+			},
+		)
+		failingHandler := newFakeHandler("Failing", errors.New("failed"))
+		healthyHandler := newFakeHandler("Healthy", nil)
+		Expect(fanOutHandler.Join(failingHandler, nil)).To(BeTrue())
+		Expect(fanOutHandler.Join(healthyHandler, nil)).To(BeTrue())
+
+		Expect(fanOutHandler.HandleEvents(0, []event.Event{})).To(Succeed())
+		Expect(fanOutHandler.HandleEvents(1, []event.Event{})).To(Succeed())
+
+		Expect(detachedHandlers).To(Equal([]string{"Failing"}))
+		Expect(fanOutHandler.Handlers()).To(Equal([]string{"Healthy"}))
+		Expect(failingHandler.HandledHeights()).To(Equal([]int64{0}))
+		Expect(healthyHandler.HandledHeights()).To(Equal([]int64{0, 1}))
+	})
+
+	It("should hand over heights to dependent handlers after their dependencies", func() {
+		detachedHandlers := make([]string, 0)
+		fanOutHandler := eventhandler.NewFanOutHandler(
+			test.NewFakeLogger(),
+			nil,
+			func(handler eventhandler.Handler, _ error) {
+				detachedHandlers = append(detachedHandlers, handler.Id())
+			},
+		)
+		dependency := newFakeHandler("Dependency", nil)
+		dependentHandler := &fakeDependentHandler{newFakeHandler("Dependent", nil), dependency}
+		Expect(fanOutHandler.Join(dependentHandler, nil)).To(BeTrue())
+		Expect(fanOutHandler.Join(dependency, nil)).To(BeTrue())
+
+		Expect(fanOutHandler.HandleEvents(0, []event.Event{})).To(Succeed())
+		Expect(dependentHandler.HandledHeights()).To(Equal([]int64{0}))
+
+		dependency.err = errors.New("failed")
+		Expect(fanOutHandler.HandleEvents(1, []event.Event{})).To(Succeed())
+		Expect(detachedHandlers).To(ConsistOf("Dependency", "Dependent"))
+		Expect(dependentHandler.HandledHeights()).To(Equal([]int64{0}))
+	})
+
+	It("should detach the handlers rewound further than the rolled back heights", func() {
+		detachedHandlers := make([]string, 0)
+		fanOutHandler := eventhandler.NewFanOutHandler(
+			test.NewFakeLogger(),
+			primptr.Int64(12),
+			func(handler eventhandler.Handler, _ error) {
+				detachedHandlers = append(detachedHandlers, handler.Id())
+			},
+		)
+		rolledBackHandler := &fakeRollbackableHandler{newFakeHandler("RolledBack", nil), false}
+		resetHandler := &fakeRollbackableHandler{newFakeHandler("Reset", nil), true}
+		for height := int64(0); height <= 12; height += 1 {
+			Expect(rolledBackHandler.HandleEvents(height, []event.Event{})).To(Succeed())
+			Expect(resetHandler.HandleEvents(height, []event.Event{})).To(Succeed())
+		}
+		Expect(fanOutHandler.Join(rolledBackHandler, primptr.Int64(12))).To(BeTrue())
+		Expect(fanOutHandler.Join(resetHandler, primptr.Int64(12))).To(BeTrue())
+
+		Expect(fanOutHandler.Rollback(10, 12)).To(Succeed())
+		Expect(detachedHandlers).To(Equal([]string{"Reset"}))
+		Expect(fanOutHandler.Handlers()).To(Equal([]string{"RolledBack"}))
+		Expect(fanOutHandler.GetLastHandledEventHeight()).To(Equal(primptr.Int64(9)))
+
+		Expect(fanOutHandler.HandleEvents(10, []event.Event{})).To(Succeed())
+		Expect(rolledBackHandler.HandledHeights()).To(HaveLen(11))
+		Expect(resetHandler.HandledHeights()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("CatchUpHandler", func() {
+	It("should join the fan-out handler once it has caught up", func() {
+		fanOutHandler := eventhandler.NewFanOutHandler(test.NewFakeLogger(), primptr.Int64(5), nil)
+		handler := newFakeHandler("CatchingUp", nil)
+		joined := false
+		catchUpHandler := eventhandler.NewCatchUpHandler(handler, fanOutHandler, func() {
+			joined = true
+		})
+
+		Expect(catchUpHandler.HandleEvents(4, []event.Event{})).To(Succeed())
+		Expect(joined).To(BeFalse())
+		Expect(catchUpHandler.HandleEvents(5, []event.Event{})).To(Succeed())
+		Expect(joined).To(BeTrue())
+		Expect(fanOutHandler.Handlers()).To(Equal([]string{"CatchingUp"}))
+
+		Expect(fanOutHandler.HandleEvents(6, []event.Event{})).To(Succeed())
+		Expect(handler.HandledHeights()).To(Equal([]int64{4, 5, 6}))
+	})
+})
+
+type fakeHandler struct {
+	id  string
+	err error
+
+	mutex          sync.Mutex
+	handledHeights []int64
+}
+
+func newFakeHandler(id string, err error) *fakeHandler {
+	return &fakeHandler{
+		id:  id,
+		err: err,
+
+		handledHeights: make([]int64, 0),
+	}
+}
+
+func (handler *fakeHandler) GetLastHandledEventHeight() (*int64, error) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if len(handler.handledHeights) == 0 {
+		return nil, nil
+	}
+	return primptr.Int64(handler.handledHeights[len(handler.handledHeights)-1]), nil
+}
+
+func (handler *fakeHandler) HandleEvents(blockHeight int64, _ []event.Event) error {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.handledHeights = append(handler.handledHeights, blockHeight)
+	return handler.err
+}
+
+func (handler *fakeHandler) Id() string {
+	return handler.id
+}
+
+func (handler *fakeHandler) HandledHeights() []int64 {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	return handler.handledHeights
+}
+
+// fakeDependentHandler fails heights its dependency has not handled yet
+type fakeDependentHandler struct {
+	*fakeHandler
+	dependency *fakeHandler
+}
+
+func (handler *fakeDependentHandler) GetDependencies() []string {
+	return []string{handler.dependency.Id()}
+}
+
+func (handler *fakeDependentHandler) HandleEvents(blockHeight int64, events []event.Event) error {
+	dependencyHeight, _ := handler.dependency.GetLastHandledEventHeight()
+	if dependencyHeight == nil || *dependencyHeight < blockHeight {
+		return errors.New("dependency has not handled the height")
+	}
+	return handler.fakeHandler.HandleEvents(blockHeight, events)
+}
+
+// fakeRollbackableHandler forgets the rolled back heights, or all of them when it resets
+type fakeRollbackableHandler struct {
+	*fakeHandler
+	isReset bool
+}
+
+func (handler *fakeRollbackableHandler) Rollback(fromHeight int64, _ int64) error {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	keptHeights := make([]int64, 0)
+	for _, height := range handler.handledHeights {
+		if !handler.isReset && height < fromHeight {
+			keptHeights = append(keptHeights, height)
+		}
+	}
+	handler.handledHeights = keptHeights
+	return nil
+}
@@ -1,6 +1,11 @@
 package eventhandler
 
-import "github.com/AstraProtocol/astra-indexing/entity/event"
+import (
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/entity/event"
+)
 
 type Handler interface {
 	GetLastHandledEventHeight() (*int64, error)
@@ -9,3 +14,59 @@ type Handler interface {
 
 	Id() string
 }
+
+// DependentHandler is a Handler which must not handle a height before the handlers it depends on
+// have handled it
+type DependentHandler interface {
+	Handler
+
+	// Returns the ids of the handlers it depends on
+	GetDependencies() []string
+}
+
+// TransactionalHandler is a Handler handling the events of a height in a single DB transaction, in
+// which the caller is able to write its own outcomes of the height, e.g. the indexed block hash
+type TransactionalHandler interface {
+	Handler
+
+	// HandleEventsWithinTx handles the events like HandleEvents, and calls `withinTx` with the
+	// transaction handle before committing it
+	HandleEventsWithinTx(blockHeight int64, events []event.Event, withinTx func(txHandle *rdb.Handle) error) error
+}
+
+// HandleEventsWithin hands the events to the handler and writes the caller outcomes of the height
+// with `within`. A TransactionalHandler writes them in the transaction handling the events. Other
+// handlers have them written before handling the events, such that a handled height never misses
+// them, and a failed height has them overwritten once handled again.
+func HandleEventsWithin(
+	handler Handler,
+	rdbHandle *rdb.Handle,
+	blockHeight int64,
+	events []event.Event,
+	within func(rdbHandle *rdb.Handle) error,
+) error {
+	if transactionalHandler, ok := handler.(TransactionalHandler); ok {
+		return transactionalHandler.HandleEventsWithinTx(blockHeight, events, within)
+	}
+
+	if err := within(rdbHandle); err != nil {
+		return fmt.Errorf("error writing outcomes of height %d: %v", blockHeight, err)
+	}
+	return handler.HandleEvents(blockHeight, events)
+}
+
+// RollbackableHandler is a Handler which is able to undo the events it has handled when the chain
+// reorganizes
+type RollbackableHandler interface {
+	Handler
+
+	// Rollback undoes all the handled events within [fromHeight, toHeight] and resets the last
+	// handled event height to `fromHeight - 1`
+	Rollback(fromHeight int64, toHeight int64) error
+}
+
+// Rollbacker is anything that keeps outcomes derived from handled events and has to be rolled back
+// together with the handler
+type Rollbacker interface {
+	Rollback(fromHeight int64, toHeight int64) error
+}
@@ -0,0 +1,185 @@
+package eventhandler
+
+import (
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbprojectionbase"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/entity/event"
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+)
+
+const KAFKA_SINK_ID = "KafkaSink"
+
+// EventPublisher publishes the events of a height downstream
+type EventPublisher interface {
+	// Publish returns once all the events of the height have been delivered
+	Publish(blockHeight int64, events []event.Event) error
+}
+
+var _ RollbackableHandler = &KafkaSinkHandler{}
+var _ TransactionalHandler = &KafkaSinkHandler{}
+
+// KafkaSinkHandler hands the events of every height to the handler it wraps, e.g. the
+// RDbEventStoreHandler, and publishes them afterwards. The last published height is recorded as a
+// projection, such that the heights not published yet are synchronized again and published at
+// least once after a failure or a restart. It starts publishing from the height of the wrapped
+// handler when no height has been published yet.
+type KafkaSinkHandler struct {
+	logger  applogger.Logger
+	rdbConn rdb.Conn
+
+	handler   Handler
+	publisher EventPublisher
+	base      *rdbprojectionbase.Base
+
+	// Cached last published height, loaded on first use
+	isPublishedHeightLoaded bool
+	publishedHeight         *int64
+}
+
+func NewKafkaSinkHandler(
+	logger applogger.Logger,
+	rdbConn rdb.Conn,
+	handler Handler,
+	publisher EventPublisher,
+) *KafkaSinkHandler {
+	return &KafkaSinkHandler{
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "KafkaSinkHandler",
+		}),
+		rdbConn: rdbConn,
+
+		handler:   handler,
+		publisher: publisher,
+		base:      rdbprojectionbase.NewRDbBase(rdbConn.ToHandle(), KAFKA_SINK_ID),
+	}
+}
+
+// GetLastHandledEventHeight returns the lower height among the wrapped handler and the publishing
+func (handler *KafkaSinkHandler) GetLastHandledEventHeight() (*int64, error) {
+	handledHeight, err := handler.handler.GetLastHandledEventHeight()
+	if err != nil {
+		return nil, err
+	}
+	publishedHeight, err := handler.getPublishedHeight(handledHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	if handledHeight == nil || publishedHeight == nil {
+		return nil, nil
+	}
+	if *publishedHeight < *handledHeight {
+		return publishedHeight, nil
+	}
+	return handledHeight, nil
+}
+
+func (handler *KafkaSinkHandler) HandleEvents(blockHeight int64, events []event.Event) error {
+	return handler.HandleEventsWithinTx(blockHeight, events, nil)
+}
+
+// HandleEventsWithinTx writes the outcomes of `withinTx` together with the wrapped handler, see
+// HandleEventsWithin. They are not written again for the heights synchronized again for the
+// publishing only.
+func (handler *KafkaSinkHandler) HandleEventsWithinTx(
+	blockHeight int64,
+	events []event.Event,
+	withinTx func(txHandle *rdb.Handle) error,
+) error {
+	handledHeight, err := handler.handler.GetLastHandledEventHeight()
+	if err != nil {
+		return err
+	}
+	// Heights synchronized again for the publishing only are not handed to the wrapped handler
+	if handledHeight == nil || *handledHeight < blockHeight {
+		if withinTx == nil {
+			err = handler.handler.HandleEvents(blockHeight, events)
+		} else {
+			err = HandleEventsWithin(handler.handler, handler.rdbConn.ToHandle(), blockHeight, events, withinTx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	publishedHeight, err := handler.getPublishedHeight(handledHeight)
+	if err != nil {
+		return err
+	}
+	if publishedHeight != nil && *publishedHeight >= blockHeight {
+		return nil
+	}
+	if err = handler.publisher.Publish(blockHeight, events); err != nil {
+		return fmt.Errorf("error publishing events of height %d: %v", blockHeight, err)
+	}
+	if err = handler.base.UpdateLastHandledEventHeight(handler.rdbConn.ToHandle(), blockHeight); err != nil {
+		return fmt.Errorf("error updating last published height to %d: %v", blockHeight, err)
+	}
+	handler.publishedHeight = &blockHeight
+
+	return nil
+}
+
+// Rollback rolls back the wrapped handler and rewinds the last published height, such that the
+// heights are published again once synchronized again. The events already published are not
+// retracted.
+func (handler *KafkaSinkHandler) Rollback(fromHeight int64, toHeight int64) error {
+	rollbackableHandler, ok := handler.handler.(RollbackableHandler)
+	if !ok {
+		return fmt.Errorf("handler `%s` does not support rollback", handler.handler.Id())
+	}
+	if err := rollbackableHandler.Rollback(fromHeight, toHeight); err != nil {
+		return err
+	}
+
+	publishedHeight, err := handler.getPublishedHeight(nil)
+	if err != nil {
+		return err
+	}
+	if publishedHeight == nil || *publishedHeight < fromHeight {
+		return nil
+	}
+	if err = handler.base.RewindLastHandledEventHeight(handler.rdbConn.ToHandle(), fromHeight); err != nil {
+		return fmt.Errorf("error rewinding last published height to %d: %v", fromHeight-1, err)
+	}
+	if fromHeight <= 0 {
+		handler.publishedHeight = nil
+	} else {
+		rewoundHeight := fromHeight - 1
+		handler.publishedHeight = &rewoundHeight
+	}
+	handler.logger.Infof("rewound publishing to height %d", fromHeight-1)
+
+	return nil
+}
+
+// Id keeps the id of the wrapped handler, such that the block hashes recorded for it stay valid
+func (handler *KafkaSinkHandler) Id() string {
+	return handler.handler.Id()
+}
+
+// getPublishedHeight returns the last published height. The first time no height has been
+// published, it starts publishing from `handledHeight` of the wrapped handler.
+func (handler *KafkaSinkHandler) getPublishedHeight(handledHeight *int64) (*int64, error) {
+	if handler.isPublishedHeightLoaded {
+		return handler.publishedHeight, nil
+	}
+
+	publishedHeight, err := handler.base.GetLastHandledEventHeight()
+	if err != nil {
+		return nil, fmt.Errorf("error getting last published height: %v", err)
+	}
+	if publishedHeight == nil && handledHeight != nil {
+		if err = handler.base.UpdateLastHandledEventHeight(handler.rdbConn.ToHandle(), *handledHeight); err != nil {
+			return nil, fmt.Errorf("error initializing last published height to %d: %v", *handledHeight, err)
+		}
+		handler.logger.Infof("start publishing after height %d", *handledHeight)
+		publishedHeight = handledHeight
+	}
+
+	handler.isPublishedHeightLoaded = true
+	handler.publishedHeight = publishedHeight
+	return publishedHeight, nil
+}
@@ -8,17 +8,31 @@ import (
 	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
 )
 
-var _ Handler = &ProjectionHandler{}
+var _ RollbackableHandler = &ProjectionHandler{}
+var _ DependentHandler = &ProjectionHandler{}
 
 type ProjectionHandler struct {
 	logger     applogger.Logger
 	projection projection_entity.Projection
+	// Projections the projection depends on, which must have handled a height before it
+	dependencies []projection_entity.Projection
 }
 
 func NewProjectionHandler(logger applogger.Logger, projection projection_entity.Projection) *ProjectionHandler {
+	return NewProjectionHandlerWithDependencies(logger, projection, nil)
+}
+
+// NewProjectionHandlerWithDependencies creates a handler refusing to handle a height until all the
+// dependencies have handled it
+func NewProjectionHandlerWithDependencies(
+	logger applogger.Logger,
+	projection projection_entity.Projection,
+	dependencies []projection_entity.Projection,
+) *ProjectionHandler {
 	return &ProjectionHandler{
 		logger,
 		projection,
+		dependencies,
 	}
 }
 
@@ -31,6 +45,16 @@ func (handler *ProjectionHandler) HandleEvents(blockHeight int64, events []event
 		"height": blockHeight,
 	})
 
+	for _, dependency := range handler.dependencies {
+		lastHandledEventHeight, err := dependency.GetLastHandledEventHeight()
+		if err != nil {
+			return fmt.Errorf("error getting last handled event height of projection `%s`: %v", dependency.Id(), err)
+		}
+		if lastHandledEventHeight == nil || *lastHandledEventHeight < blockHeight {
+			return fmt.Errorf("projection `%s` has not handled height %d yet", dependency.Id(), blockHeight)
+		}
+	}
+
 	filteredEvents := make([]event.Event, 0)
 	for _, event := range events {
 		if !isListeningEvent(event, handler.projection.GetEventsToListen()) {
@@ -53,6 +77,26 @@ func (handler *ProjectionHandler) HandleEvents(blockHeight int64, events []event
 	return nil
 }
 
+func (handler *ProjectionHandler) GetDependencies() []string {
+	return projection_entity.GetDependencies(handler.projection)
+}
+
+// Rollback rolls back the projection, or resets it to be rebuilt from the genesis when it does not
+// support rollback but opts in to be reset. The reset projection then has to catch up on its own.
+func (handler *ProjectionHandler) Rollback(fromHeight int64, toHeight int64) error {
+	isReset, err := projection_entity.RollbackOrReset(handler.projection, fromHeight, toHeight)
+	if err != nil {
+		return fmt.Errorf("error rolling back projection from height %d to %d: %v", fromHeight, toHeight, err)
+	}
+
+	if isReset {
+		handler.logger.Infof("projection does not support rollback, reset it to be rebuilt from the genesis as it opts in")
+		return nil
+	}
+	handler.logger.Infof("successfully rolled back projection from height %d to %d", fromHeight, toHeight)
+	return nil
+}
+
 func (handler *ProjectionHandler) Id() string {
 	return handler.projection.Id()
 }
@@ -10,7 +10,8 @@ import (
 	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
 )
 
-var _ Handler = &RDbEventStoreHandler{}
+var _ RollbackableHandler = &RDbEventStoreHandler{}
+var _ TransactionalHandler = &RDbEventStoreHandler{}
 
 // RDbEventStoreHandler is an event handler which persist the event to event store
 type RDbEventStoreHandler struct {
@@ -19,6 +20,9 @@ type RDbEventStoreHandler struct {
 
 	eventStore  *event_interface.RDbStore
 	statusStore *rdbstatusstore.RDbStatusStore
+
+	// outcomes derived from the stored events, e.g. projections replaying the event store
+	rollbackDependents []Rollbacker
 }
 
 func NewRDbEventStoreHandler(
@@ -35,19 +39,40 @@ func NewRDbEventStoreHandler(
 
 		eventStore:  initEventStore(rdbHandle, eventRegistry),
 		statusStore: initStatusStore(rdbHandle),
+
+		rollbackDependents: make([]Rollbacker, 0),
 	}
 }
 
+// AddRollbackDependent registers a dependent to be rolled back before the stored events are removed
+func (handler *RDbEventStoreHandler) AddRollbackDependent(dependent Rollbacker) {
+	handler.rollbackDependents = append(handler.rollbackDependents, dependent)
+}
+
 func (handler *RDbEventStoreHandler) GetLastHandledEventHeight() (*int64, error) {
 	return handler.statusStore.GetLastIndexedBlockHeight()
 }
 
 func (handler *RDbEventStoreHandler) HandleEvents(blockHeight int64, events []event.Event) error {
+	return handler.HandleEventsWithinTx(blockHeight, events, nil)
+}
+
+func (handler *RDbEventStoreHandler) HandleEventsWithinTx(
+	blockHeight int64,
+	events []event.Event,
+	withinTx func(txHandle *rdb.Handle) error,
+) error {
 	handler.logger.Debug("start persisting blocks events")
 	tx, err := handler.rdbConn.Begin()
 	if err != nil {
 		return fmt.Errorf("error when beginning transaction: %v", err)
 	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
 	txHandle := tx.ToHandle()
 
 	if err := handler.eventStore.InsertAllWithRDbHandle(txHandle, events); err != nil {
@@ -58,9 +83,61 @@ func (handler *RDbEventStoreHandler) HandleEvents(blockHeight int64, events []ev
 		return fmt.Errorf("error updating last indexed block height to %d: %v", blockHeight, err)
 	}
 
+	if withinTx != nil {
+		if err := withinTx(txHandle); err != nil {
+			return fmt.Errorf("error writing outcomes of height %d: %v", blockHeight, err)
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("error committing block synchronization outcomes: %v", err)
 	}
+	committed = true
+	return nil
+}
+
+// Rollback removes the stored events within [fromHeight, toHeight] after rolling back all the
+// dependents. A failure in between is recovered by rolling back again, because every step is
+// idempotent.
+func (handler *RDbEventStoreHandler) Rollback(fromHeight int64, toHeight int64) error {
+	for _, dependent := range handler.rollbackDependents {
+		if err := dependent.Rollback(fromHeight, toHeight); err != nil {
+			return fmt.Errorf("error rolling back dependent: %v", err)
+		}
+	}
+
+	tx, err := handler.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error when beginning transaction: %v", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+	txHandle := tx.ToHandle()
+
+	deletedCount, err := handler.eventStore.DeleteAllByHeightRangeWithRDbHandle(txHandle, fromHeight, toHeight)
+	if err != nil {
+		return fmt.Errorf("error deleting events from height %d to %d: %v", fromHeight, toHeight, err)
+	}
+
+	if fromHeight <= 0 {
+		err = handler.statusStore.ResetLastIndexedBlockHeightWithRDbHandle(txHandle)
+	} else {
+		err = handler.statusStore.UpdateLastIndexedBlockHeightWithRDbHandle(txHandle, fromHeight-1)
+	}
+	if err != nil {
+		return fmt.Errorf("error rewinding last indexed block height to %d: %v", fromHeight-1, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing rollback outcomes: %v", err)
+	}
+	committed = true
+
+	handler.logger.Infof("successfully removed %d events from height %d to %d", deletedCount, fromHeight, toHeight)
 	return nil
 }
 
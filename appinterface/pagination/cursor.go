@@ -0,0 +1,65 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points at a record of a list ordered by a keyset, e.g. (height, index), with the values of
+// the keyset of that record. Clients receive it encoded as an opaque string.
+type Cursor struct {
+	Keys []interface{} `json:"k"`
+	// The page is the records before the cursor, otherwise after
+	Backward bool `json:"b,omitempty"`
+}
+
+func NewCursor(keys []interface{}, backward bool) *Cursor {
+	return &Cursor{
+		Keys:     keys,
+		Backward: backward,
+	}
+}
+
+func (cursor *Cursor) Encode() string {
+	// The keys are strings and numbers of the rows, always encodable
+	encoded, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// DecodeCursor decodes a cursor encoded by Encode. The integer keys are decoded as int64 without loss
+// of precision.
+func DecodeCursor(encoded string) (*Cursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(decoded))
+	decoder.UseNumber()
+	var cursor Cursor
+	if err = decoder.Decode(&cursor); err != nil || len(cursor.Keys) == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	for i, key := range cursor.Keys {
+		switch typedKey := key.(type) {
+		case json.Number:
+			if integer, intErr := typedKey.Int64(); intErr == nil {
+				cursor.Keys[i] = integer
+			} else if float, floatErr := typedKey.Float64(); floatErr == nil {
+				cursor.Keys[i] = float
+			} else {
+				return nil, ErrInvalidCursor
+			}
+		case string, bool:
+		default:
+			return nil, fmt.Errorf("%w: unsupported key %v", ErrInvalidCursor, key)
+		}
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,34 @@
+package pagination_test
+
+import (
+	"encoding/base64"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+)
+
+var _ = Describe("Cursor", func() {
+	It("should decode the keys of an encoded cursor", func() {
+		cursor := pagination.NewCursor([]interface{}{int64(9007199254740993), "astra1address", 1.5}, true)
+
+		decoded, err := pagination.DecodeCursor(cursor.Encode())
+
+		Expect(err).To(BeNil())
+		Expect(decoded).To(Equal(cursor))
+	})
+
+	It("should reject the malformed cursors", func() {
+		for _, encoded := range []string{
+			"not base64!",
+			base64.RawURLEncoding.EncodeToString([]byte("not JSON")),
+			base64.RawURLEncoding.EncodeToString([]byte(`{"k":[]}`)),
+			base64.RawURLEncoding.EncodeToString([]byte(`{"k":[{"nested":true}]}`)),
+		} {
+			_, err := pagination.DecodeCursor(encoded)
+
+			Expect(err).To(MatchError(pagination.ErrInvalidCursor))
+		}
+	})
+})
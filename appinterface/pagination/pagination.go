@@ -1,11 +1,15 @@
 package pagination
 
-import "math"
+import (
+	"fmt"
+	"math"
+)
 
 // Pagination stores pagination request data
 type Pagination struct {
 	t            string
 	offsetParams PaginationOffsetParams
+	cursorParams PaginationCursorParams
 }
 
 func NewOffsetPagination(page int64, limit int64) *Pagination {
@@ -19,6 +23,18 @@ func NewOffsetPagination(page int64, limit int64) *Pagination {
 	}
 }
 
+// NewCursorPagination creates a pagination continuing from the cursor, nil for the first page
+func NewCursorPagination(cursor *Cursor, limit int64) *Pagination {
+	return &Pagination{
+		t: PAGINATION_CURSOR,
+
+		cursorParams: PaginationCursorParams{
+			Cursor: cursor,
+			Limit:  limit,
+		},
+	}
+}
+
 func (pagination *Pagination) Type() string {
 	return pagination.t
 }
@@ -30,10 +46,24 @@ func (pagination *Pagination) OffsetParams() *PaginationOffsetParams {
 	return &pagination.offsetParams
 }
 
-// TODO: Cursor pagination
-// func (pagination *Pagination) CursorParams() *PaginationCursorParams {
-// 	return &pagination.cursorParams
-// }
+func (pagination *Pagination) CursorParams() *PaginationCursorParams {
+	if pagination.Type() != PAGINATION_CURSOR {
+		return nil
+	}
+	return &pagination.cursorParams
+}
+
+// Key identifies the page requested, e.g. in cache keys
+func (pagination *Pagination) Key() string {
+	if pagination.Type() == PAGINATION_CURSOR {
+		cursor := ""
+		if pagination.cursorParams.Cursor != nil {
+			cursor = pagination.cursorParams.Cursor.Encode()
+		}
+		return fmt.Sprintf("%s_%s_%d", PAGINATION_CURSOR, cursor, pagination.cursorParams.Limit)
+	}
+	return fmt.Sprintf("%d_%d", pagination.offsetParams.Page, pagination.offsetParams.Limit)
+}
 
 func (pagination *Pagination) OffsetResult(totalRecord int64) *Result {
 	if pagination.Type() != PAGINATION_OFFSET {
@@ -46,6 +76,15 @@ func (pagination *Pagination) OffsetResult(totalRecord int64) *Result {
 	)
 }
 
+// CursorResult returns the result of a page of the cursor pagination from the keys of its first and
+// last records, nil when there is no page before or after
+func (pagination *Pagination) CursorResult(prevKeys []interface{}, nextKeys []interface{}) *Result {
+	if pagination.Type() != PAGINATION_CURSOR {
+		return nil
+	}
+	return NewCursorPaginationResult(prevKeys, nextKeys, pagination.cursorParams.Limit)
+}
+
 type PaginationOffsetParams struct {
 	Page  int64
 	Limit int64
@@ -55,10 +94,17 @@ func (params *PaginationOffsetParams) Offset() int64 {
 	return params.Limit * (params.Page - 1)
 }
 
+type PaginationCursorParams struct {
+	// Cursor of the last record of the previous page, nil for the first page
+	Cursor *Cursor
+	Limit  int64
+}
+
 type Result struct {
 	T string `json:"t"`
 
 	Por OffsetResult `json:"pagination_offset_result"`
+	Pcr CursorResult `json:"pagination_cursor_result"`
 }
 
 func NewOffsetPaginationResult(totalRecord int64, currentPage int64, limit int64) *Result {
@@ -73,6 +119,23 @@ func NewOffsetPaginationResult(totalRecord int64, currentPage int64, limit int64
 	}
 }
 
+func NewCursorPaginationResult(prevKeys []interface{}, nextKeys []interface{}, limit int64) *Result {
+	result := &Result{
+		T: PAGINATION_CURSOR,
+
+		Pcr: CursorResult{
+			Limit: limit,
+		},
+	}
+	if prevKeys != nil {
+		result.Pcr.PrevCursor = NewCursor(prevKeys, true).Encode()
+	}
+	if nextKeys != nil {
+		result.Pcr.NextCursor = NewCursor(nextKeys, false).Encode()
+	}
+	return result
+}
+
 func (result *Result) Type() string {
 	return result.T
 }
@@ -84,6 +147,13 @@ func (result *Result) OffsetResult() *OffsetResult {
 	return &result.Por
 }
 
+func (result *Result) CursorResult() *CursorResult {
+	if result.Type() != PAGINATION_CURSOR {
+		return nil
+	}
+	return &result.Pcr
+}
+
 type OffsetResult struct {
 	TotalRecord int64 `json:"total_record"`
 	CurrentPage int64 `json:"current_page"`
@@ -94,9 +164,17 @@ func (result *OffsetResult) TotalPage() int64 {
 	return int64(math.Ceil(float64(result.TotalRecord) / float64(result.Limit)))
 }
 
+// CursorResult holds the opaque cursors of the pages around the current page, empty when there is
+// no such page
+type CursorResult struct {
+	PrevCursor string `json:"prev_cursor"`
+	NextCursor string `json:"next_cursor"`
+	Limit      int64  `json:"limit"`
+}
+
 const (
 	PAGINATION_OFFSET string = "offset"
-	// PAGINATION_CURSOR
+	PAGINATION_CURSOR string = "cursor"
 )
 
 const (
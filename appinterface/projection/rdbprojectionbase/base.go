@@ -1,7 +1,11 @@
 package rdbprojectionbase
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
 	"github.com/AstraProtocol/astra-indexing/external/primptr"
 	projection_usecase "github.com/AstraProtocol/astra-indexing/usecase/projection"
 )
@@ -68,3 +72,56 @@ func (base *Base) UpdateLastHandledEventHeight(rdbHandle *rdb.Handle, height int
 func (base *Base) GetLastHandledEventHeight() (*int64, error) {
 	return base.store.GetLastHandledEventHeight(base.rdbHandle, base.Id())
 }
+
+// RewindLastHandledEventHeight resets the last handled event height to the height right before
+// `fromHeight`. Rewinding to before the genesis removes the projection record.
+func (base *Base) RewindLastHandledEventHeight(rdbHandle *rdb.Handle, fromHeight int64) error {
+	if fromHeight <= 0 {
+		return base.store.DeleteLastHandledEventHeight(rdbHandle, base.Id())
+	}
+	return base.store.UpdateLastHandledEventHeight(rdbHandle, base.Id(), fromHeight-1)
+}
+
+// ResetViews truncates the view tables of the projection, removes its view journal and rewinds the
+// last handled event height in a single DB transaction. It implements
+// projection.RebuildableProjection.Reset() for projections keeping all states in their views. The
+// truncated views only allow replaying from the genesis.
+func (base *Base) ResetViews(rdbConn rdb.Conn, fromHeight int64, viewTables []string) error {
+	if fromHeight != 0 {
+		return fmt.Errorf(
+			"error resetting views from height %d: %w", fromHeight, projection_entity.ErrProjectionNotRebuildableFromHeight,
+		)
+	}
+
+	rdbTx, err := rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	if len(viewTables) > 0 {
+		sql := fmt.Sprintf("TRUNCATE TABLE %s RESTART IDENTITY", strings.Join(viewTables, ", "))
+		if _, err = rdbTxHandle.Exec(sql); err != nil {
+			return fmt.Errorf("error truncating view tables: %v", err)
+		}
+	}
+	if err = base.deleteJournal(rdbTxHandle); err != nil {
+		return err
+	}
+	if err = base.RewindLastHandledEventHeight(rdbTxHandle, fromHeight); err != nil {
+		return fmt.Errorf("error rewinding last handled event height: %v", err)
+	}
+
+	if err = rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
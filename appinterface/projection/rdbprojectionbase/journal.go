@@ -0,0 +1,327 @@
+package rdbprojectionbase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
+	"github.com/AstraProtocol/astra-indexing/external/json"
+)
+
+// Number of heights whose view changes are kept in the journal. It covers the deepest chain
+// reorganization the sync manager rolls back.
+const DEFAULT_JOURNAL_RETAINED_HEIGHTS = int64(1000)
+
+const (
+	JOURNAL_TABLE         = "projection_journal"
+	JOURNAL_HEIGHTS_TABLE = "projection_journal_heights"
+)
+
+// Journal tables should have the following schemas, with the view tables journaled by the row
+// trigger `journal_projection_view()`. A journaled view table must have a primary key.
+//
+// projection_journal
+// | Field         | Data Type | Constraint  |
+// | ------------- | --------- | ----------- |
+// | id            | BIGSERIAL | PRIMARY KEY |
+// | projection_id | VARCHAR   | NOT NULL    |
+// | block_height  | INT64     | NOT NULL    |
+// | table_name    | VARCHAR   | NOT NULL    |
+// | operation     | VARCHAR   | NOT NULL    |
+// | old_row       | JSONB     |             |
+// | new_row       | JSONB     |             |
+//
+// projection_journal_heights
+// | Field         | Data Type | Constraint  |
+// | ------------- | --------- | ----------- |
+// | projection_id | VARCHAR   | PRIMARY KEY |
+// | from_height   | INT64     | NOT NULL    |
+
+// JournalViews records the changes of the journaled view tables made in the DB transaction as the
+// changes of `height`, such that RollbackViews is able to undo them. It has to be called in the
+// DB transaction handling the height before any view change. The journal keeps the changes of the
+// last DEFAULT_JOURNAL_RETAINED_HEIGHTS heights.
+func (base *Base) JournalViews(rdbHandle *rdb.Handle, height int64) error {
+	if _, err := rdbHandle.Exec(
+		"SELECT set_config('projection_journal.projection_id', $1, true), set_config('projection_journal.block_height', $2, true)",
+		base.Id(), fmt.Sprintf("%d", height),
+	); err != nil {
+		return fmt.Errorf("error enabling view journal: %v: %w", err, rdb.ErrWrite)
+	}
+
+	retainedFromHeight := height - DEFAULT_JOURNAL_RETAINED_HEIGHTS + 1
+	if _, err := rdbHandle.Exec(
+		fmt.Sprintf(
+			"INSERT INTO %s AS heights (projection_id, from_height) VALUES ($1, $2) "+
+				"ON CONFLICT (projection_id) DO UPDATE SET from_height = GREATEST(heights.from_height, $3)",
+			JOURNAL_HEIGHTS_TABLE,
+		),
+		base.Id(), height, retainedFromHeight,
+	); err != nil {
+		return fmt.Errorf("error updating view journal heights: %v: %w", err, rdb.ErrWrite)
+	}
+
+	sql, sqlArgs, err := rdbHandle.StmtBuilder.Delete(
+		JOURNAL_TABLE,
+	).Where(
+		"projection_id = ? AND block_height < ?", base.Id(), retainedFromHeight,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building view journal pruning sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+	if _, err = rdbHandle.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error pruning view journal: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+// RollbackViews undoes the journaled view changes of the heights from `fromHeight` onwards, deletes
+// the rows from `fromHeight` onwards of the view tables keyed by a `block_height` column, which
+// need no journal, and rewinds the last handled event height in a single DB transaction. It
+// implements projection.RollbackableProjection.Rollback() for projections journaling their views,
+// and returns the undone journal entries. Heights no longer in the journal are rejected with
+// ErrProjectionNotRebuildableFromHeight.
+func (base *Base) RollbackViews(rdbConn rdb.Conn, fromHeight int64, heightViewTables []string) ([]JournalEntry, error) {
+	rdbTx, err := rdbConn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+
+	var journalFromHeight int64
+	sql, sqlArgs, err := rdbTxHandle.StmtBuilder.Select(
+		"from_height",
+	).From(
+		JOURNAL_HEIGHTS_TABLE,
+	).Where(
+		"projection_id = ?", base.Id(),
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building view journal heights selection sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+	if err = rdbTxHandle.QueryRow(sql, sqlArgs...).Scan(&journalFromHeight); err != nil {
+		if !errors.Is(err, rdb.ErrNoRows) {
+			return nil, fmt.Errorf("error scanning view journal heights: %v: %w", err, rdb.ErrQuery)
+		}
+		journalFromHeight = fromHeight + 1
+	}
+	if journalFromHeight > fromHeight {
+		return nil, fmt.Errorf(
+			"error rolling back views from height %d not in the journal: %w",
+			fromHeight, projection_entity.ErrProjectionNotRebuildableFromHeight,
+		)
+	}
+
+	entries, err := base.selectJournalEntries(rdbTxHandle, fromHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	primaryKeys := make(map[string][]string)
+	for _, entry := range entries {
+		if _, exist := primaryKeys[entry.TableName]; !exist {
+			primaryKeyColumns, queryErr := selectPrimaryKeyColumns(rdbTxHandle, entry.TableName)
+			if queryErr != nil {
+				return nil, queryErr
+			}
+			primaryKeys[entry.TableName] = primaryKeyColumns
+		}
+
+		for _, undoStmt := range UndoJournalEntryStmts(entry, primaryKeys[entry.TableName]) {
+			if _, err = rdbTxHandle.Exec(undoStmt.SQL, undoStmt.Args...); err != nil {
+				return nil, fmt.Errorf("error undoing %s on %s: %v: %w", entry.Operation, entry.TableName, err, rdb.ErrWrite)
+			}
+		}
+	}
+
+	for _, table := range heightViewTables {
+		sql, sqlArgs, err = rdbTxHandle.StmtBuilder.Delete(
+			table,
+		).Where(
+			"block_height >= ?", fromHeight,
+		).ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("error building %s deletion sql: %v: %w", table, err, rdb.ErrBuildSQLStmt)
+		}
+		if _, err = rdbTxHandle.Exec(sql, sqlArgs...); err != nil {
+			return nil, fmt.Errorf("error deleting %s: %v: %w", table, err, rdb.ErrWrite)
+		}
+	}
+
+	sql, sqlArgs, err = rdbTxHandle.StmtBuilder.Delete(
+		JOURNAL_TABLE,
+	).Where(
+		"projection_id = ? AND block_height >= ?", base.Id(), fromHeight,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building view journal deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+	if _, err = rdbTxHandle.Exec(sql, sqlArgs...); err != nil {
+		return nil, fmt.Errorf("error deleting view journal: %v: %w", err, rdb.ErrWrite)
+	}
+
+	if err = base.RewindLastHandledEventHeight(rdbTxHandle, fromHeight); err != nil {
+		return nil, fmt.Errorf("error rewinding last handled event height: %v", err)
+	}
+
+	if err = rdbTx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return entries, nil
+}
+
+// deleteJournal removes the whole journal of the projection
+func (base *Base) deleteJournal(rdbHandle *rdb.Handle) error {
+	for _, table := range []string{JOURNAL_TABLE, JOURNAL_HEIGHTS_TABLE} {
+		sql, sqlArgs, err := rdbHandle.StmtBuilder.Delete(
+			table,
+		).Where(
+			"projection_id = ?", base.Id(),
+		).ToSql()
+		if err != nil {
+			return fmt.Errorf("error building view journal deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+		}
+		if _, err = rdbHandle.Exec(sql, sqlArgs...); err != nil {
+			return fmt.Errorf("error deleting view journal: %v: %w", err, rdb.ErrWrite)
+		}
+	}
+
+	return nil
+}
+
+// selectJournalEntries returns the journal entries from `fromHeight` onwards, latest first
+func (base *Base) selectJournalEntries(rdbHandle *rdb.Handle, fromHeight int64) ([]JournalEntry, error) {
+	sql, sqlArgs, err := rdbHandle.StmtBuilder.Select(
+		"table_name", "operation", "old_row::TEXT", "new_row::TEXT",
+	).From(
+		JOURNAL_TABLE,
+	).Where(
+		"projection_id = ? AND block_height >= ?", base.Id(), fromHeight,
+	).OrderBy("id DESC").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building view journal selection sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := rdbHandle.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing view journal selection sql: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	entries := make([]JournalEntry, 0)
+	for rowsResult.Next() {
+		var entry JournalEntry
+		if err = rowsResult.Scan(&entry.TableName, &entry.Operation, &entry.MaybeOldRow, &entry.MaybeNewRow); err != nil {
+			return nil, fmt.Errorf("error scanning view journal entry: %v: %w", err, rdb.ErrQuery)
+		}
+		entries = append(entries, entry)
+	}
+	if err = rowsResult.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating view journal entries: %v: %w", err, rdb.ErrQuery)
+	}
+
+	return entries, nil
+}
+
+func selectPrimaryKeyColumns(rdbHandle *rdb.Handle, table string) ([]string, error) {
+	rowsResult, err := rdbHandle.Query(
+		"SELECT pg_attribute.attname FROM pg_index "+
+			"JOIN pg_attribute ON pg_attribute.attrelid = pg_index.indrelid AND pg_attribute.attnum = ANY(pg_index.indkey) "+
+			"WHERE pg_index.indrelid = $1::regclass AND pg_index.indisprimary",
+		quoteIdentifier(table),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting primary key of %s: %v: %w", table, err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	columns := make([]string, 0)
+	for rowsResult.Next() {
+		var column string
+		if err = rowsResult.Scan(&column); err != nil {
+			return nil, fmt.Errorf("error scanning primary key of %s: %v: %w", table, err, rdb.ErrQuery)
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("error undoing changes of %s without primary key: %w", table, rdb.ErrQuery)
+	}
+
+	return columns, nil
+}
+
+type JournalEntry struct {
+	TableName   string
+	Operation   string
+	MaybeOldRow *string
+	MaybeNewRow *string
+}
+
+// Values returns the values of a column in the old and new rows of the entry
+func (entry JournalEntry) Values(column string) []string {
+	values := make([]string, 0, 2)
+	for _, maybeRow := range []*string{entry.MaybeOldRow, entry.MaybeNewRow} {
+		if maybeRow == nil {
+			continue
+		}
+		var row map[string]interface{}
+		if err := json.UnmarshalFromString(*maybeRow, &row); err != nil {
+			continue
+		}
+		if value, ok := row[column]; ok && value != nil {
+			values = append(values, fmt.Sprintf("%v", value))
+		}
+	}
+	return values
+}
+
+type UndoStmt struct {
+	SQL  string
+	Args []interface{}
+}
+
+// UndoJournalEntryStmts returns the statements undoing the journaled change of a row. The changed
+// row is identified by the primary key columns of its table.
+func UndoJournalEntryStmts(entry JournalEntry, primaryKeyColumns []string) []UndoStmt {
+	table := quoteIdentifier(entry.TableName)
+	quotedColumns := make([]string, 0, len(primaryKeyColumns))
+	for _, column := range primaryKeyColumns {
+		quotedColumns = append(quotedColumns, quoteIdentifier(column))
+	}
+	primaryKey := strings.Join(quotedColumns, ", ")
+
+	stmts := make([]UndoStmt, 0, 2)
+	if entry.MaybeNewRow != nil {
+		stmts = append(stmts, UndoStmt{
+			SQL: fmt.Sprintf(
+				"DELETE FROM %s WHERE (%s) = (SELECT %s FROM jsonb_populate_record(NULL::%s, $1::JSONB))",
+				table, primaryKey, primaryKey, table,
+			),
+			Args: []interface{}{*entry.MaybeNewRow},
+		})
+	}
+	if entry.MaybeOldRow != nil {
+		stmts = append(stmts, UndoStmt{
+			SQL:  fmt.Sprintf("INSERT INTO %s SELECT * FROM jsonb_populate_record(NULL::%s, $1::JSONB)", table, table),
+			Args: []interface{}{*entry.MaybeOldRow},
+		})
+	}
+
+	return stmts
+}
+
+func quoteIdentifier(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
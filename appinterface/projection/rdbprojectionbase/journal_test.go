@@ -0,0 +1,63 @@
+package rdbprojectionbase_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbprojectionbase"
+	"github.com/AstraProtocol/astra-indexing/external/primptr"
+)
+
+var _ = Describe("UndoJournalEntryStmts", func() {
+	It("should delete the inserted row", func() {
+		stmts := rdbprojectionbase.UndoJournalEntryStmts(rdbprojectionbase.JournalEntry{
+			TableName:   "view_accounts",
+			Operation:   "INSERT",
+			MaybeNewRow: primptr.String(`{"address":"astra1"}`),
+		}, []string{"address"})
+
+		Expect(stmts).To(Equal([]rdbprojectionbase.UndoStmt{
+			{
+				SQL: `DELETE FROM "view_accounts" WHERE ("address") = ` +
+					`(SELECT "address" FROM jsonb_populate_record(NULL::"view_accounts", $1::JSONB))`,
+				Args: []interface{}{`{"address":"astra1"}`},
+			},
+		}))
+	})
+
+	It("should replace the updated row by the old row", func() {
+		stmts := rdbprojectionbase.UndoJournalEntryStmts(rdbprojectionbase.JournalEntry{
+			TableName:   "view_delegations",
+			Operation:   "UPDATE",
+			MaybeOldRow: primptr.String(`{"delegator_address":"astra1","validator_address":"astravaloper1","shares":"1"}`),
+			MaybeNewRow: primptr.String(`{"delegator_address":"astra1","validator_address":"astravaloper1","shares":"2"}`),
+		}, []string{"delegator_address", "validator_address"})
+
+		Expect(stmts).To(Equal([]rdbprojectionbase.UndoStmt{
+			{
+				SQL: `DELETE FROM "view_delegations" WHERE ("delegator_address", "validator_address") = ` +
+					`(SELECT "delegator_address", "validator_address" FROM jsonb_populate_record(NULL::"view_delegations", $1::JSONB))`,
+				Args: []interface{}{`{"delegator_address":"astra1","validator_address":"astravaloper1","shares":"2"}`},
+			},
+			{
+				SQL:  `INSERT INTO "view_delegations" SELECT * FROM jsonb_populate_record(NULL::"view_delegations", $1::JSONB)`,
+				Args: []interface{}{`{"delegator_address":"astra1","validator_address":"astravaloper1","shares":"1"}`},
+			},
+		}))
+	})
+
+	It("should insert back the deleted row", func() {
+		stmts := rdbprojectionbase.UndoJournalEntryStmts(rdbprojectionbase.JournalEntry{
+			TableName:   "view_accounts",
+			Operation:   "DELETE",
+			MaybeOldRow: primptr.String(`{"address":"astra1"}`),
+		}, []string{"address"})
+
+		Expect(stmts).To(Equal([]rdbprojectionbase.UndoStmt{
+			{
+				SQL:  `INSERT INTO "view_accounts" SELECT * FROM jsonb_populate_record(NULL::"view_accounts", $1::JSONB)`,
+				Args: []interface{}{`{"address":"astra1"}`},
+			},
+		}))
+	})
+})
@@ -0,0 +1,11 @@
+package rdb
+
+import "strings"
+
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLikePattern escapes the LIKE wildcards in value so that it is matched literally, using the default backslash
+// escape character.
+func EscapeLikePattern(value string) string {
+	return likePatternEscaper.Replace(value)
+}
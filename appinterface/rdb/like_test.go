@@ -0,0 +1,18 @@
+package rdb_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+)
+
+var _ = Describe("EscapeLikePattern", func() {
+	It("should escape the LIKE wildcards and the escape character", func() {
+		Expect(rdb.EscapeLikePattern(`50%_off\`)).To(Equal(`50\%\_off\\`))
+	})
+
+	It("should keep other characters untouched", func() {
+		Expect(rdb.EscapeLikePattern("astra validator")).To(Equal("astra validator"))
+	})
+})
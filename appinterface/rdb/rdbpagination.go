@@ -1,6 +1,7 @@
 package rdb
 
 import (
+	"errors"
 	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
@@ -13,13 +14,21 @@ type RDbPaginationBuilder struct {
 	*pagination_interface.Pagination
 
 	customTotalQueryFn CustomTotalQueryFn
+	keyset             []KeysetColumn
 	rdbHandle          *Handle
 }
 
+// KeysetColumn is a column of the unique ordering of a list, required by the cursor pagination
+type KeysetColumn struct {
+	Column string
+	Desc   bool
+}
+
 func NewRDbPaginationBuilder(pagination *pagination_interface.Pagination, rdbHandle *Handle) *RDbPaginationBuilder {
 	return &RDbPaginationBuilder{
 		pagination,
 
+		nil,
 		nil,
 		rdbHandle,
 	}
@@ -31,6 +40,14 @@ func (pagination *RDbPaginationBuilder) WithCustomTotalQueryFn(fn CustomTotalQue
 	return pagination
 }
 
+// WithKeyset orders the statement by the keyset, which must identify the rows. The statement is
+// then paginated with either the offset or the cursor pagination.
+func (pagination *RDbPaginationBuilder) WithKeyset(keyset ...KeysetColumn) *RDbPaginationBuilder {
+	pagination.keyset = keyset
+
+	return pagination
+}
+
 func (pagination *RDbPaginationBuilder) BuildStmt(stmtBuilder sq.SelectBuilder) *RDbPaginationStmtBuilder {
 	return &RDbPaginationStmtBuilder{
 		Pagination: pagination.Pagination,
@@ -38,6 +55,7 @@ func (pagination *RDbPaginationBuilder) BuildStmt(stmtBuilder sq.SelectBuilder)
 		rdbHandle:          pagination.rdbHandle,
 		stmtBuilder:        stmtBuilder,
 		customTotalQueryFn: pagination.customTotalQueryFn,
+		keyset:             pagination.keyset,
 	}
 }
 
@@ -58,6 +76,7 @@ type RDbPaginationStmtBuilder struct {
 	rdbHandle          *Handle
 	stmtBuilder        sq.SelectBuilder
 	customTotalQueryFn CustomTotalQueryFn
+	keyset             []KeysetColumn
 }
 
 func (pagination *RDbPaginationStmtBuilder) ToStmtBuilder() sq.SelectBuilder {
@@ -65,21 +84,89 @@ func (pagination *RDbPaginationStmtBuilder) ToStmtBuilder() sq.SelectBuilder {
 	case pagination_interface.PAGINATION_OFFSET:
 
 		params := pagination.OffsetParams()
-		return pagination.stmtBuilder.Suffix("LIMIT ? OFFSET ?", params.Limit, params.Offset())
+		return orderByKeyset(pagination.stmtBuilder, pagination.keyset, false).Suffix(
+			"LIMIT ? OFFSET ?", params.Limit, params.Offset(),
+		)
+	case pagination_interface.PAGINATION_CURSOR:
+		params := pagination.CursorParams()
+		backward := params.Cursor != nil && params.Cursor.Backward
+
+		stmtBuilder := pagination.stmtBuilder
+		if params.Cursor != nil {
+			stmtBuilder = stmtBuilder.Where(keysetPredicate{
+				keyset:   pagination.keyset,
+				keys:     params.Cursor.Keys,
+				backward: backward,
+			})
+		}
+		// One more row tells whether there is a next page
+		return orderByKeyset(stmtBuilder, pagination.keyset, backward).Suffix("LIMIT ?", params.Limit+1)
 	}
 
 	return pagination.stmtBuilder
 }
 
+// Result returns the offset pagination result, see CursorPage for the cursor pagination
 func (pagination *RDbPaginationStmtBuilder) Result() (*pagination_interface.Result, error) {
 	switch pagination.Type() {
 	case pagination_interface.PAGINATION_OFFSET:
 		return pagination.offsetResult()
+	case pagination_interface.PAGINATION_CURSOR:
+		return nil, errors.New("cursor pagination result requires the rows of the page")
 	}
 
 	return nil, nil
 }
 
+// CursorPage returns the rows of the page with the pagination result. With the cursor pagination, the
+// extra row fetched is dropped and the rows read backward are put back in order. keysOf returns the
+// values of the keyset of the i-th row fetched.
+func CursorPage[T any](
+	pagination *RDbPaginationStmtBuilder,
+	rows []T,
+	keysOf func(i int) []interface{},
+) ([]T, *pagination_interface.Result, error) {
+	if pagination.Type() != pagination_interface.PAGINATION_CURSOR {
+		result, err := pagination.Result()
+		return rows, result, err
+	}
+
+	params := pagination.CursorParams()
+	backward := params.Cursor != nil && params.Cursor.Backward
+	hasMore := int64(len(rows)) > params.Limit
+	if hasMore {
+		rows = rows[:params.Limit]
+	}
+	keys := make([][]interface{}, len(rows))
+	for i := range rows {
+		keys[i] = keysOf(i)
+	}
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+
+	var prevKeys, nextKeys []interface{}
+	if len(rows) > 0 {
+		if backward {
+			if hasMore {
+				prevKeys = keys[0]
+			}
+			nextKeys = keys[len(keys)-1]
+		} else {
+			if params.Cursor != nil {
+				prevKeys = keys[0]
+			}
+			if hasMore {
+				nextKeys = keys[len(keys)-1]
+			}
+		}
+	}
+	return rows, pagination.CursorResult(prevKeys, nextKeys), nil
+}
+
 func (pagination *RDbPaginationStmtBuilder) offsetResult() (*pagination_interface.Result, error) {
 	var err error
 
@@ -158,6 +245,51 @@ func (pagination *RDbPaginationSQLBuilder) offsetResult() (*pagination_interface
 	return pagination.OffsetResult(total), nil
 }
 
+// orderByKeyset orders the statement by the keyset, reversed to read backward from a cursor
+func orderByKeyset(stmtBuilder sq.SelectBuilder, keyset []KeysetColumn, backward bool) sq.SelectBuilder {
+	if len(keyset) == 0 {
+		return stmtBuilder
+	}
+	orderBys := make([]string, 0, len(keyset))
+	for _, column := range keyset {
+		if column.Desc != backward {
+			orderBys = append(orderBys, column.Column+" DESC")
+		} else {
+			orderBys = append(orderBys, column.Column)
+		}
+	}
+	return stmtBuilder.OrderBy(orderBys...)
+}
+
+// keysetPredicate selects the rows after the keys in the keyset order, or before them backward, e.g.
+// `(a > ?) OR (a = ? AND b < ?)` for the keyset (a, b DESC)
+type keysetPredicate struct {
+	keyset   []KeysetColumn
+	keys     []interface{}
+	backward bool
+}
+
+func (predicate keysetPredicate) ToSql() (string, []interface{}, error) {
+	if len(predicate.keyset) == 0 || len(predicate.keys) != len(predicate.keyset) {
+		return "", nil, fmt.Errorf("cursor of %d keys for a keyset of %d columns", len(predicate.keys), len(predicate.keyset))
+	}
+
+	disjunction := make(sq.Or, 0, len(predicate.keyset))
+	for i, column := range predicate.keyset {
+		conjunction := make(sq.And, 0, i+1)
+		for j := 0; j < i; j++ {
+			conjunction = append(conjunction, sq.Eq{predicate.keyset[j].Column: predicate.keys[j]})
+		}
+		if column.Desc != predicate.backward {
+			conjunction = append(conjunction, sq.Lt{column.Column: predicate.keys[i]})
+		} else {
+			conjunction = append(conjunction, sq.Gt{column.Column: predicate.keys[i]})
+		}
+		disjunction = append(disjunction, conjunction)
+	}
+	return disjunction.ToSql()
+}
+
 type CustomTotalQueryFn = func(conn *Handle, originalSelectBuilder sq.SelectBuilder) (int64, error)
 
 //"SELECT reltuples::bigint FROM pg_class where relname='$1';
@@ -0,0 +1,132 @@
+package rdb_test
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	pagination_interface "github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+)
+
+var _ = Describe("RDbPaginationStmtBuilder", func() {
+	keyset := []rdb.KeysetColumn{
+		{Column: "block_height", Desc: true},
+		{Column: "id"},
+	}
+	stmtBuilder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select(
+		"block_height",
+	).From(
+		"view_transactions",
+	).Where(
+		"success = ?", true,
+	)
+
+	build := func(pagination *pagination_interface.Pagination) *rdb.RDbPaginationStmtBuilder {
+		return rdb.NewRDbPaginationBuilder(pagination, nil).WithKeyset(keyset...).BuildStmt(stmtBuilder)
+	}
+
+	Describe("ToStmtBuilder", func() {
+		It("should order the offset pagination by the keyset", func() {
+			sql, args, err := build(pagination_interface.NewOffsetPagination(3, 20)).ToStmtBuilder().ToSql()
+
+			Expect(err).To(BeNil())
+			Expect(sql).To(Equal(
+				"SELECT block_height FROM view_transactions WHERE success = $1 " +
+					"ORDER BY block_height DESC, id LIMIT $2 OFFSET $3",
+			))
+			Expect(args).To(Equal([]interface{}{true, int64(20), int64(40)}))
+		})
+
+		It("should fetch one more row for the first page of the cursor pagination", func() {
+			sql, args, err := build(pagination_interface.NewCursorPagination(nil, 20)).ToStmtBuilder().ToSql()
+
+			Expect(err).To(BeNil())
+			Expect(sql).To(Equal(
+				"SELECT block_height FROM view_transactions WHERE success = $1 " +
+					"ORDER BY block_height DESC, id LIMIT $2",
+			))
+			Expect(args).To(Equal([]interface{}{true, int64(21)}))
+		})
+
+		It("should select the rows after the cursor", func() {
+			cursor := pagination_interface.NewCursor([]interface{}{int64(10), int64(5)}, false)
+			sql, args, err := build(pagination_interface.NewCursorPagination(cursor, 20)).ToStmtBuilder().ToSql()
+
+			Expect(err).To(BeNil())
+			Expect(sql).To(Equal(
+				"SELECT block_height FROM view_transactions WHERE success = $1 " +
+					"AND ((block_height < $2) OR (block_height = $3 AND id > $4)) " +
+					"ORDER BY block_height DESC, id LIMIT $5",
+			))
+			Expect(args).To(Equal([]interface{}{true, int64(10), int64(10), int64(5), int64(21)}))
+		})
+
+		It("should select the rows before the backward cursor in the reverse order", func() {
+			cursor := pagination_interface.NewCursor([]interface{}{int64(10), int64(5)}, true)
+			sql, _, err := build(pagination_interface.NewCursorPagination(cursor, 20)).ToStmtBuilder().ToSql()
+
+			Expect(err).To(BeNil())
+			Expect(sql).To(Equal(
+				"SELECT block_height FROM view_transactions WHERE success = $1 " +
+					"AND ((block_height > $2) OR (block_height = $3 AND id < $4)) " +
+					"ORDER BY block_height, id DESC LIMIT $5",
+			))
+		})
+
+		It("should fail to build with a cursor of another keyset", func() {
+			cursor := pagination_interface.NewCursor([]interface{}{int64(10)}, false)
+			_, _, err := build(pagination_interface.NewCursorPagination(cursor, 20)).ToStmtBuilder().ToSql()
+
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("CursorPage", func() {
+		keysOf := func(rows []int64) func(int) []interface{} {
+			return func(i int) []interface{} {
+				return []interface{}{rows[i], int64(0)}
+			}
+		}
+
+		It("should return the next cursor when there are more rows", func() {
+			rows := []int64{9, 8, 7}
+			page, result, err := rdb.CursorPage(build(pagination_interface.NewCursorPagination(nil, 2)), rows, keysOf(rows))
+
+			Expect(err).To(BeNil())
+			Expect(page).To(Equal([]int64{9, 8}))
+			Expect(result.CursorResult().PrevCursor).To(BeEmpty())
+			nextCursor, err := pagination_interface.DecodeCursor(result.CursorResult().NextCursor)
+			Expect(err).To(BeNil())
+			Expect(nextCursor).To(Equal(pagination_interface.NewCursor([]interface{}{int64(8), int64(0)}, false)))
+		})
+
+		It("should return the previous cursor only after the first page", func() {
+			rows := []int64{7, 6}
+			cursor := pagination_interface.NewCursor([]interface{}{int64(8), int64(0)}, false)
+			page, result, err := rdb.CursorPage(build(pagination_interface.NewCursorPagination(cursor, 2)), rows, keysOf(rows))
+
+			Expect(err).To(BeNil())
+			Expect(page).To(Equal([]int64{7, 6}))
+			Expect(result.CursorResult().NextCursor).To(BeEmpty())
+			prevCursor, err := pagination_interface.DecodeCursor(result.CursorResult().PrevCursor)
+			Expect(err).To(BeNil())
+			Expect(prevCursor).To(Equal(pagination_interface.NewCursor([]interface{}{int64(7), int64(0)}, true)))
+		})
+
+		It("should put back in order the rows read backward", func() {
+			rows := []int64{8, 9, 10}
+			cursor := pagination_interface.NewCursor([]interface{}{int64(7), int64(0)}, true)
+			page, result, err := rdb.CursorPage(build(pagination_interface.NewCursorPagination(cursor, 2)), rows, keysOf(rows))
+
+			Expect(err).To(BeNil())
+			Expect(page).To(Equal([]int64{9, 8}))
+			prevCursor, err := pagination_interface.DecodeCursor(result.CursorResult().PrevCursor)
+			Expect(err).To(BeNil())
+			Expect(prevCursor).To(Equal(pagination_interface.NewCursor([]interface{}{int64(9), int64(0)}, true)))
+			nextCursor, err := pagination_interface.DecodeCursor(result.CursorResult().NextCursor)
+			Expect(err).To(BeNil())
+			Expect(nextCursor).To(Equal(pagination_interface.NewCursor([]interface{}{int64(8), int64(0)}, false)))
+		})
+	})
+})
@@ -0,0 +1,131 @@
+package rdbblockhashstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+)
+
+const DEFAULT_TABLE = "indexed_block_hashes"
+
+// Block hashes table should have the following schema
+// | Field      | Data Type | Constraint  |
+// | ---------- | --------- | ----------- |
+// | handler_id | VARCHAR   | PRIMARY KEY |
+// | height     | INT64     | PRIMARY KEY |
+// | hash       | VARCHAR   | NOT NULL    |
+
+// RDbBlockHashStore keeps the hashes of the recently indexed blocks of each event handler, so that
+// the sync manager can verify the chain continuity of the upcoming blocks.
+type RDbBlockHashStore struct {
+	rdbHandle *rdb.Handle
+
+	table string
+}
+
+func NewRDbBlockHashStore(rdbHandle *rdb.Handle) *RDbBlockHashStore {
+	return &RDbBlockHashStore{
+		rdbHandle: rdbHandle,
+
+		table: DEFAULT_TABLE,
+	}
+}
+
+// FindBy returns the indexed block hash of the handler at the height, nil if it is not recorded
+func (impl *RDbBlockHashStore) FindBy(handlerId string, height int64) (*string, error) {
+	sql, args, err := impl.rdbHandle.StmtBuilder.Select(
+		"hash",
+	).From(
+		impl.table,
+	).Where(
+		"handler_id = ? AND height = ?", handlerId, height,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building block hash selection SQL: %v", err)
+	}
+
+	var hash string
+	if err := impl.rdbHandle.QueryRow(sql, args...).Scan(&hash); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error querying block hash: %v", err)
+	}
+
+	return &hash, nil
+}
+
+// Save records the indexed block hash of the handler at the height, replacing any existing record
+func (impl *RDbBlockHashStore) Save(handlerId string, height int64, hash string) error {
+	return impl.SaveWithRDbHandle(impl.rdbHandle, handlerId, height, hash)
+}
+
+// SaveWithRDbHandle is Save using the RDb handle, e.g. within the transaction handling the block
+func (impl *RDbBlockHashStore) SaveWithRDbHandle(rdbHandle *rdb.Handle, handlerId string, height int64, hash string) error {
+	// Postgres UPSERT statement
+	sql, args, err := rdbHandle.StmtBuilder.Insert(
+		impl.table,
+	).Columns(
+		"handler_id", "height", "hash",
+	).Values(
+		handlerId, height, hash,
+	).Suffix(
+		"ON CONFLICT (handler_id, height) DO UPDATE SET hash = EXCLUDED.hash",
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building block hash insertion SQL: %v", err)
+	}
+
+	execResult, err := rdbHandle.Exec(sql, args...)
+	if err != nil {
+		return fmt.Errorf("error executing block hash insertion SQL: %v", err)
+	}
+	if execResult.RowsAffected() == 0 {
+		return errors.New("error executing block hash insertion SQL: no rows inserted")
+	}
+
+	return nil
+}
+
+// DeleteAbove removes all the block hashes of the handler above the height
+func (impl *RDbBlockHashStore) DeleteAbove(handlerId string, height int64) error {
+	sql, args, err := impl.rdbHandle.StmtBuilder.Delete(
+		impl.table,
+	).Where(
+		"handler_id = ? AND height > ?", handlerId, height,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building block hashes deletion SQL: %v", err)
+	}
+
+	if _, err := impl.rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing block hashes deletion SQL: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteBelow removes all the block hashes of the handler below the height. It is used to keep only
+// the block hashes within the maximum reorganization depth.
+func (impl *RDbBlockHashStore) DeleteBelow(handlerId string, height int64) error {
+	return impl.DeleteBelowWithRDbHandle(impl.rdbHandle, handlerId, height)
+}
+
+// DeleteBelowWithRDbHandle is DeleteBelow using the RDb handle
+func (impl *RDbBlockHashStore) DeleteBelowWithRDbHandle(rdbHandle *rdb.Handle, handlerId string, height int64) error {
+	sql, args, err := rdbHandle.StmtBuilder.Delete(
+		impl.table,
+	).Where(
+		"handler_id = ? AND height < ?", handlerId, height,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building block hashes deletion SQL: %v", err)
+	}
+
+	if _, err := rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing block hashes deletion SQL: %v", err)
+	}
+
+	return nil
+}
@@ -142,3 +142,26 @@ func (impl *RDbStatusStore) UpdateLastIndexedBlockHeightWithRDbHandle(rdbHandle
 
 	return nil
 }
+
+// ResetLastIndexedBlockHeightWithRDbHandle marks no block has been indexed
+func (impl *RDbStatusStore) ResetLastIndexedBlockHeightWithRDbHandle(rdbHandle *rdb.Handle) error {
+	// lazy init
+	if err := impl.init(); err != nil {
+		return fmt.Errorf("error initializing status store: %v", err)
+	}
+
+	sql, args, err := rdbHandle.StmtBuilder.Update(
+		impl.table,
+	).Set(
+		"last_indexed_block_height", nil,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building last indexed block height reset SQL: %v", err)
+	}
+
+	if _, err := rdbHandle.Exec(sql, args...); err != nil {
+		return fmt.Errorf("error executing last indexed block height reset SQL: %v", err)
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package tendermint
 
 import (
+	"context"
+
 	usecase_model "github.com/AstraProtocol/astra-indexing/usecase/model"
 	"github.com/AstraProtocol/astra-indexing/usecase/model/genesis"
 )
@@ -11,3 +13,10 @@ type Client interface {
 	BlockResults(height int64) (*usecase_model.BlockResults, error)
 	LatestBlockHeight() (int64, error)
 }
+
+// BlockSubscriber pushes the new blocks of the chain
+type BlockSubscriber interface {
+	// SubscribeNewBlocks sends the new blocks to the channel until the context is done or the
+	// subscription is dropped, which is reported by the returned error
+	SubscribeNewBlocks(ctx context.Context, blockCh chan<- *usecase_model.Block) error
+}
@@ -1,10 +1,11 @@
 package bootstrap
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
-	"os/signal"
+	"sync"
 	"time"
 
 	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
@@ -14,6 +15,7 @@ import (
 	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
 	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
 	worker_consumer "github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer/worker"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/producer"
 	"github.com/AstraProtocol/astra-indexing/infrastructure/metric/prometheus"
 	"github.com/AstraProtocol/astra-indexing/infrastructure/pg"
 	"github.com/AstraProtocol/astra-indexing/internal/evm"
@@ -29,6 +31,7 @@ type app struct {
 	rdbConn       rdb.Conn
 	httpAPIServer *HTTPAPIServer
 	indexService  *IndexService
+	cronSchedules []*cron.Cron
 }
 
 func NewApp(logger applogger.Logger, config *config.Config, evmUtil evm.EvmUtils) *app {
@@ -85,63 +88,157 @@ func (a *app) GetRDbConn() rdb.Conn {
 func (a *app) InitHTTPAPIServer(registry RouteRegistry) {
 	if a.config.HTTPService.Enable {
 		a.httpAPIServer = NewHTTPAPIServer(a.logger, a.config)
+		if a.config.HTTPService.APIAuth.Enable {
+			a.httpAPIServer.UseAPIAuth(a.rdbConn, a.config.HTTPService.APIAuth)
+		}
 		a.httpAPIServer.RegisterRoutes(registry)
 	}
 }
 
-func (a *app) InitIndexService(projections []projection_entity.Projection, cronJobs []projection_entity.CronJob) {
-	if a.config.IndexService.Enable {
-		a.indexService = NewIndexService(a.logger, a.rdbConn, a.config, projections, cronJobs)
+// InitIndexService creates the index service when it is enabled. It fails on invalid index service
+// settings, such that they are reported on startup.
+func (a *app) InitIndexService(projections []projection_entity.Projection, cronJobs []projection_entity.CronJob) error {
+	if !a.config.IndexService.Enable {
+		return nil
+	}
+	if _, err := config.ParseSyncStrategy(a.config.IndexService.SyncStrategy); err != nil {
+		return fmt.Errorf("error in index_service.sync_strategy: %v", err)
 	}
+
+	a.indexService = NewIndexService(a.logger, a.rdbConn, a.config, projections, cronJobs)
+	if a.config.KafkaService.Producer.Enable {
+		eventPublisher, err := a.newEventPublisher()
+		if err != nil {
+			return fmt.Errorf("error creating Kafka event publisher: %v", err)
+		}
+		a.indexService.UseEventPublisher(eventPublisher)
+	}
+	return nil
 }
 
-func (a *app) Run() {
-	if a.httpAPIServer != nil {
+// newEventPublisher connects a publisher of the synchronized events to the Kafka service with the
+// routes of the producer
+func (a *app) newEventPublisher() (*producer.EventPublisher, error) {
+	producerConfig := a.config.KafkaService.Producer
+	encoding, err := producer.ParseEncoding(producerConfig.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	router := producer.NewRouter(producerConfig.DefaultTopic)
+	for _, route := range producerConfig.Routes {
+		if err = router.AddRoute(route.Event, producer.Route{
+			Topic:        route.Topic,
+			Key:          route.Key,
+			AddressField: route.AddressField,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// The connection settings are shared with the consumers
+	writer, err := worker_consumer.NewConsumer[json.RawMessage](
+		context.Background(), a.config, "", "", a.logger,
+	).CreateWriter()
+	if err != nil {
+		return nil, err
+	}
+	return producer.NewEventPublisher(writer, router, encoding), nil
+}
+
+// GetProjectionRebuilder returns the rebuilder of the projections replaying the event store, nil when
+// the index service is disabled or not in event store mode
+func (a *app) GetProjectionRebuilder() projection_entity.Rebuilder {
+	if a.indexService == nil {
+		return nil
+	}
+	return a.indexService.ProjectionRebuilder()
+}
+
+// Run starts all the enabled services and blocks until the context is done or any of the services
+// fails. All the services are then stopped gracefully and the first failure is returned.
+func (a *app) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, 1)
+	runService := func(name string, run func() error) {
+		wg.Add(1)
 		go func() {
-			if runErr := a.httpAPIServer.Run(); runErr != nil {
-				a.logger.Panicf("%v", runErr)
+			defer wg.Done()
+			if runErr := run(); runErr != nil {
+				a.logger.Errorf("error running %s: %v", name, runErr)
+				// Only the first failure is returned
+				select {
+				case errCh <- runErr:
+				default:
+				}
+				cancel()
 			}
 		}()
 	}
 
+	if a.httpAPIServer != nil {
+		runService("HTTP API server", a.httpAPIServer.Run)
+	}
+
 	if a.indexService != nil {
-		go func() {
-			if runErr := a.indexService.Run(); runErr != nil {
-				a.logger.Panicf("%v", runErr)
-			}
-		}()
+		runService("index service", func() error {
+			return a.indexService.Run(ctx)
+		})
 	}
 
 	if a.config.Prometheus.Enable {
-		go func() {
-			if runErr := prometheus.Run(a.config.Prometheus.ExportPath, a.config.Prometheus.Port); runErr != nil {
-				a.logger.Panicf("%v", runErr)
-			}
-		}()
+		runService("prometheus exporter", func() error {
+			return prometheus.Run(ctx, a.config.Prometheus.ExportPath, a.config.Prometheus.Port)
+		})
 	}
 
 	if a.config.KafkaService.EnableConsumer {
-		sigchan := make(chan os.Signal, 1)
-		signal.Notify(sigchan, os.Interrupt)
-
-		go func() {
-			if runErr := worker_consumer.RunInternalTxsConsumer(a.rdbConn.ToHandle(), a.config, a.logger, a.evmUtil, sigchan); runErr != nil {
-				a.logger.Errorf("%v", runErr)
-			}
-		}()
-		go func() {
-			if runErr := worker_consumer.RunEvmTxsConsumer(a.rdbConn.ToHandle(), a.config, a.logger, sigchan); runErr != nil {
-				a.logger.Errorf("%v", runErr)
-			}
-		}()
-		go func() {
-			if runErr := worker_consumer.RunTokenTransfersConsumer(a.rdbConn.ToHandle(), a.config, a.logger, sigchan); runErr != nil {
-				a.logger.Errorf("%v", runErr)
+		topicHandlerParams := worker_consumer.TopicHandlerParams{
+			RdbConn:   a.rdbConn,
+			RdbHandle: a.rdbConn.ToHandle(),
+			Config:    a.config,
+			Logger:    a.logger,
+			EvmUtil:   a.evmUtil,
+
+			JsonrpcClient: worker_consumer.NewJsonrpcClient(a.logger, a.config),
+		}
+		for _, consumerConfig := range a.config.KafkaService.Consumers {
+			if !consumerConfig.Enable {
+				continue
 			}
-		}()
+			consumerConfig := consumerConfig
+			runService(consumerConfig.Topic+" consumer", func() error {
+				topicHandler := worker_consumer.TopicHandlers.Get(consumerConfig.Topic)
+				if topicHandler == nil {
+					return fmt.Errorf(
+						"no handler of Kafka topic %s, available topics: %v",
+						consumerConfig.Topic, worker_consumer.TopicHandlers.Topics(),
+					)
+				}
+				return topicHandler.Run(ctx, topicHandlerParams, consumerConfig)
+			})
+		}
+	}
+
+	<-ctx.Done()
+	a.logger.Info("shutting down")
+
+	if a.httpAPIServer != nil {
+		if err := a.httpAPIServer.Shutdown(); err != nil {
+			a.logger.Errorf("%v", err)
+		}
+	}
+	for _, schedule := range a.cronSchedules {
+		schedule.Stop()
 	}
 
-	select {}
+	wg.Wait()
+	close(errCh)
+	a.logger.Info("all services are stopped")
+
+	return <-errCh
 }
 
 func (a *app) RunCronJobsStats(rdbHandle *rdb.Handle) {
@@ -237,6 +334,7 @@ func (a *app) RunCronJobsStats(rdbHandle *rdb.Handle) {
 		})
 
 		s.Start()
+		a.cronSchedules = append(a.cronSchedules, s)
 	}
 }
 
@@ -408,5 +506,6 @@ func (a *app) RunCronJobsReportDashboard(rdbHandle *rdb.Handle) {
 		})
 
 		s.Start()
+		a.cronSchedules = append(a.cronSchedules, s)
 	}
 }
@@ -0,0 +1,34 @@
+package bootstrap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AstraProtocol/astra-indexing/bootstrap/config"
+	"github.com/AstraProtocol/astra-indexing/external/cache"
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+)
+
+// SetupCache configures the cache shared by the HTTP clients, the handlers and the views. An invalid
+// Redis cache fails the startup, as the invalidated responses would stay in the cache of each instance.
+func SetupCache(logger applogger.Logger, config *config.Config) error {
+	var localExpiration time.Duration
+	if config.Cache.LocalExpiration != "" {
+		var err error
+		localExpiration, err = time.ParseDuration(config.Cache.LocalExpiration)
+		if err != nil {
+			return fmt.Errorf("error parsing cache LocalExpiration string to duration %v", err)
+		}
+	}
+
+	if err := cache.Configure(cache.Config{
+		Backend:         config.Cache.Backend,
+		RedisURL:        config.Cache.RedisURL,
+		LocalCapacity:   config.Cache.LocalCapacity,
+		LocalExpiration: localExpiration,
+	}); err != nil {
+		logger.Errorf("error setting up cache, the shared cache is disabled: %v", err)
+		return fmt.Errorf("error setting up cache: %v", err)
+	}
+	return nil
+}
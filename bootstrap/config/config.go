@@ -1,8 +1,35 @@
 package config
 
+import (
+	"fmt"
+	"strings"
+)
+
 const SYSTEM_MODE_EVENT_STORE = "EVENT_STORE"
 const SYSTEM_MODE_TENDERMINT_DIRECT = "TENDERMINT_DIRECT"
 
+const SYNC_STRATEGY_WINDOW = "WINDOW"
+const SYNC_STRATEGY_PIPELINE = "PIPELINE"
+
+var SYNC_STRATEGIES = []string{SYNC_STRATEGY_PIPELINE, SYNC_STRATEGY_WINDOW}
+
+// ParseSyncStrategy returns the configured sync strategy, PIPELINE when it is not set. It fails on
+// unknown strategies instead of falling back to the default one.
+func ParseSyncStrategy(syncStrategy string) (string, error) {
+	if syncStrategy == "" {
+		return SYNC_STRATEGY_PIPELINE, nil
+	}
+	for _, validSyncStrategy := range SYNC_STRATEGIES {
+		if syncStrategy == validSyncStrategy {
+			return syncStrategy, nil
+		}
+	}
+	return "", fmt.Errorf(
+		"unsupported sync strategy: %s, valid values are %s",
+		syncStrategy, strings.Join(SYNC_STRATEGIES, ", "),
+	)
+}
+
 type Config struct {
 	Blockchain             Blockchain             `yaml:"blockchain" toml:"blockchain" xml:"blockchain" json:"blockchain"`
 	IndexService           IndexService           `yaml:"index_service" toml:"index_service" xml:"index_service" json:"index_service"`
@@ -19,18 +46,36 @@ type Config struct {
 	CronjobStats           CronjobStats           `yaml:"cronjob_stats" toml:"cronjob_stats" xml:"cronjob_stats" json:"cronjob_stats"`
 	KafkaService           KafkaService           `yaml:"kafka_service" toml:"kafka_service" xml:"kafka_service" json:"kafka_service"`
 	CronjobReportDashboard CronjobReportDashboard `yaml:"cronjob_report_dashboard" toml:"cronjob_report_dashboard" xml:"cronjob_report_dashboard" json:"cronjob_report_dashboard"`
+	Cache                  Cache                  `yaml:"cache" toml:"cache" xml:"cache" json:"cache"`
 }
 
 type IndexService struct {
 	Enable                     bool                       `yaml:"enable" toml:"enable" xml:"enable" json:"enable,omitempty"`
 	StartingBlockHeight        int64                      `yaml:"starting_block_height" toml:"starting_block_height" xml:"starting_block_height" json:"starting_block_height,omitempty"`
 	Mode                       string                     `yaml:"mode" toml:"mode" xml:"mode" json:"mode,omitempty"`
+	SyncStrategy               string                     `yaml:"sync_strategy" toml:"sync_strategy" xml:"sync_strategy" json:"sync_strategy,omitempty"`
 	WindowSize                 int                        `yaml:"window_size" toml:"window_size" xml:"window_size" json:"window_size,omitempty"`
+	SyncPipeline               SyncPipeline               `yaml:"sync_pipeline" toml:"sync_pipeline" xml:"sync_pipeline" json:"sync_pipeline"`
 	Concurrency                int                        `yaml:"concurrency" toml:"concurrency" xml:"concurrency" json:"concurrency,omitempty"`
 	Projection                 Projection                 `yaml:"projection" toml:"projection" xml:"projection" json:"projection"`
 	CronJob                    CronJob                    `yaml:"cron_job" toml:"cron_job" xml:"cron_job" json:"cron_job"`
 	CosmosVersionEnabledHeight CosmosVersionEnabledHeight `yaml:"cosmos_version_enabled_height" toml:"cosmos_version_enabled_height" xml:"cosmos_version_enabled_height" json:"cosmos_version_enabled_height"`
 	GithubAPI                  GithubAPI                  `yaml:"github_api" toml:"github_api" xml:"github_api" json:"github_api"`
+	BlockArchiveDir            string                     `yaml:"block_archive_dir" toml:"block_archive_dir" xml:"block_archive_dir" json:"block_archive_dir,omitempty"`
+	EventStore                 EventStore                 `yaml:"event_store" toml:"event_store" xml:"event_store" json:"event_store"`
+}
+
+type EventStore struct {
+	ArchiveDir             string `yaml:"archive_dir" toml:"archive_dir" xml:"archive_dir" json:"archive_dir,omitempty"`
+	ArchiveRetainedHeights int64  `yaml:"archive_retained_heights" toml:"archive_retained_heights" xml:"archive_retained_heights" json:"archive_retained_heights,omitempty"`
+	ArchiveIntervalMinutes int64  `yaml:"archive_interval_minutes" toml:"archive_interval_minutes" xml:"archive_interval_minutes" json:"archive_interval_minutes,omitempty"`
+}
+
+type SyncPipeline struct {
+	MinConcurrency  int   `yaml:"min_concurrency" toml:"min_concurrency" xml:"min_concurrency" json:"min_concurrency,omitempty"`
+	MaxConcurrency  int   `yaml:"max_concurrency" toml:"max_concurrency" xml:"max_concurrency" json:"max_concurrency,omitempty"`
+	BufferSize      int   `yaml:"buffer_size" toml:"buffer_size" xml:"buffer_size" json:"buffer_size,omitempty"`
+	TargetLatencyMs int64 `yaml:"target_latency_ms" toml:"target_latency_ms" xml:"target_latency_ms" json:"target_latency_ms,omitempty"`
 }
 
 type HTTPService struct {
@@ -40,18 +85,86 @@ type HTTPService struct {
 	CorsAllowedOrigins []string `yaml:"cors_allowed_origins" toml:"cors_allowed_origins" xml:"cors_allowed_origins" json:"cors_allowed_origins,omitempty"`
 	CorsAllowedMethods []string `yaml:"cors_allowed_methods" toml:"cors_allowed_methods" xml:"cors_allowed_methods" json:"cors_allowed_methods,omitempty"`
 	CorsAllowedHeaders []string `yaml:"cors_allowed_headers" toml:"cors_allowed_headers" xml:"cors_allowed_headers" json:"cors_allowed_headers,omitempty"`
+	EnableAdminAPI     bool     `yaml:"enable_admin_api" toml:"enable_admin_api" xml:"enable_admin_api" json:"enable_admin_api,omitempty"`
+	APIAuth            APIAuth  `yaml:"api_auth" toml:"api_auth" xml:"api_auth" json:"api_auth"`
+	GraphQL            GraphQL  `yaml:"graphql" toml:"graphql" xml:"graphql" json:"graphql"`
+	Search             Search   `yaml:"search" toml:"search" xml:"search" json:"search"`
+}
+
+type Search struct {
+	BlockscoutEnrichment bool   `yaml:"blockscout_enrichment" toml:"blockscout_enrichment" xml:"blockscout_enrichment" json:"blockscout_enrichment,omitempty"`
+	BlockscoutTimeout    string `yaml:"blockscout_timeout" toml:"blockscout_timeout" xml:"blockscout_timeout" json:"blockscout_timeout,omitempty"`
+}
+
+type GraphQL struct {
+	Enable        bool `yaml:"enable" toml:"enable" xml:"enable" json:"enable,omitempty"`
+	MaxDepth      int  `yaml:"max_depth" toml:"max_depth" xml:"max_depth" json:"max_depth,omitempty"`
+	MaxComplexity int  `yaml:"max_complexity" toml:"max_complexity" xml:"max_complexity" json:"max_complexity,omitempty"`
+}
+
+type APIAuth struct {
+	Enable                    bool            `yaml:"enable" toml:"enable" xml:"enable" json:"enable,omitempty"`
+	RequireAPIKey             bool            `yaml:"require_api_key" toml:"require_api_key" xml:"require_api_key" json:"require_api_key,omitempty"`
+	Header                    string          `yaml:"header" toml:"header" xml:"header" json:"header,omitempty"`
+	ClientIPHeader            string          `yaml:"client_ip_header" toml:"client_ip_header" xml:"client_ip_header" json:"client_ip_header,omitempty"`
+	TrustedProxyHops          int             `yaml:"trusted_proxy_hops" toml:"trusted_proxy_hops" xml:"trusted_proxy_hops" json:"trusted_proxy_hops,omitempty"`
+	FailedKeyLookupRate       float64         `yaml:"failed_key_lookup_rate" toml:"failed_key_lookup_rate" xml:"failed_key_lookup_rate" json:"failed_key_lookup_rate,omitempty"`
+	FailedKeyLookupBurst      int             `yaml:"failed_key_lookup_burst" toml:"failed_key_lookup_burst" xml:"failed_key_lookup_burst" json:"failed_key_lookup_burst,omitempty"`
+	PublicPathPrefixes        []string        `yaml:"public_path_prefixes" toml:"public_path_prefixes" xml:"public_path_prefixes" json:"public_path_prefixes,omitempty"`
+	UsageFlushIntervalSeconds int             `yaml:"usage_flush_interval_seconds" toml:"usage_flush_interval_seconds" xml:"usage_flush_interval_seconds" json:"usage_flush_interval_seconds,omitempty"`
+	DefaultRouteGroup         APIRouteGroup   `yaml:"default_route_group" toml:"default_route_group" xml:"default_route_group" json:"default_route_group"`
+	RouteGroups               []APIRouteGroup `yaml:"route_groups" toml:"route_groups" xml:"route_groups" json:"route_groups,omitempty"`
+}
+
+type APIRouteGroup struct {
+	Name         string   `yaml:"name" toml:"name" xml:"name" json:"name,omitempty"`
+	PathPrefixes []string `yaml:"path_prefixes" toml:"path_prefixes" xml:"path_prefixes" json:"path_prefixes,omitempty"`
+	PerKeyRate   float64  `yaml:"per_key_rate" toml:"per_key_rate" xml:"per_key_rate" json:"per_key_rate,omitempty"`
+	PerKeyBurst  int      `yaml:"per_key_burst" toml:"per_key_burst" xml:"per_key_burst" json:"per_key_burst,omitempty"`
+	PerIPRate    float64  `yaml:"per_ip_rate" toml:"per_ip_rate" xml:"per_ip_rate" json:"per_ip_rate,omitempty"`
+	PerIPBurst   int      `yaml:"per_ip_burst" toml:"per_ip_burst" xml:"per_ip_burst" json:"per_ip_burst,omitempty"`
 }
 
 type KafkaService struct {
-	EnableConsumer     bool     `yaml:"enable_consumer" toml:"enable_consumer" xml:"enable_consumer" json:"enable_consumer,omitempty"`
-	Brokers            []string `yaml:"brokers" toml:"brokers" xml:"brokers" json:"brokers,omitempty"`
-	GroupID            string   `yaml:"group_id" toml:"group_id" xml:"group_id" json:"group_id,omitempty"`
-	User               string   `yaml:"user" toml:"user" xml:"user" json:"user,omitempty"`
-	Password           string   `yaml:"password" toml:"password" xml:"password" json:"password,omitempty"`
-	AuthenticationType string   `yaml:"authentication_type" toml:"authentication_type" xml:"authentication_type" json:"authentication_type,omitempty"`
-	CaCertPath         string   `yaml:"ca_cert_path" toml:"ca_cert_path" xml:"ca_cert_path" json:"ca_cert_path,omitempty"`
-	TlsCertPath        string   `yaml:"tls_cert_path" toml:"tls_cert_path" xml:"tls_cert_path" json:"tls_cert_path,omitempty"`
-	TlsKeyPath         string   `yaml:"tls_key_path" toml:"tls_key_path" xml:"tls_key_path" json:"tls_key_path,omitempty"`
+	EnableConsumer         bool            `yaml:"enable_consumer" toml:"enable_consumer" xml:"enable_consumer" json:"enable_consumer,omitempty"`
+	Brokers                []string        `yaml:"brokers" toml:"brokers" xml:"brokers" json:"brokers,omitempty"`
+	GroupID                string          `yaml:"group_id" toml:"group_id" xml:"group_id" json:"group_id,omitempty"`
+	User                   string          `yaml:"user" toml:"user" xml:"user" json:"user,omitempty"`
+	Password               string          `yaml:"password" toml:"password" xml:"password" json:"password,omitempty"`
+	AuthenticationType     string          `yaml:"authentication_type" toml:"authentication_type" xml:"authentication_type" json:"authentication_type,omitempty"`
+	CaCertPath             string          `yaml:"ca_cert_path" toml:"ca_cert_path" xml:"ca_cert_path" json:"ca_cert_path,omitempty"`
+	TlsCertPath            string          `yaml:"tls_cert_path" toml:"tls_cert_path" xml:"tls_cert_path" json:"tls_cert_path,omitempty"`
+	TlsKeyPath             string          `yaml:"tls_key_path" toml:"tls_key_path" xml:"tls_key_path" json:"tls_key_path,omitempty"`
+	MaxAttempts            int             `yaml:"max_attempts" toml:"max_attempts" xml:"max_attempts" json:"max_attempts,omitempty"`
+	RetryInitialIntervalMs int64           `yaml:"retry_initial_interval_ms" toml:"retry_initial_interval_ms" xml:"retry_initial_interval_ms" json:"retry_initial_interval_ms,omitempty"`
+	RetryMaxIntervalMs     int64           `yaml:"retry_max_interval_ms" toml:"retry_max_interval_ms" xml:"retry_max_interval_ms" json:"retry_max_interval_ms,omitempty"`
+	NotReadyMaxWaitMs      int64           `yaml:"not_ready_max_wait_ms" toml:"not_ready_max_wait_ms" xml:"not_ready_max_wait_ms" json:"not_ready_max_wait_ms,omitempty"`
+	DeadLetterTopicSuffix  string          `yaml:"dead_letter_topic_suffix" toml:"dead_letter_topic_suffix" xml:"dead_letter_topic_suffix" json:"dead_letter_topic_suffix,omitempty"`
+	Consumers              []KafkaConsumer `yaml:"consumers" toml:"consumers" xml:"consumers" json:"consumers,omitempty"`
+	Producer               KafkaProducer   `yaml:"producer" toml:"producer" xml:"producer" json:"producer"`
+}
+
+type KafkaConsumer struct {
+	Topic          string `yaml:"topic" toml:"topic" xml:"topic" json:"topic,omitempty"`
+	Enable         bool   `yaml:"enable" toml:"enable" xml:"enable" json:"enable,omitempty"`
+	GroupID        string `yaml:"group_id" toml:"group_id" xml:"group_id" json:"group_id,omitempty"`
+	Concurrency    int    `yaml:"concurrency" toml:"concurrency" xml:"concurrency" json:"concurrency,omitempty"`
+	BatchSize      int    `yaml:"batch_size" toml:"batch_size" xml:"batch_size" json:"batch_size,omitempty"`
+	BatchMaxWaitMs int64  `yaml:"batch_max_wait_ms" toml:"batch_max_wait_ms" xml:"batch_max_wait_ms" json:"batch_max_wait_ms,omitempty"`
+}
+
+type KafkaProducer struct {
+	Enable       bool                 `yaml:"enable" toml:"enable" xml:"enable" json:"enable,omitempty"`
+	Encoding     string               `yaml:"encoding" toml:"encoding" xml:"encoding" json:"encoding,omitempty"`
+	DefaultTopic string               `yaml:"default_topic" toml:"default_topic" xml:"default_topic" json:"default_topic,omitempty"`
+	Routes       []KafkaProducerRoute `yaml:"routes" toml:"routes" xml:"routes" json:"routes,omitempty"`
+}
+
+type KafkaProducerRoute struct {
+	Event        string `yaml:"event" toml:"event" xml:"event" json:"event,omitempty"`
+	Topic        string `yaml:"topic" toml:"topic" xml:"topic" json:"topic,omitempty"`
+	Key          string `yaml:"key" toml:"key" xml:"key" json:"key,omitempty"`
+	AddressField string `yaml:"address_field" toml:"address_field" xml:"address_field" json:"address_field,omitempty"`
 }
 
 type Blockchain struct {
@@ -70,9 +183,10 @@ type Debug struct {
 }
 
 type TendermintApp struct {
-	HTTPRPCUrl           string `yaml:"http_rpc_url" toml:"http_rpc_url" xml:"http_rpc_url" json:"http_rpc_url,omitempty"`
-	Insecure             bool   `yaml:"insecure" toml:"insecure" xml:"insecure" json:"insecure,omitempty"`
-	StrictGenesisParsing bool   `yaml:"strict_genesis_parsing" toml:"strict_genesis_parsing" xml:"strict_genesis_parsing" json:"strict_genesis_parsing,omitempty"`
+	HTTPRPCUrl            string `yaml:"http_rpc_url" toml:"http_rpc_url" xml:"http_rpc_url" json:"http_rpc_url,omitempty"`
+	Insecure              bool   `yaml:"insecure" toml:"insecure" xml:"insecure" json:"insecure,omitempty"`
+	StrictGenesisParsing  bool   `yaml:"strict_genesis_parsing" toml:"strict_genesis_parsing" xml:"strict_genesis_parsing" json:"strict_genesis_parsing,omitempty"`
+	WebSocketSubscription bool   `yaml:"websocket_subscription" toml:"websocket_subscription" xml:"websocket_subscription" json:"websocket_subscription,omitempty"`
 }
 
 type CosmosApp struct {
@@ -118,6 +232,7 @@ type Logger struct {
 type Projection struct {
 	Enables      []string               `yaml:"enables" toml:"enables" xml:"enables" json:"enables,omitempty"`
 	ExtraConfigs map[string]interface{} `yaml:"extra_configs" toml:"extra_configs" xml:"extra_configs" json:"extra_configs,omitempty"`
+	BatchSize    int64                  `yaml:"batch_size" toml:"batch_size" xml:"batch_size" json:"batch_size,omitempty"`
 }
 
 type CronJob struct {
@@ -149,3 +264,10 @@ type CronjobReportDashboard struct {
 	Enable      bool   `yaml:"enable" toml:"enable" xml:"enable" json:"enable,omitempty"`
 	TikiAddress string `yaml:"tiki_address" toml:"tiki_address" xml:"tiki_address" json:"tiki_address,omitempty"`
 }
+
+type Cache struct {
+	Backend         string `yaml:"backend" toml:"backend" xml:"backend" json:"backend,omitempty"`
+	RedisURL        string `yaml:"redis_url" toml:"redis_url" xml:"redis_url" json:"redis_url,omitempty"`
+	LocalCapacity   uint64 `yaml:"local_capacity" toml:"local_capacity" xml:"local_capacity" json:"local_capacity,omitempty"`
+	LocalExpiration string `yaml:"local_expiration" toml:"local_expiration" xml:"local_expiration" json:"local_expiration,omitempty"`
+}
@@ -1,11 +1,16 @@
 package bootstrap
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
 	"github.com/AstraProtocol/astra-indexing/bootstrap/config"
 	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
 	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/apiauth"
 	"github.com/lab259/cors"
 	"github.com/valyala/fasthttp"
 )
@@ -18,9 +23,23 @@ type HTTPAPIServer struct {
 
 	pprof config.Debug
 
-	httpServer *httpapi.Server
+	httpServer  *httpapi.Server
+	pprofServer *httpapi.Server
+
+	authenticator      *apiauth.Authenticator
+	usageFlushInterval time.Duration
+
+	// Guards the usage flush, Shutdown may be called before or while running
+	mutex          sync.Mutex
+	isShutdown     bool
+	stopUsageFlush context.CancelFunc
+	usageFlushDone chan struct{}
 }
 
+const PPROF_PATH = "/debug/pprof"
+
+const DEFAULT_API_USAGE_FLUSH_INTERVAL = 10 * time.Second
+
 type RouteRegistry interface {
 	Register(*httpapi.Server, string)
 }
@@ -44,6 +63,15 @@ func NewHTTPAPIServer(logger applogger.Logger, config *config.Config) *HTTPAPISe
 		})
 	}
 
+	var pprofServer *httpapi.Server
+	if config.Debug.PprofEnable {
+		pprofServer = httpapi.NewServer(
+			config.Debug.PprofListeningAddress,
+		).WithLogger(
+			logger,
+		).WithPprof(PPROF_PATH)
+	}
+
 	return &HTTPAPIServer{
 		logger: logger,
 
@@ -52,7 +80,60 @@ func NewHTTPAPIServer(logger applogger.Logger, config *config.Config) *HTTPAPISe
 
 		pprof: config.Debug,
 
-		httpServer: httpServer,
+		httpServer:  httpServer,
+		pprofServer: pprofServer,
+	}
+}
+
+// UseAPIAuth identifies and throttles the clients of the HTTP API with the API keys and the usage
+// recorded in the database
+func (server *HTTPAPIServer) UseAPIAuth(rdbConn rdb.Conn, apiAuthConfig config.APIAuth) {
+	server.authenticator = apiauth.NewAuthenticator(
+		server.logger,
+		newAuthenticatorConfig(apiAuthConfig),
+		apiauth.NewRDbKeyStore(rdbConn.ToHandle()),
+		apiauth.NewRDbUsageStore(rdbConn.ToHandle()),
+	)
+	server.httpServer.Use(server.authenticator.Middleware)
+
+	server.usageFlushInterval = DEFAULT_API_USAGE_FLUSH_INTERVAL
+	if apiAuthConfig.UsageFlushIntervalSeconds > 0 {
+		server.usageFlushInterval = time.Duration(apiAuthConfig.UsageFlushIntervalSeconds) * time.Second
+	}
+}
+
+func newAuthenticatorConfig(apiAuthConfig config.APIAuth) apiauth.Config {
+	routeGroups := make([]apiauth.RouteGroup, 0, len(apiAuthConfig.RouteGroups))
+	for _, routeGroup := range apiAuthConfig.RouteGroups {
+		routeGroups = append(routeGroups, newRouteGroup(routeGroup))
+	}
+	return apiauth.Config{
+		RequireAPIKey:    apiAuthConfig.RequireAPIKey,
+		Header:           apiAuthConfig.Header,
+		ClientIPHeader:   apiAuthConfig.ClientIPHeader,
+		TrustedProxyHops: apiAuthConfig.TrustedProxyHops,
+		FailedKeyLookupsPerIP: apiauth.Limit{
+			Rate:  apiAuthConfig.FailedKeyLookupRate,
+			Burst: apiAuthConfig.FailedKeyLookupBurst,
+		},
+		PublicPathPrefixes: apiAuthConfig.PublicPathPrefixes,
+		DefaultRouteGroup:  newRouteGroup(apiAuthConfig.DefaultRouteGroup),
+		RouteGroups:        routeGroups,
+	}
+}
+
+func newRouteGroup(routeGroup config.APIRouteGroup) apiauth.RouteGroup {
+	return apiauth.RouteGroup{
+		Name:         routeGroup.Name,
+		PathPrefixes: routeGroup.PathPrefixes,
+		PerKey: apiauth.Limit{
+			Rate:  routeGroup.PerKeyRate,
+			Burst: routeGroup.PerKeyBurst,
+		},
+		PerIP: apiauth.Limit{
+			Rate:  routeGroup.PerIPRate,
+			Burst: routeGroup.PerIPBurst,
+		},
 	}
 }
 
@@ -65,27 +146,75 @@ func (server *HTTPAPIServer) RegisterRoutes(registry RouteRegistry) {
 	registry.Register(server.httpServer, server.routePrefix)
 }
 
+// Run serves the HTTP API until it is shut down. It fails when either the HTTP API or the pprof
+// server fails to serve.
 func (server *HTTPAPIServer) Run() error {
-	if server.pprof.PprofEnable {
-		pprofServer := httpapi.NewServer(
-			server.pprof.PprofListeningAddress,
-		).WithLogger(
-			server.logger,
-		)
-		fixPath := "/debug/pprof"
-		pprofServer = pprofServer.WithPprof(fixPath)
+	server.startUsageFlush()
+
+	serveErrCh := make(chan error, 2)
+	if server.pprofServer != nil {
 		go func() {
-			server.logger.Infof("pprof server start listening on: %s%s", server.pprof.PprofListeningAddress, fixPath)
-			if err := pprofServer.ListenAndServe(); err != nil {
-				panic(fmt.Errorf("error listening and serving HTTP pprof server: %w", err))
+			server.logger.Infof("pprof server start listening on: %s%s", server.pprof.PprofListeningAddress, PPROF_PATH)
+			if err := server.pprofServer.ListenAndServe(); err != nil {
+				serveErrCh <- fmt.Errorf("error listening and serving HTTP pprof server: %v", err)
 			}
 		}()
 	}
 
-	server.logger.Infof("server start listening on: %s", server.listeningAddress)
 	server.httpServer.UpdateIsAddMetrics()
-	if err := server.httpServer.ListenAndServe(); err != nil {
-		return fmt.Errorf("error listening and serving HTTP API server: %v", err)
+	go func() {
+		server.logger.Infof("server start listening on: %s", server.listeningAddress)
+		if err := server.httpServer.ListenAndServe(); err != nil {
+			serveErrCh <- fmt.Errorf("error listening and serving HTTP API server: %v", err)
+			return
+		}
+		serveErrCh <- nil
+	}()
+
+	return <-serveErrCh
+}
+
+// startUsageFlush records the usage counted by the authenticator periodically until shut down
+func (server *HTTPAPIServer) startUsageFlush() {
+	if server.authenticator == nil {
+		return
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if server.isShutdown || server.stopUsageFlush != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	server.stopUsageFlush = cancel
+	server.usageFlushDone = make(chan struct{})
+	go func(done chan struct{}) {
+		defer close(done)
+		server.authenticator.Run(ctx, server.usageFlushInterval)
+	}(server.usageFlushDone)
+}
+
+// Shutdown gracefully drains the serving requests. It is safe to call before or while running, the
+// servers then never start serving.
+func (server *HTTPAPIServer) Shutdown() error {
+	server.mutex.Lock()
+	server.isShutdown = true
+	stopUsageFlush, usageFlushDone := server.stopUsageFlush, server.usageFlushDone
+	server.mutex.Unlock()
+
+	if server.pprofServer != nil {
+		if err := server.pprofServer.Shutdown(); err != nil {
+			return fmt.Errorf("error shutting down HTTP pprof server: %v", err)
+		}
+	}
+	if err := server.httpServer.Shutdown(); err != nil {
+		return fmt.Errorf("error shutting down HTTP API server: %v", err)
+	}
+	// The usage counted by the drained requests is recorded once more
+	if stopUsageFlush != nil {
+		stopUsageFlush()
+		<-usageFlushDone
 	}
 
 	return nil
@@ -1,7 +1,9 @@
 package bootstrap
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	event_interface "github.com/AstraProtocol/astra-indexing/appinterface/event"
@@ -11,8 +13,10 @@ import (
 	"github.com/AstraProtocol/astra-indexing/entity/event"
 	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
 	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/external/primptr"
 	event_usecase "github.com/AstraProtocol/astra-indexing/usecase/event"
 	"github.com/AstraProtocol/astra-indexing/usecase/parser/utils"
+	"github.com/AstraProtocol/astra-indexing/usecase/syncstrategy"
 )
 
 type IndexService struct {
@@ -21,16 +25,28 @@ type IndexService struct {
 	projections []projection_entity.Projection
 	cronJobs    []projection_entity.CronJob
 
+	// Only used in event store mode
+	eventRegistry     *event.Registry
+	projectionManager *projection_entity.StoreBasedManager
+	// nil when the event store is not archived
+	eventArchiver *event_interface.Archiver
+	// Publishes the synchronized events downstream, nil when they are not published
+	eventPublisher eventhandler_interface.EventPublisher
+
 	mode                     string
 	accountAddressPrefix     string
 	consNodeAddressPrefix    string
 	bondingDenom             string
+	syncStrategy             string
 	windowSize               int
+	syncPipeline             syncstrategy.PipelineConfig
 	tendermintHTTPRPCURL     string
 	cosmosAppHTTPRPCURL      string
 	insecureTendermintClient bool
 	insecureCosmosAppClient  bool
 	strictGenesisParsing     bool
+	webSocketSubscription    bool
+	blockArchiveDir          string
 	startingBlockHeight      int64
 	concurrency              int
 
@@ -48,16 +64,40 @@ func NewIndexService(
 	projections []projection_entity.Projection,
 	cronJobs []projection_entity.CronJob,
 ) *IndexService {
+	eventRegistry := event.NewRegistry()
+	event_usecase.RegisterEvents(eventRegistry)
+	eventStore := event_interface.NewRDbStore(rdbConn.ToHandle(), eventRegistry)
+	projectionManager := projection_entity.NewStoreBasedManagerWithOptions(
+		logger, eventStore, projection_entity.StoreBasedManagerOptions{
+			MaybeMaxBatchSize: primptr.Int64(config.IndexService.Projection.BatchSize),
+		},
+	)
+	var eventArchiver *event_interface.Archiver
+	if config.IndexService.EventStore.ArchiveDir != "" {
+		eventArchiver = event_interface.NewArchiver(
+			logger, eventStore, projectionManager, event_interface.ArchiverConfig{
+				Dir:             config.IndexService.EventStore.ArchiveDir,
+				RetainedHeights: config.IndexService.EventStore.ArchiveRetainedHeights,
+				Interval:        time.Duration(config.IndexService.EventStore.ArchiveIntervalMinutes) * time.Minute,
+			},
+		)
+	}
+
 	return &IndexService{
 		logger:      logger,
 		rdbConn:     rdbConn,
 		projections: projections,
 		cronJobs:    cronJobs,
 
+		eventRegistry:     eventRegistry,
+		projectionManager: projectionManager,
+		eventArchiver:     eventArchiver,
+
 		mode:                     config.IndexService.Mode,
 		consNodeAddressPrefix:    config.Blockchain.ConNodeAddressPrefix,
 		accountAddressPrefix:     config.Blockchain.AccountAddressPrefix,
 		bondingDenom:             config.Blockchain.BondingDenom,
+		syncStrategy:             config.IndexService.SyncStrategy,
 		windowSize:               config.IndexService.WindowSize,
 		concurrency:              config.IndexService.Concurrency,
 		tendermintHTTPRPCURL:     config.TendermintApp.HTTPRPCUrl,
@@ -65,16 +105,40 @@ func NewIndexService(
 		insecureTendermintClient: config.TendermintApp.Insecure,
 		insecureCosmosAppClient:  config.CosmosApp.Insecure,
 		strictGenesisParsing:     config.TendermintApp.StrictGenesisParsing,
+		webSocketSubscription:    config.TendermintApp.WebSocketSubscription,
+		blockArchiveDir:          config.IndexService.BlockArchiveDir,
 		startingBlockHeight:      config.IndexService.StartingBlockHeight,
 		cosmosVersionBlockHeight: utils.CosmosVersionBlockHeight{
 			V0_42_7: utils.ParserBlockHeight(config.IndexService.CosmosVersionEnabledHeight.V0_42_7),
 		},
+		syncPipeline: syncstrategy.PipelineConfig{
+			MinConcurrency: config.IndexService.SyncPipeline.MinConcurrency,
+			MaxConcurrency: config.IndexService.SyncPipeline.MaxConcurrency,
+			BufferSize:     config.IndexService.SyncPipeline.BufferSize,
+			TargetLatency:  time.Duration(config.IndexService.SyncPipeline.TargetLatencyMs) * time.Millisecond,
+		},
 		GithubAPIUser:  config.IndexService.GithubAPI.Username,
 		GithubAPIToken: config.IndexService.GithubAPI.Token,
 	}
 }
 
-func (service *IndexService) Run() error {
+// UseEventPublisher publishes all the synchronized events with the publisher after they have been
+// handled
+func (service *IndexService) UseEventPublisher(publisher eventhandler_interface.EventPublisher) {
+	service.eventPublisher = publisher
+}
+
+// withEventSink wraps the handler of the synchronized events with the Kafka sink when the events are
+// published
+func (service *IndexService) withEventSink(handler eventhandler_interface.Handler) eventhandler_interface.Handler {
+	if service.eventPublisher == nil {
+		return handler
+	}
+	return eventhandler_interface.NewKafkaSinkHandler(service.logger, service.rdbConn, handler, service.eventPublisher)
+}
+
+// Run starts indexing until the context is done
+func (service *IndexService) Run(ctx context.Context) error {
 	// run polling tendermint manager, update view tables directly
 	infoManager := NewInfoManager(
 		service.logger,
@@ -86,19 +150,19 @@ func (service *IndexService) Run() error {
 
 	switch service.mode {
 	case config.SYSTEM_MODE_EVENT_STORE:
-		infoManager.Run()
-		service.runCronJobs()
-		return service.RunEventStoreMode()
+		infoManager.Run(ctx)
+		service.runCronJobs(ctx)
+		return service.RunEventStoreMode(ctx)
 	case config.SYSTEM_MODE_TENDERMINT_DIRECT:
-		infoManager.Run()
-		service.runCronJobs()
-		return service.RunTendermintDirectMode()
+		infoManager.Run(ctx)
+		service.runCronJobs(ctx)
+		return service.RunTendermintDirectMode(ctx)
 	default:
 		return fmt.Errorf("unsupported system mode: %s", service.mode)
 	}
 }
 
-func (service *IndexService) runCronJobs() {
+func (service *IndexService) runCronJobs(ctx context.Context) {
 	for i := range service.cronJobs {
 		cronJobClosure := service.cronJobs[i]
 		go func() {
@@ -110,42 +174,234 @@ func (service *IndexService) runCronJobs() {
 					logger.Errorf("error executing cron job: %v", cronJobErr)
 				}
 				logger.Infof("successfully executed cron job, going to execute again in %s", cronJobClosure.Interval())
-				<-time.After(cronJobClosure.Interval())
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(cronJobClosure.Interval()):
+				}
 			}
 		}()
 	}
 }
 
-func (service *IndexService) RunEventStoreMode() error {
-	eventRegistry := event.NewRegistry()
-	event_usecase.RegisterEvents(eventRegistry)
-	eventStore := event_interface.NewRDbStore(service.rdbConn.ToHandle(), eventRegistry)
+// ProjectionRebuilder returns the rebuilder of the projections replaying the event store, nil when
+// the projections are not running in event store mode
+func (service *IndexService) ProjectionRebuilder() projection_entity.Rebuilder {
+	if service.mode != config.SYSTEM_MODE_EVENT_STORE {
+		return nil
+	}
+	return service.projectionManager
+}
 
-	projectionManager := projection_entity.NewStoreBasedManager(service.logger, eventStore)
+func (service *IndexService) RunEventStoreMode(ctx context.Context) error {
+	projectionManager := service.projectionManager
 
 	for _, projection := range service.projections {
 		if err := projectionManager.RegisterProjection(projection); err != nil {
 			return fmt.Errorf("error registering projection `%s` to manager %v", projection.Id(), err)
 		}
 	}
-	projectionManager.RunInBackground()
+	if err := projectionManager.RunInBackground(ctx); err != nil {
+		return err
+	}
+
+	archiverDone := make(chan struct{})
+	go func() {
+		defer close(archiverDone)
+		if service.eventArchiver != nil {
+			service.eventArchiver.Run(ctx)
+		}
+	}()
 
 	eventStoreHandler := eventhandler_interface.NewRDbEventStoreHandler(
 		service.logger,
 		service.rdbConn,
-		eventRegistry,
+		service.eventRegistry,
+	)
+	// Projections replaying the event store have to be rolled back together with the stored events
+	eventStoreHandler.AddRollbackDependent(projectionManager)
+	syncManager, err := service.newSyncManager(service.logger, service.withEventSink(eventStoreHandler))
+	if err != nil {
+		return fmt.Errorf("error creating sync manager %v", err)
+	}
+	if err := syncManager.Run(ctx); err != nil {
+		return fmt.Errorf("error running sync manager %v", err)
+	}
+	// Let the projections finish their current heights and the archiver its current partition before
+	// returning
+	projectionManager.Wait()
+	<-archiverDone
+
+	return nil
+}
+
+// DEFAULT_CATCH_UP_THRESHOLD is the number of heights a projection may lag behind the leading
+// projection and still join the shared synchronization on start. Projections lagging further catch
+// up with a synchronization of their own first, such that they do not hold back the others.
+const DEFAULT_CATCH_UP_THRESHOLD = int64(1000)
+
+// RunTendermintDirectMode synchronizes the blocks once for all projections and fans the events out to
+// them. Lagging or failing projections are synchronized on their own until they catch up. Dependent
+// projections never handle a height before the projections they depend on.
+func (service *IndexService) RunTendermintDirectMode(ctx context.Context) error {
+	if len(service.projections) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	projections, err := projection_entity.SortByDependencies(service.projections)
+	if err != nil {
+		return fmt.Errorf("error resolving projection dependencies: %w", err)
+	}
+	projectionsById := make(map[string]projection_entity.Projection, len(projections))
+	for _, projection := range projections {
+		projectionsById[projection.Id()] = projection
+	}
+
+	handlers := make([]eventhandler_interface.Handler, 0, len(projections))
+	handledHeights := make([]*int64, 0, len(projections))
+	leadingHeight := int64(-1)
+	for _, projection := range projections {
+		handledHeight, err := projection.GetLastHandledEventHeight()
+		if err != nil {
+			return fmt.Errorf("error getting last handled event height of projection `%s`: %v", projection.Id(), err)
+		}
+		dependencies := make([]projection_entity.Projection, 0)
+		for _, dependencyId := range projection_entity.GetDependencies(projection) {
+			dependencies = append(dependencies, projectionsById[dependencyId])
+		}
+		handlers = append(handlers, eventhandler_interface.NewProjectionHandlerWithDependencies(
+			service.logger, projection, dependencies,
+		))
+		handledHeights = append(handledHeights, handledHeight)
+		if handledHeight != nil && *handledHeight > leadingHeight {
+			leadingHeight = *handledHeight
+		}
+	}
+
+	// The shared synchronization starts from the lowest height among the projections joining it
+	lowestJoiningHeight := leadingHeight
+	isJoining := make([]bool, len(handlers))
+	for i, handledHeight := range handledHeights {
+		height := int64(-1)
+		if handledHeight != nil {
+			height = *handledHeight
+		}
+		if leadingHeight-height > DEFAULT_CATCH_UP_THRESHOLD {
+			continue
+		}
+		isJoining[i] = true
+		if height < lowestJoiningHeight {
+			lowestJoiningHeight = height
+		}
+	}
+	var fanOutHeight *int64
+	if lowestJoiningHeight >= 0 {
+		fanOutHeight = &lowestJoiningHeight
+	}
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, 1)
+	reportErr := func(err error) {
+		select {
+		case errCh <- err:
+		default:
+		}
+		// Stop the other synchronizations as the indexing is no longer complete
+		cancel()
+	}
+
+	var fanOutHandler *eventhandler_interface.FanOutHandler
+	catchUp := func(handler eventhandler_interface.Handler) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := service.runCatchUp(ctx, fanOutHandler, handler); err != nil {
+				reportErr(err)
+			}
+		}()
+	}
+	fanOutHandler = eventhandler_interface.NewFanOutHandler(
+		service.logger,
+		fanOutHeight,
+		func(handler eventhandler_interface.Handler, _ error) {
+			catchUp(handler)
+		},
+	)
+	for i, handler := range handlers {
+		if isJoining[i] && fanOutHandler.Join(handler, handledHeights[i]) {
+			continue
+		}
+		service.logger.Infof("projection `%s` is lagging behind, catching up on its own first", handler.Id())
+		catchUp(handler)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		syncManager, err := service.newSyncManager(service.logger, service.withEventSink(fanOutHandler))
+		if err != nil {
+			reportErr(fmt.Errorf("error creating sync manager %v", err))
+			return
+		}
+		if err := syncManager.Run(ctx); err != nil {
+			reportErr(fmt.Errorf("error running sync manager %v", err))
+		}
+	}()
+	wg.Wait()
+	close(errCh)
+
+	return <-errCh
+}
+
+// runCatchUp synchronizes the blocks for the handler on its own until it joins the fan-out handler
+// or the context is done
+func (service *IndexService) runCatchUp(
+	ctx context.Context,
+	fanOutHandler *eventhandler_interface.FanOutHandler,
+	handler eventhandler_interface.Handler,
+) error {
+	catchUpCtx, stopCatchUp := context.WithCancel(ctx)
+	defer stopCatchUp()
+
+	logger := service.logger.WithFields(applogger.LogFields{
+		"projection": handler.Id(),
+	})
+	syncManager, err := service.newSyncManager(
+		logger,
+		eventhandler_interface.NewCatchUpHandler(handler, fanOutHandler, stopCatchUp),
 	)
-	syncManager := NewSyncManager(
+	if err != nil {
+		return fmt.Errorf("error creating catch up sync manager of projection `%s` %v", handler.Id(), err)
+	}
+	if err := syncManager.Run(catchUpCtx); err != nil {
+		return fmt.Errorf("error running catch up sync manager of projection `%s` %v", handler.Id(), err)
+	}
+	return nil
+}
+
+func (service *IndexService) newSyncManager(
+	logger applogger.Logger,
+	eventHandler eventhandler_interface.Handler,
+) (*SyncManager, error) {
+	return NewSyncManager(
 		SyncManagerParams{
-			Logger:  service.logger,
+			Logger:  logger,
 			RDbConn: service.rdbConn,
 			Config: SyncManagerConfig{
+				SyncStrategy:             service.syncStrategy,
 				WindowSize:               service.windowSize,
+				Pipeline:                 service.syncPipeline,
 				TendermintRPCUrl:         service.tendermintHTTPRPCURL,
 				CosmosAppHTTPRPCURL:      service.cosmosAppHTTPRPCURL,
 				InsecureTendermintClient: service.insecureTendermintClient,
 				InsecureCosmosAppClient:  service.insecureCosmosAppClient,
 				StrictGenesisParsing:     service.strictGenesisParsing,
+				WebSocketSubscription:    service.webSocketSubscription,
+				BlockArchiveDir:          service.blockArchiveDir,
 				AccountAddressPrefix:     service.accountAddressPrefix,
 				StakingDenom:             service.bondingDenom,
 				StartingBlockHeight:      service.startingBlockHeight,
@@ -154,59 +410,12 @@ func (service *IndexService) RunEventStoreMode() error {
 		},
 		utils.NewCosmosParserManager(
 			utils.CosmosParserManagerParams{
-				Logger: service.logger,
+				Logger: logger,
 				Config: utils.CosmosParserManagerConfig{
 					CosmosVersionBlockHeight: service.cosmosVersionBlockHeight,
 				},
 			},
 		),
-		eventStoreHandler,
+		eventHandler,
 	)
-	if err := syncManager.Run(); err != nil {
-		return fmt.Errorf("error running sync manager %v", err)
-	}
-
-	return nil
-}
-
-func (service *IndexService) RunTendermintDirectMode() error {
-
-	for i := range service.projections {
-		go func(projection projection_entity.Projection) {
-			syncManager := NewSyncManager(
-				SyncManagerParams{
-					Logger: service.logger.WithFields(applogger.LogFields{
-						"projection": projection.Id(),
-					}),
-					RDbConn: service.rdbConn,
-					Config: SyncManagerConfig{
-						WindowSize:               service.windowSize,
-						TendermintRPCUrl:         service.tendermintHTTPRPCURL,
-						CosmosAppHTTPRPCURL:      service.cosmosAppHTTPRPCURL,
-						InsecureTendermintClient: service.insecureTendermintClient,
-						InsecureCosmosAppClient:  service.insecureCosmosAppClient,
-						AccountAddressPrefix:     service.accountAddressPrefix,
-						StakingDenom:             service.bondingDenom,
-						StartingBlockHeight:      service.startingBlockHeight,
-						Concurrency:              service.concurrency,
-					},
-				},
-				utils.NewCosmosParserManager(
-					utils.CosmosParserManagerParams{
-						Logger: service.logger.WithFields(applogger.LogFields{
-							"projection": projection.Id(),
-						}),
-						Config: utils.CosmosParserManagerConfig{
-							CosmosVersionBlockHeight: service.cosmosVersionBlockHeight,
-						},
-					},
-				),
-				eventhandler_interface.NewProjectionHandler(service.logger, projection),
-			)
-			if err := syncManager.Run(); err != nil {
-				panic(fmt.Sprintf("error running sync manager %v", err))
-			}
-		}(service.projections[i])
-	}
-	select {}
 }
@@ -1,6 +1,7 @@
 package bootstrap
 
 import (
+	"context"
 	"time"
 
 	"github.com/AstraProtocol/astra-indexing/appinterface/polling"
@@ -45,28 +46,35 @@ func NewInfoManager(
 
 }
 
-func (manager *InfoManager) Run() {
+// Run polls the chain status in background until the context is done
+func (manager *InfoManager) Run(ctx context.Context) {
 	manager.logger.Infof("InfoManager started")
 	go func() {
 		for {
-			status, err := manager.client.Status()
-			if err != nil {
-				manager.logger.Errorf("error querying Tendermint status: %v", err)
-				time.Sleep(manager.pollingInterval)
-				continue
-			}
-			result := (*status)["result"]
-			syncInfo := result.(map[string]interface{})["sync_info"]
-			latestHeight := syncInfo.(map[string]interface{})["latest_block_height"].(string)
+			manager.updateLatestHeight()
 
-			err = manager.viewStatus.Upsert("LatestHeight", latestHeight)
-			if err != nil {
-				manager.logger.Errorf("error upserting latest height: %v", err)
-				time.Sleep(manager.pollingInterval)
-				continue
+			select {
+			case <-ctx.Done():
+				manager.logger.Infof("InfoManager stopped")
+				return
+			case <-time.After(manager.pollingInterval):
 			}
-
-			time.Sleep(manager.pollingInterval)
 		}
 	}()
 }
+
+func (manager *InfoManager) updateLatestHeight() {
+	status, err := manager.client.Status()
+	if err != nil {
+		manager.logger.Errorf("error querying Tendermint status: %v", err)
+		return
+	}
+	result := (*status)["result"]
+	syncInfo := result.(map[string]interface{})["sync_info"]
+	latestHeight := syncInfo.(map[string]interface{})["latest_block_height"].(string)
+
+	err = manager.viewStatus.Upsert("LatestHeight", latestHeight)
+	if err != nil {
+		manager.logger.Errorf("error upserting latest height: %v", err)
+	}
+}
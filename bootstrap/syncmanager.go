@@ -1,17 +1,24 @@
 package bootstrap
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	cosmosapp_interface "github.com/AstraProtocol/astra-indexing/appinterface/cosmosapp"
 	eventhandler_interface "github.com/AstraProtocol/astra-indexing/appinterface/eventhandler"
+	tendermint_interface "github.com/AstraProtocol/astra-indexing/appinterface/tendermint"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/blockarchive"
 	cosmosapp_infrastructure "github.com/AstraProtocol/astra-indexing/infrastructure/cosmosapp"
 	"github.com/AstraProtocol/astra-indexing/usecase/model"
 	"github.com/cenkalti/backoff/v4"
 
 	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdbblockhashstore"
+	"github.com/AstraProtocol/astra-indexing/bootstrap/config"
 	command_entity "github.com/AstraProtocol/astra-indexing/entity/command"
 	"github.com/AstraProtocol/astra-indexing/entity/event"
 	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
@@ -28,23 +35,42 @@ const DEFAULT_POLLING_INTERVAL = 2500 * time.Millisecond
 const DEFAULT_MAX_RETRY_INTERVAL = 15 * time.Minute
 const DEFAULT_MAX_RETRY_TIME = MAX_RETRY_TIME_ALWAYS_RETRY
 
+// errStopSync stops the synchronization without error, it restarts from the last handled height on
+// next round
+var errStopSync = errors.New("stop synchronization")
+
+// DEFAULT_MAX_REORG_DEPTH is the number of recently indexed block hashes kept to find the common
+// ancestor when the chain reorganizes
+const DEFAULT_MAX_REORG_DEPTH = int64(100)
+
 type SyncManager struct {
 	rdbConn              rdb.Conn
-	tendermintClient     *tendermint.HTTPClient
+	tendermintClient     tendermint_interface.Client
+	tendermintRPCUrl     string
 	cosmosClient         cosmosapp_interface.Client
+	txClient             cosmosapp_interface.TxClient
 	logger               applogger.Logger
 	pollingInterval      time.Duration
 	maxRetryInterval     time.Duration
 	maxRetryTime         time.Duration
 	strictGenesisParsing bool
 
+	// Subscribe to the new blocks instead of polling the latest block height
+	webSocketSubscription bool
+
 	accountAddressPrefix string
 	stakingDenom         string
 
-	windowSyncStrategy *syncstrategy.Window
+	syncStrategyName string
+	syncStrategy     syncstrategy.Strategy
 
 	eventHandler eventhandler_interface.Handler
 
+	// Chain reorganization detection
+	blockHashStore     *rdbblockhashstore.RDbBlockHashStore
+	maxReorgDepth      int64
+	fetchedBlockHashes sync.Map
+
 	// SyncManager state
 	latestBlockHeight *int64
 	shouldSyncCh      chan bool
@@ -64,44 +90,92 @@ type SyncManagerParams struct {
 }
 
 type SyncManagerConfig struct {
+	SyncStrategy             string
 	WindowSize               int
+	Pipeline                 syncstrategy.PipelineConfig
 	TendermintRPCUrl         string
 	CosmosAppHTTPRPCURL      string
 	InsecureTendermintClient bool
 	InsecureCosmosAppClient  bool
 	StrictGenesisParsing     bool
+	WebSocketSubscription    bool
+	BlockArchiveDir          string
 	AccountAddressPrefix     string
 	StakingDenom             string
 	StartingBlockHeight      int64
 	Concurrency              int
 }
 
+// Hashes of a fetched block used to verify its continuity with the previous indexed block
+type fetchedBlockHashes struct {
+	hash          string
+	lastBlockHash string
+}
+
 type TxResult struct {
 	index int
 	tx    model.Tx
 	err   error
 }
 
-// NewSyncManager creates a new feed with polling for latest block starts at a specific height
+// NewSyncManager creates a new feed with polling for latest block starts at a specific height. Blocks
+// are read from the block archive instead of the nodes when the archive directory is configured.
 func NewSyncManager(
 	params SyncManagerParams,
 	pm *utils.CosmosParserManager,
 	eventHandler eventhandler_interface.Handler,
-) *SyncManager {
-	tendermintClient := tendermint.NewHTTPClient(
-		params.Config.TendermintRPCUrl,
-		params.Config.StrictGenesisParsing,
-	)
-
+) (*SyncManager, error) {
+	// Account queries of the parser still go to the Cosmos app in archive mode, they are best-effort
+	// lookups of the signer public keys
 	cosmosClient := cosmosapp_infrastructure.NewHTTPClient(
 		params.Config.CosmosAppHTTPRPCURL,
 		params.Config.StakingDenom,
 	)
 
+	var tendermintClient tendermint_interface.Client
+	var txClient cosmosapp_interface.TxClient
+	if params.Config.BlockArchiveDir != "" {
+		archiveClient, err := blockarchive.NewClient(
+			params.Config.BlockArchiveDir,
+			params.Config.StrictGenesisParsing,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error opening block archive: %v", err)
+		}
+		tendermintClient = archiveClient
+		txClient = archiveClient
+	} else {
+		tendermintClient = tendermint.NewHTTPClient(
+			params.Config.TendermintRPCUrl,
+			params.Config.StrictGenesisParsing,
+		)
+		txClient = cosmosClient
+	}
+
+	syncStrategyName, err := config.ParseSyncStrategy(params.Config.SyncStrategy)
+	if err != nil {
+		return nil, err
+	}
+	var syncStrategy syncstrategy.Strategy
+	switch syncStrategyName {
+	case config.SYNC_STRATEGY_WINDOW:
+		syncStrategy = syncstrategy.NewWindow(params.Logger, params.Config.WindowSize)
+	case config.SYNC_STRATEGY_PIPELINE:
+		pipelineConfig := params.Config.Pipeline
+		if pipelineConfig.MaxConcurrency <= 0 {
+			pipelineConfig.MaxConcurrency = params.Config.WindowSize
+		}
+		syncStrategy = syncstrategy.NewPipeline(params.Logger, pipelineConfig)
+	default:
+		return nil, fmt.Errorf("unsupported sync strategy: %s", syncStrategyName)
+	}
+
 	return &SyncManager{
 		rdbConn:          params.RDbConn,
 		tendermintClient: tendermintClient,
+		tendermintRPCUrl: params.Config.TendermintRPCUrl,
 		cosmosClient:     cosmosClient,
+		txClient:         txClient,
 		logger: params.Logger.WithFields(applogger.LogFields{
 			"module": "SyncManager",
 		}),
@@ -110,24 +184,32 @@ func NewSyncManager(
 		maxRetryTime:         DEFAULT_MAX_RETRY_TIME,
 		strictGenesisParsing: params.Config.StrictGenesisParsing,
 
+		// The block archive never has new blocks pushed
+		webSocketSubscription: params.Config.WebSocketSubscription && params.Config.BlockArchiveDir == "",
+
 		accountAddressPrefix: params.Config.AccountAddressPrefix,
 		stakingDenom:         params.Config.StakingDenom,
 
 		shouldSyncCh: make(chan bool, 1),
 
-		windowSyncStrategy: syncstrategy.NewWindow(params.Logger, params.Config.WindowSize),
+		syncStrategyName: syncStrategyName,
+		syncStrategy:     syncStrategy,
 
 		eventHandler: eventHandler,
 
+		blockHashStore: rdbblockhashstore.NewRDbBlockHashStore(params.RDbConn.ToHandle()),
+		maxReorgDepth:  DEFAULT_MAX_REORG_DEPTH,
+
 		parserManager: pm,
 
 		startingBlockHeight: params.Config.StartingBlockHeight,
 		concurrency:         params.Config.Concurrency,
-	}
+	}, nil
 }
 
-// SyncBlocks makes request to tendermint, create and dispatch notifications
-func (manager *SyncManager) SyncBlocks(latestHeight int64, isRetry bool) error {
+// SyncBlocks makes request to tendermint, create and dispatch notifications. When the context is
+// done, it stops after handling the events of the current height.
+func (manager *SyncManager) SyncBlocks(ctx context.Context, latestHeight int64, isRetry bool) error {
 	maybeLastIndexedHeight, err := manager.eventHandler.GetLastHandledEventHeight()
 	if err != nil {
 		return fmt.Errorf("error running GetLastIndexedBlockHeight %v", err)
@@ -139,6 +221,8 @@ func (manager *SyncManager) SyncBlocks(latestHeight int64, isRetry bool) error {
 		currentIndexingHeight = *maybeLastIndexedHeight + 1
 	}
 
+	manager.pruneFetchedBlockHashes(currentIndexingHeight)
+
 	targetHeight := latestHeight
 	if isRetry {
 		// Reduce the block size to be synced when retrying to avoid spamming and wasting resource
@@ -155,8 +239,7 @@ func (manager *SyncManager) SyncBlocks(latestHeight int64, isRetry bool) error {
 		return nil
 	}
 	manager.logger.Infof("going to synchronized blocks from %d to %d", currentIndexingHeight, targetHeight)
-	for currentIndexingHeight <= targetHeight {
-		startTime := time.Now()
+	for currentIndexingHeight <= targetHeight && ctx.Err() == nil {
 		endHeight := targetHeight
 		if currentIndexingHeight == 0 {
 			// Genesis Block as an individual window, size = 1
@@ -165,37 +248,20 @@ func (manager *SyncManager) SyncBlocks(latestHeight int64, isRetry bool) error {
 			currentIndexingHeight = manager.startingBlockHeight
 		}
 
-		blocksCommands, syncedHeight, err := manager.windowSyncStrategy.Sync(
-			currentIndexingHeight, endHeight, manager.syncBlockWorker,
+		syncedHeight, err := manager.syncStrategy.Sync(
+			ctx,
+			currentIndexingHeight,
+			endHeight,
+			manager.syncBlockWorker,
+			func(blockHeight int64, commands []command_entity.Command) error {
+				return manager.handleBlock(ctx, blockHeight, commands)
+			},
 		)
-		if err != nil {
-			return fmt.Errorf("error when synchronizing block with window strategy: %v", err)
+		if errors.Is(err, errStopSync) {
+			return nil
 		}
-
 		if err != nil {
-			return fmt.Errorf("error beginning transaction: %v", err)
-		}
-		for i, commands := range blocksCommands {
-			blockHeight := currentIndexingHeight + int64(i)
-
-			events := make([]event.Event, 0, len(commands))
-			for _, command := range commands {
-				event, err := command.Exec()
-				if err != nil {
-
-					return fmt.Errorf(
-						"error executing command %sV%d to produce events: %v",
-						command.Name(), command.Version(), err,
-					)
-				}
-				events = append(events, event)
-			}
-
-			err := manager.eventHandler.HandleEvents(blockHeight, events)
-			if err != nil {
-				return fmt.Errorf("error handling events: %v", err)
-			}
-			prometheus.RecordProjectionExecTime(manager.eventHandler.Id(), time.Since(startTime).Milliseconds())
+			return fmt.Errorf("error when synchronizing block with %s strategy: %v", manager.syncStrategyName, err)
 		}
 
 		// If there is any error before, short-circuit return in the error handling
@@ -208,6 +274,66 @@ func (manager *SyncManager) SyncBlocks(latestHeight int64, isRetry bool) error {
 	return nil
 }
 
+// handleBlock executes the commands of a synchronized block and hands the produced events to the
+// event handler. It returns errStopSync when the context is done or the indexed blocks are rolled
+// back, the synchronization then restarts from the last handled height on next round.
+func (manager *SyncManager) handleBlock(
+	ctx context.Context,
+	blockHeight int64,
+	commands []command_entity.Command,
+) error {
+	if ctx.Err() != nil {
+		manager.logger.Infof("stop synchronizing blocks before height %d", blockHeight)
+		return errStopSync
+	}
+	startTime := time.Now()
+
+	blockHashes, isForked, err := manager.verifyBlockContinuity(blockHeight)
+	if err != nil {
+		return fmt.Errorf("error verifying block continuity at height %d: %v", blockHeight, err)
+	}
+	if isForked {
+		if err := manager.rollbackFork(blockHeight - 1); err != nil {
+			return fmt.Errorf("error rolling back forked blocks: %v", err)
+		}
+		return errStopSync
+	}
+
+	events := make([]event.Event, 0, len(commands))
+	for _, command := range commands {
+		event, err := command.Exec()
+		if err != nil {
+			return fmt.Errorf(
+				"error executing command %sV%d to produce events: %v",
+				command.Name(), command.Version(), err,
+			)
+		}
+		events = append(events, event)
+	}
+
+	if blockHashes == nil {
+		err = manager.eventHandler.HandleEvents(blockHeight, events)
+	} else {
+		// The block hash is recorded together with the events, such that the continuity of the next
+		// block is always verified
+		err = eventhandler_interface.HandleEventsWithin(
+			manager.eventHandler,
+			manager.rdbConn.ToHandle(),
+			blockHeight,
+			events,
+			func(rdbHandle *rdb.Handle) error {
+				return manager.recordBlockHash(rdbHandle, blockHeight, blockHashes.hash)
+			},
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("error handling events: %v", err)
+	}
+	prometheus.RecordProjectionExecTime(manager.eventHandler.Id(), time.Since(startTime).Milliseconds())
+
+	return nil
+}
+
 func (manager *SyncManager) syncBlockWorker(blockHeight int64) ([]command_entity.Command, error) {
 	logger := manager.logger.WithFields(applogger.LogFields{
 		"submodule":   "SyncBlockWorker",
@@ -232,6 +358,11 @@ func (manager *SyncManager) syncBlockWorker(blockHeight int64) ([]command_entity
 		return nil, fmt.Errorf("error requesting chain block at height %d: %v", blockHeight, err)
 	}
 
+	manager.fetchedBlockHashes.Store(blockHeight, &fetchedBlockHashes{
+		hash:          block.Hash,
+		lastBlockHash: rawBlock.Block.Header.LastBlockID.Hash,
+	})
+
 	blockResults, err := manager.tendermintClient.BlockResults(blockHeight)
 	if err != nil {
 		return nil, fmt.Errorf("error requesting chain block_results at height %d: %v", blockHeight, err)
@@ -253,7 +384,7 @@ func (manager *SyncManager) syncBlockWorker(blockHeight int64) ([]command_entity
 			semaphoreChan <- struct{}{}
 
 			var tx *model.Tx
-			tx, err = manager.cosmosClient.Tx(parser.TxHash(txHex))
+			tx, err = manager.txClient.Tx(parser.TxHash(txHex))
 			txResult := &TxResult{
 				index,
 				model.Tx{
@@ -321,31 +452,152 @@ func (manager *SyncManager) syncBlockWorker(blockHeight int64) ([]command_entity
 	return commands, nil
 }
 
-// Run starts the polling service for blocks
-func (manager *SyncManager) Run() error {
-	tracker := chainfeed.NewBlockHeightTracker(manager.logger, manager.tendermintClient)
-	manager.latestBlockHeight = tracker.GetLatestBlockHeight()
+// verifyBlockContinuity checks the fetched block at the height is built on top of the indexed
+// block at the previous height. Returns the fetched block hashes, nil for the genesis, and whether
+// the indexed previous block is forked away.
+func (manager *SyncManager) verifyBlockContinuity(blockHeight int64) (*fetchedBlockHashes, bool, error) {
+	value, ok := manager.fetchedBlockHashes.LoadAndDelete(blockHeight)
+	if !ok {
+		return nil, false, nil
+	}
+	blockHashes := value.(*fetchedBlockHashes)
+
+	indexedLastBlockHash, err := manager.blockHashStore.FindBy(manager.eventHandler.Id(), blockHeight-1)
+	if err != nil {
+		return nil, false, fmt.Errorf("error getting indexed block hash at height %d: %v", blockHeight-1, err)
+	}
+	if indexedLastBlockHash == nil || *indexedLastBlockHash == blockHashes.lastBlockHash {
+		return blockHashes, false, nil
+	}
+
+	manager.logger.Errorf(
+		"detected chain reorganization: block %d is built on top of %s but the indexed block %d is %s",
+		blockHeight, blockHashes.lastBlockHash, blockHeight-1, *indexedLastBlockHash,
+	)
+	return blockHashes, true, nil
+}
+
+// pruneFetchedBlockHashes removes the hashes of the blocks fetched below the height, e.g. by a failed
+// synchronization round which has fetched blocks ahead of the last handled one
+func (manager *SyncManager) pruneFetchedBlockHashes(height int64) {
+	manager.fetchedBlockHashes.Range(func(key, _ interface{}) bool {
+		if key.(int64) < height {
+			manager.fetchedBlockHashes.Delete(key)
+		}
+		return true
+	})
+}
+
+func (manager *SyncManager) recordBlockHash(rdbHandle *rdb.Handle, blockHeight int64, hash string) error {
+	handlerId := manager.eventHandler.Id()
+	if err := manager.blockHashStore.SaveWithRDbHandle(rdbHandle, handlerId, blockHeight, hash); err != nil {
+		return err
+	}
+	return manager.blockHashStore.DeleteBelowWithRDbHandle(rdbHandle, handlerId, blockHeight-manager.maxReorgDepth)
+}
+
+// rollbackFork finds the common ancestor of the indexed blocks and the chain starting from the
+// forked height, then rolls back every indexed block above the common ancestor
+func (manager *SyncManager) rollbackFork(forkedHeight int64) error {
+	rollbackableHandler, ok := manager.eventHandler.(eventhandler_interface.RollbackableHandler)
+	if !ok {
+		return fmt.Errorf("event handler `%s` does not support rollback", manager.eventHandler.Id())
+	}
+
+	handlerId := manager.eventHandler.Id()
+	commonAncestorHeight := int64(-1)
+	for height := forkedHeight; height > forkedHeight-manager.maxReorgDepth && height > 0; height -= 1 {
+		indexedHash, err := manager.blockHashStore.FindBy(handlerId, height)
+		if err != nil {
+			return fmt.Errorf("error getting indexed block hash at height %d: %v", height, err)
+		}
+		if indexedHash == nil {
+			break
+		}
+
+		block, _, err := manager.tendermintClient.Block(height)
+		if err != nil {
+			return fmt.Errorf("error requesting chain block at height %d: %v", height, err)
+		}
+		if block.Hash == *indexedHash {
+			commonAncestorHeight = height
+			break
+		}
+	}
+	if commonAncestorHeight < 0 {
+		return fmt.Errorf(
+			"unable to find common ancestor within %d blocks below height %d, a full re-index is required",
+			manager.maxReorgDepth, forkedHeight,
+		)
+	}
+
+	lastHandledHeight, err := manager.eventHandler.GetLastHandledEventHeight()
+	if err != nil {
+		return fmt.Errorf("error getting last handled event height: %v", err)
+	}
+	if lastHandledHeight == nil || *lastHandledHeight <= commonAncestorHeight {
+		return nil
+	}
+
+	manager.logger.Infof(
+		"rolling back blocks from height %d to %d on top of common ancestor %d",
+		commonAncestorHeight+1, *lastHandledHeight, commonAncestorHeight,
+	)
+	if err := rollbackableHandler.Rollback(commonAncestorHeight+1, *lastHandledHeight); err != nil {
+		return fmt.Errorf("error rolling back event handler: %v", err)
+	}
+	if err := manager.blockHashStore.DeleteAbove(handlerId, commonAncestorHeight); err != nil {
+		return fmt.Errorf("error deleting forked block hashes: %v", err)
+	}
+	prometheus.RecordProjectionLatestHeight(handlerId, commonAncestorHeight)
+
+	return nil
+}
+
+// newBlockSource creates the source of the chain latest blocks running until the context is done
+func (manager *SyncManager) newBlockSource(ctx context.Context) chainfeed.BlockSource {
+	if manager.webSocketSubscription {
+		return chainfeed.NewWebSocketBlockSource(
+			ctx,
+			manager.logger,
+			manager.tendermintClient,
+			tendermint.NewWebSocketClient(manager.tendermintRPCUrl),
+		)
+	}
+
+	return chainfeed.NewBlockHeightTracker(ctx, manager.logger, manager.tendermintClient)
+}
+
+// Run starts the synchronization service for blocks until the context is done
+func (manager *SyncManager) Run(ctx context.Context) error {
+	blockSource := manager.newBlockSource(ctx)
+	manager.latestBlockHeight = blockSource.GetLatestBlockHeight()
 	blockHeightCh := make(chan int64, 1)
 	go func() {
 		for {
-			latestBlockHeight := <-blockHeightCh
-			manager.latestBlockHeight = &latestBlockHeight
-			manager.drainShouldSyncCh()
-			manager.shouldSyncCh <- true
+			select {
+			case <-ctx.Done():
+				return
+			case latestBlockHeight := <-blockHeightCh:
+				manager.latestBlockHeight = &latestBlockHeight
+				prometheus.RecordChainLatestHeight(latestBlockHeight)
+				manager.drainShouldSyncCh()
+				manager.shouldSyncCh <- true
+			}
 		}
 	}()
-	tracker.Subscribe(blockHeightCh)
+	blockSource.Subscribe(blockHeightCh)
 
 	parser.InitParsers(manager.parserManager)
 	parser.RegisterBreakingVersionParsers(manager.parserManager)
 
-	for {
+	for ctx.Err() == nil {
 		isRetry := false
 		operation := func() error {
 			if manager.latestBlockHeight == nil {
 				manager.logger.Info("the chain has no block yet")
 			} else {
-				if syncErr := manager.SyncBlocks(*manager.latestBlockHeight, isRetry); syncErr != nil {
+				if syncErr := manager.SyncBlocks(ctx, *manager.latestBlockHeight, isRetry); syncErr != nil {
 					return fmt.Errorf(
 						"error synchronizing blocks to latest height %d: %v", *manager.latestBlockHeight, syncErr,
 					)
@@ -353,6 +605,7 @@ func (manager *SyncManager) Run() error {
 			}
 
 			select {
+			case <-ctx.Done():
 			case <-manager.shouldSyncCh:
 			case <-time.After(manager.pollingInterval):
 			}
@@ -370,12 +623,15 @@ func (manager *SyncManager) Run() error {
 		neverStopExponentialBackoff.MaxInterval = manager.maxRetryInterval
 		if err := backoff.RetryNotify(
 			operation,
-			backoff.NewExponentialBackOff(),
+			backoff.WithContext(neverStopExponentialBackoff, ctx),
 			notifyFn,
-		); err != nil {
-			manager.logger.Errorf("stopping retry after too many errors: %s: %v", err)
+		); err != nil && ctx.Err() == nil {
+			manager.logger.Errorf("stopping retry after too many errors: %v", err)
 		}
 	}
+
+	manager.logger.Info("sync manager stopped")
+	return nil
 }
 
 func (manager *SyncManager) drainShouldSyncCh() {
@@ -37,6 +37,8 @@ type CLIConfig struct {
 
 	CorsAllowedOrigins string
 
+	RedisURL string
+
 	IndexService *bool
 
 	StartingBlockHeight *int64
@@ -109,6 +111,9 @@ func OverrideByCLIConfig(config *config.Config, cliConfig *CLIConfig) {
 	if cliConfig.CorsAllowedOrigins != "" {
 		config.HTTPService.CorsAllowedOrigins = []string{cliConfig.CorsAllowedOrigins}
 	}
+	if cliConfig.RedisURL != "" {
+		config.Cache.RedisURL = cliConfig.RedisURL
+	}
 	if cliConfig.IndexService != nil {
 		config.IndexService.Enable = *cliConfig.IndexService
 	}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/AstraProtocol/astra-indexing/bootstrap"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/blockarchive"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/cosmosapp"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/tendermint"
+)
+
+// dumpBlocksCommand writes the blocks of the configured live node into a block archive, which is read
+// by the index service when `index_service.block_archive_dir` is configured
+func dumpBlocksCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "dump-blocks",
+		Usage: "Dump blocks, block results and transactions of a live node into a block archive",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "output",
+				Usage:    "Block archive `DIRECTORY` to write to",
+				Required: true,
+			},
+			&cli.Int64Flag{
+				Name:  "from",
+				Usage: "First height to dump, genesis is included when it is 0 or 1",
+				Value: 0,
+			},
+			&cli.Int64Flag{
+				Name:  "to",
+				Usage: "Last height to dump, defaults to the chain latest height",
+			},
+			&cli.Int64Flag{
+				Name:  "chunkSize",
+				Usage: "Number of heights per archive file",
+				Value: blockarchive.DEFAULT_CHUNK_SIZE,
+			},
+			&cli.IntFlag{
+				Name:  "windowSize",
+				Usage: "Number of heights fetched in parallel",
+				Value: 10,
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			config, _, err := loadConfig(ctx)
+			if err != nil {
+				return err
+			}
+			logger := newLogger(config)
+
+			if err = bootstrap.SetupCache(logger, config); err != nil {
+				return err
+			}
+
+			tendermintClient := tendermint.NewHTTPClient(
+				config.TendermintApp.HTTPRPCUrl,
+				config.TendermintApp.StrictGenesisParsing,
+			)
+			cosmosClient := cosmosapp.NewHTTPClient(
+				config.CosmosApp.HTTPRPCUrl,
+				config.Blockchain.BondingDenom,
+			)
+
+			fromHeight := ctx.Int64("from")
+			toHeight := ctx.Int64("to")
+			if !ctx.IsSet("to") {
+				if toHeight, err = tendermintClient.LatestBlockHeight(); err != nil {
+					return err
+				}
+			}
+			if fromHeight > toHeight {
+				return errors.New("from height is greater than to height")
+			}
+
+			writer, err := blockarchive.NewWriter(ctx.String("output"), ctx.Int64("chunkSize"))
+			if err != nil {
+				return err
+			}
+			dumper := blockarchive.NewDumper(logger, tendermintClient, cosmosClient, writer, ctx.Int("windowSize"))
+
+			// Stop at the end of the current window on interrupt or termination signals, the archive
+			// then covers the heights dumped so far
+			signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			dumpErr := dumper.Dump(signalCtx, fromHeight, toHeight)
+			if closeErr := writer.Close(); closeErr != nil && dumpErr == nil {
+				return closeErr
+			}
+			return dumpErr
+		},
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"strings"
 
 	evmUtil "github.com/AstraProtocol/astra-indexing/internal/evm"
@@ -20,32 +21,102 @@ import (
 	"github.com/AstraProtocol/astra-indexing/projection/account_transaction"
 	"github.com/AstraProtocol/astra-indexing/projection/block"
 	"github.com/AstraProtocol/astra-indexing/projection/chainstats"
+	"github.com/AstraProtocol/astra-indexing/projection/delegation"
 	"github.com/AstraProtocol/astra-indexing/projection/ibc_channel"
 	"github.com/AstraProtocol/astra-indexing/projection/ibc_channel_message"
 	"github.com/AstraProtocol/astra-indexing/projection/proposal"
+	"github.com/AstraProtocol/astra-indexing/projection/search"
 	"github.com/AstraProtocol/astra-indexing/projection/transaction"
 	"github.com/AstraProtocol/astra-indexing/projection/validator"
+	"github.com/AstraProtocol/astra-indexing/projection/validator_uptime"
 	"github.com/AstraProtocol/astra-indexing/projection/validatorstats"
 )
 
+// initProjections creates the enabled projections and initializes them in the order of their
+// dependencies. It fails on unknown projections and on dependencies which are not enabled or form a
+// cycle. A projection failing to initialize is skipped together with its dependents.
 func initProjections(
 	logger applogger.Logger,
 	rdbConn rdb.Conn,
 	config *configuration.Config,
 	customConfig *CustomConfig,
 	evmUtil evmUtil.EvmUtils,
-) []projection_entity.Projection {
+) ([]projection_entity.Projection, error) {
 	if !config.IndexService.Enable {
-		return []projection_entity.Projection{}
+		return []projection_entity.Projection{}, nil
 	}
 
+	enabledProjections := make([]projection_entity.Projection, 0, len(config.IndexService.Projection.Enables))
+	initParams := newInitProjectionParams(logger, rdbConn, config, customConfig)
+
+	for _, projectionName := range config.IndexService.Projection.Enables {
+		projection := InitProjection(
+			projectionName, initParams,
+			evmUtil,
+		)
+		if projection == nil {
+			return nil, fmt.Errorf("unknown projection %s in enabled projections", projectionName)
+		}
+		// The sync manager rolls back the projections on every chain reorganization, a projection
+		// unable to undo its writes would stall the sync on the same fork
+		if !projection_entity.CanRollbackOrReset(projection) {
+			return nil, fmt.Errorf("projection %s in enabled projections is unable to roll back on a chain reorganization", projectionName)
+		}
+		enabledProjections = append(enabledProjections, projection)
+	}
+	sortedProjections, err := projection_entity.SortByDependencies(enabledProjections)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving dependencies of enabled projections: %w", err)
+	}
+
+	projections := make([]projection_entity.Projection, 0, len(sortedProjections))
+	failedProjections := make(map[string]bool)
+	for _, projection := range sortedProjections {
+		if failedDependency := findFailedDependency(projection, failedProjections); failedDependency != "" {
+			logger.Errorf(
+				"skipping projection %s as projection %s it depends on has failed to initialize",
+				projection.Id(), failedDependency,
+			)
+			failedProjections[projection.Id()] = true
+			continue
+		}
+		if onInitErr := projection.OnInit(); onInitErr != nil {
+			logger.Errorf(
+				"error initializing projection %s, system will attempt to initialize the projection again on next restart: %v",
+				projection.Id(), onInitErr,
+			)
+			failedProjections[projection.Id()] = true
+			continue
+		}
+		projections = append(projections, projection)
+	}
+
+	logger.Infof("Enabled the follow projection: [%s]", strings.Join(config.IndexService.Projection.Enables, ", "))
+
+	return projections, nil
+}
+
+func findFailedDependency(projection projection_entity.Projection, failedProjections map[string]bool) string {
+	for _, dependencyId := range projection_entity.GetDependencies(projection) {
+		if failedProjections[dependencyId] {
+			return dependencyId
+		}
+	}
+	return ""
+}
+
+func newInitProjectionParams(
+	logger applogger.Logger,
+	rdbConn rdb.Conn,
+	config *configuration.Config,
+	customConfig *CustomConfig,
+) InitProjectionParams {
 	cosmosAppClient := cosmosapp_infrastructure.NewHTTPClient(
 		config.CosmosApp.HTTPRPCUrl,
 		config.Blockchain.BondingDenom,
 	)
 
-	projections := make([]projection_entity.Projection, 0, len(config.IndexService.Projection.Enables))
-	initParams := InitProjectionParams{
+	return InitProjectionParams{
 		Logger:  logger,
 		RdbConn: rdbConn,
 
@@ -61,29 +132,6 @@ func initProjections(
 
 		ServerMigrationRepoRef: customConfig.ServerGithubAPI.MigrationRepoRef,
 	}
-
-	for _, projectionName := range config.IndexService.Projection.Enables {
-		projection := InitProjection(
-			projectionName, initParams,
-			evmUtil,
-		)
-		if projection == nil {
-			continue
-		} else {
-			if onInitErr := projection.OnInit(); onInitErr != nil {
-				logger.Errorf(
-					"error initializing projection %s, system will attempt to initialize the projection again on next restart: %v",
-					projection.Id(), onInitErr,
-				)
-				continue
-			}
-		}
-		projections = append(projections, projection)
-	}
-
-	logger.Infof("Enabled the follow projection: [%s]", strings.Join(config.IndexService.Projection.Enables, ", "))
-
-	return projections
 }
 
 func InitProjection(name string, params InitProjectionParams, util evmUtil.EvmUtils) projection_entity.Projection {
@@ -224,6 +272,21 @@ func InitProjection(name string, params InitProjectionParams, util evmUtil.EvmUt
 			return ibc_channel_message.NewIBCChannelMessage(params.Logger, params.RdbConn, nil)
 		}
 		return ibc_channel_message.NewIBCChannelMessage(params.Logger, params.RdbConn, migrationHelper)
+	case "Search":
+		if params.GithubAPIToken == "" {
+			return search.NewSearch(params.Logger, params.RdbConn, params.ConsNodeAddressPrefix, nil)
+		}
+		return search.NewSearch(params.Logger, params.RdbConn, params.ConsNodeAddressPrefix, migrationHelper)
+	case "ValidatorUptime":
+		if params.GithubAPIToken == "" {
+			return validator_uptime.NewValidatorUptime(params.Logger, params.RdbConn, params.ConsNodeAddressPrefix, nil)
+		}
+		return validator_uptime.NewValidatorUptime(params.Logger, params.RdbConn, params.ConsNodeAddressPrefix, migrationHelper)
+	case "Delegation":
+		if params.GithubAPIToken == "" {
+			return delegation.NewDelegation(params.Logger, params.RdbConn, params.ConsNodeAddressPrefix, nil)
+		}
+		return delegation.NewDelegation(params.Logger, params.RdbConn, params.ConsNodeAddressPrefix, migrationHelper)
 	}
 
 	return nil
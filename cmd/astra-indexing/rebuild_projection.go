@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/urfave/cli/v2"
+
+	event_interface "github.com/AstraProtocol/astra-indexing/appinterface/event"
+	"github.com/AstraProtocol/astra-indexing/bootstrap"
+	configuration "github.com/AstraProtocol/astra-indexing/bootstrap/config"
+	"github.com/AstraProtocol/astra-indexing/entity/event"
+	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
+	"github.com/AstraProtocol/astra-indexing/external/primptr"
+	"github.com/AstraProtocol/astra-indexing/internal/evm"
+	event_usecase "github.com/AstraProtocol/astra-indexing/usecase/event"
+)
+
+// rebuildProjectionCommand resets a single projection and replays the event store until it catches
+// up with the latest event height. The projection must not be running in the index service at the
+// same time, use the admin API `api/v1/admin/projections/{id}/rebuild` to rebuild a running one.
+func rebuildProjectionCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "rebuild-projection",
+		Usage: "Truncate the views of a projection and replay it from the event store",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "projection",
+				Usage:    "`ID` of the projection to rebuild, e.g. AccountTransaction",
+				Required: true,
+			},
+			&cli.Int64Flag{
+				Name:  "fromHeight",
+				Usage: "Height to replay the events from, keeping the records below it. Requires rollback support unless 0",
+				Value: 0,
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			config, customConfig, err := loadConfig(ctx)
+			if err != nil {
+				return err
+			}
+			logger := newLogger(config)
+
+			if err = bootstrap.SetupCache(logger, config); err != nil {
+				return err
+			}
+
+			if config.IndexService.Mode != configuration.SYSTEM_MODE_EVENT_STORE {
+				return fmt.Errorf(
+					"projections are only replayed from the event store in %s mode",
+					configuration.SYSTEM_MODE_EVENT_STORE,
+				)
+			}
+
+			rdbConn, err := bootstrap.SetupRDbConn(config, logger)
+			if err != nil {
+				return fmt.Errorf("error setting up RDb connection: %v", err)
+			}
+			evmUtil, err := evm.NewEvmUtils()
+			if err != nil {
+				return err
+			}
+
+			projectionId := ctx.String("projection")
+			initParams := newInitProjectionParams(logger, rdbConn, config, customConfig)
+			projection := InitProjection(projectionId, initParams, evmUtil)
+			if projection == nil {
+				return fmt.Errorf("unknown projection: %s", projectionId)
+			}
+			if err = projection.OnInit(); err != nil {
+				return fmt.Errorf("error initializing projection %s: %v", projectionId, err)
+			}
+			// The projections it depends on are not replayed, they only bound the replayed heights
+			dependencies := make([]projection_entity.Projection, 0)
+			for _, dependencyId := range projection_entity.GetDependencies(projection) {
+				dependency := InitProjection(dependencyId, initParams, evmUtil)
+				if dependency == nil {
+					return fmt.Errorf("unknown projection %s depended on by %s", dependencyId, projectionId)
+				}
+				dependencies = append(dependencies, dependency)
+			}
+
+			eventRegistry := event.NewRegistry()
+			event_usecase.RegisterEvents(eventRegistry)
+			eventStore := event_interface.NewRDbStore(rdbConn.ToHandle(), eventRegistry)
+
+			projectionManager := projection_entity.NewStoreBasedManagerWithOptions(
+				logger, eventStore, projection_entity.StoreBasedManagerOptions{
+					MaybeMaxBatchSize: primptr.Int64(config.IndexService.Projection.BatchSize),
+				},
+			)
+			if err = projectionManager.RegisterProjection(projection); err != nil {
+				return err
+			}
+			for _, dependency := range dependencies {
+				if err = projectionManager.RegisterDependency(dependency); err != nil {
+					return err
+				}
+			}
+			if err = projectionManager.Rebuild(projectionId, ctx.Int64("fromHeight")); err != nil {
+				return err
+			}
+
+			// Stop after the current height on interrupt or termination signals, the index service
+			// resumes the replay from there
+			signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			runCtx, cancelRun := context.WithCancel(signalCtx)
+			if err = projectionManager.RunInBackground(runCtx); err != nil {
+				cancelRun()
+				return err
+			}
+			waitErr := projectionManager.WaitUntilCaughtUp(signalCtx, projectionId)
+			cancelRun()
+			projectionManager.Wait()
+
+			if errors.Is(waitErr, context.Canceled) {
+				logger.Infof("stopped replaying projection %s before catching up", projectionId)
+				return nil
+			}
+			if waitErr != nil {
+				return waitErr
+			}
+			logger.Infof("successfully rebuilt projection %s", projectionId)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/AstraProtocol/astra-indexing/bootstrap"
+	worker_consumer "github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer/worker"
+	"github.com/AstraProtocol/astra-indexing/internal/evm"
+)
+
+// replayDeadLettersCommand hands the messages moved to the dead letter topic of a consumed topic
+// back to its handler, once the cause of their failure is fixed. It stops once the dead letter topic
+// is drained, or on the first message still failing, which is then kept in the dead letter topic.
+func replayDeadLettersCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "replay-dead-letters",
+		Usage: "Handle the messages of the dead letter topic of a Kafka topic again",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "topic",
+				Usage:    "Consumed `TOPIC` to replay the dead letters of, e.g. evm-txs",
+				Required: true,
+			},
+			&cli.DurationFlag{
+				Name:  "idleTimeout",
+				Usage: "Stop once no dead letter has arrived for this duration",
+				Value: 10 * time.Second,
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			config, _, err := loadConfig(ctx)
+			if err != nil {
+				return err
+			}
+			logger := newLogger(config)
+
+			if err = bootstrap.SetupCache(logger, config); err != nil {
+				return err
+			}
+
+			topic := ctx.String("topic")
+			topicHandler := worker_consumer.TopicHandlers.Get(topic)
+			if topicHandler == nil {
+				return fmt.Errorf("unknown topic %s, available topics: %v", topic, worker_consumer.TopicHandlers.Topics())
+			}
+
+			rdbConn, err := bootstrap.SetupRDbConn(config, logger)
+			if err != nil {
+				return fmt.Errorf("error setting up RDb connection: %v", err)
+			}
+			evmUtil, err := evm.NewEvmUtils()
+			if err != nil {
+				return err
+			}
+
+			// Stop after the current message on interrupt or termination signals
+			signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			replayed, err := topicHandler.ReplayDeadLetters(
+				signalCtx,
+				worker_consumer.TopicHandlerParams{
+					RdbConn:   rdbConn,
+					RdbHandle: rdbConn.ToHandle(),
+					Config:    config,
+					Logger:    logger,
+					EvmUtil:   evmUtil,
+
+					JsonrpcClient: worker_consumer.NewJsonrpcClient(logger, config),
+				},
+				ctx.Duration("idleTimeout"),
+			)
+			logger.Infof("replayed %d dead letters of topic %s", replayed, topic)
+			return err
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/openapi"
+)
+
+// Params read by httpapi.ParsePagination
+func paginationParams(params ...openapi.Param) []openapi.Param {
+	return append([]openapi.Param{
+		openapi.QueryParam("pagination").Enum(pagination.PAGINATION_OFFSET),
+		openapi.QueryParam("page").Integer().Min(1),
+		openapi.QueryParam("limit").Integer().Describe("Number of records of the page, 20 by default"),
+	}, params...)
+}
+
+// Params read by httpapi.ParsePaginationWithCursor
+func cursorPaginationParams(params ...openapi.Param) []openapi.Param {
+	return append([]openapi.Param{
+		openapi.QueryParam("pagination").Enum(pagination.PAGINATION_OFFSET, pagination.PAGINATION_CURSOR),
+		openapi.QueryParam("cursor").Describe("Opaque cursor of the pagination, from the `next` or `prev` of a page"),
+		openapi.QueryParam("page").Integer().Min(1).Describe("Page of the offset pagination"),
+		openapi.QueryParam("limit").Integer().Describe("Number of records of the page, 20 by default"),
+	}, params...)
+}
+
+// Params of the lists proxied from Blockscout
+func blockscoutPaginationParams(params ...openapi.Param) []openapi.Param {
+	return append([]openapi.Param{
+		openapi.QueryParam("blockscout").Enum("true").Required(),
+		openapi.QueryParam("page").Integer().Min(1),
+		openapi.QueryParam("offset").Integer().Min(1).Describe("Number of records of the page"),
+	}, params...)
+}
+
+// Params of the lists proxied from Blockscout continuing after a record
+func blockscoutKeysetParams(params ...openapi.Param) []openapi.Param {
+	return blockscoutPaginationParams(append([]openapi.Param{
+		openapi.QueryParam("block_number").Integer().Min(0),
+		openapi.QueryParam("index").Integer().Min(0),
+	}, params...)...)
+}
+
+// Params of the stats histories
+var historyParams = []openapi.Param{
+	openapi.QueryParam("year").Integer(),
+	openapi.QueryParam("daily").Boolean(),
+}
+
+// orderParam accepts every field ascending, either bare or with `.asc`, and descending with `.desc`,
+// such that all the values the handlers have ever parsed are still accepted
+func orderParam(fields ...string) openapi.Param {
+	values := make([]string, 0, len(fields)*3)
+	for _, field := range fields {
+		values = append(values, field, field+".asc", field+".desc")
+	}
+	return openapi.QueryParam("order").Enum(values...)
+}
@@ -0,0 +1,122 @@
+package routes
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/valyala/fasthttp"
+
+	rdb_test "github.com/AstraProtocol/astra-indexing/appinterface/rdb/test"
+	"github.com/AstraProtocol/astra-indexing/bootstrap/config"
+	logger_test "github.com/AstraProtocol/astra-indexing/external/logger/test"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/openapi"
+	"github.com/AstraProtocol/astra-indexing/internal/evm"
+)
+
+var _ = Describe("Order params", func() {
+	var registry *RouteRegistry
+
+	BeforeEach(func() {
+		registry = InitRouteRegistry(
+			logger_test.NewFakeLogger(), rdb_test.NewFakeRDbConn(), &config.Config{}, evm.EvmUtils{}, nil,
+		).(*RouteRegistry)
+	})
+
+	validate := func(path string, pathParams map[string]string, order string) int {
+		for _, route := range registry.routes {
+			if route.path != path {
+				continue
+			}
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.SetRequestURI(fmt.Sprintf("/%s?order=%s", path, order))
+			for name, value := range pathParams {
+				ctx.SetUserValue(name, value)
+			}
+			openapi.Validate(route.spec, func(ctx *fasthttp.RequestCtx) {
+				ctx.SetStatusCode(fasthttp.StatusOK)
+			})(ctx)
+			return ctx.Response.StatusCode()
+		}
+		Fail(fmt.Sprintf("route %s not found", path))
+		return 0
+	}
+
+	type orderEntry struct {
+		description string
+		path        string
+		pathParams  map[string]string
+		orders      []string
+	}
+	entry := func(description string, path string, pathParams map[string]string, orders ...string) orderEntry {
+		return orderEntry{description, path, pathParams, orders}
+	}
+	// Values the handlers of the routes have parsed, the bare and `.asc` fields were the ascending
+	// order of the handlers ignoring the other values
+	for _, e := range []orderEntry{
+		entry("blocks", "api/v1/blocks", nil, "height", "height.asc", "height.desc"),
+		entry(
+			"transactions of a block", "api/v1/blocks/{height}/transactions", map[string]string{"height": "10"},
+			"height", "height.asc", "height.desc",
+		),
+		entry("accounts", "api/v1/accounts", nil, "address", "address.asc", "address.desc"),
+		entry(
+			"transactions of an account", "api/v1/accounts/{account}/transactions", map[string]string{"account": "astra1"},
+			"height", "height.asc", "height.desc",
+		),
+		entry("transactions", "api/v1/transactions", nil, "height", "height.asc", "height.desc"),
+		entry("proposals", "api/v1/proposals", nil, "id", "id.asc", "id.desc"),
+		entry(
+			"votes of a proposal", "api/v1/proposals/{id}/votes", map[string]string{"id": "1"},
+			"voteAt", "voteAt.asc", "voteAt.desc",
+		),
+		entry(
+			"depositors of a proposal", "api/v1/proposals/{id}/depositors", map[string]string{"id": "1"},
+			"depositAt", "depositAt.asc", "depositAt.desc",
+		),
+		entry(
+			"validators", "api/v1/validators", nil,
+			"power", "power.desc", "commission", "commission.desc",
+		),
+		entry(
+			"active validators", "api/v1/validators/active", nil,
+			"power", "power.desc", "commission", "commission.desc",
+		),
+		entry(
+			"activities of a validator", "api/v1/validators/{address}/activities", map[string]string{"address": "astravaloper1"},
+			"height", "height.desc",
+		),
+		entry(
+			"blocks missed by a validator", "api/v1/validators/{address}/missed-blocks", map[string]string{"address": "astravaloper1"},
+			"height", "height.desc",
+		),
+		entry(
+			"slashing events of a validator", "api/v1/validators/{address}/slashing-events", map[string]string{"address": "astravaloper1"},
+			"height", "height.desc",
+		),
+		entry(
+			"delegation history", "api/v1/accounts/{account}/delegation-history", map[string]string{"account": "astra1"},
+			"height", "height.desc",
+		),
+		entry(
+			"IBC channels", "api/v1/ibc/channels", nil,
+			"createdAtBlockTime", "createdAtBlockTime.asc", "createdAtBlockTime.desc",
+			"lastActivityBlockTime", "lastActivityBlockTime.asc", "lastActivityBlockTime.desc",
+		),
+		entry(
+			"messages of an IBC channel", "api/v1/ibc/channels/{channelId}/messages", map[string]string{"channelId": "channel-0"},
+			"blockTime", "blockTime.asc", "blockTime.desc",
+		),
+	} {
+		e := e
+		It(fmt.Sprintf("should accept the order values parsed by the handler of %s", e.description), func() {
+			for _, order := range e.orders {
+				Expect(validate(e.path, e.pathParams, order)).To(Equal(fasthttp.StatusOK), order)
+			}
+		})
+	}
+
+	It("should reject the order of an unknown field", func() {
+		Expect(validate("api/v1/blocks", nil, "hash")).To(Equal(fasthttp.StatusBadRequest))
+	})
+})
@@ -4,17 +4,26 @@ import (
 	"fmt"
 
 	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/apiauth"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/openapi"
 	"github.com/valyala/fasthttp"
 )
 
 type RouteRegistry struct {
 	routes []Route
+	// Routes requiring a scope are only served when the clients are identified by their API key
+	requireScopes bool
 }
 
 type Route struct {
 	Method  string
 	path    string
 	handler fasthttp.RequestHandler
+	// Params and responses of the route, documented in the OpenAPI document and validated before
+	// the handler
+	spec openapi.Spec
+	// Scope of the API key required by the route, empty for any client
+	scope string
 }
 
 func (registry *RouteRegistry) Register(server *httpapi.Server, routePrefix string) {
@@ -22,9 +31,37 @@ func (registry *RouteRegistry) Register(server *httpapi.Server, routePrefix stri
 		routePrefix = ""
 	}
 
-	for _, route := range registry.routes {
+	document := openapi.NewDocument(OPENAPI_TITLE, OPENAPI_VERSION)
+	for _, route := range registry.servedRoutes() {
+		document.AddOperation(route.Method, fmt.Sprintf("%s/%s", routePrefix, route.path), route.handler, route.spec)
+
+		route.handler = openapi.Validate(route.spec, route.handler)
+		if route.scope != "" {
+			route.handler = apiauth.RequireScope(route.scope, route.handler)
+		}
 		registerRoute(server, routePrefix, route)
 	}
+
+	registerRoute(server, routePrefix, Route{
+		Method:  GET,
+		path:    "api/openapi.json",
+		handler: openapi.Handler(document),
+	})
+}
+
+// servedRoutes returns the routes to register. The routes requiring a scope are left out when the
+// clients are not identified by their API key, so that they are not open to any client.
+func (registry *RouteRegistry) servedRoutes() []Route {
+	if registry.requireScopes {
+		return registry.routes
+	}
+	routes := make([]Route, 0, len(registry.routes))
+	for _, route := range registry.routes {
+		if route.scope == "" {
+			routes = append(routes, route)
+		}
+	}
+	return routes
 }
 
 func registerRoute(server *httpapi.Server, routePrefix string, route Route) {
@@ -40,3 +77,8 @@ const (
 	GET  = "GET"
 	POST = "POST"
 )
+
+const (
+	OPENAPI_TITLE   = "Astra Indexing API"
+	OPENAPI_VERSION = "v1"
+)
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rdb_test "github.com/AstraProtocol/astra-indexing/appinterface/rdb/test"
+	"github.com/AstraProtocol/astra-indexing/bootstrap/config"
+	logger_test "github.com/AstraProtocol/astra-indexing/external/logger/test"
+	"github.com/AstraProtocol/astra-indexing/internal/evm"
+)
+
+var _ = Describe("RouteRegistry", func() {
+	servedPaths := func(apiAuthEnabled bool) []string {
+		registryConfig := &config.Config{}
+		registryConfig.HTTPService.APIAuth.Enable = apiAuthEnabled
+		registry := InitRouteRegistry(
+			logger_test.NewFakeLogger(), rdb_test.NewFakeRDbConn(), registryConfig, evm.EvmUtils{}, nil,
+		).(*RouteRegistry)
+
+		paths := make([]string, 0)
+		for _, route := range registry.servedRoutes() {
+			paths = append(paths, route.path)
+		}
+		return paths
+	}
+
+	It("should not serve the admin routes when API auth is disabled", func() {
+		paths := servedPaths(false)
+
+		Expect(paths).NotTo(ContainElement("api/v1/report-dashboard/update"))
+		Expect(paths).To(ContainElement("api/v1/report-dashboard"))
+	})
+
+	It("should serve the admin routes when API auth is enabled", func() {
+		Expect(servedPaths(true)).To(ContainElement("api/v1/report-dashboard/update"))
+	})
+})
@@ -1,16 +1,34 @@
 package routes
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
 	"github.com/AstraProtocol/astra-indexing/bootstrap"
 	"github.com/AstraProtocol/astra-indexing/bootstrap/config"
+	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
 	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
 	blockscout_infrastructure "github.com/AstraProtocol/astra-indexing/infrastructure/blockscout"
 	cosmosapp_infrastructure "github.com/AstraProtocol/astra-indexing/infrastructure/cosmosapp"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/apiauth"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/graphql"
 	httpapi_handlers "github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/handlers"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/openapi"
 	jsonrpc_infrastructure "github.com/AstraProtocol/astra-indexing/infrastructure/jsonrpc"
 	tendermint_infrastructure "github.com/AstraProtocol/astra-indexing/infrastructure/tendermint"
 	evmUtil "github.com/AstraProtocol/astra-indexing/internal/evm"
+	account_view "github.com/AstraProtocol/astra-indexing/projection/account/view"
+	account_transaction_view "github.com/AstraProtocol/astra-indexing/projection/account_transaction/view"
+	block_view "github.com/AstraProtocol/astra-indexing/projection/block/view"
+	delegation_view "github.com/AstraProtocol/astra-indexing/projection/delegation/view"
+	ibc_channel_types "github.com/AstraProtocol/astra-indexing/projection/ibc_channel/types"
+	ibc_channel_view "github.com/AstraProtocol/astra-indexing/projection/ibc_channel/view"
+	ibc_channel_message_view "github.com/AstraProtocol/astra-indexing/projection/ibc_channel_message/view"
+	proposal_view "github.com/AstraProtocol/astra-indexing/projection/proposal/view"
+	transaction_view "github.com/AstraProtocol/astra-indexing/projection/transaction/view"
+	validator_view "github.com/AstraProtocol/astra-indexing/projection/validator/view"
+	validator_uptime_view "github.com/AstraProtocol/astra-indexing/projection/validator_uptime/view"
 )
 
 func InitRouteRegistry(
@@ -18,6 +36,7 @@ func InitRouteRegistry(
 	rdbConn rdb.Conn,
 	config *config.Config,
 	evmUtil evmUtil.EvmUtils,
+	projectionRebuilder projection_entity.Rebuilder,
 ) bootstrap.RouteRegistry {
 	cosmosAppClient := cosmosapp_infrastructure.NewHTTPClient(
 		config.CosmosApp.HTTPRPCUrl,
@@ -53,15 +72,28 @@ func InitRouteRegistry(
 			Method:  GET,
 			path:    "api/v1/token-price/{contractaddress}",
 			handler: jsonrpcHandler.GetTokenPrice,
+			spec:    openapi.Spec{Summary: "Price of a token from its pair reserves", Errors: []int{http.StatusNotFound}},
 		},
 	)
 
-	searchHandler := httpapi_handlers.NewSearch(logger, *blockscoutClient, cosmosAppClient, rdbConn.ToHandle())
+	var searchBlockscoutTimeout time.Duration
+	if config.HTTPService.Search.BlockscoutTimeout != "" {
+		var err error
+		searchBlockscoutTimeout, err = time.ParseDuration(config.HTTPService.Search.BlockscoutTimeout)
+		if err != nil {
+			logger.Panicf("error parsing search BlockscoutTimeout string to duration %v", err)
+		}
+	}
+	searchHandler := httpapi_handlers.NewSearch(logger, *blockscoutClient, cosmosAppClient, rdbConn.ToHandle(), httpapi_handlers.SearchConfig{
+		BlockscoutEnrichment: config.HTTPService.Search.BlockscoutEnrichment,
+		BlockscoutTimeout:    searchBlockscoutTimeout,
+	})
 	routes = append(routes,
 		Route{
 			Method:  GET,
 			path:    "api/v1/search",
 			handler: searchHandler.Search,
+			spec:    openapi.Spec{Summary: "Search blocks, transactions, validators, tokens and accounts by prefix or fuzzy match", Params: []openapi.Param{openapi.QueryParam("keyword")}, Result: httpapi_handlers.SearchResults{}},
 		},
 	)
 
@@ -71,26 +103,31 @@ func InitRouteRegistry(
 			Method:  GET,
 			path:    "api/v1/blocks",
 			handler: blocksHandler.List,
+			spec:    openapi.Spec{Summary: "List blocks", Params: cursorPaginationParams(orderParam("height")), Result: []block_view.Block{}, Paginated: true, CursorPaginated: true},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/eth-block-number",
 			handler: blocksHandler.EthBlockNumber,
+			spec:    openapi.Spec{Summary: "Latest EVM block number"},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/blocks/{height-or-hash}",
 			handler: blocksHandler.FindBy,
+			spec:    openapi.Spec{Summary: "Find a block by height or hash", Result: block_view.Block{}, Errors: []int{http.StatusNotFound}},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/blocks/{height}/transactions",
 			handler: blocksHandler.ListTransactionsByHeight,
+			spec:    openapi.Spec{Summary: "List transactions of a block", Params: cursorPaginationParams(openapi.PathParam("height").Integer().Min(0), orderParam("height")), Result: []transaction_view.TransactionRow{}, Paginated: true, CursorPaginated: true},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/blocks/check-blocks/{height}",
 			handler: blocksHandler.CheckBlocks,
+			spec:    openapi.Spec{Summary: "Check the blocks indexed up to a height", Params: []openapi.Param{openapi.PathParam("height").Integer().Min(0)}},
 		},
 	)
 
@@ -101,14 +138,17 @@ func InitRouteRegistry(
 	)
 	routes = append(routes,
 		Route{
-			Method:  GET,
+			Method:  POST,
 			path:    "api/v1/report-dashboard/update",
 			handler: reportDashboardHandlers.UpdateReportDashboardByDate,
+			spec:    openapi.Spec{Summary: "Update the report dashboard of a day", Params: []openapi.Param{openapi.QueryParam("date").Describe("Day formatted as 2006-01-02")}, Errors: []int{http.StatusUnauthorized, http.StatusForbidden}},
+			scope:   apiauth.SCOPE_ADMIN,
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/report-dashboard",
 			handler: reportDashboardHandlers.GetReportDashboardByTimeRange,
+			spec:    openapi.Spec{Summary: "Report dashboard of a time range", Params: []openapi.Param{openapi.QueryParam("fromDate").Required().Describe("Day formatted as 2006-01-02"), openapi.QueryParam("toDate").Describe("Day formatted as 2006-01-02, today by default"), openapi.QueryParam("keyword")}, Errors: []int{http.StatusNotFound}},
 		},
 	)
 
@@ -124,36 +164,43 @@ func InitRouteRegistry(
 			Method:  GET,
 			path:    "api/v1/accounts",
 			handler: accountsHandlers.List,
+			spec:    openapi.Spec{Summary: "List accounts", Params: paginationParams(orderParam("address")), Result: []account_view.AccountRow{}, Paginated: true},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/accounts/{account}",
 			handler: accountsHandlers.FindBy,
+			spec:    openapi.Spec{Summary: "Find an account", Result: httpapi_handlers.AccountInfo{}, Errors: []int{http.StatusNotFound}},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/accounts/detail/{account}",
 			handler: accountsHandlers.GetDetailAddress,
+			spec:    openapi.Spec{Summary: "Detail of an address", Errors: []int{http.StatusNotFound}},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/accounts/getabi/{account}",
 			handler: accountsHandlers.GetAbiByAddressHash,
+			spec:    openapi.Spec{Summary: "ABI of a contract address"},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/accounts/tokenlist/{account}",
 			handler: accountsHandlers.GetTokensOfAnAddress,
+			spec:    openapi.Spec{Summary: "List tokens of an address", Params: []openapi.Param{openapi.QueryParam("blockscout").Enum("true").Required(), openapi.QueryParam("page").Integer().Min(1), openapi.QueryParam("limit").Integer().Min(1), openapi.QueryParam("type"), openapi.QueryParam("token_name"), openapi.QueryParam("token_type"), openapi.QueryParam("value")}},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/accounts/get-coin-balances-history/{account}",
 			handler: accountsHandlers.GetCoinBalancesHistory,
+			spec:    openapi.Spec{Summary: "List coin balance changes of an address", Params: blockscoutKeysetParams(openapi.QueryParam("token_name"), openapi.QueryParam("token_type"), openapi.QueryParam("value"))},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/accounts/{account}/coin-balances/by-day",
 			handler: accountsHandlers.AddressCoinBalancesByDate,
+			spec:    openapi.Spec{Summary: "Coin balances of an address by day"},
 		},
 	)
 
@@ -166,51 +213,61 @@ func InitRouteRegistry(
 			Method:  GET,
 			path:    "api/v1/contract/get-list-tokens",
 			handler: contractsHandler.GetListTokens,
+			spec:    openapi.Spec{Summary: "List tokens", Params: blockscoutPaginationParams()},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/contract/token-transfers/{contractaddress}",
 			handler: contractsHandler.GetListTokenTransfersByContractAddressHash,
+			spec:    openapi.Spec{Summary: "List token transfers of a contract", Params: blockscoutKeysetParams()},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/contract/txs/{contractaddress}",
 			handler: contractsHandler.GetListTxsByContractAddressHash,
+			spec:    openapi.Spec{Summary: "List transactions of a contract", Params: blockscoutKeysetParams(openapi.QueryParam("filter"))},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/contract/deposit-txs/{contractaddress}",
 			handler: contractsHandler.GetListDepositTxsByContractAddressHash,
+			spec:    openapi.Spec{Summary: "List deposit transactions of a contract", Params: blockscoutKeysetParams()},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/contract/token-holders/{contractaddress}",
 			handler: contractsHandler.GetListTokenHoldersOfAContractAddressHash,
+			spec:    openapi.Spec{Summary: "List token holders of a contract", Params: blockscoutPaginationParams()},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/contract/token-inventory/{contractaddress}",
 			handler: contractsHandler.GetTokenInventoryOfAContractAddressHash,
+			spec:    openapi.Spec{Summary: "List token inventory of a contract", Params: blockscoutPaginationParams(openapi.QueryParam("token_id"))},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/contract/token-transfers-by-tokenid/contractaddress={contractaddress}/tokenid={tokenid}",
 			handler: contractsHandler.GetTokenTransfersByTokenId,
+			spec:    openapi.Spec{Summary: "List transfers of a token", Params: blockscoutKeysetParams()},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/contract/source-code/{contractaddress}",
 			handler: contractsHandler.GetSourceCodeOfAContractAddressHash,
+			spec:    openapi.Spec{Summary: "Source code of a contract"},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/contract/token-detail/{contractaddress}",
 			handler: contractsHandler.GetTokenDetail,
+			spec:    openapi.Spec{Summary: "Detail of a token"},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/contract/token-metadata/contractaddress={contractaddress}/tokenid={tokenid}",
 			handler: contractsHandler.GetTokenMetadata,
+			spec:    openapi.Spec{Summary: "Metadata of a token"},
 		},
 	)
 
@@ -223,16 +280,19 @@ func InitRouteRegistry(
 			Method:  GET,
 			path:    "api",
 			handler: contractVerifiersHandler.ContractActions,
+			spec:    openapi.Spec{Summary: "Etherscan compatible contract actions", Params: []openapi.Param{openapi.QueryParam("module"), openapi.QueryParam("action"), openapi.QueryParam("address"), openapi.QueryParam("guid")}, Unwrapped: true},
 		},
 		Route{
 			Method:  POST,
 			path:    "verify_smart_contract/contract_verifications",
 			handler: contractVerifiersHandler.VerifyFlattened,
+			spec:    openapi.Spec{Summary: "Verify a flattened contract", Unwrapped: true},
 		},
 		Route{
 			Method:  POST,
 			path:    "api",
 			handler: contractVerifiersHandler.Verify,
+			spec:    openapi.Spec{Summary: "Etherscan compatible contract verification", Unwrapped: true},
 		},
 	)
 
@@ -248,31 +308,37 @@ func InitRouteRegistry(
 			Method:  GET,
 			path:    "api/v1/accounts/{account}/transactions",
 			handler: accountTransactionsHandler.ListByAccount,
+			spec:    openapi.Spec{Summary: "List transactions of an account", Params: cursorPaginationParams(orderParam("height"), openapi.QueryParam("memo"), openapi.QueryParam("includingInternalTx").Enum("true", "false"), openapi.QueryParam("txType"), openapi.QueryParam("fromAddress"), openapi.QueryParam("fromDate").Describe("Day formatted as 2006-01-02, 100 days ago by default"), openapi.QueryParam("toDate").Describe("Day formatted as 2006-01-02, today by default"), openapi.QueryParam("status").Enum("success", "failed", "all")), Result: []account_transaction_view.AccountTransactionReadRow{}, Paginated: true, CursorPaginated: true},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/accounts/{account}/counters",
 			handler: accountTransactionsHandler.GetCounters,
+			spec:    openapi.Spec{Summary: "Counters of an account"},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/accounts/get-top-addresses-balance",
 			handler: accountTransactionsHandler.GetTopAddressesBalance,
+			spec:    openapi.Spec{Summary: "List addresses with the top balances", Params: blockscoutPaginationParams(openapi.QueryParam("fetched_coin_balance"), openapi.QueryParam("hash")), Errors: []int{http.StatusNotFound}},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/accounts/internal-transactions/{account}",
 			handler: accountTransactionsHandler.GetInternalTxsByAddressHash,
+			spec:    openapi.Spec{Summary: "List internal transactions of an address", Params: blockscoutKeysetParams(openapi.QueryParam("transaction_index").Integer().Min(0))},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/accounts/internal-transactions/sync/{txhash}",
 			handler: accountTransactionsHandler.SyncAccountInternalTxsByTxHash,
+			spec:    openapi.Spec{Summary: "Index the internal transactions of a transaction", Unwrapped: true},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/accounts/token-transfers/{account}",
 			handler: accountTransactionsHandler.GetListTokenTransfersByAddressHash,
+			spec:    openapi.Spec{Summary: "List token transfers of an address", Params: blockscoutKeysetParams()},
 		},
 	)
 
@@ -287,66 +353,79 @@ func InitRouteRegistry(
 			Method:  GET,
 			path:    "api/v1/estimate-counted-info",
 			handler: statsHandlers.EstimateCounted,
+			spec:    openapi.Spec{Summary: "Estimated counts of the chain", Result: httpapi_handlers.EstimateCountedInfo{}},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/common-stats",
 			handler: statsHandlers.GetCommonStats,
+			spec:    openapi.Spec{Summary: "Common stats of the chain"},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/transactions-history-chart",
 			handler: statsHandlers.GetTransactionsHistoryChart,
+			spec:    openapi.Spec{Summary: "Transactions history chart", Errors: []int{http.StatusNotFound}},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/transactions-history",
 			handler: statsHandlers.GetTransactionsHistory,
+			spec:    openapi.Spec{Summary: "Transactions history by month, or by day", Params: historyParams},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/active-addresses-history",
 			handler: statsHandlers.GetActiveAddressesHistory,
+			spec:    openapi.Spec{Summary: "Active addresses history by month, or by day", Params: historyParams},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/total-addresses-growth",
 			handler: statsHandlers.GetTotalAddressesGrowth,
+			spec:    openapi.Spec{Summary: "Total addresses growth by month, or by day", Params: historyParams},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/gas-used-history",
 			handler: statsHandlers.GetGasUsedHistory,
+			spec:    openapi.Spec{Summary: "Gas used history by month, or by day", Params: historyParams},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/total-fee-history",
 			handler: statsHandlers.GetTotalFeeHistory,
+			spec:    openapi.Spec{Summary: "Total fees history by month, or by day", Params: historyParams},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/market-history-chart",
 			handler: statsHandlers.MarketHistoryChart,
+			spec:    openapi.Spec{Summary: "Market history chart"},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/gas-price-oracle",
 			handler: statsHandlers.GasPriceOracle,
+			spec:    openapi.Spec{Summary: "Gas price oracle"},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/evm-versions",
 			handler: statsHandlers.EvmVersions,
+			spec:    openapi.Spec{Summary: "List EVM versions of the contract compilers"},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/compiler-versions/{compiler}",
 			handler: statsHandlers.CompilerVersions,
+			spec:    openapi.Spec{Summary: "List versions of a contract compiler"},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/configs",
 			handler: statsHandlers.ChainConfigs,
+			spec:    openapi.Spec{Summary: "Configs of the chain", Unwrapped: true},
 		},
 	)
 
@@ -361,6 +440,7 @@ func InitRouteRegistry(
 			Method:  GET,
 			path:    "api/v1/status",
 			handler: statusHandlers.GetStatus,
+			spec:    openapi.Spec{Summary: "Status of the indexing", Result: httpapi_handlers.Status{}},
 		},
 	)
 
@@ -370,36 +450,43 @@ func InitRouteRegistry(
 			Method:  GET,
 			path:    "api/v1/transactions",
 			handler: transactionHandler.List,
+			spec:    openapi.Spec{Summary: "List transactions", Params: cursorPaginationParams(orderParam("height")), Result: []transaction_view.TransactionRow{}, Paginated: true, CursorPaginated: true},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/transactions/{hash}",
 			handler: transactionHandler.FindByHash,
+			spec:    openapi.Spec{Summary: "Find a transaction by Cosmos or EVM hash", Params: []openapi.Param{openapi.QueryParam("type").Enum("evm")}, Errors: []int{http.StatusNotFound}},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/transactions/internal-transactions/{hash}",
 			handler: transactionHandler.ListInternalTransactionsByHash,
+			spec:    openapi.Spec{Summary: "List internal transactions of a transaction"},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v2/transactions/internal-transactions/{hash}",
 			handler: transactionHandler.ListInternalTransactionsByHashv2,
+			spec:    openapi.Spec{Summary: "List internal transactions of a transaction", Params: blockscoutKeysetParams(openapi.QueryParam("transaction_index").Integer().Min(0)), Unwrapped: true},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/transactions/getabi/{hash}",
 			handler: transactionHandler.GetAbiByTransactionHash,
+			spec:    openapi.Spec{Summary: "ABI of the contract called by a transaction", Errors: []int{http.StatusNotFound}},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/transactions/getrawtrace/{hash}",
 			handler: transactionHandler.GetRawTraceByTransactionHash,
+			spec:    openapi.Spec{Summary: "Raw trace of a transaction"},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/transactions/txswithtokentransfers/{hashes}",
 			handler: transactionHandler.GetTxsWithTokenTransfersByTxHashes,
+			spec:    openapi.Spec{Summary: "List transactions with their token transfers", Params: []openapi.Param{openapi.PathParam("hashes").Describe("Comma separated hashes")}, Unwrapped: true},
 		},
 	)
 
@@ -413,21 +500,25 @@ func InitRouteRegistry(
 			Method:  GET,
 			path:    "api/v1/proposals",
 			handler: proposalsHandler.List,
+			spec:    openapi.Spec{Summary: "List proposals", Params: paginationParams(orderParam("id"), openapi.QueryParam("status"), openapi.QueryParam("proposerAddress")), Result: []proposal_view.ProposalWithMonikerRow{}, Paginated: true},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/proposals/{id}",
 			handler: proposalsHandler.FindById,
+			spec:    openapi.Spec{Summary: "Find a proposal", Result: httpapi_handlers.ProposalDetails{}, Errors: []int{http.StatusNotFound}},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/proposals/{id}/votes",
 			handler: proposalsHandler.ListVotesById,
+			spec:    openapi.Spec{Summary: "List votes of a proposal", Params: paginationParams(orderParam("voteAt"), openapi.QueryParam("answer"), openapi.QueryParam("voterAddress")), Result: []proposal_view.VoteWithMonikerRow{}, Paginated: true},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/proposals/{id}/depositors",
 			handler: proposalsHandler.ListDepositorsById,
+			spec:    openapi.Spec{Summary: "List depositors of a proposal", Params: paginationParams(orderParam("depositAt"), openapi.QueryParam("depositorAddress")), Result: []proposal_view.DepositorWithMonikerRow{}, Paginated: true},
 		},
 	)
 
@@ -444,21 +535,78 @@ func InitRouteRegistry(
 			Method:  GET,
 			path:    "api/v1/validators",
 			handler: validatorsHandler.List,
+			spec:    openapi.Spec{Summary: "List validators", Params: paginationParams(orderParam("power", "commission").Multiple()), Result: []validator_view.ListValidatorRow{}, Paginated: true},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/validators/active",
 			handler: validatorsHandler.ListActive,
+			spec:    openapi.Spec{Summary: "List active validators", Params: paginationParams(orderParam("power", "commission").Multiple()), Result: []validator_view.ListValidatorRow{}, Paginated: true},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/validators/{address}",
 			handler: validatorsHandler.FindBy,
+			spec:    openapi.Spec{Summary: "Find a validator by operator or consensus node address", Result: httpapi_handlers.ValidatorDetails{}, Errors: []int{http.StatusNotFound}},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/validators/{address}/activities",
 			handler: validatorsHandler.ListActivities,
+			spec:    openapi.Spec{Summary: "List activities of a validator", Params: paginationParams(orderParam("height")), Result: []validator_view.ValidatorActivityRow{}, Paginated: true},
+		},
+	)
+
+	validatorUptimeHandler := httpapi_handlers.NewValidatorUptime(
+		logger,
+		validatorAddressPrefix,
+		conNodeAddressPrefix,
+		rdbConn.ToHandle(),
+	)
+	routes = append(routes,
+		Route{
+			Method:  GET,
+			path:    "api/v1/validators/{address}/uptime",
+			handler: validatorUptimeHandler.FindBy,
+			spec:    openapi.Spec{Summary: "Find the signing window uptime of a validator", Result: httpapi_handlers.ValidatorUptimeDetails{}, Errors: []int{http.StatusNotFound}},
+		},
+		Route{
+			Method:  GET,
+			path:    "api/v1/validators/{address}/missed-blocks",
+			handler: validatorUptimeHandler.ListMissedBlocks,
+			spec:    openapi.Spec{Summary: "List blocks missed by a validator", Params: paginationParams(orderParam("height")), Result: []validator_uptime_view.MissedBlockRow{}, Paginated: true, Errors: []int{http.StatusNotFound}},
+		},
+		Route{
+			Method:  GET,
+			path:    "api/v1/validators/{address}/slashing-events",
+			handler: validatorUptimeHandler.ListSlashingEvents,
+			spec:    openapi.Spec{Summary: "List jail, slash and unjail events of a validator", Params: paginationParams(orderParam("height")), Result: []validator_uptime_view.SlashingEventRow{}, Paginated: true, Errors: []int{http.StatusNotFound}},
+		},
+	)
+
+	delegationHandler := httpapi_handlers.NewDelegation(
+		logger,
+		validatorAddressPrefix,
+		rdbConn.ToHandle(),
+	)
+	routes = append(routes,
+		Route{
+			Method:  GET,
+			path:    "api/v1/accounts/{account}/delegations",
+			handler: delegationHandler.FindPortfolioByDelegator,
+			spec:    openapi.Spec{Summary: "Find the delegations, unbonding delegations and redelegations of a delegator", Result: httpapi_handlers.DelegatorPortfolio{}},
+		},
+		Route{
+			Method:  GET,
+			path:    "api/v1/accounts/{account}/delegation-history",
+			handler: delegationHandler.ListEventsByDelegator,
+			spec:    openapi.Spec{Summary: "List delegation history of a delegator", Params: paginationParams(orderParam("height")), Result: []delegation_view.DelegationEventRow{}, Paginated: true},
+		},
+		Route{
+			Method:  GET,
+			path:    "api/v1/validators/{address}/delegators",
+			handler: delegationHandler.ListDelegationsByValidator,
+			spec:    openapi.Spec{Summary: "List delegators of a validator by shares", Params: paginationParams(), Result: []delegation_view.DelegationRow{}, Paginated: true, Errors: []int{http.StatusNotFound}},
 		},
 	)
 
@@ -471,16 +619,19 @@ func InitRouteRegistry(
 			Method:  GET,
 			path:    "api/v1/ibc/channels",
 			handler: ibcChannelHandler.ListChannels,
+			spec:    openapi.Spec{Summary: "List IBC channels", Params: paginationParams(orderParam("createdAtBlockTime", "lastActivityBlockTime"), openapi.QueryParam("status").Enum(string(ibc_channel_types.STATUS_NOT_ESTABLISHED), string(ibc_channel_types.STATUS_OPENED), string(ibc_channel_types.STATUS_CLOSED)), openapi.QueryParam("groupBy").Enum("chainId")), Result: []ibc_channel_view.IBCChannelRow{}, Paginated: true},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/ibc/channels/{channelId}",
 			handler: ibcChannelHandler.FindChannelById,
+			spec:    openapi.Spec{Summary: "Find an IBC channel", Result: ibc_channel_view.IBCChannelRow{}, Errors: []int{http.StatusNotFound}},
 		},
 		Route{
 			Method:  GET,
 			path:    "api/v1/ibc/denom-hash-mappings",
 			handler: ibcChannelHandler.ListAllDenomHashMapping,
+			spec:    openapi.Spec{Summary: "List IBC denom hash mappings", Result: []ibc_channel_view.IBCDenomHashMappingRow{}},
 		},
 	)
 
@@ -493,8 +644,50 @@ func InitRouteRegistry(
 			Method:  GET,
 			path:    "api/v1/ibc/channels/{channelId}/messages",
 			handler: ibcChannelMessageHandler.ListByChannelID,
+			spec:    openapi.Spec{Summary: "List messages of an IBC channel", Params: paginationParams(orderParam("blockTime"), openapi.QueryParam("filter.msgType")), Result: []ibc_channel_message_view.IBCChannelMessageRow{}, Paginated: true},
 		},
 	)
 
-	return &RouteRegistry{routes: routes}
+	if config.HTTPService.GraphQL.Enable {
+		graphqlExecutor, err := graphql.NewExecutor(graphql.NewViews(rdbConn.ToHandle()), graphql.Limits{
+			MaxDepth:      config.HTTPService.GraphQL.MaxDepth,
+			MaxComplexity: config.HTTPService.GraphQL.MaxComplexity,
+		})
+		if err != nil {
+			logger.Panicf("error creating GraphQL schema: %v", err)
+		}
+		graphqlHandler := httpapi_handlers.NewGraphQL(logger, graphqlExecutor)
+		routes = append(routes,
+			Route{
+				Method:  POST,
+				path:    "graphql",
+				handler: graphqlHandler.Query,
+				spec:    openapi.Spec{Summary: "Query the projection views with GraphQL", Tags: []string{"graphql"}, Unwrapped: true},
+			},
+			Route{
+				Method:  GET,
+				path:    "graphql",
+				handler: graphqlHandler.Query,
+				spec:    openapi.Spec{Summary: "Query the projection views with GraphQL", Tags: []string{"graphql"}, Unwrapped: true},
+			},
+		)
+	}
+
+	if config.HTTPService.EnableAdminAPI && projectionRebuilder != nil {
+		projectionsHandler := httpapi_handlers.NewProjections(logger, projectionRebuilder)
+		routes = append(routes,
+			Route{
+				Method:  POST,
+				path:    "api/v1/admin/projections/{id}/rebuild",
+				handler: projectionsHandler.Rebuild,
+				spec:    openapi.Spec{Summary: "Rebuild a projection", Params: []openapi.Param{openapi.QueryParam("fromHeight").Integer().Min(0)}, Errors: []int{http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden}},
+				scope:   apiauth.SCOPE_ADMIN,
+			},
+		)
+	}
+
+	if !config.HTTPService.APIAuth.Enable {
+		logger.Infof("API auth is disabled, the routes requiring a scope, e.g. the admin API, are not served")
+	}
+	return &RouteRegistry{routes: routes, requireScopes: config.HTTPService.APIAuth.Enable}
 }
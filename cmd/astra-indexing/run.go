@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 
 	"github.com/AstraProtocol/astra-indexing/cmd/astra-indexing/routes"
 	"github.com/urfave/cli/v2"
@@ -61,10 +65,9 @@ func run(args []string) error {
 				EnvVars: []string{"DB_USERNAME"},
 			},
 			&cli.StringFlag{
-				Name:     "dbPassword",
-				Usage:    "Postgres password",
-				EnvVars:  []string{"DB_PASSWORD"},
-				Required: true,
+				Name:    "dbPassword",
+				Usage:   "Postgres password",
+				EnvVars: []string{"DB_PASSWORD"},
 			},
 			&cli.StringFlag{
 				Name:    "dbName",
@@ -107,6 +110,11 @@ func run(args []string) error {
 				Usage:   "Cors Allowed Origins",
 				EnvVars: []string{"CORS_ALLOWED_ORIGINS"},
 			},
+			&cli.StringFlag{
+				Name:    "redisURL",
+				Usage:   "Redis URL of the cache",
+				EnvVars: []string{"REDIS_URL"},
+			},
 			&cli.BoolFlag{
 				Name:    "indexService",
 				Usage:   "Enable Index Service",
@@ -178,124 +186,58 @@ func run(args []string) error {
 				EnvVars: []string{"KAFKA_TLS_KEY_PATH"},
 			},
 		},
+		Commands: []*cli.Command{
+			dumpBlocksCommand(),
+			rebuildProjectionCommand(),
+			replayDeadLettersCommand(),
+		},
 		Action: func(ctx *cli.Context) error {
 			if args := ctx.Args(); args.Len() > 0 {
 				return fmt.Errorf("unexpected arguments: %q", args.Get(0))
 			}
 
-			// Prepare FileConfig
-			configPath := ctx.String("config")
-			var config configuration.Config
-			err := yaml.FromYAMLFile(configPath, &config)
-			if err != nil {
-				return fmt.Errorf("error config from yaml: %v", err)
+			if !ctx.IsSet("dbPassword") {
+				return errors.New("Required flag \"dbPassword\" not set")
 			}
 
-			var customConfig CustomConfig
-			err = yaml.FromYAMLFile(configPath, &customConfig)
+			config, customConfig, err := loadConfig(ctx)
 			if err != nil {
-				return fmt.Errorf("error custom config from yaml: %v", err)
+				return err
 			}
 
-			cliConfig := CLIConfig{
-				LogLevel: ctx.String("logLevel"),
+			logger := newLogger(config)
 
-				DatabaseHost:     ctx.String("dbHost"),
-				DatabaseUsername: ctx.String("dbUsername"),
-				DatabasePassword: ctx.String("dbPassword"),
-				DatabaseName:     ctx.String("dbName"),
-				DatabaseSchema:   ctx.String("dbSchema"),
-
-				TendermintHTTPRPCUrl:       ctx.String("tendermintURL"),
-				CosmosHTTPRPCUrl:           ctx.String("cosmosAppURL"),
-				BlockscoutHTTPRPCUrl:       ctx.String("blockscoutURL"),
-				BlockscoutWorkerHTTPRPCUrl: ctx.String("blockscoutWorkerURL"),
-				JsonHTTPRPCUrl:             ctx.String("JsonRpcURL"),
-
-				GithubAPIUsername: ctx.String("githubAPIUsername"),
-				GithubAPIToken:    ctx.String("githubAPIToken"),
-
-				CorsAllowedOrigins: ctx.String("corsAllowedOrigins"),
-			}
-			if ctx.IsSet("color") {
-				cliConfig.LoggerColor = primptr.Bool(ctx.Bool("color"))
-			}
-			if ctx.IsSet("dbSSL") {
-				cliConfig.DatabaseSSL = primptr.Bool(ctx.Bool("dbSSL"))
-			}
-			if ctx.IsSet("dbPort") {
-				cliConfig.DatabasePort = primptr.Int32(int32(ctx.Int("dbPort")))
-			}
-			if ctx.IsSet("indexService") {
-				cliConfig.IndexService = primptr.Bool(ctx.Bool("indexService"))
-			}
-			if ctx.IsSet("cronjobStats") {
-				cliConfig.CronjobStats = primptr.Bool(ctx.Bool("cronjobStats"))
-			}
-			if ctx.IsSet("cronjobReportDashboard") {
-				cliConfig.CronjobReportDashboard = primptr.Bool(ctx.Bool("cronjobReportDashboard"))
-			}
-			if ctx.IsSet("tikiAddress") {
-				cliConfig.TikiAddress = ctx.String("tikiAddress")
-			}
-			if ctx.IsSet("startingBlockHeight") {
-				cliConfig.StartingBlockHeight = primptr.Int64(int64(ctx.Int("startingBlockHeight")))
-			}
-			if ctx.IsSet("enableConsumer") {
-				cliConfig.EnableConsumer = primptr.Bool(ctx.Bool("enableConsumer"))
-			}
-			if ctx.IsSet("consumerGroupId") {
-				cliConfig.ConsumerGroupId = ctx.String("consumerGroupId")
-			}
-			if ctx.IsSet("kafkaBrokers") {
-				cliConfig.KafkaBrokers = ctx.String("kafkaBrokers")
-			}
-			if ctx.IsSet("kafkaUser") {
-				cliConfig.KafkaUser = ctx.String("kafkaUser")
-			}
-			if ctx.IsSet("kafkaPassword") {
-				cliConfig.KafkaPassword = ctx.String("kafkaPassword")
-			}
-			if ctx.IsSet("kafkaAuthenticationType") {
-				cliConfig.KafkaAuthenticationType = ctx.String("kafkaAuthenticationType")
-			}
-			if ctx.IsSet("caCertPath") {
-				cliConfig.CaCertPath = ctx.String("caCertPath")
-			}
-			if ctx.IsSet("tlsCertPath") {
-				cliConfig.TlsCertPath = ctx.String("tlsCertPath")
-			}
-			if ctx.IsSet("tlsKeyPath") {
-				cliConfig.TlsKeyPath = ctx.String("tlsKeyPath")
+			if err = bootstrap.SetupCache(logger, config); err != nil {
+				return err
 			}
 
-			OverrideByCLIConfig(&config, &cliConfig)
-
-			// Create logger
-			logLevel := parseLogLevel(config.Logger.Level)
-			logger := infrastructure.NewZerologLogger(os.Stdout)
-			logger.SetLogLevel(logLevel)
-
 			evmUtil, err := evm.NewEvmUtils()
 			if err != nil {
 				return err
 			}
 
-			app := bootstrap.NewApp(logger, &config, evmUtil)
+			app := bootstrap.NewApp(logger, config, evmUtil)
 
-			app.InitIndexService(
-				initProjections(logger, app.GetRDbConn(), &config, &customConfig, evmUtil),
-				nil,
-			)
-			app.InitHTTPAPIServer(routes.InitRouteRegistry(logger, app.GetRDbConn(), &config, evmUtil))
+			projections, err := initProjections(logger, app.GetRDbConn(), config, customConfig, evmUtil)
+			if err != nil {
+				return err
+			}
+			if err = app.InitIndexService(projections, nil); err != nil {
+				return err
+			}
+			app.InitHTTPAPIServer(routes.InitRouteRegistry(
+				logger, app.GetRDbConn(), config, evmUtil, app.GetProjectionRebuilder(),
+			))
 
 			app.RunCronJobsStats(app.GetRDbConn().ToHandle())
 
 			app.RunCronJobsReportDashboard(app.GetRDbConn().ToHandle())
 
-			app.Run()
+			// Stop the services gracefully on interrupt or termination signals
+			signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
 
-			return nil
+			return app.Run(signalCtx)
 		},
 	}
 
@@ -307,6 +249,109 @@ func run(args []string) error {
 	return nil
 }
 
+// loadConfig loads the configuration file overridden by the CLI flags and environment variables
+func loadConfig(ctx *cli.Context) (*configuration.Config, *CustomConfig, error) {
+	// Prepare FileConfig
+	configPath := ctx.String("config")
+	var config configuration.Config
+	err := yaml.FromYAMLFile(configPath, &config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error config from yaml: %v", err)
+	}
+
+	var customConfig CustomConfig
+	err = yaml.FromYAMLFile(configPath, &customConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error custom config from yaml: %v", err)
+	}
+
+	cliConfig := CLIConfig{
+		LogLevel: ctx.String("logLevel"),
+
+		DatabaseHost:     ctx.String("dbHost"),
+		DatabaseUsername: ctx.String("dbUsername"),
+		DatabasePassword: ctx.String("dbPassword"),
+		DatabaseName:     ctx.String("dbName"),
+		DatabaseSchema:   ctx.String("dbSchema"),
+
+		TendermintHTTPRPCUrl:       ctx.String("tendermintURL"),
+		CosmosHTTPRPCUrl:           ctx.String("cosmosAppURL"),
+		BlockscoutHTTPRPCUrl:       ctx.String("blockscoutURL"),
+		BlockscoutWorkerHTTPRPCUrl: ctx.String("blockscoutWorkerURL"),
+		JsonHTTPRPCUrl:             ctx.String("JsonRpcURL"),
+
+		GithubAPIUsername: ctx.String("githubAPIUsername"),
+		GithubAPIToken:    ctx.String("githubAPIToken"),
+
+		CorsAllowedOrigins: ctx.String("corsAllowedOrigins"),
+
+		RedisURL: ctx.String("redisURL"),
+	}
+	if ctx.IsSet("color") {
+		cliConfig.LoggerColor = primptr.Bool(ctx.Bool("color"))
+	}
+	if ctx.IsSet("dbSSL") {
+		cliConfig.DatabaseSSL = primptr.Bool(ctx.Bool("dbSSL"))
+	}
+	if ctx.IsSet("dbPort") {
+		cliConfig.DatabasePort = primptr.Int32(int32(ctx.Int("dbPort")))
+	}
+	if ctx.IsSet("indexService") {
+		cliConfig.IndexService = primptr.Bool(ctx.Bool("indexService"))
+	}
+	if ctx.IsSet("cronjobStats") {
+		cliConfig.CronjobStats = primptr.Bool(ctx.Bool("cronjobStats"))
+	}
+	if ctx.IsSet("cronjobReportDashboard") {
+		cliConfig.CronjobReportDashboard = primptr.Bool(ctx.Bool("cronjobReportDashboard"))
+	}
+	if ctx.IsSet("tikiAddress") {
+		cliConfig.TikiAddress = ctx.String("tikiAddress")
+	}
+	if ctx.IsSet("startingBlockHeight") {
+		cliConfig.StartingBlockHeight = primptr.Int64(int64(ctx.Int("startingBlockHeight")))
+	}
+	if ctx.IsSet("enableConsumer") {
+		cliConfig.EnableConsumer = primptr.Bool(ctx.Bool("enableConsumer"))
+	}
+	if ctx.IsSet("consumerGroupId") {
+		cliConfig.ConsumerGroupId = ctx.String("consumerGroupId")
+	}
+	if ctx.IsSet("kafkaBrokers") {
+		cliConfig.KafkaBrokers = ctx.String("kafkaBrokers")
+	}
+	if ctx.IsSet("kafkaUser") {
+		cliConfig.KafkaUser = ctx.String("kafkaUser")
+	}
+	if ctx.IsSet("kafkaPassword") {
+		cliConfig.KafkaPassword = ctx.String("kafkaPassword")
+	}
+	if ctx.IsSet("kafkaAuthenticationType") {
+		cliConfig.KafkaAuthenticationType = ctx.String("kafkaAuthenticationType")
+	}
+	if ctx.IsSet("caCertPath") {
+		cliConfig.CaCertPath = ctx.String("caCertPath")
+	}
+	if ctx.IsSet("tlsCertPath") {
+		cliConfig.TlsCertPath = ctx.String("tlsCertPath")
+	}
+	if ctx.IsSet("tlsKeyPath") {
+		cliConfig.TlsKeyPath = ctx.String("tlsKeyPath")
+	}
+
+	OverrideByCLIConfig(&config, &cliConfig)
+
+	return &config, &customConfig, nil
+}
+
+func newLogger(config *configuration.Config) applogger.Logger {
+	logLevel := parseLogLevel(config.Logger.Level)
+	logger := infrastructure.NewZerologLogger(os.Stdout)
+	logger.SetLogLevel(logLevel)
+
+	return logger
+}
+
 func parseLogLevel(level string) applogger.LogLevel {
 	switch level {
 	case "panic":
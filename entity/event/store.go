@@ -6,6 +6,9 @@ type Store interface {
 
 	GetAllByHeight(height int64) ([]Event, error)
 
+	// GetAllByHeightRange returns all events with height within [fromHeight, toHeight] ordered by height
+	GetAllByHeightRange(fromHeight int64, toHeight int64) ([]Event, error)
+
 	Insert(evt Event) error
 
 	// InsertAll insert all events into store. It will rollback when the insert fails at any point.
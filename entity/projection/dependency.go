@@ -0,0 +1,71 @@
+package projection
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrMissingDependency = errors.New("projection dependency is not enabled")
+var ErrDependencyCycle = errors.New("projection dependencies form a cycle")
+
+// GetDependencies returns the ids of the projections the projection depends on, empty when it is
+// not a DependentProjection
+func GetDependencies(projection Projection) []string {
+	dependentProjection, ok := projection.(DependentProjection)
+	if !ok {
+		return []string{}
+	}
+	return dependentProjection.GetDependencies()
+}
+
+// SortByDependencies returns the projections ordered such that every projection comes after the
+// projections it depends on. Independent projections keep their relative order. It fails when a
+// dependency is not among the projections or the dependencies form a cycle.
+func SortByDependencies(projections []Projection) ([]Projection, error) {
+	projectionsById := make(map[string]Projection, len(projections))
+	for _, projection := range projections {
+		projectionsById[projection.Id()] = projection
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := make(map[string]int, len(projections))
+	sortedProjections := make([]Projection, 0, len(projections))
+
+	var visit func(projection Projection, path []string) error
+	visit = func(projection Projection, path []string) error {
+		switch states[projection.Id()] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%v -> %s: %w", path, projection.Id(), ErrDependencyCycle)
+		}
+		states[projection.Id()] = visiting
+
+		path = append(path, projection.Id())
+		for _, dependencyId := range GetDependencies(projection) {
+			dependency, ok := projectionsById[dependencyId]
+			if !ok {
+				return fmt.Errorf(
+					"projection `%s` depends on `%s`: %w", projection.Id(), dependencyId, ErrMissingDependency,
+				)
+			}
+			if err := visit(dependency, path); err != nil {
+				return err
+			}
+		}
+
+		states[projection.Id()] = visited
+		sortedProjections = append(sortedProjections, projection)
+		return nil
+	}
+
+	for _, projection := range projections {
+		if err := visit(projection, make([]string, 0)); err != nil {
+			return nil, err
+		}
+	}
+	return sortedProjections, nil
+}
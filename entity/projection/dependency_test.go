@@ -0,0 +1,62 @@
+package projection_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/AstraProtocol/astra-indexing/entity/projection"
+	projection_test "github.com/AstraProtocol/astra-indexing/entity/projection/test"
+)
+
+var _ = Describe("SortByDependencies", func() {
+	newDependentProjection := func(id string, dependencies ...string) projection.Projection {
+		dependentProjection := projection_test.NewMockDependentProjection()
+		dependentProjection.On("Id").Return(id)
+		dependentProjection.On("GetDependencies").Return(dependencies)
+		return dependentProjection
+	}
+	ids := func(projections []projection.Projection) []string {
+		projectionIds := make([]string, 0, len(projections))
+		for _, sortedProjection := range projections {
+			projectionIds = append(projectionIds, sortedProjection.Id())
+		}
+		return projectionIds
+	}
+
+	It("should order the projections after the projections they depend on", func() {
+		independentProjection := projection_test.NewMockProjection()
+		independentProjection.On("Id").Return("Independent")
+
+		sortedProjections, err := projection.SortByDependencies([]projection.Projection{
+			newDependentProjection("AccountTransaction", "Transaction"),
+			independentProjection,
+			newDependentProjection("Stats", "AccountTransaction", "Transaction"),
+			newDependentProjection("Transaction"),
+		})
+
+		Expect(err).To(BeNil())
+		Expect(ids(sortedProjections)).To(Equal([]string{
+			"Transaction", "AccountTransaction", "Independent", "Stats",
+		}))
+	})
+
+	It("should fail when a dependency is not among the projections", func() {
+		_, err := projection.SortByDependencies([]projection.Projection{
+			newDependentProjection("AccountTransaction", "Transaction"),
+		})
+
+		Expect(errors.Is(err, projection.ErrMissingDependency)).To(BeTrue())
+	})
+
+	It("should fail when the dependencies form a cycle", func() {
+		_, err := projection.SortByDependencies([]projection.Projection{
+			newDependentProjection("A", "B"),
+			newDependentProjection("B", "C"),
+			newDependentProjection("C", "A"),
+		})
+
+		Expect(errors.Is(err, projection.ErrDependencyCycle)).To(BeTrue())
+	})
+})
@@ -1,7 +1,10 @@
 package projection
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	entity_event "github.com/AstraProtocol/astra-indexing/entity/event"
@@ -11,26 +14,75 @@ import (
 
 const DEFAULT_BLOCK_TIME = 2500 * time.Millisecond
 
+// DEFAULT_MAX_BATCH_SIZE is the maximum number of heights handed over at once to a BatchProjection
+const DEFAULT_MAX_BATCH_SIZE = int64(100)
+
+var ErrProjectionNotRegistered = errors.New("projection is not registered")
+var ErrProjectionNotRebuildable = errors.New("projection does not support rebuild")
+var ErrProjectionNotRebuildableFromHeight = errors.New(
+	"projection does not support rebuild from a height other than the genesis",
+)
+
 // StoreBasedManager is a projection manager relies on replaying events from EventStore
 type StoreBasedManager struct {
 	logger     applogger.Logger
 	eventStore entity_event.Store
 
 	projections []Projection
+	// Projections which are depended on by the registered ones but are run elsewhere
+	dependencies []Projection
+
+	maxBatchSize int64
+
+	// Runners hold the read lock while handling a height, such that a rollback or a rebuild never
+	// interleaves with an event handling. rollbackGeneration is bumped on every rollback and rebuild
+	// to notify the runners to reload their last handled event height. The mutex also guards the
+	// registered projections.
+	rollbackMutex      sync.RWMutex
+	rollbackGeneration int64
+
+	runnersWaitGroup sync.WaitGroup
 }
 
 func NewStoreBasedManager(logger applogger.Logger, eventStore entity_event.Store) *StoreBasedManager {
+	return NewStoreBasedManagerWithOptions(logger, eventStore, StoreBasedManagerOptions{})
+}
+
+func NewStoreBasedManagerWithOptions(
+	logger applogger.Logger,
+	eventStore entity_event.Store,
+	options StoreBasedManagerOptions,
+) *StoreBasedManager {
+	maxBatchSize := DEFAULT_MAX_BATCH_SIZE
+	if options.MaybeMaxBatchSize != nil && *options.MaybeMaxBatchSize > 0 {
+		maxBatchSize = *options.MaybeMaxBatchSize
+	}
+
 	return &StoreBasedManager{
 		logger: logger.WithFields(applogger.LogFields{
 			"module": "projectionManager",
 		}),
 		eventStore: eventStore,
 
-		projections: make([]Projection, 0),
+		projections:  make([]Projection, 0),
+		dependencies: make([]Projection, 0),
+
+		maxBatchSize: maxBatchSize,
 	}
 }
 
+type StoreBasedManagerOptions struct {
+	// Customize the maximum number of heights handed over at once to a BatchProjection. A size of 1
+	// disables batching.
+	MaybeMaxBatchSize *int64
+}
+
+var _ Rebuilder = &StoreBasedManager{}
+
 func (manager *StoreBasedManager) RegisterProjection(projection Projection) error {
+	manager.rollbackMutex.Lock()
+	defer manager.rollbackMutex.Unlock()
+
 	if manager.IsProjectionRegistered(projection) {
 		return fmt.Errorf("projection `%s` already registered", projection.Id())
 	}
@@ -38,6 +90,19 @@ func (manager *StoreBasedManager) RegisterProjection(projection Projection) erro
 	return nil
 }
 
+// RegisterDependency registers a projection which registered projections depend on, but which is
+// run elsewhere. The dependent projections never handle a height before it has handled it.
+func (manager *StoreBasedManager) RegisterDependency(projection Projection) error {
+	manager.rollbackMutex.Lock()
+	defer manager.rollbackMutex.Unlock()
+
+	if manager.IsProjectionRegistered(projection) || manager.findDependency(projection.Id()) != nil {
+		return fmt.Errorf("projection `%s` already registered", projection.Id())
+	}
+	manager.dependencies = append(manager.dependencies, projection)
+	return nil
+}
+
 func (manager *StoreBasedManager) IsProjectionRegistered(projection Projection) bool {
 	for _, registeredProjection := range manager.projections {
 		if projection.Id() == registeredProjection.Id() {
@@ -47,14 +112,39 @@ func (manager *StoreBasedManager) IsProjectionRegistered(projection Projection)
 	return false
 }
 
-// Starts projectionManager by running all registered projection.
-func (manager *StoreBasedManager) RunInBackground() {
-	for _, projection := range manager.projections {
-		go manager.projectionRunner(projection)
+// Starts projectionManager by running all registered projection until the context is done. The
+// projections start after the projections they depend on. It fails without running any projection
+// when a dependency is not registered or the dependencies form a cycle.
+func (manager *StoreBasedManager) RunInBackground(ctx context.Context) error {
+	manager.rollbackMutex.RLock()
+	projections := make([]Projection, 0, len(manager.dependencies)+len(manager.projections))
+	projections = append(append(projections, manager.dependencies...), manager.projections...)
+	sortedProjections, err := SortByDependencies(projections)
+	manager.rollbackMutex.RUnlock()
+	if err != nil {
+		return fmt.Errorf("error resolving projection dependencies: %w", err)
+	}
+
+	for _, projection := range sortedProjections {
+		if !manager.IsProjectionRegistered(projection) {
+			continue
+		}
+		manager.runnersWaitGroup.Add(1)
+		go func(projection Projection) {
+			defer manager.runnersWaitGroup.Done()
+			manager.projectionRunner(ctx, projection)
+		}(projection)
 	}
+	return nil
 }
 
-func (manager *StoreBasedManager) projectionRunner(projection Projection) {
+// Wait blocks until all projection runners have stopped. A runner stops after the context is done
+// and the events of its current height are handled.
+func (manager *StoreBasedManager) Wait() {
+	manager.runnersWaitGroup.Wait()
+}
+
+func (manager *StoreBasedManager) projectionRunner(ctx context.Context, projection Projection) {
 	eventsToListen := projection.GetEventsToListen()
 	logger := manager.logger.WithFields(applogger.LogFields{
 		"projection": projection.Id(),
@@ -64,76 +154,401 @@ func (manager *StoreBasedManager) projectionRunner(projection Projection) {
 		"eventsToListen": eventsToListen,
 	}).Infof("projection start running")
 
-	var lastHandledEventHeight *int64
-	for {
-		var err error
-		lastHandledEventHeight, err = projection.GetLastHandledEventHeight()
-		if err == nil {
-			break
+	nextEventHeight, rollbackGeneration, ok := manager.loadNextEventHeight(ctx, logger, projection)
+	for ok {
+		if manager.isRolledBackSince(rollbackGeneration) {
+			nextEventHeight, rollbackGeneration, ok = manager.loadNextEventHeight(ctx, logger, projection)
+			continue
 		}
 
-		logger.Infof("error getting last handled event height from projection")
-		<-waitFor(DEFAULT_BLOCK_TIME)
-	}
-
-	var nextEventHeight int64
-	if lastHandledEventHeight == nil {
-		nextEventHeight = 0
-	} else {
-		nextEventHeight = *lastHandledEventHeight + 1
-	}
-
-	for {
 		latestEventHeight, _ := manager.eventStore.GetLatestHeight()
 		if latestEventHeight == nil {
 			logger.Debugf("no event in in the system yet")
-			<-waitFor(DEFAULT_BLOCK_TIME)
+			ok = waitFor(ctx, DEFAULT_BLOCK_TIME)
+			continue
+		}
+		// Dependent projections never run ahead of the projections they depend on
+		maxEventHeight, err := manager.getDependenciesHandledHeight(projection, *latestEventHeight)
+		if err != nil {
+			logger.Errorf("error getting last handled event height of dependencies: %v", err)
+			ok = waitFor(ctx, DEFAULT_BLOCK_TIME)
 			continue
 		}
-		for nextEventHeight <= *latestEventHeight {
+		for nextEventHeight <= maxEventHeight && ok {
 			startTime := time.Now()
 			var err error
 
+			toEventHeight := manager.batchToHeight(projection, nextEventHeight, maxEventHeight)
 			eventLogger := logger.WithFields(applogger.LogFields{
 				"height": nextEventHeight,
 			})
+			if toEventHeight > nextEventHeight {
+				eventLogger = eventLogger.WithFields(applogger.LogFields{
+					"toHeight": toEventHeight,
+				})
+			}
 
-			var eventsAtHeight []entity_event.Event
-			if eventsAtHeight, err = manager.eventStore.GetAllByHeight(nextEventHeight); err != nil {
-				eventLogger.Errorf("error getting all events by height: %v", err)
-				<-waitFor(time.Second)
+			manager.rollbackMutex.RLock()
+			if manager.rollbackGeneration != rollbackGeneration {
+				manager.rollbackMutex.RUnlock()
+				nextEventHeight, rollbackGeneration, ok = manager.loadNextEventHeight(ctx, logger, projection)
 				continue
 			}
 
-			var events = make([]entity_event.Event, 0)
-			for _, event := range eventsAtHeight {
-				if !isListeningEvent(event, eventsToListen) {
-					//eventLogger.WithFields(applogger.LogFields{
-					//	"eventName": event.Name(),
-					//}).Debugf("skipping because event is not one of the listening events")
-					continue
-				}
-				events = append(events, event)
+			var events []entity_event.Event
+			if events, err = manager.getListeningEvents(nextEventHeight, toEventHeight, eventsToListen); err != nil {
+				manager.rollbackMutex.RUnlock()
+				eventLogger.Errorf("error getting events: %v", err)
+				ok = waitFor(ctx, time.Second)
+				continue
 			}
 
 			eventLogger = eventLogger.WithFields(applogger.LogFields{
 				"eventCount": len(events),
 			})
-			if err = projection.HandleEvents(nextEventHeight, events); err != nil {
+			if toEventHeight > nextEventHeight {
+				err = projection.(BatchProjection).HandleEventsBatch(nextEventHeight, toEventHeight, events)
+			} else {
+				err = projection.HandleEvents(nextEventHeight, events)
+			}
+			manager.rollbackMutex.RUnlock()
+			if err != nil {
 				eventLogger.WithFields(applogger.LogFields{
 					"events": events,
 				}).Errorf("error handling events: %v", err)
-				<-waitFor(DEFAULT_BLOCK_TIME)
+				ok = waitFor(ctx, DEFAULT_BLOCK_TIME)
 				continue
 			}
 
 			eventLogger.Infof("successfully handled events")
 			prometheus.RecordProjectionExecTime(projection.Id(), time.Since(startTime).Milliseconds())
-			nextEventHeight += 1
+			nextEventHeight = toEventHeight + 1
+			ok = ctx.Err() == nil
 		}
 		prometheus.RecordProjectionLatestHeight(projection.Id(), nextEventHeight)
-		<-waitFor(DEFAULT_BLOCK_TIME)
+		ok = ok && waitFor(ctx, DEFAULT_BLOCK_TIME)
+	}
+
+	logger.Infof("projection stopped")
+}
+
+// getDependenciesHandledHeight returns the lowest last handled event height among the projections
+// the projection depends on, capped by `latestEventHeight`. It is -1 when any of them has not
+// handled an event yet.
+func (manager *StoreBasedManager) getDependenciesHandledHeight(
+	projection Projection,
+	latestEventHeight int64,
+) (int64, error) {
+	dependencyIds := GetDependencies(projection)
+	if len(dependencyIds) == 0 {
+		return latestEventHeight, nil
+	}
+
+	manager.rollbackMutex.RLock()
+	defer manager.rollbackMutex.RUnlock()
+
+	handledHeight := latestEventHeight
+	for _, dependencyId := range dependencyIds {
+		dependency := manager.findProjection(dependencyId)
+		if dependency == nil {
+			dependency = manager.findDependency(dependencyId)
+		}
+		if dependency == nil {
+			return 0, fmt.Errorf("projection `%s` depends on `%s`: %w", projection.Id(), dependencyId, ErrMissingDependency)
+		}
+
+		lastHandledEventHeight, err := dependency.GetLastHandledEventHeight()
+		if err != nil {
+			return 0, fmt.Errorf("error getting last handled event height of projection `%s`: %v", dependencyId, err)
+		}
+		if lastHandledEventHeight == nil {
+			return -1, nil
+		}
+		if *lastHandledEventHeight < handledHeight {
+			handledHeight = *lastHandledEventHeight
+		}
+	}
+	return handledHeight, nil
+}
+
+// batchToHeight returns the last height of the range to hand over to the projection from
+// `fromHeight`. Only BatchProjection is handed over more than one height at once.
+func (manager *StoreBasedManager) batchToHeight(
+	projection Projection,
+	fromHeight int64,
+	latestEventHeight int64,
+) int64 {
+	if _, ok := projection.(BatchProjection); !ok {
+		return fromHeight
+	}
+
+	toHeight := fromHeight + manager.maxBatchSize - 1
+	if toHeight > latestEventHeight {
+		return latestEventHeight
+	}
+	return toHeight
+}
+
+// getListeningEvents returns the events within [fromHeight, toHeight] which are listened by the
+// projection, ordered by height
+func (manager *StoreBasedManager) getListeningEvents(
+	fromHeight int64,
+	toHeight int64,
+	eventsToListen []string,
+) ([]entity_event.Event, error) {
+	var storedEvents []entity_event.Event
+	var err error
+	if toHeight > fromHeight {
+		if storedEvents, err = manager.eventStore.GetAllByHeightRange(fromHeight, toHeight); err != nil {
+			return nil, fmt.Errorf("error getting all events by height range: %v", err)
+		}
+	} else {
+		if storedEvents, err = manager.eventStore.GetAllByHeight(fromHeight); err != nil {
+			return nil, fmt.Errorf("error getting all events by height: %v", err)
+		}
+	}
+
+	var events = make([]entity_event.Event, 0)
+	for _, event := range storedEvents {
+		if !isListeningEvent(event, eventsToListen) {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// loadNextEventHeight returns the next event height to handle of the projection together with
+// the rollback generation it is loaded at. It retries until the height is loaded or the context is
+// done, which is reported by the last returned value.
+func (manager *StoreBasedManager) loadNextEventHeight(
+	ctx context.Context,
+	logger applogger.Logger,
+	projection Projection,
+) (int64, int64, bool) {
+	for ctx.Err() == nil {
+		manager.rollbackMutex.RLock()
+		rollbackGeneration := manager.rollbackGeneration
+		lastHandledEventHeight, err := projection.GetLastHandledEventHeight()
+		manager.rollbackMutex.RUnlock()
+		if err != nil {
+			logger.Infof("error getting last handled event height from projection")
+			waitFor(ctx, DEFAULT_BLOCK_TIME)
+			continue
+		}
+
+		if lastHandledEventHeight == nil {
+			return 0, rollbackGeneration, true
+		}
+		return *lastHandledEventHeight + 1, rollbackGeneration, true
 	}
+	return 0, 0, false
+}
+
+func (manager *StoreBasedManager) isRolledBackSince(rollbackGeneration int64) bool {
+	manager.rollbackMutex.RLock()
+	defer manager.rollbackMutex.RUnlock()
+
+	return manager.rollbackGeneration != rollbackGeneration
+}
+
+// GetLowestLastHandledEventHeight returns the lowest last handled event height among the registered
+// projections. It is nil when no projection is registered or any of them has not handled an event.
+func (manager *StoreBasedManager) GetLowestLastHandledEventHeight() (*int64, error) {
+	manager.rollbackMutex.RLock()
+	defer manager.rollbackMutex.RUnlock()
+
+	var lowestHeight *int64
+	for _, projection := range manager.projections {
+		lastHandledEventHeight, err := projection.GetLastHandledEventHeight()
+		if err != nil {
+			return nil, fmt.Errorf("error getting last handled event height of projection `%s`: %v", projection.Id(), err)
+		}
+		if lastHandledEventHeight == nil {
+			return nil, nil
+		}
+		if lowestHeight == nil || *lastHandledEventHeight < *lowestHeight {
+			lowestHeight = lastHandledEventHeight
+		}
+	}
+	return lowestHeight, nil
+}
+
+// Rollback undoes the outcomes of all registered projections within [fromHeight, toHeight]. The
+// projection runners are paused during the rollback and resume from their rewound heights.
+// Projections not supporting rollback are reset and replay the events from the genesis instead.
+func (manager *StoreBasedManager) Rollback(fromHeight int64, toHeight int64) error {
+	manager.rollbackMutex.Lock()
+	defer manager.rollbackMutex.Unlock()
+	manager.rollbackGeneration += 1
+
+	// Verify every affected projection is able to rollback before touching any of them
+	affectedProjections := make([]Projection, 0)
+	for _, projection := range manager.projections {
+		lastHandledEventHeight, err := projection.GetLastHandledEventHeight()
+		if err != nil {
+			return fmt.Errorf("error getting last handled event height of projection `%s`: %v", projection.Id(), err)
+		}
+		if lastHandledEventHeight == nil || *lastHandledEventHeight < fromHeight {
+			continue
+		}
+
+		if !CanRollbackOrReset(projection) {
+			return fmt.Errorf("projection `%s` does not support rollback, it has to be rebuilt", projection.Id())
+		}
+		affectedProjections = append(affectedProjections, projection)
+	}
+
+	for _, projection := range affectedProjections {
+		isReset, err := RollbackOrReset(projection, fromHeight, toHeight)
+		if err != nil {
+			return fmt.Errorf("error rolling back projection `%s`: %v", projection.Id(), err)
+		}
+		logger := manager.logger.WithFields(applogger.LogFields{
+			"projection": projection.Id(),
+		})
+		if isReset {
+			logger.Infof("projection does not support rollback, reset it to replay events from the genesis")
+			continue
+		}
+		logger.Infof("successfully rolled back projection from height %d to %d", fromHeight, toHeight)
+	}
+
+	return nil
+}
+
+// Rebuild resets the registered projection and its dependents, then replays the events from
+// `fromHeight`. The projection runners are paused during the reset, the runners of the rebuilt
+// projections then resume from `fromHeight` and the others from where they were.
+//
+// Only the genesis resets the whole views. Rebuilding from a later height removes the records from
+// `fromHeight` onwards with a rollback, such that the records below it are kept, so every rebuilt
+// projection has to support rollback.
+func (manager *StoreBasedManager) Rebuild(projectionId string, fromHeight int64) error {
+	if fromHeight < 0 {
+		return fmt.Errorf("invalid rebuild height: %d", fromHeight)
+	}
+
+	manager.rollbackMutex.Lock()
+	defer manager.rollbackMutex.Unlock()
+
+	projection := manager.findProjection(projectionId)
+	if projection == nil {
+		return fmt.Errorf("error rebuilding `%s`: %w", projectionId, ErrProjectionNotRegistered)
+	}
+	// The dependents are rebuilt together, such that they never run ahead of the rebuilt projection
+	projectionsToRebuild := append([]Projection{projection}, manager.findDependents(projectionId)...)
+	for _, projectionToRebuild := range projectionsToRebuild {
+		if _, ok := projectionToRebuild.(RebuildableProjection); !ok {
+			return fmt.Errorf("error rebuilding `%s`: %w", projectionToRebuild.Id(), ErrProjectionNotRebuildable)
+		}
+		if _, ok := projectionToRebuild.(RollbackableProjection); !ok && fromHeight > 0 {
+			return fmt.Errorf("error rebuilding `%s`: %w", projectionToRebuild.Id(), ErrProjectionNotRebuildableFromHeight)
+		}
+	}
+	manager.rollbackGeneration += 1
+
+	for _, projectionToRebuild := range projectionsToRebuild {
+		if fromHeight == 0 {
+			if err := projectionToRebuild.(RebuildableProjection).Reset(fromHeight); err != nil {
+				return fmt.Errorf("error resetting projection `%s`: %v", projectionToRebuild.Id(), err)
+			}
+		} else if err := rollbackFrom(projectionToRebuild.(RollbackableProjection), fromHeight); err != nil {
+			return fmt.Errorf("error resetting projection `%s`: %v", projectionToRebuild.Id(), err)
+		}
+		manager.logger.WithFields(applogger.LogFields{
+			"projection": projectionToRebuild.Id(),
+		}).Infof("successfully reset projection, going to replay events from height %d", fromHeight)
+	}
+
+	return nil
+}
+
+// rollbackFrom rolls back all the heights the projection has handled from `fromHeight` onwards
+func rollbackFrom(projection RollbackableProjection, fromHeight int64) error {
+	lastHandledEventHeight, err := projection.GetLastHandledEventHeight()
+	if err != nil {
+		return fmt.Errorf("error getting last handled event height: %v", err)
+	}
+	if lastHandledEventHeight == nil || *lastHandledEventHeight < fromHeight {
+		return nil
+	}
+	return projection.Rollback(fromHeight, *lastHandledEventHeight)
+}
+
+// WaitUntilCaughtUp blocks until the registered projection has handled the latest event height at
+// the time of calling, or the context is done
+func (manager *StoreBasedManager) WaitUntilCaughtUp(ctx context.Context, projectionId string) error {
+	manager.rollbackMutex.RLock()
+	projection := manager.findProjection(projectionId)
+	manager.rollbackMutex.RUnlock()
+	if projection == nil {
+		return fmt.Errorf("error waiting for `%s`: %w", projectionId, ErrProjectionNotRegistered)
+	}
+
+	latestEventHeight, err := manager.eventStore.GetLatestHeight()
+	if err != nil {
+		return fmt.Errorf("error getting latest event height: %v", err)
+	}
+	if latestEventHeight == nil {
+		return nil
+	}
+	for {
+		lastHandledEventHeight, err := projection.GetLastHandledEventHeight()
+		if err != nil {
+			return fmt.Errorf("error getting last handled event height of projection `%s`: %v", projectionId, err)
+		}
+		if lastHandledEventHeight != nil && *lastHandledEventHeight >= *latestEventHeight {
+			return nil
+		}
+		if !waitFor(ctx, DEFAULT_BLOCK_TIME) {
+			return ctx.Err()
+		}
+	}
+}
+
+// findProjection returns the registered projection of the id, the caller must hold the rollback mutex
+func (manager *StoreBasedManager) findProjection(projectionId string) Projection {
+	for _, projection := range manager.projections {
+		if projection.Id() == projectionId {
+			return projection
+		}
+	}
+	return nil
+}
+
+// findDependency returns the registered dependency of the id, the caller must hold the rollback mutex
+func (manager *StoreBasedManager) findDependency(projectionId string) Projection {
+	for _, projection := range manager.dependencies {
+		if projection.Id() == projectionId {
+			return projection
+		}
+	}
+	return nil
+}
+
+// findDependents returns the registered projections depending on the projection of the id, directly
+// or transitively. The caller must hold the rollback mutex.
+func (manager *StoreBasedManager) findDependents(projectionId string) []Projection {
+	dependents := make([]Projection, 0)
+	dependencyIds := map[string]bool{projectionId: true}
+	for foundDependent := true; foundDependent; {
+		foundDependent = false
+		for _, projection := range manager.projections {
+			if dependencyIds[projection.Id()] {
+				continue
+			}
+			for _, dependencyId := range GetDependencies(projection) {
+				if dependencyIds[dependencyId] {
+					dependencyIds[projection.Id()] = true
+					dependents = append(dependents, projection)
+					foundDependent = true
+					break
+				}
+			}
+		}
+	}
+	return dependents
 }
 
 func isListeningEvent(event entity_event.Event, eventsToListen []string) bool {
@@ -147,6 +562,12 @@ func isListeningEvent(event entity_event.Event, eventsToListen []string) bool {
 	return false
 }
 
-func waitFor(wait time.Duration) <-chan time.Time {
-	return time.After(wait)
+// waitFor waits for the duration, returns false when the context is done before that
+func waitFor(ctx context.Context, wait time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(wait):
+		return true
+	}
 }
@@ -0,0 +1,394 @@
+package projection_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	entity_event "github.com/AstraProtocol/astra-indexing/entity/event"
+	event_test "github.com/AstraProtocol/astra-indexing/entity/event/test"
+	"github.com/AstraProtocol/astra-indexing/entity/projection"
+	projection_test "github.com/AstraProtocol/astra-indexing/entity/projection/test"
+	"github.com/AstraProtocol/astra-indexing/external/logger/test"
+	"github.com/AstraProtocol/astra-indexing/external/primptr"
+)
+
+var _ = Describe("StoreBasedManager", func() {
+	Describe("RunInBackground", func() {
+		It("should stop all projection runners when the context is done", func() {
+			eventStore := event_test.NewMockEventStore()
+			eventStore.On("GetLatestHeight").Return(nil, nil)
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), eventStore)
+
+			mockProjection := projection_test.NewMockProjection()
+			mockProjection.On("Id").Return("Mock")
+			mockProjection.On("GetEventsToListen").Return([]string{})
+			mockProjection.On("GetLastHandledEventHeight").Return(nil, nil)
+			Expect(manager.RegisterProjection(mockProjection)).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			Expect(manager.RunInBackground(ctx)).To(Succeed())
+			cancel()
+
+			stopped := make(chan struct{})
+			go func() {
+				manager.Wait()
+				close(stopped)
+			}()
+			Eventually(stopped, time.Second).Should(BeClosed())
+		})
+
+		It("should fail without running any projection when a dependency is not registered", func() {
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), event_test.NewMockEventStore())
+
+			dependentProjection := projection_test.NewMockDependentProjection()
+			dependentProjection.On("Id").Return("Dependent")
+			dependentProjection.On("GetDependencies").Return([]string{"Missing"})
+			Expect(manager.RegisterProjection(dependentProjection)).To(Succeed())
+
+			Expect(errors.Is(
+				manager.RunInBackground(context.Background()), projection.ErrMissingDependency,
+			)).To(BeTrue())
+			dependentProjection.AssertNotCalled(GinkgoT(), "GetLastHandledEventHeight")
+		})
+	})
+
+	Describe("projection runner", func() {
+		It("should hand over ranges of heights to batch projections", func() {
+			fakeEvent := event_test.NewFakeEvent()
+			eventStore := event_test.NewMockEventStore()
+			eventStore.On("GetLatestHeight").Return(primptr.Int64(4), nil)
+			eventStore.On("GetAllByHeightRange", int64(0), int64(1)).Return([]entity_event.Event{fakeEvent}, nil)
+			eventStore.On("GetAllByHeightRange", int64(2), int64(3)).Return([]entity_event.Event{}, nil)
+			eventStore.On("GetAllByHeight", int64(4)).Return([]entity_event.Event{fakeEvent}, nil)
+			manager := projection.NewStoreBasedManagerWithOptions(
+				test.NewFakeLogger(), eventStore, projection.StoreBasedManagerOptions{
+					MaybeMaxBatchSize: primptr.Int64(2),
+				},
+			)
+
+			batchProjection := projection_test.NewMockBatchProjection()
+			batchProjection.On("Id").Return("Batch")
+			batchProjection.On("GetEventsToListen").Return([]string{fakeEvent.Name()})
+			batchProjection.On("GetLastHandledEventHeight").Return((*int64)(nil), nil)
+			batchProjection.On("HandleEventsBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			caughtUp := make(chan struct{})
+			batchProjection.On("HandleEvents", int64(4), mock.Anything).Return(nil).Run(func(_ mock.Arguments) {
+				close(caughtUp)
+			})
+			Expect(manager.RegisterProjection(batchProjection)).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer func() {
+				cancel()
+				manager.Wait()
+			}()
+			Expect(manager.RunInBackground(ctx)).To(Succeed())
+
+			Eventually(caughtUp, time.Second).Should(BeClosed())
+			batchProjection.AssertCalled(
+				GinkgoT(), "HandleEventsBatch", int64(0), int64(1), []entity_event.Event{fakeEvent},
+			)
+			batchProjection.AssertCalled(GinkgoT(), "HandleEventsBatch", int64(2), int64(3), []entity_event.Event{})
+			batchProjection.AssertCalled(GinkgoT(), "HandleEvents", int64(4), []entity_event.Event{fakeEvent})
+		})
+
+		It("should hand over one height at a time to other projections", func() {
+			fakeEvent := event_test.NewFakeEvent()
+			eventStore := event_test.NewMockEventStore()
+			eventStore.On("GetLatestHeight").Return(primptr.Int64(1), nil)
+			eventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{fakeEvent}, nil)
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), eventStore)
+
+			mockProjection := projection_test.NewMockProjection()
+			mockProjection.On("Id").Return("Mock")
+			mockProjection.On("GetEventsToListen").Return([]string{"OtherEvent"})
+			mockProjection.On("GetLastHandledEventHeight").Return((*int64)(nil), nil)
+			mockProjection.On("HandleEvents", int64(0), mock.Anything).Return(nil)
+			caughtUp := make(chan struct{})
+			mockProjection.On("HandleEvents", int64(1), mock.Anything).Return(nil).Run(func(_ mock.Arguments) {
+				close(caughtUp)
+			})
+			Expect(manager.RegisterProjection(mockProjection)).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer func() {
+				cancel()
+				manager.Wait()
+			}()
+			Expect(manager.RunInBackground(ctx)).To(Succeed())
+
+			Eventually(caughtUp, time.Second).Should(BeClosed())
+			mockProjection.AssertCalled(GinkgoT(), "HandleEvents", int64(0), []entity_event.Event{})
+			mockProjection.AssertCalled(GinkgoT(), "HandleEvents", int64(1), []entity_event.Event{})
+			eventStore.AssertNotCalled(GinkgoT(), "GetAllByHeightRange", mock.Anything, mock.Anything)
+		})
+
+		It("should not hand over heights the dependencies have not handled yet", func() {
+			fakeEvent := event_test.NewFakeEvent()
+			eventStore := event_test.NewMockEventStore()
+			eventStore.On("GetLatestHeight").Return(primptr.Int64(5), nil)
+			eventStore.On("GetAllByHeight", mock.Anything).Return([]entity_event.Event{fakeEvent}, nil)
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), eventStore)
+
+			dependency := projection_test.NewMockProjection()
+			dependency.On("Id").Return("Dependency")
+			dependency.On("GetLastHandledEventHeight").Return(primptr.Int64(1), nil)
+			Expect(manager.RegisterDependency(dependency)).To(Succeed())
+
+			dependentProjection := projection_test.NewMockDependentProjection()
+			dependentProjection.On("Id").Return("Dependent")
+			dependentProjection.On("GetDependencies").Return([]string{"Dependency"})
+			dependentProjection.On("GetEventsToListen").Return([]string{})
+			dependentProjection.On("GetLastHandledEventHeight").Return((*int64)(nil), nil)
+			dependentProjection.On("HandleEvents", int64(0), mock.Anything).Return(nil)
+			caughtUp := make(chan struct{})
+			dependentProjection.On("HandleEvents", int64(1), mock.Anything).Return(nil).Run(func(_ mock.Arguments) {
+				close(caughtUp)
+			})
+			Expect(manager.RegisterProjection(dependentProjection)).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			Expect(manager.RunInBackground(ctx)).To(Succeed())
+			Eventually(caughtUp, time.Second).Should(BeClosed())
+			cancel()
+			manager.Wait()
+
+			dependentProjection.AssertNotCalled(GinkgoT(), "HandleEvents", int64(2), mock.Anything)
+		})
+	})
+
+	Describe("GetLowestLastHandledEventHeight", func() {
+		It("should return the lowest last handled event height among the projections", func() {
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), event_test.NewMockEventStore())
+			Expect(manager.GetLowestLastHandledEventHeight()).To(BeNil())
+
+			leadingProjection := projection_test.NewMockProjection()
+			leadingProjection.On("Id").Return("Leading")
+			leadingProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(12), nil)
+			laggingProjection := projection_test.NewMockProjection()
+			laggingProjection.On("Id").Return("Lagging")
+			laggingProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(9), nil)
+			Expect(manager.RegisterProjection(leadingProjection)).To(Succeed())
+			Expect(manager.RegisterProjection(laggingProjection)).To(Succeed())
+
+			Expect(manager.GetLowestLastHandledEventHeight()).To(Equal(primptr.Int64(9)))
+		})
+
+		It("should return nil when any projection has not handled an event", func() {
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), event_test.NewMockEventStore())
+
+			handledProjection := projection_test.NewMockProjection()
+			handledProjection.On("Id").Return("Handled")
+			handledProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(12), nil)
+			newProjection := projection_test.NewMockProjection()
+			newProjection.On("Id").Return("New")
+			newProjection.On("GetLastHandledEventHeight").Return((*int64)(nil), nil)
+			Expect(manager.RegisterProjection(handledProjection)).To(Succeed())
+			Expect(manager.RegisterProjection(newProjection)).To(Succeed())
+
+			Expect(manager.GetLowestLastHandledEventHeight()).To(BeNil())
+		})
+	})
+
+	Describe("Rollback", func() {
+		It("should rollback projections which have handled the rolled back heights", func() {
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), event_test.NewMockEventStore())
+
+			affectedProjection := projection_test.NewMockRollbackableProjection()
+			affectedProjection.On("Id").Return("Affected")
+			affectedProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(12), nil)
+			affectedProjection.On("Rollback", int64(10), int64(12)).Return(nil)
+
+			laggingProjection := projection_test.NewMockProjection()
+			laggingProjection.On("Id").Return("Lagging")
+			laggingProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(9), nil)
+
+			Expect(manager.RegisterProjection(affectedProjection)).To(Succeed())
+			Expect(manager.RegisterProjection(laggingProjection)).To(Succeed())
+
+			Expect(manager.Rollback(10, 12)).To(Succeed())
+			affectedProjection.AssertCalled(GinkgoT(), "Rollback", int64(10), int64(12))
+		})
+
+		It("should reset the affected projections which do not support rollback but opt in to be reset", func() {
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), event_test.NewMockEventStore())
+
+			rollbackableProjection := projection_test.NewMockRollbackableProjection()
+			rollbackableProjection.On("Id").Return("Rollbackable")
+			rollbackableProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(12), nil)
+			rollbackableProjection.On("Rollback", int64(10), int64(12)).Return(nil)
+
+			resetOnRollbackProjection := projection_test.NewMockResetOnRollbackProjection()
+			resetOnRollbackProjection.On("Id").Return("ResetOnRollback")
+			resetOnRollbackProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(12), nil)
+			resetOnRollbackProjection.On("Reset", int64(0)).Return(nil)
+
+			Expect(manager.RegisterProjection(rollbackableProjection)).To(Succeed())
+			Expect(manager.RegisterProjection(resetOnRollbackProjection)).To(Succeed())
+
+			Expect(manager.Rollback(10, 12)).To(Succeed())
+			rollbackableProjection.AssertCalled(GinkgoT(), "Rollback", int64(10), int64(12))
+			resetOnRollbackProjection.AssertCalled(GinkgoT(), "Reset", int64(0))
+		})
+
+		It("should not reset the affected rebuildable projections which do not opt in to be reset", func() {
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), event_test.NewMockEventStore())
+
+			rebuildableProjection := projection_test.NewMockRebuildableProjection()
+			rebuildableProjection.On("Id").Return("Rebuildable")
+			rebuildableProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(12), nil)
+
+			Expect(manager.RegisterProjection(rebuildableProjection)).To(Succeed())
+
+			Expect(manager.Rollback(10, 12)).To(
+				MatchError("projection `Rebuildable` does not support rollback, it has to be rebuilt"),
+			)
+			rebuildableProjection.AssertNotCalled(GinkgoT(), "Reset", int64(0))
+		})
+
+		It("should not rollback any projection when an affected projection does not support rollback", func() {
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), event_test.NewMockEventStore())
+
+			rollbackableProjection := projection_test.NewMockRollbackableProjection()
+			rollbackableProjection.On("Id").Return("Rollbackable")
+			rollbackableProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(12), nil)
+			rollbackableProjection.On("Rollback", int64(10), int64(12)).Return(nil)
+
+			nonRollbackableProjection := projection_test.NewMockProjection()
+			nonRollbackableProjection.On("Id").Return("NonRollbackable")
+			nonRollbackableProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(12), nil)
+
+			Expect(manager.RegisterProjection(rollbackableProjection)).To(Succeed())
+			Expect(manager.RegisterProjection(nonRollbackableProjection)).To(Succeed())
+
+			Expect(manager.Rollback(10, 12)).To(
+				MatchError("projection `NonRollbackable` does not support rollback, it has to be rebuilt"),
+			)
+			rollbackableProjection.AssertNotCalled(GinkgoT(), "Rollback", int64(10), int64(12))
+		})
+	})
+
+	Describe("Rebuild", func() {
+		It("should reset the projection to replay the events from the genesis", func() {
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), event_test.NewMockEventStore())
+
+			rebuildableProjection := projection_test.NewMockRebuildableProjection()
+			rebuildableProjection.On("Id").Return("Rebuildable")
+			rebuildableProjection.On("Reset", int64(0)).Return(nil)
+			Expect(manager.RegisterProjection(rebuildableProjection)).To(Succeed())
+
+			Expect(manager.Rebuild("Rebuildable", 0)).To(Succeed())
+			rebuildableProjection.AssertCalled(GinkgoT(), "Reset", int64(0))
+		})
+
+		It("should keep the records below the height when rebuilding from a later height", func() {
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), event_test.NewMockEventStore())
+
+			recordHeights := []int64{98, 99, 100, 101, 120}
+			rebuildableProjection := projection_test.NewMockRebuildableRollbackableProjection()
+			rebuildableProjection.On("Id").Return("Rebuildable")
+			rebuildableProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(120), nil)
+			rebuildableProjection.On("Rollback", int64(100), int64(120)).Return(nil).Run(func(args mock.Arguments) {
+				keptHeights := make([]int64, 0)
+				for _, height := range recordHeights {
+					if height < args.Get(0).(int64) || height > args.Get(1).(int64) {
+						keptHeights = append(keptHeights, height)
+					}
+				}
+				recordHeights = keptHeights
+			})
+			Expect(manager.RegisterProjection(rebuildableProjection)).To(Succeed())
+
+			Expect(manager.Rebuild("Rebuildable", 100)).To(Succeed())
+			rebuildableProjection.AssertNotCalled(GinkgoT(), "Reset", mock.Anything)
+			Expect(recordHeights).To(Equal([]int64{98, 99}))
+		})
+
+		It("should reject rebuilding from a later height when a projection does not support rollback", func() {
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), event_test.NewMockEventStore())
+
+			rollbackableProjection := projection_test.NewMockRebuildableRollbackableProjection()
+			rollbackableProjection.On("Id").Return("Rollbackable")
+			rollbackableProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(120), nil)
+			dependentProjection := projection_test.NewMockDependentProjection()
+			dependentProjection.On("Id").Return("Dependent")
+			dependentProjection.On("GetDependencies").Return([]string{"Rollbackable"})
+			Expect(manager.RegisterProjection(rollbackableProjection)).To(Succeed())
+			Expect(manager.RegisterProjection(dependentProjection)).To(Succeed())
+
+			Expect(errors.Is(
+				manager.Rebuild("Rollbackable", 100), projection.ErrProjectionNotRebuildableFromHeight,
+			)).To(BeTrue())
+			rollbackableProjection.AssertNotCalled(GinkgoT(), "Rollback", mock.Anything, mock.Anything)
+			dependentProjection.AssertNotCalled(GinkgoT(), "Reset", mock.Anything)
+		})
+
+		It("should reset the dependents of the projection together with it", func() {
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), event_test.NewMockEventStore())
+
+			rebuildableProjection := projection_test.NewMockRebuildableProjection()
+			rebuildableProjection.On("Id").Return("Rebuildable")
+			rebuildableProjection.On("Reset", int64(0)).Return(nil)
+			dependentProjection := projection_test.NewMockDependentProjection()
+			dependentProjection.On("Id").Return("Dependent")
+			dependentProjection.On("GetDependencies").Return([]string{"Rebuildable"})
+			dependentProjection.On("Reset", int64(0)).Return(nil)
+			Expect(manager.RegisterProjection(rebuildableProjection)).To(Succeed())
+			Expect(manager.RegisterProjection(dependentProjection)).To(Succeed())
+
+			Expect(manager.Rebuild("Rebuildable", 0)).To(Succeed())
+			rebuildableProjection.AssertCalled(GinkgoT(), "Reset", int64(0))
+			dependentProjection.AssertCalled(GinkgoT(), "Reset", int64(0))
+		})
+
+		It("should reject projections which are not registered or do not support rebuild", func() {
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), event_test.NewMockEventStore())
+
+			nonRebuildableProjection := projection_test.NewMockProjection()
+			nonRebuildableProjection.On("Id").Return("NonRebuildable")
+			Expect(manager.RegisterProjection(nonRebuildableProjection)).To(Succeed())
+
+			Expect(errors.Is(
+				manager.Rebuild("NonRebuildable", 0), projection.ErrProjectionNotRebuildable,
+			)).To(BeTrue())
+			Expect(errors.Is(
+				manager.Rebuild("Unknown", 0), projection.ErrProjectionNotRegistered,
+			)).To(BeTrue())
+		})
+	})
+
+	Describe("WaitUntilCaughtUp", func() {
+		It("should return once the projection has handled the latest event height", func() {
+			eventStore := event_test.NewMockEventStore()
+			eventStore.On("GetLatestHeight").Return(primptr.Int64(10), nil)
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), eventStore)
+
+			caughtUpProjection := projection_test.NewMockProjection()
+			caughtUpProjection.On("Id").Return("CaughtUp")
+			caughtUpProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(10), nil)
+			Expect(manager.RegisterProjection(caughtUpProjection)).To(Succeed())
+
+			Expect(manager.WaitUntilCaughtUp(context.Background(), "CaughtUp")).To(Succeed())
+		})
+
+		It("should stop waiting when the context is done", func() {
+			eventStore := event_test.NewMockEventStore()
+			eventStore.On("GetLatestHeight").Return(primptr.Int64(10), nil)
+			manager := projection.NewStoreBasedManager(test.NewFakeLogger(), eventStore)
+
+			laggingProjection := projection_test.NewMockProjection()
+			laggingProjection.On("Id").Return("Lagging")
+			laggingProjection.On("GetLastHandledEventHeight").Return(primptr.Int64(5), nil)
+			Expect(manager.RegisterProjection(laggingProjection)).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(manager.WaitUntilCaughtUp(ctx, "Lagging")).To(MatchError(context.Canceled))
+		})
+	})
+})
@@ -1,6 +1,8 @@
 package projection
 
 import (
+	"fmt"
+
 	entity_event "github.com/AstraProtocol/astra-indexing/entity/event"
 )
 
@@ -26,3 +28,91 @@ type Projection interface {
 	// projection. It is also responsible to update the last handled event height.
 	HandleEvents(height int64, events []entity_event.Event) error
 }
+
+// BatchProjection is a projection which is able to handle the events of a contiguous range of
+// heights at once. The manager hands over ranges of heights to it when it lags behind the event
+// store, such that catching up costs one query and one DB transaction per range instead of per
+// height.
+type BatchProjection interface {
+	Projection
+
+	// Handle all events with height within [fromHeight, toHeight] that matches
+	// `GetEventsToListen()`, ordered by height. Heights without any matching event are still part
+	// of the range. It is responsible to update the last handled event height to `toHeight`. All
+	// changes must be done in a single DB transaction.
+	HandleEventsBatch(fromHeight int64, toHeight int64, events []entity_event.Event) error
+}
+
+// DependentProjection is a projection reading the views of other projections, or whose views are
+// combined with theirs in the same responses. It never handles a height before all the projections
+// it depends on have handled it, and it starts after them.
+type DependentProjection interface {
+	Projection
+
+	// Returns the ids of the projections it depends on. They must all be enabled.
+	GetDependencies() []string
+}
+
+// RollbackableProjection is a projection which is able to undo its writes when the chain
+// reorganizes. Projections keeping aggregated states which cannot be derived back from the
+// remaining records journal their view changes per height to undo them, see
+// rdbprojectionbase.Base.JournalViews.
+type RollbackableProjection interface {
+	Projection
+
+	// Rollback removes all the records projected from events within [fromHeight, toHeight] and
+	// resets the last handled event height to `fromHeight - 1`. All changes must be done in a
+	// single DB transaction.
+	Rollback(fromHeight int64, toHeight int64) error
+}
+
+// RebuildableProjection is a projection whose views can be reset, such that it is rebuilt by
+// replaying the events from the event store. Projections keeping states outside of their view
+// tables should not implement it.
+type RebuildableProjection interface {
+	Projection
+
+	// Reset truncates all the view tables of the projection and rewinds the last handled event
+	// height to `fromHeight - 1`, such that the events are replayed from `fromHeight`. All changes
+	// must be done in a single DB transaction. Projections unable to keep the records below
+	// `fromHeight` must reject any height other than the genesis with
+	// ErrProjectionNotRebuildableFromHeight.
+	Reset(fromHeight int64) error
+}
+
+// ResetOnRollbackProjection is a rebuildable projection opting in to be reset and rebuilt from the
+// genesis on a chain reorganization, as a last resort when it is unable to undo its writes.
+type ResetOnRollbackProjection interface {
+	RebuildableProjection
+
+	// ResetOnRollback marks the projection as reset from the genesis on a chain reorganization
+	ResetOnRollback()
+}
+
+// Rebuilder rebuilds a single projection while the others keep running
+type Rebuilder interface {
+	Rebuild(projectionId string, fromHeight int64) error
+}
+
+// RollbackOrReset undoes the writes of the projection within [fromHeight, toHeight] on a chain
+// reorganization. A projection not supporting rollback is reset to be rebuilt from the genesis
+// only when it opts in with ResetOnRollbackProjection, and it fails otherwise. Returns whether the
+// projection has been reset.
+func RollbackOrReset(projection Projection, fromHeight int64, toHeight int64) (bool, error) {
+	if rollbackableProjection, ok := projection.(RollbackableProjection); ok {
+		return false, rollbackableProjection.Rollback(fromHeight, toHeight)
+	}
+	if resetOnRollbackProjection, ok := projection.(ResetOnRollbackProjection); ok {
+		return true, resetOnRollbackProjection.Reset(0)
+	}
+	return false, fmt.Errorf("projection `%s` does not support rollback, it has to be rebuilt", projection.Id())
+}
+
+// CanRollbackOrReset returns whether RollbackOrReset is able to undo the writes of the projection
+func CanRollbackOrReset(projection Projection) bool {
+	if _, ok := projection.(RollbackableProjection); ok {
+		return true
+	}
+	_, ok := projection.(ResetOnRollbackProjection)
+	return ok
+}
@@ -43,3 +43,87 @@ func (projection *MockProjection) HandleEvents(height int64, events []entity_eve
 
 	return mockArgs.Error(0)
 }
+
+type MockRollbackableProjection struct {
+	MockProjection
+}
+
+func NewMockRollbackableProjection() *MockRollbackableProjection {
+	return &MockRollbackableProjection{}
+}
+
+func (projection *MockRollbackableProjection) Rollback(fromHeight int64, toHeight int64) error {
+	mockArgs := projection.Called(fromHeight, toHeight)
+
+	return mockArgs.Error(0)
+}
+
+type MockRebuildableProjection struct {
+	MockProjection
+}
+
+func NewMockRebuildableProjection() *MockRebuildableProjection {
+	return &MockRebuildableProjection{}
+}
+
+func (projection *MockRebuildableProjection) Reset(fromHeight int64) error {
+	mockArgs := projection.Called(fromHeight)
+
+	return mockArgs.Error(0)
+}
+
+type MockBatchProjection struct {
+	MockProjection
+}
+
+func NewMockBatchProjection() *MockBatchProjection {
+	return &MockBatchProjection{}
+}
+
+func (projection *MockBatchProjection) HandleEventsBatch(
+	fromHeight int64,
+	toHeight int64,
+	events []entity_event.Event,
+) error {
+	mockArgs := projection.Called(fromHeight, toHeight, events)
+
+	return mockArgs.Error(0)
+}
+
+type MockDependentProjection struct {
+	MockRebuildableProjection
+}
+
+func NewMockDependentProjection() *MockDependentProjection {
+	return &MockDependentProjection{}
+}
+
+func (projection *MockDependentProjection) GetDependencies() []string {
+	mockArgs := projection.Called()
+
+	return mockArgs.Get(0).([]string)
+}
+
+type MockRebuildableRollbackableProjection struct {
+	MockRebuildableProjection
+}
+
+func NewMockRebuildableRollbackableProjection() *MockRebuildableRollbackableProjection {
+	return &MockRebuildableRollbackableProjection{}
+}
+
+func (projection *MockRebuildableRollbackableProjection) Rollback(fromHeight int64, toHeight int64) error {
+	mockArgs := projection.Called(fromHeight, toHeight)
+
+	return mockArgs.Error(0)
+}
+
+type MockResetOnRollbackProjection struct {
+	MockRebuildableProjection
+}
+
+func NewMockResetOnRollbackProjection() *MockResetOnRollbackProjection {
+	return &MockResetOnRollbackProjection{}
+}
+
+func (projection *MockResetOnRollbackProjection) ResetOnRollback() {}
@@ -3,47 +3,170 @@ package cache
 import (
 	"context"
 	"encoding/json"
-	"log"
-	"os"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/eko/gocache/lib/v4/store"
 	redis_store "github.com/eko/gocache/store/redis/v4"
 	"github.com/go-redis/redis/v8"
+	"golang.org/x/sync/singleflight"
 )
 
-type AstraCache struct {
-	astraCache *redis_store.RedisStore
+var ErrNotFound = errors.New("not found")
+
+// Channel of the Redis backend publishing the keys of the invalidated values
+const INVALIDATION_CHANNEL = "astra_cache_invalidations"
+
+// Cache stores JSON encoded values by key until their expiration. The values can be set with tags,
+// e.g. `proposal_42`, to be evicted before their expiration when the tagged data changes.
+type Cache interface {
+	Get(key string, valueOutput interface{}) error
+	Set(key string, value interface{}, expireAt time.Duration, tags ...string) error
+	// Load gets the value of the key, or loads and sets it on a miss. Concurrent misses of the same key
+	// wait for a single load.
+	Load(
+		key string,
+		valueOutput interface{},
+		expireAt time.Duration,
+		load func() (interface{}, error),
+		tags ...string,
+	) error
+	// Invalidate evicts the values set with any of the tags
+	Invalidate(tags ...string) error
 }
 
-func NewCache() *AstraCache {
-	redisURL, ok := os.LookupEnv("REDIS_URL")
+// rawStore is implemented by the backends, storing the encoded values
+type rawStore interface {
+	getRaw(key string) ([]byte, error)
+	setRaw(key string, value []byte, expireAt time.Duration, tags []string) error
+	// invalidateRaw deletes the values set with any of the tags, and returns their keys
+	invalidateRaw(tags []string) ([]string, error)
+	deleteRaw(keys ...string) error
+}
 
-	if !ok {
-		log.Fatalln("Missing REDIS_URL string")
-	}
+// AstraCache is the Redis backend, shared by all the instances of the service. The invalidations are
+// published on INVALIDATION_CHANNEL, for the instances keeping the values locally.
+type AstraCache struct {
+	astraCache *redis_store.RedisStore
+	client     *redis.Client
+	loader     singleflight.Group
+}
 
+func NewRedisCache(redisURL string) (*AstraCache, error) {
 	opt, err := redis.ParseURL(redisURL)
 	if err != nil {
-		log.Fatalln("Error parsing REDIS_URL string")
+		return nil, fmt.Errorf("error parsing Redis URL: %v", err)
 	}
 
-	redisStore := redis_store.NewRedis(redis.NewClient(opt))
-	return &AstraCache{astraCache: redisStore}
+	return newRedisCache(redis.NewClient(opt)), nil
 }
 
-func (ac *AstraCache) Set(key string, value interface{}, expireAt time.Duration) error {
-	tmpValue, err := json.Marshal(value)
-	if err != nil {
-		return err
+func newRedisCache(client *redis.Client) *AstraCache {
+	return &AstraCache{
+		astraCache: redis_store.NewRedis(client),
+		client:     client,
 	}
-	return ac.astraCache.Set(context.Background(), key, tmpValue, store.WithExpiration(expireAt))
+}
+
+func (ac *AstraCache) Set(key string, value interface{}, expireAt time.Duration, tags ...string) error {
+	return set(ac, key, value, expireAt, tags)
 }
 
 func (ac *AstraCache) Get(key string, valueOutput interface{}) error {
+	return get(ac, key, valueOutput)
+}
+
+func (ac *AstraCache) Load(
+	key string,
+	valueOutput interface{},
+	expireAt time.Duration,
+	load func() (interface{}, error),
+	tags ...string,
+) error {
+	return loadOnce(ac, &ac.loader, key, valueOutput, expireAt, load, tags)
+}
+
+func (ac *AstraCache) Invalidate(tags ...string) error {
+	_, err := ac.invalidateRaw(tags)
+	return err
+}
+
+func (ac *AstraCache) getRaw(key string) ([]byte, error) {
 	tmpData, err := ac.astraCache.Get(context.Background(), key)
 	if err != nil {
-		return err
+		if errors.Is(err, &store.NotFound{}) {
+			return nil, ErrNotFound
+		}
+		return nil, err
 	}
-	return json.Unmarshal([]byte(tmpData.(string)), &valueOutput)
+	return []byte(tmpData.(string)), nil
+}
+
+func (ac *AstraCache) setRaw(key string, value []byte, expireAt time.Duration, tags []string) error {
+	options := []store.Option{store.WithExpiration(expireAt)}
+	if len(tags) > 0 {
+		options = append(options, store.WithTags(tags))
+	}
+	return ac.astraCache.Set(context.Background(), key, value, options...)
+}
+
+func (ac *AstraCache) invalidateRaw(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	ctx := context.Background()
+
+	tagKeys := make([]string, 0, len(tags))
+	membersCmds := make([]*redis.StringSliceCmd, 0, len(tags))
+	pipeline := ac.client.Pipeline()
+	for _, tag := range tags {
+		tagKey := fmt.Sprintf(redis_store.RedisTagPattern, tag)
+		tagKeys = append(tagKeys, tagKey)
+		membersCmds = append(membersCmds, pipeline.SMembers(ctx, tagKey))
+	}
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("error getting keys of the invalidated tags: %v", err)
+	}
+
+	var keys []string
+	for _, membersCmd := range membersCmds {
+		keys = append(keys, membersCmd.Val()...)
+	}
+	if err := ac.client.Del(ctx, append(keys, tagKeys...)...).Err(); err != nil {
+		return nil, fmt.Errorf("error deleting invalidated keys: %v", err)
+	}
+
+	if len(keys) > 0 {
+		payload, err := json.Marshal(keys)
+		if err != nil {
+			return nil, err
+		}
+		if err := ac.client.Publish(ctx, INVALIDATION_CHANNEL, payload).Err(); err != nil {
+			return nil, fmt.Errorf("error publishing invalidated keys: %v", err)
+		}
+	}
+	return keys, nil
+}
+
+func (ac *AstraCache) deleteRaw(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return ac.client.Del(context.Background(), keys...).Err()
+}
+
+// subscribeInvalidations calls onInvalidate with the keys invalidated by any instance, until the
+// process exits. The subscription reconnects on Redis errors.
+func (ac *AstraCache) subscribeInvalidations(onInvalidate func(keys []string)) {
+	pubSub := ac.client.Subscribe(context.Background(), INVALIDATION_CHANNEL)
+	go func() {
+		for message := range pubSub.Channel() {
+			var keys []string
+			if err := json.Unmarshal([]byte(message.Payload), &keys); err != nil {
+				continue
+			}
+			onInvalidate(keys)
+		}
+	}()
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/AstraProtocol/astra-indexing/projection/block/view"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -45,3 +46,13 @@ func TestCacheBlock(t *testing.T) {
 
 	assert.Equal(t, output, block)
 }
+
+func TestIsShared(t *testing.T) {
+	local := NewLocalLRUCache("TestIsSharedLocal", 10)
+	// Not connected until used
+	shared := newRedisCache(redis.NewClient(&redis.Options{}))
+
+	assert.Equal(t, false, IsShared(local))
+	assert.Equal(t, true, IsShared(shared))
+	assert.Equal(t, true, IsShared(NewTwoTierCache(local, shared, time.Minute)))
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	BACKEND_REDIS    = "redis"
+	BACKEND_LOCAL    = "local"
+	BACKEND_TWO_TIER = "two_tier"
+)
+
+const (
+	DEFAULT_LOCAL_CAPACITY   uint64 = 100000
+	DEFAULT_LOCAL_EXPIRATION        = 5 * time.Second
+)
+
+const defaultCacheName = "AstraCache"
+
+type Config struct {
+	// One of the backends, empty for Redis when the Redis URL is set and local otherwise
+	Backend  string
+	RedisURL string
+	// Maximum number of keys of the local cache
+	LocalCapacity uint64
+	// Expiration of the values kept by the local tier of the two-tier backend
+	LocalExpiration time.Duration
+}
+
+var (
+	defaultCacheMutex sync.Mutex
+	defaultCache      Cache
+)
+
+// Configure builds the cache returned by NewCache. Without a backend nor a Redis URL, Redis is used
+// when REDIS_URL is set. It fails when Redis is configured but invalid, instead of falling back to a
+// cache not shared by the instances.
+func Configure(config Config) error {
+	if config.Backend == "" && config.RedisURL == "" {
+		config.RedisURL = os.Getenv("REDIS_URL")
+	}
+	cache, err := newCacheFromConfig(config)
+	if err != nil {
+		return err
+	}
+
+	defaultCacheMutex.Lock()
+	defer defaultCacheMutex.Unlock()
+	defaultCache = cache
+	return nil
+}
+
+// NewCache returns the cache shared by the process. Without Configure, it is the local backend.
+func NewCache() Cache {
+	defaultCacheMutex.Lock()
+	defer defaultCacheMutex.Unlock()
+
+	if defaultCache == nil {
+		defaultCache = NewLocalLRUCache(defaultCacheName, DEFAULT_LOCAL_CAPACITY)
+	}
+	return defaultCache
+}
+
+// IsShared returns whether the cache is shared by the instances of the service, i.e. whether they all
+// see the invalidations of the projections indexing in another process
+func IsShared(cache Cache) bool {
+	switch cache.(type) {
+	case *AstraCache, *AstraTwoTierCache:
+		return true
+	}
+	return false
+}
+
+func newCacheFromConfig(config Config) (Cache, error) {
+	if config.LocalCapacity == 0 {
+		config.LocalCapacity = DEFAULT_LOCAL_CAPACITY
+	}
+	if config.LocalExpiration <= 0 {
+		config.LocalExpiration = DEFAULT_LOCAL_EXPIRATION
+	}
+
+	backend := config.Backend
+	if backend == "" {
+		if config.RedisURL != "" {
+			backend = BACKEND_REDIS
+		} else {
+			backend = BACKEND_LOCAL
+		}
+	}
+
+	switch backend {
+	case BACKEND_LOCAL:
+		return NewLocalLRUCache(defaultCacheName, config.LocalCapacity), nil
+	case BACKEND_REDIS, BACKEND_TWO_TIER:
+		if config.RedisURL == "" {
+			return nil, fmt.Errorf("missing Redis URL of the %s cache backend", backend)
+		}
+		redisCache, err := NewRedisCache(config.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		if backend == BACKEND_REDIS {
+			return redisCache, nil
+		}
+		return NewTwoTierCache(
+			NewLocalLRUCache(defaultCacheName, config.LocalCapacity), redisCache, config.LocalExpiration,
+		), nil
+	}
+	return nil, fmt.Errorf("unknown cache backend: %s", backend)
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvalidateEvictsTaggedValues(t *testing.T) {
+	cache := NewLocalLRUCache("TestInvalidate", 10)
+
+	assert.Equal(t, nil, cache.Set("proposal_42", "proposal", time.Hour, "proposal_42"))
+	assert.Equal(t, nil, cache.Set("proposals", "list", time.Hour, "proposals"))
+	assert.Equal(t, nil, cache.Set("votes_42", "votes", time.Hour, "proposal_42", "votes"))
+	assert.Equal(t, nil, cache.Set("untagged", "value", time.Hour))
+
+	assert.Equal(t, nil, cache.Invalidate("proposal_42"))
+
+	output := ""
+	assert.Equal(t, ErrNotFound, cache.Get("proposal_42", &output))
+	assert.Equal(t, ErrNotFound, cache.Get("votes_42", &output))
+	assert.Equal(t, nil, cache.Get("proposals", &output))
+	assert.Equal(t, nil, cache.Get("untagged", &output))
+
+	// The other tags of the evicted values are dropped with them
+	assert.Equal(t, nil, cache.Set("votes_42", "votes", time.Hour))
+	assert.Equal(t, nil, cache.Invalidate("votes"))
+	assert.Equal(t, nil, cache.Get("votes_42", &output))
+}
+
+func TestInvalidateUsesLatestTags(t *testing.T) {
+	cache := NewLocalLRUCache("TestInvalidateRetag", 10)
+
+	assert.Equal(t, nil, cache.Set("a", "value", time.Hour, "old"))
+	assert.Equal(t, nil, cache.Set("a", "value", time.Hour, "new"))
+
+	output := ""
+	assert.Equal(t, nil, cache.Invalidate("old"))
+	assert.Equal(t, nil, cache.Get("a", &output))
+	assert.Equal(t, nil, cache.Invalidate("new"))
+	assert.Equal(t, ErrNotFound, cache.Get("a", &output))
+}
+
+func TestEvictionRemovesTags(t *testing.T) {
+	cache := NewLocalLRUCache("TestInvalidateEvicted", 1)
+
+	assert.Equal(t, nil, cache.Set("a", "value", time.Hour, "tag"))
+	// Evicts a beyond the capacity
+	assert.Equal(t, nil, cache.Set("b", "value", time.Hour))
+	time.Sleep(10 * time.Millisecond)
+
+	cache.tags.mutex.Lock()
+	assert.Equal(t, 0, len(cache.tags.entries))
+	assert.Equal(t, 0, len(cache.tags.keysByTag))
+	cache.tags.mutex.Unlock()
+}
+
+func TestTwoTierCacheInvalidatesBothTiers(t *testing.T) {
+	local := NewLocalLRUCache("TestTwoTierInvalidateLocal", 10)
+	shared := NewLocalLRUCache("TestTwoTierInvalidateShared", 10)
+	cache := newTwoTierCache(local, shared, time.Minute)
+
+	assert.Equal(t, nil, shared.Set("read", "shared value", time.Hour, "validators"))
+	assert.Equal(t, nil, cache.Set("set", "value", time.Hour, "validators"))
+
+	output := ""
+	// Kept locally without its tags
+	assert.Equal(t, nil, cache.Get("read", &output))
+
+	assert.Equal(t, nil, cache.Invalidate("validators"))
+	for _, key := range []string{"read", "set"} {
+		assert.Equal(t, ErrNotFound, local.Get(key, &output))
+		assert.Equal(t, ErrNotFound, shared.Get(key, &output))
+	}
+}
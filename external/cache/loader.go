@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"encoding/json"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+func get(store rawStore, key string, valueOutput interface{}) error {
+	tmpData, err := store.getRaw(key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(tmpData, valueOutput)
+}
+
+func set(store rawStore, key string, value interface{}, expireAt time.Duration, tags []string) error {
+	tmpValue, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return store.setRaw(key, tmpValue, expireAt, tags)
+}
+
+// loadOnce loads the value of the key missing from the store once for the concurrent callers, which
+// all decode the same encoded value
+func loadOnce(
+	store rawStore,
+	loader *singleflight.Group,
+	key string,
+	valueOutput interface{},
+	expireAt time.Duration,
+	load func() (interface{}, error),
+	tags []string,
+) error {
+	if err := get(store, key, valueOutput); err == nil {
+		return nil
+	}
+
+	tmpData, err, _ := loader.Do(key, func() (interface{}, error) {
+		// Loaded by a previous flight in the meantime
+		if tmpData, getErr := store.getRaw(key); getErr == nil {
+			return tmpData, nil
+		}
+
+		value, loadErr := load()
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		tmpValue, marshalErr := json.Marshal(value)
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+		// The loaded value is returned even when the cache is unavailable
+		_ = store.setRaw(key, tmpValue, expireAt, tags)
+		return tmpValue, nil
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(tmpData.([]byte), valueOutput)
+}
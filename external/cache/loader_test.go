@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadOnceForConcurrentMisses(t *testing.T) {
+	cache := NewLocalLRUCache("TestLoad", 10)
+
+	var loads int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	outputs := make([][]string, 10)
+	for i := range outputs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := cache.Load("validators", &outputs[i], time.Minute, func() (interface{}, error) {
+				atomic.AddInt32(&loads, 1)
+				<-release
+				return []string{"validator1", "validator2"}, nil
+			})
+			assert.Equal(t, nil, err)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), loads)
+	for _, output := range outputs {
+		assert.Equal(t, []string{"validator1", "validator2"}, output)
+	}
+
+	var cached []string
+	assert.Equal(t, nil, cache.Get("validators", &cached))
+	assert.Equal(t, []string{"validator1", "validator2"}, cached)
+}
+
+func TestLoadDoesNotCacheErrors(t *testing.T) {
+	cache := NewLocalLRUCache("TestLoadError", 10)
+
+	output := ""
+	err := cache.Load("key", &output, time.Minute, func() (interface{}, error) {
+		return nil, errors.New("backend unavailable")
+	})
+	assert.EqualError(t, err, "backend unavailable")
+	assert.Equal(t, ErrNotFound, cache.Get("key", &output))
+
+	err = cache.Load("key", &output, time.Minute, func() (interface{}, error) {
+		return "value", nil
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "value", output)
+}
+
+func TestLocalCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewLocalLRUCache("TestLRU", 2)
+
+	assert.Equal(t, nil, cache.Set("a", 1, time.Minute))
+	assert.Equal(t, nil, cache.Set("b", 2, time.Minute))
+	output := 0
+	assert.Equal(t, nil, cache.Get("a", &output))
+	assert.Equal(t, nil, cache.Set("c", 3, time.Minute))
+
+	assert.Equal(t, ErrNotFound, cache.Get("b", &output))
+	assert.Equal(t, nil, cache.Get("a", &output))
+	assert.Equal(t, 1, output)
+	assert.Equal(t, nil, cache.Get("c", &output))
+	assert.Equal(t, 3, output)
+}
+
+func TestLocalCacheExpires(t *testing.T) {
+	cache := NewLocalLRUCache("TestExpiration", 2)
+
+	assert.Equal(t, nil, cache.Set("a", 1, 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+
+	output := 0
+	assert.Equal(t, ErrNotFound, cache.Get("a", &output))
+}
+
+func TestNewCacheFromConfig(t *testing.T) {
+	cache, err := newCacheFromConfig(Config{})
+	assert.Equal(t, nil, err)
+	assert.IsType(t, &AstraLocalCache{}, cache)
+
+	cache, err = newCacheFromConfig(Config{Backend: BACKEND_TWO_TIER, RedisURL: "redis://localhost:6379/0"})
+	assert.Equal(t, nil, err)
+	assert.IsType(t, &AstraTwoTierCache{}, cache)
+
+	_, err = newCacheFromConfig(Config{Backend: BACKEND_REDIS})
+	assert.EqualError(t, err, "missing Redis URL of the redis cache backend")
+
+	_, err = newCacheFromConfig(Config{Backend: "memcached"})
+	assert.EqualError(t, err, "unknown cache backend: memcached")
+}
+
+func TestConfigureFailsOnInvalidRedisURL(t *testing.T) {
+	t.Setenv("REDIS_URL", "invalid://localhost")
+
+	err := Configure(Config{})
+	assert.NotEqual(t, nil, err)
+
+	err = Configure(Config{Backend: BACKEND_TWO_TIER, RedisURL: "invalid://localhost"})
+	assert.NotEqual(t, nil, err)
+}
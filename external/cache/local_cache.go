@@ -1,21 +1,37 @@
 package cache
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 	"strconv"
 	"time"
 
 	"github.com/AstraProtocol/astra-indexing/infrastructure/metric/prometheus"
 	"github.com/jellydator/ttlcache/v3"
+	"golang.org/x/sync/singleflight"
 )
 
+// AstraLocalCache is the in-process backend, evicting the least recently used keys beyond its capacity
 type AstraLocalCache struct {
 	astraLocalCache *ttlcache.Cache[string, []byte]
+	tags            *tagIndex
+	loader          singleflight.Group
 }
 
 func NewLocalCache(appName string) *AstraLocalCache {
-	cache := ttlcache.New[string, []byte]()
+	return NewLocalLRUCache(appName, 0)
+}
+
+// NewLocalLRUCache creates a local cache of at most capacity keys, 0 for no limit
+func NewLocalLRUCache(appName string, capacity uint64) *AstraLocalCache {
+	options := make([]ttlcache.Option[string, []byte], 0)
+	if capacity > 0 {
+		options = append(options, ttlcache.WithCapacity[string, []byte](capacity))
+	}
+	cache := ttlcache.New[string, []byte](options...)
+	tags := newTagIndex()
+	cache.OnEviction(func(_ context.Context, _ ttlcache.EvictionReason, item *ttlcache.Item[string, []byte]) {
+		tags.remove(item)
+	})
 	// force expired item deletion
 	go func() {
 		for {
@@ -33,22 +49,66 @@ func NewLocalCache(appName string) *AstraLocalCache {
 			time.Sleep(2 * time.Second)
 		}
 	}()
-	return &AstraLocalCache{astraLocalCache: cache}
+	return &AstraLocalCache{
+		astraLocalCache: cache,
+		tags:            tags,
+	}
 }
 
-func (alc *AstraLocalCache) Set(key string, value interface{}, expireAt time.Duration) error {
-	tmpValue, err := json.Marshal(value)
-	if err != nil {
-		return err
-	}
-	alc.astraLocalCache.Set(key, tmpValue, expireAt)
-	return nil
+func (alc *AstraLocalCache) Set(key string, value interface{}, expireAt time.Duration, tags ...string) error {
+	return set(alc, key, value, expireAt, tags)
 }
 
 func (alc *AstraLocalCache) Get(key string, valueOutput interface{}) error {
+	return get(alc, key, valueOutput)
+}
+
+func (alc *AstraLocalCache) Load(
+	key string,
+	valueOutput interface{},
+	expireAt time.Duration,
+	load func() (interface{}, error),
+	tags ...string,
+) error {
+	return loadOnce(alc, &alc.loader, key, valueOutput, expireAt, load, tags)
+}
+
+func (alc *AstraLocalCache) Invalidate(tags ...string) error {
+	_, err := alc.invalidateRaw(tags)
+	return err
+}
+
+func (alc *AstraLocalCache) getRaw(key string) ([]byte, error) {
 	tmpData := alc.astraLocalCache.Get(key)
-	if tmpData != nil {
-		return json.Unmarshal(tmpData.Value(), &valueOutput)
+	if tmpData == nil || tmpData.IsExpired() {
+		return nil, ErrNotFound
 	}
-	return fmt.Errorf("not found")
+	return tmpData.Value(), nil
+}
+
+func (alc *AstraLocalCache) setRaw(key string, value []byte, expireAt time.Duration, tags []string) error {
+	alc.tags.mutex.Lock()
+	defer alc.tags.mutex.Unlock()
+
+	item := alc.astraLocalCache.Set(key, value, expireAt)
+	alc.tags.set(item, tags)
+	return nil
+}
+
+func (alc *AstraLocalCache) invalidateRaw(tags []string) ([]string, error) {
+	alc.tags.mutex.Lock()
+	defer alc.tags.mutex.Unlock()
+
+	keys := alc.tags.take(tags)
+	for _, key := range keys {
+		alc.astraLocalCache.Delete(key)
+	}
+	return keys, nil
+}
+
+func (alc *AstraLocalCache) deleteRaw(keys ...string) error {
+	for _, key := range keys {
+		alc.astraLocalCache.Delete(key)
+	}
+	return nil
 }
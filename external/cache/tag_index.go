@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/jellydator/ttlcache/v3"
+)
+
+// tagIndex maps the tags to the keys of the local cache set with them. The evicted items are removed
+// from the index by the eviction handler of the cache, which runs asynchronously: an item is only
+// removed when the key has not been set again with a new item since.
+type tagIndex struct {
+	mutex     sync.Mutex
+	keysByTag map[string]map[string]struct{}
+	entries   map[string]tagEntry
+}
+
+type tagEntry struct {
+	item *ttlcache.Item[string, []byte]
+	tags []string
+}
+
+func newTagIndex() *tagIndex {
+	return &tagIndex{
+		keysByTag: make(map[string]map[string]struct{}),
+		entries:   make(map[string]tagEntry),
+	}
+}
+
+// set replaces the tags of the key of the item. Not concurrently safe.
+func (index *tagIndex) set(item *ttlcache.Item[string, []byte], tags []string) {
+	key := item.Key()
+	index.unlink(key)
+	if len(tags) == 0 {
+		return
+	}
+
+	index.entries[key] = tagEntry{item, tags}
+	for _, tag := range tags {
+		keys, exist := index.keysByTag[tag]
+		if !exist {
+			keys = make(map[string]struct{})
+			index.keysByTag[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+// take removes the tags from the index, and returns their keys. Not concurrently safe.
+func (index *tagIndex) take(tags []string) []string {
+	keys := make([]string, 0)
+	for _, tag := range tags {
+		for key := range index.keysByTag[tag] {
+			keys = append(keys, key)
+			index.unlink(key)
+		}
+	}
+	return keys
+}
+
+// remove removes the evicted item from the index
+func (index *tagIndex) remove(item *ttlcache.Item[string, []byte]) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	if entry, exist := index.entries[item.Key()]; exist && entry.item == item {
+		index.unlink(item.Key())
+	}
+}
+
+func (index *tagIndex) unlink(key string) {
+	entry, exist := index.entries[key]
+	if !exist {
+		return
+	}
+	delete(index.entries, key)
+	for _, tag := range entry.tags {
+		delete(index.keysByTag[tag], key)
+		if len(index.keysByTag[tag]) == 0 {
+			delete(index.keysByTag, tag)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// AstraTwoTierCache reads through a local cache over a shared cache, usually Redis. The values are
+// kept locally for at most the local expiration, such that the instances of the service see the
+// changes of the shared cache after that delay. The invalidated values are evicted from the local
+// caches of all the instances right away.
+type AstraTwoTierCache struct {
+	local           rawStore
+	shared          rawStore
+	localExpiration time.Duration
+	loader          singleflight.Group
+}
+
+func NewTwoTierCache(local *AstraLocalCache, shared *AstraCache, localExpiration time.Duration) *AstraTwoTierCache {
+	cache := newTwoTierCache(local, shared, localExpiration)
+	shared.subscribeInvalidations(func(keys []string) {
+		_ = local.deleteRaw(keys...)
+	})
+	return cache
+}
+
+func newTwoTierCache(local rawStore, shared rawStore, localExpiration time.Duration) *AstraTwoTierCache {
+	return &AstraTwoTierCache{
+		local:           local,
+		shared:          shared,
+		localExpiration: localExpiration,
+	}
+}
+
+func (ttc *AstraTwoTierCache) Set(key string, value interface{}, expireAt time.Duration, tags ...string) error {
+	return set(ttc, key, value, expireAt, tags)
+}
+
+func (ttc *AstraTwoTierCache) Get(key string, valueOutput interface{}) error {
+	return get(ttc, key, valueOutput)
+}
+
+func (ttc *AstraTwoTierCache) Load(
+	key string,
+	valueOutput interface{},
+	expireAt time.Duration,
+	load func() (interface{}, error),
+	tags ...string,
+) error {
+	return loadOnce(ttc, &ttc.loader, key, valueOutput, expireAt, load, tags)
+}
+
+func (ttc *AstraTwoTierCache) Invalidate(tags ...string) error {
+	_, err := ttc.invalidateRaw(tags)
+	return err
+}
+
+func (ttc *AstraTwoTierCache) getRaw(key string) ([]byte, error) {
+	if tmpData, err := ttc.local.getRaw(key); err == nil {
+		return tmpData, nil
+	}
+
+	tmpData, err := ttc.shared.getRaw(key)
+	if err != nil {
+		return nil, err
+	}
+	// The tags are unknown, the other instances evict the key on invalidations
+	_ = ttc.local.setRaw(key, tmpData, ttc.localExpiration, nil)
+	return tmpData, nil
+}
+
+func (ttc *AstraTwoTierCache) setRaw(key string, value []byte, expireAt time.Duration, tags []string) error {
+	localExpiration := ttc.localExpiration
+	if expireAt < localExpiration {
+		localExpiration = expireAt
+	}
+	_ = ttc.local.setRaw(key, value, localExpiration, tags)
+	return ttc.shared.setRaw(key, value, expireAt, tags)
+}
+
+func (ttc *AstraTwoTierCache) invalidateRaw(tags []string) ([]string, error) {
+	localKeys, _ := ttc.local.invalidateRaw(tags)
+	keys, err := ttc.shared.invalidateRaw(tags)
+	if err != nil {
+		return localKeys, err
+	}
+	// The keys read through from the shared cache are not tagged locally
+	_ = ttc.local.deleteRaw(keys...)
+	return keys, nil
+}
+
+func (ttc *AstraTwoTierCache) deleteRaw(keys ...string) error {
+	_ = ttc.local.deleteRaw(keys...)
+	return ttc.shared.deleteRaw(keys...)
+}
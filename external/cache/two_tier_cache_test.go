@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwoTierCacheReadsThroughLocal(t *testing.T) {
+	local := NewLocalLRUCache("TestTwoTierLocal", 10)
+	shared := NewLocalLRUCache("TestTwoTierShared", 10)
+	cache := newTwoTierCache(local, shared, time.Minute)
+
+	assert.Equal(t, nil, shared.Set("a", "shared value", time.Hour))
+
+	output := ""
+	assert.Equal(t, nil, cache.Get("a", &output))
+	assert.Equal(t, "shared value", output)
+	// Kept locally after the first read
+	assert.Equal(t, nil, local.Get("a", &output))
+	assert.Equal(t, "shared value", output)
+}
+
+func TestTwoTierCacheSetsBothTiers(t *testing.T) {
+	local := NewLocalLRUCache("TestTwoTierSetLocal", 10)
+	shared := NewLocalLRUCache("TestTwoTierSetShared", 10)
+	cache := newTwoTierCache(local, shared, 10*time.Millisecond)
+
+	assert.Equal(t, nil, cache.Set("a", "value", time.Hour))
+
+	output := ""
+	assert.Equal(t, nil, local.Get("a", &output))
+	assert.Equal(t, nil, shared.Get("a", &output))
+
+	// The local value expires first
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, ErrNotFound, local.Get("a", &output))
+	assert.Equal(t, nil, cache.Get("a", &output))
+	assert.Equal(t, "value", output)
+}
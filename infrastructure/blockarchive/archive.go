@@ -0,0 +1,86 @@
+package blockarchive
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// A block archive is a directory of gzip compressed JSON lines files, each of them covers a chunk of
+// consecutive heights. Every line is a Record holding the raw RPC responses of a height, so that the
+// archive is parsed exactly the same way as the responses from a live node.
+//
+// | File                                    | Content                   |
+// | --------------------------------------- | ------------------------- |
+// | genesis.json.gz                         | raw /genesis response     |
+// | blocks-000000000001-000000001000.jsonl.gz | Record of heights 1..1000 |
+
+const GENESIS_FILENAME = "genesis.json.gz"
+const DEFAULT_CHUNK_SIZE = int64(1000)
+
+const chunkFilenameFormat = "blocks-%012d-%012d.jsonl.gz"
+
+var chunkFilenameRegex = regexp.MustCompile(`^blocks-(\d{12})-(\d{12})\.jsonl\.gz$`)
+
+// Record is the archived raw responses of a block height
+type Record struct {
+	Height       int64                      `json:"height"`
+	Block        json.RawMessage            `json:"block"`
+	BlockResults json.RawMessage            `json:"block_results"`
+	Txs          map[string]json.RawMessage `json:"txs"`
+}
+
+type chunkFile struct {
+	fromHeight int64
+	toHeight   int64
+	path       string
+}
+
+func chunkFilename(fromHeight int64, toHeight int64) string {
+	return fmt.Sprintf(chunkFilenameFormat, fromHeight, toHeight)
+}
+
+// listChunkFiles returns the chunk files of the archive directory sorted by height
+func listChunkFiles(dir string) ([]chunkFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading block archive directory: %v", err)
+	}
+
+	chunkFiles := make([]chunkFile, 0)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := chunkFilenameRegex.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+		fromHeight, _ := strconv.ParseInt(matches[1], 10, 64)
+		toHeight, _ := strconv.ParseInt(matches[2], 10, 64)
+		if fromHeight > toHeight {
+			return nil, fmt.Errorf("invalid block archive chunk file %s", entry.Name())
+		}
+		chunkFiles = append(chunkFiles, chunkFile{
+			fromHeight: fromHeight,
+			toHeight:   toHeight,
+			path:       filepath.Join(dir, entry.Name()),
+		})
+	}
+	sort.Slice(chunkFiles, func(i, j int) bool {
+		return chunkFiles[i].fromHeight < chunkFiles[j].fromHeight
+	})
+	for i := 1; i < len(chunkFiles); i++ {
+		if chunkFiles[i].fromHeight <= chunkFiles[i-1].toHeight {
+			return nil, fmt.Errorf(
+				"block archive chunk files %s and %s overlap", chunkFiles[i-1].path, chunkFiles[i].path,
+			)
+		}
+	}
+
+	return chunkFiles, nil
+}
@@ -0,0 +1,13 @@
+package blockarchive_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestBlockArchive(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Block Archive Suite")
+}
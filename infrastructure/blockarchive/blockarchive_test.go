@@ -0,0 +1,148 @@
+package blockarchive_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/AstraProtocol/astra-indexing/external/logger/test"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/blockarchive"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/cosmosapp"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/tendermint"
+	infrastructure_tendermint_test "github.com/AstraProtocol/astra-indexing/infrastructure/tendermint/test"
+	usecase_parser_test "github.com/AstraProtocol/astra-indexing/usecase/parser/test"
+)
+
+const TX_MSG_DELEGATE_HEIGHT = int64(466543)
+const TX_MSG_DELEGATE_TX_HASH = "005BC5071A655A6219F7ECFE677E050866A33A174BC63A372A3B6208F4DE1F6C"
+
+// fakeNode serves the raw responses of TX_MSG_DELEGATE fixtures at every height
+type fakeNode struct{}
+
+func (node *fakeNode) RawGenesis() ([]byte, error) {
+	return []byte(infrastructure_tendermint_test.GENESIS_MIXED_NUMBER_AND_STRING_JSON), nil
+}
+
+func (node *fakeNode) RawBlock(_ int64) ([]byte, error) {
+	return []byte(usecase_parser_test.TX_MSG_DELEGATE_BLOCK_RESP), nil
+}
+
+func (node *fakeNode) RawBlockResults(_ int64) ([]byte, error) {
+	return []byte(usecase_parser_test.TX_MSG_DELEGATE_BLOCK_RESULTS_RESP), nil
+}
+
+func (node *fakeNode) RawTx(txHash string) ([]byte, error) {
+	if txHash != TX_MSG_DELEGATE_TX_HASH {
+		return nil, errors.New("tx not found")
+	}
+	return []byte(usecase_parser_test.TX_MSG_DELEGATE_TXS_RESP), nil
+}
+
+func dump(dir string, chunkSize int64, fromHeight int64, toHeight int64) {
+	writer, err := blockarchive.NewWriter(dir, chunkSize)
+	Expect(err).To(BeNil())
+	dumper := blockarchive.NewDumper(test.NewFakeLogger(), &fakeNode{}, &fakeNode{}, writer, 3)
+
+	Expect(dumper.Dump(context.Background(), fromHeight, toHeight)).To(Succeed())
+	Expect(writer.Close()).To(Succeed())
+}
+
+var _ = Describe("BlockArchive", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "blockarchive")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should read the dumped responses the same way as a live node", func() {
+		dump(dir, blockarchive.DEFAULT_CHUNK_SIZE, TX_MSG_DELEGATE_HEIGHT, TX_MSG_DELEGATE_HEIGHT)
+
+		client, err := blockarchive.NewClient(dir, false)
+		Expect(err).To(BeNil())
+
+		latestHeight, err := client.LatestBlockHeight()
+		Expect(err).To(BeNil())
+		Expect(latestHeight).To(Equal(TX_MSG_DELEGATE_HEIGHT))
+
+		expectedBlock, expectedRawBlock, _ := tendermint.ParseBlockResp(
+			strings.NewReader(usecase_parser_test.TX_MSG_DELEGATE_BLOCK_RESP),
+		)
+		block, rawBlock, err := client.Block(TX_MSG_DELEGATE_HEIGHT)
+		Expect(err).To(BeNil())
+		Expect(block).To(Equal(expectedBlock))
+		Expect(rawBlock).To(Equal(expectedRawBlock))
+
+		expectedBlockResults, _ := tendermint.ParseBlockResultsResp(
+			strings.NewReader(usecase_parser_test.TX_MSG_DELEGATE_BLOCK_RESULTS_RESP),
+		)
+		blockResults, err := client.BlockResults(TX_MSG_DELEGATE_HEIGHT)
+		Expect(err).To(BeNil())
+		Expect(blockResults).To(Equal(expectedBlockResults))
+
+		expectedTx, _ := cosmosapp.ParseTxsResp(strings.NewReader(usecase_parser_test.TX_MSG_DELEGATE_TXS_RESP))
+		tx, err := client.Tx(TX_MSG_DELEGATE_TX_HASH)
+		Expect(err).To(BeNil())
+		Expect(tx).To(Equal(expectedTx))
+
+		_, _, err = client.Block(TX_MSG_DELEGATE_HEIGHT + 1)
+		Expect(errors.Is(err, blockarchive.ErrNotArchived)).To(BeTrue())
+	})
+
+	It("should include the genesis when dumping from the beginning of the chain", func() {
+		dump(dir, blockarchive.DEFAULT_CHUNK_SIZE, 0, 1)
+
+		client, err := blockarchive.NewClient(dir, false)
+		Expect(err).To(BeNil())
+
+		expectedGenesis, _ := tendermint.ParseGenesisResp(
+			strings.NewReader(infrastructure_tendermint_test.GENESIS_MIXED_NUMBER_AND_STRING_JSON), false,
+		)
+		genesis, err := client.Genesis()
+		Expect(err).To(BeNil())
+		Expect(genesis).To(Equal(expectedGenesis))
+	})
+
+	It("should split the heights into chunk files aligned to the chunk size", func() {
+		dump(dir, 2, 1, 5)
+
+		for _, filename := range []string{
+			"blocks-000000000001-000000000002.jsonl.gz",
+			"blocks-000000000003-000000000004.jsonl.gz",
+			"blocks-000000000005-000000000005.jsonl.gz",
+		} {
+			Expect(filepath.Join(dir, filename)).To(BeARegularFile())
+		}
+
+		client, err := blockarchive.NewClient(dir, false)
+		Expect(err).To(BeNil())
+		for height := int64(1); height <= 5; height += 1 {
+			_, _, err := client.Block(height)
+			Expect(err).To(BeNil(), fmt.Sprintf("height %d", height))
+		}
+		latestHeight, _ := client.LatestBlockHeight()
+		Expect(latestHeight).To(Equal(int64(5)))
+	})
+
+	It("should reject records of non-consecutive heights", func() {
+		writer, err := blockarchive.NewWriter(dir, blockarchive.DEFAULT_CHUNK_SIZE)
+		Expect(err).To(BeNil())
+
+		Expect(writer.Write(&blockarchive.Record{Height: 1})).To(Succeed())
+		Expect(writer.Write(&blockarchive.Record{Height: 3})).To(
+			MatchError("error writing block archive: expected height 2, got 3"),
+		)
+		Expect(writer.Close()).To(Succeed())
+	})
+})
@@ -0,0 +1,233 @@
+package blockarchive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	cosmosapp_interface "github.com/AstraProtocol/astra-indexing/appinterface/cosmosapp"
+	tendermint_interface "github.com/AstraProtocol/astra-indexing/appinterface/tendermint"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/cosmosapp"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/tendermint"
+	usecase_model "github.com/AstraProtocol/astra-indexing/usecase/model"
+	"github.com/AstraProtocol/astra-indexing/usecase/model/genesis"
+)
+
+var _ tendermint_interface.Client = &Client{}
+var _ cosmosapp_interface.TxClient = &Client{}
+
+var ErrNotArchived = errors.New("not found in block archive")
+
+// DEFAULT_MAX_LOADED_CHUNKS keeps the chunk being synced and the previous one in memory, so that a
+// sync window crossing the chunk boundary does not reload the chunks. Chunks with pending tx lookups
+// are kept on top of it.
+const DEFAULT_MAX_LOADED_CHUNKS = 2
+
+// Client reads the blocks, block results and transactions from a block archive directory
+type Client struct {
+	dir                  string
+	strictGenesisParsing bool
+	chunkFiles           []chunkFile
+
+	maxLoadedChunks int
+	// Most recently used first
+	loadedChunks []*loadedChunk
+	mutex        sync.Mutex
+}
+
+type loadedChunk struct {
+	file    chunkFile
+	records map[int64]*Record
+	// Tx hash to the height of the record containing it
+	txHeights map[string]int64
+	// Txs of the blocks queried which are not looked up yet, the chunk is not evicted until they are
+	pendingTxs map[string]bool
+}
+
+func NewClient(dir string, strictGenesisParsing bool) (*Client, error) {
+	chunkFiles, err := listChunkFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		dir:                  dir,
+		strictGenesisParsing: strictGenesisParsing,
+		chunkFiles:           chunkFiles,
+
+		maxLoadedChunks: DEFAULT_MAX_LOADED_CHUNKS,
+		loadedChunks:    make([]*loadedChunk, 0, DEFAULT_MAX_LOADED_CHUNKS),
+	}, nil
+}
+
+func (client *Client) Genesis() (*genesis.Genesis, error) {
+	file, err := os.Open(filepath.Join(client.dir, GENESIS_FILENAME))
+	if err != nil {
+		return nil, fmt.Errorf("error opening block archive genesis file: %v", err)
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing block archive genesis file: %v", err)
+	}
+	defer gzipReader.Close()
+
+	return tendermint.ParseGenesisResp(gzipReader, client.strictGenesisParsing)
+}
+
+// Block returns the archived block. Its txs are then kept loaded until they are looked up with Tx.
+func (client *Client) Block(height int64) (*usecase_model.Block, *usecase_model.RawBlock, error) {
+	record, err := client.record(height, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tendermint.ParseBlockResp(bytes.NewReader(record.Block))
+}
+
+func (client *Client) BlockResults(height int64) (*usecase_model.BlockResults, error) {
+	record, err := client.record(height, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return tendermint.ParseBlockResultsResp(bytes.NewReader(record.BlockResults))
+}
+
+// LatestBlockHeight returns the last archived height
+func (client *Client) LatestBlockHeight() (int64, error) {
+	if len(client.chunkFiles) == 0 {
+		return int64(0), fmt.Errorf("error getting latest block height: block archive %s is empty", client.dir)
+	}
+
+	return client.chunkFiles[len(client.chunkFiles)-1].toHeight, nil
+}
+
+// Tx returns the archived transaction. The chunk containing it has to be loaded by querying its
+// block beforehand, which is always the case when syncing blocks.
+func (client *Client) Tx(txHash string) (*usecase_model.Tx, error) {
+	client.mutex.Lock()
+	var rawTx json.RawMessage
+	for _, chunk := range client.loadedChunks {
+		if height, ok := chunk.txHeights[txHash]; ok {
+			rawTx = chunk.records[height].Txs[txHash]
+			delete(chunk.pendingTxs, txHash)
+			break
+		}
+	}
+	client.mutex.Unlock()
+
+	if rawTx == nil {
+		return nil, fmt.Errorf("error getting tx %s: %w", txHash, ErrNotArchived)
+	}
+	tx, err := cosmosapp.ParseTxsResp(bytes.NewReader(rawTx))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Tx(%s): %v", txHash, err)
+	}
+	return tx, nil
+}
+
+// record returns the archived record at the height. With `withPendingTxs`, its txs are marked as
+// pending lookups, such that the chunk is kept loaded until they are looked up.
+func (client *Client) record(height int64, withPendingTxs bool) (*Record, error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	chunk, err := client.loadChunk(height)
+	if err != nil {
+		return nil, err
+	}
+	record, ok := chunk.records[height]
+	if !ok {
+		return nil, fmt.Errorf("error getting block archive record at height %d: %w", height, ErrNotArchived)
+	}
+	if withPendingTxs {
+		for txHash := range record.Txs {
+			chunk.pendingTxs[txHash] = true
+		}
+	}
+
+	return record, nil
+}
+
+// loadChunk returns the loaded chunk covering the height, it loads the chunk file when it is not
+// loaded yet
+func (client *Client) loadChunk(height int64) (*loadedChunk, error) {
+	for i, chunk := range client.loadedChunks {
+		if chunk.file.fromHeight <= height && height <= chunk.file.toHeight {
+			// Move to the front as the most recently used
+			copy(client.loadedChunks[1:i+1], client.loadedChunks[:i])
+			client.loadedChunks[0] = chunk
+			return chunk, nil
+		}
+	}
+
+	i := sort.Search(len(client.chunkFiles), func(i int) bool {
+		return client.chunkFiles[i].toHeight >= height
+	})
+	if i == len(client.chunkFiles) || client.chunkFiles[i].fromHeight > height {
+		return nil, fmt.Errorf("error getting block archive record at height %d: %w", height, ErrNotArchived)
+	}
+
+	chunk, err := readChunkFile(client.chunkFiles[i])
+	if err != nil {
+		return nil, err
+	}
+	client.evictChunks()
+	client.loadedChunks = append([]*loadedChunk{chunk}, client.loadedChunks...)
+
+	return chunk, nil
+}
+
+// evictChunks makes room for loading a chunk by evicting the least recently used chunks without
+// pending tx lookups, the caller must hold the mutex
+func (client *Client) evictChunks() {
+	for i := len(client.loadedChunks) - 1; i >= 0 && len(client.loadedChunks) >= client.maxLoadedChunks; i -= 1 {
+		if len(client.loadedChunks[i].pendingTxs) > 0 {
+			continue
+		}
+		client.loadedChunks = append(client.loadedChunks[:i], client.loadedChunks[i+1:]...)
+	}
+}
+
+func readChunkFile(file chunkFile) (*loadedChunk, error) {
+	osFile, err := os.Open(file.path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening block archive chunk file: %v", err)
+	}
+	defer osFile.Close()
+
+	gzipReader, err := gzip.NewReader(osFile)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing block archive chunk file %s: %v", file.path, err)
+	}
+	defer gzipReader.Close()
+
+	chunk := &loadedChunk{
+		file:       file,
+		records:    make(map[int64]*Record),
+		txHeights:  make(map[string]int64),
+		pendingTxs: make(map[string]bool),
+	}
+	decoder := json.NewDecoder(bufio.NewReader(gzipReader))
+	for decoder.More() {
+		var record Record
+		if err := decoder.Decode(&record); err != nil {
+			return nil, fmt.Errorf("error decoding block archive chunk file %s: %v", file.path, err)
+		}
+		chunk.records[record.Height] = &record
+		for txHash := range record.Txs {
+			chunk.txHeights[txHash] = record.Height
+		}
+	}
+
+	return chunk, nil
+}
@@ -0,0 +1,148 @@
+package blockarchive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/tendermint"
+	"github.com/AstraProtocol/astra-indexing/usecase/parser"
+)
+
+// RawBlockFetcher fetches the raw Tendermint RPC responses from a live node
+type RawBlockFetcher interface {
+	RawGenesis() ([]byte, error)
+	RawBlock(height int64) ([]byte, error)
+	RawBlockResults(height int64) ([]byte, error)
+}
+
+// RawTxFetcher fetches the raw Cosmos tx responses from a live node
+type RawTxFetcher interface {
+	RawTx(txHash string) ([]byte, error)
+}
+
+// Dumper writes the blocks of a live node into a block archive
+type Dumper struct {
+	logger applogger.Logger
+
+	blockFetcher RawBlockFetcher
+	txFetcher    RawTxFetcher
+	writer       *Writer
+
+	windowSize int
+}
+
+func NewDumper(
+	logger applogger.Logger,
+	blockFetcher RawBlockFetcher,
+	txFetcher RawTxFetcher,
+	writer *Writer,
+	windowSize int,
+) *Dumper {
+	if windowSize <= 0 {
+		windowSize = 1
+	}
+
+	return &Dumper{
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "BlockArchiveDumper",
+		}),
+
+		blockFetcher: blockFetcher,
+		txFetcher:    txFetcher,
+		writer:       writer,
+
+		windowSize: windowSize,
+	}
+}
+
+// Dump writes the heights within [fromHeight, toHeight] into the archive, the genesis is included
+// when starting from the beginning of the chain. Heights are fetched concurrently by windows and
+// it stops at the end of a window when the context is done.
+func (dumper *Dumper) Dump(ctx context.Context, fromHeight int64, toHeight int64) error {
+	if fromHeight <= 1 {
+		rawGenesis, err := dumper.blockFetcher.RawGenesis()
+		if err != nil {
+			return fmt.Errorf("error fetching genesis: %v", err)
+		}
+		if err = dumper.writer.WriteGenesis(rawGenesis); err != nil {
+			return err
+		}
+		fromHeight = 1
+	}
+
+	for beginHeight := fromHeight; beginHeight <= toHeight; beginHeight += int64(dumper.windowSize) {
+		if ctx.Err() != nil {
+			dumper.logger.Infof("stop dumping blocks before height %d", beginHeight)
+			return nil
+		}
+
+		endHeight := beginHeight + int64(dumper.windowSize) - 1
+		if endHeight > toHeight {
+			endHeight = toHeight
+		}
+
+		records := make([]*Record, endHeight-beginHeight+1)
+		fetchersErrGroup, _ := errgroup.WithContext(ctx)
+		for height := beginHeight; height <= endHeight; height += 1 {
+			height := height
+			fetchersErrGroup.Go(func() error {
+				record, err := dumper.fetchRecord(height)
+				if err != nil {
+					return err
+				}
+				records[height-beginHeight] = record
+				return nil
+			})
+		}
+		if err := fetchersErrGroup.Wait(); err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			if err := dumper.writer.Write(record); err != nil {
+				return err
+			}
+		}
+		dumper.logger.Infof("dumped blocks from %d to %d", beginHeight, endHeight)
+	}
+
+	return nil
+}
+
+func (dumper *Dumper) fetchRecord(height int64) (*Record, error) {
+	rawBlock, err := dumper.blockFetcher.RawBlock(height)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching block at height %d: %v", height, err)
+	}
+	block, _, err := tendermint.ParseBlockResp(bytes.NewReader(rawBlock))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing block at height %d: %v", height, err)
+	}
+
+	rawBlockResults, err := dumper.blockFetcher.RawBlockResults(height)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching block_results at height %d: %v", height, err)
+	}
+
+	rawTxs := make(map[string]json.RawMessage, len(block.Txs))
+	for _, txHex := range block.Txs {
+		txHash := parser.TxHash(txHex)
+		rawTx, err := dumper.txFetcher.RawTx(txHash)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching tx %s at height %d: %v", txHash, height, err)
+		}
+		rawTxs[txHash] = rawTx
+	}
+
+	return &Record{
+		Height:       height,
+		Block:        rawBlock,
+		BlockResults: rawBlockResults,
+		Txs:          rawTxs,
+	}, nil
+}
@@ -0,0 +1,145 @@
+package blockarchive
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Writer writes the records of consecutive heights into the chunk files of an archive directory.
+// Chunks are aligned to the chunk size so that the archives of separated dumps can be merged.
+type Writer struct {
+	dir       string
+	chunkSize int64
+
+	current *chunkWriter
+}
+
+type chunkWriter struct {
+	file       *os.File
+	gzipWriter *gzip.Writer
+	encoder    *json.Encoder
+
+	fromHeight int64
+	lastHeight int64
+}
+
+func NewWriter(dir string, chunkSize int64) (*Writer, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("invalid block archive chunk size: %d", chunkSize)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating block archive directory: %v", err)
+	}
+
+	return &Writer{
+		dir:       dir,
+		chunkSize: chunkSize,
+	}, nil
+}
+
+// WriteGenesis writes the raw /genesis response
+func (writer *Writer) WriteGenesis(rawGenesis []byte) error {
+	path := filepath.Join(writer.dir, GENESIS_FILENAME)
+	file, err := os.Create(path + ".tmp")
+	if err != nil {
+		return fmt.Errorf("error creating block archive genesis file: %v", err)
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	if _, err = gzipWriter.Write(rawGenesis); err != nil {
+		return fmt.Errorf("error writing block archive genesis file: %v", err)
+	}
+	if err = gzipWriter.Close(); err != nil {
+		return fmt.Errorf("error writing block archive genesis file: %v", err)
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("error closing block archive genesis file: %v", err)
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// Write appends the record to the archive. Records have to be written in consecutive heights.
+func (writer *Writer) Write(record *Record) error {
+	if writer.current != nil && record.Height != writer.current.lastHeight+1 {
+		return fmt.Errorf(
+			"error writing block archive: expected height %d, got %d", writer.current.lastHeight+1, record.Height,
+		)
+	}
+
+	if writer.current == nil {
+		if err := writer.openChunk(record.Height); err != nil {
+			return err
+		}
+	}
+	if err := writer.current.encoder.Encode(record); err != nil {
+		return fmt.Errorf("error writing block archive record at height %d: %v", record.Height, err)
+	}
+	writer.current.lastHeight = record.Height
+
+	if record.Height%writer.chunkSize == 0 {
+		return writer.finishChunk()
+	}
+	return nil
+}
+
+// Close finishes the chunk being written, which then covers the heights written so far
+func (writer *Writer) Close() error {
+	if writer.current == nil {
+		return nil
+	}
+	return writer.finishChunk()
+}
+
+func (writer *Writer) openChunk(fromHeight int64) error {
+	file, err := os.Create(writer.tmpChunkPath(fromHeight))
+	if err != nil {
+		return fmt.Errorf("error creating block archive chunk file: %v", err)
+	}
+	gzipWriter := gzip.NewWriter(file)
+	writer.current = &chunkWriter{
+		file:       file,
+		gzipWriter: gzipWriter,
+		encoder:    json.NewEncoder(gzipWriter),
+
+		fromHeight: fromHeight,
+		lastHeight: fromHeight - 1,
+	}
+
+	return nil
+}
+
+// finishChunk closes the chunk being written and renames it after the heights it covers. Only
+// finished chunks are visible to the archive readers.
+func (writer *Writer) finishChunk() error {
+	current := writer.current
+	writer.current = nil
+
+	if err := current.gzipWriter.Close(); err != nil {
+		_ = current.file.Close()
+		return fmt.Errorf("error writing block archive chunk file: %v", err)
+	}
+	if err := current.file.Close(); err != nil {
+		return fmt.Errorf("error closing block archive chunk file: %v", err)
+	}
+
+	tmpPath := writer.tmpChunkPath(current.fromHeight)
+	if current.lastHeight < current.fromHeight {
+		return os.Remove(tmpPath)
+	}
+	if err := os.Rename(
+		tmpPath, filepath.Join(writer.dir, chunkFilename(current.fromHeight, current.lastHeight)),
+	); err != nil {
+		return fmt.Errorf("error renaming block archive chunk file: %v", err)
+	}
+
+	return nil
+}
+
+func (writer *Writer) tmpChunkPath(fromHeight int64) string {
+	return filepath.Join(writer.dir, fmt.Sprintf("blocks-%012d.jsonl.gz.tmp", fromHeight))
+}
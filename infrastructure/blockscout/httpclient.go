@@ -71,7 +71,7 @@ type HTTPClient struct {
 	httpClient *retryablehttp.Client
 	url        string
 	workerUrl  string
-	httpCache  *cache.AstraCache
+	httpCache  cache.Cache
 }
 
 var (
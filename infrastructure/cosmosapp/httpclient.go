@@ -43,7 +43,7 @@ type HTTPClient struct {
 	httpClient   *retryablehttp.Client
 	rpcUrl       string
 	bondingDenom string
-	httpCache    *cache.AstraCache
+	httpCache    cache.Cache
 }
 
 // DefaultRetryPolicy provides a default callback for Client.CheckRetry, which
@@ -1590,6 +1590,27 @@ func (client *HTTPClient) Tx(hash string) (*model.Tx, error) {
 	return tx, nil
 }
 
+// RawTx gets the raw tx response, which is parsed by ParseTxsResp
+func (client *HTTPClient) RawTx(hash string) ([]byte, error) {
+	rawRespBody, err := client.request(
+		fmt.Sprintf(
+			"%s/%s",
+			client.getUrl("tx", "txs"),
+			hash,
+		), "",
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rawRespBody.Close()
+
+	rawResp, err := io.ReadAll(rawRespBody)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Tx(%s) response: %v", hash, err)
+	}
+	return rawResp, nil
+}
+
 func (client *HTTPClient) TotalFeeBurn() (cosmosapp_interface.TotalFeeBurn, error) {
 	cacheKey := "CosmosTotalFeeBurn"
 	var totalFeeBurnTmp cosmosapp_interface.TotalFeeBurn
@@ -1,80 +1,71 @@
 package chain
 
 import (
+	"context"
 	"fmt"
-	"github.com/AstraProtocol/astra-indexing/infrastructure/metric/prometheus"
-	"sync"
 	"time"
 
 	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
-	"github.com/AstraProtocol/astra-indexing/external/primptr"
 	"github.com/cenkalti/backoff/v4"
 
 	"github.com/AstraProtocol/astra-indexing/appinterface/tendermint"
 )
 
+var _ BlockSource = &BlockHeightTracker{}
+
 const MAX_RETRY_TIME_ALWAYS_RETRY = 0
 const DEFAULT_POLLING_INTERVAL = 3 * time.Second
 const DEFAULT_MAX_RETRY_INTERVAL = 15 * time.Minute
 const DEFAULT_MAX_RETRY_TIME = MAX_RETRY_TIME_ALWAYS_RETRY
 
+// BlockHeightTracker is the block source polling the chain latest block height
 type BlockHeightTracker struct {
+	*blockFeed
+
 	logger applogger.Logger
 	client tendermint.Client
 
 	pollingInterval  time.Duration
 	maxRetryInterval time.Duration
 	maxRetryTime     time.Duration
+}
+
+// NewBlockHeightTracker creates a tracker polling the latest block height until the context is done
+func NewBlockHeightTracker(ctx context.Context, logger applogger.Logger, client tendermint.Client) *BlockHeightTracker {
+	logger = logger.WithFields(applogger.LogFields{
+		"module": "BlockHeightTracker",
+	})
+	tracker := newBlockHeightTracker(logger, client, newBlockFeed(logger))
 
-	subscriptions []chan<- int64
+	go tracker.Run(ctx)
 
-	latestBlockHeight *int64
-	rwMutex           sync.RWMutex
+	return tracker
 }
 
-func NewBlockHeightTracker(logger applogger.Logger, client tendermint.Client) *BlockHeightTracker {
-	tracker := &BlockHeightTracker{
-		logger: logger.WithFields(applogger.LogFields{
-			"module": "BlockHeightTracker",
-		}),
+func newBlockHeightTracker(logger applogger.Logger, client tendermint.Client, feed *blockFeed) *BlockHeightTracker {
+	return &BlockHeightTracker{
+		blockFeed: feed,
+
+		logger: logger,
 		client: client,
 
 		pollingInterval:  DEFAULT_POLLING_INTERVAL,
 		maxRetryInterval: DEFAULT_MAX_RETRY_INTERVAL,
 		maxRetryTime:     DEFAULT_MAX_RETRY_TIME,
-
-		subscriptions: make([]chan<- int64, 0),
-
-		latestBlockHeight: primptr.Int64Nil(),
 	}
-
-	go tracker.Run()
-
-	return tracker
 }
 
-func (tracker *BlockHeightTracker) Run() {
-	for {
+func (tracker *BlockHeightTracker) Run(ctx context.Context) {
+	for ctx.Err() == nil {
 		operation := func() error {
-			height, err := tracker.client.LatestBlockHeight()
-			if err != nil {
-				return fmt.Errorf("error getting chain latest block height: %v", err)
+			if err := tracker.poll(); err != nil {
+				return err
 			}
 
-			for _, subscription := range tracker.subscriptions {
-				select {
-				case subscription <- height:
-				default:
-					tracker.logger.Info("block subscription channel is blocked, maybe busy?")
-				}
+			select {
+			case <-ctx.Done():
+			case <-time.After(tracker.pollingInterval):
 			}
-
-			tracker.rwMutex.Lock()
-			tracker.latestBlockHeight = &height
-			tracker.rwMutex.Unlock()
-			prometheus.RecordProjectionLatestHeight("LatestBlockHeight", height)
-			tracker.logger.Infof("updated chain latest block height: %d", height)
-			<-time.After(tracker.pollingInterval)
 			return nil
 		}
 		notifyFn := func(opErr error, backoffDuration time.Duration) {
@@ -83,19 +74,33 @@ func (tracker *BlockHeightTracker) Run() {
 		neverStopExponentialBackoff := backoff.NewExponentialBackOff()
 		neverStopExponentialBackoff.MaxElapsedTime = tracker.maxRetryTime
 		neverStopExponentialBackoff.MaxInterval = tracker.maxRetryInterval
-		if err := backoff.RetryNotify(operation, neverStopExponentialBackoff, notifyFn); err != nil {
-			tracker.logger.Errorf("stopping retry after too many errors: %s: %v", err)
+		if err := backoff.RetryNotify(
+			operation, backoff.WithContext(neverStopExponentialBackoff, ctx), notifyFn,
+		); err != nil && ctx.Err() == nil {
+			tracker.logger.Errorf("stopping retry after too many errors: %v", err)
 		}
 	}
+	tracker.logger.Info("block height tracker stopped")
 }
 
-func (tracker *BlockHeightTracker) Subscribe(ch chan<- int64) {
-	tracker.subscriptions = append(tracker.subscriptions, ch)
-}
+// poll publishes the chain latest block height. The latest block is fetched only when there are
+// block subscribers and the height has moved.
+func (tracker *BlockHeightTracker) poll() error {
+	height, err := tracker.client.LatestBlockHeight()
+	if err != nil {
+		return fmt.Errorf("error getting chain latest block height: %v", err)
+	}
 
-func (tracker *BlockHeightTracker) GetLatestBlockHeight() *int64 {
-	tracker.rwMutex.RLock()
-	defer tracker.rwMutex.RUnlock()
+	lastHeight := tracker.GetLatestBlockHeight()
+	if tracker.hasBlockSubscriptions() && (lastHeight == nil || *lastHeight != height) {
+		block, _, err := tracker.client.Block(height)
+		if err != nil {
+			return fmt.Errorf("error getting chain latest block: %v", err)
+		}
+		tracker.publishBlock(block)
+		return nil
+	}
 
-	return tracker.latestBlockHeight
+	tracker.publishHeight(height)
+	return nil
 }
@@ -0,0 +1,104 @@
+package chain
+
+import (
+	"sync"
+
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/external/primptr"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/metric/prometheus"
+	usecase_model "github.com/AstraProtocol/astra-indexing/usecase/model"
+)
+
+// BlockSource feeds the new blocks of the chain to the subscribers
+type BlockSource interface {
+	// Subscribe registers a channel receiving the chain latest block heights
+	Subscribe(ch chan<- int64)
+	// SubscribeBlocks registers a channel receiving the chain latest blocks. Heights may be skipped
+	// when the chain moves faster than the source.
+	SubscribeBlocks(ch chan<- *usecase_model.Block)
+	// GetLatestBlockHeight returns the chain latest block height, nil when it is not known yet
+	GetLatestBlockHeight() *int64
+}
+
+// blockFeed keeps the subscriptions and the latest block height shared by the block sources
+type blockFeed struct {
+	logger applogger.Logger
+
+	subscriptions      []chan<- int64
+	blockSubscriptions []chan<- *usecase_model.Block
+
+	latestBlockHeight *int64
+	rwMutex           sync.RWMutex
+}
+
+func newBlockFeed(logger applogger.Logger) *blockFeed {
+	return &blockFeed{
+		logger: logger,
+
+		subscriptions:      make([]chan<- int64, 0),
+		blockSubscriptions: make([]chan<- *usecase_model.Block, 0),
+
+		latestBlockHeight: primptr.Int64Nil(),
+	}
+}
+
+func (feed *blockFeed) Subscribe(ch chan<- int64) {
+	feed.rwMutex.Lock()
+	defer feed.rwMutex.Unlock()
+
+	feed.subscriptions = append(feed.subscriptions, ch)
+}
+
+func (feed *blockFeed) SubscribeBlocks(ch chan<- *usecase_model.Block) {
+	feed.rwMutex.Lock()
+	defer feed.rwMutex.Unlock()
+
+	feed.blockSubscriptions = append(feed.blockSubscriptions, ch)
+}
+
+func (feed *blockFeed) GetLatestBlockHeight() *int64 {
+	feed.rwMutex.RLock()
+	defer feed.rwMutex.RUnlock()
+
+	return feed.latestBlockHeight
+}
+
+func (feed *blockFeed) hasBlockSubscriptions() bool {
+	feed.rwMutex.RLock()
+	defer feed.rwMutex.RUnlock()
+
+	return len(feed.blockSubscriptions) > 0
+}
+
+// publishHeight updates the latest block height and notifies the subscribers
+func (feed *blockFeed) publishHeight(height int64) {
+	feed.rwMutex.Lock()
+	defer feed.rwMutex.Unlock()
+
+	for _, subscription := range feed.subscriptions {
+		select {
+		case subscription <- height:
+		default:
+			feed.logger.Info("block subscription channel is blocked, maybe busy?")
+		}
+	}
+
+	feed.latestBlockHeight = &height
+	prometheus.RecordProjectionLatestHeight("LatestBlockHeight", height)
+	feed.logger.Infof("updated chain latest block height: %d", height)
+}
+
+// publishBlock notifies the block subscribers, followed by the height subscribers
+func (feed *blockFeed) publishBlock(block *usecase_model.Block) {
+	feed.rwMutex.RLock()
+	for _, subscription := range feed.blockSubscriptions {
+		select {
+		case subscription <- block:
+		default:
+			feed.logger.Info("block data subscription channel is blocked, maybe busy?")
+		}
+	}
+	feed.rwMutex.RUnlock()
+
+	feed.publishHeight(block.Height)
+}
@@ -0,0 +1,108 @@
+package chain
+
+import (
+	"context"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/tendermint"
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	usecase_model "github.com/AstraProtocol/astra-indexing/usecase/model"
+)
+
+var _ BlockSource = &WebSocketBlockSource{}
+
+const DEFAULT_MAX_RECONNECT_INTERVAL = 1 * time.Minute
+
+// WebSocketBlockSource is the block source subscribing to the new blocks pushed by the chain. It falls
+// back to polling whenever the subscription is not established.
+type WebSocketBlockSource struct {
+	*blockFeed
+
+	logger     applogger.Logger
+	subscriber tendermint.BlockSubscriber
+	poller     *BlockHeightTracker
+
+	maxReconnectInterval time.Duration
+}
+
+// NewWebSocketBlockSource creates a block source subscribing to the new blocks until the context is
+// done
+func NewWebSocketBlockSource(
+	ctx context.Context,
+	logger applogger.Logger,
+	client tendermint.Client,
+	subscriber tendermint.BlockSubscriber,
+) *WebSocketBlockSource {
+	logger = logger.WithFields(applogger.LogFields{
+		"module": "WebSocketBlockSource",
+	})
+	feed := newBlockFeed(logger)
+	source := &WebSocketBlockSource{
+		blockFeed: feed,
+
+		logger:     logger,
+		subscriber: subscriber,
+		poller:     newBlockHeightTracker(logger, client, feed),
+
+		maxReconnectInterval: DEFAULT_MAX_RECONNECT_INTERVAL,
+	}
+
+	go source.Run(ctx)
+
+	return source
+}
+
+func (source *WebSocketBlockSource) Run(ctx context.Context) {
+	reconnectBackoff := backoff.NewExponentialBackOff()
+	reconnectBackoff.MaxElapsedTime = MAX_RETRY_TIME_ALWAYS_RETRY
+	reconnectBackoff.MaxInterval = source.maxReconnectInterval
+
+	// Poll until the first block is pushed, so that the latest height is known from the start
+	pollingCtx, stopPolling := context.WithCancel(ctx)
+	go source.poller.Run(pollingCtx)
+	for ctx.Err() == nil {
+		isSubscribed := false
+		blockCh := make(chan *usecase_model.Block)
+		subscriptionErrCh := make(chan error, 1)
+		go func() {
+			subscriptionErrCh <- source.subscriber.SubscribeNewBlocks(ctx, blockCh)
+		}()
+
+		var subscriptionErr error
+	receiving:
+		for {
+			select {
+			case block := <-blockCh:
+				if !isSubscribed {
+					isSubscribed = true
+					stopPolling()
+					reconnectBackoff.Reset()
+					source.logger.Info("subscribed to new blocks, polling is stopped")
+				}
+				source.publishBlock(block)
+			case subscriptionErr = <-subscriptionErrCh:
+				break receiving
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		if isSubscribed {
+			pollingCtx, stopPolling = context.WithCancel(ctx)
+			go source.poller.Run(pollingCtx)
+		}
+		reconnectDuration := reconnectBackoff.NextBackOff()
+		source.logger.Errorf(
+			"new blocks subscription dropped, polling until reconnected in %s: %v", reconnectDuration, subscriptionErr,
+		)
+		select {
+		case <-ctx.Done():
+		case <-time.After(reconnectDuration):
+		}
+	}
+	stopPolling()
+	source.logger.Info("websocket block source stopped")
+}
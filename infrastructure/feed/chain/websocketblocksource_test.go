@@ -0,0 +1,108 @@
+package chain_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/AstraProtocol/astra-indexing/external/logger/test"
+	chainfeed "github.com/AstraProtocol/astra-indexing/infrastructure/feed/chain"
+	usecase_model "github.com/AstraProtocol/astra-indexing/usecase/model"
+	"github.com/AstraProtocol/astra-indexing/usecase/model/genesis"
+)
+
+type fakeTendermintClient struct {
+	latestBlockHeight int64
+}
+
+func (client *fakeTendermintClient) Genesis() (*genesis.Genesis, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (client *fakeTendermintClient) Block(height int64) (*usecase_model.Block, *usecase_model.RawBlock, error) {
+	return &usecase_model.Block{Height: height}, nil, nil
+}
+
+func (client *fakeTendermintClient) BlockResults(_ int64) (*usecase_model.BlockResults, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (client *fakeTendermintClient) LatestBlockHeight() (int64, error) {
+	return client.latestBlockHeight, nil
+}
+
+// subscriptionSession pushes the blocks, then drops with the error or stays until the context is done
+type subscriptionSession struct {
+	blocks []*usecase_model.Block
+	err    error
+}
+
+type fakeBlockSubscriber struct {
+	sessions chan subscriptionSession
+}
+
+func (subscriber *fakeBlockSubscriber) SubscribeNewBlocks(
+	ctx context.Context,
+	blockCh chan<- *usecase_model.Block,
+) error {
+	var session subscriptionSession
+	select {
+	case <-ctx.Done():
+		return nil
+	case session = <-subscriber.sessions:
+	}
+
+	for _, block := range session.blocks {
+		select {
+		case <-ctx.Done():
+			return nil
+		case blockCh <- block:
+		}
+	}
+	if session.err != nil {
+		return session.err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+var _ = Describe("WebSocketBlockSource", func() {
+	It("should switch between polling and subscription", func() {
+		subscriber := &fakeBlockSubscriber{
+			sessions: make(chan subscriptionSession),
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		source := chainfeed.NewWebSocketBlockSource(
+			ctx, test.NewFakeLogger(), &fakeTendermintClient{latestBlockHeight: 5}, subscriber,
+		)
+		blockCh := make(chan *usecase_model.Block, 1)
+		source.SubscribeBlocks(blockCh)
+		heightCh := make(chan int64, 1)
+		source.Subscribe(heightCh)
+
+		By("polling before the subscription is established")
+		Eventually(source.GetLatestBlockHeight).Should(Equal(primptrInt64(5)))
+		Eventually(blockCh).Should(Receive(Equal(&usecase_model.Block{Height: 5})))
+		Eventually(heightCh).Should(Receive(Equal(int64(5))))
+
+		By("pushing the subscribed blocks")
+		Eventually(subscriber.sessions).Should(BeSent(subscriptionSession{
+			blocks: []*usecase_model.Block{{Height: 10}},
+			err:    errors.New("connection reset by peer"),
+		}))
+		Eventually(blockCh).Should(Receive(Equal(&usecase_model.Block{Height: 10})))
+		Eventually(heightCh).Should(Receive(Equal(int64(10))))
+
+		By("falling back to polling when the subscription is dropped")
+		Eventually(source.GetLatestBlockHeight, 2*time.Second).Should(Equal(primptrInt64(5)))
+	})
+})
+
+func primptrInt64(value int64) *int64 {
+	return &value
+}
@@ -0,0 +1,13 @@
+package apiauth_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestAPIAuth(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "API Auth Suite")
+}
@@ -0,0 +1,137 @@
+package apiauth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+)
+
+const DEFAULT_API_KEYS_TABLE = "api_keys"
+
+// Scope granting the administrative endpoints, e.g. rebuilding projections
+const SCOPE_ADMIN = "admin"
+
+// Time a looked up key is cached before it is looked up again, such that disabled keys are rejected
+// soon without a lookup on every request
+const DEFAULT_KEY_CACHE_TTL = time.Minute
+
+// Maximum number of cached keys, including the unknown ones, the least recently used ones are evicted
+// beyond
+const maxCachedKeys = 10000
+
+// API keys table should have the following schema
+// | Field       | Data Type | Constraint  |
+// | ----------- | --------- | ----------- |
+// | id          | BIGSERIAL | PRIMARY KEY |
+// | key_hash    | VARCHAR   | UNIQUE      |
+// | name        | VARCHAR   | NOT NULL    |
+// | scopes      | VARCHAR   | NOT NULL    |
+// | daily_quota | INT64     | NOT NULL    |
+// | enabled     | BOOLEAN   | NOT NULL    |
+// | created_at  | INT64     | NOT NULL    |
+
+// APIKey is a client of the HTTP API
+type APIKey struct {
+	Id   int64
+	Name string
+	// Space separated in the table
+	Scopes []string
+	// Maximum number of requests per UTC day, 0 is unlimited
+	DailyQuota int64
+}
+
+func (key *APIKey) HasScope(scope string) bool {
+	for _, keyScope := range key.Scopes {
+		if keyScope == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HashKey returns the hash of the key stored in the table, keys themselves are never stored
+func HashKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// KeyStore looks up the API keys
+type KeyStore interface {
+	// FindByKey returns the enabled API key, nil when the key is unknown or disabled
+	FindByKey(key string) (*APIKey, error)
+}
+
+// RDbKeyStore looks up the API keys in the table by their hash, and caches them for a while in a
+// bounded LRU cache. Unknown keys are cached as nil.
+type RDbKeyStore struct {
+	rdbHandle *rdb.Handle
+	table     string
+
+	cached *ttlcache.Cache[string, *APIKey]
+}
+
+func NewRDbKeyStore(rdbHandle *rdb.Handle) *RDbKeyStore {
+	return &RDbKeyStore{
+		rdbHandle: rdbHandle,
+		table:     DEFAULT_API_KEYS_TABLE,
+
+		cached: ttlcache.New[string, *APIKey](
+			ttlcache.WithTTL[string, *APIKey](DEFAULT_KEY_CACHE_TTL),
+			ttlcache.WithCapacity[string, *APIKey](maxCachedKeys),
+			// The keys are looked up again once their TTL is over, however often they are used
+			ttlcache.WithDisableTouchOnHit[string, *APIKey](),
+		),
+	}
+}
+
+func (store *RDbKeyStore) FindByKey(key string) (*APIKey, error) {
+	keyHash := HashKey(key)
+
+	if cached := store.cached.Get(keyHash); cached != nil {
+		return cached.Value(), nil
+	}
+
+	apiKey, err := store.findByKeyHash(keyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	store.cached.Set(keyHash, apiKey, ttlcache.DefaultTTL)
+	return apiKey, nil
+}
+
+func (store *RDbKeyStore) findByKeyHash(keyHash string) (*APIKey, error) {
+	sql, args, err := store.rdbHandle.StmtBuilder.Select(
+		"id", "name", "scopes", "daily_quota",
+	).From(
+		store.table,
+	).Where(
+		"key_hash = ? AND enabled", keyHash,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building API key selection SQL: %v", err)
+	}
+
+	var (
+		apiKey APIKey
+		scopes string
+	)
+	if err = store.rdbHandle.QueryRow(sql, args...).Scan(
+		&apiKey.Id, &apiKey.Name, &scopes, &apiKey.DailyQuota,
+	); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error scanning API key row: %v", err)
+	}
+	apiKey.Scopes = strings.Fields(scopes)
+
+	return &apiKey, nil
+}
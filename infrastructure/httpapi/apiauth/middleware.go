@@ -0,0 +1,254 @@
+package apiauth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi"
+)
+
+const DEFAULT_API_KEY_HEADER = "X-API-Key"
+const DEFAULT_ROUTE_GROUP_NAME = "default"
+
+// Limit of the requests with an unknown API key of every IP, guarding the API keys table against
+// keys being guessed
+var DEFAULT_FAILED_KEY_LOOKUP_LIMIT = Limit{Rate: 0.1, Burst: 10}
+
+// User value of the request holding its *APIKey
+const apiKeyUserValue = "apiauth.apiKey"
+
+// RouteGroup shares the limits among the routes of its path prefixes
+type RouteGroup struct {
+	Name string
+	// Prefixes of the request paths, including the route prefix of the server
+	PathPrefixes []string
+	// Limit of every API key
+	PerKey Limit
+	// Limit of every IP of the requests without API key
+	PerIP Limit
+}
+
+type Config struct {
+	// Rejects the requests without API key, except the public paths
+	RequireAPIKey bool
+	// Header carrying the API key, empty defaults to DEFAULT_API_KEY_HEADER
+	Header string
+	// Header carrying the client IP set by a trusted reverse proxy, e.g. X-Forwarded-For. Empty uses
+	// the remote address of the connection.
+	ClientIPHeader string
+	// Number of trusted reverse proxies appending to ClientIPHeader, the client IP is the address
+	// appended by the farthest of them. 0 defaults to 1.
+	TrustedProxyHops int
+	// Limit of the requests with an unknown API key of every IP, a zero rate defaults to
+	// DEFAULT_FAILED_KEY_LOOKUP_LIMIT
+	FailedKeyLookupsPerIP Limit
+	// Prefixes of the request paths served without API key nor limit, e.g. the health check
+	PublicPathPrefixes []string
+	// Group of the requests matching no route group
+	DefaultRouteGroup RouteGroup
+	RouteGroups       []RouteGroup
+}
+
+// Authenticator identifies the clients of the HTTP API by their API key, or by their IP without API
+// key, and throttles them with the token buckets and the daily quota of their route group.
+type Authenticator struct {
+	logger applogger.Logger
+	config Config
+
+	keys    KeyStore
+	usage   UsageStore
+	limiter *RateLimiter
+	now     func() time.Time
+}
+
+func NewAuthenticator(logger applogger.Logger, config Config, keys KeyStore, usage UsageStore) *Authenticator {
+	if config.Header == "" {
+		config.Header = DEFAULT_API_KEY_HEADER
+	}
+	if config.DefaultRouteGroup.Name == "" {
+		config.DefaultRouteGroup.Name = DEFAULT_ROUTE_GROUP_NAME
+	}
+	if config.TrustedProxyHops <= 0 {
+		config.TrustedProxyHops = 1
+	}
+	if config.FailedKeyLookupsPerIP.isUnlimited() {
+		config.FailedKeyLookupsPerIP = DEFAULT_FAILED_KEY_LOOKUP_LIMIT
+	}
+	return &Authenticator{
+		logger: logger.WithFields(applogger.LogFields{
+			"module": "Authenticator",
+		}),
+		config: config,
+
+		keys:    keys,
+		usage:   usage,
+		limiter: NewRateLimiter(),
+		now:     time.Now,
+	}
+}
+
+// UseClock makes the authenticator read the time from `now`, e.g. a fake clock in tests
+func (authenticator *Authenticator) UseClock(now func() time.Time) {
+	authenticator.now = now
+	authenticator.limiter = NewRateLimiterWithClock(now)
+}
+
+// Middleware rejects the requests with an unknown API key with 401, and the throttled ones with 429
+// and Retry-After. The IPs sending too many unknown API keys are throttled before their keys are
+// looked up. The API key of the served requests is available with APIKeyOf.
+func (authenticator *Authenticator) Middleware(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		path := string(ctx.Path())
+		if ctx.IsOptions() || hasAnyPrefix(path, authenticator.config.PublicPathPrefixes) {
+			handler(ctx)
+			return
+		}
+		routeGroup := authenticator.routeGroupOf(path)
+
+		key := string(ctx.Request.Header.Peek(authenticator.config.Header))
+		if key == "" {
+			if authenticator.config.RequireAPIKey {
+				httpapi.Unauthorized(ctx, httpapi.ErrMissingAPIKey)
+				return
+			}
+			client := fmt.Sprintf("ip:%s:%s", authenticator.clientIPOf(ctx), routeGroup.Name)
+			if allowed, retryAfter := authenticator.limiter.Allow(client, routeGroup.PerIP); !allowed {
+				httpapi.TooManyRequests(ctx, httpapi.ErrRateLimited, retryAfter)
+				return
+			}
+			handler(ctx)
+			return
+		}
+
+		failedLookupsClient := fmt.Sprintf("failed-key-lookups:%s", authenticator.clientIPOf(ctx))
+		if exhausted, retryAfter := authenticator.limiter.Exhausted(
+			failedLookupsClient, authenticator.config.FailedKeyLookupsPerIP,
+		); exhausted {
+			httpapi.TooManyRequests(ctx, httpapi.ErrRateLimited, retryAfter)
+			return
+		}
+		apiKey, err := authenticator.keys.FindByKey(key)
+		if err != nil {
+			authenticator.logger.Errorf("error finding API key: %v", err)
+			httpapi.InternalServerError(ctx)
+			return
+		}
+		if apiKey == nil {
+			authenticator.limiter.Allow(failedLookupsClient, authenticator.config.FailedKeyLookupsPerIP)
+			httpapi.Unauthorized(ctx, httpapi.ErrInvalidAPIKey)
+			return
+		}
+
+		client := fmt.Sprintf("key:%d:%s", apiKey.Id, routeGroup.Name)
+		if allowed, retryAfter := authenticator.limiter.Allow(client, routeGroup.PerKey); !allowed {
+			httpapi.TooManyRequests(ctx, httpapi.ErrRateLimited, retryAfter)
+			return
+		}
+		now := authenticator.now().UTC()
+		counted, err := authenticator.usage.Consume(apiKey.Id, now.Format(DAY_LAYOUT), routeGroup.Name, apiKey.DailyQuota)
+		if err != nil {
+			authenticator.logger.Errorf("error counting usage of API key %d: %v", apiKey.Id, err)
+			httpapi.InternalServerError(ctx)
+			return
+		}
+		if !counted {
+			nextDay := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+			httpapi.TooManyRequests(ctx, httpapi.ErrQuotaExceeded, nextDay.Sub(now))
+			return
+		}
+
+		ctx.SetUserValue(apiKeyUserValue, apiKey)
+		handler(ctx)
+	}
+}
+
+// Run records the usage counted every interval until the context is done, and a last time
+// afterwards
+func (authenticator *Authenticator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			authenticator.flush()
+			return
+		case <-ticker.C:
+			authenticator.flush()
+		}
+	}
+}
+
+func (authenticator *Authenticator) flush() {
+	if err := authenticator.usage.Flush(); err != nil {
+		authenticator.logger.Errorf("error recording API key usages: %v", err)
+	}
+}
+
+// routeGroupOf returns the route group with the longest path prefix of the path
+func (authenticator *Authenticator) routeGroupOf(path string) *RouteGroup {
+	routeGroup := &authenticator.config.DefaultRouteGroup
+	longestPrefix := 0
+	for i := range authenticator.config.RouteGroups {
+		for _, prefix := range authenticator.config.RouteGroups[i].PathPrefixes {
+			if len(prefix) > longestPrefix && strings.HasPrefix(path, prefix) {
+				routeGroup = &authenticator.config.RouteGroups[i]
+				longestPrefix = len(prefix)
+			}
+		}
+	}
+	return routeGroup
+}
+
+// clientIPOf returns the address appended to the client IP header by the farthest trusted proxy.
+// The addresses on its left are set by the client itself and cannot be trusted.
+func (authenticator *Authenticator) clientIPOf(ctx *fasthttp.RequestCtx) string {
+	if authenticator.config.ClientIPHeader != "" {
+		forwardedFor := strings.Split(string(ctx.Request.Header.Peek(authenticator.config.ClientIPHeader)), ",")
+		// Fewer addresses than trusted proxies leaves the farthest one known
+		i := len(forwardedFor) - authenticator.config.TrustedProxyHops
+		if i < 0 {
+			i = 0
+		}
+		if clientIP := strings.TrimSpace(forwardedFor[i]); clientIP != "" {
+			return clientIP
+		}
+	}
+	return ctx.RemoteIP().String()
+}
+
+// RequireScope rejects the requests without an API key of the scope, it relies on the Middleware of
+// the Authenticator to identify the API key
+func RequireScope(scope string, handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		apiKey := APIKeyOf(ctx)
+		if apiKey == nil {
+			httpapi.Unauthorized(ctx, httpapi.ErrMissingAPIKey)
+			return
+		}
+		if !apiKey.HasScope(scope) {
+			httpapi.Forbidden(ctx, httpapi.ErrInsufficientScope)
+			return
+		}
+		handler(ctx)
+	}
+}
+
+// APIKeyOf returns the API key of the request, nil when it has none
+func APIKeyOf(ctx *fasthttp.RequestCtx) *APIKey {
+	apiKey, _ := ctx.UserValue(apiKeyUserValue).(*APIKey)
+	return apiKey
+}
+
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
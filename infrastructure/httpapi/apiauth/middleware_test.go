@@ -0,0 +1,178 @@
+package apiauth_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/valyala/fasthttp"
+
+	"github.com/AstraProtocol/astra-indexing/external/logger/test"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/apiauth"
+)
+
+type fakeKeyStore struct {
+	keys map[string]*apiauth.APIKey
+}
+
+func (store *fakeKeyStore) FindByKey(key string) (*apiauth.APIKey, error) {
+	return store.keys[key], nil
+}
+
+type fakeUsageStore struct {
+	counts map[int64]int64
+}
+
+func (store *fakeUsageStore) Consume(keyId int64, _ string, _ string, dailyQuota int64) (bool, error) {
+	if dailyQuota > 0 && store.counts[keyId] >= dailyQuota {
+		return false, nil
+	}
+	store.counts[keyId] += 1
+	return true, nil
+}
+
+func (store *fakeUsageStore) Flush() error {
+	return nil
+}
+
+var _ = Describe("Authenticator", func() {
+	var now time.Time
+	var usage *fakeUsageStore
+	var authenticator *apiauth.Authenticator
+
+	newRequest := func(path string, apiKey string) *fasthttp.RequestCtx {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fasthttp.MethodGet)
+		ctx.Request.SetRequestURI(path)
+		if apiKey != "" {
+			ctx.Request.Header.Set(apiauth.DEFAULT_API_KEY_HEADER, apiKey)
+		}
+		return ctx
+	}
+	okHandler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	}
+	serve := func(handler fasthttp.RequestHandler, path string, apiKey string) *fasthttp.RequestCtx {
+		ctx := newRequest(path, apiKey)
+		authenticator.Middleware(handler)(ctx)
+		return ctx
+	}
+
+	newAuthenticator := func(config apiauth.Config) *apiauth.Authenticator {
+		newAuthenticator := apiauth.NewAuthenticator(
+			test.NewFakeLogger(),
+			config,
+			&fakeKeyStore{keys: map[string]*apiauth.APIKey{
+				"partner-key": {Id: 1, Name: "partner", DailyQuota: 3},
+				"admin-key":   {Id: 2, Name: "admin", Scopes: []string{apiauth.SCOPE_ADMIN}},
+			}},
+			usage,
+		)
+		newAuthenticator.UseClock(func() time.Time {
+			return now
+		})
+		return newAuthenticator
+	}
+	newConfig := func() apiauth.Config {
+		return apiauth.Config{
+			PublicPathPrefixes: []string{"/api/v1/health"},
+			DefaultRouteGroup: apiauth.RouteGroup{
+				PerKey: apiauth.Limit{Rate: 10, Burst: 10},
+				PerIP:  apiauth.Limit{Rate: 10, Burst: 10},
+			},
+			RouteGroups: []apiauth.RouteGroup{
+				{
+					Name:         "search",
+					PathPrefixes: []string{"/api/v1/search"},
+					PerKey:       apiauth.Limit{Rate: 1, Burst: 2},
+					PerIP:        apiauth.Limit{Rate: 0.5, Burst: 1},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC)
+		usage = &fakeUsageStore{counts: make(map[int64]int64)}
+		authenticator = newAuthenticator(newConfig())
+	})
+
+	It("should reject unknown API keys", func() {
+		ctx := serve(okHandler, "/api/v1/blocks", "unknown-key")
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusUnauthorized))
+	})
+
+	It("should throttle the IPs sending unknown API keys before looking up their keys", func() {
+		config := newConfig()
+		config.FailedKeyLookupsPerIP = apiauth.Limit{Rate: 0.1, Burst: 2}
+		authenticator = newAuthenticator(config)
+
+		Expect(serve(okHandler, "/api/v1/blocks", "unknown-key").Response.StatusCode()).To(Equal(fasthttp.StatusUnauthorized))
+		Expect(serve(okHandler, "/api/v1/blocks", "partner-key").Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+		Expect(serve(okHandler, "/api/v1/blocks", "unknown-key").Response.StatusCode()).To(Equal(fasthttp.StatusUnauthorized))
+
+		ctx := serve(okHandler, "/api/v1/blocks", "partner-key")
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusTooManyRequests))
+		Expect(string(ctx.Response.Header.Peek("Retry-After"))).To(Equal("10"))
+
+		now = now.Add(10 * time.Second)
+		Expect(serve(okHandler, "/api/v1/blocks", "partner-key").Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+	})
+
+	It("should identify the client by the address appended by the farthest trusted proxy", func() {
+		config := newConfig()
+		config.ClientIPHeader = "X-Forwarded-For"
+		config.TrustedProxyHops = 2
+		authenticator = newAuthenticator(config)
+		serveFrom := func(forwardedFor string) int {
+			ctx := newRequest("/api/v1/search", "")
+			ctx.Request.Header.Set("X-Forwarded-For", forwardedFor)
+			authenticator.Middleware(okHandler)(ctx)
+			return ctx.Response.StatusCode()
+		}
+
+		Expect(serveFrom("10.0.0.1, 203.0.113.7, 10.0.0.2")).To(Equal(fasthttp.StatusOK))
+		// Addresses prepended by the client do not make another client
+		Expect(serveFrom("10.0.0.9, 203.0.113.7, 10.0.0.2")).To(Equal(fasthttp.StatusTooManyRequests))
+		Expect(serveFrom("198.51.100.1, 10.0.0.2")).To(Equal(fasthttp.StatusOK))
+	})
+
+	It("should throttle the requests without API key per IP and route group with Retry-After", func() {
+		Expect(serve(okHandler, "/api/v1/search", "").Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+
+		ctx := serve(okHandler, "/api/v1/search", "")
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusTooManyRequests))
+		Expect(string(ctx.Response.Header.Peek("Retry-After"))).To(Equal("2"))
+
+		Expect(serve(okHandler, "/api/v1/blocks", "").Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+		Expect(serve(okHandler, "/api/v1/health", "").Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+	})
+
+	It("should throttle the requests of an API key with the limit of its route group", func() {
+		Expect(serve(okHandler, "/api/v1/search", "partner-key").Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+		Expect(serve(okHandler, "/api/v1/search", "partner-key").Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+
+		ctx := serve(okHandler, "/api/v1/search", "partner-key")
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusTooManyRequests))
+		Expect(string(ctx.Response.Header.Peek("Retry-After"))).To(Equal("1"))
+		Expect(usage.counts[1]).To(Equal(int64(2)))
+	})
+
+	It("should reject the requests of an API key over its daily quota until the next UTC day", func() {
+		for i := 0; i < 3; i++ {
+			Expect(serve(okHandler, "/api/v1/blocks", "partner-key").Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+		}
+
+		ctx := serve(okHandler, "/api/v1/blocks", "partner-key")
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusTooManyRequests))
+		Expect(string(ctx.Response.Header.Peek("Retry-After"))).To(Equal("60"))
+	})
+
+	It("should require the scope of the route", func() {
+		adminHandler := apiauth.RequireScope(apiauth.SCOPE_ADMIN, okHandler)
+
+		Expect(serve(adminHandler, "/api/v1/report-dashboard/update", "").Response.StatusCode()).To(Equal(fasthttp.StatusUnauthorized))
+		Expect(serve(adminHandler, "/api/v1/report-dashboard/update", "partner-key").Response.StatusCode()).To(Equal(fasthttp.StatusForbidden))
+		Expect(serve(adminHandler, "/api/v1/report-dashboard/update", "admin-key").Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+	})
+})
@@ -0,0 +1,129 @@
+package apiauth
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Interval between the removals of the idle buckets
+const bucketPruneInterval = time.Minute
+
+// Limit is a token bucket refilled with Rate tokens per second up to Burst tokens, every request
+// takes a token. A Rate of 0 is unlimited.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (limit Limit) isUnlimited() bool {
+	return limit.Rate <= 0
+}
+
+func (limit Limit) burst() float64 {
+	if limit.Burst < 1 {
+		return 1
+	}
+	return float64(limit.Burst)
+}
+
+// waitFor returns the time until the next token of the bucket
+func (limit Limit) waitFor(b *bucket) time.Duration {
+	return time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// RateLimiter keeps a token bucket per client, e.g. an API key or an IP of a route group
+type RateLimiter struct {
+	now func() time.Time
+
+	mutex    sync.Mutex
+	buckets  map[string]*bucket
+	prunedAt time.Time
+}
+
+func NewRateLimiter() *RateLimiter {
+	return NewRateLimiterWithClock(time.Now)
+}
+
+// NewRateLimiterWithClock creates a rate limiter reading the time from `now`, e.g. a fake clock in
+// tests
+func NewRateLimiterWithClock(now func() time.Time) *RateLimiter {
+	return &RateLimiter{
+		now: now,
+
+		buckets:  make(map[string]*bucket),
+		prunedAt: now(),
+	}
+}
+
+// Allow takes a token of the bucket of the client. When the bucket is empty, it returns false and
+// the time until the next token.
+func (limiter *RateLimiter) Allow(client string, limit Limit) (bool, time.Duration) {
+	if limit.isUnlimited() {
+		return true, 0
+	}
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	b := limiter.refill(client, limit)
+	if b.tokens < 1 {
+		return false, limit.waitFor(b)
+	}
+	b.tokens -= 1
+	return true, 0
+}
+
+// Exhausted returns whether the bucket of the client is empty, without taking a token, and the time
+// until the next token
+func (limiter *RateLimiter) Exhausted(client string, limit Limit) (bool, time.Duration) {
+	if limit.isUnlimited() {
+		return false, 0
+	}
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	b := limiter.refill(client, limit)
+	if b.tokens < 1 {
+		return true, limit.waitFor(b)
+	}
+	return false, 0
+}
+
+// refill returns the bucket of the client refilled up to now, the caller must hold the mutex
+func (limiter *RateLimiter) refill(client string, limit Limit) *bucket {
+	now := limiter.now()
+	limiter.prune(now)
+
+	b, exist := limiter.buckets[client]
+	if !exist {
+		b = &bucket{
+			tokens:    limit.burst(),
+			updatedAt: now,
+		}
+		limiter.buckets[client] = b
+	}
+	b.tokens = math.Min(limit.burst(), b.tokens+now.Sub(b.updatedAt).Seconds()*limit.Rate)
+	b.updatedAt = now
+	return b
+}
+
+// prune removes the buckets idle since the last prune, they would be full again by now for any
+// limit refilling a token within the interval
+func (limiter *RateLimiter) prune(now time.Time) {
+	if now.Sub(limiter.prunedAt) < bucketPruneInterval {
+		return
+	}
+	for client, b := range limiter.buckets {
+		if b.updatedAt.Before(limiter.prunedAt) {
+			delete(limiter.buckets, client)
+		}
+	}
+	limiter.prunedAt = now
+}
@@ -0,0 +1,70 @@
+package apiauth_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/apiauth"
+)
+
+var _ = Describe("RateLimiter", func() {
+	var now time.Time
+	var limiter *apiauth.RateLimiter
+
+	BeforeEach(func() {
+		now = time.Unix(1700000000, 0)
+		limiter = apiauth.NewRateLimiterWithClock(func() time.Time {
+			return now
+		})
+	})
+
+	It("should allow a burst and refill the bucket at the rate", func() {
+		limit := apiauth.Limit{Rate: 2, Burst: 3}
+		for i := 0; i < 3; i++ {
+			allowed, _ := limiter.Allow("client", limit)
+			Expect(allowed).To(BeTrue())
+		}
+
+		allowed, retryAfter := limiter.Allow("client", limit)
+		Expect(allowed).To(BeFalse())
+		Expect(retryAfter).To(Equal(500 * time.Millisecond))
+
+		now = now.Add(500 * time.Millisecond)
+		allowed, _ = limiter.Allow("client", limit)
+		Expect(allowed).To(BeTrue())
+		allowed, _ = limiter.Allow("client", limit)
+		Expect(allowed).To(BeFalse())
+	})
+
+	It("should keep a bucket per client", func() {
+		limit := apiauth.Limit{Rate: 1, Burst: 1}
+		allowed, _ := limiter.Allow("first", limit)
+		Expect(allowed).To(BeTrue())
+		allowed, _ = limiter.Allow("first", limit)
+		Expect(allowed).To(BeFalse())
+
+		allowed, _ = limiter.Allow("second", limit)
+		Expect(allowed).To(BeTrue())
+	})
+
+	It("should report an empty bucket without taking a token", func() {
+		limit := apiauth.Limit{Rate: 1, Burst: 1}
+		exhausted, _ := limiter.Exhausted("client", limit)
+		Expect(exhausted).To(BeFalse())
+		allowed, _ := limiter.Allow("client", limit)
+		Expect(allowed).To(BeTrue())
+
+		exhausted, retryAfter := limiter.Exhausted("client", limit)
+		Expect(exhausted).To(BeTrue())
+		Expect(retryAfter).To(Equal(time.Second))
+	})
+
+	It("should never limit a rate of 0", func() {
+		for i := 0; i < 100; i++ {
+			allowed, _ := limiter.Allow("client", apiauth.Limit{})
+			Expect(allowed).To(BeTrue())
+		}
+	})
+})
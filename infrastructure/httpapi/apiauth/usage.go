@@ -0,0 +1,201 @@
+package apiauth
+
+import (
+	"fmt"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+)
+
+const DEFAULT_API_KEY_USAGES_TABLE = "api_key_usages"
+
+// Layout of the UTC days the usage is counted by
+const DAY_LAYOUT = "2006-01-02"
+
+// API key usages table should have the following schema
+// | Field         | Data Type | Constraint                                   |
+// | ------------- | --------- | -------------------------------------------- |
+// | api_key_id    | INT64     | PRIMARY KEY(api_key_id, day, route_group)    |
+// | day           | VARCHAR   |                                              |
+// | route_group   | VARCHAR   |                                              |
+// | request_count | INT64     | NOT NULL                                     |
+
+// UsageStore counts the requests of the API keys per UTC day and route group
+type UsageStore interface {
+	// Consume counts a request of the key when the key has not reached its daily quota yet, and
+	// returns whether the request is counted. A quota of 0 is unlimited.
+	Consume(keyId int64, day string, routeGroup string, dailyQuota int64) (bool, error)
+	// Flush records the requests counted since the last flush
+	Flush() error
+}
+
+type usageKey struct {
+	keyId      int64
+	day        string
+	routeGroup string
+}
+
+type dailyUsageKey struct {
+	keyId int64
+	day   string
+}
+
+// RDbUsageStore counts the requests in memory and records them to the table on Flush. The daily
+// count of a key is loaded from the table on its first request of the day, such that the quota
+// survives restarts. Instances of the HTTP API do not see the requests counted by the others until
+// the next day, the quota is then enforced per instance.
+type RDbUsageStore struct {
+	rdbHandle *rdb.Handle
+	table     string
+	keysTable string
+
+	mutex       sync.Mutex
+	pending     map[usageKey]int64
+	dailyCounts map[dailyUsageKey]int64
+}
+
+func NewRDbUsageStore(rdbHandle *rdb.Handle) *RDbUsageStore {
+	return &RDbUsageStore{
+		rdbHandle: rdbHandle,
+		table:     DEFAULT_API_KEY_USAGES_TABLE,
+		keysTable: DEFAULT_API_KEYS_TABLE,
+
+		pending:     make(map[usageKey]int64),
+		dailyCounts: make(map[dailyUsageKey]int64),
+	}
+}
+
+// Consume loads the daily count of the key outside the lock, such that the requests of the other
+// keys are not held by the lookup
+func (store *RDbUsageStore) Consume(keyId int64, day string, routeGroup string, dailyQuota int64) (bool, error) {
+	dailyKey := dailyUsageKey{keyId, day}
+	store.mutex.Lock()
+	_, loaded := store.dailyCounts[dailyKey]
+	store.mutex.Unlock()
+	if !loaded {
+		count, err := store.countWithRDbHandle(keyId, day)
+		if err != nil {
+			return false, err
+		}
+		store.mutex.Lock()
+		// Another request of the key may have loaded and counted meanwhile
+		if _, loaded = store.dailyCounts[dailyKey]; !loaded {
+			store.dailyCounts[dailyKey] = count
+		}
+		store.mutex.Unlock()
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	dailyCount := store.dailyCounts[dailyKey]
+	if dailyQuota > 0 && dailyCount >= dailyQuota {
+		return false, nil
+	}
+
+	store.dailyCounts[dailyKey] = dailyCount + 1
+	store.pending[usageKey{keyId, day, routeGroup}] += 1
+	return true, nil
+}
+
+// Flush records the pending counts in a single statement, and forgets the daily counts of the
+// previous days
+func (store *RDbUsageStore) Flush() error {
+	store.mutex.Lock()
+	pending := store.pending
+	store.pending = make(map[usageKey]int64)
+	latestDay := ""
+	for dailyKey := range store.dailyCounts {
+		if dailyKey.day > latestDay {
+			latestDay = dailyKey.day
+		}
+	}
+	for dailyKey := range store.dailyCounts {
+		if dailyKey.day < latestDay {
+			delete(store.dailyCounts, dailyKey)
+		}
+	}
+	store.mutex.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// The counts of the keys deleted meanwhile are dropped instead of violating the foreign key,
+	// which would fail every following flush
+	var usagesBuilder sq.SelectBuilder
+	first := true
+	for key, count := range pending {
+		if first {
+			first = false
+			usagesBuilder = store.rdbHandle.StmtBuilder.Select().Column(
+				"?::BIGINT AS api_key_id", key.keyId,
+			).Column(
+				"?::VARCHAR AS day", key.day,
+			).Column(
+				"?::VARCHAR AS route_group", key.routeGroup,
+			).Column(
+				"?::BIGINT AS request_count", count,
+			)
+			continue
+		}
+		usagesBuilder = usagesBuilder.Suffix(
+			"UNION ALL SELECT ?::BIGINT, ?::VARCHAR, ?::VARCHAR, ?::BIGINT", key.keyId, key.day, key.routeGroup, count,
+		)
+	}
+	sql, args, err := store.rdbHandle.StmtBuilder.Insert(
+		store.table,
+	).Columns(
+		"api_key_id", "day", "route_group", "request_count",
+	).Select(
+		store.rdbHandle.StmtBuilder.Select(
+			"usages.api_key_id", "usages.day", "usages.route_group", "usages.request_count",
+		).FromSelect(
+			usagesBuilder, "usages",
+		).Where(
+			"EXISTS (SELECT 1 FROM " + store.keysTable + " WHERE " + store.keysTable + ".id = usages.api_key_id)",
+		),
+	).Suffix(
+		"ON CONFLICT (api_key_id, day, route_group) DO UPDATE SET request_count = " +
+			store.table + ".request_count + EXCLUDED.request_count",
+	).ToSql()
+	if err != nil {
+		store.restore(pending)
+		return fmt.Errorf("error building API key usages insertion SQL: %v", err)
+	}
+	if _, err = store.rdbHandle.Exec(sql, args...); err != nil {
+		store.restore(pending)
+		return fmt.Errorf("error recording API key usages: %v", err)
+	}
+
+	return nil
+}
+
+// restore puts back the counts failing to be recorded, such that the next flush records them
+func (store *RDbUsageStore) restore(pending map[usageKey]int64) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	for key, count := range pending {
+		store.pending[key] += count
+	}
+}
+
+func (store *RDbUsageStore) countWithRDbHandle(keyId int64, day string) (int64, error) {
+	sql, args, err := store.rdbHandle.StmtBuilder.Select(
+		"COALESCE(SUM(request_count), 0)",
+	).From(
+		store.table,
+	).Where(
+		"api_key_id = ? AND day = ?", keyId, day,
+	).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error building API key usage selection SQL: %v", err)
+	}
+
+	var count int64
+	if err = store.rdbHandle.QueryRow(sql, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error scanning API key usage: %v", err)
+	}
+	return count, nil
+}
@@ -8,6 +8,13 @@ var (
 	ErrInvalidPagination = errors.New("invalid pagination type")
 	ErrInvalidPage       = errors.New("invalid page number")
 	ErrInvalidLimit      = errors.New("invalid page limit")
+	ErrInvalidCursor     = errors.New("invalid pagination cursor")
 
 	ErrInvalidQuery = errors.New("invalid query parameter")
+
+	ErrMissingAPIKey     = errors.New("missing API key")
+	ErrInvalidAPIKey     = errors.New("invalid API key")
+	ErrInsufficientScope = errors.New("insufficient scope of API key")
+	ErrRateLimited       = errors.New("rate limit exceeded")
+	ErrQuotaExceeded     = errors.New("daily quota exceeded")
 )
@@ -0,0 +1,208 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+)
+
+// Number of nodes of a connection without `first` argument
+const DEFAULT_FIRST = 20
+
+// Maximum `first` argument of a connection
+const MAX_FIRST = 100
+
+const cursorPrefix = "offset:"
+
+// Connection is a page of nodes following the Relay cursor connections specification. Cursors are
+// opaque to the clients, they encode the offset of the node in the list.
+type Connection struct {
+	Edges      []Edge
+	PageInfo   PageInfo
+	TotalCount int64
+}
+
+type Edge struct {
+	Cursor string
+	Node   interface{}
+}
+
+type PageInfo struct {
+	HasNextPage     bool
+	HasPreviousPage bool
+	StartCursor     *string
+	EndCursor       *string
+}
+
+func encodeCursor(offset int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(offset, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return 0, fmt.Errorf("invalid cursor: %s", cursor)
+	}
+	offset, err := strconv.ParseInt(strings.TrimPrefix(string(decoded), cursorPrefix), 10, 64)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor: %s", cursor)
+	}
+	return offset, nil
+}
+
+// connectionArgs are the arguments of a connection field
+type connectionArgs struct {
+	first int64
+	// Offset of the first node of the page
+	start int64
+}
+
+var connectionArgsConfig = gql.FieldConfigArgument{
+	"first": &gql.ArgumentConfig{
+		Type:         gql.Int,
+		DefaultValue: DEFAULT_FIRST,
+		Description:  fmt.Sprintf("Number of nodes, up to %d", MAX_FIRST),
+	},
+	"after": &gql.ArgumentConfig{
+		Type:        gql.String,
+		Description: "Cursor of the node the page starts after",
+	},
+}
+
+// withConnectionArgs adds the connection arguments to the arguments of a field
+func withConnectionArgs(args gql.FieldConfigArgument) gql.FieldConfigArgument {
+	for name, arg := range connectionArgsConfig {
+		args[name] = arg
+	}
+	return args
+}
+
+func connectionArgsOf(args map[string]interface{}) (connectionArgs, error) {
+	result := connectionArgs{
+		first: DEFAULT_FIRST,
+	}
+	if first, ok := args["first"].(int); ok {
+		if first < 1 || first > MAX_FIRST {
+			return result, fmt.Errorf("first must be between 1 and %d", MAX_FIRST)
+		}
+		result.first = int64(first)
+	}
+	if after, ok := args["after"].(string); ok && after != "" {
+		offset, err := decodeCursor(after)
+		if err != nil {
+			return result, err
+		}
+		result.start = offset + 1
+	}
+	return result, nil
+}
+
+// listConnection lists the page of the connection with an offset paginated list of the view. A page
+// not aligned with the pages of the view is gathered from two of them.
+func listConnection[T any](
+	args connectionArgs,
+	list func(pagination *pagination.Pagination) ([]T, *pagination.Result, error),
+) (*Connection, error) {
+	page := args.start/args.first + 1
+	skip := args.start % args.first
+
+	rows, paginationResult, err := list(pagination.NewOffsetPagination(page, args.first))
+	if err != nil {
+		return nil, err
+	}
+	isLastPage := int64(len(rows)) < args.first
+	if skip >= int64(len(rows)) {
+		rows = rows[:0]
+	} else {
+		rows = rows[skip:]
+	}
+	if skip > 0 && !isLastPage {
+		nextRows, _, err := list(pagination.NewOffsetPagination(page+1, args.first))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(nextRows)) > skip {
+			nextRows = nextRows[:skip]
+		}
+		rows = append(rows, nextRows...)
+	}
+
+	connection := &Connection{
+		Edges: make([]Edge, 0, len(rows)),
+		PageInfo: PageInfo{
+			HasPreviousPage: args.start > 0,
+		},
+	}
+	for i := range rows {
+		connection.Edges = append(connection.Edges, Edge{
+			Cursor: encodeCursor(args.start + int64(i)),
+			Node:   &rows[i],
+		})
+	}
+	if len(connection.Edges) > 0 {
+		connection.PageInfo.StartCursor = &connection.Edges[0].Cursor
+		connection.PageInfo.EndCursor = &connection.Edges[len(connection.Edges)-1].Cursor
+	}
+	if paginationResult != nil && paginationResult.OffsetResult() != nil {
+		connection.TotalCount = paginationResult.OffsetResult().TotalRecord
+	}
+	connection.PageInfo.HasNextPage = args.start+int64(len(rows)) < connection.TotalCount
+
+	return connection, nil
+}
+
+var pageInfoType = gql.NewObject(gql.ObjectConfig{
+	Name: "PageInfo",
+	Fields: gql.Fields{
+		"hasNextPage": fieldOf(gql.NewNonNull(gql.Boolean), func(pageInfo *PageInfo) interface{} {
+			return pageInfo.HasNextPage
+		}),
+		"hasPreviousPage": fieldOf(gql.NewNonNull(gql.Boolean), func(pageInfo *PageInfo) interface{} {
+			return pageInfo.HasPreviousPage
+		}),
+		"startCursor": fieldOf(gql.String, func(pageInfo *PageInfo) interface{} {
+			return pageInfo.StartCursor
+		}),
+		"endCursor": fieldOf(gql.String, func(pageInfo *PageInfo) interface{} {
+			return pageInfo.EndCursor
+		}),
+	},
+})
+
+// newConnectionType creates the connection type of the nodes with their edge type
+func newConnectionType(nodeType *gql.Object) *gql.Object {
+	edgeType := gql.NewObject(gql.ObjectConfig{
+		Name: nodeType.Name() + "Edge",
+		Fields: gql.Fields{
+			"cursor": fieldOf(gql.NewNonNull(gql.String), func(edge *Edge) interface{} {
+				return edge.Cursor
+			}),
+			"node": fieldOf(gql.NewNonNull(nodeType), func(edge *Edge) interface{} {
+				return edge.Node
+			}),
+		},
+	})
+	return gql.NewObject(gql.ObjectConfig{
+		Name: nodeType.Name() + "Connection",
+		Fields: gql.Fields{
+			"edges": fieldOf(gql.NewNonNull(gql.NewList(gql.NewNonNull(edgeType))), func(connection *Connection) interface{} {
+				edges := make([]*Edge, 0, len(connection.Edges))
+				for i := range connection.Edges {
+					edges = append(edges, &connection.Edges[i])
+				}
+				return edges
+			}),
+			"pageInfo": fieldOf(gql.NewNonNull(pageInfoType), func(connection *Connection) interface{} {
+				return &connection.PageInfo
+			}),
+			"totalCount": fieldOf(gql.NewNonNull(gql.Int), func(connection *Connection) interface{} {
+				return connection.TotalCount
+			}),
+		},
+	})
+}
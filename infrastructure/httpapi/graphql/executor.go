@@ -0,0 +1,103 @@
+package graphql
+
+import (
+	"context"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	block_view "github.com/AstraProtocol/astra-indexing/projection/block/view"
+)
+
+type loadersContextKey struct{}
+
+// loaders are created for every request, such that the cached values do not outlive it
+type loaders struct {
+	blocks *Loader[int64, *block_view.Block]
+}
+
+func newLoaders(views Views) *loaders {
+	return &loaders{
+		blocks: NewLoader(func(heights []int64) (map[int64]*block_view.Block, error) {
+			blocks, err := views.Blocks.ListByHeights(heights)
+			if err != nil {
+				return nil, err
+			}
+			blocksByHeight := make(map[int64]*block_view.Block, len(blocks))
+			for i := range blocks {
+				blocksByHeight[blocks[i].Height] = &blocks[i]
+			}
+			return blocksByHeight, nil
+		}),
+	}
+}
+
+func loadersOf(ctx context.Context) *loaders {
+	if ctx == nil {
+		return nil
+	}
+	loaders, _ := ctx.Value(loadersContextKey{}).(*loaders)
+	return loaders
+}
+
+type Request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// Executor executes the queries within the limits
+type Executor struct {
+	views  Views
+	schema gql.Schema
+	limits Limits
+}
+
+func NewExecutor(views Views, limits Limits) (*Executor, error) {
+	schema, err := NewSchema(views)
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{
+		views:  views,
+		schema: schema,
+		limits: limits,
+	}, nil
+}
+
+// Execute validates the query and checks its limits before resolving it. The errors are reported in
+// the result as the specification requires.
+func (executor *Executor) Execute(ctx context.Context, request Request) *gql.Result {
+	document, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(request.Query),
+			Name: "GraphQL request",
+		}),
+	})
+	if err != nil {
+		return &gql.Result{
+			Errors: gqlerrors.FormatErrors(err),
+		}
+	}
+	validationResult := gql.ValidateDocument(&executor.schema, document, nil)
+	if !validationResult.IsValid {
+		return &gql.Result{
+			Errors: validationResult.Errors,
+		}
+	}
+	if err = executor.limits.check(document, request.Variables); err != nil {
+		return &gql.Result{
+			Errors: gqlerrors.FormatErrors(err),
+		}
+	}
+
+	return gql.Execute(gql.ExecuteParams{
+		Schema:        executor.schema,
+		AST:           document,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       context.WithValue(ctx, loadersContextKey{}, newLoaders(executor.views)),
+	})
+}
@@ -0,0 +1,198 @@
+package graphql_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/graphql"
+	block_view "github.com/AstraProtocol/astra-indexing/projection/block/view"
+	transaction_view "github.com/AstraProtocol/astra-indexing/projection/transaction/view"
+)
+
+// page returns the rows of the offset pagination
+func page[T any](rows []T, paginationInput *pagination.Pagination) ([]T, *pagination.Result) {
+	params := paginationInput.OffsetParams()
+	start := (params.Page - 1) * params.Limit
+	end := start + params.Limit
+	if start > int64(len(rows)) {
+		start = int64(len(rows))
+	}
+	if end > int64(len(rows)) {
+		end = int64(len(rows))
+	}
+	return rows[start:end], pagination.NewOffsetPaginationResult(int64(len(rows)), params.Page, params.Limit)
+}
+
+type fakeBlocksView struct {
+	blocks             []block_view.Block
+	listByHeightsCalls [][]int64
+}
+
+func (view *fakeBlocksView) FindBy(identity *block_view.BlockIdentity) (*block_view.Block, error) {
+	for i := range view.blocks {
+		if identity.MaybeHeight != nil && view.blocks[i].Height == *identity.MaybeHeight {
+			return &view.blocks[i], nil
+		}
+	}
+	return nil, rdb.ErrNoRows
+}
+
+func (view *fakeBlocksView) ListByHeights(heights []int64) ([]block_view.Block, error) {
+	view.listByHeightsCalls = append(view.listByHeightsCalls, heights)
+	blocks := make([]block_view.Block, 0)
+	for _, height := range heights {
+		for _, block := range view.blocks {
+			if block.Height == height {
+				blocks = append(blocks, block)
+			}
+		}
+	}
+	return blocks, nil
+}
+
+func (view *fakeBlocksView) List(
+	_ block_view.BlocksListOrder,
+	pagination *pagination.Pagination,
+) ([]block_view.Block, *pagination.Result, error) {
+	rows, result := page(view.blocks, pagination)
+	return rows, result, nil
+}
+
+type fakeTransactionsView struct {
+	transactions []transaction_view.TransactionRow
+}
+
+func (view *fakeTransactionsView) FindByHash(txHash string) (*transaction_view.TransactionRow, error) {
+	for i := range view.transactions {
+		if view.transactions[i].Hash == txHash {
+			return &view.transactions[i], nil
+		}
+	}
+	return nil, rdb.ErrNoRows
+}
+
+func (view *fakeTransactionsView) List(
+	_ transaction_view.TransactionsListFilter,
+	_ transaction_view.TransactionsListOrder,
+	pagination *pagination.Pagination,
+) ([]transaction_view.TransactionRow, *pagination.Result, error) {
+	rows, result := page(view.transactions, pagination)
+	return rows, result, nil
+}
+
+var _ = Describe("Executor", func() {
+	var blocksView *fakeBlocksView
+	var executor *graphql.Executor
+
+	BeforeEach(func() {
+		blocksView = &fakeBlocksView{}
+		for height := int64(1); height <= 5; height++ {
+			blocksView.blocks = append(blocksView.blocks, block_view.Block{
+				Height: height,
+				Hash:   "HASH",
+				Time:   utctime.FromUnixNano(0),
+			})
+		}
+		transactionsView := &fakeTransactionsView{
+			transactions: []transaction_view.TransactionRow{
+				{BlockHeight: 1, Hash: "TX1", BlockTime: utctime.FromUnixNano(0)},
+				{BlockHeight: 2, Hash: "TX2", BlockTime: utctime.FromUnixNano(0)},
+				{BlockHeight: 2, Hash: "TX3", BlockTime: utctime.FromUnixNano(0)},
+			},
+		}
+
+		var err error
+		executor, err = graphql.NewExecutor(graphql.Views{
+			Blocks:       blocksView,
+			Transactions: transactionsView,
+		}, graphql.Limits{
+			MaxDepth:      5,
+			MaxComplexity: 100,
+		})
+		Expect(err).To(BeNil())
+	})
+
+	It("should batch the blocks of the nodes of a connection into a single read", func() {
+		result := executor.Execute(context.Background(), graphql.Request{
+			Query: `{ transactions(first: 3) { edges { node { hash block { height } } } } }`,
+		})
+
+		Expect(result.Errors).To(BeEmpty())
+		Expect(blocksView.listByHeightsCalls).To(HaveLen(1))
+		Expect(blocksView.listByHeightsCalls[0]).To(ConsistOf(int64(1), int64(2)))
+		edges := result.Data.(map[string]interface{})["transactions"].(map[string]interface{})["edges"].([]interface{})
+		Expect(edges).To(HaveLen(3))
+		Expect(edges[2].(map[string]interface{})["node"]).To(Equal(map[string]interface{}{
+			"hash": "TX3",
+			"block": map[string]interface{}{
+				"height": 2,
+			},
+		}))
+	})
+
+	It("should page the connections with the cursors", func() {
+		firstPage := executor.Execute(context.Background(), graphql.Request{
+			Query: `{ blocks(first: 2) { totalCount pageInfo { hasNextPage endCursor } edges { node { height } } } }`,
+		})
+		Expect(firstPage.Errors).To(BeEmpty())
+		blocks := firstPage.Data.(map[string]interface{})["blocks"].(map[string]interface{})
+		Expect(blocks["totalCount"]).To(Equal(5))
+		pageInfo := blocks["pageInfo"].(map[string]interface{})
+		Expect(pageInfo["hasNextPage"]).To(BeTrue())
+
+		secondPage := executor.Execute(context.Background(), graphql.Request{
+			Query: `query ($after: String) { blocks(first: 3, after: $after) { pageInfo { hasNextPage hasPreviousPage } edges { node { height } } } }`,
+			Variables: map[string]interface{}{
+				"after": pageInfo["endCursor"],
+			},
+		})
+		Expect(secondPage.Errors).To(BeEmpty())
+		blocks = secondPage.Data.(map[string]interface{})["blocks"].(map[string]interface{})
+		heights := make([]interface{}, 0)
+		for _, edge := range blocks["edges"].([]interface{}) {
+			heights = append(heights, edge.(map[string]interface{})["node"].(map[string]interface{})["height"])
+		}
+		Expect(heights).To(Equal([]interface{}{3, 4, 5}))
+		Expect(blocks["pageInfo"]).To(Equal(map[string]interface{}{
+			"hasNextPage":     false,
+			"hasPreviousPage": true,
+		}))
+	})
+
+	It("should resolve the missing rows to null", func() {
+		result := executor.Execute(context.Background(), graphql.Request{
+			Query: `{ transaction(hash: "UNKNOWN") { hash } }`,
+		})
+
+		Expect(result.Errors).To(BeEmpty())
+		Expect(result.Data).To(Equal(map[string]interface{}{
+			"transaction": nil,
+		}))
+	})
+
+	It("should reject the queries deeper than the maximum depth", func() {
+		result := executor.Execute(context.Background(), graphql.Request{
+			Query: `{ transactions { edges { node { block { transactions { edges { node { hash } } } } } } } }`,
+		})
+
+		Expect(result.Data).To(BeNil())
+		Expect(result.Errors).To(HaveLen(1))
+		Expect(result.Errors[0].Message).To(ContainSubstring("exceeds the maximum depth 5"))
+	})
+
+	It("should reject the queries more complex than the maximum complexity", func() {
+		result := executor.Execute(context.Background(), graphql.Request{
+			Query: `{ blocks(first: 50) { edges { node { height hash } } } }`,
+		})
+
+		Expect(result.Data).To(BeNil())
+		Expect(result.Errors).To(HaveLen(1))
+		Expect(result.Errors[0].Message).To(ContainSubstring("exceeds the maximum complexity 100"))
+		Expect(blocksView.listByHeightsCalls).To(BeEmpty())
+	})
+})
@@ -0,0 +1,143 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const DEFAULT_MAX_DEPTH = 8
+const DEFAULT_MAX_COMPLEXITY = 5000
+
+// Limits bound the queries before they are executed
+type Limits struct {
+	// Maximum nesting of the fields of a query, 0 defaults to DEFAULT_MAX_DEPTH
+	MaxDepth int
+	// Maximum cost of a query, 0 defaults to DEFAULT_MAX_COMPLEXITY. Every field costs 1, and the
+	// fields selected under a connection cost as many times as its number of nodes.
+	MaxComplexity int
+}
+
+func (limits Limits) maxDepth() int {
+	if limits.MaxDepth <= 0 {
+		return DEFAULT_MAX_DEPTH
+	}
+	return limits.MaxDepth
+}
+
+func (limits Limits) maxComplexity() int {
+	if limits.MaxComplexity <= 0 {
+		return DEFAULT_MAX_COMPLEXITY
+	}
+	return limits.MaxComplexity
+}
+
+// check fails when an operation of the document exceeds the limits. Introspection fields are not
+// counted.
+func (limits Limits) check(document *ast.Document, variables map[string]interface{}) error {
+	measure := &queryMeasure{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+	}
+	for _, definition := range document.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			measure.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		depth, complexity := measure.selectionSet(operation.SelectionSet, make(map[string]bool))
+		if depth > limits.maxDepth() {
+			return fmt.Errorf("query depth %d exceeds the maximum depth %d", depth, limits.maxDepth())
+		}
+		if complexity > limits.maxComplexity() {
+			return fmt.Errorf("query complexity %d exceeds the maximum complexity %d", complexity, limits.maxComplexity())
+		}
+	}
+	return nil
+}
+
+type queryMeasure struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// selectionSet returns the depth and the complexity of the selection set. `spreading` holds the
+// fragments being spread, such that fragment cycles, rejected by the validation, end the measure.
+func (measure *queryMeasure) selectionSet(selectionSet *ast.SelectionSet, spreading map[string]bool) (int, int) {
+	if selectionSet == nil {
+		return 0, 0
+	}
+
+	maxDepth, complexity := 0, 0
+	for _, selection := range selectionSet.Selections {
+		var depth, cost int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			childDepth, childComplexity := measure.selectionSet(selection.SelectionSet, spreading)
+			depth = childDepth + 1
+			cost = 1 + measure.multiplierOf(selection)*childComplexity
+		case *ast.InlineFragment:
+			depth, cost = measure.selectionSet(selection.SelectionSet, spreading)
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, exist := measure.fragments[name]
+			if !exist || spreading[name] {
+				continue
+			}
+			spreading[name] = true
+			depth, cost = measure.selectionSet(fragment.SelectionSet, spreading)
+			delete(spreading, name)
+		}
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+		complexity += cost
+	}
+	return maxDepth, complexity
+}
+
+// multiplierOf returns the number of nodes the field selects its fields for, the `first` argument
+// of a connection
+func (measure *queryMeasure) multiplierOf(field *ast.Field) int {
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "first" {
+			continue
+		}
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			if first, err := strconv.Atoi(value.Value); err == nil && first > 0 {
+				return first
+			}
+		case *ast.Variable:
+			switch first := measure.variables[value.Name.Value].(type) {
+			case float64:
+				if first > 0 {
+					return int(first)
+				}
+			case int:
+				if first > 0 {
+					return first
+				}
+			}
+		}
+		return DEFAULT_FIRST
+	}
+	if field.SelectionSet != nil {
+		for _, selection := range field.SelectionSet.Selections {
+			if child, ok := selection.(*ast.Field); ok && child.Name.Value == "edges" {
+				return DEFAULT_FIRST
+			}
+		}
+	}
+	return 1
+}
@@ -0,0 +1,79 @@
+package graphql
+
+import (
+	"sync"
+)
+
+// Loader batches the keys loaded while resolving a level of a query into a single fetch. Load
+// returns a thunk, the executor resolves the thunks of a level only once all the fields of the level
+// have been resolved, such that the first thunk fetches the keys of all of them. Results are cached
+// for the request the loader is created for.
+type Loader[K comparable, V any] struct {
+	fetch func(keys []K) (map[K]V, error)
+
+	mutex   sync.Mutex
+	pending map[K]struct{}
+	results map[K]loaderResult[V]
+}
+
+type loaderResult[V any] struct {
+	value V
+	found bool
+	err   error
+}
+
+// NewLoader creates a loader with the function fetching the values of the keys. Keys missing from
+// the fetched values resolve to null.
+func NewLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch: fetch,
+
+		pending: make(map[K]struct{}),
+		results: make(map[K]loaderResult[V]),
+	}
+}
+
+func (loader *Loader[K, V]) Load(key K) func() (interface{}, error) {
+	loader.mutex.Lock()
+	if _, loaded := loader.results[key]; !loaded {
+		loader.pending[key] = struct{}{}
+	}
+	loader.mutex.Unlock()
+
+	return func() (interface{}, error) {
+		loader.mutex.Lock()
+		defer loader.mutex.Unlock()
+
+		loader.flush()
+		result := loader.results[key]
+		if result.err != nil {
+			return nil, result.err
+		}
+		if !result.found {
+			return nil, nil
+		}
+		return result.value, nil
+	}
+}
+
+// flush fetches the pending keys, it is called with the mutex locked
+func (loader *Loader[K, V]) flush() {
+	if len(loader.pending) == 0 {
+		return
+	}
+	keys := make([]K, 0, len(loader.pending))
+	for key := range loader.pending {
+		keys = append(keys, key)
+	}
+	loader.pending = make(map[K]struct{})
+
+	values, err := loader.fetch(keys)
+	for _, key := range keys {
+		value, found := values[key]
+		loader.results[key] = loaderResult[V]{
+			value: value,
+			found: found,
+			err:   err,
+		}
+	}
+}
@@ -0,0 +1,904 @@
+package graphql
+
+import (
+	"errors"
+
+	gql "github.com/graphql-go/graphql"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/view"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	account_view "github.com/AstraProtocol/astra-indexing/projection/account/view"
+	account_transaction_view "github.com/AstraProtocol/astra-indexing/projection/account_transaction/view"
+	block_view "github.com/AstraProtocol/astra-indexing/projection/block/view"
+	ibc_channel_view "github.com/AstraProtocol/astra-indexing/projection/ibc_channel/view"
+	proposal_view "github.com/AstraProtocol/astra-indexing/projection/proposal/view"
+	transaction_view "github.com/AstraProtocol/astra-indexing/projection/transaction/view"
+	validator_view "github.com/AstraProtocol/astra-indexing/projection/validator/view"
+)
+
+// schemaBuilder creates the types of the schema with the resolvers reading the views
+type schemaBuilder struct {
+	views Views
+
+	blockType              *gql.Object
+	transactionType        *gql.Object
+	accountType            *gql.Object
+	accountTransactionType *gql.Object
+	validatorType          *gql.Object
+	proposalType           *gql.Object
+	voteType               *gql.Object
+	ibcChannelType         *gql.Object
+
+	// The connection types by the name of their node type, the names of the types are unique in a
+	// schema
+	connectionTypes map[string]*gql.Object
+}
+
+// NewSchema creates the schema resolving the queries from the views
+func NewSchema(views Views) (gql.Schema, error) {
+	builder := &schemaBuilder{
+		views: views,
+
+		connectionTypes: make(map[string]*gql.Object),
+	}
+
+	builder.blockType = builder.newBlockType()
+	builder.transactionType = builder.newTransactionType()
+	builder.accountTransactionType = builder.newAccountTransactionType()
+	builder.accountType = builder.newAccountType()
+	builder.validatorType = builder.newValidatorType()
+	builder.voteType = builder.newVoteType()
+	builder.proposalType = builder.newProposalType()
+	builder.ibcChannelType = builder.newIBCChannelType()
+
+	return gql.NewSchema(gql.SchemaConfig{
+		Query: builder.newQueryType(),
+	})
+}
+
+// blockField resolves the block at the height with the block loader of the request, such that the
+// blocks of all the nodes of a connection are read at once
+func (builder *schemaBuilder) blockField(heightOf func(source interface{}) (int64, bool)) *gql.Field {
+	return &gql.Field{
+		Type: builder.blockType,
+		Resolve: func(params gql.ResolveParams) (interface{}, error) {
+			height, ok := heightOf(params.Source)
+			if !ok {
+				return nil, nil
+			}
+			loaders := loadersOf(params.Context)
+			if loaders == nil {
+				return nil, errors.New("missing loaders of the request")
+			}
+			return loaders.blocks.Load(height), nil
+		},
+	}
+}
+
+func (builder *schemaBuilder) connectionTypeOf(nodeType *gql.Object) *gql.Object {
+	connectionType, exist := builder.connectionTypes[nodeType.Name()]
+	if !exist {
+		connectionType = newConnectionType(nodeType)
+		builder.connectionTypes[nodeType.Name()] = connectionType
+	}
+	return connectionType
+}
+
+func (builder *schemaBuilder) newBlockType() *gql.Object {
+	return gql.NewObject(gql.ObjectConfig{
+		Name: "Block",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"height": fieldOf(gql.NewNonNull(gql.Int), func(block *block_view.Block) interface{} {
+					return block.Height
+				}),
+				"hash": fieldOf(gql.NewNonNull(gql.String), func(block *block_view.Block) interface{} {
+					return block.Hash
+				}),
+				"time": fieldOf(gql.NewNonNull(gql.String), func(block *block_view.Block) interface{} {
+					return timeOf(block.Time)
+				}),
+				"appHash": fieldOf(gql.NewNonNull(gql.String), func(block *block_view.Block) interface{} {
+					return block.AppHash
+				}),
+				"transactionCount": fieldOf(gql.NewNonNull(gql.Int), func(block *block_view.Block) interface{} {
+					return block.TransactionCount
+				}),
+				"committedCouncilNodes": fieldOf(jsonType, func(block *block_view.Block) interface{} {
+					return block.CommittedCouncilNodes
+				}),
+				"transactions": &gql.Field{
+					Type: gql.NewNonNull(builder.connectionTypeOf(builder.transactionType)),
+					Args: withConnectionArgs(gql.FieldConfigArgument{}),
+					Resolve: func(params gql.ResolveParams) (interface{}, error) {
+						block, ok := params.Source.(*block_view.Block)
+						if !ok {
+							return nil, nil
+						}
+						args, err := connectionArgsOf(params.Args)
+						if err != nil {
+							return nil, err
+						}
+						return listConnection(args, func(pagination *pagination.Pagination) ([]transaction_view.TransactionRow, *pagination.Result, error) {
+							return builder.views.Transactions.List(transaction_view.TransactionsListFilter{
+								MaybeBlockHeight: &block.Height,
+							}, transaction_view.TransactionsListOrder{
+								Height: view.ORDER_ASC,
+							}, pagination)
+						})
+					},
+				},
+			}
+		}),
+	})
+}
+
+func (builder *schemaBuilder) newTransactionType() *gql.Object {
+	return gql.NewObject(gql.ObjectConfig{
+		Name: "Transaction",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"blockHeight": fieldOf(gql.NewNonNull(gql.Int), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.BlockHeight
+				}),
+				"blockHash": fieldOf(gql.NewNonNull(gql.String), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.BlockHash
+				}),
+				"blockTime": fieldOf(gql.NewNonNull(gql.String), func(tx *transaction_view.TransactionRow) interface{} {
+					return timeOf(tx.BlockTime)
+				}),
+				"block": builder.blockField(func(source interface{}) (int64, bool) {
+					tx, ok := source.(*transaction_view.TransactionRow)
+					if !ok {
+						return 0, false
+					}
+					return tx.BlockHeight, true
+				}),
+				"hash": fieldOf(gql.NewNonNull(gql.String), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.Hash
+				}),
+				"evmHash": fieldOf(gql.String, func(tx *transaction_view.TransactionRow) interface{} {
+					return nonEmptyStringOf(tx.EvmHash)
+				}),
+				"index": fieldOf(gql.NewNonNull(gql.Int), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.Index
+				}),
+				"success": fieldOf(gql.NewNonNull(gql.Boolean), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.Success
+				}),
+				"status": fieldOf(gql.NewNonNull(gql.String), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.Status
+				}),
+				"code": fieldOf(gql.NewNonNull(gql.Int), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.Code
+				}),
+				"log": fieldOf(gql.NewNonNull(gql.String), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.Log
+				}),
+				"fee": fieldOf(jsonType, func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.Fee
+				}),
+				"feePayer": fieldOf(gql.NewNonNull(gql.String), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.FeePayer
+				}),
+				"feeGranter": fieldOf(gql.NewNonNull(gql.String), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.FeeGranter
+				}),
+				"gasWanted": fieldOf(gql.NewNonNull(gql.Int), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.GasWanted
+				}),
+				"gasUsed": fieldOf(gql.NewNonNull(gql.Int), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.GasUsed
+				}),
+				"fromAddress": fieldOf(gql.NewNonNull(gql.String), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.FromAddress
+				}),
+				"toAddress": fieldOf(gql.NewNonNull(gql.String), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.ToAddress
+				}),
+				"memo": fieldOf(gql.NewNonNull(gql.String), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.Memo
+				}),
+				"timeoutHeight": fieldOf(gql.NewNonNull(gql.Int), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.TimeoutHeight
+				}),
+				"messages": fieldOf(jsonType, func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.Messages
+				}),
+				"signers": fieldOf(jsonType, func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.Signers
+				}),
+				"txType": fieldOf(gql.NewNonNull(gql.String), func(tx *transaction_view.TransactionRow) interface{} {
+					return tx.TxType
+				}),
+			}
+		}),
+	})
+}
+
+func (builder *schemaBuilder) newAccountTransactionType() *gql.Object {
+	return gql.NewObject(gql.ObjectConfig{
+		Name: "AccountTransaction",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"id": fieldOf(gql.NewNonNull(gql.Int), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.Id
+				}),
+				"account": fieldOf(gql.NewNonNull(gql.String), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.Account
+				}),
+				"blockHeight": fieldOf(gql.NewNonNull(gql.Int), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.BlockHeight
+				}),
+				"blockHash": fieldOf(gql.NewNonNull(gql.String), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.BlockHash
+				}),
+				"blockTime": fieldOf(gql.NewNonNull(gql.String), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return timeOf(tx.BlockTime)
+				}),
+				"block": builder.blockField(func(source interface{}) (int64, bool) {
+					tx, ok := source.(*account_transaction_view.AccountTransactionReadRow)
+					if !ok {
+						return 0, false
+					}
+					return tx.BlockHeight, true
+				}),
+				"hash": fieldOf(gql.NewNonNull(gql.String), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.Hash
+				}),
+				"messageTypes": fieldOf(gql.NewList(gql.NewNonNull(gql.String)), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.MessageTypes
+				}),
+				"success": fieldOf(gql.NewNonNull(gql.Boolean), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.Success
+				}),
+				"code": fieldOf(gql.NewNonNull(gql.Int), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.Code
+				}),
+				"log": fieldOf(gql.NewNonNull(gql.String), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.Log
+				}),
+				"fee": fieldOf(jsonType, func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.Fee
+				}),
+				"feePayer": fieldOf(gql.NewNonNull(gql.String), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.FeePayer
+				}),
+				"feeGranter": fieldOf(gql.NewNonNull(gql.String), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.FeeGranter
+				}),
+				"gasWanted": fieldOf(gql.NewNonNull(gql.Int), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.GasWanted
+				}),
+				"gasUsed": fieldOf(gql.NewNonNull(gql.Int), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.GasUsed
+				}),
+				"fromAddress": fieldOf(gql.String, func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return nonEmptyStringOf(tx.FromAddress)
+				}),
+				"toAddress": fieldOf(gql.String, func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return nonEmptyStringOf(tx.ToAddress)
+				}),
+				"isInternalTx": fieldOf(gql.NewNonNull(gql.Boolean), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.IsInternalTx
+				}),
+				"memo": fieldOf(gql.NewNonNull(gql.String), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.Memo
+				}),
+				"timeoutHeight": fieldOf(gql.NewNonNull(gql.Int), func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.TimeoutHeight
+				}),
+				"messages": fieldOf(jsonType, func(tx *account_transaction_view.AccountTransactionReadRow) interface{} {
+					return tx.Messages
+				}),
+			}
+		}),
+	})
+}
+
+func (builder *schemaBuilder) newAccountType() *gql.Object {
+	accountTransactionConnectionType := builder.connectionTypeOf(builder.accountTransactionType)
+	return gql.NewObject(gql.ObjectConfig{
+		Name: "Account",
+		Fields: gql.Fields{
+			"address": fieldOf(gql.NewNonNull(gql.String), func(account *account_view.AccountRow) interface{} {
+				return account.Address
+			}),
+			"type": fieldOf(gql.NewNonNull(gql.String), func(account *account_view.AccountRow) interface{} {
+				return account.Type
+			}),
+			"name": fieldOf(gql.String, func(account *account_view.AccountRow) interface{} {
+				return maybeStringOf(account.MaybeName)
+			}),
+			"pubkey": fieldOf(gql.String, func(account *account_view.AccountRow) interface{} {
+				return maybeStringOf(account.MaybePubkey)
+			}),
+			"accountNumber": fieldOf(gql.NewNonNull(gql.String), func(account *account_view.AccountRow) interface{} {
+				return account.AccountNumber
+			}),
+			"sequenceNumber": fieldOf(gql.NewNonNull(gql.String), func(account *account_view.AccountRow) interface{} {
+				return account.SequenceNumber
+			}),
+			"balance": fieldOf(jsonType, func(account *account_view.AccountRow) interface{} {
+				return account.Balance
+			}),
+			"transactions": &gql.Field{
+				Type: gql.NewNonNull(accountTransactionConnectionType),
+				Args: withConnectionArgs(gql.FieldConfigArgument{
+					"order": &gql.ArgumentConfig{
+						Type:         orderType,
+						DefaultValue: view.ORDER_DESC,
+					},
+				}),
+				Resolve: func(params gql.ResolveParams) (interface{}, error) {
+					account, ok := params.Source.(*account_view.AccountRow)
+					if !ok {
+						return nil, nil
+					}
+					args, err := connectionArgsOf(params.Args)
+					if err != nil {
+						return nil, err
+					}
+					order := orderOf(params.Args)
+					return listConnection(args, func(pagination *pagination.Pagination) ([]account_transaction_view.AccountTransactionReadRow, *pagination.Result, error) {
+						return builder.views.AccountTransactions.List(account_transaction_view.AccountTransactionsListFilter{
+							Account: account.Address,
+						}, account_transaction_view.AccountTransactionsListOrder{
+							Id: order,
+						}, pagination)
+					})
+				},
+			},
+		},
+	})
+}
+
+func (builder *schemaBuilder) newValidatorType() *gql.Object {
+	return gql.NewObject(gql.ObjectConfig{
+		Name: "Validator",
+		Fields: gql.Fields{
+			"operatorAddress": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.OperatorAddress
+			}),
+			"consensusNodeAddress": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.ConsensusNodeAddress
+			}),
+			"initialDelegatorAddress": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.InitialDelegatorAddress
+			}),
+			"tendermintPubkey": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.TendermintPubkey
+			}),
+			"tendermintAddress": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.TendermintAddress
+			}),
+			"status": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.Status
+			}),
+			"jailed": fieldOf(gql.NewNonNull(gql.Boolean), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.Jailed
+			}),
+			"joinedAtBlockHeight": fieldOf(gql.NewNonNull(gql.Int), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.JoinedAtBlockHeight
+			}),
+			"power": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.Power
+			}),
+			"moniker": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.Moniker
+			}),
+			"identity": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.Identity
+			}),
+			"website": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.Website
+			}),
+			"securityContact": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.SecurityContact
+			}),
+			"details": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.Details
+			}),
+			"commissionRate": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.CommissionRate
+			}),
+			"commissionMaxRate": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.CommissionMaxRate
+			}),
+			"commissionMaxChangeRate": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.CommissionMaxChangeRate
+			}),
+			"minSelfDelegation": fieldOf(gql.NewNonNull(gql.String), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.MinSelfDelegation
+			}),
+			"totalSignedBlock": fieldOf(gql.NewNonNull(gql.Int), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.TotalSignedBlock
+			}),
+			"totalActiveBlock": fieldOf(gql.NewNonNull(gql.Int), func(validator *validator_view.ListValidatorRow) interface{} {
+				return validator.TotalActiveBlock
+			}),
+			"impreciseUpTime": fieldOf(gql.String, func(validator *validator_view.ListValidatorRow) interface{} {
+				return bigFloatOf(validator.ImpreciseUpTime)
+			}),
+			"votedGovProposal": fieldOf(gql.String, func(validator *validator_view.ListValidatorRow) interface{} {
+				return bigIntOf(validator.VotedGovProposal)
+			}),
+			// Only known when listing the validators
+			"powerPercentage": fieldOf(gql.String, func(validator *validator_view.ListValidatorRow) interface{} {
+				return nonEmptyStringOf(validator.PowerPercentage)
+			}),
+			"cumulativePowerPercentage": fieldOf(gql.String, func(validator *validator_view.ListValidatorRow) interface{} {
+				return nonEmptyStringOf(validator.CumulativePowerPercentage)
+			}),
+		},
+	})
+}
+
+func (builder *schemaBuilder) newVoteType() *gql.Object {
+	return gql.NewObject(gql.ObjectConfig{
+		Name: "Vote",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"proposalId": fieldOf(gql.NewNonNull(gql.String), func(vote *proposal_view.VoteWithMonikerRow) interface{} {
+					return vote.ProposalId
+				}),
+				"voterAddress": fieldOf(gql.NewNonNull(gql.String), func(vote *proposal_view.VoteWithMonikerRow) interface{} {
+					return vote.VoterAddress
+				}),
+				"voterOperatorAddress": fieldOf(gql.String, func(vote *proposal_view.VoteWithMonikerRow) interface{} {
+					return maybeStringOf(vote.MaybeVoterOperatorAddress)
+				}),
+				"voterMoniker": fieldOf(gql.String, func(vote *proposal_view.VoteWithMonikerRow) interface{} {
+					return maybeStringOf(vote.MaybeVoterMoniker)
+				}),
+				"transactionHash": fieldOf(gql.NewNonNull(gql.String), func(vote *proposal_view.VoteWithMonikerRow) interface{} {
+					return vote.TransactionHash
+				}),
+				"voteAtBlockHeight": fieldOf(gql.NewNonNull(gql.Int), func(vote *proposal_view.VoteWithMonikerRow) interface{} {
+					return vote.VoteAtBlockHeight
+				}),
+				"voteAtBlockTime": fieldOf(gql.NewNonNull(gql.String), func(vote *proposal_view.VoteWithMonikerRow) interface{} {
+					return timeOf(vote.VoteAtBlockTime)
+				}),
+				"block": builder.blockField(func(source interface{}) (int64, bool) {
+					vote, ok := source.(*proposal_view.VoteWithMonikerRow)
+					if !ok {
+						return 0, false
+					}
+					return vote.VoteAtBlockHeight, true
+				}),
+				"answer": fieldOf(gql.NewNonNull(gql.String), func(vote *proposal_view.VoteWithMonikerRow) interface{} {
+					return vote.Answer
+				}),
+				"histories": fieldOf(jsonType, func(vote *proposal_view.VoteWithMonikerRow) interface{} {
+					return vote.Histories
+				}),
+			}
+		}),
+	})
+}
+
+func (builder *schemaBuilder) newProposalType() *gql.Object {
+	voteConnectionType := builder.connectionTypeOf(builder.voteType)
+	return gql.NewObject(gql.ObjectConfig{
+		Name: "Proposal",
+		Fields: gql.Fields{
+			"id": fieldOf(gql.NewNonNull(gql.String), func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return proposal.ProposalId
+			}),
+			"title": fieldOf(gql.NewNonNull(gql.String), func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return proposal.Title
+			}),
+			"description": fieldOf(gql.NewNonNull(gql.String), func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return proposal.Description
+			}),
+			"type": fieldOf(gql.NewNonNull(gql.String), func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return proposal.Type
+			}),
+			"status": fieldOf(gql.NewNonNull(gql.String), func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return proposal.Status
+			}),
+			"proposerAddress": fieldOf(gql.NewNonNull(gql.String), func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return proposal.ProposerAddress
+			}),
+			"proposerOperatorAddress": fieldOf(gql.String, func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return maybeStringOf(proposal.MaybeProposerOperatorAddress)
+			}),
+			"proposerMoniker": fieldOf(gql.String, func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return maybeStringOf(proposal.MaybeProposerMoniker)
+			}),
+			"data": fieldOf(jsonType, func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return proposal.Data
+			}),
+			"initialDeposit": fieldOf(jsonType, func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return proposal.InitialDeposit
+			}),
+			"totalDeposit": fieldOf(jsonType, func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return proposal.TotalDeposit
+			}),
+			"totalVote": fieldOf(gql.String, func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return bigIntOf(proposal.TotalVote)
+			}),
+			"transactionHash": fieldOf(gql.NewNonNull(gql.String), func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return proposal.TransactionHash
+			}),
+			"submitBlockHeight": fieldOf(gql.NewNonNull(gql.Int), func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return proposal.SubmitBlockHeight
+			}),
+			"submitTime": fieldOf(gql.NewNonNull(gql.String), func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return timeOf(proposal.SubmitTime)
+			}),
+			"depositEndTime": fieldOf(gql.NewNonNull(gql.String), func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return timeOf(proposal.DepositEndTime)
+			}),
+			"votingStartTime": fieldOf(gql.String, func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return maybeTimeOf(proposal.MaybeVotingStartTime)
+			}),
+			"votingEndBlockHeight": fieldOf(gql.Int, func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return maybeInt64Of(proposal.MaybeVotingEndBlockHeight)
+			}),
+			"votingEndTime": fieldOf(gql.String, func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return maybeTimeOf(proposal.MaybeVotingEndTime)
+			}),
+			"tally": fieldOf(jsonType, func(proposal *proposal_view.ProposalWithMonikerRow) interface{} {
+				return proposal.Tally
+			}),
+			"votes": &gql.Field{
+				Type: gql.NewNonNull(voteConnectionType),
+				Args: withConnectionArgs(gql.FieldConfigArgument{
+					"answer": &gql.ArgumentConfig{
+						Type: gql.String,
+					},
+					"order": &gql.ArgumentConfig{
+						Type:         orderType,
+						DefaultValue: view.ORDER_DESC,
+					},
+				}),
+				Resolve: func(params gql.ResolveParams) (interface{}, error) {
+					proposal, ok := params.Source.(*proposal_view.ProposalWithMonikerRow)
+					if !ok {
+						return nil, nil
+					}
+					args, err := connectionArgsOf(params.Args)
+					if err != nil {
+						return nil, err
+					}
+					filters := proposal_view.Filters{}
+					if answer, ok := params.Args["answer"].(string); ok {
+						filters.Answer = answer
+					}
+					order := orderOf(params.Args)
+					return listConnection(args, func(pagination *pagination.Pagination) ([]proposal_view.VoteWithMonikerRow, *pagination.Result, error) {
+						return builder.views.Votes.ListByProposalId(proposal.ProposalId, proposal_view.VoteListOrder{
+							VoteAtBlockHeight: order,
+						}, filters, pagination)
+					})
+				},
+			},
+		},
+	})
+}
+
+func (builder *schemaBuilder) newIBCChannelType() *gql.Object {
+	return gql.NewObject(gql.ObjectConfig{
+		Name: "IBCChannel",
+		Fields: gql.Fields{
+			"channelId": fieldOf(gql.NewNonNull(gql.String), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.ChannelID
+			}),
+			"portId": fieldOf(gql.NewNonNull(gql.String), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.PortID
+			}),
+			"connectionId": fieldOf(gql.NewNonNull(gql.String), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.ConnectionID
+			}),
+			"counterpartyChannelId": fieldOf(gql.NewNonNull(gql.String), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.CounterpartyChannelID
+			}),
+			"counterpartyPortId": fieldOf(gql.NewNonNull(gql.String), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.CounterpartyPortID
+			}),
+			"counterpartyChainId": fieldOf(gql.NewNonNull(gql.String), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.CounterpartyChainID
+			}),
+			"status": fieldOf(gql.NewNonNull(gql.String), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.Status
+			}),
+			"packetOrdering": fieldOf(gql.NewNonNull(gql.String), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.PacketOrdering
+			}),
+			"lastInPacketSequence": fieldOf(gql.NewNonNull(gql.Int), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.LastInPacketSequence
+			}),
+			"lastOutPacketSequence": fieldOf(gql.NewNonNull(gql.Int), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.LastOutPacketSequence
+			}),
+			"totalRelayInCount": fieldOf(gql.NewNonNull(gql.Int), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.TotalRelayInCount
+			}),
+			"totalRelayOutCount": fieldOf(gql.NewNonNull(gql.Int), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.TotalRelayOutCount
+			}),
+			"totalRelayOutSuccessCount": fieldOf(gql.NewNonNull(gql.Int), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.TotalRelayOutSuccessCount
+			}),
+			"totalRelayOutSuccessRate": fieldOf(gql.NewNonNull(gql.Float), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.TotalRelayOutSuccessRate
+			}),
+			"createdAtBlockTime": fieldOf(gql.NewNonNull(gql.String), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return timeOf(channel.CreatedAtBlockTime)
+			}),
+			"createdAtBlockHeight": fieldOf(gql.NewNonNull(gql.Int), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.CreatedAtBlockHeight
+			}),
+			"verified": fieldOf(gql.NewNonNull(gql.Boolean), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.Verified
+			}),
+			"description": fieldOf(gql.NewNonNull(gql.String), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.Description
+			}),
+			"lastActivityBlockTime": fieldOf(gql.NewNonNull(gql.String), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return timeOf(channel.LastActivityBlockTime)
+			}),
+			"lastActivityBlockHeight": fieldOf(gql.NewNonNull(gql.Int), func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.LastActivityBlockHeight
+			}),
+			"bondedTokens": fieldOf(jsonType, func(channel *ibc_channel_view.IBCChannelRow) interface{} {
+				return channel.BondedTokens
+			}),
+		},
+	})
+}
+
+func (builder *schemaBuilder) newQueryType() *gql.Object {
+	orderArg := &gql.ArgumentConfig{
+		Type:         orderType,
+		DefaultValue: view.ORDER_DESC,
+	}
+	blockConnectionType := builder.connectionTypeOf(builder.blockType)
+	transactionConnectionType := builder.connectionTypeOf(builder.transactionType)
+	validatorConnectionType := builder.connectionTypeOf(builder.validatorType)
+	proposalConnectionType := builder.connectionTypeOf(builder.proposalType)
+	ibcChannelConnectionType := builder.connectionTypeOf(builder.ibcChannelType)
+
+	return gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"block": &gql.Field{
+				Type: builder.blockType,
+				Args: gql.FieldConfigArgument{
+					"height": &gql.ArgumentConfig{
+						Type: gql.Int,
+					},
+					"hash": &gql.ArgumentConfig{
+						Type: gql.String,
+					},
+				},
+				Resolve: func(params gql.ResolveParams) (interface{}, error) {
+					identity := block_view.BlockIdentity{}
+					if height, ok := params.Args["height"].(int); ok {
+						height64 := int64(height)
+						identity.MaybeHeight = &height64
+					} else if hash, ok := params.Args["hash"].(string); ok {
+						identity.MaybeHash = &hash
+					} else {
+						return nil, errors.New("either height or hash is required")
+					}
+					return nullIfNotFound(builder.views.Blocks.FindBy(&identity))
+				},
+			},
+			"blocks": &gql.Field{
+				Type: gql.NewNonNull(blockConnectionType),
+				Args: withConnectionArgs(gql.FieldConfigArgument{
+					"order": orderArg,
+				}),
+				Resolve: func(params gql.ResolveParams) (interface{}, error) {
+					args, err := connectionArgsOf(params.Args)
+					if err != nil {
+						return nil, err
+					}
+					order := orderOf(params.Args)
+					return listConnection(args, func(pagination *pagination.Pagination) ([]block_view.Block, *pagination.Result, error) {
+						return builder.views.Blocks.List(block_view.BlocksListOrder{
+							Height: order,
+						}, pagination)
+					})
+				},
+			},
+			"transaction": &gql.Field{
+				Type: builder.transactionType,
+				Args: gql.FieldConfigArgument{
+					"hash": &gql.ArgumentConfig{
+						Type: gql.NewNonNull(gql.String),
+					},
+				},
+				Resolve: func(params gql.ResolveParams) (interface{}, error) {
+					hash, _ := params.Args["hash"].(string)
+					return nullIfNotFound(builder.views.Transactions.FindByHash(hash))
+				},
+			},
+			"transactions": &gql.Field{
+				Type: gql.NewNonNull(transactionConnectionType),
+				Args: withConnectionArgs(gql.FieldConfigArgument{
+					"blockHeight": &gql.ArgumentConfig{
+						Type: gql.Int,
+					},
+					"order": orderArg,
+				}),
+				Resolve: func(params gql.ResolveParams) (interface{}, error) {
+					args, err := connectionArgsOf(params.Args)
+					if err != nil {
+						return nil, err
+					}
+					filter := transaction_view.TransactionsListFilter{}
+					if blockHeight, ok := params.Args["blockHeight"].(int); ok {
+						blockHeight64 := int64(blockHeight)
+						filter.MaybeBlockHeight = &blockHeight64
+					}
+					order := orderOf(params.Args)
+					return listConnection(args, func(pagination *pagination.Pagination) ([]transaction_view.TransactionRow, *pagination.Result, error) {
+						return builder.views.Transactions.List(filter, transaction_view.TransactionsListOrder{
+							Height: order,
+						}, pagination)
+					})
+				},
+			},
+			"account": &gql.Field{
+				Type: builder.accountType,
+				Args: gql.FieldConfigArgument{
+					"address": &gql.ArgumentConfig{
+						Type: gql.NewNonNull(gql.String),
+					},
+				},
+				Resolve: func(params gql.ResolveParams) (interface{}, error) {
+					address, _ := params.Args["address"].(string)
+					return nullIfNotFound(builder.views.Accounts.FindBy(&account_view.AccountIdentity{
+						Address: address,
+					}))
+				},
+			},
+			"validator": &gql.Field{
+				Type: builder.validatorType,
+				Args: gql.FieldConfigArgument{
+					"operatorAddress": &gql.ArgumentConfig{
+						Type: gql.String,
+					},
+					"consensusNodeAddress": &gql.ArgumentConfig{
+						Type: gql.String,
+					},
+				},
+				Resolve: func(params gql.ResolveParams) (interface{}, error) {
+					identity := validator_view.ValidatorIdentity{}
+					if operatorAddress, ok := params.Args["operatorAddress"].(string); ok {
+						identity.MaybeOperatorAddress = &operatorAddress
+					} else if consensusNodeAddress, ok := params.Args["consensusNodeAddress"].(string); ok {
+						identity.MaybeConsensusNodeAddress = &consensusNodeAddress
+					} else {
+						return nil, errors.New("either operatorAddress or consensusNodeAddress is required")
+					}
+					validator, err := nullIfNotFound(builder.views.Validators.FindBy(identity))
+					if validator == nil || err != nil {
+						return nil, err
+					}
+					return &validator_view.ListValidatorRow{
+						ValidatorRow: *validator,
+					}, nil
+				},
+			},
+			"validators": &gql.Field{
+				Type: gql.NewNonNull(validatorConnectionType),
+				Args: withConnectionArgs(gql.FieldConfigArgument{
+					"statuses": &gql.ArgumentConfig{
+						Type: gql.NewList(gql.NewNonNull(gql.String)),
+					},
+				}),
+				Resolve: func(params gql.ResolveParams) (interface{}, error) {
+					args, err := connectionArgsOf(params.Args)
+					if err != nil {
+						return nil, err
+					}
+					filter := validator_view.ValidatorsListFilter{}
+					if statuses, ok := params.Args["statuses"].([]interface{}); ok {
+						filter.MaybeStatuses = make([]string, 0, len(statuses))
+						for _, status := range statuses {
+							if status, ok := status.(string); ok {
+								filter.MaybeStatuses = append(filter.MaybeStatuses, status)
+							}
+						}
+					}
+					// Same order as the REST API
+					statusOrder := view.ORDER_ASC
+					joinedAtBlockHeightOrder := view.ORDER_ASC
+					return listConnection(args, func(pagination *pagination.Pagination) ([]validator_view.ListValidatorRow, *pagination.Result, error) {
+						return builder.views.Validators.List(filter, validator_view.ValidatorsListOrder{
+							MaybeStatus:              &statusOrder,
+							MaybeJoinedAtBlockHeight: &joinedAtBlockHeightOrder,
+						}, pagination)
+					})
+				},
+			},
+			"proposal": &gql.Field{
+				Type: builder.proposalType,
+				Args: gql.FieldConfigArgument{
+					"id": &gql.ArgumentConfig{
+						Type: gql.NewNonNull(gql.String),
+					},
+				},
+				Resolve: func(params gql.ResolveParams) (interface{}, error) {
+					id, _ := params.Args["id"].(string)
+					return nullIfNotFound(builder.views.Proposals.FindById(id))
+				},
+			},
+			"proposals": &gql.Field{
+				Type: gql.NewNonNull(proposalConnectionType),
+				Args: withConnectionArgs(gql.FieldConfigArgument{
+					"status": &gql.ArgumentConfig{
+						Type: gql.String,
+					},
+					"order": orderArg,
+				}),
+				Resolve: func(params gql.ResolveParams) (interface{}, error) {
+					args, err := connectionArgsOf(params.Args)
+					if err != nil {
+						return nil, err
+					}
+					filter := proposal_view.ProposalListFilter{}
+					if status, ok := params.Args["status"].(string); ok {
+						filter.MaybeStatus = &status
+					}
+					order := orderOf(params.Args)
+					return listConnection(args, func(pagination *pagination.Pagination) ([]proposal_view.ProposalWithMonikerRow, *pagination.Result, error) {
+						return builder.views.Proposals.List(filter, proposal_view.ProposalListOrder{
+							Id: order,
+						}, pagination)
+					})
+				},
+			},
+			"ibcChannel": &gql.Field{
+				Type: builder.ibcChannelType,
+				Args: gql.FieldConfigArgument{
+					"channelId": &gql.ArgumentConfig{
+						Type: gql.NewNonNull(gql.String),
+					},
+				},
+				Resolve: func(params gql.ResolveParams) (interface{}, error) {
+					channelID, _ := params.Args["channelId"].(string)
+					return nullIfNotFound(builder.views.IBCChannels.FindBy(channelID))
+				},
+			},
+			"ibcChannels": &gql.Field{
+				Type: gql.NewNonNull(ibcChannelConnectionType),
+				Args: withConnectionArgs(gql.FieldConfigArgument{
+					"status": &gql.ArgumentConfig{
+						Type: gql.String,
+					},
+				}),
+				Resolve: func(params gql.ResolveParams) (interface{}, error) {
+					args, err := connectionArgsOf(params.Args)
+					if err != nil {
+						return nil, err
+					}
+					filter := ibc_channel_view.IBCChannelsListFilter{}
+					if status, ok := params.Args["status"].(string); ok {
+						filter.MaybeStatus = &status
+					}
+					return listConnection(args, func(pagination *pagination.Pagination) ([]ibc_channel_view.IBCChannelRow, *pagination.Result, error) {
+						return builder.views.IBCChannels.List(ibc_channel_view.IBCChannelsListOrder{}, filter, pagination)
+					})
+				},
+			},
+		},
+	})
+}
+
+// nullIfNotFound resolves the rows missing from the views to null
+func nullIfNotFound[T any](row *T, err error) (*T, error) {
+	if errors.Is(err, rdb.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
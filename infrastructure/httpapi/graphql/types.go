@@ -0,0 +1,112 @@
+package graphql
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	gql "github.com/graphql-go/graphql"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/view"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+)
+
+// fieldOf creates a field resolved from its source of type *T. The default resolver of the library
+// does not see through the embedded structs of the view rows, so that every field is explicit.
+func fieldOf[T any](fieldType gql.Output, value func(source *T) interface{}) *gql.Field {
+	return &gql.Field{
+		Type: fieldType,
+		Resolve: func(params gql.ResolveParams) (interface{}, error) {
+			source, ok := params.Source.(*T)
+			if !ok {
+				return nil, fmt.Errorf("unexpected source %T", params.Source)
+			}
+			return value(source), nil
+		},
+	}
+}
+
+// jsonType serializes the structured values of the views, e.g. coins and messages, as they are in
+// the REST API
+var jsonType = gql.NewScalar(gql.ScalarConfig{
+	Name:        "JSON",
+	Description: "A value serialized as in the REST API",
+	Serialize: func(value interface{}) interface{} {
+		encoded, err := jsoniter.Marshal(value)
+		if err != nil {
+			return nil
+		}
+		var decoded interface{}
+		if err := jsoniter.Unmarshal(encoded, &decoded); err != nil {
+			return nil
+		}
+		return decoded
+	},
+})
+
+var orderType = gql.NewEnum(gql.EnumConfig{
+	Name: "Order",
+	Values: gql.EnumValueConfigMap{
+		"ASC": &gql.EnumValueConfig{
+			Value: view.ORDER_ASC,
+		},
+		"DESC": &gql.EnumValueConfig{
+			Value: view.ORDER_DESC,
+		},
+	},
+})
+
+func orderOf(args map[string]interface{}) view.ORDER {
+	if order, ok := args["order"].(view.ORDER); ok {
+		return order
+	}
+	return view.ORDER_DESC
+}
+
+// timeOf formats the time as RFC 3339 in UTC
+func timeOf(t utctime.UTCTime) string {
+	return time.Unix(0, t.UnixNano()).UTC().Format(time.RFC3339Nano)
+}
+
+func maybeTimeOf(t *utctime.UTCTime) interface{} {
+	if t == nil {
+		return nil
+	}
+	return timeOf(*t)
+}
+
+func maybeStringOf(value *string) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func maybeInt64Of(value *int64) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func bigIntOf(value *big.Int) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value.String()
+}
+
+func bigFloatOf(value *big.Float) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value.String()
+}
+
+func nonEmptyStringOf(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,102 @@
+package graphql
+
+import (
+	"github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	account_view "github.com/AstraProtocol/astra-indexing/projection/account/view"
+	account_transaction_view "github.com/AstraProtocol/astra-indexing/projection/account_transaction/view"
+	block_view "github.com/AstraProtocol/astra-indexing/projection/block/view"
+	ibc_channel_view "github.com/AstraProtocol/astra-indexing/projection/ibc_channel/view"
+	proposal_view "github.com/AstraProtocol/astra-indexing/projection/proposal/view"
+	transaction_view "github.com/AstraProtocol/astra-indexing/projection/transaction/view"
+	validator_view "github.com/AstraProtocol/astra-indexing/projection/validator/view"
+)
+
+// The readers of the projection views the schema is resolved from
+
+type BlocksView interface {
+	FindBy(identity *block_view.BlockIdentity) (*block_view.Block, error)
+	ListByHeights(heights []int64) ([]block_view.Block, error)
+	List(order block_view.BlocksListOrder, pagination *pagination.Pagination) ([]block_view.Block, *pagination.Result, error)
+}
+
+type TransactionsView interface {
+	FindByHash(txHash string) (*transaction_view.TransactionRow, error)
+	List(
+		filter transaction_view.TransactionsListFilter,
+		order transaction_view.TransactionsListOrder,
+		pagination *pagination.Pagination,
+	) ([]transaction_view.TransactionRow, *pagination.Result, error)
+}
+
+type AccountsView interface {
+	FindBy(identity *account_view.AccountIdentity) (*account_view.AccountRow, error)
+}
+
+type AccountTransactionsView interface {
+	List(
+		filter account_transaction_view.AccountTransactionsListFilter,
+		order account_transaction_view.AccountTransactionsListOrder,
+		pagination *pagination.Pagination,
+	) ([]account_transaction_view.AccountTransactionReadRow, *pagination.Result, error)
+}
+
+type ValidatorsView interface {
+	FindBy(identity validator_view.ValidatorIdentity) (*validator_view.ValidatorRow, error)
+	List(
+		filter validator_view.ValidatorsListFilter,
+		order validator_view.ValidatorsListOrder,
+		pagination *pagination.Pagination,
+	) ([]validator_view.ListValidatorRow, *pagination.Result, error)
+}
+
+type ProposalsView interface {
+	FindById(proposalId string) (*proposal_view.ProposalWithMonikerRow, error)
+	List(
+		filter proposal_view.ProposalListFilter,
+		order proposal_view.ProposalListOrder,
+		pagination *pagination.Pagination,
+	) ([]proposal_view.ProposalWithMonikerRow, *pagination.Result, error)
+}
+
+type VotesView interface {
+	ListByProposalId(
+		proposalId string,
+		order proposal_view.VoteListOrder,
+		filters proposal_view.Filters,
+		pagination *pagination.Pagination,
+	) ([]proposal_view.VoteWithMonikerRow, *pagination.Result, error)
+}
+
+type IBCChannelsView interface {
+	FindBy(channelID string) (*ibc_channel_view.IBCChannelRow, error)
+	List(
+		order ibc_channel_view.IBCChannelsListOrder,
+		filter ibc_channel_view.IBCChannelsListFilter,
+		pagination *pagination.Pagination,
+	) ([]ibc_channel_view.IBCChannelRow, *pagination.Result, error)
+}
+
+type Views struct {
+	Blocks              BlocksView
+	Transactions        TransactionsView
+	Accounts            AccountsView
+	AccountTransactions AccountTransactionsView
+	Validators          ValidatorsView
+	Proposals           ProposalsView
+	Votes               VotesView
+	IBCChannels         IBCChannelsView
+}
+
+func NewViews(rdbHandle *rdb.Handle) Views {
+	return Views{
+		Blocks:              block_view.NewBlocks(rdbHandle),
+		Transactions:        transaction_view.NewTransactionsView(rdbHandle),
+		Accounts:            account_view.NewAccountsView(rdbHandle),
+		AccountTransactions: account_transaction_view.NewAccountTransactions(rdbHandle),
+		Validators:          validator_view.NewValidators(rdbHandle),
+		Proposals:           proposal_view.NewProposalsView(rdbHandle),
+		Votes:               proposal_view.NewVotesView(rdbHandle),
+		IBCChannels:         ibc_channel_view.NewIBCChannelsView(rdbHandle),
+	}
+}
@@ -41,7 +41,7 @@ type AccountTransactions struct {
 	accountGasUsedTotalView      *account_transaction_view.AccountGasUsedTotal
 	accountFeesTotalView         *account_transaction_view.AccountFeesTotal
 
-	astraCache *cache.AstraCache
+	astraCache cache.Cache
 	evmUtil    evm_utils.EvmUtils
 }
 
@@ -226,7 +226,8 @@ func (handler *AccountTransactions) ListByAccount(ctx *fasthttp.RequestCtx) {
 	startTime := time.Now()
 	recordMethod := "ListTxsByAccount"
 
-	pagination, err := httpapi.ParsePagination(ctx)
+	// Account transactions are paginated by id
+	pagination, err := httpapi.ParsePaginationWithCursor(ctx, httpapi.CURSOR_KEY_INTEGER)
 	if err != nil {
 		handler.logger.Errorf("invalid %s params", recordMethod)
 		prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(fasthttp.StatusBadRequest), "GET", time.Since(startTime).Milliseconds())
@@ -340,8 +341,9 @@ func (handler *AccountTransactions) ListByAccount(ctx *fasthttp.RequestCtx) {
 		Id: idOrder,
 	}
 
-	cacheKeyResult := fmt.Sprintf(
-		"ListByAccountResult%s%s%s%s%s%s%s%s%d%d",
+	// The cursors of the pagination are specific to the list
+	cacheKeyParams := fmt.Sprintf(
+		"%s%s%s%s%s%s%s%s%s",
 		account,
 		memo,
 		includingInternalTx,
@@ -350,14 +352,10 @@ func (handler *AccountTransactions) ListByAccount(ctx *fasthttp.RequestCtx) {
 		fromDate,
 		toDate,
 		status,
-		pagination.OffsetParams().Page,
-		pagination.OffsetParams().Limit,
-	)
-	cacheKeyPagination := fmt.Sprintf(
-		"ListByAccountPagination%d%d",
-		pagination.OffsetParams().Page,
-		pagination.OffsetParams().Limit,
+		pagination.Key(),
 	)
+	cacheKeyResult := "ListByAccountResult" + cacheKeyParams
+	cacheKeyPagination := "ListByAccountPagination" + cacheKeyParams
 	var resultCache interface{}
 	var paginationCache *pagination_interface.Result
 
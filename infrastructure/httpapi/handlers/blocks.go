@@ -44,7 +44,7 @@ type Blocks struct {
 	transactionsView              transaction_view.BlockTransactions
 	blockEventsView               *blockevent_view.BlockEvents
 	validatorBlockCommitmentsView *validator_view.ValidatorBlockCommitments
-	astraCache                    *cache.AstraCache
+	astraCache                    cache.Cache
 	astraLocalCache               *cache.AstraLocalCache
 	cosmosClient                  cosmosapp.Client
 	blockscoutClient              blockscout_infrastructure.HTTPClient
@@ -116,24 +116,23 @@ func (handler *Blocks) FindBy(ctx *fasthttp.RequestCtx) {
 }
 
 func getKeyPagination(pagination *pagination.Pagination, heightOrder view.ORDER) string {
-	return fmt.Sprintf("pagination_%d_%d_%s", pagination.OffsetParams().Page, pagination.OffsetParams().Limit, heightOrder)
+	return fmt.Sprintf("pagination_%s_%s", pagination.Key(), heightOrder)
 }
 
 func getKeyPaginationByHeight(pagination *pagination.Pagination, heightOrder view.ORDER, blockHeight int64) string {
-	return fmt.Sprintf("%d_pagination_%d_%d_%s", blockHeight, pagination.OffsetParams().Page, pagination.OffsetParams().Limit, heightOrder)
+	return fmt.Sprintf("%d_pagination_%s_%s", blockHeight, pagination.Key(), heightOrder)
 }
 
-func (handler *Blocks) List(ctx *fasthttp.RequestCtx) {
-	startTime := time.Now()
-	recordMethod := "ListBlocks"
-	paginationInput, err := httpapi.ParsePagination(ctx)
-	if err != nil {
-		handler.logger.Errorf("invalid %s params", recordMethod)
-		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+// limitPagination caps the limit, and the page of the offset pagination, which scans all the rows of
+// the pages before
+func limitPagination(paginationInput *pagination.Pagination) {
+	if cursorParams := paginationInput.CursorParams(); cursorParams != nil {
+		if cursorParams.Limit > pagination.MAX_LIMIT {
+			cursorParams.Limit = pagination.MAX_LIMIT
+		}
 		return
 	}
 
-	//limit pagination
 	if paginationInput.OffsetParams().Limit > pagination.MAX_LIMIT {
 		paginationInput.OffsetParams().Limit = pagination.MAX_LIMIT
 	}
@@ -142,6 +141,21 @@ func (handler *Blocks) List(ctx *fasthttp.RequestCtx) {
 	if paginationInput.OffsetParams().Page > maxPage {
 		paginationInput.OffsetParams().Page = maxPage
 	}
+}
+
+func (handler *Blocks) List(ctx *fasthttp.RequestCtx) {
+	startTime := time.Now()
+	recordMethod := "ListBlocks"
+	// Blocks are paginated by height
+	paginationInput, err := httpapi.ParsePaginationWithCursor(ctx, httpapi.CURSOR_KEY_INTEGER)
+	if err != nil {
+		handler.logger.Errorf("invalid %s params", recordMethod)
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+
+	//limit pagination
+	limitPagination(paginationInput)
 
 	heightOrder := view.ORDER_ASC
 	queryArgs := ctx.QueryArgs()
@@ -211,7 +225,8 @@ func (handler *Blocks) EthBlockNumber(ctx *fasthttp.RequestCtx) {
 func (handler *Blocks) ListTransactionsByHeight(ctx *fasthttp.RequestCtx) {
 	startTime := time.Now()
 	recordMethod := "ListTransactionsByHeight"
-	paginationInput, err := httpapi.ParsePagination(ctx)
+	// Transactions are paginated by block height and id
+	paginationInput, err := httpapi.ParsePaginationWithCursor(ctx, httpapi.CURSOR_KEY_INTEGER, httpapi.CURSOR_KEY_INTEGER)
 	if err != nil {
 		handler.logger.Errorf("invalid %s params", recordMethod)
 		prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(fasthttp.StatusBadRequest), "GET", time.Since(startTime).Milliseconds())
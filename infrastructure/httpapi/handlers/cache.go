@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/AstraProtocol/astra-indexing/external/cache"
+	"github.com/AstraProtocol/astra-indexing/infrastructure"
+)
+
+// invalidatedCacheExpiration is the expiration of the responses evicted by the projections when the
+// indexed data changes. The projections indexing in another process evict them only from a shared
+// cache, so a local cache keeps them for a short time.
+func invalidatedCacheExpiration(astraCache cache.Cache) time.Duration {
+	if cache.IsShared(astraCache) {
+		return infrastructure.TIME_CACHE_LONG
+	}
+	return infrastructure.TIME_CACHE_FAST
+}
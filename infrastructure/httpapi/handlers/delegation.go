@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/external/cache"
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/external/tmcosmosutils"
+	"github.com/AstraProtocol/astra-indexing/infrastructure"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi"
+	evm_utils "github.com/AstraProtocol/astra-indexing/internal/evm"
+	delegation_view "github.com/AstraProtocol/astra-indexing/projection/delegation/view"
+	"github.com/AstraProtocol/astra-indexing/usecase/coin"
+)
+
+type Delegation struct {
+	logger applogger.Logger
+
+	validatorAddressPrefix string
+
+	validatorsView           delegation_view.Validators
+	delegationsView          delegation_view.Delegations
+	unbondingDelegationsView delegation_view.UnbondingDelegations
+	redelegationsView        delegation_view.Redelegations
+	delegationEventsView     delegation_view.DelegationEvents
+	astraCache               cache.Cache
+}
+
+func NewDelegation(
+	logger applogger.Logger,
+	validatorAddressPrefix string,
+	rdbHandle *rdb.Handle,
+) *Delegation {
+	return &Delegation{
+		logger.WithFields(applogger.LogFields{
+			"module": "DelegationHandler",
+		}),
+
+		validatorAddressPrefix,
+
+		delegation_view.NewValidatorsView(rdbHandle),
+		delegation_view.NewDelegationsView(rdbHandle),
+		delegation_view.NewUnbondingDelegationsView(rdbHandle),
+		delegation_view.NewRedelegationsView(rdbHandle),
+		delegation_view.NewDelegationEventsView(rdbHandle),
+		cache.NewCache(),
+	}
+}
+
+func (handler *Delegation) FindPortfolioByDelegator(ctx *fasthttp.RequestCtx) {
+	delegatorAddress, ok := handler.delegatorAddress(ctx)
+	if !ok {
+		return
+	}
+
+	cacheKey := fmt.Sprintf("delegation_FindPortfolioByDelegator_%s", delegatorAddress)
+	var tmpPortfolio DelegatorPortfolio
+	if err := handler.astraCache.Get(cacheKey, &tmpPortfolio); err == nil {
+		httpapi.Success(ctx, tmpPortfolio)
+		return
+	}
+
+	delegations, err := handler.delegationsView.ListByDelegatorAddress(delegatorAddress)
+	if err != nil {
+		handler.logger.Errorf("error listing delegations of delegator: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+	unbondingDelegations, err := handler.unbondingDelegationsView.ListPendingByDelegatorAddress(delegatorAddress)
+	if err != nil {
+		handler.logger.Errorf("error listing unbonding delegations of delegator: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+	redelegations, err := handler.redelegationsView.ListPendingByDelegatorAddress(delegatorAddress)
+	if err != nil {
+		handler.logger.Errorf("error listing redelegations of delegator: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	totalDelegated := coin.ZeroInt()
+	for _, delegation := range delegations {
+		totalDelegated = totalDelegated.Add(delegation.Balance)
+	}
+	totalUnbonding := coin.ZeroInt()
+	for _, unbondingDelegation := range unbondingDelegations {
+		totalUnbonding = totalUnbonding.Add(unbondingDelegation.Balance)
+	}
+
+	portfolio := DelegatorPortfolio{
+		DelegatorAddress:     delegatorAddress,
+		TotalDelegated:       totalDelegated,
+		TotalUnbonding:       totalUnbonding,
+		Delegations:          delegations,
+		UnbondingDelegations: unbondingDelegations,
+		Redelegations:        redelegations,
+	}
+
+	_ = handler.astraCache.Set(cacheKey, portfolio, infrastructure.TIME_CACHE_FAST)
+	httpapi.Success(ctx, portfolio)
+}
+
+func (handler *Delegation) ListEventsByDelegator(ctx *fasthttp.RequestCtx) {
+	paginationInput, err := httpapi.ParsePagination(ctx)
+	if err != nil {
+		httpapi.BadRequest(ctx, err)
+		return
+	}
+	order, ok := parseHeightOrder(ctx)
+	if !ok {
+		return
+	}
+
+	delegatorAddress, ok := handler.delegatorAddress(ctx)
+	if !ok {
+		return
+	}
+
+	cacheKey := fmt.Sprintf(
+		"delegation_ListEventsByDelegator_%s_%s_%s", delegatorAddress, paginationInput.Key(), order,
+	)
+	var tmpDelegationEvents DelegationEventRowsPaginationResult
+	if err = handler.astraCache.Get(cacheKey, &tmpDelegationEvents); err == nil {
+		httpapi.SuccessWithPagination(ctx, tmpDelegationEvents.DelegationEventRows, &tmpDelegationEvents.PaginationResult)
+		return
+	}
+
+	delegationEvents, paginationResult, err := handler.delegationEventsView.ListByDelegatorAddress(
+		delegatorAddress,
+		delegation_view.DelegationEventsListOrder{Height: order},
+		paginationInput,
+	)
+	if err != nil {
+		handler.logger.Errorf("error listing delegation events of delegator: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	_ = handler.astraCache.Set(cacheKey, DelegationEventRowsPaginationResult{
+		delegationEvents, *paginationResult,
+	}, infrastructure.TIME_CACHE_FAST)
+	httpapi.SuccessWithPagination(ctx, delegationEvents, paginationResult)
+}
+
+func (handler *Delegation) ListDelegationsByValidator(ctx *fasthttp.RequestCtx) {
+	paginationInput, err := httpapi.ParsePagination(ctx)
+	if err != nil {
+		httpapi.BadRequest(ctx, err)
+		return
+	}
+
+	addressParams, addressParamsOk := URLValueGuard(ctx, handler.logger, "address")
+	if !addressParamsOk {
+		return
+	}
+	if !strings.HasPrefix(addressParams, handler.validatorAddressPrefix) {
+		httpapi.BadRequest(ctx, errors.New("invalid address"))
+		return
+	}
+
+	cacheKey := fmt.Sprintf("delegation_ListDelegationsByValidator_%s_%s", addressParams, paginationInput.Key())
+	var tmpDelegations DelegationRowsPaginationResult
+	if err = handler.astraCache.Get(cacheKey, &tmpDelegations); err == nil {
+		httpapi.SuccessWithPagination(ctx, tmpDelegations.DelegationRows, &tmpDelegations.PaginationResult)
+		return
+	}
+
+	if _, err = handler.validatorsView.FindBy(delegation_view.ValidatorIdentity{
+		MaybeOperatorAddress: &addressParams,
+	}); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			httpapi.NotFound(ctx)
+			return
+		}
+		handler.logger.Errorf("error finding delegation validator: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	delegations, paginationResult, err := handler.delegationsView.ListByValidatorAddress(addressParams, paginationInput)
+	if err != nil {
+		handler.logger.Errorf("error listing delegations of validator: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	_ = handler.astraCache.Set(cacheKey, DelegationRowsPaginationResult{
+		delegations, *paginationResult,
+	}, infrastructure.TIME_CACHE_FAST)
+	httpapi.SuccessWithPagination(ctx, delegations, paginationResult)
+}
+
+// delegatorAddress returns the delegator address in the URL, converting EVM addresses to their account address,
+// and writes the error response when it is invalid
+func (handler *Delegation) delegatorAddress(ctx *fasthttp.RequestCtx) (string, bool) {
+	accountParam, accountParamOk := URLValueGuard(ctx, handler.logger, "account")
+	if !accountParamOk {
+		return "", false
+	}
+
+	if evm_utils.IsHexAddress(accountParam) {
+		converted, _ := hex.DecodeString(accountParam[2:])
+		astraAddress, err := tmcosmosutils.EncodeHexToAddress("astra", converted)
+		if err != nil {
+			httpapi.BadRequest(ctx, errors.New("invalid account param"))
+			return "", false
+		}
+		return astraAddress, true
+	}
+	if !tmcosmosutils.IsValidCosmosAddress(accountParam) {
+		httpapi.BadRequest(ctx, errors.New("invalid account param"))
+		return "", false
+	}
+
+	return accountParam, true
+}
+
+// DelegatorPortfolio is the tokens currently delegated by a delegator, worth the shares of its delegations, and its
+// unbonding delegations and redelegations still pending
+type DelegatorPortfolio struct {
+	DelegatorAddress     string                                   `json:"delegatorAddress"`
+	TotalDelegated       coin.Int                                 `json:"totalDelegated"`
+	TotalUnbonding       coin.Int                                 `json:"totalUnbonding"`
+	Delegations          []delegation_view.DelegationRow          `json:"delegations"`
+	UnbondingDelegations []delegation_view.UnbondingDelegationRow `json:"unbondingDelegations"`
+	Redelegations        []delegation_view.RedelegationRow        `json:"redelegations"`
+}
+
+type DelegationEventRowsPaginationResult struct {
+	DelegationEventRows []delegation_view.DelegationEventRow `json:"delegationEventRows"`
+	PaginationResult    pagination.Result                    `json:"paginationResult"`
+}
+
+type DelegationRowsPaginationResult struct {
+	DelegationRows   []delegation_view.DelegationRow `json:"delegationRows"`
+	PaginationResult pagination.Result               `json:"paginationResult"`
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/valyala/fasthttp"
+
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/graphql"
+)
+
+type GraphQL struct {
+	logger applogger.Logger
+
+	executor *graphql.Executor
+}
+
+func NewGraphQL(logger applogger.Logger, executor *graphql.Executor) *GraphQL {
+	return &GraphQL{
+		logger.WithFields(applogger.LogFields{
+			"module": "GraphQLHandler",
+		}),
+
+		executor,
+	}
+}
+
+// Query executes the query of a POST JSON body `{"query", "variables", "operationName"}`, or of the
+// query params of the same names of a GET request. The errors of the query are reported in the
+// `errors` of the response, as the GraphQL specification requires.
+func (handler *GraphQL) Query(ctx *fasthttp.RequestCtx) {
+	var request graphql.Request
+	if ctx.IsPost() {
+		if err := jsoniter.Unmarshal(ctx.PostBody(), &request); err != nil {
+			httpapi.BadRequest(ctx, errors.New("invalid GraphQL request body"))
+			return
+		}
+	} else {
+		queryArgs := ctx.QueryArgs()
+		request.Query = string(queryArgs.Peek("query"))
+		request.OperationName = string(queryArgs.Peek("operationName"))
+		if variables := queryArgs.Peek("variables"); len(variables) > 0 {
+			if err := jsoniter.Unmarshal(variables, &request.Variables); err != nil {
+				httpapi.BadRequest(ctx, errors.New("invalid variables param"))
+				return
+			}
+		}
+	}
+	if request.Query == "" {
+		httpapi.BadRequest(ctx, errors.New("missing GraphQL query"))
+		return
+	}
+
+	result := handler.executor.Execute(ctx, request)
+	if result.HasErrors() {
+		handler.logger.Debugf("GraphQL query errors: %v", result.Errors)
+	}
+	httpapi.SuccessNotWrappedResult(ctx, result)
+}
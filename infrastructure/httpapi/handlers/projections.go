@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+
+	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi"
+)
+
+type Projections struct {
+	logger applogger.Logger
+
+	rebuilder projection_entity.Rebuilder
+}
+
+func NewProjections(logger applogger.Logger, rebuilder projection_entity.Rebuilder) *Projections {
+	return &Projections{
+		logger.WithFields(applogger.LogFields{
+			"module": "ProjectionsHandler",
+		}),
+
+		rebuilder,
+	}
+}
+
+// Rebuild resets the projection and replays the events from the `fromHeight` query param, which
+// is the genesis by default. It returns once the projection is reset, the replay then runs in the
+// background together with the other projections.
+func (handler *Projections) Rebuild(ctx *fasthttp.RequestCtx) {
+	projectionId, projectionIdOk := URLValueGuard(ctx, handler.logger, "id")
+	if !projectionIdOk {
+		return
+	}
+
+	fromHeight := int64(0)
+	queryArgs := ctx.QueryArgs()
+	if queryArgs.Has("fromHeight") {
+		var err error
+		fromHeight, err = strconv.ParseInt(string(queryArgs.Peek("fromHeight")), 10, 64)
+		if err != nil || fromHeight < 0 {
+			httpapi.BadRequest(ctx, errors.New("invalid fromHeight param"))
+			return
+		}
+	}
+
+	if err := handler.rebuilder.Rebuild(projectionId, fromHeight); err != nil {
+		if errors.Is(err, projection_entity.ErrProjectionNotRegistered) {
+			httpapi.NotFound(ctx)
+			return
+		}
+		if errors.Is(err, projection_entity.ErrProjectionNotRebuildable) ||
+			errors.Is(err, projection_entity.ErrProjectionNotRebuildableFromHeight) {
+			httpapi.BadRequest(ctx, err)
+			return
+		}
+		handler.logger.Errorf("error rebuilding projection %s: %v", projectionId, err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	httpapi.Success(ctx, map[string]interface{}{
+		"projection": projectionId,
+		"fromHeight": fromHeight,
+	})
+}
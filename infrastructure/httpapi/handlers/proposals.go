@@ -36,7 +36,7 @@ type Proposals struct {
 
 	totalBonded              coin.Coin
 	totalBondedLastUpdatedAt time.Time
-	astraCache               *cache.AstraCache
+	astraCache               cache.Cache
 }
 
 func NewProposals(logger applogger.Logger, rdbHandle *rdb.Handle, cosmosClient cosmosapp.Client) *Proposals {
@@ -100,6 +100,8 @@ func (handler *Proposals) FindById(ctx *fasthttp.RequestCtx) {
 	}
 
 	tally := cosmosapp.Tally{}
+	// The final tally is indexed, the tally of a proposal being voted is queried from the node
+	expiration := invalidatedCacheExpiration(handler.astraCache)
 	if proposal.Tally != nil {
 		tallyMap := proposal.Tally.(map[string]interface{})
 		tally.No = tallyMap["no"].(string)
@@ -113,6 +115,10 @@ func (handler *Proposals) FindById(ctx *fasthttp.RequestCtx) {
 			tally.Yes = "0"
 			tally.Abstain = "0"
 			tally.NoWithVeto = "0"
+			// The tally defaulted on errors is not cached
+			expiration = 0
+		} else {
+			expiration = infrastructure.TIME_CACHE_FAST
 		}
 	}
 
@@ -184,7 +190,9 @@ func (handler *Proposals) FindById(ctx *fasthttp.RequestCtx) {
 			NoWithVeto: tally.NoWithVeto,
 		},
 	}
-	handler.astraCache.Set(proposalKey, proposalDetails, infrastructure.TIME_CACHE_FAST)
+	if expiration > 0 {
+		handler.astraCache.Set(proposalKey, proposalDetails, expiration, proposal_view.ProposalCacheTag(idParam))
+	}
 	prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(200), "GET", time.Since(startTime).Milliseconds())
 	httpapi.Success(ctx, proposalDetails)
 }
@@ -208,7 +216,7 @@ func (handler *Proposals) List(ctx *fasthttp.RequestCtx) {
 			idOrder = view.ORDER_DESC
 		}
 	}
-	proposalKey := "Proposals_" + idOrder
+	proposalKey := "Proposals_" + pagePagination.Key() + "_" + idOrder
 	filter := proposal_view.ProposalListFilter{
 		MaybeStatus:          nil,
 		MaybeProposerAddress: nil,
@@ -241,7 +249,8 @@ func (handler *Proposals) List(ctx *fasthttp.RequestCtx) {
 		httpapi.InternalServerError(ctx)
 		return
 	}
-	handler.astraCache.Set(proposalKey, NewProposalPaginationResult(proposals, *paginationResult), infrastructure.TIME_CACHE_FAST)
+	handler.astraCache.Set(proposalKey, NewProposalPaginationResult(proposals, *paginationResult), invalidatedCacheExpiration(handler.astraCache),
+		proposal_view.PROPOSALS_CACHE_TAG)
 	prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(200), "GET", time.Since(startTime).Milliseconds())
 	httpapi.SuccessWithPagination(ctx, proposals, paginationResult)
 }
@@ -291,7 +300,7 @@ func (handler *Proposals) ListVotesById(ctx *fasthttp.RequestCtx) {
 		filters.Address = string(queryArgs.Peek("voterAddress"))
 	}
 
-	voteCacheKey := fmt.Sprintf("voteById_%s_%s_%s", idParam, voteAtOrder, filters.ToStr())
+	voteCacheKey := fmt.Sprintf("voteById_%s_%s_%s_%s", idParam, parsePagination.Key(), voteAtOrder, filters.ToStr())
 	var tmpVoteCache VotesPaginationResult
 	err := handler.astraCache.Get(voteCacheKey, &tmpVoteCache)
 	if err == nil {
@@ -309,7 +318,8 @@ func (handler *Proposals) ListVotesById(ctx *fasthttp.RequestCtx) {
 		httpapi.InternalServerError(ctx)
 		return
 	}
-	handler.astraCache.Set(voteCacheKey, NewVotesPaginationResult(votes, *paginationResult), infrastructure.TIME_CACHE_FAST)
+	handler.astraCache.Set(voteCacheKey, NewVotesPaginationResult(votes, *paginationResult), invalidatedCacheExpiration(handler.astraCache),
+		proposal_view.ProposalCacheTag(idParam))
 	prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(200), "GET", time.Since(startTime).Milliseconds())
 	httpapi.SuccessWithPagination(ctx, votes, paginationResult)
 }
@@ -357,7 +367,7 @@ func (handler *Proposals) ListDepositorsById(ctx *fasthttp.RequestCtx) {
 		filters.Address = string(queryArgs.Peek("depositorAddress"))
 	}
 
-	depositCacheKey := fmt.Sprintf("depositById_%s_%s_%s", idParam, depositAtOrder, filters.ToStr())
+	depositCacheKey := fmt.Sprintf("depositById_%s_%s_%s_%s", idParam, parsePagination.Key(), depositAtOrder, filters.ToStr())
 	var tmpDepositorCache DepositPaginationResult
 
 	err := handler.astraCache.Get(depositCacheKey, &tmpDepositorCache)
@@ -376,7 +386,8 @@ func (handler *Proposals) ListDepositorsById(ctx *fasthttp.RequestCtx) {
 		httpapi.InternalServerError(ctx)
 		return
 	}
-	handler.astraCache.Set(depositCacheKey, NewDepositPaginationResult(depositors, *paginationResult), infrastructure.TIME_CACHE_FAST)
+	handler.astraCache.Set(depositCacheKey, NewDepositPaginationResult(depositors, *paginationResult), invalidatedCacheExpiration(handler.astraCache),
+		proposal_view.ProposalCacheTag(idParam))
 	prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(200), "GET", time.Since(startTime).Milliseconds())
 	httpapi.SuccessWithPagination(ctx, depositors, paginationResult)
 }
@@ -24,7 +24,7 @@ type ReportDashboardHandler struct {
 	reportDashboardView   *report_dashboard_view.ReportDashboard
 	transactionsTotalView transaction_view.TransactionsTotal
 	accountsView          account_view.Accounts
-	astraCache            *cache.AstraCache
+	astraCache            cache.Cache
 }
 
 func NewReportDashboardHandler(
@@ -16,22 +16,36 @@ import (
 	"github.com/AstraProtocol/astra-indexing/infrastructure/metric/prometheus"
 	evm_utils "github.com/AstraProtocol/astra-indexing/internal/evm"
 	account_view "github.com/AstraProtocol/astra-indexing/projection/account/view"
-	account_transaction_view "github.com/AstraProtocol/astra-indexing/projection/account_transaction/view"
 	block_view "github.com/AstraProtocol/astra-indexing/projection/block/view"
+	search_view "github.com/AstraProtocol/astra-indexing/projection/search/view"
 	transaction_view "github.com/AstraProtocol/astra-indexing/projection/transaction/view"
 	validator_view "github.com/AstraProtocol/astra-indexing/projection/validator/view"
 	"github.com/valyala/fasthttp"
 )
 
+// Maximum number of search entries and of labelled accounts returned by a search
+const SEARCH_RESULTS_LIMIT = 20
+
+const DEFAULT_SEARCH_BLOCKSCOUT_TIMEOUT = time.Second
+
+type SearchConfig struct {
+	// Searches Blockscout concurrently to add the contracts, the address names and the tokens to the results
+	BlockscoutEnrichment bool
+	// Blockscout results received later are dropped
+	BlockscoutTimeout time.Duration
+}
+
 type Search struct {
-	logger                       applogger.Logger
-	blockscoutClient             blockscout_infrastructure.HTTPClient
-	cosmosClient                 cosmosapp.Client
-	blocksView                   *block_view.Blocks
-	transactionsView             transaction_view.BlockTransactions
-	validatorsView               *validator_view.Validators
-	accountsView                 account_view.Accounts
-	accountTransactionsTotalView *account_transaction_view.AccountTransactionsTotal
+	logger            applogger.Logger
+	blockscoutClient  blockscout_infrastructure.HTTPClient
+	cosmosClient      cosmosapp.Client
+	blocksView        *block_view.Blocks
+	transactionsView  transaction_view.BlockTransactions
+	validatorsView    *validator_view.Validators
+	accountsView      account_view.Accounts
+	searchEntriesView search_view.SearchEntries
+
+	config SearchConfig
 }
 
 type SearchResults struct {
@@ -43,7 +57,17 @@ type SearchResults struct {
 	Contracts    []blockscout_infrastructure.ContractResult    `json:"contracts"`
 }
 
-func NewSearch(logger applogger.Logger, blockscoutClient blockscout_infrastructure.HTTPClient, cosmosClient cosmosapp.Client, rdbHandle *rdb.Handle) *Search {
+func NewSearch(
+	logger applogger.Logger,
+	blockscoutClient blockscout_infrastructure.HTTPClient,
+	cosmosClient cosmosapp.Client,
+	rdbHandle *rdb.Handle,
+	config SearchConfig,
+) *Search {
+	if config.BlockscoutTimeout <= 0 {
+		config.BlockscoutTimeout = DEFAULT_SEARCH_BLOCKSCOUT_TIMEOUT
+	}
+
 	return &Search{
 		logger.WithFields(applogger.LogFields{
 			"module": "SearchHandler",
@@ -54,179 +78,251 @@ func NewSearch(logger applogger.Logger, blockscoutClient blockscout_infrastructu
 		transaction_view.NewTransactionsView(rdbHandle),
 		validator_view.NewValidators(rdbHandle),
 		account_view.NewAccountsView(rdbHandle),
-		account_transaction_view.NewAccountTransactionsTotal(rdbHandle),
+		search_view.NewSearchEntriesView(rdbHandle),
+
+		config,
 	}
 }
 
+// Search answers from the local search entries and account names, ranked by the views. Blockscout is only searched
+// for enrichment, and its results are dropped when it does not answer in time.
 func (search *Search) Search(ctx *fasthttp.RequestCtx) {
 	startTime := time.Now()
 	recordMethod := "Search"
 
-	resultsChan := make(chan []blockscout_infrastructure.SearchResult)
-	keyword := string(ctx.QueryArgs().Peek("keyword"))
-	var results SearchResults
-
-	if tmcosmosutils.IsValidCosmosAddress(keyword) && strings.Contains(keyword, "valoper") {
-		// If keyword is validator address (e.g: "astravaloper16mqptvptnds4098cmdmz846lmazenegc270ljs")
-		// use chainindexing's validator search only
-		validators, err := search.validatorsView.Search(keyword)
-		if err != nil {
-			if errors.Is(err, rdb.ErrNoRows) {
-				validators = nil
-			} else {
-				search.logger.Errorf("error searching validator: %v", err)
-				prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(fasthttp.StatusNotFound), "GET", time.Since(startTime).Milliseconds())
-				httpapi.NotFound(ctx)
-				return
-			}
-		}
-		if len(validators) > 0 {
-			results.Validators = search.parseValidators(validators)
-			prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(200), "GET", time.Since(startTime).Milliseconds())
-			httpapi.Success(ctx, results)
-			return
-		}
+	keyword := strings.TrimSpace(string(ctx.QueryArgs().Peek("keyword")))
+	results := SearchResults{}
+	if keyword == "" {
+		prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(200), "GET", time.Since(startTime).Milliseconds())
+		httpapi.Success(ctx, results)
+		return
 	}
 
-	var blockscoutSearchParam string
+	var addressHash string
 	var astraAddress string
-
-	if tmcosmosutils.IsValidCosmosAddress(keyword) {
+	if tmcosmosutils.IsValidCosmosAddress(keyword) && !strings.Contains(keyword, "valoper") && !strings.Contains(keyword, "valcons") {
 		// If keyword is bech32 address (e.g: "astra1g9v3fp9wkhar696e7896x6wu3hqjsy5cpxdzff")
-		// Address must be converted to hex address then using blockscout's api search
+		// Address must be converted to hex address for Blockscout
 		_, converted, _ := tmcosmosutils.DecodeAddressToHex(keyword)
-		blockscoutSearchParam = "0x" + hex.EncodeToString(converted)
+		addressHash = "0x" + hex.EncodeToString(converted)
 		astraAddress = keyword
 	} else if evm_utils.IsHexAddress(keyword) {
 		// If keyword is hex address (e.g: "0x194c37D6C9B51660e4dA668bC03Ed0E86469cDEE")
-		// Address must be converted to astra address then using chainindexing search
-		blockscoutSearchParam = keyword
+		// Address must be converted to astra address for the local accounts
+		addressHash = strings.ToLower(keyword)
 		converted, _ := hex.DecodeString(keyword[2:])
 		astraAddress, _ = tmcosmosutils.EncodeHexToAddress("astra", converted)
 	}
 
-	if tmcosmosutils.IsValidCosmosAddress(keyword) || evm_utils.IsHexAddress(keyword) {
-		// Using simultaneously blockscout and chainindexing search
-		go search.blockscoutClient.GetSearchResultsAsync(blockscoutSearchParam, resultsChan)
-
-		// Using chainindexing search for astra address
-		accountIdentity := account_view.AccountIdentity{}
-		accountIdentity.Address = astraAddress
-		accounts, err := search.accountsView.FindBy(&accountIdentity)
-		if err != nil {
-			if errors.Is(err, rdb.ErrNoRows) {
-				accounts = nil
-			} else {
-				search.logger.Errorf("error searching account: %v", err)
-				prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(fasthttp.StatusNotFound), "GET", time.Since(startTime).Milliseconds())
-				httpapi.NotFound(ctx)
-				return
-			}
+	var blockscoutResultsChan chan []blockscout_infrastructure.SearchResult
+	if search.config.BlockscoutEnrichment {
+		blockscoutKeyword := keyword
+		if addressHash != "" {
+			blockscoutKeyword = addressHash
 		}
+		// Buffered so that the request does not block once its results are dropped
+		blockscoutResultsChan = make(chan []blockscout_infrastructure.SearchResult, 1)
+		go search.blockscoutClient.GetSearchResultsAsync(blockscoutKeyword, blockscoutResultsChan)
+	}
 
-		// Get blockscout's address search result from channel
-		blockscoutAddressResults := <-resultsChan
+	var err error
+	if astraAddress != "" {
+		err = search.searchAddress(astraAddress, addressHash, &results)
+	} else {
+		err = search.searchKeyword(keyword, &results)
+	}
+	if err != nil {
+		search.logger.Errorf("error searching %s: %v", keyword, err)
+		prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(fasthttp.StatusInternalServerError), "GET", time.Since(startTime).Milliseconds())
+		httpapi.InternalServerError(ctx)
+		return
+	}
 
-		if accounts != nil {
-			// Merge blockscout and chainindexing search results
-			results.Addresses = search.parseAddresses(*accounts, blockscoutAddressResults)
-		} else {
-			for _, result := range blockscoutAddressResults {
-				results.Addresses = append(results.Addresses, result.ToAddress())
-			}
+	if blockscoutResultsChan != nil {
+		select {
+		case blockscoutResults := <-blockscoutResultsChan:
+			search.enrich(&results, blockscoutResults)
+		case <-time.After(search.config.BlockscoutTimeout - time.Since(startTime)):
+			search.logger.Infof("dropping Blockscout search results of %s not received within %s", keyword, search.config.BlockscoutTimeout)
 		}
+	}
 
-		if accounts == nil {
-			account, err := search.cosmosClient.Account(astraAddress)
-			if err == nil && account.Address != "" {
-				results.Addresses[0].AddressHash = blockscoutSearchParam
-			}
-		}
+	prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(200), "GET", time.Since(startTime).Milliseconds())
+	httpapi.Success(ctx, results)
+}
 
-		prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(200), "GET", time.Since(startTime).Milliseconds())
-		httpapi.Success(ctx, results)
-		return
+func (search *Search) searchAddress(astraAddress string, addressHash string, results *SearchResults) error {
+	account, err := search.accountsView.FindBy(&account_view.AccountIdentity{Address: astraAddress})
+	if err != nil {
+		if !errors.Is(err, rdb.ErrNoRows) {
+			return err
+		}
+		// Accounts which never signed a transaction are only known by the chain
+		chainAccount, chainErr := search.cosmosClient.Account(astraAddress)
+		if chainErr != nil || chainAccount.Address == "" {
+			return nil
+		}
+		account = &account_view.AccountRow{Address: astraAddress}
 	}
 
-	// Using simultaneously blockscout and chainindexing search
-	go search.blockscoutClient.GetSearchResultsAsync(keyword, resultsChan)
+	address := blockscout_infrastructure.AddressResult{
+		AddressHash: addressHash,
+		Address:     account.Address,
+	}
+	if account.MaybeName != nil {
+		address.Name = *account.MaybeName
+	}
+	results.Addresses = append(results.Addresses, address)
+	return nil
+}
 
-	// If keyword is cosmos tx (e.g: "90FEE96EE94CA74AD67FCF155E15488B901B3AE2530EBE4D35A9E77B609EB348")
-	// use chainindexing search for cosmos tx then merge with evm tx from blockscout's search result (if exist)
-	transactions, err := search.transactionsView.Search(keyword)
+func (search *Search) searchKeyword(keyword string, results *SearchResults) error {
+	entries, err := search.searchEntriesView.Search(keyword, SEARCH_RESULTS_LIMIT)
 	if err != nil {
-		if errors.Is(err, rdb.ErrNoRows) {
-			transactions = nil
-		} else {
-			search.logger.Errorf("error searching transaction: %v", err)
-			prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(fasthttp.StatusNotFound), "GET", time.Since(startTime).Milliseconds())
-			httpapi.NotFound(ctx)
-			return
+		return err
+	}
+	for _, entry := range entries {
+		switch entry.Type {
+		case search_view.SEARCH_ENTRY_VALIDATOR:
+			validator, err := search.parseValidator(entry)
+			if err != nil {
+				return err
+			}
+			results.Validators = append(results.Validators, validator)
+		case search_view.SEARCH_ENTRY_TRANSACTION:
+			results.Transactions = append(results.Transactions, search.parseTransaction(entry))
+		case search_view.SEARCH_ENTRY_BLOCK:
+			results.Blocks = append(results.Blocks, search.parseBlock(entry))
+		case search_view.SEARCH_ENTRY_TOKEN:
+			results.Tokens = append(results.Tokens, search.parseToken(entry))
 		}
 	}
 
-	// Get blockscout's search result from channel
-	blockscoutSearchResults := <-resultsChan
-
-	if len(transactions) > 0 {
-		// merge with evm tx from blockscout's search result (if exist)
-		results.Transactions = search.parseTransactions(transactions, blockscoutSearchResults)
-		prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(200), "GET", time.Since(startTime).Milliseconds())
-		httpapi.Success(ctx, results)
-		return
+	accounts, err := search.accountsView.Search(keyword, SEARCH_RESULTS_LIMIT)
+	if err != nil {
+		return err
 	}
+	results.Addresses = append(results.Addresses, search.parseAddresses(accounts)...)
 
-	// Using blockscout's search results when chainindexing's search results are empty
-	// mostly using for token, contract, block search or in case of keyword is hex type
-	if search.isResultsEmpty(results) {
-		if len(blockscoutSearchResults) > 0 {
-			for _, result := range blockscoutSearchResults {
-				switch blockscoutSearchResults[0].Type {
-				case "token":
-					if result.AddressHash != "" {
-						results.Tokens = append(results.Tokens, result.ToToken())
-					}
-				case "block":
-					results.Blocks = append(results.Blocks, result.ToBlock())
-				case "address":
-					results.Addresses = append(results.Addresses, result.ToAddress())
-				case "contract":
-					results.Contracts = append(results.Contracts, result.ToContract())
-				case "transaction":
-					results.Transactions = append(results.Transactions, result.ToTransaction())
-				case "transaction_cosmos":
-					results.Transactions = append(results.Transactions, result.ToTransaction())
-				}
-			}
+	// Transactions of the heights not indexed yet by the Search projection
+	if results.Transactions == nil && (evm_utils.IsHexTx(keyword) || isCosmosTxHash(keyword)) {
+		transactions, err := search.transactionsView.Search(keyword)
+		if err != nil && !errors.Is(err, rdb.ErrNoRows) {
+			return err
 		}
+		results.Transactions = append(results.Transactions, search.parseTransactions(transactions)...)
 	}
 
 	// searching blocks in case of keyword is number
 	if _, err := strconv.Atoi(keyword); err == nil {
 		blocks, err := search.blocksView.Search(keyword)
-		if err == nil {
-			results.Blocks = search.parseBlocks(blocks)
+		if err != nil && !errors.Is(err, rdb.ErrNoRows) {
+			return err
 		}
+		heightBlocks := search.parseBlocks(blocks)
+		for _, block := range results.Blocks {
+			if !hasBlock(heightBlocks, block.BlockNumber) {
+				heightBlocks = append(heightBlocks, block)
+			}
+		}
+		results.Blocks = heightBlocks
 	}
 
-	prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(200), "GET", time.Since(startTime).Milliseconds())
-	httpapi.Success(ctx, results)
+	return nil
 }
 
-func (search *Search) parseValidators(data []validator_view.ValidatorRow) []blockscout_infrastructure.ValidatorResult {
-	var validators []blockscout_infrastructure.ValidatorResult
-	for _, validator_data := range data {
-		var validator blockscout_infrastructure.ValidatorResult
-		validator.Jailed = validator_data.Jailed
-		validator.Status = validator_data.Status
-		validator.ConsensusNodeAddress = validator_data.ConsensusNodeAddress
-		validator.InitialDelegatorAddress = validator_data.InitialDelegatorAddress
-		validator.Moniker = validator_data.Moniker
-		_, converted, _ := tmcosmosutils.DecodeAddressToHex(validator_data.InitialDelegatorAddress)
-		validator.InitialDelegatorAddressHash = "0x" + hex.EncodeToString(converted)
-		validators = append(validators, validator)
-	}
-	return validators
+// enrich adds the Blockscout results missing locally
+func (search *Search) enrich(results *SearchResults, blockscoutResults []blockscout_infrastructure.SearchResult) {
+	for _, result := range blockscoutResults {
+		switch result.Type {
+		case "token":
+			if result.AddressHash == "" {
+				continue
+			}
+			if !hasToken(results.Tokens, result.AddressHash) {
+				results.Tokens = append(results.Tokens, result.ToToken())
+			}
+		case "address":
+			if result.AddressHash == "" {
+				continue
+			}
+			if !nameAddress(results.Addresses, result.AddressHash, result.Name) {
+				results.Addresses = append(results.Addresses, result.ToAddress())
+			}
+		case "contract":
+			nameAddress(results.Addresses, result.AddressHash, result.Name)
+			results.Contracts = append(results.Contracts, result.ToContract())
+		case "transaction", "transaction_cosmos":
+			if !hasTransaction(results.Transactions, result.CosmosHash, result.TxHash) {
+				results.Transactions = append(results.Transactions, result.ToTransaction())
+			}
+		case "block":
+			if !hasBlock(results.Blocks, result.BlockNumber) {
+				results.Blocks = append(results.Blocks, result.ToBlock())
+			}
+		}
+	}
+}
+
+func (search *Search) parseValidator(entry search_view.SearchEntryRow) (blockscout_infrastructure.ValidatorResult, error) {
+	validator := blockscout_infrastructure.ValidatorResult{
+		OperatorAddress: entry.Key,
+		Moniker:         entry.Label,
+	}
+
+	validatorRow, err := search.validatorsView.FindBy(validator_view.ValidatorIdentity{
+		MaybeOperatorAddress: &entry.Key,
+	})
+	if err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return validator, nil
+		}
+		return validator, err
+	}
+
+	validator.Jailed = validatorRow.Jailed
+	validator.Status = validatorRow.Status
+	validator.ConsensusNodeAddress = validatorRow.ConsensusNodeAddress
+	validator.InitialDelegatorAddress = validatorRow.InitialDelegatorAddress
+	validator.Moniker = validatorRow.Moniker
+	_, converted, _ := tmcosmosutils.DecodeAddressToHex(validatorRow.InitialDelegatorAddress)
+	validator.InitialDelegatorAddressHash = "0x" + hex.EncodeToString(converted)
+	return validator, nil
+}
+
+func (search *Search) parseTransaction(entry search_view.SearchEntryRow) blockscout_infrastructure.TransactionResult {
+	transaction := blockscout_infrastructure.TransactionResult{
+		CosmosHash: entry.Key,
+		EvmHash:    entry.Attributes["evmHash"],
+	}
+	if entry.MaybeBlockTime != nil {
+		transaction.InsertedAt = *entry.MaybeBlockTime
+	}
+	return transaction
+}
+
+func (search *Search) parseBlock(entry search_view.SearchEntryRow) blockscout_infrastructure.BlockResult {
+	block := blockscout_infrastructure.BlockResult{
+		BlockHash:   entry.Label,
+		BlockNumber: int(entry.BlockHeight),
+	}
+	if entry.MaybeBlockTime != nil {
+		block.InsertedAt = *entry.MaybeBlockTime
+	}
+	return block
+}
+
+func (search *Search) parseToken(entry search_view.SearchEntryRow) blockscout_infrastructure.TokenResult {
+	token := blockscout_infrastructure.TokenResult{
+		AddressHash: entry.Key,
+		Name:        entry.Label,
+		Symbol:      entry.Attributes["symbol"],
+	}
+	token.HolderCount, _ = strconv.Atoi(entry.Attributes["holderCount"])
+	if entry.MaybeBlockTime != nil {
+		token.InsertedAt = *entry.MaybeBlockTime
+	}
+	return token
 }
 
 func (search *Search) parseBlocks(data []block_view.Block) []blockscout_infrastructure.BlockResult {
@@ -241,58 +337,78 @@ func (search *Search) parseBlocks(data []block_view.Block) []blockscout_infrastr
 	return blocks
 }
 
-func (search *Search) parseAddresses(data account_view.AccountRow, blockscout_data []blockscout_infrastructure.SearchResult) []blockscout_infrastructure.AddressResult {
+func (search *Search) parseAddresses(data []account_view.AccountRow) []blockscout_infrastructure.AddressResult {
 	var addresses []blockscout_infrastructure.AddressResult
-	var address blockscout_infrastructure.AddressResult
-	address.Address = data.Address
-	_, converted, _ := tmcosmosutils.DecodeAddressToHex(address.Address)
-	address.AddressHash = "0x" + hex.EncodeToString(converted)
-	for _, result := range blockscout_data {
-		if address.AddressHash == result.AddressHash {
-			address.Name = result.Name
+	for _, account := range data {
+		var address blockscout_infrastructure.AddressResult
+		address.Address = account.Address
+		_, converted, _ := tmcosmosutils.DecodeAddressToHex(account.Address)
+		address.AddressHash = "0x" + hex.EncodeToString(converted)
+		if account.MaybeName != nil {
+			address.Name = *account.MaybeName
 		}
+		addresses = append(addresses, address)
 	}
-	addresses = append(addresses, address)
 	return addresses
 }
 
-func (search *Search) parseTransactions(data []transaction_view.TransactionRow, blockscout_data []blockscout_infrastructure.SearchResult) []blockscout_infrastructure.TransactionResult {
+func (search *Search) parseTransactions(data []transaction_view.TransactionRow) []blockscout_infrastructure.TransactionResult {
 	var transactions []blockscout_infrastructure.TransactionResult
 	for _, transaction_data := range data {
 		var transaction blockscout_infrastructure.TransactionResult
 		transaction.CosmosHash = transaction_data.Hash
 		transaction.InsertedAt = transaction_data.BlockTime
 		transaction.EvmHash = transaction_data.EvmHash
-		if transaction.EvmHash == "" {
-			for _, result := range blockscout_data {
-				if transaction.CosmosHash == result.CosmosHash {
-					transaction.EvmHash = result.TxHash
-				}
-			}
-		}
 		transactions = append(transactions, transaction)
 	}
 	return transactions
 }
 
-func (search *Search) isResultsEmpty(results SearchResults) bool {
-	if results.Addresses != nil {
-		return false
-	}
-	if results.Blocks != nil {
-		return false
-	}
-	if results.Tokens != nil {
-		return false
+func hasToken(tokens []blockscout_infrastructure.TokenResult, addressHash string) bool {
+	for _, token := range tokens {
+		if strings.EqualFold(token.AddressHash, addressHash) {
+			return true
+		}
 	}
-	if results.Transactions != nil {
-		return false
+	return false
+}
+
+// nameAddress names the address of the results matching addressHash, unless it is already named. It returns false
+// when no address matches.
+func nameAddress(addresses []blockscout_infrastructure.AddressResult, addressHash string, name string) bool {
+	for i := range addresses {
+		if strings.EqualFold(addresses[i].AddressHash, addressHash) {
+			if addresses[i].Name == "" {
+				addresses[i].Name = name
+			}
+			return true
+		}
 	}
-	if results.Validators != nil {
-		return false
+	return false
+}
+
+func hasTransaction(transactions []blockscout_infrastructure.TransactionResult, cosmosHash string, evmHash string) bool {
+	for _, transaction := range transactions {
+		if cosmosHash != "" && strings.EqualFold(transaction.CosmosHash, cosmosHash) {
+			return true
+		}
+		if evmHash != "" && strings.EqualFold(transaction.EvmHash, evmHash) {
+			return true
+		}
 	}
-	if results.Contracts != nil {
-		return false
+	return false
+}
+
+func hasBlock(blocks []blockscout_infrastructure.BlockResult, blockNumber int) bool {
+	for _, block := range blocks {
+		if block.BlockNumber == blockNumber {
+			return true
+		}
 	}
-	return true
+	return false
+}
+
+func isCosmosTxHash(value string) bool {
+	_, err := hex.DecodeString(value)
+	return err == nil && len(value) == 64
 }
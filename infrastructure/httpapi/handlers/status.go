@@ -17,6 +17,7 @@ import (
 	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
 	blockscout_infrastructure "github.com/AstraProtocol/astra-indexing/infrastructure/blockscout"
 	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/metric/prometheus"
 	block_view "github.com/AstraProtocol/astra-indexing/projection/block/view"
 	chainstats_view "github.com/AstraProtocol/astra-indexing/projection/chainstats/view"
 	transaction_view "github.com/AstraProtocol/astra-indexing/projection/transaction/view"
@@ -208,6 +209,7 @@ func (handler *StatusHandler) GetStatus(ctx *fasthttp.RequestCtx) {
 		ActiveValidatorCount:        activeValidatorCount,
 		LatestHeight:                latestHeight,
 		AverageBlockTimeMillisecond: averageBlockTime.Text('f', 0),
+		KafkaConsumerLag:            newKafkaConsumerLag(prometheus.KafkaConsumerLagByTopic()),
 	}
 
 	httpapi.Success(ctx, status)
@@ -222,4 +224,26 @@ type Status struct {
 	ActiveValidatorCount        int64         `json:"activeValidatorCount"`
 	LatestHeight                int64         `json:"latestHeight"`
 	AverageBlockTimeMillisecond string        `json:"averageBlockTimeMillisecond"`
+	// Omitted when this process runs no Kafka consumer
+	KafkaConsumerLag *KafkaConsumerLag `json:"kafkaConsumerLag,omitempty"`
+}
+
+// KafkaConsumerLag is the number of messages not consumed yet, as of the last message fetched from
+// or the last lag sampled of every partition assigned to the consumers of this process
+type KafkaConsumerLag struct {
+	Total   int64            `json:"total"`
+	ByTopic map[string]int64 `json:"byTopic"`
+}
+
+func newKafkaConsumerLag(lagByTopic map[string]int64) *KafkaConsumerLag {
+	if len(lagByTopic) == 0 {
+		return nil
+	}
+	lag := &KafkaConsumerLag{
+		ByTopic: lagByTopic,
+	}
+	for _, topicLag := range lagByTopic {
+		lag.Total += topicLag
+	}
+	return lag
 }
@@ -3,7 +3,6 @@ package handlers
 import (
 	"errors"
 	"fmt"
-	"math"
 	"strconv"
 	"time"
 
@@ -28,7 +27,7 @@ type Transactions struct {
 	logger           applogger.Logger
 	transactionsView transactionView.BlockTransactions
 	blockscoutClient blockscout_infrastructure.HTTPClient
-	astraCache       *cache.AstraCache
+	astraCache       cache.Cache
 	astraLocalCache  *cache.AstraLocalCache
 }
 
@@ -261,7 +260,8 @@ func (handler *Transactions) List(ctx *fasthttp.RequestCtx) {
 	startTime := time.Now()
 	recordMethod := "ListTransactions"
 
-	paginationInput, err := httpapi.ParsePagination(ctx)
+	// Transactions are paginated by block height and id
+	paginationInput, err := httpapi.ParsePaginationWithCursor(ctx, httpapi.CURSOR_KEY_INTEGER, httpapi.CURSOR_KEY_INTEGER)
 	if err != nil {
 		handler.logger.Errorf("invalid %s params", recordMethod)
 		prometheus.RecordApiExecTime(recordMethod, strconv.Itoa(fasthttp.StatusBadRequest), "GET", time.Since(startTime).Milliseconds())
@@ -270,14 +270,7 @@ func (handler *Transactions) List(ctx *fasthttp.RequestCtx) {
 	}
 
 	//limit pagination
-	if paginationInput.OffsetParams().Limit > pagination.MAX_LIMIT {
-		paginationInput.OffsetParams().Limit = pagination.MAX_LIMIT
-	}
-
-	maxPage := int64(math.Ceil(float64(pagination.MAX_ELEMENTS) / float64(paginationInput.OffsetParams().Limit)))
-	if paginationInput.OffsetParams().Page > maxPage {
-		paginationInput.OffsetParams().Page = maxPage
-	}
+	limitPagination(paginationInput)
 
 	heightOrder := view.ORDER_ASC
 	queryArgs := ctx.QueryArgs()
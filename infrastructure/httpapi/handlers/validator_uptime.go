@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	rdbparambase_view "github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbparambase/view"
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/view"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/external/cache"
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/infrastructure"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi"
+	"github.com/AstraProtocol/astra-indexing/projection/validator_uptime"
+	validator_uptime_view "github.com/AstraProtocol/astra-indexing/projection/validator_uptime/view"
+)
+
+type ValidatorUptime struct {
+	logger applogger.Logger
+
+	validatorAddressPrefix string
+	consNodeAddressPrefix  string
+
+	signingInfosView   validator_uptime_view.SigningInfos
+	missedBlocksView   validator_uptime_view.MissedBlocks
+	slashingEventsView validator_uptime_view.SlashingEvents
+	paramsView         rdbparambase_view.Params
+	astraCache         cache.Cache
+}
+
+func NewValidatorUptime(
+	logger applogger.Logger,
+	validatorAddressPrefix string,
+	consNodeAddressPrefix string,
+	rdbHandle *rdb.Handle,
+) *ValidatorUptime {
+	return &ValidatorUptime{
+		logger.WithFields(applogger.LogFields{
+			"module": "ValidatorUptimeHandler",
+		}),
+
+		validatorAddressPrefix,
+		consNodeAddressPrefix,
+
+		validator_uptime_view.NewSigningInfosView(rdbHandle),
+		validator_uptime_view.NewMissedBlocksView(rdbHandle),
+		validator_uptime_view.NewSlashingEventsView(rdbHandle),
+		rdbparambase_view.NewParamsView(rdbHandle, validator_uptime_view.PARAMS_TABLE_NAME),
+		cache.NewCache(),
+	}
+}
+
+func (handler *ValidatorUptime) FindBy(ctx *fasthttp.RequestCtx) {
+	signingInfo, ok := handler.findSigningInfo(ctx)
+	if !ok {
+		return
+	}
+
+	cacheKey := fmt.Sprintf("validatorUptime_%s", signingInfo.OperatorAddress)
+	var tmpUptime ValidatorUptimeDetails
+	if err := handler.astraCache.Get(cacheKey, &tmpUptime); err == nil {
+		httpapi.Success(ctx, tmpUptime)
+		return
+	}
+
+	signedBlocksWindow, err := handler.paramsView.FindInt64By(validator_uptime.SignedBlocksWindowParam)
+	if err != nil {
+		handler.logger.Errorf("error getting signed_blocks_window param: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+	minSignedPerWindow, err := handler.paramsView.FindBy(validator_uptime.MinSignedPerWindowParam)
+	if err != nil {
+		handler.logger.Errorf("error getting min_signed_per_window param: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+	maxMissedBlocks, err := maxMissedBlocksPerWindow(signedBlocksWindow, minSignedPerWindow)
+	if err != nil {
+		handler.logger.Errorf("error computing max missed blocks per window: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	blocksInWindow := signingInfo.IndexOffset
+	if blocksInWindow > signedBlocksWindow {
+		blocksInWindow = signedBlocksWindow
+	}
+	uptime := float64(100)
+	if blocksInWindow > 0 {
+		uptime = float64(blocksInWindow-signingInfo.MissedBlocksCounter) * 100 / float64(blocksInWindow)
+	}
+	remainingMissableBlocks := maxMissedBlocks - signingInfo.MissedBlocksCounter
+	if remainingMissableBlocks < 0 {
+		remainingMissableBlocks = 0
+	}
+
+	validatorUptime := ValidatorUptimeDetails{
+		OperatorAddress:         signingInfo.OperatorAddress,
+		ConsensusNodeAddress:    signingInfo.ConsensusNodeAddress,
+		Active:                  signingInfo.Active,
+		Jailed:                  signingInfo.Jailed,
+		Tombstoned:              signingInfo.Tombstoned,
+		StartHeight:             signingInfo.StartHeight,
+		SignedBlocksWindow:      signedBlocksWindow,
+		MinSignedPerWindow:      minSignedPerWindow,
+		BlocksInWindow:          blocksInWindow,
+		MissedBlocksCounter:     signingInfo.MissedBlocksCounter,
+		MaxMissedBlocks:         maxMissedBlocks,
+		RemainingMissableBlocks: remainingMissableBlocks,
+		TotalMissedBlocks:       signingInfo.TotalMissedBlocks,
+		Uptime:                  strconv.FormatFloat(uptime, 'f', 2, 64),
+		MaybeLastMissedHeight:   signingInfo.MaybeLastMissedHeight,
+	}
+
+	_ = handler.astraCache.Set(cacheKey, validatorUptime, infrastructure.TIME_CACHE_FAST)
+	httpapi.Success(ctx, validatorUptime)
+}
+
+func (handler *ValidatorUptime) ListMissedBlocks(ctx *fasthttp.RequestCtx) {
+	paginationInput, err := httpapi.ParsePagination(ctx)
+	if err != nil {
+		httpapi.BadRequest(ctx, err)
+		return
+	}
+	order, ok := parseHeightOrder(ctx)
+	if !ok {
+		return
+	}
+
+	signingInfo, ok := handler.findSigningInfo(ctx)
+	if !ok {
+		return
+	}
+
+	cacheKey := fmt.Sprintf(
+		"validatorUptime_ListMissedBlocks_%s_%s_%s", signingInfo.OperatorAddress, paginationInput.Key(), order,
+	)
+	var tmpMissedBlocks MissedBlockRowsPaginationResult
+	if err = handler.astraCache.Get(cacheKey, &tmpMissedBlocks); err == nil {
+		httpapi.SuccessWithPagination(ctx, tmpMissedBlocks.MissedBlockRows, &tmpMissedBlocks.PaginationResult)
+		return
+	}
+
+	missedBlocks, paginationResult, err := handler.missedBlocksView.ListByOperatorAddress(
+		signingInfo.OperatorAddress,
+		validator_uptime_view.MissedBlocksListOrder{Height: order},
+		paginationInput,
+	)
+	if err != nil {
+		handler.logger.Errorf("error listing validator missed blocks: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	_ = handler.astraCache.Set(cacheKey, MissedBlockRowsPaginationResult{
+		missedBlocks, *paginationResult,
+	}, infrastructure.TIME_CACHE_FAST)
+	httpapi.SuccessWithPagination(ctx, missedBlocks, paginationResult)
+}
+
+func (handler *ValidatorUptime) ListSlashingEvents(ctx *fasthttp.RequestCtx) {
+	paginationInput, err := httpapi.ParsePagination(ctx)
+	if err != nil {
+		httpapi.BadRequest(ctx, err)
+		return
+	}
+	order, ok := parseHeightOrder(ctx)
+	if !ok {
+		return
+	}
+
+	signingInfo, ok := handler.findSigningInfo(ctx)
+	if !ok {
+		return
+	}
+
+	cacheKey := fmt.Sprintf(
+		"validatorUptime_ListSlashingEvents_%s_%s_%s", signingInfo.OperatorAddress, paginationInput.Key(), order,
+	)
+	var tmpSlashingEvents SlashingEventRowsPaginationResult
+	if err = handler.astraCache.Get(cacheKey, &tmpSlashingEvents); err == nil {
+		httpapi.SuccessWithPagination(ctx, tmpSlashingEvents.SlashingEventRows, &tmpSlashingEvents.PaginationResult)
+		return
+	}
+
+	slashingEvents, paginationResult, err := handler.slashingEventsView.ListByOperatorAddress(
+		signingInfo.OperatorAddress,
+		validator_uptime_view.SlashingEventsListOrder{Height: order},
+		paginationInput,
+	)
+	if err != nil {
+		handler.logger.Errorf("error listing validator slashing events: %v", err)
+		httpapi.InternalServerError(ctx)
+		return
+	}
+
+	_ = handler.astraCache.Set(cacheKey, SlashingEventRowsPaginationResult{
+		slashingEvents, *paginationResult,
+	}, infrastructure.TIME_CACHE_FAST)
+	httpapi.SuccessWithPagination(ctx, slashingEvents, paginationResult)
+}
+
+// findSigningInfo finds the signing info of the validator by the operator or consensus node address in the URL,
+// writing the error response when it cannot be found
+func (handler *ValidatorUptime) findSigningInfo(
+	ctx *fasthttp.RequestCtx,
+) (*validator_uptime_view.SigningInfoRow, bool) {
+	addressParams, addressParamsOk := URLValueGuard(ctx, handler.logger, "address")
+	if !addressParamsOk {
+		return nil, false
+	}
+	var identity validator_uptime_view.SigningInfoIdentity
+	// The consensus node address prefix is checked first as it may start with the validator address prefix
+	if strings.HasPrefix(addressParams, handler.consNodeAddressPrefix) {
+		identity = validator_uptime_view.SigningInfoIdentity{
+			MaybeConsensusNodeAddress: &addressParams,
+		}
+	} else if strings.HasPrefix(addressParams, handler.validatorAddressPrefix) {
+		identity = validator_uptime_view.SigningInfoIdentity{
+			MaybeOperatorAddress: &addressParams,
+		}
+	} else {
+		httpapi.BadRequest(ctx, errors.New("invalid address"))
+		return nil, false
+	}
+
+	signingInfo, err := handler.signingInfosView.FindBy(identity)
+	if err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			httpapi.NotFound(ctx)
+			return nil, false
+		}
+		handler.logger.Errorf("error finding validator signing info: %v", err)
+		httpapi.InternalServerError(ctx)
+		return nil, false
+	}
+
+	return signingInfo, true
+}
+
+func parseHeightOrder(ctx *fasthttp.RequestCtx) (view.ORDER, bool) {
+	queryArgs := ctx.QueryArgs()
+	if !queryArgs.Has("order") {
+		return view.ORDER_DESC, true
+	}
+
+	switch string(queryArgs.Peek("order")) {
+	case "height", "height.asc":
+		return view.ORDER_ASC, true
+	case "height.desc":
+		return view.ORDER_DESC, true
+	default:
+		httpapi.BadRequest(ctx, errors.New("invalid order"))
+		return "", false
+	}
+}
+
+// maxMissedBlocksPerWindow returns the number of blocks a validator can miss in a signing window without being
+// jailed, rounding the minimum signed blocks half to even as the slashing module does
+func maxMissedBlocksPerWindow(signedBlocksWindow int64, minSignedPerWindow string) (int64, error) {
+	minSignedRatio, ok := new(big.Rat).SetString(minSignedPerWindow)
+	if !ok {
+		return 0, fmt.Errorf("invalid min_signed_per_window: %s", minSignedPerWindow)
+	}
+	minSigned := minSignedRatio.Mul(minSignedRatio, new(big.Rat).SetInt64(signedBlocksWindow))
+
+	quotient, remainder := new(big.Int).QuoRem(minSigned.Num(), minSigned.Denom(), new(big.Int))
+	switch new(big.Int).Mul(remainder, big.NewInt(2)).Cmp(minSigned.Denom()) {
+	case 1:
+		quotient.Add(quotient, big.NewInt(1))
+	case 0:
+		if quotient.Bit(0) == 1 {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	return signedBlocksWindow - quotient.Int64(), nil
+}
+
+type ValidatorUptimeDetails struct {
+	OperatorAddress         string `json:"operatorAddress"`
+	ConsensusNodeAddress    string `json:"consensusNodeAddress"`
+	Active                  bool   `json:"active"`
+	Jailed                  bool   `json:"jailed"`
+	Tombstoned              bool   `json:"tombstoned"`
+	StartHeight             int64  `json:"startHeight"`
+	SignedBlocksWindow      int64  `json:"signedBlocksWindow"`
+	MinSignedPerWindow      string `json:"minSignedPerWindow"`
+	BlocksInWindow          int64  `json:"blocksInWindow"`
+	MissedBlocksCounter     int64  `json:"missedBlocksCounter"`
+	MaxMissedBlocks         int64  `json:"maxMissedBlocks"`
+	RemainingMissableBlocks int64  `json:"remainingMissableBlocks"`
+	TotalMissedBlocks       int64  `json:"totalMissedBlocks"`
+	// Percentage of the blocks in the signing window signed by the validator
+	Uptime                string `json:"uptime"`
+	MaybeLastMissedHeight *int64 `json:"lastMissedHeight"`
+}
+
+type MissedBlockRowsPaginationResult struct {
+	MissedBlockRows  []validator_uptime_view.MissedBlockRow `json:"missedBlockRows"`
+	PaginationResult pagination.Result                      `json:"paginationResult"`
+}
+
+type SlashingEventRowsPaginationResult struct {
+	SlashingEventRows []validator_uptime_view.SlashingEventRow `json:"slashingEventRows"`
+	PaginationResult  pagination.Result                        `json:"paginationResult"`
+}
@@ -45,7 +45,7 @@ type Validators struct {
 	validatorActivitiesView *validator_view.ValidatorActivities
 	chainStatsView          *chainstats_view.ChainStats
 	blockView               *block_view.Blocks
-	astraCache              *cache.AstraCache
+	astraCache              cache.Cache
 }
 
 func NewValidators(
@@ -94,46 +94,74 @@ func (handler *Validators) FindBy(ctx *fasthttp.RequestCtx) {
 	}
 
 	validatorCacheKey := fmt.Sprintf("validatorAddress_%s", addressParams)
-	var tmpValidatorDetail ValidatorDetails
-	err := handler.astraCache.Get(validatorCacheKey, &tmpValidatorDetail)
-	if err == nil {
-		httpapi.Success(ctx, tmpValidatorDetail)
-		return
-	}
-	rawValidator, err := handler.validatorsView.FindBy(identity)
+	var rawValidator *validator_view.ValidatorRow
+	err := handler.astraCache.Get(validatorCacheKey, &rawValidator)
 	if err != nil {
-		if errors.Is(err, rdb.ErrNoRows) {
-			httpapi.NotFound(ctx)
+		rawValidator, err = handler.validatorsView.FindBy(identity)
+		if err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				httpapi.NotFound(ctx)
+				return
+			}
+			handler.logger.Errorf("error finding validator by operator address: %v", err)
+			httpapi.InternalServerError(ctx)
 			return
 		}
-		handler.logger.Errorf("error finding validator by operator address: %v", err)
-		httpapi.InternalServerError(ctx)
-		return
+		_ = handler.astraCache.Set(validatorCacheKey, rawValidator, invalidatedCacheExpiration(handler.astraCache),
+			validator_view.ValidatorCacheTag(addressParams))
 	}
 
-	validator := ValidatorDetails{
+	// The stake is queried from the node, not evicted by the projection
+	stakeCacheKey := fmt.Sprintf("validatorStake_%s", rawValidator.OperatorAddress)
+	var stake validatorStake
+	if err = handler.astraCache.Get(stakeCacheKey, &stake); err != nil {
+		var complete bool
+		stake, complete = handler.queryValidatorStake(rawValidator)
+		// The stake defaulted on errors is not cached
+		if complete {
+			_ = handler.astraCache.Set(stakeCacheKey, stake, infrastructure.TIME_CACHE_FAST)
+		}
+	}
+
+	httpapi.Success(ctx, ValidatorDetails{
 		ValidatorRow: rawValidator,
 
+		Tokens:         stake.Tokens,
+		SelfDelegation: stake.SelfDelegation,
+	})
+}
+
+type validatorStake struct {
+	Tokens         string `json:"tokens"`
+	SelfDelegation string `json:"selfDelegation"`
+}
+
+// queryValidatorStake returns the stake of the validator, with "0" for the amounts failed to be queried,
+// and whether none failed
+func (handler *Validators) queryValidatorStake(validator *validator_view.ValidatorRow) (validatorStake, bool) {
+	stake := validatorStake{
 		Tokens:         "0",
 		SelfDelegation: "0",
 	}
+	complete := true
 
 	validatorData, err := handler.cosmosAppClient.Validator(validator.OperatorAddress)
 	if err != nil {
 		handler.logger.Errorf("error getting validator details: %v", err)
+		complete = false
 	} else {
-		validator.Tokens = validatorData.Tokens
+		stake.Tokens = validatorData.Tokens
 	}
 
 	delegation, err := handler.cosmosAppClient.Delegation(validator.InitialDelegatorAddress, validator.OperatorAddress)
 	if err != nil {
 		handler.logger.Errorf("error getting self delegation record: %v", err)
+		complete = false
 	} else if delegation != nil {
-		validator.SelfDelegation = delegation.Balance.Amount
+		stake.SelfDelegation = delegation.Balance.Amount
 	}
 
-	_ = handler.astraCache.Set(validatorCacheKey, validator, infrastructure.TIME_CACHE_FAST)
-	httpapi.Success(ctx, validator)
+	return stake, complete
 }
 
 type ValidatorPaginationResult struct {
@@ -165,11 +193,11 @@ func (handler *Validators) List(ctx *fasthttp.RequestCtx) {
 		rawOrderArgs := queryArgs.PeekMulti("order")
 		for _, rawOrderArg := range rawOrderArgs {
 			orderArg := string(rawOrderArg)
-			if orderArg == "power" {
+			if orderArg == "power" || orderArg == "power.asc" {
 				order.MaybePower = primptr.String(view.ORDER_ASC)
 			} else if orderArg == "power.desc" {
 				order.MaybePower = primptr.String(view.ORDER_DESC)
-			} else if orderArg == "commission" {
+			} else if orderArg == "commission" || orderArg == "commission.asc" {
 				order.MaybeCommission = primptr.String(view.ORDER_ASC)
 			} else if orderArg == "commission.desc" {
 				order.MaybeCommission = primptr.String(view.ORDER_DESC)
@@ -184,17 +212,33 @@ func (handler *Validators) List(ctx *fasthttp.RequestCtx) {
 		paginationParse.OffsetParams().Page,
 		paginationParse.OffsetParams().Limit, order.ToStr())
 
-	var tmpValidatorPaginationResult ValidatorPaginationResult
-	err = handler.astraCache.Get(keyCacheValidator, &tmpValidatorPaginationResult)
-	if err == nil {
-		httpapi.SuccessWithPagination(ctx,
-			tmpValidatorPaginationResult.ValidatorRowWithAPY,
-			&tmpValidatorPaginationResult.PaginationResult)
-		return
-	}
+	// Concurrent misses share a single listing
+	var validatorPaginationResult ValidatorPaginationResult
+	err = handler.astraCache.Load(keyCacheValidator, &validatorPaginationResult,
+		invalidatedCacheExpiration(handler.astraCache),
+		func() (interface{}, error) {
+			validators, paginationResult, listErr := handler.validatorsView.List(
+				validator_view.ValidatorsListFilter{}, order, paginationParse,
+			)
+			if listErr != nil {
+				return nil, listErr
+			}
 
-	validators, paginationResult, err := handler.validatorsView.List(
-		validator_view.ValidatorsListFilter{}, order, paginationParse,
+			validatorsWithAPY := make([]validatorRowWithAPY, 0, len(validators))
+			for _, validator := range validators {
+				if validator.Status != constants.BONDED {
+					validatorsWithAPY = append(validatorsWithAPY, validatorRowWithAPY{
+						validator,
+					})
+					continue
+				}
+				validatorsWithAPY = append(validatorsWithAPY, validatorRowWithAPY{
+					validator,
+				})
+			}
+			return NewValidatorPaginationResult(validatorsWithAPY, *paginationResult), nil
+		},
+		validator_view.VALIDATORS_CACHE_TAG,
 	)
 	if err != nil {
 		handler.logger.Errorf("error listing validators: %v", err)
@@ -202,24 +246,9 @@ func (handler *Validators) List(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	validatorsWithAPY := make([]validatorRowWithAPY, 0, len(validators))
-	for _, validator := range validators {
-		if validator.Status != constants.BONDED {
-			validatorsWithAPY = append(validatorsWithAPY, validatorRowWithAPY{
-				validator,
-			})
-			continue
-		}
-		validatorsWithAPY = append(validatorsWithAPY, validatorRowWithAPY{
-			validator,
-		})
-	}
-
-	_ = handler.astraCache.Set(keyCacheValidator,
-		NewValidatorPaginationResult(validatorsWithAPY, *paginationResult),
-		infrastructure.TIME_CACHE_FAST)
-
-	httpapi.SuccessWithPagination(ctx, validatorsWithAPY, paginationResult)
+	httpapi.SuccessWithPagination(ctx,
+		validatorPaginationResult.ValidatorRowWithAPY,
+		&validatorPaginationResult.PaginationResult)
 }
 
 type validatorRowWithAPY struct {
@@ -347,11 +376,11 @@ func (handler *Validators) ListActive(ctx *fasthttp.RequestCtx) {
 		rawOrderArgs := queryArgs.PeekMulti("order")
 		for _, rawOrderArg := range rawOrderArgs {
 			orderArg := string(rawOrderArg)
-			if orderArg == "power" {
+			if orderArg == "power" || orderArg == "power.asc" {
 				order.MaybePower = primptr.String(view.ORDER_ASC)
 			} else if orderArg == "power.desc" {
 				order.MaybePower = primptr.String(view.ORDER_DESC)
-			} else if orderArg == "commission" {
+			} else if orderArg == "commission" || orderArg == "commission.asc" {
 				order.MaybeCommission = primptr.String(view.ORDER_ASC)
 			} else if orderArg == "commission.desc" {
 				order.MaybeCommission = primptr.String(view.ORDER_DESC)
@@ -366,31 +395,33 @@ func (handler *Validators) ListActive(ctx *fasthttp.RequestCtx) {
 	keyCacheListActive := fmt.Sprintf("ValidatorListActive_%d_%d_%s",
 		paginationParse.OffsetParams().Page, paginationParse.OffsetParams().Limit, order.ToStr())
 
-	var tmpListValidatorRowPaginationResult ListValidatorsRowPaginationResult
-
-	err = handler.astraCache.Get(keyCacheListActive, &tmpListValidatorRowPaginationResult)
-	if err == nil {
-		httpapi.SuccessWithPagination(ctx, tmpListValidatorRowPaginationResult.ListValidatorRow,
-			&tmpListValidatorRowPaginationResult.PaginationResult)
-		return
-	}
-
-	validators, paginationResult, err := handler.validatorsView.List(validator_view.ValidatorsListFilter{
-		MaybeStatuses: []constants.Status{
-			constants.BONDED,
-			constants.JAILED,
-			constants.UNBONDING,
+	// Concurrent misses share a single listing
+	var listValidatorRowPaginationResult ListValidatorsRowPaginationResult
+	err = handler.astraCache.Load(keyCacheListActive, &listValidatorRowPaginationResult,
+		invalidatedCacheExpiration(handler.astraCache),
+		func() (interface{}, error) {
+			validators, paginationResult, listErr := handler.validatorsView.List(validator_view.ValidatorsListFilter{
+				MaybeStatuses: []constants.Status{
+					constants.BONDED,
+					constants.JAILED,
+					constants.UNBONDING,
+				},
+			}, order, paginationParse)
+			if listErr != nil {
+				return nil, listErr
+			}
+			return NewListValidatorsRowPaginationResult(validators, *paginationResult), nil
 		},
-	}, order, paginationParse)
+		validator_view.VALIDATORS_CACHE_TAG,
+	)
 	if err != nil {
 		handler.logger.Errorf("error listing active validators: %v", err)
 		httpapi.InternalServerError(ctx)
 		return
 	}
 
-	_ = handler.astraCache.Set(keyCacheListActive,
-		NewListValidatorsRowPaginationResult(validators, *paginationResult), infrastructure.TIME_CACHE_FAST)
-	httpapi.SuccessWithPagination(ctx, validators, paginationResult)
+	httpapi.SuccessWithPagination(ctx, listValidatorRowPaginationResult.ListValidatorRow,
+		&listValidatorRowPaginationResult.PaginationResult)
 }
 
 type ListActivitiesActivityRowPaginationResult struct {
@@ -435,7 +466,7 @@ func (handler *Validators) ListActivities(ctx *fasthttp.RequestCtx) {
 	queryArgs := ctx.QueryArgs()
 	if queryArgs.Has("order") {
 		orderArg := string(queryArgs.Peek("order"))
-		if orderArg == "height" {
+		if orderArg == "height" || orderArg == "height.asc" {
 			order.MaybeBlockHeight = primptr.String(view.ORDER_ASC)
 		} else if orderArg == "height.desc" {
 			order.MaybeBlockHeight = primptr.String(view.ORDER_DESC)
@@ -445,9 +476,9 @@ func (handler *Validators) ListActivities(ctx *fasthttp.RequestCtx) {
 		}
 	}
 
-	cacheKeyListActivities := "validator_ListActivities_"
+	cacheKeyListActivities := fmt.Sprintf("validator_ListActivities_%s_%s", addressParams, paginationParse.Key())
 	if order.MaybeBlockHeight != nil {
-		cacheKeyListActivities = "validator_ListActivities_" + *order.MaybeBlockHeight
+		cacheKeyListActivities += "_" + *order.MaybeBlockHeight
 	}
 	var tmpListActivitiesRow ListActivitiesActivityRowPaginationResult
 
@@ -0,0 +1,247 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi"
+)
+
+const OPENAPI_VERSION = "3.0.3"
+
+const errorSchemaName = "Error"
+
+var pathParamPattern = regexp.MustCompile(`{([^}]+)}`)
+
+// Spec describes the params and the responses of a route
+type Spec struct {
+	Summary string
+	// Tags grouping the route, empty defaults to the first segment of the path after the API version
+	Tags   []string
+	Params []Param
+	// Value of the type of the `result` of the response, nil for any JSON value
+	Result interface{}
+	// The response has the offset pagination of the result
+	Paginated bool
+	// The response has the cursor pagination of the result instead when requested
+	CursorPaginated bool
+	// The result is the whole response, not wrapped in `result`, e.g. the responses proxied from
+	// Blockscout
+	Unwrapped bool
+	// Status codes of the errors besides 400 and 500
+	Errors []int
+}
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+
+	schemas      *schemaGenerator
+	operationIds map[string]bool
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+type PathItem struct {
+	Get  *Operation `json:"get,omitempty"`
+	Post *Operation `json:"post,omitempty"`
+}
+
+type Operation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []*ParameterObject         `json:"parameters,omitempty"`
+	Responses   map[string]*ResponseObject `json:"responses"`
+}
+
+type ParameterObject struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type ResponseObject struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+func NewDocument(title string, version string) *Document {
+	schemas := newSchemaGenerator()
+	// The standard error shape of httpapi
+	schemas.schemas[errorSchemaName] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"result": {Nullable: true},
+			"error":  {Type: "string"},
+		},
+		Required: []string{"error"},
+	}
+
+	return &Document{
+		OpenAPI: OPENAPI_VERSION,
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: make(map[string]*PathItem),
+		Components: Components{
+			Schemas: schemas.schemas,
+		},
+
+		schemas:      schemas,
+		operationIds: make(map[string]bool),
+	}
+}
+
+// AddOperation adds the route to the document. The path params missing from the spec are
+// documented as strings. The operation ID is derived from the name of the handler.
+func (document *Document) AddOperation(method string, path string, handler interface{}, spec Spec) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	operation := &Operation{
+		OperationID: document.operationIdOf(method, handler),
+		Summary:     spec.Summary,
+		Tags:        spec.Tags,
+		Responses:   document.responsesOf(spec),
+	}
+	if len(operation.Tags) == 0 {
+		operation.Tags = tagsOf(path)
+	}
+
+	declared := make(map[string]bool)
+	for _, param := range spec.Params {
+		if param.in == IN_PATH {
+			declared[param.name] = true
+		}
+	}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		if !declared[match[1]] {
+			operation.Parameters = append(operation.Parameters, PathParam(match[1]).parameterObject())
+		}
+	}
+	for _, param := range spec.Params {
+		operation.Parameters = append(operation.Parameters, param.parameterObject())
+	}
+
+	pathItem, exist := document.Paths[path]
+	if !exist {
+		pathItem = &PathItem{}
+		document.Paths[path] = pathItem
+	}
+	switch method {
+	case http.MethodGet:
+		pathItem.Get = operation
+	case http.MethodPost:
+		pathItem.Post = operation
+	}
+}
+
+func (document *Document) responsesOf(spec Spec) map[string]*ResponseObject {
+	resultSchema := document.schemas.schemaOf(spec.Result)
+	successSchema := resultSchema
+	if !spec.Unwrapped {
+		successSchema = &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"result": resultSchema,
+			},
+			Required: []string{"result"},
+		}
+		if spec.Paginated {
+			paginationSchema := document.schemas.schemaOf(httpapi.PaginationOffsetResponse{})
+			if spec.CursorPaginated {
+				paginationSchema = &Schema{
+					OneOf: []*Schema{
+						paginationSchema,
+						document.schemas.schemaOf(httpapi.PaginationCursorResponse{}),
+					},
+				}
+			}
+			successSchema.Properties["pagination"] = paginationSchema
+			successSchema.Required = append(successSchema.Required, "pagination")
+		}
+	}
+
+	responses := map[string]*ResponseObject{
+		strconv.Itoa(http.StatusOK): {
+			Description: http.StatusText(http.StatusOK),
+			Content: map[string]*MediaType{
+				"application/json": {Schema: successSchema},
+			},
+		},
+	}
+	for _, statusCode := range append([]int{http.StatusBadRequest, http.StatusInternalServerError}, spec.Errors...) {
+		responses[strconv.Itoa(statusCode)] = &ResponseObject{
+			Description: http.StatusText(statusCode),
+			Content: map[string]*MediaType{
+				"application/json": {Schema: &Schema{Ref: "#/components/schemas/" + errorSchemaName}},
+			},
+		}
+	}
+	return responses
+}
+
+// operationIdOf returns the name of the handler method, e.g. `Blocks.List`, suffixed with the
+// method when the same handler serves several routes
+func (document *Document) operationIdOf(method string, handler interface{}) string {
+	operationId := strings.ToLower(method)
+	if handler != nil && reflect.TypeOf(handler).Kind() == reflect.Func {
+		// e.g. github.com/.../handlers.(*Blocks).List-fm
+		funcName := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+		funcName = funcName[strings.LastIndex(funcName, "/")+1:]
+		funcName = strings.TrimSuffix(funcName, "-fm")
+		funcName = strings.NewReplacer("(*", "", ")", "").Replace(funcName)
+		if dot := strings.Index(funcName, "."); dot >= 0 {
+			// Without the package name
+			funcName = funcName[dot+1:]
+		}
+		operationId = funcName
+	}
+	if document.operationIds[operationId] {
+		operationId = fmt.Sprintf("%s.%s", operationId, strings.ToLower(method))
+	}
+	baseOperationId := operationId
+	for suffix := 2; document.operationIds[operationId]; suffix++ {
+		operationId = fmt.Sprintf("%s%d", baseOperationId, suffix)
+	}
+	document.operationIds[operationId] = true
+	return operationId
+}
+
+// tagsOf returns the first segment of the path after the API version, e.g. `blocks` of
+// `/api/v1/blocks/{height}`
+func tagsOf(path string) []string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if segment == "api" && i+2 < len(segments) && strings.HasPrefix(segments[i+1], "v") {
+			return []string{segments[i+2]}
+		}
+	}
+	if len(segments) > 0 && !strings.Contains(segments[0], "{") {
+		return []string{segments[0]}
+	}
+	return nil
+}
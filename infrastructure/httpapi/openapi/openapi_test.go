@@ -0,0 +1,214 @@
+package openapi_test
+
+import (
+	"net/http"
+
+	jsoniter "github.com/json-iterator/go"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/valyala/fasthttp"
+
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi/openapi"
+)
+
+type block struct {
+	Height   int64           `json:"blockHeight"`
+	Hash     string          `json:"blockHash"`
+	Time     utctime.UTCTime `json:"blockTime"`
+	Proposer *string         `json:"proposer,omitempty"`
+	Parent   *block          `json:"parent"`
+	ignored  string
+}
+
+type blocks struct{}
+
+func (handler *blocks) List(ctx *fasthttp.RequestCtx) {
+	httpapi.Success(ctx, []block{})
+}
+
+func (handler *blocks) FindBy(ctx *fasthttp.RequestCtx) {
+	httpapi.Success(ctx, block{})
+}
+
+var _ = Describe("Document", func() {
+	var handler *blocks
+	var document *openapi.Document
+
+	BeforeEach(func() {
+		handler = &blocks{}
+		document = openapi.NewDocument("Test API", "v1")
+	})
+
+	It("should reference the component schema of the result and the pagination", func() {
+		document.AddOperation(http.MethodGet, "/api/v1/blocks", handler.List, openapi.Spec{
+			Params: []openapi.Param{
+				openapi.QueryParam("page").Integer().Min(1),
+			},
+			Result:    []block{},
+			Paginated: true,
+		})
+
+		operation := document.Paths["/api/v1/blocks"].Get
+		Expect(operation.OperationID).To(Equal("blocks.List"))
+		Expect(operation.Tags).To(Equal([]string{"blocks"}))
+		Expect(operation.Parameters).To(HaveLen(1))
+		Expect(operation.Parameters[0].In).To(Equal(openapi.IN_QUERY))
+		Expect(operation.Parameters[0].Schema.Type).To(Equal(openapi.TYPE_INTEGER))
+		Expect(*operation.Parameters[0].Schema.Minimum).To(Equal(int64(1)))
+
+		success := operation.Responses["200"].Content["application/json"].Schema
+		Expect(success.Required).To(ConsistOf("result", "pagination"))
+		Expect(success.Properties["result"].Items.Ref).To(Equal("#/components/schemas/block"))
+		Expect(success.Properties["pagination"].Ref).To(Equal("#/components/schemas/PaginationOffsetResponse"))
+		Expect(operation.Responses["400"].Content["application/json"].Schema.Ref).To(Equal("#/components/schemas/Error"))
+
+		schema := document.Components.Schemas["block"]
+		Expect(schema.Properties).To(HaveLen(5))
+		Expect(schema.Properties["blockHeight"].Type).To(Equal("integer"))
+		Expect(schema.Properties["blockTime"].Format).To(Equal("date-time"))
+		Expect(schema.Properties["proposer"].Nullable).To(BeTrue())
+		Expect(schema.Properties["parent"].Ref).To(Equal("#/components/schemas/block"))
+		Expect(schema.Required).To(ConsistOf("blockHeight", "blockHash", "blockTime", "parent"))
+	})
+
+	It("should document the undeclared path params and the errors", func() {
+		document.AddOperation(http.MethodGet, "/api/v1/blocks/{height-or-hash}", handler.FindBy, openapi.Spec{
+			Result: block{},
+			Errors: []int{http.StatusNotFound},
+		})
+
+		operation := document.Paths["/api/v1/blocks/{height-or-hash}"].Get
+		Expect(operation.Parameters).To(HaveLen(1))
+		Expect(operation.Parameters[0].Name).To(Equal("height-or-hash"))
+		Expect(operation.Parameters[0].In).To(Equal(openapi.IN_PATH))
+		Expect(operation.Parameters[0].Required).To(BeTrue())
+		Expect(operation.Responses).To(HaveKey("404"))
+		Expect(operation.Responses["200"].Content["application/json"].Schema.Properties["result"].Ref).To(
+			Equal("#/components/schemas/block"),
+		)
+	})
+
+	It("should give unique operation IDs to the handlers serving several routes", func() {
+		document.AddOperation(http.MethodGet, "/api/v1/blocks", handler.List, openapi.Spec{})
+		document.AddOperation(http.MethodPost, "/api/v1/blocks", handler.List, openapi.Spec{})
+		document.AddOperation(http.MethodGet, "/api/v1/latest-blocks", handler.List, openapi.Spec{Unwrapped: true})
+
+		Expect(document.Paths["/api/v1/blocks"].Get.OperationID).To(Equal("blocks.List"))
+		Expect(document.Paths["/api/v1/blocks"].Post.OperationID).To(Equal("blocks.List.post"))
+		Expect(document.Paths["/api/v1/latest-blocks"].Get.OperationID).To(Equal("blocks.List.get"))
+		Expect(document.Paths["/api/v1/latest-blocks"].Get.Responses["200"].Content["application/json"].Schema).To(
+			Equal(&openapi.Schema{}),
+		)
+	})
+
+	It("should serve the encoded document", func() {
+		document.AddOperation(http.MethodGet, "/api/v1/blocks", handler.List, openapi.Spec{Result: []block{}})
+
+		ctx := &fasthttp.RequestCtx{}
+		openapi.Handler(document)(ctx)
+
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+		var served map[string]interface{}
+		Expect(jsoniter.Unmarshal(ctx.Response.Body(), &served)).To(Succeed())
+		Expect(served["openapi"]).To(Equal(openapi.OPENAPI_VERSION))
+		Expect(served["paths"]).To(HaveKey("/api/v1/blocks"))
+	})
+})
+
+var _ = Describe("Validate", func() {
+	spec := openapi.Spec{
+		Params: []openapi.Param{
+			openapi.PathParam("height").Integer().Min(0),
+			openapi.QueryParam("order").Enum("height", "height.desc").Multiple(),
+			openapi.QueryParam("daily").Boolean(),
+			openapi.QueryParam("fromDate").Required(),
+		},
+	}
+
+	serve := func(path string, query string) *fasthttp.RequestCtx {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.SetUserValue("height", path)
+		ctx.Request.SetRequestURI("/api/v1/blocks?" + query)
+		openapi.Validate(spec, func(ctx *fasthttp.RequestCtx) {
+			httpapi.Success(ctx, "handled")
+		})(ctx)
+		return ctx
+	}
+
+	errorOf := func(ctx *fasthttp.RequestCtx) string {
+		var response httpapi.Response
+		Expect(jsoniter.Unmarshal(ctx.Response.Body(), &response)).To(Succeed())
+		return response.Err
+	}
+
+	It("should call the handler with valid params", func() {
+		ctx := serve("10", "fromDate=2022-01-01&order=height&order=height.desc&daily=true&unknown=1")
+
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+	})
+
+	It("should treat the empty query params as absent", func() {
+		ctx := serve("10", "fromDate=2022-01-01&daily=")
+
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+	})
+
+	It("should reject a path param not an integer", func() {
+		ctx := serve("ten", "fromDate=2022-01-01")
+
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusBadRequest))
+		Expect(errorOf(ctx)).To(Equal("invalid height param: must be an integer"))
+	})
+
+	It("should reject a path param below the minimum", func() {
+		ctx := serve("-1", "fromDate=2022-01-01")
+
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusBadRequest))
+		Expect(errorOf(ctx)).To(Equal("invalid height param: must be at least 0"))
+	})
+
+	It("should reject a query param not in the enum", func() {
+		ctx := serve("10", "fromDate=2022-01-01&order=time")
+
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusBadRequest))
+		Expect(errorOf(ctx)).To(Equal("invalid order param: must be one of height, height.desc"))
+	})
+
+	It("should reject a query param not a boolean", func() {
+		ctx := serve("10", "fromDate=2022-01-01&daily=1")
+
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusBadRequest))
+		Expect(errorOf(ctx)).To(Equal("invalid daily param: must be true or false"))
+	})
+
+	It("should reject a missing required param", func() {
+		ctx := serve("10", "")
+
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusBadRequest))
+		Expect(errorOf(ctx)).To(Equal("missing fromDate param"))
+	})
+
+	It("should only validate the first value of a repeated param not multiple", func() {
+		ctx := serve("10", "fromDate=2022-01-01&daily=true&daily=invalid")
+
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+	})
+
+	It("should reject an invalid first value of a repeated param not multiple", func() {
+		ctx := serve("10", "fromDate=2022-01-01&daily=invalid&daily=true")
+
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusBadRequest))
+		Expect(errorOf(ctx)).To(Equal("invalid daily param: must be true or false"))
+	})
+
+	It("should return the handler when there are no params", func() {
+		ctx := &fasthttp.RequestCtx{}
+		openapi.Validate(openapi.Spec{}, func(ctx *fasthttp.RequestCtx) {
+			httpapi.Success(ctx, "handled")
+		})(ctx)
+
+		Expect(ctx.Response.StatusCode()).To(Equal(fasthttp.StatusOK))
+	})
+})
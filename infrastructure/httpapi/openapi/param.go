@@ -0,0 +1,163 @@
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	IN_PATH  = "path"
+	IN_QUERY = "query"
+)
+
+const (
+	TYPE_STRING  = "string"
+	TYPE_INTEGER = "integer"
+	TYPE_NUMBER  = "number"
+	TYPE_BOOLEAN = "boolean"
+)
+
+// Param describes a path or query parameter of a route. The methods return a copy of the param,
+// such that the params can be declared inline, e.g.
+//
+//	openapi.QueryParam("page").Integer().Min(1)
+type Param struct {
+	name        string
+	in          string
+	paramType   string
+	description string
+	required    bool
+	// A query param repeated in the query, e.g. `order=power&order=commission.desc`
+	multiple bool
+	enum     []string
+	minimum  *int64
+	maximum  *int64
+}
+
+func QueryParam(name string) Param {
+	return Param{
+		name:      name,
+		in:        IN_QUERY,
+		paramType: TYPE_STRING,
+	}
+}
+
+// PathParam describes a param of the path of the route, path params are always required
+func PathParam(name string) Param {
+	return Param{
+		name:      name,
+		in:        IN_PATH,
+		paramType: TYPE_STRING,
+		required:  true,
+	}
+}
+
+func (param Param) Name() string {
+	return param.name
+}
+
+func (param Param) In() string {
+	return param.in
+}
+
+func (param Param) Integer() Param {
+	param.paramType = TYPE_INTEGER
+	return param
+}
+
+func (param Param) Number() Param {
+	param.paramType = TYPE_NUMBER
+	return param
+}
+
+func (param Param) Boolean() Param {
+	param.paramType = TYPE_BOOLEAN
+	return param
+}
+
+func (param Param) Enum(values ...string) Param {
+	param.enum = values
+	return param
+}
+
+func (param Param) Min(minimum int64) Param {
+	param.minimum = &minimum
+	return param
+}
+
+func (param Param) Max(maximum int64) Param {
+	param.maximum = &maximum
+	return param
+}
+
+func (param Param) Required() Param {
+	param.required = true
+	return param
+}
+
+func (param Param) Multiple() Param {
+	param.multiple = true
+	return param
+}
+
+func (param Param) Describe(description string) Param {
+	param.description = description
+	return param
+}
+
+// validate checks a value of the param
+func (param Param) validate(value string) error {
+	switch param.paramType {
+	case TYPE_INTEGER:
+		integer, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s param: must be an integer", param.name)
+		}
+		if param.minimum != nil && integer < *param.minimum {
+			return fmt.Errorf("invalid %s param: must be at least %d", param.name, *param.minimum)
+		}
+		if param.maximum != nil && integer > *param.maximum {
+			return fmt.Errorf("invalid %s param: must be at most %d", param.name, *param.maximum)
+		}
+	case TYPE_NUMBER:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("invalid %s param: must be a number", param.name)
+		}
+	case TYPE_BOOLEAN:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("invalid %s param: must be true or false", param.name)
+		}
+	}
+	if len(param.enum) > 0 {
+		for _, allowed := range param.enum {
+			if value == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid %s param: must be one of %s", param.name, strings.Join(param.enum, ", "))
+	}
+	return nil
+}
+
+func (param Param) parameterObject() *ParameterObject {
+	schema := &Schema{
+		Type:    param.paramType,
+		Enum:    param.enum,
+		Minimum: param.minimum,
+		Maximum: param.maximum,
+	}
+	if param.multiple {
+		schema = &Schema{
+			Type:  "array",
+			Items: schema,
+		}
+	}
+	return &ParameterObject{
+		Name:        param.name,
+		In:          param.in,
+		Description: param.description,
+		Required:    param.required,
+		Schema:      schema,
+	}
+}
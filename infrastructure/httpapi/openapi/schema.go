@@ -0,0 +1,247 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/usecase/coin"
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *int64             `json:"minimum,omitempty"`
+	Maximum              *int64             `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+// Schemas of the types marshalled to JSON by their own method
+var knownSchemas = map[reflect.Type]*Schema{
+	reflect.TypeOf(utctime.UTCTime{}): {Type: "string", Format: "date-time"},
+	reflect.TypeOf(time.Time{}):       {Type: "string", Format: "date-time"},
+	reflect.TypeOf(big.Int{}):         {Type: "integer"},
+	reflect.TypeOf(big.Float{}):       {Type: "string", Format: "decimal"},
+	reflect.TypeOf(coin.Coins{}): {
+		Type: "array",
+		Items: &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"denom":  {Type: "string"},
+				"amount": {Type: "string"},
+			},
+			Required: []string{"denom", "amount"},
+		},
+	},
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+)
+
+// schemaGenerator generates the schemas of the Go types from their JSON encoding. The named structs
+// are generated once in the component schemas and referenced.
+type schemaGenerator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schemaOf returns the schema of the value, nil is any JSON value
+func (generator *schemaGenerator) schemaOf(value interface{}) *Schema {
+	if value == nil {
+		return &Schema{}
+	}
+	return generator.schemaOfType(reflect.TypeOf(value))
+}
+
+func (generator *schemaGenerator) schemaOfType(t reflect.Type) *Schema {
+	if schema, known := knownSchemas[t]; known {
+		return schema
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := generator.schemaOfType(t.Elem())
+		if schema.Ref != "" {
+			return schema
+		}
+		nullable := *schema
+		nullable.Nullable = true
+		return &nullable
+	case reflect.Interface:
+		return &Schema{}
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		// The encoding is unknown
+		return &Schema{}
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64", Minimum: new(int64)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{
+			Type:  "array",
+			Items: generator.schemaOfType(t.Elem()),
+		}
+	case reflect.Map:
+		return &Schema{
+			Type:                 "object",
+			AdditionalProperties: generator.schemaOfType(t.Elem()),
+		}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return generator.structSchema(t)
+		}
+		return generator.structRef(t)
+	}
+	return &Schema{}
+}
+
+// structRef generates the component schema of the named struct once
+func (generator *schemaGenerator) structRef(t reflect.Type) *Schema {
+	name, generated := generator.names[t]
+	if !generated {
+		name = generator.nameOf(t)
+		generator.names[t] = name
+		// Registered before its fields, for the recursive structs
+		generator.schemas[name] = &Schema{}
+		*generator.schemas[name] = *generator.structSchema(t)
+	}
+	return &Schema{
+		Ref: "#/components/schemas/" + name,
+	}
+}
+
+// nameOf returns a unique component name for the type, qualified with its package when its name is
+// already taken
+func (generator *schemaGenerator) nameOf(t reflect.Type) string {
+	name := invalidNameChars.ReplaceAllString(t.Name(), "_")
+	if _, taken := generator.schemas[name]; !taken {
+		return name
+	}
+	packagePath := strings.Split(t.PkgPath(), "/")
+	qualifiedName := name
+	// e.g. projection/block/view.Block is named block.view.Block
+	for i := len(packagePath) - 1; i >= 0; i-- {
+		qualifiedName = packagePath[i] + "." + qualifiedName
+		if _, taken := generator.schemas[qualifiedName]; !taken {
+			return qualifiedName
+		}
+	}
+	for suffix := 2; ; suffix++ {
+		if _, taken := generator.schemas[fmt.Sprintf("%s%d", qualifiedName, suffix)]; !taken {
+			return fmt.Sprintf("%s%d", qualifiedName, suffix)
+		}
+	}
+}
+
+// structSchema follows the encoding/json rules: unexported and `-` fields are skipped, and the
+// fields of the embedded structs without JSON name are promoted
+func (generator *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		tagParts := strings.Split(tag, ",")
+		name := tagParts[0]
+
+		if field.Anonymous && name == "" {
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct {
+				embedded := generator.structSchema(embeddedType)
+				for propertyName, property := range embedded.Properties {
+					if _, shadowed := schema.Properties[propertyName]; !shadowed {
+						schema.Properties[propertyName] = property
+					}
+				}
+				schema.Required = append(schema.Required, embedded.Required...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		omitEmpty := false
+		asString := false
+		for _, option := range tagParts[1:] {
+			omitEmpty = omitEmpty || option == "omitempty"
+			asString = asString || option == "string"
+		}
+		if asString {
+			schema.Properties[name] = &Schema{Type: "string"}
+		} else {
+			schema.Properties[name] = generator.schemaOfType(field.Type)
+		}
+		if !omitEmpty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	schema.Required = uniqueStrings(schema.Required)
+	return schema
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := values[:0]
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/valyala/fasthttp"
+
+	"github.com/AstraProtocol/astra-indexing/infrastructure/httpapi"
+)
+
+// Validate rejects the requests with params not matching the params of the spec with 400 and the
+// standard error shape of httpapi. Query params not in the spec are left to the handler.
+func Validate(spec Spec, handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if len(spec.Params) == 0 {
+		return handler
+	}
+	return func(ctx *fasthttp.RequestCtx) {
+		if err := validateParams(ctx, spec.Params); err != nil {
+			httpapi.BadRequest(ctx, err)
+			return
+		}
+		handler(ctx)
+	}
+}
+
+func validateParams(ctx *fasthttp.RequestCtx, params []Param) error {
+	queryArgs := ctx.QueryArgs()
+	for _, param := range params {
+		var values []string
+		switch param.in {
+		case IN_PATH:
+			if value, ok := ctx.UserValue(param.name).(string); ok {
+				values = append(values, value)
+			}
+		case IN_QUERY:
+			// Empty values are absent, as the handlers read them
+			if !param.multiple {
+				// The handlers only read the first value of a repeated param
+				if value := queryArgs.Peek(param.name); len(value) > 0 {
+					values = append(values, string(value))
+				}
+				break
+			}
+			for _, value := range queryArgs.PeekMulti(param.name) {
+				if len(value) > 0 {
+					values = append(values, string(value))
+				}
+			}
+		}
+
+		if len(values) == 0 {
+			if param.required {
+				return fmt.Errorf("missing %s param", param.name)
+			}
+			continue
+		}
+		for _, value := range values {
+			if err := param.validate(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Handler serves the document, encoded once
+func Handler(document *Document) fasthttp.RequestHandler {
+	encoded, err := jsoniter.Marshal(document)
+	return func(ctx *fasthttp.RequestCtx) {
+		if err != nil {
+			httpapi.InternalServerError(ctx)
+			return
+		}
+		ctx.Response.Header.Set("Content-Type", "application/json")
+		ctx.SetBody(encoded)
+	}
+}
@@ -37,20 +37,83 @@ func ParsePagination(ctx *fasthttp.RequestCtx) (*pagination_interface.Pagination
 		}
 	}
 
-	var defaultLimit int64 = int64(20)
+	limit, err = parseLimit(queryArgs)
+	if err != nil {
+		return nil, err
+	}
 
-	limitQuery := queryArgs.Get("limit")
-	if limitQuery == "" {
-		limit = defaultLimit
-	} else {
-		limit, err = strconv.ParseInt(limitQuery, 10, 64)
-		if err != nil {
-			return nil, ErrInvalidPage
+	return pagination_interface.NewOffsetPagination(page, limit), nil
+}
+
+// CursorKey is the type of a column of the keyset a list is paginated by
+type CursorKey int
+
+const (
+	CURSOR_KEY_INTEGER CursorKey = iota
+	CURSOR_KEY_STRING
+)
+
+// ParsePaginationWithCursor parses the cursor pagination when requested by `pagination=cursor` or
+// a `cursor`, and the offset pagination otherwise, for the lists supporting both. `keyset` is the
+// type of every column of the keyset of the list, a cursor whose keys do not match it is invalid.
+func ParsePaginationWithCursor(ctx *fasthttp.RequestCtx, keyset ...CursorKey) (*pagination_interface.Pagination, error) {
+	queryArgs := NewQueryArgs(ctx.QueryArgs())
+
+	pagination := queryArgs.Get("pagination")
+	encodedCursor := queryArgs.Get("cursor")
+	if pagination != pagination_interface.PAGINATION_CURSOR && (pagination != "" || encodedCursor == "") {
+		return ParsePagination(ctx)
+	}
+
+	limit, err := parseLimit(queryArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor *pagination_interface.Cursor
+	if encodedCursor != "" {
+		cursor, err = pagination_interface.DecodeCursor(encodedCursor)
+		if err != nil || !cursorMatches(cursor, keyset) {
+			return nil, ErrInvalidCursor
 		}
-		if limit <= 0 {
-			limit = defaultLimit
+	}
+
+	return pagination_interface.NewCursorPagination(cursor, limit), nil
+}
+
+// cursorMatches returns whether the cursor has a key of the type of every column of the keyset
+func cursorMatches(cursor *pagination_interface.Cursor, keyset []CursorKey) bool {
+	if len(cursor.Keys) != len(keyset) {
+		return false
+	}
+	for i, key := range cursor.Keys {
+		switch keyset[i] {
+		case CURSOR_KEY_INTEGER:
+			if _, ok := key.(int64); !ok {
+				return false
+			}
+		case CURSOR_KEY_STRING:
+			if _, ok := key.(string); !ok {
+				return false
+			}
 		}
 	}
+	return true
+}
 
-	return pagination_interface.NewOffsetPagination(page, limit), nil
+func parseLimit(queryArgs *QueryArgs) (int64, error) {
+	var defaultLimit int64 = int64(20)
+
+	limitQuery := queryArgs.Get("limit")
+	if limitQuery == "" {
+		return defaultLimit, nil
+	}
+	limit, err := strconv.ParseInt(limitQuery, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidPage
+	}
+	if limit <= 0 {
+		return defaultLimit, nil
+	}
+	return limit, nil
 }
@@ -1,6 +1,10 @@
 package httpapi
 
 import (
+	"math"
+	"strconv"
+	"time"
+
 	pagination_interface "github.com/AstraProtocol/astra-indexing/appinterface/pagination"
 	"github.com/valyala/fasthttp"
 
@@ -32,13 +36,23 @@ func SuccessWithPagination(
 	paginationResult *pagination_interface.Result,
 ) {
 	ctx.Response.Header.Set("Content-Type", "application/json")
-	err := jsoniter.NewEncoder(ctx.Response.BodyWriter()).Encode(PagedResponse{
+	var response interface{} = PagedResponse{
 		Response: Response{
 			Result: result,
 			Err:    "",
 		},
 		OffsetPagination: OptPaginationOffsetResponseFromResult(paginationResult.OffsetResult()),
-	})
+	}
+	if cursorResult := paginationResult.CursorResult(); cursorResult != nil {
+		response = CursorPagedResponse{
+			Response: Response{
+				Result: result,
+				Err:    "",
+			},
+			CursorPagination: PaginationCursorResponseFromResult(ctx, cursorResult),
+		}
+	}
+	err := jsoniter.NewEncoder(ctx.Response.BodyWriter()).Encode(response)
 	if err != nil {
 		InternalServerError(ctx)
 	}
@@ -72,6 +86,38 @@ func BadRequest(ctx *fasthttp.RequestCtx, errResp error) {
 	ctx.SetBody(message)
 }
 
+func Unauthorized(ctx *fasthttp.RequestCtx, errResp error) {
+	errorResponse(ctx, fasthttp.StatusUnauthorized, errResp)
+}
+
+func Forbidden(ctx *fasthttp.RequestCtx, errResp error) {
+	errorResponse(ctx, fasthttp.StatusForbidden, errResp)
+}
+
+// TooManyRequests tells the client to retry after the duration, rounded up to the second
+func TooManyRequests(ctx *fasthttp.RequestCtx, errResp error, retryAfter time.Duration) {
+	retryAfterSeconds := int64(math.Ceil(retryAfter.Seconds()))
+	if retryAfterSeconds < 1 {
+		retryAfterSeconds = 1
+	}
+	ctx.Response.Header.Set("Retry-After", strconv.FormatInt(retryAfterSeconds, 10))
+	errorResponse(ctx, fasthttp.StatusTooManyRequests, errResp)
+}
+
+func errorResponse(ctx *fasthttp.RequestCtx, statusCode int, errResp error) {
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	message, err := jsoniter.Marshal(Response{
+		Err: errResp.Error(),
+	})
+	if err != nil {
+		InternalServerError(ctx)
+		return
+	}
+
+	ctx.SetStatusCode(statusCode)
+	ctx.SetBody(message)
+}
+
 func InternalServerError(ctx *fasthttp.RequestCtx) {
 	ctx.Response.Header.Set("Content-Type", "application/json")
 	message, _ := jsoniter.Marshal(Response{
@@ -88,6 +134,12 @@ type PagedResponse struct {
 	OffsetPagination *PaginationOffsetResponse `json:"pagination,omitempty"`
 }
 
+type CursorPagedResponse struct {
+	Response
+
+	CursorPagination *PaginationCursorResponse `json:"pagination"`
+}
+
 type Response struct {
 	Result interface{} `json:"result"`
 	Err    string      `json:"error,omitempty"`
@@ -114,3 +166,41 @@ func OptPaginationOffsetResponseFromResult(
 		Limit:       offsetResult.Limit,
 	}
 }
+
+// PaginationCursorResponse holds the opaque cursors of the previous and next pages, and the links to
+// them, empty when there is no such page
+type PaginationCursorResponse struct {
+	PrevCursor string `json:"prev_cursor"`
+	NextCursor string `json:"next_cursor"`
+	Prev       string `json:"prev"`
+	Next       string `json:"next"`
+	Limit      int64  `json:"limit"`
+}
+
+func PaginationCursorResponseFromResult(
+	ctx *fasthttp.RequestCtx,
+	cursorResult *pagination_interface.CursorResult,
+) *PaginationCursorResponse {
+	return &PaginationCursorResponse{
+		PrevCursor: cursorResult.PrevCursor,
+		NextCursor: cursorResult.NextCursor,
+		Prev:       cursorLink(ctx, cursorResult.PrevCursor),
+		Next:       cursorLink(ctx, cursorResult.NextCursor),
+		Limit:      cursorResult.Limit,
+	}
+}
+
+// cursorLink returns the request URI with the cursor replaced, empty without cursor
+func cursorLink(ctx *fasthttp.RequestCtx, cursor string) string {
+	if cursor == "" {
+		return ""
+	}
+
+	uri := &fasthttp.URI{}
+	ctx.URI().CopyTo(uri)
+	queryArgs := uri.QueryArgs()
+	queryArgs.Set("pagination", pagination_interface.PAGINATION_CURSOR)
+	queryArgs.Set("cursor", cursor)
+	queryArgs.Del("page")
+	return string(uri.RequestURI())
+}
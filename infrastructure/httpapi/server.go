@@ -2,6 +2,9 @@ package httpapi
 
 import (
 	"fmt"
+	"net"
+	"sync"
+
 	metrics "github.com/slok/go-http-metrics/metrics/prometheus"
 	"github.com/slok/go-http-metrics/middleware"
 	fasthttpmiddleware "github.com/slok/go-http-metrics/middleware/fasthttp"
@@ -22,6 +25,12 @@ type Server struct {
 	corsMiddleware   Middleware
 	loggerMiddleware Middleware
 	isAddMetric      bool
+
+	// Guards the serving state, Shutdown may be called before or while starting to serve
+	mutex      sync.Mutex
+	httpServer *fasthttp.Server
+	listener   net.Listener
+	isShutdown bool
 }
 
 func NewServer(listeningAddress string) *Server {
@@ -35,6 +44,11 @@ func NewServer(listeningAddress string) *Server {
 		nil,
 		nil,
 		false,
+
+		sync.Mutex{},
+		nil,
+		nil,
+		false,
 	}
 }
 
@@ -125,7 +139,50 @@ func (server *Server) ListenAndServe() error {
 
 		handler = fasthttpmiddleware.Handler("", mdlw, handler)
 	}
-	return fasthttp.ListenAndServe(server.listeningAddress, handler)
+
+	server.mutex.Lock()
+	if server.isShutdown {
+		server.mutex.Unlock()
+		return nil
+	}
+	listener, err := net.Listen("tcp4", server.listeningAddress)
+	if err != nil {
+		server.mutex.Unlock()
+		return err
+	}
+	httpServer := &fasthttp.Server{
+		Handler: handler,
+	}
+	server.httpServer = httpServer
+	server.listener = listener
+	server.mutex.Unlock()
+
+	err = httpServer.Serve(listener)
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if server.isShutdown {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops accepting new connections and waits for all the serving requests to complete. A
+// server shut down before serving never starts serving.
+func (server *Server) Shutdown() error {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.isShutdown {
+		return nil
+	}
+	server.isShutdown = true
+	if server.httpServer == nil {
+		return nil
+	}
+	err := server.httpServer.Shutdown()
+	// The listener is not registered to the fasthttp server yet when serving has just started
+	_ = server.listener.Close()
+	return err
 }
 
 type Middleware = func(fasthttp.RequestHandler) fasthttp.RequestHandler
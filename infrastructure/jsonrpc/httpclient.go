@@ -25,7 +25,7 @@ type HTTPClient struct {
 	logger     applogger.Logger
 	httpClient *retryablehttp.Client
 	url        string
-	httpCache  *cache.AstraCache
+	httpCache  cache.Cache
 }
 
 var (
@@ -0,0 +1,103 @@
+package jsonrpc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ERC20_NAME_SELECTOR   = "0x06fdde03"
+	ERC20_SYMBOL_SELECTOR = "0x95d89b41"
+)
+
+type TokenMetadata struct {
+	Name   string
+	Symbol string
+}
+
+// GetTokenMetadata calls the `name()` and `symbol()` methods of a token contract. The methods are optional, a
+// contract reverting them gets an empty name or symbol.
+func (client *HTTPClient) GetTokenMetadata(contractAddress string) (TokenMetadata, error) {
+	name, err := client.callStringMethod(contractAddress, ERC20_NAME_SELECTOR)
+	if err != nil {
+		return TokenMetadata{}, fmt.Errorf("error calling name of token %s: %v", contractAddress, err)
+	}
+	symbol, err := client.callStringMethod(contractAddress, ERC20_SYMBOL_SELECTOR)
+	if err != nil {
+		return TokenMetadata{}, fmt.Errorf("error calling symbol of token %s: %v", contractAddress, err)
+	}
+
+	return TokenMetadata{
+		Name:   name,
+		Symbol: symbol,
+	}, nil
+}
+
+func (client *HTTPClient) callStringMethod(contractAddress string, selector string) (string, error) {
+	payload := map[string]interface{}{
+		"id":      1,
+		"jsonrpc": "2.0",
+		"method":  "eth_call",
+		"params": []interface{}{
+			map[string]string{
+				"data": selector,
+				"to":   contractAddress,
+			},
+			"latest",
+		},
+	}
+
+	response, err := client.EthCall(payload)
+	if err != nil {
+		return "", err
+	}
+	if response.Error != nil {
+		return "", nil
+	}
+	result, ok := response.Result.(string)
+	if !ok {
+		return "", nil
+	}
+
+	return DecodeStringResult(result), nil
+}
+
+// DecodeStringResult decodes the ABI encoded string returned by a contract method. Legacy tokens returning a
+// `bytes32` get their bytes up to the first zero byte, and undecodable results an empty string.
+func DecodeStringResult(result string) string {
+	data, err := hex.DecodeString(strings.TrimPrefix(result, "0x"))
+	if err != nil {
+		return ""
+	}
+
+	var value []byte
+	if len(data) == 32 {
+		value = data
+		if end := strings.IndexByte(string(value), 0); end >= 0 {
+			value = value[:end]
+		}
+	} else {
+		if len(data) < 64 {
+			return ""
+		}
+		offset := new(big.Int).SetBytes(data[:32])
+		if !offset.IsInt64() || offset.Int64() > int64(len(data)-32) {
+			return ""
+		}
+		start := offset.Int64() + 32
+		length := new(big.Int).SetBytes(data[start-32 : start])
+		if !length.IsInt64() || length.Int64() > int64(len(data))-start {
+			return ""
+		}
+		value = data[start : start+length.Int64()]
+	}
+
+	if !utf8.Valid(value) {
+		return ""
+	}
+	// Postgres rejects the zero bytes in texts
+	return strings.TrimSpace(strings.ReplaceAll(string(value), "\x00", ""))
+}
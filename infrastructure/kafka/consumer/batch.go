@@ -0,0 +1,185 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const DEFAULT_BATCH_MAX_SIZE = 100
+const DEFAULT_BATCH_MAX_WAIT = 500 * time.Millisecond
+
+// BatchPolicy bounds the batches handed by FetchBatch
+type BatchPolicy struct {
+	// Maximum number of messages of a batch, 0 defaults to DEFAULT_BATCH_MAX_SIZE
+	MaxSize int
+	// Maximum time to gather a batch after its first message, 0 defaults to DEFAULT_BATCH_MAX_WAIT
+	MaxWait time.Duration
+}
+
+func (policy BatchPolicy) maxSize() int {
+	if policy.MaxSize <= 0 {
+		return DEFAULT_BATCH_MAX_SIZE
+	}
+	return policy.MaxSize
+}
+
+func (policy BatchPolicy) maxWait() time.Duration {
+	if policy.MaxWait <= 0 {
+		return DEFAULT_BATCH_MAX_WAIT
+	}
+	return policy.MaxWait
+}
+
+// FetchBatch hands the decoded messages to the handler in batches, until the consumer context is
+// done. A batch is gathered until it has Batch.MaxSize messages or Batch.MaxWait has passed since
+// its first message. Once a batch is handled, only the highest offset of every partition is
+// committed.
+//
+// A failing batch is retried with backoff according to Retry. When it still fails after all
+// attempts, its messages are handled one by one, such that only the failing ones are moved to the
+// dead letter topic, as Process does. Without DeadLetter, FetchBatch returns the error and leaves the
+// batch uncommitted.
+func (c *Consumer[T]) FetchBatch(handler func(ctx context.Context, models []T, messages []kafka.Message) error) error {
+	defer c.Close()
+	defer c.sampleLag()()
+	for {
+		messages, err := c.fetchBatch()
+		if c.Ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w from topic %s: %v", ErrFetch, c.Topic, err)
+		}
+		c.recordFetched(messages...)
+
+		ctx := context.Background()
+		if err = c.handleBatch(ctx, messages, handler); err != nil {
+			if c.Ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		lastMessages := lastMessagesByPartition(messages)
+		if err = c.commit(ctx, lastMessages...); err != nil {
+			c.Logger.Errorf("error committing %d messages of topic %s: %v", len(messages), c.Topic, err)
+		}
+	}
+}
+
+// fetchBatch waits for the first message of a batch, and gathers the following ones until the batch
+// is full or the maximum wait has passed
+func (c *Consumer[T]) fetchBatch() ([]kafka.Message, error) {
+	message, err := c.reader.FetchMessage(c.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	messages := []kafka.Message{message}
+
+	ctx, cancel := context.WithTimeout(c.Ctx, c.Batch.maxWait())
+	defer cancel()
+	for len(messages) < c.Batch.maxSize() {
+		message, err = c.reader.FetchMessage(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// handleBatch decodes the messages and hands them to the handler together, falling back to handing
+// them one by one when the batch fails after all attempts
+func (c *Consumer[T]) handleBatch(
+	ctx context.Context,
+	messages []kafka.Message,
+	handler func(ctx context.Context, models []T, messages []kafka.Message) error,
+) error {
+	models := make([]T, 0, len(messages))
+	decodedMessages := make([]kafka.Message, 0, len(messages))
+	for _, message := range messages {
+		var model T
+		if err := json.Unmarshal(message.Value, &model); err != nil {
+			// Decoding is never retried as it fails the same way every time
+			if failErr := c.fail(ctx, message, 1, fmt.Errorf("error decoding message: %v", err)); failErr != nil {
+				return failErr
+			}
+			continue
+		}
+		models = append(models, model)
+		decodedMessages = append(decodedMessages, message)
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	attempts, err := c.retry(
+		fmt.Sprintf("batch of %d messages of topic %s", len(models), c.Topic),
+		func() error {
+			return handler(ctx, models, decodedMessages)
+		},
+	)
+	if err == nil {
+		recordHandled(decodedMessages...)
+		return nil
+	}
+	if c.Ctx.Err() != nil {
+		return err
+	}
+	if len(models) == 1 {
+		return c.fail(ctx, decodedMessages[0], attempts, err)
+	}
+
+	c.Logger.Errorf(
+		"error handling batch of %d messages of topic %s after %d attempts, handling them one by one: %v",
+		len(models), c.Topic, attempts, err,
+	)
+	for i := range models {
+		message := decodedMessages[i]
+		attempts, err = c.retry(
+			fmt.Sprintf("message of topic %s at partition %d offset %d", message.Topic, message.Partition, message.Offset),
+			func() error {
+				return handler(ctx, models[i:i+1], decodedMessages[i:i+1])
+			},
+		)
+		if err == nil {
+			recordHandled(message)
+			continue
+		}
+		if c.Ctx.Err() != nil {
+			return err
+		}
+		if err = c.fail(ctx, message, attempts, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lastMessagesByPartition returns the message with the highest offset of every partition, ordered
+// by partition. Committing them commits all the messages.
+func lastMessagesByPartition(messages []kafka.Message) []kafka.Message {
+	lastMessages := make(map[int]kafka.Message)
+	for _, message := range messages {
+		if lastMessage, exist := lastMessages[message.Partition]; !exist || message.Offset > lastMessage.Offset {
+			lastMessages[message.Partition] = message
+		}
+	}
+
+	result := make([]kafka.Message, 0, len(lastMessages))
+	for _, message := range lastMessages {
+		result = append(result, message)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Partition < result[j].Partition
+	})
+	return result
+}
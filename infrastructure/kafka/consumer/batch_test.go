@@ -0,0 +1,127 @@
+package consumer_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/AstraProtocol/astra-indexing/external/logger/test"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer"
+	kafka_test "github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer/test"
+)
+
+var _ = Describe("FetchBatch", func() {
+	const topic = "fake-topic"
+	const deadLetterTopic = "fake-topic-dead-letter"
+	const groupId = "fake-group"
+
+	var broker *kafka_test.MemoryBroker
+	newBatchConsumer := func(ctx context.Context, maxSize int) *consumer.Consumer[fakePayload] {
+		fakeConsumer := &consumer.Consumer[fakePayload]{
+			Topic:   topic,
+			GroupId: groupId,
+			Retry: consumer.RetryPolicy{
+				MaxAttempts:     2,
+				InitialInterval: time.Millisecond,
+				MaxInterval:     time.Millisecond,
+			},
+			Batch: consumer.BatchPolicy{
+				MaxSize: maxSize,
+				MaxWait: 50 * time.Millisecond,
+			},
+			DeadLetter: consumer.NewDeadLetterProducer(broker.Writer(), deadLetterTopic),
+			Logger:     test.NewFakeLogger(),
+			Ctx:        ctx,
+		}
+		fakeConsumer.UseReader(broker.Reader(topic, groupId))
+		return fakeConsumer
+	}
+	valuesOf := func(models []fakePayload) []string {
+		values := make([]string, 0, len(models))
+		for _, model := range models {
+			values = append(values, model.Value)
+		}
+		return values
+	}
+
+	BeforeEach(func() {
+		broker = kafka_test.NewMemoryBroker()
+	})
+
+	It("should hand the messages in batches of up to the maximum size", func() {
+		for _, value := range []string{"a", "b", "c", "d", "e"} {
+			broker.Produce(topic, kafka.Message{Value: []byte(`{"value":"` + value + `"}`)})
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		batches := make([][]string, 0)
+		err := newBatchConsumer(ctx, 2).FetchBatch(func(_ context.Context, models []fakePayload, messages []kafka.Message) error {
+			Expect(messages).To(HaveLen(len(models)))
+			batches = append(batches, valuesOf(models))
+			if len(batches) == 3 {
+				cancel()
+			}
+			return nil
+		})
+
+		Expect(err).To(BeNil())
+		Expect(batches).To(Equal([][]string{{"a", "b"}, {"c", "d"}, {"e"}}))
+		Expect(broker.CommittedOffset(groupId, topic)).To(Equal(int64(5)))
+	})
+
+	It("should hand a partial batch once the maximum wait has passed", func() {
+		broker.Produce(topic, kafka.Message{Value: []byte(`{"value":"a"}`)})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		batches := make([][]string, 0)
+		err := newBatchConsumer(ctx, 10).FetchBatch(func(_ context.Context, models []fakePayload, _ []kafka.Message) error {
+			batches = append(batches, valuesOf(models))
+			cancel()
+			return nil
+		})
+
+		Expect(err).To(BeNil())
+		Expect(batches).To(Equal([][]string{{"a"}}))
+		Expect(broker.CommittedOffset(groupId, topic)).To(Equal(int64(1)))
+	})
+
+	It("should hand a failing batch one by one and move only the failing messages to the dead letter topic", func() {
+		broker.Produce(
+			topic,
+			kafka.Message{Value: []byte(`{"value":"a"}`)},
+			kafka.Message{Value: []byte(`not json`)},
+			kafka.Message{Value: []byte(`{"value":"failing"}`)},
+			kafka.Message{Value: []byte(`{"value":"c"}`)},
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		handledValues := make([]string, 0)
+		err := newBatchConsumer(ctx, 4).FetchBatch(func(_ context.Context, models []fakePayload, _ []kafka.Message) error {
+			for _, model := range models {
+				if model.Value == "failing" {
+					return errors.New("permanent failure")
+				}
+			}
+			handledValues = append(handledValues, valuesOf(models)...)
+			if len(handledValues) == 2 {
+				cancel()
+			}
+			return nil
+		})
+
+		Expect(err).To(BeNil())
+		Expect(handledValues).To(Equal([]string{"a", "c"}))
+		Expect(broker.CommittedOffset(groupId, topic)).To(Equal(int64(4)))
+		deadLetters := broker.Messages(deadLetterTopic)
+		Expect(deadLetters).To(HaveLen(2))
+		Expect(deadLetters[0].Value).To(Equal([]byte(`not json`)))
+		Expect(deadLetters[1].Value).To(Equal([]byte(`{"value":"failing"}`)))
+	})
+})
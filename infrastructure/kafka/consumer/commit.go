@@ -0,0 +1,29 @@
+package consumer
+
+import "time"
+
+const DEFAULT_COMMIT_INTERVAL = time.Second
+
+// CommitPolicy is when the offsets of the handled messages are committed to Kafka
+type CommitPolicy string
+
+const (
+	// Commits the offset of every message once handled, before the next message is fetched
+	COMMIT_POLICY_SYNC CommitPolicy = "sync"
+	// Commits the offsets in the background every DEFAULT_COMMIT_INTERVAL. It is faster, but the
+	// messages handled since the last commit are handled again after a crash, so the handler must
+	// be idempotent.
+	COMMIT_POLICY_PERIODIC CommitPolicy = "periodic"
+	// Records the offset of every message in the same database transaction as the views written by
+	// the handler with an OffsetStore, which skips the messages handled already. The offsets are
+	// also committed to Kafka periodically to track the lag, and the consumer group resumes from
+	// the recorded offsets on start.
+	COMMIT_POLICY_OFFSET_STORE CommitPolicy = "offset_store"
+)
+
+func (policy CommitPolicy) commitInterval() time.Duration {
+	if policy == COMMIT_POLICY_PERIODIC || policy == COMMIT_POLICY_OFFSET_STORE {
+		return DEFAULT_COMMIT_INTERVAL
+	}
+	return 0
+}
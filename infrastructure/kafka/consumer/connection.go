@@ -8,16 +8,29 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/segmentio/kafka-go"
 	"github.com/segmentio/kafka-go/sasl/scram"
 
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
 	utils "github.com/AstraProtocol/astra-indexing/infrastructure"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/metric/prometheus"
 )
 
+// MessageReader reads the messages of a topic as a member of a consumer group, it is implemented by
+// kafka.Reader
+type MessageReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
 type Consumer[T any] struct {
-	reader             *kafka.Reader
+	reader             MessageReader
 	Brokers            []string
 	Topic              string
 	GroupId            string
@@ -25,10 +38,40 @@ type Consumer[T any] struct {
 	User               string
 	Password           string
 	AuthenticationType string
-	Sigchan            chan os.Signal
 	CaCertPath         string
 	TlsCertPath        string
 	TlsKeyPath         string
+
+	// When the offsets of the handled messages are committed to Kafka
+	CommitPolicy CommitPolicy
+	// Retry of the messages failing to be handled by Process and FetchBatch
+	Retry RetryPolicy
+	// Size of the batches handed by FetchBatch
+	Batch BatchPolicy
+	// Receives the messages Process and FetchBatch fail to decode or to handle after all attempts,
+	// nil stops them on such messages instead
+	DeadLetter *DeadLetterProducer
+	// Logs the failures of messages, it is required by Process and FetchBatch
+	Logger applogger.Logger
+	// How often the lag is sampled from the reader stats, 0 defaults to DEFAULT_LAG_SAMPLE_INTERVAL
+	LagSampleInterval time.Duration
+
+	// Ctx stops the consumer once it is done. The message being processed is still committed.
+	Ctx context.Context
+
+	lagMutex sync.Mutex
+	// Partitions whose lag is recorded by the consumer
+	lagPartitions map[int]bool
+	// Partition of the last fetched message
+	lastFetchedPartition int
+	// Rebalances of the consumer group as of the last lag sample
+	rebalances int64
+}
+
+// UseReader makes the consumer read from the reader instead of connecting to the brokers, e.g. an
+// in-memory broker in tests
+func (c *Consumer[T]) UseReader(reader MessageReader) {
+	c.reader = reader
 }
 
 func (c *Consumer[T]) CreateConnection() error {
@@ -47,65 +90,309 @@ func (c *Consumer[T]) CreateConnection() error {
 		Dialer:                dialer,
 		WatchPartitionChanges: true,
 		ReadBackoffMax:        utils.KAFKA_READ_BACKOFF_MAX,
+		CommitInterval:        c.CommitPolicy.commitInterval(),
 		ErrorLogger:           kafka.LoggerFunc(logf),
 		//Logger:                kafka.LoggerFunc(logf),
 	})
 	return nil
 }
 
+// CreateWriter connects a writer to the brokers of the consumer with the same authentication. The
+// messages written must have their topic set.
+func (c *Consumer[T]) CreateWriter() (*kafka.Writer, error) {
+	transport, err := c.getTransport()
+	if err != nil {
+		return nil, err
+	}
+	return &kafka.Writer{
+		Addr:         kafka.TCP(c.Brokers...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		Transport:    transport,
+		ErrorLogger:  kafka.LoggerFunc(logf),
+	}, nil
+}
+
+// CreateDeadLetterProducer connects a producer of the dead letter topic to the brokers of the
+// consumer with the same authentication
+func (c *Consumer[T]) CreateDeadLetterProducer(topic string) (*DeadLetterProducer, error) {
+	writer, err := c.CreateWriter()
+	if err != nil {
+		return nil, err
+	}
+	return NewDeadLetterProducer(writer, topic), nil
+}
+
+// SeekToOffsets commits the next offsets to read of the partitions to the consumer group before the
+// consumer joins it, such that the group resumes from them. Only the offsets ahead of the ones
+// committed to Kafka are committed. It fails when the consumer group has active members.
+func (c *Consumer[T]) SeekToOffsets(ctx context.Context, offsets map[int]int64) error {
+	if len(offsets) == 0 {
+		return nil
+	}
+	transport, err := c.getTransport()
+	if err != nil {
+		return err
+	}
+	client := &kafka.Client{
+		Addr:      kafka.TCP(c.Brokers...),
+		Timeout:   c.TimeOut,
+		Transport: transport,
+	}
+
+	partitions := make([]int, 0, len(offsets))
+	for partition := range offsets {
+		partitions = append(partitions, partition)
+	}
+	fetchResponse, err := client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: c.GroupId,
+		Topics:  map[string][]int{c.Topic: partitions},
+	})
+	if err != nil {
+		return fmt.Errorf("error fetching committed offsets: %v", err)
+	}
+	if fetchResponse.Error != nil {
+		return fmt.Errorf("error fetching committed offsets: %v", fetchResponse.Error)
+	}
+
+	commits := make([]kafka.OffsetCommit, 0, len(offsets))
+	for _, partition := range fetchResponse.Topics[c.Topic] {
+		if partition.Error != nil {
+			return fmt.Errorf("error fetching committed offset of partition %d: %v", partition.Partition, partition.Error)
+		}
+		if offsets[partition.Partition] > partition.CommittedOffset {
+			commits = append(commits, kafka.OffsetCommit{
+				Partition: partition.Partition,
+				Offset:    offsets[partition.Partition],
+			})
+		}
+	}
+	if len(commits) == 0 {
+		return nil
+	}
+
+	// Offsets are only accepted outside of a generation while the group has no active member
+	commitResponse, err := client.OffsetCommit(ctx, &kafka.OffsetCommitRequest{
+		GroupID:      c.GroupId,
+		GenerationID: -1,
+		Topics:       map[string][]kafka.OffsetCommit{c.Topic: commits},
+	})
+	if err != nil {
+		return fmt.Errorf("error committing offsets: %v", err)
+	}
+	for _, partition := range commitResponse.Topics[c.Topic] {
+		if partition.Error != nil {
+			return fmt.Errorf("error committing offset of partition %d: %v", partition.Partition, partition.Error)
+		}
+	}
+	return nil
+}
+
 // Auto commit offset
 func (c *Consumer[T]) Read(model T, callback func(T, error)) {
+	defer c.Close()
 	for {
-		select {
-		case <-c.Sigchan:
-			c.Close()
+		message, err := c.reader.ReadMessage(c.Ctx)
+		if c.Ctx.Err() != nil {
 			return
-		default:
-			ctx := context.Background()
-			message, err := c.reader.ReadMessage(ctx)
-
-			if err != nil {
-				callback(model, err)
-				continue
-			}
+		}
 
-			err = json.Unmarshal(message.Value, &model)
+		if err != nil {
+			callback(model, err)
+			continue
+		}
 
-			if err != nil {
-				callback(model, err)
-				continue
-			}
+		err = json.Unmarshal(message.Value, &model)
 
-			callback(model, nil)
+		if err != nil {
+			callback(model, err)
+			continue
 		}
+
+		callback(model, nil)
 	}
 }
 
+// Fetch hands each message to the callback until the consumer context is done. The callback
+// context is not bound to the consumer context, such that the offset of the in-flight message can
+// still be committed during shutdown.
 func (c *Consumer[T]) Fetch(model T, callback func(T, kafka.Message, context.Context, error)) {
+	defer c.Close()
 	for {
-		select {
-		case <-c.Sigchan:
-			c.Close()
+		message, err := c.reader.FetchMessage(c.Ctx)
+		if c.Ctx.Err() != nil {
 			return
-		default:
-			ctx := context.Background()
-			message, err := c.reader.FetchMessage(ctx)
+		}
 
-			if err != nil {
-				callback(model, message, ctx, err)
-				continue
-			}
+		ctx := context.Background()
+		if err != nil {
+			callback(model, message, ctx, err)
+			continue
+		}
+
+		err = json.Unmarshal(message.Value, &model)
+
+		if err != nil {
+			callback(model, message, ctx, err)
+			continue
+		}
 
-			err = json.Unmarshal(message.Value, &model)
+		callback(model, message, ctx, nil)
+	}
+}
 
-			if err != nil {
-				callback(model, message, ctx, err)
-				continue
+// Process hands each decoded message to the handler and commits it once handled, until the
+// consumer context is done. A failing handler is retried with backoff according to Retry. Messages
+// failing to decode, or still failing after all attempts, are moved to the dead letter topic and
+// committed, such that they never stall the partition. So are the messages still not ready after
+// the not ready maximum elapsed time. Without DeadLetter, Process returns the error
+// of such a message and leaves it uncommitted.
+//
+// The handler context is not bound to the consumer context, such that the in-flight message is
+// still handled and committed during shutdown. Retries stop once the consumer context is done, the
+// message is then handled again on restart.
+func (c *Consumer[T]) Process(handler func(ctx context.Context, model T, message kafka.Message) error) error {
+	defer c.Close()
+	defer c.sampleLag()()
+	for {
+		message, err := c.reader.FetchMessage(c.Ctx)
+		if c.Ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w from topic %s: %v", ErrFetch, c.Topic, err)
+		}
+		c.recordFetched(message)
+
+		ctx := context.Background()
+		attempts, handleErr := c.handle(ctx, message, handler)
+		if handleErr != nil {
+			if c.Ctx.Err() != nil {
+				return nil
 			}
+			if err = c.fail(ctx, message, attempts, handleErr); err != nil {
+				return err
+			}
+		} else {
+			recordHandled(message)
+		}
+
+		if err = c.commit(ctx, message); err != nil {
+			c.Logger.Errorf(
+				"error committing message of topic %s at partition %d offset %d: %v",
+				message.Topic, message.Partition, message.Offset, err,
+			)
+		}
+	}
+}
+
+// handle decodes the message and hands it to the handler until it succeeds or the attempts are
+// exhausted, and returns the number of attempts
+func (c *Consumer[T]) handle(
+	ctx context.Context,
+	message kafka.Message,
+	handler func(ctx context.Context, model T, message kafka.Message) error,
+) (int, error) {
+	var model T
+	if err := json.Unmarshal(message.Value, &model); err != nil {
+		// Decoding is never retried as it fails the same way every time
+		return 1, fmt.Errorf("error decoding message: %v", err)
+	}
 
-			callback(model, message, ctx, nil)
+	return c.retry(
+		fmt.Sprintf("message of topic %s at partition %d offset %d", message.Topic, message.Partition, message.Offset),
+		func() error {
+			return handler(ctx, model, message)
+		},
+	)
+}
+
+// retry runs the handling of the described messages until it succeeds or the attempts are
+// exhausted, and returns the number of attempts. Handling failing with ErrNotReady is waited for
+// until it succeeds or fails otherwise, without counting towards the attempts limit, and is given up
+// once the not ready maximum elapsed time has passed.
+func (c *Consumer[T]) retry(description string, handle func() error) (int, error) {
+	attempts := 0
+	for {
+		err := backoff.RetryNotify(
+			func() error {
+				attempts += 1
+				if err := handle(); err != nil {
+					if errors.Is(err, ErrNotReady) {
+						return backoff.Permanent(err)
+					}
+					return err
+				}
+				return nil
+			},
+			c.Retry.backOff(c.Ctx),
+			func(err error, backoffDuration time.Duration) {
+				c.Logger.Errorf(
+					"error handling %s, retrying in %s: %v", description, backoffDuration.String(), err,
+				)
+			},
+		)
+		if !errors.Is(err, ErrNotReady) {
+			return attempts, err
+		}
+
+		err = backoff.RetryNotify(
+			func() error {
+				attempts += 1
+				if err := handle(); err != nil {
+					if !errors.Is(err, ErrNotReady) {
+						return backoff.Permanent(err)
+					}
+					return err
+				}
+				return nil
+			},
+			c.Retry.notReadyBackOff(c.Ctx),
+			func(err error, backoffDuration time.Duration) {
+				c.Logger.Infof(
+					"%s is not ready to be handled, waiting %s: %v", description, backoffDuration.String(), err,
+				)
+			},
+		)
+		if err == nil || c.Ctx.Err() != nil || errors.Is(err, ErrNotReady) {
+			return attempts, err
 		}
+		// Failing otherwise once ready, the handling is retried within the attempts limit again
+	}
+}
+
+// fail records the message failing to be handled and moves it to the dead letter topic. Without
+// DeadLetter, it returns the error of the message instead.
+func (c *Consumer[T]) fail(ctx context.Context, message kafka.Message, attempts int, handleErr error) error {
+	prometheus.RecordKafkaConsumerFailed(message.Topic, message.Partition, 1)
+	if c.DeadLetter == nil {
+		return fmt.Errorf(
+			"error handling message of topic %s at partition %d offset %d after %d attempts: %v",
+			message.Topic, message.Partition, message.Offset, attempts, handleErr,
+		)
 	}
+	return c.sendToDeadLetter(ctx, message, attempts, handleErr)
+}
+
+// sendToDeadLetter moves the message to the dead letter topic, retrying with backoff until it
+// succeeds or the attempts are exhausted
+func (c *Consumer[T]) sendToDeadLetter(ctx context.Context, message kafka.Message, attempts int, handleErr error) error {
+	c.Logger.Errorf(
+		"moving message of topic %s at partition %d offset %d to dead letter topic %s after %d attempts: %v",
+		message.Topic, message.Partition, message.Offset, c.DeadLetter.Topic(), attempts, handleErr,
+	)
+	if err := backoff.Retry(
+		func() error {
+			return c.DeadLetter.Send(ctx, message, c.GroupId, attempts, handleErr)
+		},
+		c.Retry.backOff(c.Ctx),
+	); err != nil {
+		return fmt.Errorf(
+			"error moving message of topic %s at partition %d offset %d to dead letter topic: %v",
+			message.Topic, message.Partition, message.Offset, err,
+		)
+	}
+	return nil
 }
 
 // Commit offset manual
@@ -114,6 +401,11 @@ func (c *Consumer[T]) Commit(ctx context.Context, msgs ...kafka.Message) error {
 }
 
 func (c *Consumer[T]) Close() error {
+	if c.DeadLetter != nil {
+		if err := c.DeadLetter.Close(); err != nil {
+			return err
+		}
+	}
 	return c.reader.Close()
 }
 
@@ -196,6 +488,18 @@ func (c *Consumer[T]) getDialer() (*kafka.Dialer, error) {
 	}
 }
 
+func (c *Consumer[T]) getTransport() (*kafka.Transport, error) {
+	dialer, err := c.getDialer()
+	if err != nil {
+		return nil, fmt.Errorf("error setup dialer: %v", err)
+	}
+	return &kafka.Transport{
+		Dial: dialer.DialFunc,
+		SASL: dialer.SASLMechanism,
+		TLS:  dialer.TLS,
+	}, nil
+}
+
 func logf(msg string, a ...interface{}) {
 	fmt.Printf(msg, a...)
 	fmt.Println()
@@ -0,0 +1,202 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/segmentio/kafka-go"
+)
+
+// Headers added to the messages moved to a dead letter topic
+const (
+	DEAD_LETTER_HEADER_TOPIC     = "x-original-topic"
+	DEAD_LETTER_HEADER_PARTITION = "x-original-partition"
+	DEAD_LETTER_HEADER_OFFSET    = "x-original-offset"
+	DEAD_LETTER_HEADER_GROUP_ID  = "x-group-id"
+	DEAD_LETTER_HEADER_ATTEMPTS  = "x-attempts"
+	DEAD_LETTER_HEADER_ERROR     = "x-error"
+)
+
+// MessageWriter writes messages to a topic, it is implemented by kafka.Writer
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// DeadLetterTopicOf returns the dead letter topic of the topic, empty when dead letter topics are
+// disabled by an empty suffix
+func DeadLetterTopicOf(topic string, suffix string) string {
+	if suffix == "" {
+		return ""
+	}
+	return topic + suffix
+}
+
+// DeadLetterProducer moves the messages which cannot be handled to a dead letter topic, together
+// with the error and where they were consumed from. The original key and value are kept, such that
+// the messages can be handled again once the cause is fixed.
+type DeadLetterProducer struct {
+	writer MessageWriter
+	topic  string
+}
+
+// NewDeadLetterProducer creates a producer of the dead letter topic. The writer must not have a
+// topic set, as the topic is set on every message.
+func NewDeadLetterProducer(writer MessageWriter, topic string) *DeadLetterProducer {
+	return &DeadLetterProducer{
+		writer: writer,
+		topic:  topic,
+	}
+}
+
+func (producer *DeadLetterProducer) Topic() string {
+	return producer.topic
+}
+
+func (producer *DeadLetterProducer) Send(
+	ctx context.Context,
+	message kafka.Message,
+	groupId string,
+	attempts int,
+	handleErr error,
+) error {
+	headers := make([]kafka.Header, 0, len(message.Headers)+6)
+	for _, header := range message.Headers {
+		if isDeadLetterHeader(header.Key) {
+			continue
+		}
+		headers = append(headers, header)
+	}
+	headers = append(headers,
+		kafka.Header{Key: DEAD_LETTER_HEADER_TOPIC, Value: []byte(message.Topic)},
+		kafka.Header{Key: DEAD_LETTER_HEADER_PARTITION, Value: []byte(strconv.Itoa(message.Partition))},
+		kafka.Header{Key: DEAD_LETTER_HEADER_OFFSET, Value: []byte(strconv.FormatInt(message.Offset, 10))},
+		kafka.Header{Key: DEAD_LETTER_HEADER_GROUP_ID, Value: []byte(groupId)},
+		kafka.Header{Key: DEAD_LETTER_HEADER_ATTEMPTS, Value: []byte(strconv.Itoa(attempts))},
+		kafka.Header{Key: DEAD_LETTER_HEADER_ERROR, Value: []byte(handleErr.Error())},
+	)
+
+	if err := producer.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   producer.topic,
+		Key:     message.Key,
+		Value:   message.Value,
+		Headers: headers,
+	}); err != nil {
+		return fmt.Errorf("error writing message to dead letter topic %s: %v", producer.topic, err)
+	}
+	return nil
+}
+
+func (producer *DeadLetterProducer) Close() error {
+	return producer.writer.Close()
+}
+
+// DeadLetter is where a message of a dead letter topic was originally consumed from and why it
+// could not be handled
+type DeadLetter struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	GroupId   string
+	Attempts  int
+	Error     string
+}
+
+// ParseDeadLetter reads the dead letter headers of a message consumed from a dead letter topic
+func ParseDeadLetter(message kafka.Message) (*DeadLetter, error) {
+	var (
+		deadLetter DeadLetter
+		found      = make(map[string]bool)
+		err        error
+	)
+	for _, header := range message.Headers {
+		value := string(header.Value)
+		switch header.Key {
+		case DEAD_LETTER_HEADER_TOPIC:
+			deadLetter.Topic = value
+		case DEAD_LETTER_HEADER_PARTITION:
+			deadLetter.Partition, err = strconv.Atoi(value)
+		case DEAD_LETTER_HEADER_OFFSET:
+			deadLetter.Offset, err = strconv.ParseInt(value, 10, 64)
+		case DEAD_LETTER_HEADER_GROUP_ID:
+			deadLetter.GroupId = value
+		case DEAD_LETTER_HEADER_ATTEMPTS:
+			deadLetter.Attempts, err = strconv.Atoi(value)
+		case DEAD_LETTER_HEADER_ERROR:
+			deadLetter.Error = value
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing dead letter header %s: %v", header.Key, err)
+		}
+		found[header.Key] = true
+	}
+	if !found[DEAD_LETTER_HEADER_TOPIC] || !found[DEAD_LETTER_HEADER_OFFSET] {
+		return nil, fmt.Errorf("message at offset %d is not a dead letter", message.Offset)
+	}
+
+	return &deadLetter, nil
+}
+
+func isDeadLetterHeader(key string) bool {
+	switch key {
+	case DEAD_LETTER_HEADER_TOPIC, DEAD_LETTER_HEADER_PARTITION, DEAD_LETTER_HEADER_OFFSET,
+		DEAD_LETTER_HEADER_GROUP_ID, DEAD_LETTER_HEADER_ATTEMPTS, DEAD_LETTER_HEADER_ERROR:
+		return true
+	}
+	return false
+}
+
+// ReplayDeadLetters hands the messages of the dead letter topic read by the consumer back to the
+// handler of their original topic, until no message has arrived for `idleTimeout` or the consumer
+// context is done. It stops on a message still failing after all attempts, which then stays
+// uncommitted in the dead letter topic. The consumer must not have a DeadLetter producer. It returns
+// the number of replayed messages.
+func ReplayDeadLetters[T any](
+	c *Consumer[T],
+	originalTopic string,
+	handler func(ctx context.Context, model T, message kafka.Message) error,
+	idleTimeout time.Duration,
+) (int, error) {
+	if c.DeadLetter != nil {
+		return 0, fmt.Errorf("dead letters of topic %s must not be moved to another dead letter topic", originalTopic)
+	}
+
+	ctx, cancel := context.WithCancel(c.Ctx)
+	defer cancel()
+	c.Ctx = ctx
+	idleTimer := time.AfterFunc(idleTimeout, cancel)
+	defer idleTimer.Stop()
+
+	replayed := 0
+	err := c.Process(func(handlerCtx context.Context, model T, message kafka.Message) error {
+		idleTimer.Stop()
+		defer idleTimer.Reset(idleTimeout)
+
+		deadLetter, err := ParseDeadLetter(message)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		if deadLetter.Topic != originalTopic {
+			return backoff.Permanent(fmt.Errorf(
+				"message at offset %d is a dead letter of topic %s instead of %s",
+				message.Offset, deadLetter.Topic, originalTopic,
+			))
+		}
+
+		if err = handler(handlerCtx, model, message); err != nil {
+			return err
+		}
+		replayed += 1
+		c.Logger.Infof(
+			"replayed dead letter of topic %s at partition %d offset %d",
+			deadLetter.Topic, deadLetter.Partition, deadLetter.Offset,
+		)
+		return nil
+	})
+	return replayed, err
+}
@@ -0,0 +1,225 @@
+package consumer_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/AstraProtocol/astra-indexing/external/logger/test"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer"
+	kafka_test "github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer/test"
+)
+
+type fakePayload struct {
+	Value string `json:"value"`
+}
+
+var _ = Describe("Consumer", func() {
+	const topic = "fake-topic"
+	const deadLetterTopic = "fake-topic-dead-letter"
+	const groupId = "fake-group"
+
+	var broker *kafka_test.MemoryBroker
+	newConsumer := func(ctx context.Context, withDeadLetter bool) *consumer.Consumer[fakePayload] {
+		fakeConsumer := &consumer.Consumer[fakePayload]{
+			Topic:   topic,
+			GroupId: groupId,
+			Retry: consumer.RetryPolicy{
+				MaxAttempts:         3,
+				InitialInterval:     time.Millisecond,
+				MaxInterval:         time.Millisecond,
+				NotReadyMaxInterval: time.Millisecond,
+			},
+			Logger: test.NewFakeLogger(),
+			Ctx:    ctx,
+		}
+		fakeConsumer.UseReader(broker.Reader(topic, groupId))
+		if withDeadLetter {
+			fakeConsumer.DeadLetter = consumer.NewDeadLetterProducer(broker.Writer(), deadLetterTopic)
+		}
+		return fakeConsumer
+	}
+
+	BeforeEach(func() {
+		broker = kafka_test.NewMemoryBroker()
+	})
+
+	Describe("Process", func() {
+		It("should retry failing messages and commit them once handled", func() {
+			broker.Produce(topic, kafka.Message{Value: []byte(`{"value":"first"}`)})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			attempts := 0
+			handledValues := make([]string, 0)
+			err := newConsumer(ctx, true).Process(func(_ context.Context, model fakePayload, _ kafka.Message) error {
+				attempts += 1
+				if attempts < 3 {
+					return errors.New("temporary failure")
+				}
+				handledValues = append(handledValues, model.Value)
+				cancel()
+				return nil
+			})
+
+			Expect(err).To(BeNil())
+			Expect(attempts).To(Equal(3))
+			Expect(handledValues).To(Equal([]string{"first"}))
+			Expect(broker.CommittedOffset(groupId, topic)).To(Equal(int64(1)))
+			Expect(broker.Messages(deadLetterTopic)).To(BeEmpty())
+		})
+
+		It("should move undecodable and exhausted messages to the dead letter topic", func() {
+			broker.Produce(
+				topic,
+				kafka.Message{Key: []byte("poison"), Value: []byte(`not json`)},
+				kafka.Message{Value: []byte(`{"value":"failing"}`)},
+				kafka.Message{Value: []byte(`{"value":"healthy"}`)},
+			)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := newConsumer(ctx, true).Process(func(_ context.Context, model fakePayload, _ kafka.Message) error {
+				if model.Value == "failing" {
+					return errors.New("permanent failure")
+				}
+				cancel()
+				return nil
+			})
+
+			Expect(err).To(BeNil())
+			Expect(broker.CommittedOffset(groupId, topic)).To(Equal(int64(3)))
+			deadLetters := broker.Messages(deadLetterTopic)
+			Expect(deadLetters).To(HaveLen(2))
+			Expect(deadLetters[0].Key).To(Equal([]byte("poison")))
+			Expect(deadLetters[0].Value).To(Equal([]byte(`not json`)))
+
+			deadLetter, err := consumer.ParseDeadLetter(deadLetters[1])
+			Expect(err).To(BeNil())
+			Expect(*deadLetter).To(Equal(consumer.DeadLetter{
+				Topic:     topic,
+				Partition: 0,
+				Offset:    1,
+				GroupId:   groupId,
+				Attempts:  3,
+				Error:     "permanent failure",
+			}))
+		})
+
+		It("should wait for messages not ready beyond the attempts without moving them to the dead letter topic", func() {
+			broker.Produce(topic, kafka.Message{Value: []byte(`{"value":"pending"}`)})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			attempts := 0
+			err := newConsumer(ctx, true).Process(func(_ context.Context, _ fakePayload, _ kafka.Message) error {
+				attempts += 1
+				if attempts < 10 {
+					return fmt.Errorf("dependency not indexed yet: %w", consumer.ErrNotReady)
+				}
+				cancel()
+				return nil
+			})
+
+			Expect(err).To(BeNil())
+			Expect(attempts).To(Equal(10))
+			Expect(broker.CommittedOffset(groupId, topic)).To(Equal(int64(1)))
+			Expect(broker.Messages(deadLetterTopic)).To(BeEmpty())
+		})
+
+		It("should move messages still not ready after the maximum wait to the dead letter topic", func() {
+			broker.Produce(
+				topic,
+				kafka.Message{Value: []byte(`{"value":"never-ready"}`)},
+				kafka.Message{Value: []byte(`{"value":"healthy"}`)},
+			)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			fakeConsumer := newConsumer(ctx, true)
+			fakeConsumer.Retry.NotReadyMaxElapsedTime = 20 * time.Millisecond
+			err := fakeConsumer.Process(func(_ context.Context, model fakePayload, _ kafka.Message) error {
+				if model.Value == "never-ready" {
+					return fmt.Errorf("dependency not indexed yet: %w", consumer.ErrNotReady)
+				}
+				cancel()
+				return nil
+			})
+
+			Expect(err).To(BeNil())
+			Expect(broker.CommittedOffset(groupId, topic)).To(Equal(int64(2)))
+			deadLetters := broker.Messages(deadLetterTopic)
+			Expect(deadLetters).To(HaveLen(1))
+			deadLetter, err := consumer.ParseDeadLetter(deadLetters[0])
+			Expect(err).To(BeNil())
+			Expect(deadLetter.Offset).To(Equal(int64(0)))
+			Expect(deadLetter.Error).To(Equal("dependency not indexed yet: not ready"))
+		})
+
+		It("should leave messages not ready uncommitted when stopped", func() {
+			broker.Produce(topic, kafka.Message{Value: []byte(`{"value":"pending"}`)})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			attempts := 0
+			err := newConsumer(ctx, true).Process(func(_ context.Context, _ fakePayload, _ kafka.Message) error {
+				attempts += 1
+				if attempts == 5 {
+					cancel()
+				}
+				return fmt.Errorf("dependency not indexed yet: %w", consumer.ErrNotReady)
+			})
+
+			Expect(err).To(BeNil())
+			Expect(broker.CommittedOffset(groupId, topic)).To(Equal(int64(0)))
+			Expect(broker.Messages(deadLetterTopic)).To(BeEmpty())
+		})
+
+		It("should stop on exhausted messages without dead letter topic", func() {
+			broker.Produce(topic, kafka.Message{Value: []byte(`{"value":"failing"}`)})
+
+			err := newConsumer(context.Background(), false).Process(
+				func(_ context.Context, _ fakePayload, _ kafka.Message) error {
+					return errors.New("permanent failure")
+				},
+			)
+
+			Expect(err).To(MatchError(ContainSubstring("after 3 attempts: permanent failure")))
+			Expect(broker.CommittedOffset(groupId, topic)).To(Equal(int64(0)))
+		})
+	})
+
+	Describe("ReplayDeadLetters", func() {
+		It("should hand the dead letters back to the handler until the dead letter topic is drained", func() {
+			broker.Produce(topic, kafka.Message{Value: []byte(`{"value":"failing"}`)})
+			deadLetterProducer := consumer.NewDeadLetterProducer(broker.Writer(), deadLetterTopic)
+			Expect(deadLetterProducer.Send(
+				context.Background(), broker.Messages(topic)[0], groupId, 3, errors.New("not fixed yet"),
+			)).To(Succeed())
+
+			replayedValues := make([]string, 0)
+			handler := func(_ context.Context, model fakePayload, _ kafka.Message) error {
+				replayedValues = append(replayedValues, model.Value)
+				return nil
+			}
+			replayConsumer := &consumer.Consumer[fakePayload]{
+				Topic:   deadLetterTopic,
+				GroupId: groupId + "-replay",
+				Logger:  test.NewFakeLogger(),
+				Ctx:     context.Background(),
+			}
+			replayConsumer.UseReader(broker.Reader(deadLetterTopic, groupId+"-replay"))
+			replayed, err := consumer.ReplayDeadLetters(replayConsumer, topic, handler, 50*time.Millisecond)
+
+			Expect(err).To(BeNil())
+			Expect(replayed).To(Equal(1))
+			Expect(replayedValues).To(Equal([]string{"failing"}))
+			Expect(broker.CommittedOffset(groupId+"-replay", deadLetterTopic)).To(Equal(int64(1)))
+		})
+	})
+})
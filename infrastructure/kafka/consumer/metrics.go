@@ -0,0 +1,146 @@
+package consumer
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/AstraProtocol/astra-indexing/infrastructure/metric/prometheus"
+)
+
+const DEFAULT_LAG_SAMPLE_INTERVAL = 15 * time.Second
+
+// statsReader is a reader reporting its stats, such as kafka.Reader
+type statsReader interface {
+	Stats() kafka.ReaderStats
+}
+
+// recordFetched records the lag of the partitions of the fetched messages. The lag is unknown when
+// the reader does not report the high water mark of the partition.
+func (c *Consumer[T]) recordFetched(messages ...kafka.Message) {
+	c.lagMutex.Lock()
+	defer c.lagMutex.Unlock()
+
+	for _, message := range lastMessagesByPartition(messages) {
+		c.lastFetchedPartition = message.Partition
+		if message.HighWaterMark <= 0 {
+			continue
+		}
+		lag := message.HighWaterMark - message.Offset - 1
+		if lag < 0 {
+			lag = 0
+		}
+		c.recordLag(message.Partition, lag)
+	}
+}
+
+// sampleLag records the lag reported by the reader stats periodically, such that the lag keeps
+// growing while no message is fetched, e.g. when the handler is stuck. The lag of all the partitions
+// is forgotten whenever the consumer group is rebalanced, as they may have been revoked, and once
+// the returned function is called when the consumer stops.
+func (c *Consumer[T]) sampleLag() (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		reader, ok := c.reader.(statsReader)
+		if !ok {
+			return
+		}
+		interval := c.LagSampleInterval
+		if interval <= 0 {
+			interval = DEFAULT_LAG_SAMPLE_INTERVAL
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				c.recordStats(reader.Stats())
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+		c.forgetLag()
+	}
+}
+
+// recordStats records the lag of the reader stats. A consumer group reader reports the lag of the
+// partition it read from last instead of its partition, which is the one of the last fetched message.
+func (c *Consumer[T]) recordStats(stats kafka.ReaderStats) {
+	c.lagMutex.Lock()
+	defer c.lagMutex.Unlock()
+
+	if stats.Rebalances != c.rebalances {
+		c.rebalances = stats.Rebalances
+		c.forgetLagPartitions()
+		return
+	}
+	if stats.Lag < 0 {
+		return
+	}
+	partition, err := strconv.Atoi(stats.Partition)
+	if err != nil {
+		return
+	}
+	if partition < 0 {
+		if !c.lagPartitions[c.lastFetchedPartition] {
+			return
+		}
+		partition = c.lastFetchedPartition
+	}
+	c.recordLag(partition, stats.Lag)
+}
+
+// recordLag records the lag of the partition, the caller must hold lagMutex
+func (c *Consumer[T]) recordLag(partition int, lag int64) {
+	if c.lagPartitions == nil {
+		c.lagPartitions = make(map[int]bool)
+	}
+	c.lagPartitions[partition] = true
+	prometheus.RecordKafkaConsumerLag(c.Topic, partition, lag)
+}
+
+// forgetLag forgets the lag of all the partitions recorded by the consumer
+func (c *Consumer[T]) forgetLag() {
+	c.lagMutex.Lock()
+	defer c.lagMutex.Unlock()
+
+	c.forgetLagPartitions()
+}
+
+// forgetLagPartitions forgets the lag of all the partitions, the caller must hold lagMutex
+func (c *Consumer[T]) forgetLagPartitions() {
+	for partition := range c.lagPartitions {
+		prometheus.ForgetKafkaConsumerPartition(c.Topic, partition)
+	}
+	c.lagPartitions = nil
+}
+
+// recordHandled records the messages handled successfully
+func recordHandled(messages ...kafka.Message) {
+	counts := make(map[int]int)
+	for _, message := range messages {
+		counts[message.Partition] += 1
+	}
+	now := time.Now()
+	for _, message := range lastMessagesByPartition(messages) {
+		prometheus.RecordKafkaConsumerProcessed(message.Topic, message.Partition, counts[message.Partition])
+		prometheus.RecordKafkaConsumerLastWrite(message.Topic, message.Partition, now)
+	}
+}
+
+// commit commits the messages and records the time it took
+func (c *Consumer[T]) commit(ctx context.Context, messages ...kafka.Message) error {
+	startTime := time.Now()
+	err := c.reader.CommitMessages(ctx, messages...)
+	prometheus.RecordKafkaConsumerCommitLatency(c.Topic, time.Since(startTime).Milliseconds())
+	return err
+}
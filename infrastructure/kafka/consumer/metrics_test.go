@@ -0,0 +1,112 @@
+package consumer_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/AstraProtocol/astra-indexing/external/logger/test"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer"
+	kafka_test "github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer/test"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/metric/prometheus"
+)
+
+// fakeStatsReader reports the stats of a consumer group reader
+type fakeStatsReader struct {
+	*kafka_test.MemoryReader
+
+	mutex sync.Mutex
+	stats kafka.ReaderStats
+}
+
+func (reader *fakeStatsReader) Stats() kafka.ReaderStats {
+	reader.mutex.Lock()
+	defer reader.mutex.Unlock()
+	return reader.stats
+}
+
+func (reader *fakeStatsReader) setStats(stats kafka.ReaderStats) {
+	reader.mutex.Lock()
+	defer reader.mutex.Unlock()
+	reader.stats = stats
+}
+
+var _ = Describe("Consumer metrics", func() {
+	const topic = "fake-metrics-topic"
+	const groupId = "fake-group"
+
+	It("should record the lag of the partition as of the last fetched message", func() {
+		broker := kafka_test.NewMemoryBroker()
+		broker.Produce(
+			topic,
+			kafka.Message{Value: []byte(`{"value":"a"}`)},
+			kafka.Message{Value: []byte(`{"value":"b"}`)},
+			kafka.Message{Value: []byte(`{"value":"c"}`)},
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fakeConsumer := &consumer.Consumer[fakePayload]{
+			Topic:   topic,
+			GroupId: groupId,
+			Logger:  test.NewFakeLogger(),
+			Ctx:     ctx,
+		}
+		fakeConsumer.UseReader(broker.Reader(topic, groupId))
+
+		lags := make([]int64, 0)
+		err := fakeConsumer.Process(func(_ context.Context, model fakePayload, _ kafka.Message) error {
+			lags = append(lags, prometheus.KafkaConsumerLagByTopic()[topic])
+			if model.Value == "c" {
+				cancel()
+			}
+			return nil
+		})
+
+		Expect(err).To(BeNil())
+		Expect(lags).To(Equal([]int64{2, 1, 0}))
+	})
+
+	It("should sample the lag from the reader stats and forget it on rebalance and once stopped", func() {
+		const statsTopic = "fake-stats-topic"
+		broker := kafka_test.NewMemoryBroker()
+		broker.Produce(statsTopic, kafka.Message{Value: []byte(`{"value":"stuck"}`)})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		reader := &fakeStatsReader{MemoryReader: broker.Reader(statsTopic, groupId)}
+		reader.setStats(kafka.ReaderStats{Partition: "-1", Lag: 42})
+		fakeConsumer := &consumer.Consumer[fakePayload]{
+			Topic:             statsTopic,
+			GroupId:           groupId,
+			Logger:            test.NewFakeLogger(),
+			LagSampleInterval: time.Millisecond,
+			Ctx:               ctx,
+		}
+		fakeConsumer.UseReader(reader)
+
+		lagOf := func() int64 {
+			return prometheus.KafkaConsumerLagByTopic()[statsTopic]
+		}
+		err := fakeConsumer.Process(func(_ context.Context, _ fakePayload, _ kafka.Message) error {
+			// The handler is stuck while more messages are produced
+			Eventually(lagOf).Should(Equal(int64(42)))
+
+			reader.setStats(kafka.ReaderStats{Partition: "-1", Lag: 42, Rebalances: 1})
+			Eventually(func() bool {
+				_, ok := prometheus.KafkaConsumerLagByTopic()[statsTopic]
+				return ok
+			}).Should(BeFalse())
+
+			cancel()
+			return nil
+		})
+
+		Expect(err).To(BeNil())
+		Expect(prometheus.KafkaConsumerLagByTopic()).NotTo(HaveKey(statsTopic))
+	})
+})
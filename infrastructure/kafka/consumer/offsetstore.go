@@ -0,0 +1,273 @@
+package consumer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+)
+
+const DEFAULT_OFFSETS_TABLE = "kafka_offsets"
+
+// Offsets table should have the following schema
+// | Field       | Data Type | Constraint                              |
+// | ----------- | --------- | --------------------------------------- |
+// | group_id    | VARCHAR   | PRIMARY KEY(group_id, topic, partition) |
+// | topic       | VARCHAR   |                                         |
+// | partition   | INT       |                                         |
+// | next_offset | INT64     | NOT NULL                                |
+// | updated_at  | INT64     | NOT NULL                                |
+
+// OffsetStore records the offsets of the handled messages in the same database transaction as the
+// views written by the handler, such that every message has its effects exactly once even when it
+// is delivered again by Kafka.
+type OffsetStore struct {
+	rdbConn rdb.Conn
+	table   string
+}
+
+func NewOffsetStore(rdbConn rdb.Conn) *OffsetStore {
+	return &OffsetStore{
+		rdbConn: rdbConn,
+		table:   DEFAULT_OFFSETS_TABLE,
+	}
+}
+
+// GetOffsets returns the next offset to handle of every partition of the topic with a handled
+// message
+func (store *OffsetStore) GetOffsets(groupId string, topic string) (map[int]int64, error) {
+	rdbHandle := store.rdbConn.ToHandle()
+	sql, args, err := rdbHandle.StmtBuilder.Select(
+		"partition", "next_offset",
+	).From(
+		store.table,
+	).Where(
+		"group_id = ? AND topic = ?", groupId, topic,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building offsets selection SQL: %v", err)
+	}
+
+	rows, err := rdbHandle.Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing offsets selection SQL: %v", err)
+	}
+	defer rows.Close()
+
+	offsets := make(map[int]int64)
+	for rows.Next() {
+		var (
+			partition  int
+			nextOffset int64
+		)
+		if err = rows.Scan(&partition, &nextOffset); err != nil {
+			return nil, fmt.Errorf("error scanning offset row: %v", err)
+		}
+		offsets[partition] = nextOffset
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating offset rows: %v", err)
+	}
+
+	return offsets, nil
+}
+
+// HandleOnce runs `handle` with a transaction recording the message as handled by the consumer
+// group. The message is skipped when it has been handled already, and nothing `handle` writes is
+// kept when it fails.
+func (store *OffsetStore) HandleOnce(
+	groupId string,
+	message kafka.Message,
+	handle func(rdbHandle *rdb.Handle) error,
+) (bool, error) {
+	rdbTx, err := store.rdbConn.Begin()
+	if err != nil {
+		return false, fmt.Errorf("error beginning transaction: %v", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	claimed, err := store.claimWithRDbHandle(rdbTxHandle, groupId, message)
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	if err = handle(rdbTxHandle); err != nil {
+		return false, err
+	}
+
+	if err = rdbTx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing transaction: %v", err)
+	}
+	committed = true
+	return true, nil
+}
+
+// HandleBatchOnce runs `handle` with a transaction recording all the messages as handled by the
+// consumer group. `handle` receives the indexes of the messages not handled yet, and is not run when
+// all of them have been handled already. It returns the number of messages handled.
+func (store *OffsetStore) HandleBatchOnce(
+	groupId string,
+	messages []kafka.Message,
+	handle func(rdbHandle *rdb.Handle, pending []int) error,
+) (int, error) {
+	rdbTx, err := store.rdbConn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("error beginning transaction: %v", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	pending, err := store.claimBatchWithRDbHandle(rdbTxHandle, groupId, messages)
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	if err = handle(rdbTxHandle, pending); err != nil {
+		return 0, err
+	}
+
+	if err = rdbTx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing transaction: %v", err)
+	}
+	committed = true
+	return len(pending), nil
+}
+
+// claimBatchWithRDbHandle advances the next offset of every partition past its last message, and
+// returns the indexes of the messages not handled yet
+func (store *OffsetStore) claimBatchWithRDbHandle(
+	rdbHandle *rdb.Handle,
+	groupId string,
+	messages []kafka.Message,
+) ([]int, error) {
+	type partitionKey struct {
+		topic     string
+		partition int
+	}
+	indexesByPartition := make(map[partitionKey][]int)
+	partitions := make([]partitionKey, 0)
+	for i, message := range messages {
+		key := partitionKey{message.Topic, message.Partition}
+		if _, exist := indexesByPartition[key]; !exist {
+			partitions = append(partitions, key)
+		}
+		indexesByPartition[key] = append(indexesByPartition[key], i)
+	}
+
+	pending := make([]int, 0, len(messages))
+	for _, key := range partitions {
+		indexes := indexesByPartition[key]
+		nextOffset, err := store.lockOffsetWithRDbHandle(rdbHandle, groupId, key.topic, key.partition)
+		if err != nil {
+			return nil, err
+		}
+
+		lastOffset := int64(-1)
+		for _, i := range indexes {
+			if messages[i].Offset < nextOffset {
+				continue
+			}
+			pending = append(pending, i)
+			if messages[i].Offset > lastOffset {
+				lastOffset = messages[i].Offset
+			}
+		}
+		if lastOffset < 0 {
+			continue
+		}
+		claimed, err := store.claimWithRDbHandle(rdbHandle, groupId, kafka.Message{
+			Topic:     key.topic,
+			Partition: key.partition,
+			Offset:    lastOffset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		// The partition had no offset to lock, and another consumer has recorded one meanwhile
+		if !claimed {
+			return nil, fmt.Errorf("offset of topic %s partition %d is recorded concurrently", key.topic, key.partition)
+		}
+	}
+	sort.Ints(pending)
+
+	return pending, nil
+}
+
+// lockOffsetWithRDbHandle returns the next offset to handle of the partition, 0 when none has been
+// handled, and locks it until the transaction ends
+func (store *OffsetStore) lockOffsetWithRDbHandle(
+	rdbHandle *rdb.Handle,
+	groupId string,
+	topic string,
+	partition int,
+) (int64, error) {
+	sql, args, err := rdbHandle.StmtBuilder.Select(
+		"next_offset",
+	).From(
+		store.table,
+	).Where(
+		"group_id = ? AND topic = ? AND partition = ?", groupId, topic, partition,
+	).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error building offset selection SQL: %v", err)
+	}
+
+	var nextOffset int64
+	if err = rdbHandle.QueryRow(sql, args...).Scan(&nextOffset); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("error querying offset: %v", err)
+	}
+	return nextOffset, nil
+}
+
+// claimWithRDbHandle advances the next offset of the partition past the message, unless the
+// message has been handled already. The row lock it takes serializes the consumers handling the same
+// partition until the transaction ends.
+func (store *OffsetStore) claimWithRDbHandle(rdbHandle *rdb.Handle, groupId string, message kafka.Message) (bool, error) {
+	sql, args, err := rdbHandle.StmtBuilder.Insert(
+		store.table,
+	).Columns(
+		"group_id", "topic", "partition", "next_offset", "updated_at",
+	).Values(
+		groupId, message.Topic, message.Partition, message.Offset+1, utctime.Now().UnixNano(),
+	).Suffix(
+		fmt.Sprintf(
+			"ON CONFLICT (group_id, topic, partition) DO UPDATE SET "+
+				"next_offset = EXCLUDED.next_offset, updated_at = EXCLUDED.updated_at "+
+				"WHERE %s.next_offset < EXCLUDED.next_offset",
+			store.table,
+		),
+	).ToSql()
+	if err != nil {
+		return false, fmt.Errorf("error building offset claim SQL: %v", err)
+	}
+
+	result, err := rdbHandle.Exec(sql, args...)
+	if err != nil {
+		return false, fmt.Errorf("error executing offset claim SQL: %v", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
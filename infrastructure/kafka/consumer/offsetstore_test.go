@@ -0,0 +1,132 @@
+package consumer_test
+
+import (
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	rdb_test "github.com/AstraProtocol/astra-indexing/appinterface/rdb/test"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer"
+)
+
+var _ = Describe("OffsetStore", func() {
+	const groupId = "fake-group"
+	message := kafka.Message{Topic: "fake-topic", Partition: 2, Offset: 41}
+
+	var (
+		mockConn     *rdb_test.MockRDbConn
+		mockTx       *rdb_test.MockRDbTx
+		mockTxHandle *rdb.Handle
+	)
+	BeforeEach(func() {
+		mockConn = rdb_test.NewMockRDbConn()
+		mockTx = &rdb_test.MockRDbTx{}
+		mockTxHandle = &rdb.Handle{
+			Runner: mockTx,
+			StmtBuilder: &rdb.StatementBuilder{
+				StatementBuilderType: sq.StatementBuilder,
+			},
+		}
+		mockConn.On("Begin").Return(mockTx, nil)
+		mockTx.On("ToHandle").Return(mockTxHandle)
+		mockTx.On("Rollback").Return(nil).Maybe()
+	})
+
+	mockClaim := func(rowsAffected int64) {
+		mockExecResult := &rdb_test.MockRDbExecResult{}
+		mockExecResult.On("RowsAffected").Return(rowsAffected)
+		mockTx.On(
+			"Exec",
+			mock.MatchedBy(func(sql string) bool {
+				return sql == "INSERT INTO kafka_offsets (group_id,topic,partition,next_offset,updated_at) "+
+					"VALUES (?,?,?,?,?) ON CONFLICT (group_id, topic, partition) DO UPDATE SET "+
+					"next_offset = EXCLUDED.next_offset, updated_at = EXCLUDED.updated_at "+
+					"WHERE kafka_offsets.next_offset < EXCLUDED.next_offset"
+			}),
+			groupId, "fake-topic", 2, int64(42), mock.Anything,
+		).Return(mockExecResult, nil)
+	}
+
+	It("should handle the message with the transaction recording its offset", func() {
+		mockClaim(1)
+		mockTx.On("Commit").Return(nil)
+
+		var handleHandle *rdb.Handle
+		handled, err := consumer.NewOffsetStore(mockConn).HandleOnce(groupId, message, func(rdbHandle *rdb.Handle) error {
+			handleHandle = rdbHandle
+			return nil
+		})
+
+		Expect(err).To(BeNil())
+		Expect(handled).To(BeTrue())
+		Expect(handleHandle).To(Equal(mockTxHandle))
+		mockTx.AssertCalled(GinkgoT(), "Commit")
+		mockTx.AssertNotCalled(GinkgoT(), "Rollback")
+	})
+
+	It("should skip the message handled already", func() {
+		mockClaim(0)
+
+		handled, err := consumer.NewOffsetStore(mockConn).HandleOnce(groupId, message, func(_ *rdb.Handle) error {
+			Fail("handled message handled again")
+			return nil
+		})
+
+		Expect(err).To(BeNil())
+		Expect(handled).To(BeFalse())
+		mockTx.AssertNotCalled(GinkgoT(), "Commit")
+	})
+
+	It("should roll back the offset when handling fails", func() {
+		mockClaim(1)
+
+		handled, err := consumer.NewOffsetStore(mockConn).HandleOnce(groupId, message, func(_ *rdb.Handle) error {
+			return errors.New("handle error")
+		})
+
+		Expect(err).To(MatchError("handle error"))
+		Expect(handled).To(BeFalse())
+		mockTx.AssertCalled(GinkgoT(), "Rollback")
+		mockTx.AssertNotCalled(GinkgoT(), "Commit")
+	})
+
+	It("should hand the messages of a batch not handled yet with the transaction recording their offsets", func() {
+		mockRowResult := &rdb_test.MockRDbRowResult{}
+		mockRowResult.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*int64) = 43
+		}).Return(nil)
+		mockTx.On(
+			"QueryRow",
+			"SELECT next_offset FROM kafka_offsets WHERE group_id = ? AND topic = ? AND partition = ? FOR UPDATE",
+			groupId, "fake-topic", 2,
+		).Return(mockRowResult)
+		mockExecResult := &rdb_test.MockRDbExecResult{}
+		mockExecResult.On("RowsAffected").Return(int64(1))
+		mockTx.On("Exec", mock.Anything, groupId, "fake-topic", 2, int64(45), mock.Anything).Return(mockExecResult, nil)
+		mockTx.On("Commit").Return(nil)
+
+		messages := []kafka.Message{
+			{Topic: "fake-topic", Partition: 2, Offset: 41},
+			{Topic: "fake-topic", Partition: 2, Offset: 42},
+			{Topic: "fake-topic", Partition: 2, Offset: 43},
+			{Topic: "fake-topic", Partition: 2, Offset: 44},
+		}
+		var handlePending []int
+		handledCount, err := consumer.NewOffsetStore(mockConn).HandleBatchOnce(
+			groupId, messages, func(_ *rdb.Handle, pending []int) error {
+				handlePending = pending
+				return nil
+			},
+		)
+
+		Expect(err).To(BeNil())
+		Expect(handledCount).To(Equal(2))
+		Expect(handlePending).To(Equal([]int{2, 3}))
+		mockTx.AssertCalled(GinkgoT(), "Commit")
+	})
+})
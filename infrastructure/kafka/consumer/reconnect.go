@@ -0,0 +1,64 @@
+package consumer
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// ErrFetch is wrapped by the errors of Process and FetchBatch failing to fetch messages from the
+// brokers, which are transient as opposed to the messages failing to be handled
+var ErrFetch = errors.New("error fetching messages")
+
+// RunReconnecting runs the connected consumer with `run` until its context is done. When fetching
+// messages fails, e.g. during a broker outage, the consumer is replaced by the one returned by
+// `reconnect`, with exponential backoff according to Retry without attempts limit. Any other error,
+// such as a message failing to be handled without dead letter topic, is returned.
+func RunReconnecting[T any](
+	c *Consumer[T],
+	reconnect func() (*Consumer[T], error),
+	run func(c *Consumer[T]) error,
+) error {
+	ctx := c.Ctx
+	topic := c.Topic
+	logger := c.Logger
+	reconnectBackOff := c.Retry.reconnectBackOff(ctx)
+	maxInterval := c.Retry.maxInterval()
+	for {
+		startedAt := time.Now()
+		err := run(c)
+		if err == nil || ctx.Err() != nil {
+			return nil
+		}
+		if !errors.Is(err, ErrFetch) {
+			return err
+		}
+		// A consumer having run for a while starts over from the shortest backoff
+		if time.Since(startedAt) > maxInterval {
+			reconnectBackOff.Reset()
+		}
+
+		for {
+			backoffDuration := reconnectBackOff.NextBackOff()
+			if backoffDuration == backoff.Stop {
+				return nil
+			}
+			logger.Errorf("reconnecting consumer of topic %s in %s: %v", topic, backoffDuration.String(), err)
+			timer := time.NewTimer(backoffDuration)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+			case <-timer.C:
+			}
+
+			reconnected, reconnectErr := reconnect()
+			if reconnectErr == nil {
+				c = reconnected
+				break
+			}
+			err = reconnectErr
+		}
+	}
+}
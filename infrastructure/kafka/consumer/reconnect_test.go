@@ -0,0 +1,96 @@
+package consumer_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/AstraProtocol/astra-indexing/external/logger/test"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer"
+	kafka_test "github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer/test"
+)
+
+var _ = Describe("RunReconnecting", func() {
+	const topic = "fake-topic"
+	const groupId = "fake-group"
+
+	var broker *kafka_test.MemoryBroker
+	newConsumer := func(ctx context.Context, reader consumer.MessageReader) *consumer.Consumer[fakePayload] {
+		fakeConsumer := &consumer.Consumer[fakePayload]{
+			Topic:   topic,
+			GroupId: groupId,
+			Retry: consumer.RetryPolicy{
+				InitialInterval: time.Millisecond,
+				MaxInterval:     time.Millisecond,
+			},
+			Logger: test.NewFakeLogger(),
+			Ctx:    ctx,
+		}
+		fakeConsumer.UseReader(reader)
+		return fakeConsumer
+	}
+
+	BeforeEach(func() {
+		broker = kafka_test.NewMemoryBroker()
+	})
+
+	It("should reconnect the consumer failing to fetch messages", func() {
+		broker.Produce(topic, kafka.Message{Value: []byte(`{"value":"first"}`)})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		failingReader := broker.Reader(topic, groupId)
+		Expect(failingReader.Close()).To(Succeed())
+
+		reconnections := 0
+		reconnect := func() (*consumer.Consumer[fakePayload], error) {
+			reconnections += 1
+			if reconnections == 1 {
+				return nil, errors.New("brokers unreachable")
+			}
+			return newConsumer(ctx, broker.Reader(topic, groupId)), nil
+		}
+		handledValues := make([]string, 0)
+		err := consumer.RunReconnecting(
+			newConsumer(ctx, failingReader),
+			reconnect,
+			func(c *consumer.Consumer[fakePayload]) error {
+				return c.Process(func(_ context.Context, model fakePayload, _ kafka.Message) error {
+					handledValues = append(handledValues, model.Value)
+					cancel()
+					return nil
+				})
+			},
+		)
+
+		Expect(err).To(BeNil())
+		Expect(reconnections).To(Equal(2))
+		Expect(handledValues).To(Equal([]string{"first"}))
+		Expect(broker.CommittedOffset(groupId, topic)).To(Equal(int64(1)))
+	})
+
+	It("should return the errors other than fetching failures", func() {
+		broker.Produce(topic, kafka.Message{Value: []byte(`{"value":"failing"}`)})
+
+		reconnections := 0
+		err := consumer.RunReconnecting(
+			newConsumer(context.Background(), broker.Reader(topic, groupId)),
+			func() (*consumer.Consumer[fakePayload], error) {
+				reconnections += 1
+				return newConsumer(context.Background(), broker.Reader(topic, groupId)), nil
+			},
+			func(c *consumer.Consumer[fakePayload]) error {
+				return c.Process(func(_ context.Context, _ fakePayload, _ kafka.Message) error {
+					return errors.New("permanent failure")
+				})
+			},
+		)
+
+		Expect(err).To(MatchError(ContainSubstring("permanent failure")))
+		Expect(reconnections).To(Equal(0))
+	})
+})
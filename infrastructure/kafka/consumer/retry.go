@@ -0,0 +1,88 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+const DEFAULT_MAX_ATTEMPTS = 5
+const DEFAULT_RETRY_INITIAL_INTERVAL = 500 * time.Millisecond
+const DEFAULT_RETRY_MAX_INTERVAL = 30 * time.Second
+const DEFAULT_NOT_READY_MAX_INTERVAL = 5 * time.Minute
+const DEFAULT_NOT_READY_MAX_ELAPSED_TIME = 1 * time.Hour
+
+// ErrNotReady is wrapped by the handler errors of messages which cannot be handled yet, e.g. as they
+// depend on data not indexed yet. Such messages are waited for without attempts limit, up to the
+// not ready maximum elapsed time, before they are given up.
+var ErrNotReady = errors.New("not ready")
+
+// RetryPolicy is how many times and how often a message failing to be handled is handed to the
+// handler again before it is given up. Zero values are replaced by the defaults.
+type RetryPolicy struct {
+	MaxAttempts     int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	// NotReadyMaxInterval is the longest interval between the attempts of a message not ready yet
+	NotReadyMaxInterval time.Duration
+	// NotReadyMaxElapsedTime is how long a message not ready yet is waited for before it is given up
+	NotReadyMaxElapsedTime time.Duration
+}
+
+// backOff returns the exponential backoff between the attempts, which stops after the last attempt
+// or once the context is done
+func (policy RetryPolicy) backOff(ctx context.Context) backoff.BackOff {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DEFAULT_MAX_ATTEMPTS
+	}
+	return backoff.WithContext(
+		backoff.WithMaxRetries(policy.exponentialBackOff(policy.maxInterval()), uint64(maxAttempts-1)), ctx,
+	)
+}
+
+// reconnectBackOff returns the exponential backoff between the reconnections of a consumer, which
+// only stops once the context is done
+func (policy RetryPolicy) reconnectBackOff(ctx context.Context) backoff.BackOff {
+	return backoff.WithContext(policy.exponentialBackOff(policy.maxInterval()), ctx)
+}
+
+// notReadyBackOff returns the exponential backoff between the attempts of a message not ready yet,
+// which stops once the maximum elapsed time has passed or the context is done
+func (policy RetryPolicy) notReadyBackOff(ctx context.Context) backoff.BackOff {
+	maxInterval := DEFAULT_NOT_READY_MAX_INTERVAL
+	if policy.NotReadyMaxInterval > 0 {
+		maxInterval = policy.NotReadyMaxInterval
+	}
+	maxElapsedTime := DEFAULT_NOT_READY_MAX_ELAPSED_TIME
+	if policy.NotReadyMaxElapsedTime > 0 {
+		maxElapsedTime = policy.NotReadyMaxElapsedTime
+	}
+
+	exponentialBackOff := policy.exponentialBackOff(maxInterval)
+	exponentialBackOff.MaxElapsedTime = maxElapsedTime
+	exponentialBackOff.Reset()
+	return backoff.WithContext(exponentialBackOff, ctx)
+}
+
+func (policy RetryPolicy) maxInterval() time.Duration {
+	if policy.MaxInterval > 0 {
+		return policy.MaxInterval
+	}
+	return DEFAULT_RETRY_MAX_INTERVAL
+}
+
+func (policy RetryPolicy) exponentialBackOff(maxInterval time.Duration) *backoff.ExponentialBackOff {
+	exponentialBackOff := backoff.NewExponentialBackOff()
+	exponentialBackOff.InitialInterval = DEFAULT_RETRY_INITIAL_INTERVAL
+	if policy.InitialInterval > 0 {
+		exponentialBackOff.InitialInterval = policy.InitialInterval
+	}
+	exponentialBackOff.MaxInterval = maxInterval
+	exponentialBackOff.MaxElapsedTime = 0
+	exponentialBackOff.Reset()
+
+	return exponentialBackOff
+}
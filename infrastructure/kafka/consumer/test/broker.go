@@ -0,0 +1,166 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer"
+)
+
+var _ consumer.MessageReader = &MemoryReader{}
+var _ consumer.MessageWriter = &MemoryWriter{}
+
+// MemoryBroker is an in-memory stand-in of Kafka brokers with single partition topics. Consumer
+// groups resume from their last committed offset.
+type MemoryBroker struct {
+	mutex  sync.Mutex
+	topics map[string][]kafka.Message
+	// Next offset to read of each topic by consumer group
+	committedOffsets map[string]map[string]int64
+	// Closed and replaced whenever a message is produced
+	produced chan struct{}
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		topics:           make(map[string][]kafka.Message),
+		committedOffsets: make(map[string]map[string]int64),
+		produced:         make(chan struct{}),
+	}
+}
+
+// Produce appends the messages to the topic
+func (broker *MemoryBroker) Produce(topic string, msgs ...kafka.Message) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	for _, msg := range msgs {
+		msg.Topic = topic
+		msg.Partition = 0
+		msg.Offset = int64(len(broker.topics[topic]))
+		broker.topics[topic] = append(broker.topics[topic], msg)
+	}
+	close(broker.produced)
+	broker.produced = make(chan struct{})
+}
+
+// Messages returns all the messages of the topic
+func (broker *MemoryBroker) Messages(topic string) []kafka.Message {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	return append([]kafka.Message{}, broker.topics[topic]...)
+}
+
+// CommittedOffset returns the next offset the consumer group reads from the topic
+func (broker *MemoryBroker) CommittedOffset(groupId string, topic string) int64 {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	return broker.committedOffsets[groupId][topic]
+}
+
+// Reader returns a reader of the topic for the consumer group starting from its committed offset
+func (broker *MemoryBroker) Reader(topic string, groupId string) *MemoryReader {
+	return &MemoryReader{
+		broker:  broker,
+		topic:   topic,
+		groupId: groupId,
+
+		offset: broker.CommittedOffset(groupId, topic),
+	}
+}
+
+// Writer returns a writer producing to the topic of each message
+func (broker *MemoryBroker) Writer() *MemoryWriter {
+	return &MemoryWriter{
+		broker: broker,
+	}
+}
+
+type MemoryReader struct {
+	broker  *MemoryBroker
+	topic   string
+	groupId string
+
+	offset int64
+	closed bool
+}
+
+func (reader *MemoryReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	for {
+		reader.broker.mutex.Lock()
+		if reader.closed {
+			reader.broker.mutex.Unlock()
+			return kafka.Message{}, errors.New("reader closed")
+		}
+		messages := reader.broker.topics[reader.topic]
+		produced := reader.broker.produced
+		if reader.offset < int64(len(messages)) {
+			message := messages[reader.offset]
+			message.HighWaterMark = int64(len(messages))
+			reader.offset += 1
+			reader.broker.mutex.Unlock()
+			return message, nil
+		}
+		reader.broker.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return kafka.Message{}, ctx.Err()
+		case <-produced:
+		}
+	}
+}
+
+func (reader *MemoryReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	message, err := reader.FetchMessage(ctx)
+	if err != nil {
+		return message, err
+	}
+	return message, reader.CommitMessages(ctx, message)
+}
+
+func (reader *MemoryReader) CommitMessages(_ context.Context, msgs ...kafka.Message) error {
+	reader.broker.mutex.Lock()
+	defer reader.broker.mutex.Unlock()
+
+	if reader.broker.committedOffsets[reader.groupId] == nil {
+		reader.broker.committedOffsets[reader.groupId] = make(map[string]int64)
+	}
+	for _, msg := range msgs {
+		if msg.Offset+1 > reader.broker.committedOffsets[reader.groupId][msg.Topic] {
+			reader.broker.committedOffsets[reader.groupId][msg.Topic] = msg.Offset + 1
+		}
+	}
+	return nil
+}
+
+func (reader *MemoryReader) Close() error {
+	reader.broker.mutex.Lock()
+	defer reader.broker.mutex.Unlock()
+
+	reader.closed = true
+	return nil
+}
+
+type MemoryWriter struct {
+	broker *MemoryBroker
+}
+
+func (writer *MemoryWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	for _, msg := range msgs {
+		if msg.Topic == "" {
+			return errors.New("message topic is not set")
+		}
+		writer.broker.Produce(msg.Topic, msg)
+	}
+	return nil
+}
+
+func (writer *MemoryWriter) Close() error {
+	return nil
+}
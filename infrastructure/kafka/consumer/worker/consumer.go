@@ -0,0 +1,63 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AstraProtocol/astra-indexing/bootstrap/config"
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	utils "github.com/AstraProtocol/astra-indexing/infrastructure"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer"
+)
+
+// NewConsumer creates a consumer of the topic for the consumer group with the connection and retry
+// settings of the Kafka service. It is not connected yet.
+func NewConsumer[T any](
+	ctx context.Context,
+	config *config.Config,
+	topic string,
+	groupId string,
+	logger applogger.Logger,
+) *consumer.Consumer[T] {
+	return &consumer.Consumer[T]{
+		TimeOut:            utils.KAFKA_TIME_OUT,
+		Brokers:            config.KafkaService.Brokers,
+		Topic:              topic,
+		GroupId:            groupId,
+		User:               config.KafkaService.User,
+		Password:           config.KafkaService.Password,
+		AuthenticationType: config.KafkaService.AuthenticationType,
+		Ctx:                ctx,
+		CaCertPath:         config.KafkaService.CaCertPath,
+		TlsCertPath:        config.KafkaService.TlsCertPath,
+		TlsKeyPath:         config.KafkaService.TlsKeyPath,
+		Retry: consumer.RetryPolicy{
+			MaxAttempts:            config.KafkaService.MaxAttempts,
+			InitialInterval:        time.Duration(config.KafkaService.RetryInitialIntervalMs) * time.Millisecond,
+			MaxInterval:            time.Duration(config.KafkaService.RetryMaxIntervalMs) * time.Millisecond,
+			NotReadyMaxElapsedTime: time.Duration(config.KafkaService.NotReadyMaxWaitMs) * time.Millisecond,
+		},
+		Logger: logger,
+	}
+}
+
+// connectConsumer connects the consumer of the Kafka service and the producer of its dead letter
+// topic, unless dead letter topics are disabled
+func connectConsumer[T any](c *consumer.Consumer[T], config *config.Config) error {
+	if err := c.CreateConnection(); err != nil {
+		return err
+	}
+
+	deadLetterTopic := consumer.DeadLetterTopicOf(c.Topic, config.KafkaService.DeadLetterTopicSuffix)
+	if deadLetterTopic == "" {
+		return nil
+	}
+	deadLetter, err := c.CreateDeadLetterProducer(deadLetterTopic)
+	if err != nil {
+		_ = c.Close()
+		return fmt.Errorf("error creating dead letter producer of topic %s: %v", c.Topic, err)
+	}
+	c.DeadLetter = deadLetter
+	return nil
+}
@@ -2,86 +2,66 @@ package consumer
 
 import (
 	"context"
+	"fmt"
 	"math/big"
-	"os"
 
 	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
-	"github.com/AstraProtocol/astra-indexing/bootstrap/config"
 	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer"
 	"github.com/segmentio/kafka-go"
 
-	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
 	utils "github.com/AstraProtocol/astra-indexing/infrastructure"
 	accountTransactionView "github.com/AstraProtocol/astra-indexing/projection/account_transaction/view"
 	transactionView "github.com/AstraProtocol/astra-indexing/projection/transaction/view"
 )
 
-func RunEvmTxsConsumer(rdbHandle *rdb.Handle, config *config.Config, logger applogger.Logger, sigchan chan os.Signal) error {
-	rdbTransactionView := transactionView.NewTransactionsView(rdbHandle)
-	rdbAccountTransactionDataView := accountTransactionView.NewAccountTransactionData(rdbHandle)
+// DEFAULT_EVM_TXS_BATCH_SIZE is the number of messages of the evm txs topic updated together, the
+// topic is write-bound while Blockscout backfills
+const DEFAULT_EVM_TXS_BATCH_SIZE = 100
 
-	evmTxsConsumer := consumer.Consumer[[]consumer.CollectedEvmTx]{
-		TimeOut:            utils.KAFKA_TIME_OUT,
-		Brokers:            config.KafkaService.Brokers,
-		Topic:              utils.EVM_TXS_TOPIC,
-		GroupId:            config.KafkaService.GroupID,
-		User:               config.KafkaService.User,
-		Password:           config.KafkaService.Password,
-		AuthenticationType: config.KafkaService.AuthenticationType,
-		Sigchan:            sigchan,
-		CaCertPath:         config.KafkaService.CaCertPath,
-		TlsCertPath:        config.KafkaService.TlsCertPath,
-		TlsKeyPath:         config.KafkaService.TlsKeyPath,
-	}
-	errConn := evmTxsConsumer.CreateConnection()
-	if errConn != nil {
-		return errConn
-	}
+func init() {
+	TopicHandlers.Register(NewBatchTopicHandler(
+		utils.EVM_TXS_TOPIC,
+		consumer.COMMIT_POLICY_OFFSET_STORE,
+		DEFAULT_EVM_TXS_BATCH_SIZE,
+		func(params TopicHandlerParams) func(context.Context, [][]consumer.CollectedEvmTx, []kafka.Message) error {
+			return NewEvmTxsHandler(params.RdbHandle)
+		},
+	))
+}
 
-	logger = logger.WithFields(applogger.LogFields{
-		"module": "EvmTxsConsumer",
-	})
+// NewEvmTxsHandler returns the handler of the batches of messages of the evm txs topic, which
+// updates the fee and status of the indexed transactions of all the messages at once
+func NewEvmTxsHandler(rdbHandle *rdb.Handle) func(context.Context, [][]consumer.CollectedEvmTx, []kafka.Message) error {
+	rdbTransactionView := transactionView.NewTransactionsView(rdbHandle)
+	rdbAccountTransactionDataView := accountTransactionView.NewAccountTransactionData(rdbHandle)
 
-	var mapValues []map[string]interface{}
-	evmTxsConsumer.Fetch(
-		[]consumer.CollectedEvmTx{},
-		func(collectedEvmTxs []consumer.CollectedEvmTx, message kafka.Message, ctx context.Context, err error) {
-			if err != nil {
-				logger.Infof("Kafka Evm Txs Consumer error: %v", err)
-			} else {
-				mapValues = nil
-				for _, evmTx := range collectedEvmTxs {
-					feeValue := big.NewInt(0).Mul(big.NewInt(evmTx.GasUsed), big.NewInt(evmTx.GasPrice)).String()
-					isSuccess := true
-					if evmTx.Status == "error" {
-						isSuccess = false
-					}
-					mapValue := map[string]interface{}{
-						"evm_hash":  evmTx.TransactionHash,
-						"fee_value": feeValue,
-						"success":   isSuccess,
-					}
-					mapValues = append(mapValues, mapValue)
+	return func(_ context.Context, batch [][]consumer.CollectedEvmTx, messages []kafka.Message) error {
+		var mapValues []map[string]interface{}
+		for _, collectedEvmTxs := range batch {
+			for _, evmTx := range collectedEvmTxs {
+				feeValue := big.NewInt(0).Mul(big.NewInt(evmTx.GasUsed), big.NewInt(evmTx.GasPrice)).String()
+				isSuccess := true
+				if evmTx.Status == "error" {
+					isSuccess = false
 				}
-
-				if len(mapValues) > 0 {
-					errUpdate := rdbTransactionView.UpdateAll(mapValues)
-					if errUpdate == nil {
-						errUpdateTxData := rdbAccountTransactionDataView.UpdateAll(mapValues)
-						// commit offset
-						if errUpdateTxData == nil {
-							if errCommit := evmTxsConsumer.Commit(ctx, message); errCommit != nil {
-								logger.Infof("Topic: %s. Consumer partition %d failed to commit messages: %v", utils.EVM_TXS_TOPIC, message.Partition, errCommit)
-							}
-						} else {
-							logger.Infof("Failed to update account txs data from Consumer partition %d: %v", utils.EVM_TXS_TOPIC, message.Partition, errUpdate)
-						}
-					} else {
-						logger.Infof("Failed to update txs from Consumer partition %d: %v", message.Partition, errUpdate)
-					}
+				mapValue := map[string]interface{}{
+					"evm_hash":  evmTx.TransactionHash,
+					"fee_value": feeValue,
+					"success":   isSuccess,
 				}
+				mapValues = append(mapValues, mapValue)
 			}
-		},
-	)
-	return nil
+		}
+
+		if len(mapValues) == 0 {
+			return nil
+		}
+		if err := rdbTransactionView.UpdateAll(mapValues); err != nil {
+			return fmt.Errorf("failed to update txs from %d consumer messages: %v", len(messages), err)
+		}
+		if err := rdbAccountTransactionDataView.UpdateAll(mapValues); err != nil {
+			return fmt.Errorf("failed to update account txs data from %d consumer messages: %v", len(messages), err)
+		}
+		return nil
+	}
 }
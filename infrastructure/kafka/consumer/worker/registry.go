@@ -0,0 +1,361 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/bootstrap/config"
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/jsonrpc"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/metric/prometheus"
+	"github.com/AstraProtocol/astra-indexing/internal/evm"
+)
+
+const DEAD_LETTER_REPLAY_GROUP_ID_SUFFIX = "-dead-letter-replay"
+const DEFAULT_CONCURRENCY = 1
+
+// TopicHandlers holds the handlers of all the topics the service is able to consume. Every worker
+// file registers its handler on init.
+var TopicHandlers = NewRegistry()
+
+// TopicHandlerParams are the dependencies handed to the topic handlers
+type TopicHandlerParams struct {
+	// RdbConn begins the transactions of the topics with COMMIT_POLICY_OFFSET_STORE
+	RdbConn   rdb.Conn
+	RdbHandle *rdb.Handle
+	Config    *config.Config
+	Logger    applogger.Logger
+	EvmUtil   evm.EvmUtils
+	// JsonrpcClient reads the metadata of the tokens, nil when no JSON-RPC URL is configured
+	JsonrpcClient *jsonrpc.HTTPClient
+}
+
+// NewJsonrpcClient returns the JSON-RPC client of the topic handler params, nil when no JSON-RPC URL is
+// configured
+func NewJsonrpcClient(logger applogger.Logger, config *config.Config) *jsonrpc.HTTPClient {
+	if config.JsonrpcApp.HTTPJSONRPCUrl == "" {
+		return nil
+	}
+	return jsonrpc.NewHTTPClient(logger, config.JsonrpcApp.HTTPJSONRPCUrl)
+}
+
+// TopicHandler consumes a Kafka topic, it is declared with NewTopicHandler
+type TopicHandler interface {
+	Topic() string
+	// Run consumes the topic with the settings of the consumer until the context is done
+	Run(ctx context.Context, params TopicHandlerParams, consumerConfig config.KafkaConsumer) error
+	// ReplayDeadLetters hands the messages of the dead letter topic of the topic back to the handler,
+	// until the dead letter topic has been idle for `idleTimeout`. It returns the number of replayed
+	// messages.
+	ReplayDeadLetters(ctx context.Context, params TopicHandlerParams, idleTimeout time.Duration) (int, error)
+}
+
+// NewTopicHandler declares the handler of the messages of a topic, whose values are JSON encoded T.
+// `newHandler` creates the function handling each decoded message. With COMMIT_POLICY_OFFSET_STORE
+// it is called for every message with `params.RdbHandle` of the transaction recording its offset.
+func NewTopicHandler[T any](
+	topic string,
+	commitPolicy consumer.CommitPolicy,
+	newHandler func(params TopicHandlerParams) func(context.Context, T, kafka.Message) error,
+) TopicHandler {
+	return &topicHandler[T]{
+		topic:        topic,
+		commitPolicy: commitPolicy,
+		batchSize:    1,
+		newBatchHandler: func(params TopicHandlerParams) func(context.Context, []T, []kafka.Message) error {
+			handle := newHandler(params)
+			return func(ctx context.Context, models []T, messages []kafka.Message) error {
+				for i := range models {
+					if err := handle(ctx, models[i], messages[i]); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		},
+	}
+}
+
+// NewBatchTopicHandler declares the handler of the messages of a topic in batches of up to
+// `batchSize` messages, whose values are JSON encoded T. `newHandler` creates the function handling
+// each batch of decoded messages, which should write them in one DB round trip. With
+// COMMIT_POLICY_OFFSET_STORE it is called for every batch with `params.RdbHandle` of the transaction
+// recording its offsets, and only receives the messages not handled yet.
+func NewBatchTopicHandler[T any](
+	topic string,
+	commitPolicy consumer.CommitPolicy,
+	batchSize int,
+	newHandler func(params TopicHandlerParams) func(context.Context, []T, []kafka.Message) error,
+) TopicHandler {
+	return &topicHandler[T]{
+		topic:           topic,
+		commitPolicy:    commitPolicy,
+		batchSize:       batchSize,
+		newBatchHandler: newHandler,
+	}
+}
+
+type topicHandler[T any] struct {
+	topic        string
+	commitPolicy consumer.CommitPolicy
+	// Messages are handed one by one when it is not above 1
+	batchSize       int
+	newBatchHandler func(params TopicHandlerParams) func(context.Context, []T, []kafka.Message) error
+}
+
+func (handler *topicHandler[T]) Topic() string {
+	return handler.topic
+}
+
+// Run starts a consumer per concurrency within the same consumer group, such that the partitions
+// of the topic are shared among them. A consumer failing to fetch messages is reconnected with
+// backoff, all of them are only stopped when any of them fails otherwise.
+func (handler *topicHandler[T]) Run(
+	ctx context.Context,
+	params TopicHandlerParams,
+	consumerConfig config.KafkaConsumer,
+) error {
+	groupId := consumerConfig.GroupID
+	if groupId == "" {
+		groupId = params.Config.KafkaService.GroupID
+	}
+	concurrency := consumerConfig.Concurrency
+	if concurrency <= 0 {
+		concurrency = DEFAULT_CONCURRENCY
+	}
+	batchSize := handler.batchSize
+	if consumerConfig.BatchSize > 0 {
+		batchSize = consumerConfig.BatchSize
+	}
+	handle := handler.newBatchHandler(params)
+	if handler.commitPolicy == consumer.COMMIT_POLICY_OFFSET_STORE {
+		var err error
+		if handle, err = handler.newOffsetStoreHandler(ctx, params, groupId); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, concurrency)
+	for i := 0; i < concurrency; i++ {
+		logger := params.Logger.WithFields(applogger.LogFields{
+			"module":   "Consumer",
+			"topic":    handler.topic,
+			"consumer": i,
+		})
+		connect := func() (*consumer.Consumer[T], error) {
+			topicConsumer := NewConsumer[T](ctx, params.Config, handler.topic, groupId, logger)
+			topicConsumer.CommitPolicy = handler.commitPolicy
+			topicConsumer.Batch = consumer.BatchPolicy{
+				MaxSize: batchSize,
+				MaxWait: time.Duration(consumerConfig.BatchMaxWaitMs) * time.Millisecond,
+			}
+			if err := connectConsumer(topicConsumer, params.Config); err != nil {
+				return nil, err
+			}
+			return topicConsumer, nil
+		}
+		topicConsumer, err := connect()
+		if err != nil {
+			cancel()
+			wg.Wait()
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := consumer.RunReconnecting(topicConsumer, connect, func(c *consumer.Consumer[T]) error {
+				if batchSize > 1 {
+					return c.FetchBatch(handle)
+				}
+				return c.Process(singleMessageHandler(handle))
+			})
+			if err != nil {
+				errCh <- err
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+
+	return <-errCh
+}
+
+// newOffsetStoreHandler returns a handler handling every message in a transaction recording its
+// offset, and resumes the consumer group from the recorded offsets. The group is only resumed when
+// no consumer of it is running, the recorded offsets still skip the messages handled already.
+func (handler *topicHandler[T]) newOffsetStoreHandler(
+	ctx context.Context,
+	params TopicHandlerParams,
+	groupId string,
+) (func(context.Context, []T, []kafka.Message) error, error) {
+	logger := params.Logger.WithFields(applogger.LogFields{
+		"module": "OffsetStore",
+		"topic":  handler.topic,
+	})
+	offsetStore := consumer.NewOffsetStore(params.RdbConn)
+
+	offsets, err := offsetStore.GetOffsets(groupId, handler.topic)
+	if err != nil {
+		return nil, fmt.Errorf("error getting recorded offsets of topic %s: %v", handler.topic, err)
+	}
+	seekingConsumer := NewConsumer[T](ctx, params.Config, handler.topic, groupId, logger)
+	if err = seekingConsumer.SeekToOffsets(ctx, offsets); err != nil {
+		logger.Errorf("error resuming consumer group %s from recorded offsets: %v", groupId, err)
+	}
+
+	return handler.offsetStoreHandler(params, groupId, offsetStore, logger), nil
+}
+
+// offsetStoreHandler returns a handler handling every message in a transaction recording its offset
+// for the consumer group, skipping the messages handled already
+func (handler *topicHandler[T]) offsetStoreHandler(
+	params TopicHandlerParams,
+	groupId string,
+	offsetStore *consumer.OffsetStore,
+	logger applogger.Logger,
+) func(context.Context, []T, []kafka.Message) error {
+	return func(ctx context.Context, models []T, messages []kafka.Message) error {
+		if len(messages) == 1 {
+			handled, err := offsetStore.HandleOnce(groupId, messages[0], func(rdbHandle *rdb.Handle) error {
+				txParams := params
+				txParams.RdbHandle = rdbHandle
+				return handler.newBatchHandler(txParams)(ctx, models, messages)
+			})
+			if err != nil {
+				return err
+			}
+			if !handled {
+				prometheus.RecordKafkaConsumerSkipped(messages[0].Topic, messages[0].Partition, 1)
+				logger.Debugf(
+					"skipped message at partition %d offset %d handled already", messages[0].Partition, messages[0].Offset,
+				)
+			}
+			return nil
+		}
+
+		// All the messages are skipped when none of them is pending
+		skipped := messages
+		handledCount, err := offsetStore.HandleBatchOnce(groupId, messages, func(rdbHandle *rdb.Handle, pending []int) error {
+			skipped = skippedMessages(messages, pending)
+			txParams := params
+			txParams.RdbHandle = rdbHandle
+			pendingModels := make([]T, 0, len(pending))
+			pendingMessages := make([]kafka.Message, 0, len(pending))
+			for _, i := range pending {
+				pendingModels = append(pendingModels, models[i])
+				pendingMessages = append(pendingMessages, messages[i])
+			}
+			return handler.newBatchHandler(txParams)(ctx, pendingModels, pendingMessages)
+		})
+		if err != nil {
+			return err
+		}
+		for _, message := range skipped {
+			prometheus.RecordKafkaConsumerSkipped(message.Topic, message.Partition, 1)
+		}
+		if handledCount < len(messages) {
+			logger.Debugf("skipped %d messages of batch handled already", len(messages)-handledCount)
+		}
+		return nil
+	}
+}
+
+func (handler *topicHandler[T]) ReplayDeadLetters(
+	ctx context.Context,
+	params TopicHandlerParams,
+	idleTimeout time.Duration,
+) (int, error) {
+	deadLetterTopic := consumer.DeadLetterTopicOf(handler.topic, params.Config.KafkaService.DeadLetterTopicSuffix)
+	if deadLetterTopic == "" {
+		return 0, fmt.Errorf("dead letter topics are disabled")
+	}
+	logger := params.Logger.WithFields(applogger.LogFields{
+		"module": "DeadLetterReplay",
+		"topic":  handler.topic,
+	})
+
+	// The replay has its own consumer group, such that it is run without stopping the consumers
+	groupId := params.Config.KafkaService.GroupID + DEAD_LETTER_REPLAY_GROUP_ID_SUFFIX
+	deadLetterConsumer := NewConsumer[T](ctx, params.Config, deadLetterTopic, groupId, logger)
+	if err := deadLetterConsumer.CreateConnection(); err != nil {
+		return 0, err
+	}
+	// The dead letters are recorded in the offset store like the messages of the topic, such that
+	// each of them is replayed within a transaction and only once
+	handle := handler.newBatchHandler(params)
+	if handler.commitPolicy == consumer.COMMIT_POLICY_OFFSET_STORE {
+		handle = handler.offsetStoreHandler(params, groupId, consumer.NewOffsetStore(params.RdbConn), logger)
+	}
+	return consumer.ReplayDeadLetters(deadLetterConsumer, handler.topic, singleMessageHandler(handle), idleTimeout)
+}
+
+// skippedMessages returns the messages of the batch not pending to be handled
+func skippedMessages(messages []kafka.Message, pending []int) []kafka.Message {
+	isPending := make(map[int]bool, len(pending))
+	for _, i := range pending {
+		isPending[i] = true
+	}
+	skipped := make([]kafka.Message, 0, len(messages)-len(pending))
+	for i, message := range messages {
+		if !isPending[i] {
+			skipped = append(skipped, message)
+		}
+	}
+	return skipped
+}
+
+// singleMessageHandler hands every message to the batch handler on its own
+func singleMessageHandler[T any](
+	handle func(context.Context, []T, []kafka.Message) error,
+) func(context.Context, T, kafka.Message) error {
+	return func(ctx context.Context, model T, message kafka.Message) error {
+		return handle(ctx, []T{model}, []kafka.Message{message})
+	}
+}
+
+// Registry holds topic handlers by topic
+type Registry struct {
+	handlers map[string]TopicHandler
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		handlers: make(map[string]TopicHandler),
+	}
+}
+
+// Register adds the handler of a topic, it panics when the topic already has a handler
+func (registry *Registry) Register(handler TopicHandler) {
+	if _, exist := registry.handlers[handler.Topic()]; exist {
+		panic(fmt.Sprintf("topic %s already has a handler", handler.Topic()))
+	}
+	registry.handlers[handler.Topic()] = handler
+}
+
+// Get returns the handler of the topic, nil when the topic has no handler
+func (registry *Registry) Get(topic string) TopicHandler {
+	return registry.handlers[topic]
+}
+
+// Topics returns the topics with a handler in alphabetical order
+func (registry *Registry) Topics() []string {
+	topics := make([]string, 0, len(registry.handlers))
+	for topic := range registry.handlers {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
@@ -0,0 +1,51 @@
+package consumer_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer"
+	worker_consumer "github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer/worker"
+)
+
+var _ = Describe("Registry", func() {
+	newFakeTopicHandler := func(topic string) worker_consumer.TopicHandler {
+		return worker_consumer.NewTopicHandler(
+			topic,
+			consumer.COMMIT_POLICY_SYNC,
+			func(_ worker_consumer.TopicHandlerParams) func(context.Context, map[string]string, kafka.Message) error {
+				return func(_ context.Context, _ map[string]string, _ kafka.Message) error {
+					return nil
+				}
+			},
+		)
+	}
+
+	It("should hold the handlers by topic", func() {
+		registry := worker_consumer.NewRegistry()
+		registry.Register(newFakeTopicHandler("logs"))
+		registry.Register(newFakeTopicHandler("blocks"))
+
+		Expect(registry.Get("logs").Topic()).To(Equal("logs"))
+		Expect(registry.Get("unknown")).To(BeNil())
+		Expect(registry.Topics()).To(Equal([]string{"blocks", "logs"}))
+	})
+
+	It("should reject a second handler of a topic", func() {
+		registry := worker_consumer.NewRegistry()
+		registry.Register(newFakeTopicHandler("logs"))
+
+		Expect(func() {
+			registry.Register(newFakeTopicHandler("logs"))
+		}).To(Panic())
+	})
+
+	It("should register the handlers of all the worker topics", func() {
+		Expect(worker_consumer.TopicHandlers.Topics()).To(Equal([]string{
+			"evm-txs", "internal-txs", "token-transfers",
+		}))
+	})
+})
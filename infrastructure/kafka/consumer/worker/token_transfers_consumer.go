@@ -4,19 +4,19 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
 	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
-	"github.com/AstraProtocol/astra-indexing/bootstrap/config"
-	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
 	"github.com/AstraProtocol/astra-indexing/external/tmcosmosutils"
 	"github.com/AstraProtocol/astra-indexing/external/utctime"
 	utils "github.com/AstraProtocol/astra-indexing/infrastructure"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/jsonrpc"
 	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer"
 	"github.com/AstraProtocol/astra-indexing/projection/account_transaction"
 	accountTransactionView "github.com/AstraProtocol/astra-indexing/projection/account_transaction/view"
+	"github.com/AstraProtocol/astra-indexing/projection/search"
+	searchView "github.com/AstraProtocol/astra-indexing/projection/search/view"
 	transactionView "github.com/AstraProtocol/astra-indexing/projection/transaction/view"
 	"github.com/AstraProtocol/astra-indexing/usecase/coin"
 	"github.com/AstraProtocol/astra-indexing/usecase/event"
@@ -29,166 +29,212 @@ var transferCouponType = map[string]bool{
 	"mintCoupons":      true,
 }
 
-func RunTokenTransfersConsumer(rdbHandle *rdb.Handle, config *config.Config, logger applogger.Logger, sigchan chan os.Signal) error {
-	tokenTransfersConsumer := consumer.Consumer[consumer.CollectedTokenTransfer]{
-		TimeOut:            utils.KAFKA_TIME_OUT,
-		Brokers:            config.KafkaService.Brokers,
-		Topic:              utils.TOKEN_TRANSFERS_TOPIC,
-		GroupId:            config.KafkaService.GroupID,
-		User:               config.KafkaService.User,
-		Password:           config.KafkaService.Password,
-		AuthenticationType: config.KafkaService.AuthenticationType,
-		Sigchan:            sigchan,
-		CaCertPath:         config.KafkaService.CaCertPath,
-		TlsCertPath:        config.KafkaService.TlsCertPath,
-		TlsKeyPath:         config.KafkaService.TlsKeyPath,
-	}
-	errConn := tokenTransfersConsumer.CreateConnection()
-	if errConn != nil {
-		return errConn
-	}
-
-	logger = logger.WithFields(applogger.LogFields{
-		"module": "TokenTransfersConsumer",
-	})
+func init() {
+	TopicHandlers.Register(NewTopicHandler(
+		utils.TOKEN_TRANSFERS_TOPIC,
+		consumer.COMMIT_POLICY_OFFSET_STORE,
+		func(params TopicHandlerParams) func(context.Context, consumer.CollectedTokenTransfer, kafka.Message) error {
+			return NewTokenTransfersHandler(params.RdbHandle, params.JsonrpcClient)
+		},
+	))
+}
 
+// NewTokenTransfersHandler returns the handler of the messages of the token transfers topic, which
+// indexes the tokens for the search and the coupon transfers as account transactions. The
+// transaction of the transfer must have been indexed already, the message is retried until then.
+// Without JSON-RPC client, the tokens are not indexed.
+func NewTokenTransfersHandler(
+	rdbHandle *rdb.Handle,
+	jsonrpcClient *jsonrpc.HTTPClient,
+) func(context.Context, consumer.CollectedTokenTransfer, kafka.Message) error {
 	rdbTransactionView := transactionView.NewTransactionsView(rdbHandle)
 	rdbAccountTransactionsView := accountTransactionView.NewAccountTransactions(rdbHandle)
 	rdbAccountTransactionDataView := accountTransactionView.NewAccountTransactionData(rdbHandle)
+	rdbSearchEntriesView := searchView.NewSearchEntriesView(rdbHandle)
 
-	tokenTransfersConsumer.Fetch(
-		consumer.CollectedTokenTransfer{},
-		func(collectedTokenTransfer consumer.CollectedTokenTransfer, message kafka.Message, ctx context.Context, err error) {
-			if err != nil {
-				logger.Infof("Kafka Token Transfer Consumer error: %v", err)
-			} else {
-				// get evm types by tx hashes
-				tokenTransfer := collectedTokenTransfer.TokenTransfers[0]
-				txHashes := []string{tokenTransfer.TransactionHash}
-				evmTxTypes, err := rdbTransactionView.GetTxsTypeByEvmHashes(txHashes)
-				if err != nil {
-					logger.Infof("get txs type query error: %v", err)
-				}
-
-				// handle when tx was indexed to chainindexing db
-				if len(evmTxTypes) > 0 {
-					// index token transfer when tx type are valid
-					if transferCouponType[evmTxTypes[0].TxType] {
-						accountTransactionRows := make([]accountTransactionView.AccountTransactionBaseRow, 0)
-						txs := make([]accountTransactionView.TransactionRow, 0)
-						fee := coin.MustNewCoins(coin.MustNewCoinFromString("aastra", "0"))
-
-						transactionInfo := account_transaction.NewTransactionInfo(
-							accountTransactionView.AccountTransactionBaseRow{
-								Account:      "",
-								BlockHeight:  tokenTransfer.BlockNumber,
-								BlockHash:    "",
-								BlockTime:    utctime.UTCTime{},
-								Hash:         tokenTransfer.TransactionHash,
-								MessageTypes: []string{},
-								Success:      true,
-							},
-						)
-
-						converted, _ := hex.DecodeString(tokenTransfer.ToAddressHash[2:])
-						toAstraAddr, _ := tmcosmosutils.EncodeHexToAddress("astra", converted)
-
-						transactionInfo.AddAccount(toAstraAddr)
-
-						transactionInfo.Row.FromAddress = strings.ToLower(tokenTransfer.FromAddressHash)
-						transactionInfo.Row.ToAddress = strings.ToLower(tokenTransfer.ToAddressHash)
-
-						transactionInfo.AddMessageTypes(event.MSG_ETHEREUM_TX)
-
-						blockHash := ""
-						blockTime := utctime.Now()
-						transactionInfo.FillBlockInfo(blockHash, blockTime)
-
-						// parse token transfer to message content
-						legacyTx := model.LegacyTx{
-							Type:  tokenTransfer.TokenType,
-							Gas:   "0",
-							To:    tokenTransfer.ToAddressHash,
-							Value: "0",
-							Data:  strconv.FormatInt(tokenTransfer.TokenId, 10),
-						}
-						rawMsgEthereumTx := model.RawMsgEthereumTx{
-							Type: event.MSG_ETHEREUM_TOKEN_TRANSFER,
-							Size: 0,
-							From: tokenTransfer.FromAddressHash,
-							Hash: tokenTransfer.TransactionHash,
-							Data: legacyTx,
-						}
-						params := model.MsgEthereumTxParams{
-							RawMsgEthereumTx: rawMsgEthereumTx,
-						}
-						evmEvent := event.NewMsgEthereumTx(event.MsgCommonParams{
-							BlockHeight: tokenTransfer.BlockNumber,
-							TxHash:      tokenTransfer.TransactionHash,
-							TxSuccess:   true,
-							MsgIndex:    int(tokenTransfer.LogIndex),
-						}, params)
-						tmpMessage := accountTransactionView.TransactionRowMessage{
-							Type:    event.MSG_ETHEREUM_TX,
-							EvmType: evmTxTypes[0].TxType,
-							Content: evmEvent,
-						}
-
-						tx := accountTransactionView.TransactionRow{
-							BlockHeight:   tokenTransfer.BlockNumber,
-							BlockTime:     blockTime,
-							BlockHash:     blockHash,
-							Hash:          tokenTransfer.TransactionHash,
-							Index:         int(tokenTransfer.LogIndex),
-							Success:       true,
-							Code:          0,
-							Log:           "",
-							Fee:           fee,
-							FeePayer:      "",
-							FeeGranter:    "",
-							GasWanted:     0,
-							GasUsed:       0,
-							Memo:          "",
-							TimeoutHeight: 0,
-							Messages:      make([]accountTransactionView.TransactionRowMessage, 0),
-							EvmHash:       tokenTransfer.TransactionHash,
-							RewardTxType:  evmTxTypes[0].TxType,
-							FromAddress:   strings.ToLower(tokenTransfer.FromAddressHash),
-							ToAddress:     strings.ToLower(tokenTransfer.ToAddressHash),
-						}
-						tx.Messages = append(tx.Messages, tmpMessage)
-						txs = append(txs, tx)
-						accountTransactionRows = append(accountTransactionRows, transactionInfo.ToRowsIncludingInternalTxOrTokenTransfer(int(tokenTransfer.LogIndex))...)
-
-						err = rdbAccountTransactionsView.InsertAll(accountTransactionRows)
-						if err == nil {
-							err = rdbAccountTransactionDataView.InsertAll(txs)
-							// commit offset
-							if err == nil {
-								if errCommit := tokenTransfersConsumer.Commit(ctx, message); errCommit != nil {
-									logger.Infof("Topic: %s. Consumer partition %d failed to commit messages: %v", utils.TOKEN_TRANSFERS_TOPIC, message.Partition, errCommit)
-								}
-							} else {
-								logger.Infof("Failed to insert account txs data from Consumer partition %d: %v", message.Partition, err)
-							}
-						} else {
-							logger.Infof("Failed to insert account txs from Consumer partition %d: %v", message.Partition, err)
-							// commit offset when duplicated message
-							if strings.Contains(fmt.Sprint(err), "duplicate key value violates unique constraint") {
-								if errCommit := tokenTransfersConsumer.Commit(ctx, message); errCommit != nil {
-									logger.Infof("Topic: %s. Consumer partition %d failed to commit messages: %v", utils.TOKEN_TRANSFERS_TOPIC, message.Partition, errCommit)
-								}
-							}
-						}
-					} else {
-						// commit offset when tx type are not valid
-						if errCommit := tokenTransfersConsumer.Commit(ctx, message); errCommit != nil {
-							logger.Infof("Topic: %s. Consumer partition %d failed to commit messages: %v", utils.TOKEN_TRANSFERS_TOPIC, message.Partition, errCommit)
-						}
-					}
-				}
+	return func(_ context.Context, collectedTokenTransfer consumer.CollectedTokenTransfer, message kafka.Message) error {
+		if jsonrpcClient != nil {
+			if err := indexTokens(rdbSearchEntriesView, jsonrpcClient, collectedTokenTransfer); err != nil {
+				return err
 			}
-		},
-	)
+		}
+
+		if len(collectedTokenTransfer.TokenTransfers) == 0 {
+			return nil
+		}
+
+		// get evm types by tx hashes
+		tokenTransfer := collectedTokenTransfer.TokenTransfers[0]
+		txHashes := []string{tokenTransfer.TransactionHash}
+		evmTxTypes, err := rdbTransactionView.GetTxsTypeByEvmHashes(txHashes)
+		if err != nil {
+			return fmt.Errorf("get txs type query error: %v", err)
+		}
+		// handle when tx was indexed to chainindexing db, waiting for it otherwise
+		if len(evmTxTypes) == 0 {
+			return fmt.Errorf(
+				"transaction %s of token transfer is not indexed yet: %w", tokenTransfer.TransactionHash, consumer.ErrNotReady,
+			)
+		}
+		// index token transfer when tx type are valid
+		if !transferCouponType[evmTxTypes[0].TxType] {
+			return nil
+		}
+
+		accountTransactionRows := make([]accountTransactionView.AccountTransactionBaseRow, 0)
+		txs := make([]accountTransactionView.TransactionRow, 0)
+		fee := coin.MustNewCoins(coin.MustNewCoinFromString("aastra", "0"))
+
+		transactionInfo := account_transaction.NewTransactionInfo(
+			accountTransactionView.AccountTransactionBaseRow{
+				Account:      "",
+				BlockHeight:  tokenTransfer.BlockNumber,
+				BlockHash:    "",
+				BlockTime:    utctime.UTCTime{},
+				Hash:         tokenTransfer.TransactionHash,
+				MessageTypes: []string{},
+				Success:      true,
+			},
+		)
+
+		converted, _ := hex.DecodeString(tokenTransfer.ToAddressHash[2:])
+		toAstraAddr, _ := tmcosmosutils.EncodeHexToAddress("astra", converted)
+
+		transactionInfo.AddAccount(toAstraAddr)
+
+		transactionInfo.Row.FromAddress = strings.ToLower(tokenTransfer.FromAddressHash)
+		transactionInfo.Row.ToAddress = strings.ToLower(tokenTransfer.ToAddressHash)
+
+		transactionInfo.AddMessageTypes(event.MSG_ETHEREUM_TX)
+
+		blockHash := ""
+		blockTime := utctime.Now()
+		transactionInfo.FillBlockInfo(blockHash, blockTime)
+
+		// parse token transfer to message content
+		legacyTx := model.LegacyTx{
+			Type:  tokenTransfer.TokenType,
+			Gas:   "0",
+			To:    tokenTransfer.ToAddressHash,
+			Value: "0",
+			Data:  strconv.FormatInt(tokenTransfer.TokenId, 10),
+		}
+		rawMsgEthereumTx := model.RawMsgEthereumTx{
+			Type: event.MSG_ETHEREUM_TOKEN_TRANSFER,
+			Size: 0,
+			From: tokenTransfer.FromAddressHash,
+			Hash: tokenTransfer.TransactionHash,
+			Data: legacyTx,
+		}
+		params := model.MsgEthereumTxParams{
+			RawMsgEthereumTx: rawMsgEthereumTx,
+		}
+		evmEvent := event.NewMsgEthereumTx(event.MsgCommonParams{
+			BlockHeight: tokenTransfer.BlockNumber,
+			TxHash:      tokenTransfer.TransactionHash,
+			TxSuccess:   true,
+			MsgIndex:    int(tokenTransfer.LogIndex),
+		}, params)
+		tmpMessage := accountTransactionView.TransactionRowMessage{
+			Type:    event.MSG_ETHEREUM_TX,
+			EvmType: evmTxTypes[0].TxType,
+			Content: evmEvent,
+		}
+
+		tx := accountTransactionView.TransactionRow{
+			BlockHeight:   tokenTransfer.BlockNumber,
+			BlockTime:     blockTime,
+			BlockHash:     blockHash,
+			Hash:          tokenTransfer.TransactionHash,
+			Index:         int(tokenTransfer.LogIndex),
+			Success:       true,
+			Code:          0,
+			Log:           "",
+			Fee:           fee,
+			FeePayer:      "",
+			FeeGranter:    "",
+			GasWanted:     0,
+			GasUsed:       0,
+			Memo:          "",
+			TimeoutHeight: 0,
+			Messages:      make([]accountTransactionView.TransactionRowMessage, 0),
+			EvmHash:       tokenTransfer.TransactionHash,
+			RewardTxType:  evmTxTypes[0].TxType,
+			FromAddress:   strings.ToLower(tokenTransfer.FromAddressHash),
+			ToAddress:     strings.ToLower(tokenTransfer.ToAddressHash),
+		}
+		tx.Messages = append(tx.Messages, tmpMessage)
+		txs = append(txs, tx)
+		accountTransactionRows = append(accountTransactionRows, transactionInfo.ToRowsIncludingInternalTxOrTokenTransfer(int(tokenTransfer.LogIndex))...)
+
+		if err = rdbAccountTransactionsView.InsertAll(accountTransactionRows); err != nil {
+			return fmt.Errorf("failed to insert account txs from consumer partition %d: %v", message.Partition, err)
+		}
+		if err = rdbAccountTransactionDataView.InsertAll(txs); err != nil {
+			return fmt.Errorf("failed to insert account txs data from consumer partition %d: %v", message.Partition, err)
+		}
+		return nil
+	}
+}
+
+// indexTokens records the tokens of the message not indexed yet in the search entries, with the name and
+// symbol read from their contract
+func indexTokens(
+	searchEntriesView searchView.SearchEntries,
+	jsonrpcClient *jsonrpc.HTTPClient,
+	collectedTokenTransfer consumer.CollectedTokenTransfer,
+) error {
+	addressHashes := make([]string, 0)
+	addAddressHash := func(addressHash string) {
+		addressHash = strings.ToLower(addressHash)
+		if addressHash == "" {
+			return
+		}
+		for _, existing := range addressHashes {
+			if existing == addressHash {
+				return
+			}
+		}
+		addressHashes = append(addressHashes, addressHash)
+	}
+	for _, token := range collectedTokenTransfer.Tokens {
+		addAddressHash(token.ContractAddressHash)
+	}
+	blockHeight := int64(0)
+	for _, tokenTransfer := range collectedTokenTransfer.TokenTransfers {
+		addAddressHash(tokenTransfer.TokenContractAddressHash)
+		if blockHeight == 0 {
+			blockHeight = tokenTransfer.BlockNumber
+		}
+	}
+	if len(addressHashes) == 0 {
+		return nil
+	}
+
+	indexedAddressHashes, err := searchEntriesView.FindKeys(searchView.SEARCH_ENTRY_TOKEN, addressHashes)
+	if err != nil {
+		return fmt.Errorf("error finding indexed tokens: %v", err)
+	}
+	indexed := make(map[string]bool, len(indexedAddressHashes))
+	for _, addressHash := range indexedAddressHashes {
+		indexed[addressHash] = true
+	}
+
+	entries := make([]searchView.SearchEntryRow, 0)
+	for _, addressHash := range addressHashes {
+		if indexed[addressHash] {
+			continue
+		}
+		metadata, err := jsonrpcClient.GetTokenMetadata(addressHash)
+		if err != nil {
+			return fmt.Errorf("error getting token metadata: %v", err)
+		}
+		entries = append(entries, search.NewTokenSearchEntries(
+			addressHash, metadata.Name, metadata.Symbol, blockHeight,
+		)...)
+	}
+	if err := searchEntriesView.Upsert(entries); err != nil {
+		return fmt.Errorf("error upserting token search entries: %v", err)
+	}
 	return nil
 }
@@ -0,0 +1,159 @@
+package producer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/AstraProtocol/astra-indexing/entity/event"
+)
+
+// Encoding is how the envelope of a published event is encoded
+type Encoding string
+
+const (
+	ENCODING_JSON     Encoding = "json"
+	ENCODING_PROTOBUF Encoding = "protobuf"
+)
+
+// ParseEncoding returns the encoding of the name, empty defaults to JSON
+func ParseEncoding(name string) (Encoding, error) {
+	switch Encoding(name) {
+	case "", ENCODING_JSON:
+		return ENCODING_JSON, nil
+	case ENCODING_PROTOBUF:
+		return ENCODING_PROTOBUF, nil
+	default:
+		return "", fmt.Errorf("unsupported event envelope encoding: %s", name)
+	}
+}
+
+// Envelope wraps the JSON payload of a published event. Downstream services deduplicate the
+// events delivered more than once by UUID, which is the same whenever a height is published again.
+// Its protobuf schema is in envelope.proto.
+type Envelope struct {
+	UUID    string          `json:"uuid"`
+	Name    string          `json:"name"`
+	Version int             `json:"version"`
+	Height  int64           `json:"height"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// envelopeUUIDNamespace is the namespace of the name-based UUIDs of the envelopes
+var envelopeUUIDNamespace = uuid.MustParse("5f0c3a52-7a51-4b8e-9a8e-2f0e6c1d4b7a")
+
+// NewEnvelope wraps the event at `index` among the events of its height
+func NewEnvelope(evt event.Event, index int, payload string) Envelope {
+	return Envelope{
+		UUID:    EnvelopeUUIDOf(evt.Height(), index, evt.Name(), evt.Version()),
+		Name:    evt.Name(),
+		Version: evt.Version(),
+		Height:  evt.Height(),
+		Payload: json.RawMessage(payload),
+	}
+}
+
+// EnvelopeUUIDOf returns the deterministic UUID of the envelope of the event at `index` among the
+// events of the height, unlike the event UUID which is random on every parsing of the block
+func EnvelopeUUIDOf(height int64, index int, name string, version int) string {
+	return uuid.NewSHA1(
+		envelopeUUIDNamespace, []byte(fmt.Sprintf("%d/%d/%s/%d", height, index, name, version)),
+	).String()
+}
+
+// Encode returns the envelope encoded with the encoding
+func (envelope Envelope) Encode(encoding Encoding) ([]byte, error) {
+	switch encoding {
+	case ENCODING_JSON:
+		return json.Marshal(envelope)
+	case ENCODING_PROTOBUF:
+		return envelope.marshalProtobuf(), nil
+	default:
+		return nil, fmt.Errorf("unsupported event envelope encoding: %s", encoding)
+	}
+}
+
+// DecodeEnvelope decodes the envelope encoded with the encoding
+func DecodeEnvelope(encoding Encoding, value []byte) (*Envelope, error) {
+	var envelope Envelope
+	switch encoding {
+	case ENCODING_JSON:
+		if err := json.Unmarshal(value, &envelope); err != nil {
+			return nil, fmt.Errorf("error decoding event envelope: %v", err)
+		}
+	case ENCODING_PROTOBUF:
+		if err := envelope.unmarshalProtobuf(value); err != nil {
+			return nil, fmt.Errorf("error decoding event envelope: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported event envelope encoding: %s", encoding)
+	}
+	return &envelope, nil
+}
+
+// Field numbers of the protobuf envelope, they must match envelope.proto
+const (
+	envelopeUUIDField    protowire.Number = 1
+	envelopeNameField    protowire.Number = 2
+	envelopeVersionField protowire.Number = 3
+	envelopeHeightField  protowire.Number = 4
+	envelopePayloadField protowire.Number = 5
+)
+
+func (envelope Envelope) marshalProtobuf() []byte {
+	var b []byte
+	b = protowire.AppendTag(b, envelopeUUIDField, protowire.BytesType)
+	b = protowire.AppendString(b, envelope.UUID)
+	b = protowire.AppendTag(b, envelopeNameField, protowire.BytesType)
+	b = protowire.AppendString(b, envelope.Name)
+	b = protowire.AppendTag(b, envelopeVersionField, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(envelope.Version))
+	b = protowire.AppendTag(b, envelopeHeightField, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(envelope.Height))
+	b = protowire.AppendTag(b, envelopePayloadField, protowire.BytesType)
+	b = protowire.AppendBytes(b, envelope.Payload)
+	return b
+}
+
+func (envelope *Envelope) unmarshalProtobuf(b []byte) error {
+	for len(b) > 0 {
+		number, wireType, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		switch {
+		case number == envelopeUUIDField && wireType == protowire.BytesType:
+			var value string
+			value, n = protowire.ConsumeString(b)
+			envelope.UUID = value
+		case number == envelopeNameField && wireType == protowire.BytesType:
+			var value string
+			value, n = protowire.ConsumeString(b)
+			envelope.Name = value
+		case number == envelopeVersionField && wireType == protowire.VarintType:
+			var value uint64
+			value, n = protowire.ConsumeVarint(b)
+			envelope.Version = int(value)
+		case number == envelopeHeightField && wireType == protowire.VarintType:
+			var value uint64
+			value, n = protowire.ConsumeVarint(b)
+			envelope.Height = int64(value)
+		case number == envelopePayloadField && wireType == protowire.BytesType:
+			var value []byte
+			value, n = protowire.ConsumeBytes(b)
+			envelope.Payload = append(json.RawMessage{}, value...)
+		default:
+			// Unknown fields are skipped for forward compatibility
+			n = protowire.ConsumeFieldValue(number, wireType, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package producer_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestProducer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Kafka Producer Suite")
+}
@@ -0,0 +1,70 @@
+package producer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/AstraProtocol/astra-indexing/entity/event"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer"
+)
+
+const EVENT_HEADER_NAME = "x-event-name"
+
+// EventPublisher publishes the events of every height to the topics of their routes
+type EventPublisher struct {
+	writer   consumer.MessageWriter
+	router   *Router
+	encoding Encoding
+}
+
+func NewEventPublisher(writer consumer.MessageWriter, router *Router, encoding Encoding) *EventPublisher {
+	return &EventPublisher{
+		writer:   writer,
+		router:   router,
+		encoding: encoding,
+	}
+}
+
+// Publish writes all the routed events of the height and returns once the brokers have
+// acknowledged all of them. The events are written in order, a failure may leave some of them
+// written, such that publishing the height again delivers them more than once.
+func (publisher *EventPublisher) Publish(blockHeight int64, events []event.Event) error {
+	messages := make([]kafka.Message, 0, len(events))
+	for i, evt := range events {
+		route, ok := publisher.router.RouteOf(evt.Name())
+		if !ok {
+			continue
+		}
+
+		payload, err := evt.ToJSON()
+		if err != nil {
+			return fmt.Errorf("error encoding event %s to JSON: %v", evt.UUID(), err)
+		}
+		value, err := NewEnvelope(evt, i, payload).Encode(publisher.encoding)
+		if err != nil {
+			return fmt.Errorf("error encoding envelope of event %s: %v", evt.UUID(), err)
+		}
+		messages = append(messages, kafka.Message{
+			Topic: route.Topic,
+			Key:   route.KeyOf(blockHeight, payload),
+			Value: value,
+			Headers: []kafka.Header{
+				{Key: EVENT_HEADER_NAME, Value: []byte(evt.Name())},
+			},
+		})
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	if err := publisher.writer.WriteMessages(context.Background(), messages...); err != nil {
+		return fmt.Errorf("error publishing %d events of height %d: %v", len(messages), blockHeight, err)
+	}
+	return nil
+}
+
+func (publisher *EventPublisher) Close() error {
+	return publisher.writer.Close()
+}
@@ -0,0 +1,154 @@
+package producer_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	entity_event "github.com/AstraProtocol/astra-indexing/entity/event"
+	kafka_test "github.com/AstraProtocol/astra-indexing/infrastructure/kafka/consumer/test"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/kafka/producer"
+	"github.com/AstraProtocol/astra-indexing/usecase/coin"
+	usecase_event "github.com/AstraProtocol/astra-indexing/usecase/event"
+	usecase_model "github.com/AstraProtocol/astra-indexing/usecase/model"
+)
+
+var _ = Describe("EventPublisher", func() {
+	newMsgSend := func(fromAddress string) entity_event.Event {
+		return usecase_event.NewMsgSend(usecase_event.MsgCommonParams{
+			BlockHeight: 10,
+			TxHash:      "TxHash",
+			TxSuccess:   true,
+			MsgIndex:    0,
+		}, usecase_event.MsgSendCreatedParams{
+			FromAddress: fromAddress,
+			ToAddress:   "astra1to",
+			Amount:      coin.NewEmptyCoins(),
+		})
+	}
+	newBlockCreated := func() entity_event.Event {
+		return usecase_event.NewBlockCreated(&usecase_model.Block{
+			Height: 10,
+			Hash:   "BlockHash",
+		})
+	}
+	newRouter := func(defaultTopic string) *producer.Router {
+		router := producer.NewRouter(defaultTopic)
+		Expect(router.AddRoute("/cosmos.bank.v1beta1.MsgSend.Created", producer.Route{
+			Topic:        "transfers",
+			Key:          producer.KEY_ADDRESS,
+			AddressField: "fromAddress",
+		})).To(Succeed())
+		return router
+	}
+
+	It("should publish the events to the topics of their routes", func() {
+		broker := kafka_test.NewMemoryBroker()
+		publisher := producer.NewEventPublisher(broker.Writer(), newRouter("events"), producer.ENCODING_JSON)
+
+		msgSend := newMsgSend("astra1from")
+		blockCreated := newBlockCreated()
+		Expect(publisher.Publish(10, []entity_event.Event{blockCreated, msgSend})).To(Succeed())
+
+		transfers := broker.Messages("transfers")
+		Expect(transfers).To(HaveLen(1))
+		Expect(string(transfers[0].Key)).To(Equal("astra1from"))
+		envelope, err := producer.DecodeEnvelope(producer.ENCODING_JSON, transfers[0].Value)
+		Expect(err).To(BeNil())
+		Expect(envelope.UUID).To(Equal(producer.EnvelopeUUIDOf(10, 1, msgSend.Name(), msgSend.Version())))
+		Expect(envelope.Name).To(Equal("/cosmos.bank.v1beta1.MsgSend.Created"))
+		Expect(envelope.Height).To(Equal(int64(10)))
+		payload, _ := msgSend.ToJSON()
+		Expect(envelope.Payload).To(MatchJSON(payload))
+
+		events := broker.Messages("events")
+		Expect(events).To(HaveLen(1))
+		Expect(string(events[0].Key)).To(Equal("10"))
+		Expect(events[0].Headers[0].Key).To(Equal(producer.EVENT_HEADER_NAME))
+		Expect(string(events[0].Headers[0].Value)).To(Equal(usecase_event.BLOCK_CREATED))
+	})
+
+	It("should publish the events of a height again with the same UUIDs", func() {
+		broker := kafka_test.NewMemoryBroker()
+		publisher := producer.NewEventPublisher(broker.Writer(), newRouter("events"), producer.ENCODING_JSON)
+
+		// Parsing the block again creates new events with new random UUIDs
+		Expect(publisher.Publish(10, []entity_event.Event{newBlockCreated(), newMsgSend("astra1from")})).To(Succeed())
+		Expect(publisher.Publish(10, []entity_event.Event{newBlockCreated(), newMsgSend("astra1from")})).To(Succeed())
+
+		for _, topic := range []string{"events", "transfers"} {
+			messages := broker.Messages(topic)
+			Expect(messages).To(HaveLen(2))
+			first, err := producer.DecodeEnvelope(producer.ENCODING_JSON, messages[0].Value)
+			Expect(err).To(BeNil())
+			second, err := producer.DecodeEnvelope(producer.ENCODING_JSON, messages[1].Value)
+			Expect(err).To(BeNil())
+			Expect(second.UUID).To(Equal(first.UUID))
+		}
+		Expect(producer.EnvelopeUUIDOf(10, 0, usecase_event.BLOCK_CREATED, 1)).NotTo(
+			Equal(producer.EnvelopeUUIDOf(11, 0, usecase_event.BLOCK_CREATED, 1)),
+		)
+	})
+
+	It("should skip the events without a route when there is no default topic", func() {
+		broker := kafka_test.NewMemoryBroker()
+		publisher := producer.NewEventPublisher(broker.Writer(), newRouter(""), producer.ENCODING_JSON)
+
+		Expect(publisher.Publish(10, []entity_event.Event{newBlockCreated()})).To(Succeed())
+
+		Expect(broker.Messages("transfers")).To(BeEmpty())
+	})
+
+	It("should key the events without the address by height", func() {
+		broker := kafka_test.NewMemoryBroker()
+		publisher := producer.NewEventPublisher(broker.Writer(), newRouter(""), producer.ENCODING_JSON)
+
+		Expect(publisher.Publish(10, []entity_event.Event{newMsgSend("")})).To(Succeed())
+
+		transfers := broker.Messages("transfers")
+		Expect(transfers).To(HaveLen(1))
+		Expect(string(transfers[0].Key)).To(Equal("10"))
+	})
+
+	It("should encode the envelope with protobuf", func() {
+		broker := kafka_test.NewMemoryBroker()
+		publisher := producer.NewEventPublisher(broker.Writer(), newRouter(""), producer.ENCODING_PROTOBUF)
+
+		msgSend := newMsgSend("astra1from")
+		Expect(publisher.Publish(10, []entity_event.Event{msgSend})).To(Succeed())
+
+		transfers := broker.Messages("transfers")
+		Expect(transfers).To(HaveLen(1))
+		Expect(json.Valid(transfers[0].Value)).To(BeFalse())
+		envelope, err := producer.DecodeEnvelope(producer.ENCODING_PROTOBUF, transfers[0].Value)
+		Expect(err).To(BeNil())
+		payload, _ := msgSend.ToJSON()
+		Expect(*envelope).To(Equal(producer.Envelope{
+			UUID:    producer.EnvelopeUUIDOf(10, 0, msgSend.Name(), msgSend.Version()),
+			Name:    msgSend.Name(),
+			Version: msgSend.Version(),
+			Height:  10,
+			Payload: json.RawMessage(payload),
+		}))
+	})
+})
+
+var _ = Describe("Router", func() {
+	It("should reject invalid routes", func() {
+		router := producer.NewRouter("")
+
+		Expect(router.AddRoute("BlockCreated", producer.Route{})).NotTo(Succeed())
+		Expect(router.AddRoute("BlockCreated", producer.Route{Topic: "blocks", Key: "hash"})).NotTo(Succeed())
+		Expect(router.AddRoute("BlockCreated", producer.Route{Topic: "blocks", Key: producer.KEY_ADDRESS})).NotTo(Succeed())
+		Expect(router.AddRoute("BlockCreated", producer.Route{Topic: "blocks"})).To(Succeed())
+		Expect(router.AddRoute("BlockCreated", producer.Route{Topic: "blocks"})).NotTo(Succeed())
+	})
+
+	It("should key by the address at the nested path", func() {
+		route := producer.Route{Topic: "votes", Key: producer.KEY_ADDRESS, AddressField: "params.voter"}
+
+		Expect(string(route.KeyOf(3, `{"params":{"voter":"astra1voter"}}`))).To(Equal("astra1voter"))
+		Expect(string(route.KeyOf(3, `{"params":{"voter":1}}`))).To(Equal("3"))
+	})
+})
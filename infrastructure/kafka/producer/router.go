@@ -0,0 +1,117 @@
+package producer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Events are keyed by their height, such that the events of a height stay in order
+	KEY_HEIGHT = "height"
+	// Events are keyed by an address in their payload, such that the events of an address stay in
+	// order. Events without the address are keyed by height.
+	KEY_ADDRESS = "address"
+)
+
+// Route is where the events of a name are published
+type Route struct {
+	Topic string
+	// KEY_HEIGHT or KEY_ADDRESS, empty defaults to KEY_HEIGHT
+	Key string
+	// Dot separated path of the address in the JSON payload of the event with KEY_ADDRESS, e.g.
+	// `params.fromAddress`
+	AddressField string
+}
+
+func (route Route) validate() error {
+	if route.Topic == "" {
+		return fmt.Errorf("missing topic")
+	}
+	switch route.Key {
+	case "", KEY_HEIGHT:
+		return nil
+	case KEY_ADDRESS:
+		if route.AddressField == "" {
+			return fmt.Errorf("missing address field of key %s", KEY_ADDRESS)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported key: %s", route.Key)
+	}
+}
+
+// KeyOf returns the message key of an event of the height with the JSON payload
+func (route Route) KeyOf(height int64, payload string) []byte {
+	if route.Key == KEY_ADDRESS {
+		if address := lookupString(payload, route.AddressField); address != "" {
+			return []byte(address)
+		}
+	}
+	return []byte(strconv.FormatInt(height, 10))
+}
+
+// Router routes the events to topics by event name
+type Router struct {
+	routes       map[string]Route
+	defaultRoute *Route
+}
+
+// NewRouter creates a router publishing the events without a route to the default topic keyed by
+// height. Empty default topic skips them instead.
+func NewRouter(defaultTopic string) *Router {
+	var defaultRoute *Route
+	if defaultTopic != "" {
+		defaultRoute = &Route{
+			Topic: defaultTopic,
+			Key:   KEY_HEIGHT,
+		}
+	}
+	return &Router{
+		routes:       make(map[string]Route),
+		defaultRoute: defaultRoute,
+	}
+}
+
+// AddRoute routes the events of the name, it fails on an invalid route or an event name routed
+// already
+func (router *Router) AddRoute(eventName string, route Route) error {
+	if _, exist := router.routes[eventName]; exist {
+		return fmt.Errorf("event %s already has a route", eventName)
+	}
+	if err := route.validate(); err != nil {
+		return fmt.Errorf("invalid route of event %s: %v", eventName, err)
+	}
+	router.routes[eventName] = route
+	return nil
+}
+
+// RouteOf returns the route of the events of the name, false when they are not published
+func (router *Router) RouteOf(eventName string) (Route, bool) {
+	if route, exist := router.routes[eventName]; exist {
+		return route, true
+	}
+	if router.defaultRoute != nil {
+		return *router.defaultRoute, true
+	}
+	return Route{}, false
+}
+
+// lookupString returns the string at the dot separated path of the JSON document, empty when it
+// is missing or not a string
+func lookupString(document string, path string) string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(document), &value); err != nil {
+		return ""
+	}
+	for _, field := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = object[field]
+	}
+	str, _ := value.(string)
+	return str
+}
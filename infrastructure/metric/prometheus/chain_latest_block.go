@@ -0,0 +1,23 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	chainLatestHeightName = "chain_latest_block_height"
+)
+
+var (
+	chainLatestHeight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: chainLatestHeightName,
+		},
+	)
+)
+
+// RecordChainLatestHeight records the latest block height of the chain, the lag of a projection is
+// the difference with its `projection_latest_block_height`
+func RecordChainLatestHeight(height int64) {
+	chainLatestHeight.Set(float64(height))
+}
@@ -0,0 +1,195 @@
+package prometheus
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	kafkaConsumerLagName                   = "kafka_consumer_lag"
+	kafkaConsumerMessagesProcessedName     = "kafka_consumer_messages_processed_total"
+	kafkaConsumerMessagesFailedName        = "kafka_consumer_messages_failed_total"
+	kafkaConsumerMessagesSkippedName       = "kafka_consumer_messages_skipped_total"
+	kafkaConsumerCommitLatencyName         = "kafka_consumer_commit_latency"
+	kafkaConsumerSecondsSinceLastWriteName = "kafka_consumer_seconds_since_last_write"
+	kafkaTopicLabel                        = "topic"
+	kafkaPartitionLabel                    = "partition"
+)
+
+var (
+	// Messages of the partition not consumed yet, as of the last fetched message or reader stats
+	kafkaConsumerLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: kafkaConsumerLagName,
+		},
+		[]string{
+			kafkaTopicLabel,
+			kafkaPartitionLabel,
+		},
+	)
+
+	kafkaConsumerMessagesProcessed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: kafkaConsumerMessagesProcessedName,
+		},
+		[]string{
+			kafkaTopicLabel,
+			kafkaPartitionLabel,
+		},
+	)
+
+	// Messages moved to the dead letter topic or stopping the consumer
+	kafkaConsumerMessagesFailed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: kafkaConsumerMessagesFailedName,
+		},
+		[]string{
+			kafkaTopicLabel,
+			kafkaPartitionLabel,
+		},
+	)
+
+	// Messages handled already according to the offset store
+	kafkaConsumerMessagesSkipped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: kafkaConsumerMessagesSkippedName,
+		},
+		[]string{
+			kafkaTopicLabel,
+			kafkaPartitionLabel,
+		},
+	)
+
+	kafkaConsumerCommitLatency = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name: kafkaConsumerCommitLatencyName,
+		},
+		[]string{
+			kafkaTopicLabel,
+		},
+	)
+
+	kafkaConsumerSinceLastWrite = &sinceLastWriteCollector{
+		desc: prometheus.NewDesc(
+			kafkaConsumerSecondsSinceLastWriteName,
+			"Seconds since the last message of the partition was handled successfully",
+			[]string{kafkaTopicLabel, kafkaPartitionLabel},
+			nil,
+		),
+		lastWrites: make(map[kafkaPartition]time.Time),
+	}
+
+	kafkaConsumerLagSummary = &lagSummary{
+		lags: make(map[kafkaPartition]int64),
+	}
+)
+
+type kafkaPartition struct {
+	topic     string
+	partition int
+}
+
+func (p kafkaPartition) labels() prometheus.Labels {
+	return prometheus.Labels{
+		kafkaTopicLabel:     p.topic,
+		kafkaPartitionLabel: strconv.Itoa(p.partition),
+	}
+}
+
+// sinceLastWriteCollector computes the time since the last write of every partition when scraped
+type sinceLastWriteCollector struct {
+	desc *prometheus.Desc
+
+	mutex      sync.Mutex
+	lastWrites map[kafkaPartition]time.Time
+}
+
+func (collector *sinceLastWriteCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.desc
+}
+
+func (collector *sinceLastWriteCollector) Collect(ch chan<- prometheus.Metric) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	for p, lastWrite := range collector.lastWrites {
+		ch <- prometheus.MustNewConstMetric(
+			collector.desc,
+			prometheus.GaugeValue,
+			time.Since(lastWrite).Seconds(),
+			p.topic,
+			strconv.Itoa(p.partition),
+		)
+	}
+}
+
+// lagSummary keeps the last lag of every partition consumed by this process
+type lagSummary struct {
+	mutex sync.Mutex
+	lags  map[kafkaPartition]int64
+}
+
+func RecordKafkaConsumerLag(topic string, partition int, lag int64) {
+	p := kafkaPartition{topic, partition}
+	kafkaConsumerLag.With(p.labels()).Set(float64(lag))
+
+	kafkaConsumerLagSummary.mutex.Lock()
+	defer kafkaConsumerLagSummary.mutex.Unlock()
+	kafkaConsumerLagSummary.lags[p] = lag
+}
+
+// ForgetKafkaConsumerPartition deletes the lag and last write of the partition, once it is revoked
+// from the consumers of this process
+func ForgetKafkaConsumerPartition(topic string, partition int) {
+	p := kafkaPartition{topic, partition}
+	kafkaConsumerLag.Delete(p.labels())
+
+	kafkaConsumerLagSummary.mutex.Lock()
+	delete(kafkaConsumerLagSummary.lags, p)
+	kafkaConsumerLagSummary.mutex.Unlock()
+
+	kafkaConsumerSinceLastWrite.mutex.Lock()
+	delete(kafkaConsumerSinceLastWrite.lastWrites, p)
+	kafkaConsumerSinceLastWrite.mutex.Unlock()
+}
+
+func RecordKafkaConsumerProcessed(topic string, partition int, count int) {
+	kafkaConsumerMessagesProcessed.With(kafkaPartition{topic, partition}.labels()).Add(float64(count))
+}
+
+func RecordKafkaConsumerFailed(topic string, partition int, count int) {
+	kafkaConsumerMessagesFailed.With(kafkaPartition{topic, partition}.labels()).Add(float64(count))
+}
+
+func RecordKafkaConsumerSkipped(topic string, partition int, count int) {
+	kafkaConsumerMessagesSkipped.With(kafkaPartition{topic, partition}.labels()).Add(float64(count))
+}
+
+func RecordKafkaConsumerCommitLatency(topic string, timeInMilliseconds int64) {
+	kafkaConsumerCommitLatency.With(
+		prometheus.Labels{
+			kafkaTopicLabel: topic,
+		},
+	).Observe(float64(timeInMilliseconds))
+}
+
+func RecordKafkaConsumerLastWrite(topic string, partition int, at time.Time) {
+	kafkaConsumerSinceLastWrite.mutex.Lock()
+	defer kafkaConsumerSinceLastWrite.mutex.Unlock()
+	kafkaConsumerSinceLastWrite.lastWrites[kafkaPartition{topic, partition}] = at
+}
+
+// KafkaConsumerLagByTopic returns the total lag of every topic consumed by this process, summed
+// over its partitions. It is empty when the process runs no consumer.
+func KafkaConsumerLagByTopic() map[string]int64 {
+	kafkaConsumerLagSummary.mutex.Lock()
+	defer kafkaConsumerLagSummary.mutex.Unlock()
+
+	lags := make(map[string]int64)
+	for p, lag := range kafkaConsumerLagSummary.lags {
+		lags[p.topic] += lag
+	}
+	return lags
+}
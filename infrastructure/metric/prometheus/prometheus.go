@@ -1,6 +1,8 @@
 package prometheus
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"net/http"
@@ -8,19 +10,35 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-func Run(path, port string) error {
+// Run serves the metrics until the context is done
+func Run(ctx context.Context, path, port string) error {
 	register := prometheus.DefaultRegisterer
 	register.MustRegister(projectionExecTime)
 	register.MustRegister(projectionLatestHeight)
+	register.MustRegister(chainLatestHeight)
 	register.MustRegister(apiExecTime)
 	register.MustRegister(paramGaugeVecHit)
 	register.MustRegister(paramGaugeVecMissed)
 	register.MustRegister(paramGaugeVecEviction)
 	register.MustRegister(paramGaugeVecInsertion)
+	register.MustRegister(kafkaConsumerLag)
+	register.MustRegister(kafkaConsumerMessagesProcessed)
+	register.MustRegister(kafkaConsumerMessagesFailed)
+	register.MustRegister(kafkaConsumerMessagesSkipped)
+	register.MustRegister(kafkaConsumerCommitLatency)
+	register.MustRegister(kafkaConsumerSinceLastWrite)
 	handler := promhttp.InstrumentMetricHandler(
 		register, promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{}),
 	)
-	if err := http.ListenAndServe(fmt.Sprintf(":%s", port), handler); err != nil {
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%s", port),
+		Handler: handler,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 
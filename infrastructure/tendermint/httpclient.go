@@ -42,7 +42,7 @@ type HTTPClient struct {
 	httpClient           *retryablehttp.Client
 	tendermintRPCUrl     string
 	strictGenesisParsing bool
-	httpCache            *cache.AstraCache
+	httpCache            cache.Cache
 }
 
 // NewHTTPClient returns a new HTTPClient for tendermint request
@@ -192,6 +192,36 @@ func (client *HTTPClient) LatestBlockHeight() (int64, error) {
 	return block.Height, nil
 }
 
+// RawGenesis gets the raw genesis response, which is parsed by ParseGenesisResp
+func (client *HTTPClient) RawGenesis() ([]byte, error) {
+	return client.readAll("genesis")
+}
+
+// RawBlock gets the raw block response with target height, which is parsed by ParseBlockResp
+func (client *HTTPClient) RawBlock(height int64) ([]byte, error) {
+	return client.readAll("block", "height="+strconv.FormatInt(height, 10))
+}
+
+// RawBlockResults gets the raw block_results response with target height, which is parsed by
+// ParseBlockResultsResp
+func (client *HTTPClient) RawBlockResults(height int64) ([]byte, error) {
+	return client.readAll("block_results", "height="+strconv.FormatInt(height, 10))
+}
+
+func (client *HTTPClient) readAll(method string, queryString ...string) ([]byte, error) {
+	rawRespBody, err := client.request(method, queryString...)
+	if err != nil {
+		return nil, err
+	}
+	defer rawRespBody.Close()
+
+	rawResp, err := io.ReadAll(rawRespBody)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Tendermint %s response: %v", method, err)
+	}
+	return rawResp, nil
+}
+
 // request construct tendermint url and issues an HTTP request
 // returns the success http Body
 func (client *HTTPClient) request(method string, queryString ...string) (io.ReadCloser, error) {
@@ -259,3 +289,22 @@ type RawBlockResultsResp struct {
 	ID      int             `json:"id"`
 	Result  RawBlockResults `json:"result"`
 }
+
+type RawNewBlockEventResp struct {
+	Jsonrpc string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Result  struct {
+		Query string `json:"query"`
+		Data  struct {
+			Type  string                 `json:"type"`
+			Value usecase_model.RawBlock `json:"value"`
+		} `json:"data"`
+	} `json:"result"`
+	Error *RawRPCError `json:"error"`
+}
+
+type RawRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data"`
+}
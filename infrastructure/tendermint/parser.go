@@ -31,30 +31,62 @@ func ParseGenesisResp(rawRespReader io.Reader, strictParsing bool) (*genesis.Gen
 }
 
 func ParseBlockResp(rawRespReader io.Reader) (*model.Block, *model.RawBlock, error) {
-	var err error
-
 	var resp RawBlockResp
 	jsonDecoder := jsoniter.NewDecoder(rawRespReader)
 	jsonDecoder.DisallowUnknownFields()
-	if err = jsonDecoder.Decode(&resp); err != nil {
+	if err := jsonDecoder.Decode(&resp); err != nil {
 		return nil, nil, fmt.Errorf("error decoding Tendermint block response: %v", err)
 	}
 
-	height, err := strconv.ParseInt(resp.Result.Block.Header.Height, 10, 64)
+	block, err := parseRawBlock(&resp.Result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return block, &resp.Result, nil
+}
+
+// ParseNewBlockEventResp parses a message of the NewBlock event subscription. It returns nil block
+// when the message does not carry any block, e.g. the subscription confirmation.
+func ParseNewBlockEventResp(rawRespReader io.Reader) (*model.Block, *model.RawBlock, error) {
+	var resp RawNewBlockEventResp
+	jsonDecoder := jsoniter.NewDecoder(rawRespReader)
+	if err := jsonDecoder.Decode(&resp); err != nil {
+		return nil, nil, fmt.Errorf("error decoding Tendermint NewBlock event response: %v", err)
+	}
+	if resp.Error != nil {
+		return nil, nil, fmt.Errorf(
+			"error subscribing to Tendermint NewBlock event: %s %s", resp.Error.Message, resp.Error.Data,
+		)
+	}
+	if resp.Result.Data.Type != NEW_BLOCK_EVENT_DATA_TYPE {
+		return nil, nil, nil
+	}
+
+	block, err := parseRawBlock(&resp.Result.Data.Value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return block, &resp.Result.Data.Value, nil
+}
+
+func parseRawBlock(rawBlock *model.RawBlock) (*model.Block, error) {
+	height, err := strconv.ParseInt(rawBlock.Block.Header.Height, 10, 64)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error converting block height to unsigned integer: %v", err)
+		return nil, fmt.Errorf("error converting block height to unsigned integer: %v", err)
 	}
 
 	return &model.Block{
 		Height:          height,
-		Hash:            resp.Result.BlockID.Hash,
-		Time:            resp.Result.Block.Header.Time,
-		AppHash:         resp.Result.Block.Header.AppHash,
-		ProposerAddress: resp.Result.Block.Header.ProposerAddress,
-		Txs:             resp.Result.Block.Data.Txs,
-		Signatures:      parseBlockSignatures(resp.Result.Block.LastCommit.Signatures),
-		Evidences:       resp.Result.Block.Evidence.Evidence,
-	}, &resp.Result, nil
+		Hash:            rawBlock.BlockID.Hash,
+		Time:            rawBlock.Block.Header.Time,
+		AppHash:         rawBlock.Block.Header.AppHash,
+		ProposerAddress: rawBlock.Block.Header.ProposerAddress,
+		Txs:             rawBlock.Block.Data.Txs,
+		Signatures:      parseBlockSignatures(rawBlock.Block.LastCommit.Signatures),
+		Evidences:       rawBlock.Block.Evidence.Evidence,
+	}, nil
 }
 
 func parseBlockSignatures(rawSignatures []model.RawBlockSignature) []model.BlockSignature {
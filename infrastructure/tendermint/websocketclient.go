@@ -0,0 +1,131 @@
+package tendermint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/tendermint"
+	usecase_model "github.com/AstraProtocol/astra-indexing/usecase/model"
+)
+
+var _ tendermint.BlockSubscriber = &WebSocketClient{}
+
+const NEW_BLOCK_EVENT_DATA_TYPE = "tendermint/event/NewBlock"
+const NEW_BLOCK_EVENT_QUERY = "tm.event='NewBlock'"
+
+const DEFAULT_WEBSOCKET_HANDSHAKE_TIMEOUT = 10 * time.Second
+
+// DEFAULT_WEBSOCKET_READ_TIMEOUT is the longest time without any new block before the subscription
+// is considered dropped
+const DEFAULT_WEBSOCKET_READ_TIMEOUT = 1 * time.Minute
+
+type WebSocketClient struct {
+	dialer       *websocket.Dialer
+	webSocketUrl string
+	readTimeout  time.Duration
+}
+
+// NewWebSocketClient returns a new WebSocketClient subscribing to the `/websocket` endpoint of the
+// tendermint RPC
+func NewWebSocketClient(tendermintRPCUrl string) *WebSocketClient {
+	dialer := *websocket.DefaultDialer
+	dialer.HandshakeTimeout = DEFAULT_WEBSOCKET_HANDSHAKE_TIMEOUT
+
+	return &WebSocketClient{
+		&dialer,
+		webSocketUrlOf(tendermintRPCUrl),
+		DEFAULT_WEBSOCKET_READ_TIMEOUT,
+	}
+}
+
+func webSocketUrlOf(tendermintRPCUrl string) string {
+	webSocketUrl := strings.TrimSuffix(tendermintRPCUrl, "/")
+	if strings.HasPrefix(webSocketUrl, "https://") {
+		webSocketUrl = "wss://" + strings.TrimPrefix(webSocketUrl, "https://")
+	} else if strings.HasPrefix(webSocketUrl, "http://") {
+		webSocketUrl = "ws://" + strings.TrimPrefix(webSocketUrl, "http://")
+	}
+
+	return webSocketUrl + "/websocket"
+}
+
+// SubscribeNewBlocks subscribes to the NewBlock event and sends the new blocks to the channel. It
+// blocks until the context is done or the subscription is dropped.
+//
+// NewBlock event of Tendermint v0.34 does not carry the block ID, the hash of the pushed blocks is
+// empty in that case.
+func (client *WebSocketClient) SubscribeNewBlocks(ctx context.Context, blockCh chan<- *usecase_model.Block) error {
+	conn, _, err := client.dialer.DialContext(ctx, client.webSocketUrl, nil)
+	if err != nil {
+		return fmt.Errorf("error connecting to Tendermint websocket %s: %v", client.redactedUrl(), err)
+	}
+	defer conn.Close()
+
+	// Unblock the reading below once the context is done
+	stopCloser := make(chan struct{})
+	defer close(stopCloser)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-stopCloser:
+		}
+	}()
+
+	if err = conn.WriteJSON(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "subscribe",
+		"id":      0,
+		"params": map[string]string{
+			"query": NEW_BLOCK_EVENT_QUERY,
+		},
+	}); err != nil {
+		return fmt.Errorf("error subscribing to Tendermint NewBlock event: %v", err)
+	}
+
+	for {
+		// The connection is closed once the context is done, which is not an error
+		if err = conn.SetReadDeadline(time.Now().Add(client.readTimeout)); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error setting Tendermint websocket read deadline: %v", err)
+		}
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error reading Tendermint websocket message: %v", err)
+		}
+
+		block, _, err := ParseNewBlockEventResp(bytes.NewReader(message))
+		if err != nil {
+			return err
+		}
+		if block == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case blockCh <- block:
+		}
+	}
+}
+
+// redactedUrl returns the websocket URL without the user credentials
+func (client *WebSocketClient) redactedUrl() string {
+	parsedUrl, err := url.Parse(client.webSocketUrl)
+	if err != nil {
+		return ""
+	}
+	return parsedUrl.Redacted()
+}
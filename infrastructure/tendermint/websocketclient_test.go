@@ -0,0 +1,152 @@
+package tendermint_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/AstraProtocol/astra-indexing/infrastructure/tendermint"
+	usecase_model "github.com/AstraProtocol/astra-indexing/usecase/model"
+)
+
+const NEW_BLOCK_EVENT_JSON = `{
+  "jsonrpc": "2.0",
+  "id": 0,
+  "result": {
+    "query": "tm.event='NewBlock'",
+    "data": {
+      "type": "tendermint/event/NewBlock",
+      "value": {
+        "block": {
+          "header": {
+            "version": {"block": "11"},
+            "chain_id": "astra_11110-1",
+            "height": "100",
+            "time": "2020-10-15T09:33:42.195143319Z",
+            "last_block_id": {
+              "hash": "1532E4FFBDE4FE8CCDF5654A097D534B8C6E2EBC4473F36CFE314C0421970C2E",
+              "parts": {"total": 1, "hash": "EEDFCCF098B1695CE939CF4E395AA8FC0EEC9F4673E1418B29E8928489BEF06A"}
+            },
+            "app_hash": "6AE0920938F76727054BC2531247632C5C0521E2B91EA3A9864EA4FF55023D77",
+            "proposer_address": "384E5F30F02538C0A34CBFF32F8D5554671C9029"
+          },
+          "data": {"txs": []},
+          "evidence": {"evidence": []},
+          "last_commit": {
+            "height": "99",
+            "round": 0,
+            "block_id": {
+              "hash": "1532E4FFBDE4FE8CCDF5654A097D534B8C6E2EBC4473F36CFE314C0421970C2E",
+              "parts": {"total": 1, "hash": "EEDFCCF098B1695CE939CF4E395AA8FC0EEC9F4673E1418B29E8928489BEF06A"}
+            },
+            "signatures": []
+          }
+        },
+        "result_begin_block": {},
+        "result_end_block": {"validator_updates": null}
+      }
+    },
+    "events": {"tm.event": ["NewBlock"]}
+  }
+}`
+
+// newStandInWebSocketServer starts a server answering the NewBlock subscription with the messages
+// and then closing the connection
+func newStandInWebSocketServer(messages []string, keepOpen bool) (*httptest.Server, <-chan map[string]interface{}) {
+	requestCh := make(chan map[string]interface{}, 1)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/websocket" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var request map[string]interface{}
+		if err := conn.ReadJSON(&request); err != nil {
+			return
+		}
+		requestCh <- request
+
+		for _, message := range messages {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+				return
+			}
+		}
+		if keepOpen {
+			// Wait until the client closes the connection
+			_, _, _ = conn.ReadMessage()
+		}
+	}))
+
+	return server, requestCh
+}
+
+var _ = Describe("WebSocketClient", func() {
+	Describe("SubscribeNewBlocks", func() {
+		It("should subscribe to NewBlock event and send the pushed blocks", func() {
+			server, requestCh := newStandInWebSocketServer([]string{
+				`{"jsonrpc": "2.0", "id": 0, "result": {}}`,
+				NEW_BLOCK_EVENT_JSON,
+			}, true)
+			defer server.Close()
+
+			client := tendermint.NewWebSocketClient(server.URL + "/")
+			ctx, cancel := context.WithCancel(context.Background())
+			blockCh := make(chan *usecase_model.Block)
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- client.SubscribeNewBlocks(ctx, blockCh)
+			}()
+
+			var request map[string]interface{}
+			Eventually(requestCh).Should(Receive(&request))
+			Expect(request["method"]).To(Equal("subscribe"))
+			Expect(request["params"]).To(Equal(map[string]interface{}{
+				"query": "tm.event='NewBlock'",
+			}))
+
+			var block *usecase_model.Block
+			Eventually(blockCh).Should(Receive(&block))
+			Expect(block.Height).To(Equal(int64(100)))
+			Expect(block.AppHash).To(Equal("6AE0920938F76727054BC2531247632C5C0521E2B91EA3A9864EA4FF55023D77"))
+			Expect(block.ProposerAddress).To(Equal("384E5F30F02538C0A34CBFF32F8D5554671C9029"))
+
+			cancel()
+			Eventually(errCh, time.Second).Should(Receive(BeNil()))
+		})
+
+		It("should return error when the subscription is dropped", func() {
+			server, _ := newStandInWebSocketServer([]string{
+				`{"jsonrpc": "2.0", "id": 0, "result": {}}`,
+			}, false)
+			defer server.Close()
+
+			client := tendermint.NewWebSocketClient(server.URL)
+			err := client.SubscribeNewBlocks(context.Background(), make(chan *usecase_model.Block))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return error when the subscription is rejected", func() {
+			server, _ := newStandInWebSocketServer([]string{
+				`{"jsonrpc": "2.0", "id": 0, "error": {"code": -32603, "message": "Internal error", "data": "max_subscriptions_per_client reached"}}`,
+			}, true)
+			defer server.Close()
+
+			client := tendermint.NewWebSocketClient(server.URL)
+			err := client.SubscribeNewBlocks(context.Background(), make(chan *usecase_model.Block))
+			Expect(err).To(MatchError(
+				"error subscribing to Tendermint NewBlock event: Internal error max_subscriptions_per_client reached",
+			))
+		})
+	})
+})
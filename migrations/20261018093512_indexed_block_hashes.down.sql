@@ -0,0 +1 @@
+DROP TABLE IF EXISTS indexed_block_hashes;
@@ -0,0 +1,6 @@
+CREATE TABLE indexed_block_hashes (
+    handler_id VARCHAR NOT NULL,
+    height BIGINT NOT NULL,
+    hash VARCHAR NOT NULL,
+    PRIMARY KEY(handler_id, height)
+);
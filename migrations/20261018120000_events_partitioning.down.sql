@@ -0,0 +1,19 @@
+CREATE TABLE events_unpartitioned (
+    id BIGINT NOT NULL DEFAULT nextval('events_id_seq'),
+    uuid VARCHAR,
+    height INT NOT NULL,
+    name VARCHAR NOT NULL,
+    version INT NOT NULL,
+    payload JSONB NOT NULL,
+    PRIMARY KEY(id),
+    UNIQUE(uuid)
+);
+
+INSERT INTO events_unpartitioned (id, uuid, height, name, version, payload)
+SELECT id, uuid, height, name, version, payload FROM events;
+
+ALTER SEQUENCE events_id_seq OWNED BY NONE;
+DROP TABLE events;
+ALTER TABLE events_unpartitioned RENAME TO events;
+ALTER SEQUENCE events_id_seq OWNED BY events.id;
+CREATE INDEX events_block_height_btree_index ON events USING btree (height);
@@ -0,0 +1,38 @@
+-- Partition the events by ranges of 100000 heights, the partition size must match
+-- `EVENTS_PARTITION_SIZE` of the event store. Further partitions are created by the event store
+-- before inserting events into them. The primary key and the unique constraint have to include the
+-- partition key.
+CREATE TABLE events_partitioned (
+    id BIGINT NOT NULL DEFAULT nextval('events_id_seq'),
+    uuid VARCHAR,
+    height INT NOT NULL,
+    name VARCHAR NOT NULL,
+    version INT NOT NULL,
+    payload JSONB NOT NULL,
+    PRIMARY KEY(height, id),
+    UNIQUE(height, uuid)
+) PARTITION BY RANGE (height);
+
+DO $$
+DECLARE
+    partition_size CONSTANT BIGINT := 100000;
+    partition_from BIGINT := 0;
+    max_height BIGINT;
+BEGIN
+    SELECT COALESCE(MAX(height), 0) INTO max_height FROM events;
+    WHILE partition_from <= max_height LOOP
+        EXECUTE format(
+            'CREATE TABLE events_p%s PARTITION OF events_partitioned FOR VALUES FROM (%s) TO (%s)',
+            partition_from, partition_from, partition_from + partition_size
+        );
+        partition_from := partition_from + partition_size;
+    END LOOP;
+END $$;
+
+INSERT INTO events_partitioned (id, uuid, height, name, version, payload)
+SELECT id, uuid, height, name, version, payload FROM events;
+
+ALTER SEQUENCE events_id_seq OWNED BY NONE;
+DROP TABLE events;
+ALTER TABLE events_partitioned RENAME TO events;
+ALTER SEQUENCE events_id_seq OWNED BY events.id;
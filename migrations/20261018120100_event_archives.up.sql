@@ -0,0 +1,8 @@
+CREATE TABLE event_archives (
+    from_height BIGINT NOT NULL,
+    to_height BIGINT NOT NULL,
+    file VARCHAR NOT NULL,
+    event_count BIGINT NOT NULL,
+    archived_at BIGINT NOT NULL,
+    PRIMARY KEY(from_height)
+);
@@ -0,0 +1,8 @@
+CREATE TABLE kafka_offsets (
+    group_id VARCHAR NOT NULL,
+    topic VARCHAR NOT NULL,
+    partition INT NOT NULL,
+    next_offset BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL,
+    PRIMARY KEY(group_id, topic, partition)
+);
@@ -0,0 +1,17 @@
+CREATE TABLE api_keys (
+    id BIGSERIAL PRIMARY KEY,
+    key_hash VARCHAR NOT NULL UNIQUE,
+    name VARCHAR NOT NULL,
+    scopes VARCHAR NOT NULL DEFAULT '',
+    daily_quota BIGINT NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at BIGINT NOT NULL
+);
+
+CREATE TABLE api_key_usages (
+    api_key_id BIGINT NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    day VARCHAR NOT NULL,
+    route_group VARCHAR NOT NULL,
+    request_count BIGINT NOT NULL,
+    PRIMARY KEY(api_key_id, day, route_group)
+);
@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS journal_projection_view() CASCADE;
+DROP TABLE IF EXISTS projection_journal_heights;
+DROP TABLE IF EXISTS projection_journal;
@@ -0,0 +1,42 @@
+CREATE TABLE projection_journal (
+    id BIGSERIAL,
+    projection_id VARCHAR NOT NULL,
+    block_height BIGINT NOT NULL,
+    table_name VARCHAR NOT NULL,
+    operation VARCHAR NOT NULL,
+    old_row JSONB NULL,
+    new_row JSONB NULL,
+    PRIMARY KEY(id)
+);
+
+CREATE INDEX projection_journal_projection_id_block_height_btree_index ON projection_journal USING btree (projection_id, block_height);
+
+CREATE TABLE projection_journal_heights (
+    projection_id VARCHAR NOT NULL,
+    from_height BIGINT NOT NULL,
+    PRIMARY KEY(projection_id)
+);
+
+-- Row trigger of the journaled view tables. It records the changes made while a projection handles
+-- a height, as set by rdbprojectionbase.Base.JournalViews() in the DB transaction, and ignores the
+-- changes made outside of it.
+CREATE FUNCTION journal_projection_view() RETURNS TRIGGER AS $$
+DECLARE
+    journal_projection_id VARCHAR := NULLIF(current_setting('projection_journal.projection_id', true), '');
+BEGIN
+    IF journal_projection_id IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO projection_journal (projection_id, block_height, table_name, operation, old_row, new_row)
+    VALUES (
+        journal_projection_id,
+        current_setting('projection_journal.block_height')::BIGINT,
+        TG_TABLE_NAME,
+        TG_OP,
+        CASE WHEN TG_OP IN ('UPDATE', 'DELETE') THEN to_jsonb(OLD) END,
+        CASE WHEN TG_OP IN ('INSERT', 'UPDATE') THEN to_jsonb(NEW) END
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
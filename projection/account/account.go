@@ -11,6 +11,7 @@ import (
 	"github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbprojectionbase"
 	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
 	event_entity "github.com/AstraProtocol/astra-indexing/entity/event"
+	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
 	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
 	"github.com/AstraProtocol/astra-indexing/infrastructure/pg/migrationhelper"
 	"github.com/AstraProtocol/astra-indexing/projection/account/view"
@@ -18,6 +19,11 @@ import (
 	event_usecase "github.com/AstraProtocol/astra-indexing/usecase/event"
 )
 
+var (
+	_ projection_entity.RebuildableProjection  = &Account{}
+	_ projection_entity.RollbackableProjection = &Account{}
+)
+
 // Account number, sequence number, balances are fetched from the latest state (regardless of current replaying height)
 type Account struct {
 	*rdbprojectionbase.Base
@@ -49,6 +55,7 @@ func NewAccount(
 
 var (
 	NewAccountsView              = view.NewAccountsView
+	JournalViews                 = (*Account).JournalViews
 	UpdateLastHandledEventHeight = (*Account).UpdateLastHandledEventHeight
 )
 
@@ -66,6 +73,21 @@ func (projection *Account) OnInit() error {
 	return nil
 }
 
+func (projection *Account) Reset(fromHeight int64) error {
+	return projection.ResetViews(projection.rdbConn, fromHeight, []string{
+		"view_accounts",
+	})
+}
+
+// Rollback undoes the journaled account changes, the accounts are fetched from the latest state and
+// cannot be derived back from the events
+func (projection *Account) Rollback(fromHeight int64, toHeight int64) error {
+	if _, err := projection.RollbackViews(projection.rdbConn, fromHeight, []string{}); err != nil {
+		return fmt.Errorf("error rolling back account views: %w", err)
+	}
+	return nil
+}
+
 func (projection *Account) HandleEvents(height int64, events []event_entity.Event) error {
 	rdbTx, err := projection.rdbConn.Begin()
 	if err != nil {
@@ -80,6 +102,9 @@ func (projection *Account) HandleEvents(height int64, events []event_entity.Even
 	}()
 
 	rdbTxHandle := rdbTx.ToHandle()
+	if err = JournalViews(projection, rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error journaling views: %v", err)
+	}
 
 	accountsView := NewAccountsView(rdbTxHandle)
 
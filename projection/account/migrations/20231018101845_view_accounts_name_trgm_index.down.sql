@@ -0,0 +1 @@
+DROP INDEX IF EXISTS view_accounts_name_trgm_index;
@@ -0,0 +1,3 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX view_accounts_name_trgm_index ON view_accounts USING gin (LOWER(name) gin_trgm_ops) WHERE name IS NOT NULL;
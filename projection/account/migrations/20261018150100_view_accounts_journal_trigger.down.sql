@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS view_accounts_journal ON view_accounts;
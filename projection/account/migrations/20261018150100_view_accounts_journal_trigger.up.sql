@@ -0,0 +1,2 @@
+CREATE TRIGGER view_accounts_journal AFTER INSERT OR UPDATE OR DELETE ON view_accounts
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
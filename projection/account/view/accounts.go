@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/AstraProtocol/astra-indexing/external/json"
 	"github.com/AstraProtocol/astra-indexing/usecase/coin"
@@ -20,6 +21,7 @@ type Accounts interface {
 	TotalAccount() (int64, error)
 	FindBy(*AccountIdentity) (*AccountRow, error)
 	List(AccountsListOrder, *pagination.Pagination) ([]AccountRow, *pagination.Result, error)
+	Search(keyword string, limit uint64) ([]AccountRow, error)
 }
 
 type AccountsView struct {
@@ -210,6 +212,57 @@ func (accountsView *AccountsView) List(
 	return accounts, paginationResult, nil
 }
 
+// Search returns up to `limit` labelled accounts whose name starts with the keyword or is similar to it, prefix
+// matches first.
+func (accountsView *AccountsView) Search(keyword string, limit uint64) ([]AccountRow, error) {
+	term := strings.ToLower(strings.TrimSpace(keyword))
+	if term == "" {
+		return []AccountRow{}, nil
+	}
+	prefixPattern := rdb.EscapeLikePattern(term) + "%"
+
+	sql, sqlArgs, err := accountsView.rdb.StmtBuilder.Select(
+		"address",
+		"account_type",
+		"name",
+		"pubkey",
+	).From(
+		"view_accounts",
+	).Where(
+		"name IS NOT NULL AND (LOWER(name) LIKE ? OR LOWER(name) % ?)", prefixPattern, term,
+	).OrderByClause(
+		"LOWER(name) LIKE ? DESC, similarity(LOWER(name), ?) DESC, address", prefixPattern, term,
+	).Limit(limit).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building accounts search SQL: %v, %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := accountsView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing accounts search SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	accounts := make([]AccountRow, 0)
+	for rowsResult.Next() {
+		var account AccountRow
+		if err = rowsResult.Scan(
+			&account.Address,
+			&account.Type,
+			&account.MaybeName,
+			&account.MaybePubkey,
+		); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				return nil, rdb.ErrNoRows
+			}
+			return nil, fmt.Errorf("error scanning account row: %v: %w", err, rdb.ErrQuery)
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
 type AccountRow struct {
 	Address        string     `json:"address"`
 	Type           string     `json:"type"`
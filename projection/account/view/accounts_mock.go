@@ -40,3 +40,9 @@ func (accountsView *MockAccountsView) List(
 	result1, _ := mockArgs.Get(1).(*pagination.Result)
 	return result0, result1, mockArgs.Error(2)
 }
+
+func (accountsView *MockAccountsView) Search(keyword string, limit uint64) ([]AccountRow, error) {
+	mockArgs := accountsView.Called(keyword, limit)
+	result, _ := mockArgs.Get(0).([]AccountRow)
+	return result, mockArgs.Error(1)
+}
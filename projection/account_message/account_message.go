@@ -18,7 +18,8 @@ import (
 	event_usecase "github.com/AstraProtocol/astra-indexing/usecase/event"
 )
 
-var _ projection_entity.Projection = &AccountMessage{}
+var _ projection_entity.RollbackableProjection = &AccountMessage{}
+var _ projection_entity.RebuildableProjection = &AccountMessage{}
 
 var (
 	NewAccountMessages           = view.NewAccountMessagesView
@@ -70,6 +71,13 @@ func (projection *AccountMessage) OnInit() error {
 	return nil
 }
 
+func (projection *AccountMessage) Reset(fromHeight int64) error {
+	return projection.ResetViews(projection.rdbConn, fromHeight, []string{
+		"view_account_messages",
+		"view_account_messages_total",
+	})
+}
+
 func (projection *AccountMessage) HandleEvents(height int64, events []event_entity.Event) error {
 	rdbTx, err := projection.rdbConn.Begin()
 	if err != nil {
@@ -939,3 +947,47 @@ func (projection *AccountMessage) HandleEvents(height int64, events []event_enti
 	committed = true
 	return nil
 }
+
+func (projection *AccountMessage) Rollback(fromHeight int64, toHeight int64) error {
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	accountMessagesView := NewAccountMessages(rdbTxHandle)
+	accountMessagesTotalView := NewAccountMessagesTotal(rdbTxHandle)
+
+	removals, err := accountMessagesView.DeleteAllByBlockHeightRange(fromHeight, toHeight)
+	if err != nil {
+		return fmt.Errorf("error deleting account messages: %w", err)
+	}
+
+	totalMap := make(map[string]int64)
+	for _, removal := range removals {
+		totalMap[fmt.Sprintf("%s:-", removal.Account)] += 1
+		totalMap[fmt.Sprintf("%s:%s", removal.Account, removal.MessageType)] += 1
+	}
+	for identity, total := range totalMap {
+		if err := accountMessagesTotalView.DecrementAll([]string{identity}, total); err != nil {
+			return fmt.Errorf("error decrementing total account message of account: %w", err)
+		}
+	}
+
+	if err := projection.RewindLastHandledEventHeight(rdbTxHandle, fromHeight); err != nil {
+		return fmt.Errorf("error rewinding last handled event height: %v", err)
+	}
+
+	if err := rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 
 type AccountMessages interface {
 	Insert(*AccountMessageRow, []string) error
+	DeleteAllByBlockHeightRange(int64, int64) ([]AccountMessageRemoval, error)
 	List(AccountMessagesListFilter, AccountMessagesListOrder, *pagination_interface.Pagination) ([]AccountMessageRow, *pagination_interface.Result, error)
 }
 
@@ -79,6 +80,39 @@ func (accountMessagesView *AccountMessagesView) Insert(messageRow *AccountMessag
 	return nil
 }
 
+// DeleteAllByBlockHeightRange removes all account messages with block height within
+// [fromHeight, toHeight] and returns the removed account and message type pairs
+func (accountMessagesView *AccountMessagesView) DeleteAllByBlockHeightRange(
+	fromHeight int64,
+	toHeight int64,
+) ([]AccountMessageRemoval, error) {
+	sql, sqlArgs, err := accountMessagesView.rdb.StmtBuilder.Delete(
+		"view_account_messages",
+	).Where(
+		"block_height >= ? AND block_height <= ?", fromHeight, toHeight,
+	).Suffix("RETURNING account, message_type").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building account messages deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := accountMessagesView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting account messages from the table: %v: %w", err, rdb.ErrWrite)
+	}
+	defer rowsResult.Close()
+
+	removals := make([]AccountMessageRemoval, 0)
+	for rowsResult.Next() {
+		var removal AccountMessageRemoval
+		if err = rowsResult.Scan(&removal.Account, &removal.MessageType); err != nil {
+			return nil, fmt.Errorf("error scanning deleted account message: %v: %w", err, rdb.ErrQuery)
+		}
+		removals = append(removals, removal)
+	}
+
+	return removals, nil
+}
+
 func (accountMessagesView *AccountMessagesView) List(
 	filter AccountMessagesListFilter,
 	order AccountMessagesListOrder,
@@ -209,6 +243,11 @@ type AccountMessageRow struct {
 	Data            interface{}     `json:"data"`
 }
 
+type AccountMessageRemoval struct {
+	Account     string
+	MessageType string
+}
+
 type AccountMessagesListFilter struct {
 	// Required account filter
 	Account string
@@ -97,3 +97,12 @@ func (accountMessagesView *MockAccountMessagesView) List(
 	result1, _ := mockArgs.Get(1).(*pagination_interface.Result)
 	return result0, result1, mockArgs.Error(2)
 }
+
+func (accountMessagesView *MockAccountMessagesView) DeleteAllByBlockHeightRange(
+	fromHeight int64,
+	toHeight int64,
+) ([]AccountMessageRemoval, error) {
+	mockArgs := accountMessagesView.Called(fromHeight, toHeight)
+	result, _ := mockArgs.Get(0).([]AccountMessageRemoval)
+	return result, mockArgs.Error(1)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
 	event_entity "github.com/AstraProtocol/astra-indexing/entity/event"
 	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
+	"github.com/AstraProtocol/astra-indexing/external/json"
 	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
 	"github.com/AstraProtocol/astra-indexing/external/tmcosmosutils"
 	"github.com/AstraProtocol/astra-indexing/external/utctime"
@@ -22,7 +23,10 @@ import (
 )
 
 var (
-	_ projection_entity.Projection = &AccountTransaction{}
+	_ projection_entity.Projection             = &AccountTransaction{}
+	_ projection_entity.RebuildableProjection  = &AccountTransaction{}
+	_ projection_entity.RollbackableProjection = &AccountTransaction{}
+	_ projection_entity.DependentProjection    = &AccountTransaction{}
 )
 
 const DELEGATE = "delegate"
@@ -39,6 +43,9 @@ type AccountTransaction struct {
 
 	migrationHelper migrationhelper.MigrationHelper
 	evmUtil         evmUtil.EvmUtils
+
+	// Decodes the stored message events on rollback
+	eventRegistry *event_entity.Registry
 }
 
 func NewAccountTransaction(
@@ -50,6 +57,10 @@ func NewAccountTransaction(
 ) *AccountTransaction {
 	cfg := sdk.GetConfig()
 	cfg.SetBech32PrefixForAccount("astra", "astrapub")
+
+	eventRegistry := event_entity.NewRegistry()
+	event_usecase.RegisterEvents(eventRegistry)
+
 	return &AccountTransaction{
 		rdbprojectionbase.NewRDbBase(
 			rdbConn.ToHandle(),
@@ -63,9 +74,16 @@ func NewAccountTransaction(
 
 		migrationHelper,
 		evmUtil,
+
+		eventRegistry,
 	}
 }
 
+// The account transactions are joined with the transaction views written by the Transaction projection
+func (*AccountTransaction) GetDependencies() []string {
+	return []string{"Transaction"}
+}
+
 func (*AccountTransaction) GetEventsToListen() []string {
 	return append([]string{
 		event_usecase.BLOCK_CREATED,
@@ -81,6 +99,16 @@ func (projection *AccountTransaction) OnInit() error {
 	return nil
 }
 
+func (projection *AccountTransaction) Reset(fromHeight int64) error {
+	return projection.ResetViews(projection.rdbConn, fromHeight, []string{
+		"view_account_transactions",
+		"view_account_transaction_data",
+		"view_account_transactions_total",
+		"view_account_gas_used_total",
+		"view_account_fees_total",
+	})
+}
+
 func (projection *AccountTransaction) HandleEvents(height int64, events []event_entity.Event) error {
 	rdbTx, err := projection.rdbConn.Begin()
 	if err != nil {
@@ -504,10 +532,7 @@ func (projection *AccountTransaction) HandleEvents(height int64, events []event_
 		*/
 
 		// Calculate account gas used and account fees total
-		if tmcosmosutils.IsValidCosmosAddress(senderAddress) {
-			_, converted, _ := tmcosmosutils.DecodeAddressToHex(senderAddress)
-			address := "0x" + hex.EncodeToString(converted)
-
+		if address, ok := gasUsedTotalIdentity(senderAddress); ok {
 			if err := accountGasUsedTotalView.Increment(address, int64(tx.GasUsed)); err != nil {
 				return fmt.Errorf("error incrementing total gas used of account: %w", err)
 			}
@@ -517,23 +542,12 @@ func (projection *AccountTransaction) HandleEvents(height int64, events []event_
 				}
 			*/
 		} else {
-			if evmUtil.IsHexAddress(senderAddress) {
-				if err := accountGasUsedTotalView.Increment(senderAddress, int64(tx.GasUsed)); err != nil {
-					return fmt.Errorf("error incrementing total gas used of account: %w", err)
-				}
-				/*
-					if err := accountFeesTotalView.Increment(senderAddress, int64(math.Round(fees))); err != nil {
-						return fmt.Errorf("error incrementing total fees of account: %w", err)
-					}
-				*/
+			if msgEvent == nil {
+				projection.logger.Debug("message event is empty")
 			} else {
-				if msgEvent == nil {
-					projection.logger.Debug("message event is empty")
-				} else {
-					projection.logger.Debugf("error message event: %v", msgEvent.String())
-				}
-				projection.logger.Debugf("error preparing total gas used and total fees of account: %v", senderAddress)
+				projection.logger.Debugf("error message event: %v", msgEvent.String())
 			}
+			projection.logger.Debugf("error preparing total gas used and total fees of account: %v", senderAddress)
 		}
 
 		for _, row := range rows {
@@ -580,6 +594,127 @@ func (projection *AccountTransaction) HandleEvents(height int64, events []event_
 	return nil
 }
 
+func (projection *AccountTransaction) Rollback(fromHeight int64, toHeight int64) error {
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	accountTransactionsView := view.NewAccountTransactions(rdbTxHandle)
+	accountTransactionDataView := view.NewAccountTransactionData(rdbTxHandle)
+	accountTransactionsTotalView := view.NewAccountTransactionsTotal(rdbTxHandle)
+	accountGasUsedTotalView := view.NewAccountGasUsedTotal(rdbTxHandle)
+
+	txRemovals, err := accountTransactionDataView.DeleteAllByBlockHeightRange(fromHeight, toHeight)
+	if err != nil {
+		return fmt.Errorf("error deleting account transaction data: %w", err)
+	}
+
+	txMemos := make(map[string]string)
+	gasUsedTotalMap := make(map[string]int64)
+	for _, txRemoval := range txRemovals {
+		txMemos[txRemoval.Hash] = txRemoval.Memo
+
+		// The gas used is accounted to the sender of the first message, as in HandleEvents
+		if len(txRemoval.Messages) == 0 {
+			continue
+		}
+		msgEvent, err := projection.decodeMsgEvent(txRemoval.Messages[0].Content)
+		if err != nil {
+			return fmt.Errorf("error decoding message event of transaction %s: %v", txRemoval.Hash, err)
+		}
+		if address, ok := gasUsedTotalIdentity(tmcosmosutils.ParseSenderAddressFromMsgEvent(msgEvent)); ok {
+			gasUsedTotalMap[address] += int64(txRemoval.GasUsed)
+		}
+	}
+
+	accountTransactionRemovals, err := accountTransactionsView.DeleteAllByBlockHeightRange(fromHeight, toHeight)
+	if err != nil {
+		return fmt.Errorf("error deleting account transactions: %w", err)
+	}
+
+	totalMap := make(map[string]int64)
+	for _, removal := range accountTransactionRemovals {
+		accounts := []string{removal.Account}
+		if memo := txMemos[removal.Hash]; memo != "" {
+			accounts = append(accounts, removal.Account+"/"+memo)
+		}
+		for _, account := range accounts {
+			totalMap[fmt.Sprintf("%s:-", account)] += 1
+			for _, messageType := range removal.MessageTypes {
+				totalMap[fmt.Sprintf("%s:%s", account, messageType)] += 1
+			}
+		}
+	}
+	for identity, total := range totalMap {
+		if err := accountTransactionsTotalView.DecrementAll([]string{identity}, total); err != nil {
+			return fmt.Errorf("error decrementing total account transaction of account: %w", err)
+		}
+	}
+	for identity, total := range gasUsedTotalMap {
+		if err := accountGasUsedTotalView.DecrementAll([]string{identity}, total); err != nil {
+			return fmt.Errorf("error decrementing total gas used of account: %w", err)
+		}
+	}
+
+	if err := projection.RewindLastHandledEventHeight(rdbTxHandle, fromHeight); err != nil {
+		return fmt.Errorf("error rewinding last handled event height: %v", err)
+	}
+
+	if err := rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
+
+// decodeMsgEvent decodes a message event stored in the transaction data messages
+func (projection *AccountTransaction) decodeMsgEvent(content interface{}) (event_usecase.MsgEvent, error) {
+	encodedStr, err := json.MarshalToString(content)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding message content: %v", err)
+	}
+	encoded := []byte(encodedStr)
+
+	var eventType struct {
+		Name    string `json:"name"`
+		Version int    `json:"version"`
+	}
+	if err = json.Unmarshal(encoded, &eventType); err != nil {
+		return nil, fmt.Errorf("error decoding message event type: %v", err)
+	}
+
+	event, err := projection.eventRegistry.DecodeByType(eventType.Name, eventType.Version, encoded)
+	if err != nil {
+		return nil, err
+	}
+	msgEvent, ok := event.(event_usecase.MsgEvent)
+	if !ok {
+		return nil, fmt.Errorf("event %s is not a message event", eventType.Name)
+	}
+	return msgEvent, nil
+}
+
+// gasUsedTotalIdentity returns the hex address the gas used by a transaction of the sender is totalled under
+func gasUsedTotalIdentity(senderAddress string) (string, bool) {
+	if tmcosmosutils.IsValidCosmosAddress(senderAddress) {
+		_, converted, _ := tmcosmosutils.DecodeAddressToHex(senderAddress)
+		return "0x" + hex.EncodeToString(converted), true
+	}
+	if evmUtil.IsHexAddress(senderAddress) {
+		return senderAddress, true
+	}
+	return "", false
+}
+
 func (projection *AccountTransaction) ParseSenderAddresses(senders []model.TransactionSigner) []string {
 	addresses := make([]string, 0, len(senders))
 	for _, sender := range senders {
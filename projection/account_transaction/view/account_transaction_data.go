@@ -110,6 +110,44 @@ func (transactionsView *AccountTransactionData) InsertAll(transactions []Transac
 	return nil
 }
 
+// DeleteAllByBlockHeightRange removes the transaction data written by the projection with block height
+// within [fromHeight, toHeight] and returns them. The rows written from the Kafka topics have no block
+// hash and are kept as they are not replayed.
+func (transactionsView *AccountTransactionData) DeleteAllByBlockHeightRange(
+	fromHeight int64,
+	toHeight int64,
+) ([]TransactionDataRemoval, error) {
+	sql, sqlArgs, err := transactionsView.rdb.StmtBuilder.Delete(
+		"view_account_transaction_data",
+	).Where(
+		"block_height >= ? AND block_height <= ? AND block_hash <> ''", fromHeight, toHeight,
+	).Suffix("RETURNING hash, gas_used, memo, messages").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building account transaction data deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := transactionsView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting account transaction data from the table: %v: %w", err, rdb.ErrWrite)
+	}
+	defer rowsResult.Close()
+
+	removals := make([]TransactionDataRemoval, 0)
+	for rowsResult.Next() {
+		var removal TransactionDataRemoval
+		var messagesJSON string
+		if err = rowsResult.Scan(&removal.Hash, &removal.GasUsed, &removal.Memo, &messagesJSON); err != nil {
+			return nil, fmt.Errorf("error scanning deleted account transaction data: %v: %w", err, rdb.ErrQuery)
+		}
+		if err = json.UnmarshalFromString(messagesJSON, &removal.Messages); err != nil {
+			return nil, fmt.Errorf("error unmarshalling deleted account transaction data messages: %v: %w", err, rdb.ErrQuery)
+		}
+		removals = append(removals, removal)
+	}
+
+	return removals, nil
+}
+
 func (transactionsView *AccountTransactionData) Insert(transaction *TransactionRow) error {
 	var err error
 
@@ -245,6 +283,13 @@ type TransactionRowMessage struct {
 	Content interface{} `json:"content"`
 }
 
+type TransactionDataRemoval struct {
+	Hash     string
+	GasUsed  int
+	Memo     string
+	Messages []TransactionRowMessage
+}
+
 type TransactionsListFilter struct {
 	MaybeBlockHeight *int64
 }
@@ -105,6 +105,44 @@ func (accountMessagesView *AccountTransactions) InsertAll(
 	return nil
 }
 
+// DeleteAllByBlockHeightRange removes the account transactions written by the projection with block height
+// within [fromHeight, toHeight] and returns them. The internal txs and token transfers written from the
+// Kafka topics are kept as they are not replayed.
+func (accountMessagesView *AccountTransactions) DeleteAllByBlockHeightRange(
+	fromHeight int64,
+	toHeight int64,
+) ([]AccountTransactionRemoval, error) {
+	sql, sqlArgs, err := accountMessagesView.rdb.StmtBuilder.Delete(
+		"view_account_transactions",
+	).Where(
+		"block_height >= ? AND block_height <= ? AND is_internal_tx = FALSE", fromHeight, toHeight,
+	).Suffix("RETURNING account, transaction_hash, message_types").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building account transactions deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := accountMessagesView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting account transactions from the table: %v: %w", err, rdb.ErrWrite)
+	}
+	defer rowsResult.Close()
+
+	removals := make([]AccountTransactionRemoval, 0)
+	for rowsResult.Next() {
+		var removal AccountTransactionRemoval
+		var messageTypesJSON string
+		if err = rowsResult.Scan(&removal.Account, &removal.Hash, &messageTypesJSON); err != nil {
+			return nil, fmt.Errorf("error scanning deleted account transaction: %v: %w", err, rdb.ErrQuery)
+		}
+		if err = json.UnmarshalFromString(messageTypesJSON, &removal.MessageTypes); err != nil {
+			return nil, fmt.Errorf("error unmarshalling deleted account transaction message types: %v: %w", err, rdb.ErrQuery)
+		}
+		removals = append(removals, removal)
+	}
+
+	return removals, nil
+}
+
 func (accountMessagesView *AccountTransactions) List(
 	filter AccountTransactionsListFilter,
 	order AccountTransactionsListOrder,
@@ -301,15 +339,12 @@ func (accountMessagesView *AccountTransactions) List(
 		}
 	}
 
-	if order.Id == view.ORDER_DESC {
-		stmtBuilder = stmtBuilder.OrderBy("view_account_transactions.id DESC")
-	} else {
-		stmtBuilder = stmtBuilder.OrderBy("view_account_transactions.id")
-	}
-
 	rDbPagination := rdb.NewRDbPaginationBuilder(
 		pagination,
 		accountMessagesView.rdb,
+	).WithKeyset(
+		// DISTINCT ON requires the id first in the order
+		rdb.KeysetColumn{Column: "view_account_transactions.id", Desc: order.Id == view.ORDER_DESC},
 	).WithCustomTotalQueryFn(
 		func(rdbHandle *rdb.Handle, _ sq.SelectBuilder) (int64, error) {
 			identity := ""
@@ -425,7 +460,9 @@ func (accountMessagesView *AccountTransactions) List(
 		accountMessages = append(accountMessages, accountMessage)
 	}
 
-	paginationResult, err := rDbPagination.Result()
+	accountMessages, paginationResult, err := rdb.CursorPage(rDbPagination, accountMessages, func(i int) []interface{} {
+		return []interface{}{accountMessages[i].Id}
+	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("error preparing pagination result: %v", err)
 	}
@@ -453,6 +490,12 @@ type AccountTransactionBaseRow struct {
 	TxIndex      int             `json:"tx_index,omitempty"`
 }
 
+type AccountTransactionRemoval struct {
+	Account      string
+	Hash         string
+	MessageTypes []string
+}
+
 type AccountTransactionReadRow struct {
 	AccountTransactionBaseRow
 
@@ -13,7 +13,9 @@ import (
 	event_usecase "github.com/AstraProtocol/astra-indexing/usecase/event"
 )
 
-var _ entity_projection.Projection = &Block{}
+var _ entity_projection.RollbackableProjection = &Block{}
+var _ entity_projection.RebuildableProjection = &Block{}
+var _ entity_projection.BatchProjection = &Block{}
 
 // TODO: Listen to council node related events and project council node
 type Block struct {
@@ -54,7 +56,17 @@ func (projection *Block) OnInit() error {
 	return nil
 }
 
+func (projection *Block) Reset(fromHeight int64) error {
+	return projection.ResetViews(projection.rdbConn, fromHeight, []string{
+		"view_blocks",
+	})
+}
+
 func (projection *Block) HandleEvents(height int64, events []event_entity.Event) error {
+	return projection.HandleEventsBatch(height, height, events)
+}
+
+func (projection *Block) HandleEventsBatch(fromHeight int64, toHeight int64, events []event_entity.Event) error {
 	rdbTx, err := projection.rdbConn.Begin()
 	if err != nil {
 		return fmt.Errorf("error beginning transaction: %v", err)
@@ -77,7 +89,7 @@ func (projection *Block) HandleEvents(height int64, events []event_entity.Event)
 			}
 		}
 	}
-	if err = projection.UpdateLastHandledEventHeight(rdbTxHandle, height); err != nil {
+	if err = projection.UpdateLastHandledEventHeight(rdbTxHandle, toHeight); err != nil {
 		return fmt.Errorf("error updating last handled event height: %v", err)
 	}
 
@@ -88,6 +100,36 @@ func (projection *Block) HandleEvents(height int64, events []event_entity.Event)
 	return nil
 }
 
+func (projection *Block) Rollback(fromHeight int64, toHeight int64) error {
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	blocksView := view.NewBlocks(rdbTxHandle)
+
+	if _, err = blocksView.DeleteByHeightRange(fromHeight, toHeight); err != nil {
+		return fmt.Errorf("error deleting blocks: %v", err)
+	}
+	if err = projection.RewindLastHandledEventHeight(rdbTxHandle, fromHeight); err != nil {
+		return fmt.Errorf("error rewinding last handled event height: %v", err)
+	}
+
+	if err = rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
+
 func (projection *Block) handleBlockCreatedEvent(blocksView *view.Blocks, event *event_usecase.BlockCreated) error {
 	committedCouncilNodes := make([]view.BlockCommittedCouncilNode, 0)
 	for _, signature := range event.Block.Signatures {
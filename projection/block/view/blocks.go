@@ -71,6 +71,25 @@ func (blocksView *Blocks) Insert(block *Block) error {
 	return nil
 }
 
+// DeleteByHeightRange removes all blocks with height within [fromHeight, toHeight]
+func (blocksView *Blocks) DeleteByHeightRange(fromHeight int64, toHeight int64) (int64, error) {
+	sql, sqlArgs, err := blocksView.rdb.StmtBuilder.Delete(
+		"view_blocks",
+	).Where(
+		"height >= ? AND height <= ?", fromHeight, toHeight,
+	).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error building blocks deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := blocksView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting blocks from the table: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return result.RowsAffected(), nil
+}
+
 func (blocksView *Blocks) List(order BlocksListOrder, pagination *pagination.Pagination) ([]Block, *pagination.Result, error) {
 	stmtBuilder := blocksView.rdb.StmtBuilder.Select(
 		"height",
@@ -83,15 +102,11 @@ func (blocksView *Blocks) List(order BlocksListOrder, pagination *pagination.Pag
 		"view_blocks",
 	)
 
-	if order.Height == view.ORDER_DESC {
-		stmtBuilder = stmtBuilder.OrderBy("height DESC")
-	} else {
-		stmtBuilder = stmtBuilder.OrderBy("height")
-	}
-
 	rDbPagination := rdb.NewRDbPaginationBuilder(
 		pagination,
 		blocksView.rdb,
+	).WithKeyset(
+		rdb.KeysetColumn{Column: "height", Desc: order.Height == view.ORDER_DESC},
 	).WithCustomTotalQueryFn(
 		func(rdbHandle *rdb.Handle, _ sq.SelectBuilder) (int64, error) {
 			var total int64
@@ -146,7 +161,9 @@ func (blocksView *Blocks) List(order BlocksListOrder, pagination *pagination.Pag
 		blocks = append(blocks, block)
 	}
 
-	paginationResult, err := rDbPagination.Result()
+	blocks, paginationResult, err := rdb.CursorPage(rDbPagination, blocks, func(i int) []interface{} {
+		return []interface{}{blocks[i].Height}
+	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("error preparing pagination result: %v", err)
 	}
@@ -203,6 +220,65 @@ func (blocksView *Blocks) FindBy(identity *BlockIdentity) (*Block, error) {
 	return &block, nil
 }
 
+// ListByHeights returns the blocks of the heights in ascending height, heights without a block are
+// left out
+func (blocksView *Blocks) ListByHeights(heights []int64) ([]Block, error) {
+	if len(heights) == 0 {
+		return []Block{}, nil
+	}
+
+	sql, sqlArgs, err := blocksView.rdb.StmtBuilder.Select(
+		"height", "hash", "time", "app_hash", "committed_council_nodes", "transaction_count",
+	).From(
+		"view_blocks",
+	).Where(
+		sq.Eq{"height": heights},
+	).OrderBy(
+		"height",
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building blocks selection sql: %v: %w", err, rdb.ErrPrepare)
+	}
+
+	rowsResult, err := blocksView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing blocks selection SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	blocks := make([]Block, 0, len(heights))
+	for rowsResult.Next() {
+		var block Block
+		var committedCouncilNodesJSON *string
+		timeReader := blocksView.rdb.NtotReader()
+		if err = rowsResult.Scan(
+			&block.Height,
+			&block.Hash,
+			timeReader.ScannableArg(),
+			&block.AppHash,
+			&committedCouncilNodesJSON,
+			&block.TransactionCount,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning block row: %v: %w", err, rdb.ErrQuery)
+		}
+		blockTime, parseErr := timeReader.Parse()
+		if parseErr != nil {
+			return nil, fmt.Errorf("error parsing block time: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		block.Time = *blockTime
+
+		var committedCouncilNodes []BlockCommittedCouncilNode
+		if unmarshalErr := jsoniter.Unmarshal([]byte(*committedCouncilNodesJSON), &committedCouncilNodes); unmarshalErr != nil {
+			return nil, fmt.Errorf("error unmarshalling block council nodes JSON: %v: %w", unmarshalErr, rdb.ErrQuery)
+		}
+		block.CommittedCouncilNodes = committedCouncilNodes
+
+		blocks = append(blocks, block)
+	}
+
+	return blocks, nil
+}
+
 func (blocksView *Blocks) Search(
 	keyword string,
 ) ([]Block, error) {
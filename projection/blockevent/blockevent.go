@@ -3,6 +3,7 @@ package blockevent
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbprojectionbase"
 	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
@@ -15,7 +16,9 @@ import (
 	event_usecase "github.com/AstraProtocol/astra-indexing/usecase/event"
 )
 
-var _ projection.Projection = &BlockEvent{}
+var _ projection.RollbackableProjection = &BlockEvent{}
+var _ projection.RebuildableProjection = &BlockEvent{}
+var _ projection.BatchProjection = &BlockEvent{}
 
 type BlockEvent struct {
 	*rdbprojectionbase.Base
@@ -71,7 +74,22 @@ func (projection *BlockEvent) OnInit() error {
 	return nil
 }
 
+func (projection *BlockEvent) Reset(fromHeight int64) error {
+	return projection.ResetViews(projection.rdbConn, fromHeight, []string{
+		"view_block_events",
+		"view_block_events_total",
+	})
+}
+
 func (projection *BlockEvent) HandleEvents(height int64, events []event_entity.Event) error {
+	return projection.HandleEventsBatch(height, height, events)
+}
+
+func (projection *BlockEvent) HandleEventsBatch(
+	fromHeight int64,
+	toHeight int64,
+	events []event_entity.Event,
+) error {
 	var err error
 
 	var rdbTx rdb.Tx
@@ -91,8 +109,39 @@ func (projection *BlockEvent) HandleEvents(height int64, events []event_entity.E
 	eventsView := view.NewBlockEvents(rdbTxHandle)
 	totalView := view.NewBlockEventsTotal(rdbTxHandle)
 
-	totalMap := make(map[string]int64)
+	eventsByHeight := map[int64][]event_entity.Event{fromHeight: events}
+	if toHeight > fromHeight {
+		eventsByHeight = make(map[int64][]event_entity.Event)
+		for _, event := range events {
+			eventsByHeight[event.Height()] = append(eventsByHeight[event.Height()], event)
+		}
+	}
+	for height := fromHeight; height <= toHeight; height++ {
+		if err = projection.handleEventsAtHeight(eventsView, totalView, height, eventsByHeight[height]); err != nil {
+			return err
+		}
+	}
+
+	if err = projection.UpdateLastHandledEventHeight(rdbTxHandle, toHeight); err != nil {
+		return fmt.Errorf("error updating last handled event height: %v", err)
+	}
+
+	if err = rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
 
+func (projection *BlockEvent) handleEventsAtHeight(
+	eventsView *view.BlockEvents,
+	totalView *view.BlockEventsTotal,
+	height int64,
+	events []event_entity.Event,
+) error {
+	var err error
+
+	totalMap := make(map[string]int64)
 	var blockTime utctime.UTCTime
 	var blockHash string
 	eventRows := make([]view.BlockEventRow, 0)
@@ -143,8 +192,64 @@ func (projection *BlockEvent) HandleEvents(height int64, events []event_entity.E
 		return fmt.Errorf("error batch inserting events into view: %v", err)
 	}
 
-	if err = projection.UpdateLastHandledEventHeight(rdbTxHandle, height); err != nil {
-		return fmt.Errorf("error updating last handled event height: %v", err)
+	return nil
+}
+
+func (projection *BlockEvent) Rollback(fromHeight int64, toHeight int64) error {
+	var err error
+
+	var rdbTx rdb.Tx
+	rdbTx, err = projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	eventsView := view.NewBlockEvents(rdbTxHandle)
+	totalView := view.NewBlockEventsTotal(rdbTxHandle)
+
+	var deletedEventTypes []string
+	if deletedEventTypes, err = eventsView.DeleteAllByBlockHeightRange(fromHeight, toHeight); err != nil {
+		return fmt.Errorf("error deleting events from view: %v", err)
+	}
+
+	totalMap := make(map[string]int64)
+	for _, eventType := range deletedEventTypes {
+		totalMap[fmt.Sprintf("-:%s", eventType)] -= 1
+	}
+	totalMap["-"] = -int64(len(deletedEventTypes))
+	for key, value := range totalMap {
+		if err = totalView.Increment(key, value); err != nil {
+			return fmt.Errorf("error decrementing block event type total")
+		}
+	}
+	// Height totals are reset instead of removed, they are set again when the heights are re-indexed
+	for height := fromHeight; height <= toHeight; height += 1 {
+		if err = totalView.Set(strconv.FormatInt(height, 10), 0); err != nil {
+			return fmt.Errorf("error resetting block event total")
+		}
+	}
+	for key := range totalMap {
+		if key == "-" {
+			continue
+		}
+		eventType := strings.TrimPrefix(key, "-:")
+		for height := fromHeight; height <= toHeight; height += 1 {
+			if err = totalView.Set(fmt.Sprintf("%d:%s", height, eventType), 0); err != nil {
+				return fmt.Errorf("error resetting block event type total")
+			}
+		}
+	}
+
+	if err = projection.RewindLastHandledEventHeight(rdbTxHandle, fromHeight); err != nil {
+		return fmt.Errorf("error rewinding last handled event height: %v", err)
 	}
 
 	if err = rdbTx.Commit(); err != nil {
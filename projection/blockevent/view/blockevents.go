@@ -123,6 +123,36 @@ func (eventsView *BlockEvents) InsertAll(blockEvents []BlockEventRow) error {
 	return nil
 }
 
+// DeleteAllByBlockHeightRange removes all events with block height within [fromHeight, toHeight]
+// and returns the event types of the removed events
+func (eventsView *BlockEvents) DeleteAllByBlockHeightRange(fromHeight int64, toHeight int64) ([]string, error) {
+	sql, sqlArgs, err := eventsView.rdb.StmtBuilder.Delete(
+		"view_block_events",
+	).Where(
+		"block_height >= ? AND block_height <= ?", fromHeight, toHeight,
+	).Suffix("RETURNING data->>'type'").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building block events deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := eventsView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting block events from the table: %v: %w", err, rdb.ErrWrite)
+	}
+	defer rowsResult.Close()
+
+	eventTypes := make([]string, 0)
+	for rowsResult.Next() {
+		var eventType string
+		if err = rowsResult.Scan(&eventType); err != nil {
+			return nil, fmt.Errorf("error scanning deleted block event type: %v: %w", err, rdb.ErrQuery)
+		}
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	return eventTypes, nil
+}
+
 func (eventsView *BlockEvents) FindById(id int64) (*BlockEventRow, error) {
 	var err error
 
@@ -20,6 +20,8 @@ import (
 )
 
 var _ entity_projection.Projection = &ChainStats{}
+var _ entity_projection.DependentProjection = &ChainStats{}
+var _ entity_projection.RollbackableProjection = &ChainStats{}
 
 const GENESIS_BLOCK_TIME = "genesis_block_time"
 const TOTAL_BLOCK_TIME = "total_block_time"
@@ -58,6 +60,11 @@ func NewChainStats(
 	}
 }
 
+// The stats combine the transaction views written by the Transaction projection
+func (_ *ChainStats) GetDependencies() []string {
+	return []string{"Transaction"}
+}
+
 func (_ *ChainStats) GetEventsToListen() []string {
 	return []string{
 		event_usecase.GENESIS_CREATED,
@@ -75,12 +82,23 @@ func (projection *ChainStats) OnInit() error {
 		projection.migrationHelper.Migrate()
 	}
 
+	if err := projection.loadStats(); err != nil {
+		return fmt.Errorf("error loading stats on init: %v", err)
+	}
+	return nil
+}
+
+// loadStats loads the genesis block time and the total block count kept in memory from the view
+func (projection *ChainStats) loadStats() error {
 	chainStatsView := view.NewChainStats(projection.rdbConn.ToHandle())
 
+	projection.maybeGenesisBlockTime = nil
+	projection.maybeTotalBlockCount = nil
+
 	var getErr error
 	rawGenesisBlockTime, getErr := chainStatsView.FindBy(GENESIS_BLOCK_TIME)
 	if getErr != nil {
-		return fmt.Errorf("error getting genesis block time on init: %v", getErr)
+		return fmt.Errorf("error getting genesis block time: %v", getErr)
 	}
 
 	if rawGenesisBlockTime == "" {
@@ -89,19 +107,32 @@ func (projection *ChainStats) OnInit() error {
 
 	unixNanoTime, parseErr := strconv.ParseInt(rawGenesisBlockTime, 10, 64)
 	if parseErr != nil {
-		return fmt.Errorf("error parsing genesis block time on init: %v", parseErr)
+		return fmt.Errorf("error parsing genesis block time: %v", parseErr)
 	}
-	projection.maybeGenesisBlockTime = &unixNanoTime
 
 	rawTotalBlockCount, getErr := chainStatsView.FindBy(TOTAL_BLOCK_COUNT)
 	if getErr != nil {
-		return fmt.Errorf("error getting total block count on init: %v", getErr)
+		return fmt.Errorf("error getting total block count: %v", getErr)
 	}
-	var ok bool
-	if projection.maybeTotalBlockCount, ok = new(big.Int).SetString(rawTotalBlockCount, 10); !ok {
-		return errors.New("error parsing total block count as big.Int on init")
+	totalBlockCount, ok := new(big.Int).SetString(rawTotalBlockCount, 10)
+	if !ok {
+		return errors.New("error parsing total block count as big.Int")
 	}
 
+	projection.maybeGenesisBlockTime = &unixNanoTime
+	projection.maybeTotalBlockCount = totalBlockCount
+	return nil
+}
+
+// Rollback undoes the journaled changes of the stats accumulated over the blocks, then reloads the
+// stats kept in memory
+func (projection *ChainStats) Rollback(fromHeight int64, toHeight int64) error {
+	if _, err := projection.RollbackViews(projection.rdbConn, fromHeight, []string{}); err != nil {
+		return fmt.Errorf("error rolling back chain stats views: %w", err)
+	}
+	if err := projection.loadStats(); err != nil {
+		return fmt.Errorf("error reloading stats after rollback: %v", err)
+	}
 	return nil
 }
 
@@ -119,6 +150,9 @@ func (projection *ChainStats) HandleEvents(height int64, events []event_entity.E
 	}()
 
 	rdbTxHandle := rdbTx.ToHandle()
+	if err = projection.JournalViews(rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error journaling views: %v", err)
+	}
 	chainStatsView := view.NewChainStats(rdbTxHandle)
 
 	event := events[0]
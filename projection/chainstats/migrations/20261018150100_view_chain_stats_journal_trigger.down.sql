@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS view_chain_stats_journal ON view_chain_stats;
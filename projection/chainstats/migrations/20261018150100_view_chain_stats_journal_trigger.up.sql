@@ -0,0 +1,2 @@
+CREATE TRIGGER view_chain_stats_journal AFTER INSERT OR UPDATE OR DELETE ON view_chain_stats
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
@@ -15,7 +15,7 @@ import (
 
 type ChainStats struct {
 	rdbHandle  *rdb.Handle
-	astraCache *cache.AstraCache
+	astraCache cache.Cache
 }
 
 func NewChainStats(rdbHandle *rdb.Handle) *ChainStats {
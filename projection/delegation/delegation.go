@@ -0,0 +1,620 @@
+package delegation
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbparambase"
+	rdbparambase_types "github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbparambase/types"
+	rdbparambase_view "github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbparambase/view"
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbprojectionbase"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	event_entity "github.com/AstraProtocol/astra-indexing/entity/event"
+	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/external/tmcosmosutils"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/pg/migrationhelper"
+	"github.com/AstraProtocol/astra-indexing/projection/delegation/view"
+	"github.com/AstraProtocol/astra-indexing/usecase/coin"
+	event_usecase "github.com/AstraProtocol/astra-indexing/usecase/event"
+	usecase_model "github.com/AstraProtocol/astra-indexing/usecase/model"
+)
+
+var _ projection_entity.Projection = &Delegation{}
+var _ projection_entity.RebuildableProjection = &Delegation{}
+var _ projection_entity.RollbackableProjection = &Delegation{}
+
+var (
+	NewValidators            = view.NewValidatorsView
+	NewDelegations           = view.NewDelegationsView
+	NewUnbondingDelegations  = view.NewUnbondingDelegationsView
+	NewRedelegations         = view.NewRedelegationsView
+	NewDelegationEvents      = view.NewDelegationEventsView
+	NewSlashingParamChanges  = view.NewSlashingParamChangesView
+	NewSlashingParamsHistory = view.NewSlashingParamsHistoryView
+
+	JournalViews                 = (*Delegation).JournalViews
+	UpdateLastHandledEventHeight = (*Delegation).UpdateLastHandledEventHeight
+
+	ParamBaseHandleEvents = (*rdbparambase.Base).HandleEvents
+	ParamBaseGetView      = (*rdbparambase.Base).GetView
+)
+
+var (
+	UnbondingTimeParam = rdbparambase_types.ParamAccessor{
+		Module: "staking",
+		Key:    "unbonding_time",
+	}
+	SlashFractionDowntimeParam = rdbparambase_types.ParamAccessor{
+		Module: "slashing",
+		Key:    "slash_fraction_downtime",
+	}
+	SlashFractionDoubleSignParam = rdbparambase_types.ParamAccessor{
+		Module: "slashing",
+		Key:    "slash_fraction_double_sign",
+	}
+)
+
+// Param keys of the slashing subspace in param change proposals
+var slashFractionParamChangeKeys = map[string]rdbparambase_types.ParamAccessor{
+	"SlashFractionDowntime":   SlashFractionDowntimeParam,
+	"SlashFractionDoubleSign": SlashFractionDoubleSignParam,
+}
+
+const (
+	SLASH_REASON_MISSING_SIGNATURE = "missing_signature"
+	SLASH_REASON_DOUBLE_SIGN       = "double_sign"
+)
+
+// Delegation keeps the shares of every delegator on every validator, converting between tokens and shares the same
+// way as the staking module does, together with the pending unbonding delegations and redelegations and the
+// delegation history of the delegators.
+//
+// Slashes burn the slash fraction of the tokens worth the slashed power of the validator. The unbonding delegations
+// and redelegations from the validator since the infraction height are slashed first, lowering their balance and
+// burning the redelegated shares, and the remainder is burned from the validator, lowering the tokens its shares are
+// worth. The slash fraction is the one in effect at the infraction height, following the slashing param change
+// proposals passed since the genesis.
+type Delegation struct {
+	*rdbprojectionbase.Base
+	paramBase *rdbparambase.Base
+
+	rdbConn rdb.Conn
+	logger  applogger.Logger
+
+	conNodeAddressPrefix string
+
+	migrationHelper migrationhelper.MigrationHelper
+}
+
+func NewDelegation(
+	logger applogger.Logger,
+	rdbConn rdb.Conn,
+	conNodeAddressPrefix string,
+	migrationHelper migrationhelper.MigrationHelper,
+) *Delegation {
+	return &Delegation{
+		rdbprojectionbase.NewRDbBase(
+			rdbConn.ToHandle(),
+			"Delegation",
+		),
+		rdbparambase.NewBase(view.PARAMS_TABLE_NAME, []rdbparambase_types.ParamAccessor{
+			UnbondingTimeParam,
+			SlashFractionDowntimeParam,
+			SlashFractionDoubleSignParam,
+		}),
+
+		rdbConn,
+		logger,
+
+		conNodeAddressPrefix,
+
+		migrationHelper,
+	}
+}
+
+func (projection *Delegation) GetEventsToListen() []string {
+	return append(
+		[]string{
+			event_usecase.BLOCK_CREATED,
+			event_usecase.GENESIS_VALIDATOR_CREATED,
+			event_usecase.MSG_CREATE_VALIDATOR_CREATED,
+			event_usecase.MSG_DELEGATE_CREATED,
+			event_usecase.MSG_UNDELEGATE_CREATED,
+			event_usecase.MSG_BEGIN_REDELEGATE_CREATED,
+			event_usecase.VALIDATOR_SLASHED,
+			event_usecase.UNBONDING_COMPLETED,
+			event_usecase.MSG_SUBMIT_PARAM_CHANGE_PROPOSAL_CREATED,
+			event_usecase.PROPOSAL_ENDED,
+		},
+		projection.paramBase.GetEventsToListen()...,
+	)
+}
+
+func (projection *Delegation) OnInit() error {
+	if projection.migrationHelper != nil {
+		projection.migrationHelper.Migrate()
+	}
+
+	return nil
+}
+
+func (projection *Delegation) Reset(fromHeight int64) error {
+	return projection.ResetViews(projection.rdbConn, fromHeight, []string{
+		view.PARAMS_TABLE_NAME,
+		view.VALIDATORS_TABLE_NAME,
+		view.DELEGATIONS_TABLE_NAME,
+		view.UNBONDING_DELEGATIONS_TABLE_NAME,
+		view.REDELEGATIONS_TABLE_NAME,
+		view.DELEGATION_EVENTS_TABLE_NAME,
+		view.SLASHING_PARAM_CHANGES_TABLE_NAME,
+		view.SLASHING_PARAMS_HISTORY_TABLE_NAME,
+	})
+}
+
+// Rollback undoes the journaled changes of the validators, the delegations, the unbonding delegations and the
+// redelegations, whose shares and balances are accumulated over the blocks. The delegation events and the slash
+// fraction changes of the heights are removed.
+func (projection *Delegation) Rollback(fromHeight int64, toHeight int64) error {
+	if _, err := projection.RollbackViews(projection.rdbConn, fromHeight, []string{
+		view.DELEGATION_EVENTS_TABLE_NAME,
+		view.SLASHING_PARAMS_HISTORY_TABLE_NAME,
+	}); err != nil {
+		return fmt.Errorf("error rolling back delegation views: %w", err)
+	}
+	return nil
+}
+
+func (projection *Delegation) HandleEvents(height int64, events []event_entity.Event) error {
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	if err = JournalViews(projection, rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error journaling views: %v", err)
+	}
+
+	if err := ParamBaseHandleEvents(projection.paramBase, rdbTxHandle, projection.logger, events); err != nil {
+		return fmt.Errorf("error handling event in param base: %v", err)
+	}
+
+	unbondingDelegationsView := NewUnbondingDelegations(rdbTxHandle)
+	redelegationsView := NewRedelegations(rdbTxHandle)
+	ledger := &ledger{
+		height:               height,
+		validators:           NewValidators(rdbTxHandle),
+		delegations:          NewDelegations(rdbTxHandle),
+		unbondingDelegations: unbondingDelegationsView,
+		redelegations:        redelegationsView,
+	}
+	paramsView := ParamBaseGetView(projection.paramBase, rdbTxHandle)
+	slashingParamChangesView := NewSlashingParamChanges(rdbTxHandle)
+	slashingParamsHistoryView := NewSlashingParamsHistory(rdbTxHandle)
+
+	var maybeBlockTime *utctime.UTCTime
+	var blockEvidences []usecase_model.BlockEvidence
+	for _, event := range events {
+		if blockCreatedEvent, ok := event.(*event_usecase.BlockCreated); ok {
+			blockTime := blockCreatedEvent.Block.Time
+			maybeBlockTime = &blockTime
+			blockEvidences = blockCreatedEvent.Block.Evidences
+		}
+	}
+
+	delegationEventRows := make([]view.DelegationEventRow, 0)
+	for _, event := range events {
+		if createGenesisValidatorEvent, ok := event.(*event_usecase.CreateGenesisValidator); ok {
+			if err := projection.createValidator(
+				ledger,
+				createGenesisValidatorEvent.ValidatorAddress,
+				createGenesisValidatorEvent.TendermintPubkey,
+			); err != nil {
+				return err
+			}
+			if _, err := ledger.delegate(
+				createGenesisValidatorEvent.DelegatorAddress,
+				createGenesisValidatorEvent.ValidatorAddress,
+				createGenesisValidatorEvent.Amount.Amount,
+			); err != nil {
+				return fmt.Errorf("error handling genesis validator self-delegation: %v", err)
+			}
+
+			delegationEventRows = append(delegationEventRows, view.DelegationEventRow{
+				DelegatorAddress: createGenesisValidatorEvent.DelegatorAddress,
+				ValidatorAddress: createGenesisValidatorEvent.ValidatorAddress,
+				BlockHeight:      height,
+				MaybeBlockTime:   maybeBlockTime,
+				Type:             view.DELEGATION_EVENT_TYPE_DELEGATE,
+				Amount:           createGenesisValidatorEvent.Amount.Amount,
+			})
+
+		} else if msgCreateValidatorEvent, ok := event.(*event_usecase.MsgCreateValidator); ok {
+			if err := projection.createValidator(
+				ledger,
+				msgCreateValidatorEvent.ValidatorAddress,
+				msgCreateValidatorEvent.TendermintPubkey,
+			); err != nil {
+				return err
+			}
+			if _, err := ledger.delegate(
+				msgCreateValidatorEvent.DelegatorAddress,
+				msgCreateValidatorEvent.ValidatorAddress,
+				msgCreateValidatorEvent.Amount.Amount,
+			); err != nil {
+				return fmt.Errorf("error handling validator self-delegation: %v", err)
+			}
+
+			txHash := msgCreateValidatorEvent.TxHash()
+			delegationEventRows = append(delegationEventRows, view.DelegationEventRow{
+				DelegatorAddress:     msgCreateValidatorEvent.DelegatorAddress,
+				ValidatorAddress:     msgCreateValidatorEvent.ValidatorAddress,
+				BlockHeight:          height,
+				MaybeBlockTime:       maybeBlockTime,
+				MaybeTransactionHash: &txHash,
+				Type:                 view.DELEGATION_EVENT_TYPE_DELEGATE,
+				Amount:               msgCreateValidatorEvent.Amount.Amount,
+			})
+
+		} else if msgDelegateEvent, ok := event.(*event_usecase.MsgDelegate); ok {
+			if _, err := ledger.delegate(
+				msgDelegateEvent.DelegatorAddress,
+				msgDelegateEvent.ValidatorAddress,
+				msgDelegateEvent.Amount.Amount,
+			); err != nil {
+				return fmt.Errorf("error handling MsgDelegate: %v", err)
+			}
+
+			txHash := msgDelegateEvent.TxHash()
+			delegationEventRows = append(delegationEventRows, view.DelegationEventRow{
+				DelegatorAddress:     msgDelegateEvent.DelegatorAddress,
+				ValidatorAddress:     msgDelegateEvent.ValidatorAddress,
+				BlockHeight:          height,
+				MaybeBlockTime:       maybeBlockTime,
+				MaybeTransactionHash: &txHash,
+				Type:                 view.DELEGATION_EVENT_TYPE_DELEGATE,
+				Amount:               msgDelegateEvent.Amount.Amount,
+			})
+
+		} else if msgUndelegateEvent, ok := event.(*event_usecase.MsgUndelegate); ok {
+			returnAmount, err := ledger.unbond(
+				msgUndelegateEvent.DelegatorAddress,
+				msgUndelegateEvent.ValidatorAddress,
+				msgUndelegateEvent.Amount.Amount,
+			)
+			if err != nil {
+				return fmt.Errorf("error handling MsgUndelegate: %v", err)
+			}
+
+			completionTime, err := projection.completionTime(
+				paramsView, maybeBlockTime, msgUndelegateEvent.MaybeUnbondCompleteAt,
+			)
+			if err != nil {
+				return err
+			}
+			if err := unbondingDelegationsView.Insert(&view.UnbondingDelegationRow{
+				DelegatorAddress: msgUndelegateEvent.DelegatorAddress,
+				ValidatorAddress: msgUndelegateEvent.ValidatorAddress,
+				CreationHeight:   height,
+				CompletionTime:   completionTime,
+				InitialBalance:   returnAmount,
+				Balance:          returnAmount,
+			}); err != nil {
+				return fmt.Errorf("error inserting unbonding delegation: %v", err)
+			}
+
+			txHash := msgUndelegateEvent.TxHash()
+			delegationEventRows = append(delegationEventRows, view.DelegationEventRow{
+				DelegatorAddress:     msgUndelegateEvent.DelegatorAddress,
+				ValidatorAddress:     msgUndelegateEvent.ValidatorAddress,
+				BlockHeight:          height,
+				MaybeBlockTime:       maybeBlockTime,
+				MaybeTransactionHash: &txHash,
+				Type:                 view.DELEGATION_EVENT_TYPE_UNDELEGATE,
+				Amount:               returnAmount,
+				MaybeCompletionTime:  &completionTime,
+			})
+
+		} else if msgBeginRedelegateEvent, ok := event.(*event_usecase.MsgBeginRedelegate); ok {
+			returnAmount, err := ledger.unbond(
+				msgBeginRedelegateEvent.DelegatorAddress,
+				msgBeginRedelegateEvent.ValidatorSrcAddress,
+				msgBeginRedelegateEvent.Amount.Amount,
+			)
+			if err != nil {
+				return fmt.Errorf("error handling MsgBeginRedelegate: %v", err)
+			}
+			sharesDst, err := ledger.delegate(
+				msgBeginRedelegateEvent.DelegatorAddress,
+				msgBeginRedelegateEvent.ValidatorDstAddress,
+				returnAmount,
+			)
+			if err != nil {
+				return fmt.Errorf("error handling MsgBeginRedelegate: %v", err)
+			}
+
+			// The completion time is not in the message events, and the redelegations from a validator not bonded
+			// completing right away are kept until the unbonding time passes too
+			completionTime, err := projection.completionTime(paramsView, maybeBlockTime, nil)
+			if err != nil {
+				return err
+			}
+			if err := redelegationsView.Insert(&view.RedelegationRow{
+				DelegatorAddress:    msgBeginRedelegateEvent.DelegatorAddress,
+				ValidatorSrcAddress: msgBeginRedelegateEvent.ValidatorSrcAddress,
+				ValidatorDstAddress: msgBeginRedelegateEvent.ValidatorDstAddress,
+				CreationHeight:      height,
+				CompletionTime:      completionTime,
+				Balance:             returnAmount,
+				SharesDst:           sharesDst,
+			}); err != nil {
+				return fmt.Errorf("error inserting redelegation: %v", err)
+			}
+
+			txHash := msgBeginRedelegateEvent.TxHash()
+			validatorDstAddress := msgBeginRedelegateEvent.ValidatorDstAddress
+			delegationEventRows = append(delegationEventRows, view.DelegationEventRow{
+				DelegatorAddress:         msgBeginRedelegateEvent.DelegatorAddress,
+				ValidatorAddress:         msgBeginRedelegateEvent.ValidatorSrcAddress,
+				MaybeValidatorDstAddress: &validatorDstAddress,
+				BlockHeight:              height,
+				MaybeBlockTime:           maybeBlockTime,
+				MaybeTransactionHash:     &txHash,
+				Type:                     view.DELEGATION_EVENT_TYPE_REDELEGATE,
+				Amount:                   returnAmount,
+				MaybeCompletionTime:      &completionTime,
+			})
+
+		} else if validatorSlashedEvent, ok := event.(*event_usecase.ValidatorSlashed); ok {
+			if maybeBlockTime == nil {
+				return errors.New("error handling validator slash: missing block time")
+			}
+
+			var slashFractionParam rdbparambase_types.ParamAccessor
+			var infractionHeight int64
+			switch validatorSlashedEvent.Reason {
+			case SLASH_REASON_MISSING_SIGNATURE:
+				slashFractionParam = SlashFractionDowntimeParam
+				// The missed block is signed in the last commit of the begin blocker, and the distribution height
+				// is one before it
+				infractionHeight = height - 2
+			case SLASH_REASON_DOUBLE_SIGN:
+				slashFractionParam = SlashFractionDoubleSignParam
+				infractionHeight, err = projection.doubleSignInfractionHeight(
+					blockEvidences, validatorSlashedEvent.ConsensusNodeAddress,
+				)
+				if err != nil {
+					return fmt.Errorf("error handling validator slash: %v", err)
+				}
+			default:
+				return fmt.Errorf("error handling validator slash: unrecognized reason %s", validatorSlashedEvent.Reason)
+			}
+
+			slashFractionValue, err := slashingParamsHistoryView.FindValueAt(slashFractionParam.Key, infractionHeight)
+			if errors.Is(err, rdb.ErrNoRows) {
+				slashFractionValue, err = paramsView.FindBy(slashFractionParam)
+			}
+			if err != nil {
+				return fmt.Errorf("error retrieving %s param: %v", slashFractionParam.Key, err)
+			}
+			slashFraction, err := coin.NewDecFromStr(slashFractionValue)
+			if err != nil {
+				return fmt.Errorf("error parsing %s param: %v", slashFractionParam.Key, err)
+			}
+
+			slashedPower, ok := coin.NewIntFromString(validatorSlashedEvent.SlashedPower)
+			if !ok {
+				return fmt.Errorf("error parsing slashed power %s", validatorSlashedEvent.SlashedPower)
+			}
+
+			if err := ledger.slash(
+				validatorSlashedEvent.ConsensusNodeAddress,
+				infractionHeight,
+				slashedPower,
+				slashFraction,
+				*maybeBlockTime,
+			); err != nil {
+				return fmt.Errorf("error handling validator slash: %v", err)
+			}
+
+		} else if paramChangeProposalEvent, ok := event.(*event_usecase.MsgSubmitParamChangeProposal); ok {
+			if paramChangeProposalEvent.MaybeProposalId == nil {
+				continue
+			}
+
+			slashingParamChangeRows := make([]view.SlashingParamChangeRow, 0)
+			for _, change := range paramChangeProposalEvent.Content.Changes {
+				param, ok := slashFractionParamChangeKeys[change.Key]
+				if change.Subspace != "slashing" || !ok {
+					continue
+				}
+
+				var value string
+				if err := json.Unmarshal(change.Value, &value); err != nil {
+					return fmt.Errorf("error parsing slashing param change value: %v", err)
+				}
+
+				slashingParamChangeRows = append(slashingParamChangeRows, view.SlashingParamChangeRow{
+					ProposalId: *paramChangeProposalEvent.MaybeProposalId,
+					Key:        param.Key,
+					Value:      value,
+				})
+			}
+			if err := slashingParamChangesView.InsertAll(slashingParamChangeRows); err != nil {
+				return fmt.Errorf("error inserting slashing param changes: %v", err)
+			}
+
+		} else if proposalEndedEvent, ok := event.(*event_usecase.ProposalEnded); ok {
+			if proposalEndedEvent.Result == "proposal_passed" {
+				slashingParamChangeRows, err := slashingParamChangesView.ListByProposalId(proposalEndedEvent.ProposalId)
+				if err != nil {
+					return fmt.Errorf("error listing slashing param changes of proposal: %v", err)
+				}
+				slashingParamsHistoryRows := make([]view.SlashingParamsHistoryRow, 0, len(slashingParamChangeRows))
+				for _, slashingParamChangeRow := range slashingParamChangeRows {
+					slashingParamsHistoryRows = append(slashingParamsHistoryRows, view.SlashingParamsHistoryRow{
+						Key:         slashingParamChangeRow.Key,
+						BlockHeight: height,
+						Value:       slashingParamChangeRow.Value,
+					})
+				}
+				if err := slashingParamsHistoryView.InsertAll(slashingParamsHistoryRows); err != nil {
+					return fmt.Errorf("error inserting slashing params history: %v", err)
+				}
+			}
+			if err := slashingParamChangesView.DeleteByProposalId(proposalEndedEvent.ProposalId); err != nil {
+				return fmt.Errorf("error deleting slashing param changes of ended proposal: %v", err)
+			}
+
+		} else if unbondingCompletedEvent, ok := event.(*event_usecase.BondingCompleted); ok {
+			if maybeBlockTime == nil {
+				return errors.New("error completing unbonding delegations: missing block time")
+			}
+
+			unbondingDelegationRows, err := unbondingDelegationsView.CompleteMatured(
+				unbondingCompletedEvent.Delegator, unbondingCompletedEvent.Validator, *maybeBlockTime, height,
+			)
+			if err != nil {
+				return fmt.Errorf("error completing unbonding delegations: %v", err)
+			}
+			for _, unbondingDelegationRow := range unbondingDelegationRows {
+				delegationEventRows = append(delegationEventRows, view.DelegationEventRow{
+					DelegatorAddress: unbondingDelegationRow.DelegatorAddress,
+					ValidatorAddress: unbondingDelegationRow.ValidatorAddress,
+					BlockHeight:      height,
+					MaybeBlockTime:   maybeBlockTime,
+					Type:             view.DELEGATION_EVENT_TYPE_UNBONDING_COMPLETED,
+					Amount:           unbondingDelegationRow.Balance,
+				})
+			}
+		}
+	}
+
+	// Matured redelegations are completed in the end blocker
+	if maybeBlockTime != nil {
+		redelegationRows, err := redelegationsView.CompleteMatured(*maybeBlockTime, height)
+		if err != nil {
+			return fmt.Errorf("error completing redelegations: %v", err)
+		}
+		for _, redelegationRow := range redelegationRows {
+			validatorDstAddress := redelegationRow.ValidatorDstAddress
+			delegationEventRows = append(delegationEventRows, view.DelegationEventRow{
+				DelegatorAddress:         redelegationRow.DelegatorAddress,
+				ValidatorAddress:         redelegationRow.ValidatorSrcAddress,
+				MaybeValidatorDstAddress: &validatorDstAddress,
+				BlockHeight:              height,
+				MaybeBlockTime:           maybeBlockTime,
+				Type:                     view.DELEGATION_EVENT_TYPE_REDELEGATION_COMPLETED,
+				Amount:                   redelegationRow.Balance,
+			})
+		}
+	}
+
+	if err := NewDelegationEvents(rdbTxHandle).InsertAll(delegationEventRows); err != nil {
+		return fmt.Errorf("error inserting delegation events: %v", err)
+	}
+
+	if err := UpdateLastHandledEventHeight(projection, rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error updating last handled event height: %v", err)
+	}
+
+	if err := rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+
+	return nil
+}
+
+func (projection *Delegation) createValidator(
+	ledger *ledger,
+	operatorAddress string,
+	tendermintPubkey string,
+) error {
+	pubkey, err := base64.StdEncoding.DecodeString(tendermintPubkey)
+	if err != nil {
+		return fmt.Errorf("error base64 decoding Tendermint node pubkey: %v", err)
+	}
+	consensusNodeAddress, err := tmcosmosutils.ConsensusNodeAddressFromTmPubKey(
+		projection.conNodeAddressPrefix, pubkey,
+	)
+	if err != nil {
+		return fmt.Errorf("error converting Tendermint node pubkey to address: %v", err)
+	}
+
+	if err := ledger.validators.Upsert(&view.ValidatorRow{
+		OperatorAddress:      operatorAddress,
+		ConsensusNodeAddress: consensusNodeAddress,
+		Tokens:               coin.ZeroInt(),
+		DelegatorShares:      coin.ZeroDec(),
+	}); err != nil {
+		return fmt.Errorf("error inserting delegation validator: %v", err)
+	}
+
+	return nil
+}
+
+// doubleSignInfractionHeight returns the distribution height of the double sign evidence of the validator in the
+// block, one before the height of the conflicting votes
+func (projection *Delegation) doubleSignInfractionHeight(
+	blockEvidences []usecase_model.BlockEvidence,
+	consensusNodeAddress string,
+) (int64, error) {
+	for _, blockEvidence := range blockEvidences {
+		validatorAddress, err := hex.DecodeString(blockEvidence.Value.VoteA.ValidatorAddress)
+		if err != nil {
+			return 0, fmt.Errorf("error decoding evidence validator address: %v", err)
+		}
+		evidenceConsensusNodeAddress, err := tmcosmosutils.AccountAddressFromBytes(
+			projection.conNodeAddressPrefix, validatorAddress,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("error converting evidence validator address: %v", err)
+		}
+		if evidenceConsensusNodeAddress != consensusNodeAddress {
+			continue
+		}
+
+		evidenceHeight, err := strconv.ParseInt(blockEvidence.Value.VoteA.Height, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing evidence height: %v", err)
+		}
+		return evidenceHeight - 1, nil
+	}
+
+	return 0, fmt.Errorf("error finding double sign evidence of %s", consensusNodeAddress)
+}
+
+// completionTime returns the completion time reported by the message, or the block time plus the unbonding time
+func (projection *Delegation) completionTime(
+	paramsView rdbparambase_view.Params,
+	maybeBlockTime *utctime.UTCTime,
+	maybeCompletionTime *utctime.UTCTime,
+) (utctime.UTCTime, error) {
+	if maybeCompletionTime != nil {
+		return *maybeCompletionTime, nil
+	}
+	if maybeBlockTime == nil {
+		return utctime.UTCTime{}, errors.New("error calculating completion time: missing block time")
+	}
+
+	unbondingTime, err := paramsView.FindDurationBy(UnbondingTimeParam)
+	if err != nil {
+		return utctime.UTCTime{}, fmt.Errorf("error retrieving unbonding_time param: %v", err)
+	}
+
+	return maybeBlockTime.Add(unbondingTime), nil
+}
@@ -0,0 +1,737 @@
+package delegation_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	testify_mock "github.com/stretchr/testify/mock"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbparambase"
+	rdbparambase_view "github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbparambase/view"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb/test"
+	entity_event "github.com/AstraProtocol/astra-indexing/entity/event"
+	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
+	"github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/external/primptr"
+	"github.com/AstraProtocol/astra-indexing/external/tmcosmosutils"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/pg"
+	"github.com/AstraProtocol/astra-indexing/projection/delegation"
+	"github.com/AstraProtocol/astra-indexing/projection/delegation/view"
+	"github.com/AstraProtocol/astra-indexing/usecase/coin"
+	usecase_event "github.com/AstraProtocol/astra-indexing/usecase/event"
+	"github.com/AstraProtocol/astra-indexing/usecase/model"
+)
+
+const CON_NODE_ADDRESS_PREFIX = "astravalcons"
+
+func NewDelegationProjection(rdbConn rdb.Conn) *delegation.Delegation {
+	return delegation.NewDelegation(
+		nil,
+		rdbConn,
+		CON_NODE_ADDRESS_PREFIX,
+		nil,
+	)
+}
+
+func NewMockRDbConn() *test.MockRDbConn {
+	mock := test.NewMockRDbConn()
+	mock.On("ToHandle").Return(&rdb.Handle{
+		Runner:   mock,
+		TypeConv: &pg.PgxTypeConv{},
+		StmtBuilder: &rdb.StatementBuilder{
+			StatementBuilderType: sq.StatementBuilderType{},
+			PlaceholderFormat:    nil,
+		},
+	})
+
+	return mock
+}
+
+func NewMockRDbTx() *test.MockRDbTx {
+	mockTx := &test.MockRDbTx{}
+	mockTx.On("ToHandle").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+
+	return mockTx
+}
+
+func newMsgBase(msgName string, height int64) usecase_event.MsgBase {
+	return usecase_event.NewMsgBase(usecase_event.MsgBaseParams{
+		MsgName: msgName,
+		Version: 1,
+		MsgCommonParams: usecase_event.MsgCommonParams{
+			BlockHeight: height,
+			TxHash:      "TXHASH",
+			TxSuccess:   true,
+			MsgIndex:    0,
+		},
+	})
+}
+
+// sameJSON matches the arguments encoding to the same JSON as expected, such that the big numbers are compared by
+// their values
+func sameJSON(expected interface{}) interface{} {
+	expectedJSON, _ := json.Marshal(expected)
+	return testify_mock.MatchedBy(func(actual interface{}) bool {
+		actualJSON, _ := json.Marshal(actual)
+		return string(actualJSON) == string(expectedJSON)
+	})
+}
+
+func newValidatorRow(operatorAddress string, tokens int64, delegatorShares string) *view.ValidatorRow {
+	return &view.ValidatorRow{
+		OperatorAddress:      operatorAddress,
+		ConsensusNodeAddress: operatorAddress + "cons",
+		Tokens:               coin.NewInt(tokens),
+		DelegatorShares:      coin.MustNewDecFromStr(delegatorShares),
+	}
+}
+
+func newDelegationRow(delegatorAddress string, validatorAddress string, shares string) *view.DelegationRow {
+	return &view.DelegationRow{
+		DelegatorAddress:       delegatorAddress,
+		ValidatorAddress:       validatorAddress,
+		Shares:                 coin.MustNewDecFromStr(shares),
+		CreatedAtBlockHeight:   1,
+		LastUpdatedBlockHeight: 1,
+	}
+}
+
+// tokens returns the amount of whole tokens in the base denom
+func tokens(amount int64) coin.Int {
+	return coin.NewInt(amount).Mul(delegation.PowerReduction)
+}
+
+func newDoubleSignEvidence(height string, tendermintPubkey []byte) model.BlockEvidence {
+	var evidence model.BlockEvidence
+	evidence.Type = "tendermint/DuplicateVoteEvidence"
+	evidence.Value.VoteA.Height = height
+	evidence.Value.VoteA.ValidatorAddress = tmcosmosutils.TmAddressFromTmPubKey(tendermintPubkey)
+
+	return evidence
+}
+
+type mockViews struct {
+	validators            *view.MockValidatorsView
+	delegations           *view.MockDelegationsView
+	unbondingDelegations  *view.MockUnbondingDelegationsView
+	redelegations         *view.MockRedelegationsView
+	delegationEvents      *view.MockDelegationEventsView
+	slashingParamChanges  *view.MockSlashingParamChangesView
+	slashingParamsHistory *view.MockSlashingParamsHistoryView
+	params                *rdbparambase_view.MockParamsView
+}
+
+func (views *mockViews) mocks() []*testify_mock.Mock {
+	return []*testify_mock.Mock{
+		&views.validators.Mock,
+		&views.delegations.Mock,
+		&views.unbondingDelegations.Mock,
+		&views.redelegations.Mock,
+		&views.delegationEvents.Mock,
+		&views.slashingParamChanges.Mock,
+		&views.slashingParamsHistory.Mock,
+		&views.params.Mock,
+	}
+}
+
+func newMockViews() *mockViews {
+	views := &mockViews{
+		validators:            &view.MockValidatorsView{},
+		delegations:           &view.MockDelegationsView{},
+		unbondingDelegations:  &view.MockUnbondingDelegationsView{},
+		redelegations:         &view.MockRedelegationsView{},
+		delegationEvents:      &view.MockDelegationEventsView{},
+		slashingParamChanges:  &view.MockSlashingParamChangesView{},
+		slashingParamsHistory: &view.MockSlashingParamsHistoryView{},
+		params:                &rdbparambase_view.MockParamsView{},
+	}
+
+	delegation.NewValidators = func(_ *rdb.Handle) view.Validators {
+		return views.validators
+	}
+	delegation.NewDelegations = func(_ *rdb.Handle) view.Delegations {
+		return views.delegations
+	}
+	delegation.NewUnbondingDelegations = func(_ *rdb.Handle) view.UnbondingDelegations {
+		return views.unbondingDelegations
+	}
+	delegation.NewRedelegations = func(_ *rdb.Handle) view.Redelegations {
+		return views.redelegations
+	}
+	delegation.NewDelegationEvents = func(_ *rdb.Handle) view.DelegationEvents {
+		return views.delegationEvents
+	}
+	delegation.NewSlashingParamChanges = func(_ *rdb.Handle) view.SlashingParamChanges {
+		return views.slashingParamChanges
+	}
+	delegation.NewSlashingParamsHistory = func(_ *rdb.Handle) view.SlashingParamsHistory {
+		return views.slashingParamsHistory
+	}
+	delegation.ParamBaseHandleEvents = func(
+		_ *rdbparambase.Base, _ *rdb.Handle, _ logger.Logger, _ []entity_event.Event,
+	) error {
+		return nil
+	}
+	delegation.ParamBaseGetView = func(_ *rdbparambase.Base, _ *rdb.Handle) rdbparambase_view.Params {
+		return views.params
+	}
+	delegation.JournalViews = func(_ *delegation.Delegation, _ *rdb.Handle, _ int64) error {
+		return nil
+	}
+	delegation.UpdateLastHandledEventHeight = func(_ *delegation.Delegation, _ *rdb.Handle, _ int64) error {
+		return nil
+	}
+
+	return views
+}
+
+func TestDelegation_HandleEvents(t *testing.T) {
+	blockTime := utctime.FromUnixNano(1000)
+	unbondingTime := 21 * 24 * time.Hour
+	tendermintPubkey := make([]byte, 32)
+	consensusNodeAddress, err := tmcosmosutils.ConsensusNodeAddressFromTmPubKey(
+		CON_NODE_ADDRESS_PREFIX, tendermintPubkey,
+	)
+	assert.NoError(t, err)
+
+	newBlockCreated := func(height int64) *usecase_event.BlockCreated {
+		return &usecase_event.BlockCreated{
+			Block: &model.Block{
+				Height: height,
+				Time:   blockTime,
+			},
+		}
+	}
+
+	testCases := []struct {
+		Name     string
+		Height   int64
+		Events   []entity_event.Event
+		MockFunc func() []*testify_mock.Mock
+	}{
+		{
+			Name:   "HandleGenesisValidatorSelfDelegation",
+			Height: 0,
+			Events: []entity_event.Event{
+				&usecase_event.CreateGenesisValidator{
+					DelegatorAddress: "delegator",
+					ValidatorAddress: "validator",
+					TendermintPubkey: base64.StdEncoding.EncodeToString(tendermintPubkey),
+					Amount:           coin.MustNewCoin("aastra", coin.NewInt(100)),
+				},
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				views := newMockViews()
+
+				views.validators.On("Upsert", sameJSON(&view.ValidatorRow{
+					OperatorAddress:      "validator",
+					ConsensusNodeAddress: consensusNodeAddress,
+					Tokens:               coin.ZeroInt(),
+					DelegatorShares:      coin.ZeroDec(),
+				})).Return(nil).Once()
+				views.validators.On("FindBy", view.ValidatorIdentity{
+					MaybeOperatorAddress: primptr.String("validator"),
+				}).Return(&view.ValidatorRow{
+					OperatorAddress:      "validator",
+					ConsensusNodeAddress: consensusNodeAddress,
+					Tokens:               coin.ZeroInt(),
+					DelegatorShares:      coin.ZeroDec(),
+				}, nil)
+				views.delegations.On("FindBy", "delegator", "validator").Return(nil, rdb.ErrNoRows)
+
+				views.validators.On("Upsert", sameJSON(&view.ValidatorRow{
+					OperatorAddress:      "validator",
+					ConsensusNodeAddress: consensusNodeAddress,
+					Tokens:               coin.NewInt(100),
+					DelegatorShares:      coin.NewDec(100),
+				})).Return(nil).Once()
+				views.delegations.On("Upsert", sameJSON(&view.DelegationRow{
+					DelegatorAddress:       "delegator",
+					ValidatorAddress:       "validator",
+					Shares:                 coin.NewDec(100),
+					CreatedAtBlockHeight:   0,
+					LastUpdatedBlockHeight: 0,
+				})).Return(nil)
+				views.delegationEvents.On("InsertAll", sameJSON([]view.DelegationEventRow{{
+					DelegatorAddress: "delegator",
+					ValidatorAddress: "validator",
+					BlockHeight:      0,
+					Type:             view.DELEGATION_EVENT_TYPE_DELEGATE,
+					Amount:           coin.NewInt(100),
+				}})).Return(nil)
+
+				return views.mocks()
+			},
+		},
+		{
+			Name:   "HandleMsgDelegateOnSlashedValidator",
+			Height: 10,
+			Events: []entity_event.Event{
+				newBlockCreated(10),
+				&usecase_event.MsgDelegate{
+					MsgBase:          newMsgBase(usecase_event.MSG_DELEGATE, 10),
+					DelegatorAddress: "delegator",
+					ValidatorAddress: "validator",
+					Amount:           coin.MustNewCoin("aastra", coin.NewInt(100)),
+				},
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				views := newMockViews()
+
+				views.validators.On("FindBy", view.ValidatorIdentity{
+					MaybeOperatorAddress: primptr.String("validator"),
+				}).Return(newValidatorRow("validator", 1000, "2000"), nil)
+				views.delegations.On("FindBy", "delegator", "validator").Return(
+					newDelegationRow("delegator", "validator", "50"), nil,
+				)
+
+				// Shares are issued at 2 shares per token after the slash
+				views.validators.On("Upsert", sameJSON(newValidatorRow("validator", 1100, "2200"))).Return(nil)
+				updatedDelegation := newDelegationRow("delegator", "validator", "250")
+				updatedDelegation.LastUpdatedBlockHeight = 10
+				views.delegations.On("Upsert", sameJSON(updatedDelegation)).Return(nil)
+				views.redelegations.On("CompleteMatured", blockTime, int64(10)).Return([]view.RedelegationRow{}, nil)
+				views.delegationEvents.On("InsertAll", sameJSON([]view.DelegationEventRow{{
+					DelegatorAddress:     "delegator",
+					ValidatorAddress:     "validator",
+					BlockHeight:          10,
+					MaybeBlockTime:       &blockTime,
+					MaybeTransactionHash: primptr.String("TXHASH"),
+					Type:                 view.DELEGATION_EVENT_TYPE_DELEGATE,
+					Amount:               coin.NewInt(100),
+				}})).Return(nil)
+
+				return views.mocks()
+			},
+		},
+		{
+			Name:   "HandleMsgUndelegateOfAllTheShares",
+			Height: 10,
+			Events: []entity_event.Event{
+				newBlockCreated(10),
+				&usecase_event.MsgUndelegate{
+					MsgBase:               newMsgBase(usecase_event.MSG_UNDELEGATE, 10),
+					DelegatorAddress:      "delegator",
+					ValidatorAddress:      "validator",
+					Amount:                coin.MustNewCoin("aastra", coin.NewInt(450)),
+					MaybeUnbondCompleteAt: &blockTime,
+				},
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				views := newMockViews()
+
+				views.validators.On("FindBy", view.ValidatorIdentity{
+					MaybeOperatorAddress: primptr.String("validator"),
+				}).Return(newValidatorRow("validator", 900, "1000"), nil)
+				views.delegations.On("FindBy", "delegator", "validator").Return(
+					newDelegationRow("delegator", "validator", "500"), nil,
+				)
+
+				views.delegations.On("Delete", "delegator", "validator").Return(nil)
+				views.validators.On("Upsert", sameJSON(newValidatorRow("validator", 450, "500"))).Return(nil)
+				views.unbondingDelegations.On("Insert", sameJSON(&view.UnbondingDelegationRow{
+					DelegatorAddress: "delegator",
+					ValidatorAddress: "validator",
+					CreationHeight:   10,
+					CompletionTime:   blockTime,
+					InitialBalance:   coin.NewInt(450),
+					Balance:          coin.NewInt(450),
+				})).Return(nil)
+				views.redelegations.On("CompleteMatured", blockTime, int64(10)).Return([]view.RedelegationRow{}, nil)
+				views.delegationEvents.On("InsertAll", sameJSON([]view.DelegationEventRow{{
+					DelegatorAddress:     "delegator",
+					ValidatorAddress:     "validator",
+					BlockHeight:          10,
+					MaybeBlockTime:       &blockTime,
+					MaybeTransactionHash: primptr.String("TXHASH"),
+					Type:                 view.DELEGATION_EVENT_TYPE_UNDELEGATE,
+					Amount:               coin.NewInt(450),
+					MaybeCompletionTime:  &blockTime,
+				}})).Return(nil)
+
+				return views.mocks()
+			},
+		},
+		{
+			Name:   "HandleMsgBeginRedelegateAndCompleteMaturedRedelegations",
+			Height: 10,
+			Events: []entity_event.Event{
+				newBlockCreated(10),
+				&usecase_event.MsgBeginRedelegate{
+					MsgBase:             newMsgBase(usecase_event.MSG_BEGIN_REDELEGATE, 10),
+					DelegatorAddress:    "delegator",
+					ValidatorSrcAddress: "src",
+					ValidatorDstAddress: "dst",
+					Amount:              coin.MustNewCoin("aastra", coin.NewInt(90)),
+				},
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				views := newMockViews()
+				views.params.On("FindDurationBy", delegation.UnbondingTimeParam).Return(unbondingTime, nil)
+
+				views.validators.On("FindBy", view.ValidatorIdentity{
+					MaybeOperatorAddress: primptr.String("src"),
+				}).Return(newValidatorRow("src", 900, "1000"), nil)
+				views.delegations.On("FindBy", "delegator", "src").Return(
+					newDelegationRow("delegator", "src", "500"), nil,
+				)
+				updatedSrcDelegation := newDelegationRow("delegator", "src", "400")
+				updatedSrcDelegation.LastUpdatedBlockHeight = 10
+				views.delegations.On("Upsert", sameJSON(updatedSrcDelegation)).Return(nil)
+				views.validators.On("Upsert", sameJSON(newValidatorRow("src", 810, "900"))).Return(nil)
+
+				views.validators.On("FindBy", view.ValidatorIdentity{
+					MaybeOperatorAddress: primptr.String("dst"),
+				}).Return(newValidatorRow("dst", 100, "100"), nil)
+				views.delegations.On("FindBy", "delegator", "dst").Return(nil, rdb.ErrNoRows)
+				views.validators.On("Upsert", sameJSON(newValidatorRow("dst", 190, "190"))).Return(nil)
+				views.delegations.On("Upsert", sameJSON(&view.DelegationRow{
+					DelegatorAddress:       "delegator",
+					ValidatorAddress:       "dst",
+					Shares:                 coin.NewDec(90),
+					CreatedAtBlockHeight:   10,
+					LastUpdatedBlockHeight: 10,
+				})).Return(nil)
+
+				completionTime := blockTime.Add(unbondingTime)
+				views.redelegations.On("Insert", sameJSON(&view.RedelegationRow{
+					DelegatorAddress:    "delegator",
+					ValidatorSrcAddress: "src",
+					ValidatorDstAddress: "dst",
+					CreationHeight:      10,
+					CompletionTime:      completionTime,
+					Balance:             coin.NewInt(90),
+					SharesDst:           coin.NewDec(90),
+				})).Return(nil)
+				views.redelegations.On("CompleteMatured", blockTime, int64(10)).Return([]view.RedelegationRow{{
+					DelegatorAddress:     "other",
+					ValidatorSrcAddress:  "src",
+					ValidatorDstAddress:  "dst",
+					CreationHeight:       1,
+					CompletionTime:       blockTime,
+					Balance:              coin.NewInt(30),
+					SharesDst:            coin.NewDec(30),
+					MaybeCompletedHeight: primptr.Int64(10),
+				}}, nil)
+				views.delegationEvents.On("InsertAll", sameJSON([]view.DelegationEventRow{
+					{
+						DelegatorAddress:         "delegator",
+						ValidatorAddress:         "src",
+						MaybeValidatorDstAddress: primptr.String("dst"),
+						BlockHeight:              10,
+						MaybeBlockTime:           &blockTime,
+						MaybeTransactionHash:     primptr.String("TXHASH"),
+						Type:                     view.DELEGATION_EVENT_TYPE_REDELEGATE,
+						Amount:                   coin.NewInt(90),
+						MaybeCompletionTime:      &completionTime,
+					},
+					{
+						DelegatorAddress:         "other",
+						ValidatorAddress:         "src",
+						MaybeValidatorDstAddress: primptr.String("dst"),
+						BlockHeight:              10,
+						MaybeBlockTime:           &blockTime,
+						Type:                     view.DELEGATION_EVENT_TYPE_REDELEGATION_COMPLETED,
+						Amount:                   coin.NewInt(30),
+					},
+				})).Return(nil)
+
+				return views.mocks()
+			},
+		},
+		{
+			Name:   "HandleDoubleSignSlashOfUnbondingDelegationsAndRedelegations",
+			Height: 10,
+			Events: []entity_event.Event{
+				&usecase_event.BlockCreated{
+					Block: &model.Block{
+						Height:    10,
+						Time:      blockTime,
+						Evidences: []model.BlockEvidence{newDoubleSignEvidence("8", tendermintPubkey)},
+					},
+				},
+				usecase_event.NewValidatorSlashed(10, model.SlashValidatorParams{
+					ConsensusNodeAddress: consensusNodeAddress,
+					SlashedPower:         "10",
+					Reason:               delegation.SLASH_REASON_DOUBLE_SIGN,
+				}),
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				views := newMockViews()
+				views.slashingParamsHistory.On("FindValueAt", "slash_fraction_double_sign", int64(7)).Return("", rdb.ErrNoRows)
+				views.params.On("FindBy", delegation.SlashFractionDoubleSignParam).Return("0.050000000000000000", nil)
+
+				validator := newValidatorRow("validator", 0, "0")
+				validator.ConsensusNodeAddress = consensusNodeAddress
+				validator.Tokens = tokens(10)
+				validator.DelegatorShares = tokens(10).ToDec()
+				views.validators.On("FindBy", view.ValidatorIdentity{
+					MaybeConsensusNodeAddress: &consensusNodeAddress,
+				}).Return(validator, nil)
+
+				// The infraction is one block before the height of the conflicting votes
+				views.unbondingDelegations.On("ListSlashable", "validator", int64(7), blockTime).Return(
+					[]view.UnbondingDelegationRow{{
+						Id:               1,
+						DelegatorAddress: "delegator",
+						ValidatorAddress: "validator",
+						CreationHeight:   8,
+						CompletionTime:   blockTime.Add(unbondingTime),
+						InitialBalance:   tokens(2),
+						Balance:          tokens(2),
+					}}, nil,
+				)
+				views.unbondingDelegations.On("UpdateBalance", int64(1), sameJSON(tokens(2).Sub(tokens(1).QuoRaw(10)))).
+					Return(nil)
+
+				views.redelegations.On("ListSlashable", "validator", int64(7), blockTime).Return(
+					[]view.RedelegationRow{{
+						Id:                  1,
+						DelegatorAddress:    "delegator",
+						ValidatorSrcAddress: "validator",
+						ValidatorDstAddress: "dst",
+						CreationHeight:      9,
+						CompletionTime:      blockTime.Add(unbondingTime),
+						Balance:             tokens(4),
+						SharesDst:           tokens(4).ToDec(),
+					}}, nil,
+				)
+				views.delegations.On("FindBy", "delegator", "dst").Return(&view.DelegationRow{
+					DelegatorAddress:       "delegator",
+					ValidatorAddress:       "dst",
+					Shares:                 tokens(4).ToDec(),
+					CreatedAtBlockHeight:   9,
+					LastUpdatedBlockHeight: 9,
+				}, nil)
+				views.delegations.On("Upsert", sameJSON(&view.DelegationRow{
+					DelegatorAddress:       "delegator",
+					ValidatorAddress:       "dst",
+					Shares:                 tokens(4).Sub(tokens(1).QuoRaw(5)).ToDec(),
+					CreatedAtBlockHeight:   9,
+					LastUpdatedBlockHeight: 10,
+				})).Return(nil)
+				dstValidator := newValidatorRow("dst", 0, "0")
+				dstValidator.Tokens = tokens(8)
+				dstValidator.DelegatorShares = tokens(8).ToDec()
+				views.validators.On("FindBy", view.ValidatorIdentity{
+					MaybeOperatorAddress: primptr.String("dst"),
+				}).Return(dstValidator, nil)
+				slashedDstValidator := newValidatorRow("dst", 0, "0")
+				slashedDstValidator.Tokens = tokens(8).Sub(tokens(1).QuoRaw(5))
+				slashedDstValidator.DelegatorShares = slashedDstValidator.Tokens.ToDec()
+				views.validators.On("Upsert", sameJSON(slashedDstValidator)).Return(nil)
+
+				// 0.2 of the 0.05 * 10 tokens slashed remain after 0.1 from the unbonding delegation and 0.2 from
+				// the redelegation
+				slashedValidator := newValidatorRow("validator", 0, "0")
+				slashedValidator.ConsensusNodeAddress = consensusNodeAddress
+				slashedValidator.Tokens = tokens(10).Sub(tokens(1).QuoRaw(5))
+				slashedValidator.DelegatorShares = tokens(10).ToDec()
+				views.validators.On("Upsert", sameJSON(slashedValidator)).Return(nil)
+
+				views.redelegations.On("CompleteMatured", blockTime, int64(10)).Return([]view.RedelegationRow{}, nil)
+				views.delegationEvents.On("InsertAll", []view.DelegationEventRow{}).Return(nil)
+
+				return views.mocks()
+			},
+		},
+		{
+			Name:   "HandleDowntimeSlashBurningAtMostTheValidatorTokens",
+			Height: 10,
+			Events: []entity_event.Event{
+				newBlockCreated(10),
+				usecase_event.NewValidatorSlashed(10, model.SlashValidatorParams{
+					ConsensusNodeAddress: "validatorcons",
+					SlashedPower:         "1",
+					Reason:               delegation.SLASH_REASON_MISSING_SIGNATURE,
+				}),
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				views := newMockViews()
+				views.slashingParamsHistory.On("FindValueAt", "slash_fraction_downtime", int64(8)).Return("", rdb.ErrNoRows)
+				views.params.On("FindBy", delegation.SlashFractionDowntimeParam).Return("0.500000000000000000", nil)
+
+				views.validators.On("FindBy", view.ValidatorIdentity{
+					MaybeConsensusNodeAddress: primptr.String("validatorcons"),
+				}).Return(newValidatorRow("validator", 1000, "1000"), nil)
+				views.unbondingDelegations.On("ListSlashable", "validator", int64(8), blockTime).Return(
+					[]view.UnbondingDelegationRow{}, nil,
+				)
+				views.redelegations.On("ListSlashable", "validator", int64(8), blockTime).Return(
+					[]view.RedelegationRow{}, nil,
+				)
+				views.validators.On("Upsert", sameJSON(newValidatorRow("validator", 0, "1000"))).Return(nil)
+				views.redelegations.On("CompleteMatured", blockTime, int64(10)).Return([]view.RedelegationRow{}, nil)
+				views.delegationEvents.On("InsertAll", []view.DelegationEventRow{}).Return(nil)
+
+				return views.mocks()
+			},
+		},
+		{
+			Name:   "HandleDowntimeSlashWithTheSlashFractionChangedBeforeTheInfraction",
+			Height: 10,
+			Events: []entity_event.Event{
+				newBlockCreated(10),
+				usecase_event.NewValidatorSlashed(10, model.SlashValidatorParams{
+					ConsensusNodeAddress: "validatorcons",
+					SlashedPower:         "10",
+					Reason:               delegation.SLASH_REASON_MISSING_SIGNATURE,
+				}),
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				views := newMockViews()
+				views.slashingParamsHistory.On("FindValueAt", "slash_fraction_downtime", int64(8)).
+					Return("0.100000000000000000", nil)
+
+				views.validators.On("FindBy", view.ValidatorIdentity{
+					MaybeConsensusNodeAddress: primptr.String("validatorcons"),
+				}).Return(&view.ValidatorRow{
+					OperatorAddress: "validator",
+					Tokens:          tokens(10),
+					DelegatorShares: tokens(10).ToDec(),
+				}, nil)
+				views.unbondingDelegations.On("ListSlashable", "validator", int64(8), blockTime).Return(
+					[]view.UnbondingDelegationRow{}, nil,
+				)
+				views.redelegations.On("ListSlashable", "validator", int64(8), blockTime).Return(
+					[]view.RedelegationRow{}, nil,
+				)
+				// 0.1 of the 10 tokens slashed instead of the 0.5 of the genesis params
+				views.validators.On("Upsert", sameJSON(&view.ValidatorRow{
+					OperatorAddress: "validator",
+					Tokens:          tokens(9),
+					DelegatorShares: tokens(10).ToDec(),
+				})).Return(nil)
+				views.redelegations.On("CompleteMatured", blockTime, int64(10)).Return([]view.RedelegationRow{}, nil)
+				views.delegationEvents.On("InsertAll", []view.DelegationEventRow{}).Return(nil)
+
+				return views.mocks()
+			},
+		},
+		{
+			Name:   "HandleSlashFractionParamChangeProposals",
+			Height: 10,
+			Events: []entity_event.Event{
+				newBlockCreated(10),
+				&usecase_event.MsgSubmitParamChangeProposal{
+					MsgBase: newMsgBase(usecase_event.MSG_SUBMIT_PARAM_CHANGE_PROPOSAL, 10),
+					MsgSubmitParamChangeProposalParams: model.MsgSubmitParamChangeProposalParams{
+						MaybeProposalId: primptr.String("2"),
+						Content: model.MsgSubmitParamChangeProposalContent{
+							Changes: []model.MsgSubmitParamChangeProposalChange{{
+								Subspace: "slashing",
+								Key:      "SlashFractionDowntime",
+								Value:    json.RawMessage(`"0.010000000000000000"`),
+							}, {
+								Subspace: "slashing",
+								Key:      "SignedBlocksWindow",
+								Value:    json.RawMessage(`"200"`),
+							}},
+						},
+					},
+				},
+				usecase_event.NewProposalEnded(10, "1", "proposal_passed"),
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				views := newMockViews()
+				views.slashingParamChanges.On("InsertAll", []view.SlashingParamChangeRow{{
+					ProposalId: "2",
+					Key:        "slash_fraction_downtime",
+					Value:      "0.010000000000000000",
+				}}).Return(nil)
+				views.slashingParamChanges.On("ListByProposalId", "1").Return([]view.SlashingParamChangeRow{{
+					ProposalId: "1",
+					Key:        "slash_fraction_double_sign",
+					Value:      "0.100000000000000000",
+				}}, nil)
+				views.slashingParamsHistory.On("InsertAll", []view.SlashingParamsHistoryRow{{
+					Key:         "slash_fraction_double_sign",
+					BlockHeight: 10,
+					Value:       "0.100000000000000000",
+				}}).Return(nil)
+				views.slashingParamChanges.On("DeleteByProposalId", "1").Return(nil)
+
+				views.redelegations.On("CompleteMatured", blockTime, int64(10)).Return([]view.RedelegationRow{}, nil)
+				views.delegationEvents.On("InsertAll", []view.DelegationEventRow{}).Return(nil)
+
+				return views.mocks()
+			},
+		},
+		{
+			Name:   "HandleUnbondingCompleted",
+			Height: 10,
+			Events: []entity_event.Event{
+				newBlockCreated(10),
+				usecase_event.NewUnbondingCompleted(10, model.CompleteBondingParams{
+					Delegator: "delegator",
+					Validator: "validator",
+					Amount:    coin.MustNewCoins(coin.MustNewCoin("aastra", coin.NewInt(450))),
+				}),
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				views := newMockViews()
+
+				views.unbondingDelegations.On(
+					"CompleteMatured", "delegator", "validator", blockTime, int64(10),
+				).Return([]view.UnbondingDelegationRow{{
+					DelegatorAddress:     "delegator",
+					ValidatorAddress:     "validator",
+					CreationHeight:       1,
+					CompletionTime:       blockTime,
+					Balance:              coin.NewInt(450),
+					MaybeCompletedHeight: primptr.Int64(10),
+				}}, nil)
+				views.redelegations.On("CompleteMatured", blockTime, int64(10)).Return([]view.RedelegationRow{}, nil)
+				views.delegationEvents.On("InsertAll", sameJSON([]view.DelegationEventRow{{
+					DelegatorAddress: "delegator",
+					ValidatorAddress: "validator",
+					BlockHeight:      10,
+					MaybeBlockTime:   &blockTime,
+					Type:             view.DELEGATION_EVENT_TYPE_UNBONDING_COMPLETED,
+					Amount:           coin.NewInt(450),
+				}})).Return(nil)
+
+				return views.mocks()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		mockRDbConn := NewMockRDbConn()
+		mockTx := NewMockRDbTx()
+		mockRDbConn.On("Begin").Return(mockTx, nil)
+
+		mocks := tc.MockFunc()
+		mocks = append(mocks, &mockRDbConn.Mock)
+		mocks = append(mocks, &mockTx.Mock)
+
+		projection := NewDelegationProjection(mockRDbConn)
+		err := projection.HandleEvents(tc.Height, tc.Events)
+		assert.NoError(t, err)
+
+		for _, m := range mocks {
+			m.AssertExpectations(t)
+		}
+
+		fmt.Println(tc.Name, "Passed")
+	}
+}
+
+func TestDelegation_ResetFromHeight(t *testing.T) {
+	mockRDbConn := NewMockRDbConn()
+
+	projection := NewDelegationProjection(mockRDbConn)
+	err := projection.Reset(10)
+	assert.ErrorIs(t, err, projection_entity.ErrProjectionNotRebuildableFromHeight)
+
+	mockRDbConn.AssertNotCalled(t, "Begin")
+}
@@ -0,0 +1,244 @@
+package delegation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/projection/delegation/view"
+	"github.com/AstraProtocol/astra-indexing/usecase/coin"
+)
+
+// PowerReduction is the tokens worth one unit of consensus power, 10^18 for the 18 decimals of the staking denom
+var PowerReduction = coin.NewIntWithDecimal(1, 18)
+
+// ledger moves tokens and shares between the delegators and the validators the same way as the staking keeper does
+type ledger struct {
+	height int64
+
+	validators           view.Validators
+	delegations          view.Delegations
+	unbondingDelegations view.UnbondingDelegations
+	redelegations        view.Redelegations
+}
+
+// delegate adds the tokens to the validator and returns the shares issued to the delegator
+func (ledger *ledger) delegate(delegatorAddress string, validatorAddress string, amount coin.Int) (coin.Dec, error) {
+	validator, err := ledger.validators.FindBy(view.ValidatorIdentity{
+		MaybeOperatorAddress: &validatorAddress,
+	})
+	if err != nil {
+		return coin.Dec{}, fmt.Errorf("error getting validator %s: %v", validatorAddress, err)
+	}
+
+	delegation, err := ledger.delegations.FindBy(delegatorAddress, validatorAddress)
+	if err != nil {
+		if !errors.Is(err, rdb.ErrNoRows) {
+			return coin.Dec{}, fmt.Errorf("error getting delegation: %v", err)
+		}
+		delegation = &view.DelegationRow{
+			DelegatorAddress:     delegatorAddress,
+			ValidatorAddress:     validatorAddress,
+			Shares:               coin.ZeroDec(),
+			CreatedAtBlockHeight: ledger.height,
+		}
+	}
+
+	issuedShares := addTokensFromDel(validator, amount)
+	delegation.Shares = delegation.Shares.Add(issuedShares)
+	delegation.LastUpdatedBlockHeight = ledger.height
+
+	if err := ledger.validators.Upsert(validator); err != nil {
+		return coin.Dec{}, fmt.Errorf("error updating validator %s: %v", validatorAddress, err)
+	}
+	if err := ledger.delegations.Upsert(delegation); err != nil {
+		return coin.Dec{}, fmt.Errorf("error updating delegation: %v", err)
+	}
+
+	return issuedShares, nil
+}
+
+// unbond removes the shares worth the amount from the delegation and returns the tokens they were worth
+func (ledger *ledger) unbond(delegatorAddress string, validatorAddress string, amount coin.Int) (coin.Int, error) {
+	validator, err := ledger.validators.FindBy(view.ValidatorIdentity{
+		MaybeOperatorAddress: &validatorAddress,
+	})
+	if err != nil {
+		return coin.Int{}, fmt.Errorf("error getting validator %s: %v", validatorAddress, err)
+	}
+	delegation, err := ledger.delegations.FindBy(delegatorAddress, validatorAddress)
+	if err != nil {
+		return coin.Int{}, fmt.Errorf("error getting delegation of %s on %s: %v", delegatorAddress, validatorAddress, err)
+	}
+
+	shares := sharesFromTokens(validator, amount)
+	if shares.GT(delegation.Shares) {
+		shares = delegation.Shares
+	}
+
+	return ledger.removeShares(validator, delegation, shares)
+}
+
+// removeShares removes the shares from the delegation and the validator and returns the tokens they were worth
+func (ledger *ledger) removeShares(
+	validator *view.ValidatorRow,
+	delegation *view.DelegationRow,
+	shares coin.Dec,
+) (coin.Int, error) {
+	delegation.Shares = delegation.Shares.Sub(shares)
+	delegation.LastUpdatedBlockHeight = ledger.height
+	if delegation.Shares.IsZero() {
+		if err := ledger.delegations.Delete(delegation.DelegatorAddress, delegation.ValidatorAddress); err != nil {
+			return coin.Int{}, fmt.Errorf("error deleting delegation: %v", err)
+		}
+	} else if err := ledger.delegations.Upsert(delegation); err != nil {
+		return coin.Int{}, fmt.Errorf("error updating delegation: %v", err)
+	}
+
+	returnAmount := removeDelShares(validator, shares)
+	if err := ledger.validators.Upsert(validator); err != nil {
+		return coin.Int{}, fmt.Errorf("error updating validator %s: %v", validator.OperatorAddress, err)
+	}
+
+	return returnAmount, nil
+}
+
+// slash burns the fraction of the tokens the validator had at the infraction height, worth its slashed power. The
+// unbonding delegations and redelegations from the validator since the infraction height are slashed first and the
+// remainder is burned from the validator.
+func (ledger *ledger) slash(
+	consensusNodeAddress string,
+	infractionHeight int64,
+	power coin.Int,
+	slashFraction coin.Dec,
+	blockTime utctime.UTCTime,
+) error {
+	validator, err := ledger.validators.FindBy(view.ValidatorIdentity{
+		MaybeConsensusNodeAddress: &consensusNodeAddress,
+	})
+	if err != nil {
+		return fmt.Errorf("error getting validator %s: %v", consensusNodeAddress, err)
+	}
+
+	amount := power.Mul(PowerReduction)
+	remainingSlashAmount := amount.ToDec().Mul(slashFraction).TruncateInt()
+
+	if infractionHeight < ledger.height {
+		unbondingDelegationRows, err := ledger.unbondingDelegations.ListSlashable(
+			validator.OperatorAddress, infractionHeight, blockTime,
+		)
+		if err != nil {
+			return fmt.Errorf("error getting unbonding delegations from %s: %v", validator.OperatorAddress, err)
+		}
+		for _, unbondingDelegationRow := range unbondingDelegationRows {
+			slashAmount := slashFraction.MulInt(unbondingDelegationRow.InitialBalance).TruncateInt()
+			remainingSlashAmount = remainingSlashAmount.Sub(slashAmount)
+
+			burnAmount := coin.MinInt(slashAmount, unbondingDelegationRow.Balance)
+			if burnAmount.IsZero() {
+				continue
+			}
+			if err := ledger.unbondingDelegations.UpdateBalance(
+				unbondingDelegationRow.Id, unbondingDelegationRow.Balance.Sub(burnAmount),
+			); err != nil {
+				return fmt.Errorf("error slashing unbonding delegation: %v", err)
+			}
+		}
+
+		redelegationRows, err := ledger.redelegations.ListSlashable(
+			validator.OperatorAddress, infractionHeight, blockTime,
+		)
+		if err != nil {
+			return fmt.Errorf("error getting redelegations from %s: %v", validator.OperatorAddress, err)
+		}
+		for _, redelegationRow := range redelegationRows {
+			slashAmount := slashFraction.MulInt(redelegationRow.Balance).TruncateInt()
+			remainingSlashAmount = remainingSlashAmount.Sub(slashAmount)
+
+			if err := ledger.slashRedelegation(redelegationRow, slashFraction); err != nil {
+				return fmt.Errorf("error slashing redelegation: %v", err)
+			}
+		}
+	}
+
+	tokensToBurn := coin.MaxInt(coin.MinInt(remainingSlashAmount, validator.Tokens), coin.ZeroInt())
+	validator.Tokens = validator.Tokens.Sub(tokensToBurn)
+
+	if err := ledger.validators.Upsert(validator); err != nil {
+		return fmt.Errorf("error updating validator %s: %v", consensusNodeAddress, err)
+	}
+
+	return nil
+}
+
+// slashRedelegation unbonds and burns the slash fraction of the destination shares of the redelegation, as far as
+// the delegator still holds them
+func (ledger *ledger) slashRedelegation(redelegationRow view.RedelegationRow, slashFraction coin.Dec) error {
+	sharesToUnbond := slashFraction.Mul(redelegationRow.SharesDst)
+	if sharesToUnbond.IsZero() {
+		return nil
+	}
+
+	delegation, err := ledger.delegations.FindBy(redelegationRow.DelegatorAddress, redelegationRow.ValidatorDstAddress)
+	if err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("error getting delegation: %v", err)
+	}
+	if sharesToUnbond.GT(delegation.Shares) {
+		sharesToUnbond = delegation.Shares
+	}
+
+	validatorDstAddress := redelegationRow.ValidatorDstAddress
+	validatorDst, err := ledger.validators.FindBy(view.ValidatorIdentity{
+		MaybeOperatorAddress: &validatorDstAddress,
+	})
+	if err != nil {
+		return fmt.Errorf("error getting validator %s: %v", validatorDstAddress, err)
+	}
+
+	if _, err := ledger.removeShares(validatorDst, delegation, sharesToUnbond); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func sharesFromTokens(validator *view.ValidatorRow, amount coin.Int) coin.Dec {
+	if validator.Tokens.IsZero() {
+		return coin.ZeroDec()
+	}
+	return validator.DelegatorShares.MulInt(amount).QuoInt(validator.Tokens)
+}
+
+func addTokensFromDel(validator *view.ValidatorRow, amount coin.Int) coin.Dec {
+	var issuedShares coin.Dec
+	if validator.DelegatorShares.IsZero() {
+		issuedShares = amount.ToDec()
+	} else {
+		issuedShares = sharesFromTokens(validator, amount)
+	}
+
+	validator.Tokens = validator.Tokens.Add(amount)
+	validator.DelegatorShares = validator.DelegatorShares.Add(issuedShares)
+
+	return issuedShares
+}
+
+func removeDelShares(validator *view.ValidatorRow, shares coin.Dec) coin.Int {
+	remainingShares := validator.DelegatorShares.Sub(shares)
+
+	var issuedTokens coin.Int
+	if remainingShares.IsZero() {
+		issuedTokens = validator.Tokens
+		validator.Tokens = coin.ZeroInt()
+	} else {
+		issuedTokens = validator.TokensFromShares(shares)
+		validator.Tokens = validator.Tokens.Sub(issuedTokens)
+	}
+	validator.DelegatorShares = remainingShares
+
+	return issuedTokens
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS view_delegation_validators_consensus_node_address_index;
+
+DROP TABLE IF EXISTS view_delegation_validators;
@@ -0,0 +1,9 @@
+CREATE TABLE view_delegation_validators (
+    operator_address VARCHAR NOT NULL,
+    consensus_node_address VARCHAR NOT NULL,
+    tokens NUMERIC NOT NULL,
+    delegator_shares NUMERIC NOT NULL,
+    PRIMARY KEY (operator_address)
+);
+
+CREATE UNIQUE INDEX view_delegation_validators_consensus_node_address_index ON view_delegation_validators USING btree (consensus_node_address);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS view_delegations_validator_address_shares_index;
+
+DROP TABLE IF EXISTS view_delegations;
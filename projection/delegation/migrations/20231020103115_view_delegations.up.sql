@@ -0,0 +1,10 @@
+CREATE TABLE view_delegations (
+    delegator_address VARCHAR NOT NULL,
+    validator_address VARCHAR NOT NULL,
+    shares NUMERIC NOT NULL,
+    created_at_block_height BIGINT NOT NULL,
+    last_updated_block_height BIGINT NOT NULL,
+    PRIMARY KEY (delegator_address, validator_address)
+);
+
+CREATE INDEX view_delegations_validator_address_shares_index ON view_delegations USING btree (validator_address, shares);
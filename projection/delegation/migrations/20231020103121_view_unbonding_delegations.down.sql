@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS view_unbonding_delegations_delegator_address_validator_address_index;
+
+DROP TABLE IF EXISTS view_unbonding_delegations;
@@ -0,0 +1,12 @@
+CREATE TABLE view_unbonding_delegations (
+    id BIGSERIAL,
+    delegator_address VARCHAR NOT NULL,
+    validator_address VARCHAR NOT NULL,
+    creation_height BIGINT NOT NULL,
+    completion_time BIGINT NOT NULL,
+    balance NUMERIC NOT NULL,
+    completed_height BIGINT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX view_unbonding_delegations_delegator_address_validator_address_index ON view_unbonding_delegations USING btree (delegator_address, validator_address) WHERE completed_height IS NULL;
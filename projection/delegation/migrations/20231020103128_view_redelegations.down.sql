@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS view_redelegations_completion_time_index;
+DROP INDEX IF EXISTS view_redelegations_delegator_address_index;
+
+DROP TABLE IF EXISTS view_redelegations;
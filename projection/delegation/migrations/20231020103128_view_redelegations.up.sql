@@ -0,0 +1,15 @@
+CREATE TABLE view_redelegations (
+    id BIGSERIAL,
+    delegator_address VARCHAR NOT NULL,
+    validator_src_address VARCHAR NOT NULL,
+    validator_dst_address VARCHAR NOT NULL,
+    creation_height BIGINT NOT NULL,
+    completion_time BIGINT NOT NULL,
+    balance NUMERIC NOT NULL,
+    shares_dst NUMERIC NOT NULL,
+    completed_height BIGINT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX view_redelegations_delegator_address_index ON view_redelegations USING btree (delegator_address) WHERE completed_height IS NULL;
+CREATE INDEX view_redelegations_completion_time_index ON view_redelegations USING btree (completion_time) WHERE completed_height IS NULL;
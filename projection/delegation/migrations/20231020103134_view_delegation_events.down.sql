@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS view_delegation_events_delegator_address_block_height_index;
+
+DROP TABLE IF EXISTS view_delegation_events;
@@ -0,0 +1,15 @@
+CREATE TABLE view_delegation_events (
+    id BIGSERIAL,
+    delegator_address VARCHAR NOT NULL,
+    validator_address VARCHAR NOT NULL,
+    validator_dst_address VARCHAR NULL,
+    block_height BIGINT NOT NULL,
+    block_time BIGINT NULL,
+    transaction_hash VARCHAR NULL,
+    type VARCHAR NOT NULL,
+    amount NUMERIC NOT NULL,
+    completion_time BIGINT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX view_delegation_events_delegator_address_block_height_index ON view_delegation_events USING btree (delegator_address, block_height);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS view_unbonding_delegations_validator_address_index;
+
+ALTER TABLE view_unbonding_delegations DROP COLUMN IF EXISTS initial_balance;
@@ -0,0 +1,5 @@
+ALTER TABLE view_unbonding_delegations ADD COLUMN initial_balance NUMERIC NULL;
+UPDATE view_unbonding_delegations SET initial_balance = balance;
+ALTER TABLE view_unbonding_delegations ALTER COLUMN initial_balance SET NOT NULL;
+
+CREATE INDEX view_unbonding_delegations_validator_address_index ON view_unbonding_delegations USING btree (validator_address) WHERE completed_height IS NULL;
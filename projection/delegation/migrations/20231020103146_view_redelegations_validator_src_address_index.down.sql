@@ -0,0 +1 @@
+DROP INDEX IF EXISTS view_redelegations_validator_src_address_index;
@@ -0,0 +1 @@
+CREATE INDEX view_redelegations_validator_src_address_index ON view_redelegations USING btree (validator_src_address) WHERE completed_height IS NULL;
@@ -0,0 +1,6 @@
+CREATE TABLE view_delegation_slashing_param_changes (
+    proposal_id VARCHAR NOT NULL,
+    key VARCHAR NOT NULL,
+    value VARCHAR NOT NULL,
+    PRIMARY KEY (proposal_id, key)
+);
@@ -0,0 +1,6 @@
+CREATE TABLE view_delegation_slashing_params_history (
+    key VARCHAR NOT NULL,
+    block_height BIGINT NOT NULL,
+    value VARCHAR NOT NULL,
+    PRIMARY KEY (key, block_height)
+);
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS view_delegation_params_journal ON view_delegation_params;
+DROP TRIGGER IF EXISTS view_delegation_validators_journal ON view_delegation_validators;
+DROP TRIGGER IF EXISTS view_delegations_journal ON view_delegations;
+DROP TRIGGER IF EXISTS view_unbonding_delegations_journal ON view_unbonding_delegations;
+DROP TRIGGER IF EXISTS view_redelegations_journal ON view_redelegations;
+DROP TRIGGER IF EXISTS view_delegation_slashing_param_changes_journal ON view_delegation_slashing_param_changes;
@@ -0,0 +1,17 @@
+CREATE TRIGGER view_delegation_params_journal AFTER INSERT OR UPDATE OR DELETE ON view_delegation_params
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_delegation_validators_journal AFTER INSERT OR UPDATE OR DELETE ON view_delegation_validators
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_delegations_journal AFTER INSERT OR UPDATE OR DELETE ON view_delegations
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_unbonding_delegations_journal AFTER INSERT OR UPDATE OR DELETE ON view_unbonding_delegations
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_redelegations_journal AFTER INSERT OR UPDATE OR DELETE ON view_redelegations
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_delegation_slashing_param_changes_journal AFTER INSERT OR UPDATE OR DELETE ON view_delegation_slashing_param_changes
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
@@ -0,0 +1,199 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/view"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/usecase/coin"
+)
+
+const DELEGATION_EVENTS_TABLE_NAME = "view_delegation_events"
+
+const (
+	DELEGATION_EVENT_TYPE_DELEGATE               = "delegate"
+	DELEGATION_EVENT_TYPE_UNDELEGATE             = "undelegate"
+	DELEGATION_EVENT_TYPE_REDELEGATE             = "redelegate"
+	DELEGATION_EVENT_TYPE_UNBONDING_COMPLETED    = "unbonding_completed"
+	DELEGATION_EVENT_TYPE_REDELEGATION_COMPLETED = "redelegation_completed"
+)
+
+type DelegationEvents interface {
+	InsertAll([]DelegationEventRow) error
+	ListByDelegatorAddress(
+		delegatorAddress string,
+		order DelegationEventsListOrder,
+		pagination *pagination.Pagination,
+	) ([]DelegationEventRow, *pagination.Result, error)
+}
+
+type DelegationEventsView struct {
+	rdb *rdb.Handle
+}
+
+func NewDelegationEventsView(handle *rdb.Handle) DelegationEvents {
+	return &DelegationEventsView{
+		handle,
+	}
+}
+
+func (delegationEventsView *DelegationEventsView) InsertAll(rows []DelegationEventRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	stmtBuilder := delegationEventsView.rdb.StmtBuilder.
+		Insert(DELEGATION_EVENTS_TABLE_NAME).
+		Columns(
+			"delegator_address",
+			"validator_address",
+			"validator_dst_address",
+			"block_height",
+			"block_time",
+			"transaction_hash",
+			"type",
+			"amount",
+			"completion_time",
+		)
+	for i := range rows {
+		stmtBuilder = stmtBuilder.Values(
+			rows[i].DelegatorAddress,
+			rows[i].ValidatorAddress,
+			rows[i].MaybeValidatorDstAddress,
+			rows[i].BlockHeight,
+			delegationEventsView.rdb.Tton(rows[i].MaybeBlockTime),
+			rows[i].MaybeTransactionHash,
+			rows[i].Type,
+			delegationEventsView.rdb.Bton(rows[i].Amount.BigInt()),
+			delegationEventsView.rdb.Tton(rows[i].MaybeCompletionTime),
+		)
+	}
+
+	sql, sqlArgs, err := stmtBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("error building delegation events insertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := delegationEventsView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error inserting delegation events into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != int64(len(rows)) {
+		return fmt.Errorf("error inserting delegation events into the table: mismatched number of rows inserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (delegationEventsView *DelegationEventsView) ListByDelegatorAddress(
+	delegatorAddress string,
+	order DelegationEventsListOrder,
+	pagination *pagination.Pagination,
+) ([]DelegationEventRow, *pagination.Result, error) {
+	stmtBuilder := delegationEventsView.rdb.StmtBuilder.Select(
+		"delegator_address",
+		"validator_address",
+		"validator_dst_address",
+		"block_height",
+		"block_time",
+		"transaction_hash",
+		"type",
+		"amount",
+		"completion_time",
+	).From(
+		DELEGATION_EVENTS_TABLE_NAME,
+	).Where(
+		"delegator_address = ?", delegatorAddress,
+	)
+
+	if order.Height == view.ORDER_DESC {
+		stmtBuilder = stmtBuilder.OrderBy("block_height DESC", "id DESC")
+	} else {
+		stmtBuilder = stmtBuilder.OrderBy("block_height", "id")
+	}
+
+	rDbPagination := rdb.NewRDbPaginationBuilder(
+		pagination,
+		delegationEventsView.rdb,
+	).BuildStmt(stmtBuilder)
+	sql, sqlArgs, err := rDbPagination.ToStmtBuilder().ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building delegation events select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := delegationEventsView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error executing delegation events select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	delegationEvents := make([]DelegationEventRow, 0)
+	for rowsResult.Next() {
+		var delegationEvent DelegationEventRow
+		blockTimeReader := delegationEventsView.rdb.NtotReader()
+		amountReader := delegationEventsView.rdb.NtobReader()
+		completionTimeReader := delegationEventsView.rdb.NtotReader()
+		if err = rowsResult.Scan(
+			&delegationEvent.DelegatorAddress,
+			&delegationEvent.ValidatorAddress,
+			&delegationEvent.MaybeValidatorDstAddress,
+			&delegationEvent.BlockHeight,
+			blockTimeReader.ScannableArg(),
+			&delegationEvent.MaybeTransactionHash,
+			&delegationEvent.Type,
+			amountReader.ScannableArg(),
+			completionTimeReader.ScannableArg(),
+		); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				return nil, nil, rdb.ErrNoRows
+			}
+			return nil, nil, fmt.Errorf("error scanning delegation event row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		blockTime, parseErr := blockTimeReader.Parse()
+		if parseErr != nil {
+			return nil, nil, fmt.Errorf("error parsing delegation event block time: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		delegationEvent.MaybeBlockTime = blockTime
+		amount, parseErr := amountReader.Parse()
+		if parseErr != nil {
+			return nil, nil, fmt.Errorf("error parsing delegation event amount: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		delegationEvent.Amount = coin.NewIntFromBigInt(amount)
+		completionTime, parseErr := completionTimeReader.Parse()
+		if parseErr != nil {
+			return nil, nil, fmt.Errorf("error parsing delegation event completion time: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		delegationEvent.MaybeCompletionTime = completionTime
+
+		delegationEvents = append(delegationEvents, delegationEvent)
+	}
+
+	paginationResult, err := rDbPagination.Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error preparing pagination result: %v", err)
+	}
+
+	return delegationEvents, paginationResult, nil
+}
+
+type DelegationEventsListOrder struct {
+	Height view.ORDER
+}
+
+// DelegationEventRow is an entry of the delegation history of a delegator. ValidatorAddress is the source validator
+// of redelegations, and Amount is in the bond denom.
+type DelegationEventRow struct {
+	DelegatorAddress         string           `json:"delegatorAddress"`
+	ValidatorAddress         string           `json:"validatorAddress"`
+	MaybeValidatorDstAddress *string          `json:"validatorDstAddress"`
+	BlockHeight              int64            `json:"blockHeight"`
+	MaybeBlockTime           *utctime.UTCTime `json:"blockTime"`
+	MaybeTransactionHash     *string          `json:"transactionHash"`
+	Type                     string           `json:"type"`
+	Amount                   coin.Int         `json:"amount"`
+	MaybeCompletionTime      *utctime.UTCTime `json:"completionTime"`
+}
@@ -0,0 +1,26 @@
+package view
+
+import (
+	pagination_interface "github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	testify_mock "github.com/stretchr/testify/mock"
+)
+
+type MockDelegationEventsView struct {
+	testify_mock.Mock
+}
+
+func (delegationEventsView *MockDelegationEventsView) InsertAll(rows []DelegationEventRow) error {
+	mockArgs := delegationEventsView.Called(rows)
+	return mockArgs.Error(0)
+}
+
+func (delegationEventsView *MockDelegationEventsView) ListByDelegatorAddress(
+	delegatorAddress string,
+	order DelegationEventsListOrder,
+	pagination *pagination_interface.Pagination,
+) ([]DelegationEventRow, *pagination_interface.Result, error) {
+	mockArgs := delegationEventsView.Called(delegatorAddress, order, pagination)
+	rows, _ := mockArgs.Get(0).([]DelegationEventRow)
+	paginationResult, _ := mockArgs.Get(1).(*pagination_interface.Result)
+	return rows, paginationResult, mockArgs.Error(2)
+}
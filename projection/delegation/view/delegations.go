@@ -0,0 +1,250 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/usecase/coin"
+	sq "github.com/Masterminds/squirrel"
+)
+
+const DELEGATIONS_TABLE_NAME = "view_delegations"
+
+type Delegations interface {
+	Upsert(*DelegationRow) error
+	Delete(delegatorAddress string, validatorAddress string) error
+	FindBy(delegatorAddress string, validatorAddress string) (*DelegationRow, error)
+	ListByDelegatorAddress(delegatorAddress string) ([]DelegationRow, error)
+	ListByValidatorAddress(
+		validatorAddress string,
+		pagination *pagination.Pagination,
+	) ([]DelegationRow, *pagination.Result, error)
+}
+
+type DelegationsView struct {
+	rdb *rdb.Handle
+}
+
+func NewDelegationsView(handle *rdb.Handle) Delegations {
+	return &DelegationsView{
+		handle,
+	}
+}
+
+func (delegationsView *DelegationsView) Upsert(row *DelegationRow) error {
+	sql, sqlArgs, err := delegationsView.rdb.StmtBuilder.
+		Insert(DELEGATIONS_TABLE_NAME).
+		Columns(
+			"delegator_address",
+			"validator_address",
+			"shares",
+			"created_at_block_height",
+			"last_updated_block_height",
+		).
+		Values(
+			row.DelegatorAddress,
+			row.ValidatorAddress,
+			row.Shares.String(),
+			row.CreatedAtBlockHeight,
+			row.LastUpdatedBlockHeight,
+		).
+		Suffix(
+			"ON CONFLICT(delegator_address, validator_address) DO UPDATE SET " +
+				"shares = EXCLUDED.shares, " +
+				"last_updated_block_height = EXCLUDED.last_updated_block_height",
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building delegation upsertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := delegationsView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error upserting delegation into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error upserting delegation into the table: no rows upserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (delegationsView *DelegationsView) Delete(delegatorAddress string, validatorAddress string) error {
+	sql, sqlArgs, err := delegationsView.rdb.StmtBuilder.
+		Delete(DELEGATIONS_TABLE_NAME).
+		Where("delegator_address = ? AND validator_address = ?", delegatorAddress, validatorAddress).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building delegation deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	if _, err = delegationsView.rdb.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error deleting delegation from the table: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (delegationsView *DelegationsView) FindBy(delegatorAddress string, validatorAddress string) (*DelegationRow, error) {
+	sql, sqlArgs, err := delegationsView.selectStmtBuilder().Where(
+		"delegations.delegator_address = ? AND delegations.validator_address = ?", delegatorAddress, validatorAddress,
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building delegation selection sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	var row DelegationRow
+	if err = delegationsView.scanRow(delegationsView.rdb.QueryRow(sql, sqlArgs...), &row); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, rdb.ErrNoRows
+		}
+		return nil, fmt.Errorf("error scanning delegation row: %v: %w", err, rdb.ErrQuery)
+	}
+
+	return &row, nil
+}
+
+func (delegationsView *DelegationsView) ListByDelegatorAddress(delegatorAddress string) ([]DelegationRow, error) {
+	sql, sqlArgs, err := delegationsView.selectStmtBuilder().Where(
+		"delegations.delegator_address = ?", delegatorAddress,
+	).OrderBy(
+		"delegations.shares DESC", "delegations.validator_address",
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building delegations select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := delegationsView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing delegations select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	rows := make([]DelegationRow, 0)
+	for rowsResult.Next() {
+		var row DelegationRow
+		if err = delegationsView.scanRow(rowsResult, &row); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				return nil, rdb.ErrNoRows
+			}
+			return nil, fmt.Errorf("error scanning delegation row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (delegationsView *DelegationsView) ListByValidatorAddress(
+	validatorAddress string,
+	pagination *pagination.Pagination,
+) ([]DelegationRow, *pagination.Result, error) {
+	stmtBuilder := delegationsView.selectStmtBuilder().Where(
+		"delegations.validator_address = ?", validatorAddress,
+	).OrderBy(
+		"delegations.shares DESC", "delegations.delegator_address",
+	)
+
+	rDbPagination := rdb.NewRDbPaginationBuilder(
+		pagination,
+		delegationsView.rdb,
+	).BuildStmt(stmtBuilder)
+	sql, sqlArgs, err := rDbPagination.ToStmtBuilder().ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building delegations select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := delegationsView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error executing delegations select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	rows := make([]DelegationRow, 0)
+	for rowsResult.Next() {
+		var row DelegationRow
+		if err = delegationsView.scanRow(rowsResult, &row); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				return nil, nil, rdb.ErrNoRows
+			}
+			return nil, nil, fmt.Errorf("error scanning delegation row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		rows = append(rows, row)
+	}
+
+	paginationResult, err := rDbPagination.Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error preparing pagination result: %v", err)
+	}
+
+	return rows, paginationResult, nil
+}
+
+func (delegationsView *DelegationsView) selectStmtBuilder() sq.SelectBuilder {
+	return delegationsView.rdb.StmtBuilder.Select(
+		"delegations.delegator_address",
+		"delegations.validator_address",
+		"CAST(delegations.shares AS VARCHAR)",
+		"delegations.created_at_block_height",
+		"delegations.last_updated_block_height",
+		"validators.tokens",
+		"CAST(validators.delegator_shares AS VARCHAR)",
+	).From(
+		fmt.Sprintf("%s AS delegations", DELEGATIONS_TABLE_NAME),
+	).InnerJoin(
+		fmt.Sprintf(
+			"%s AS validators ON validators.operator_address = delegations.validator_address",
+			VALIDATORS_TABLE_NAME,
+		),
+	)
+}
+
+func (delegationsView *DelegationsView) scanRow(scanner rdb.RowResult, row *DelegationRow) error {
+	var shares string
+	var validatorDelegatorShares string
+	validatorTokensReader := delegationsView.rdb.NtobReader()
+	if err := scanner.Scan(
+		&row.DelegatorAddress,
+		&row.ValidatorAddress,
+		&shares,
+		&row.CreatedAtBlockHeight,
+		&row.LastUpdatedBlockHeight,
+		validatorTokensReader.ScannableArg(),
+		&validatorDelegatorShares,
+	); err != nil {
+		return err
+	}
+
+	var err error
+	if row.Shares, err = coin.NewDecFromStr(shares); err != nil {
+		return fmt.Errorf("error parsing delegation shares: %v", err)
+	}
+	validatorTokens, err := validatorTokensReader.Parse()
+	if err != nil {
+		return fmt.Errorf("error parsing delegation validator tokens: %v", err)
+	}
+	validator := ValidatorRow{
+		Tokens: coin.NewIntFromBigInt(validatorTokens),
+	}
+	if validator.DelegatorShares, err = coin.NewDecFromStr(validatorDelegatorShares); err != nil {
+		return fmt.Errorf("error parsing delegation validator delegator shares: %v", err)
+	}
+	row.Balance = validator.TokensFromShares(row.Shares)
+
+	return nil
+}
+
+// DelegationRow is the shares of a delegator on a validator. Balance is the tokens the shares are worth at the
+// current tokens and delegator shares of the validator, and is not persisted.
+type DelegationRow struct {
+	DelegatorAddress       string   `json:"delegatorAddress"`
+	ValidatorAddress       string   `json:"validatorAddress"`
+	Shares                 coin.Dec `json:"shares"`
+	Balance                coin.Int `json:"balance"`
+	CreatedAtBlockHeight   int64    `json:"createdAtBlockHeight"`
+	LastUpdatedBlockHeight int64    `json:"lastUpdatedBlockHeight"`
+}
@@ -0,0 +1,45 @@
+package view
+
+import (
+	pagination_interface "github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	testify_mock "github.com/stretchr/testify/mock"
+)
+
+type MockDelegationsView struct {
+	testify_mock.Mock
+}
+
+func (delegationsView *MockDelegationsView) Upsert(row *DelegationRow) error {
+	mockArgs := delegationsView.Called(row)
+	return mockArgs.Error(0)
+}
+
+func (delegationsView *MockDelegationsView) Delete(delegatorAddress string, validatorAddress string) error {
+	mockArgs := delegationsView.Called(delegatorAddress, validatorAddress)
+	return mockArgs.Error(0)
+}
+
+func (delegationsView *MockDelegationsView) FindBy(
+	delegatorAddress string,
+	validatorAddress string,
+) (*DelegationRow, error) {
+	mockArgs := delegationsView.Called(delegatorAddress, validatorAddress)
+	row, _ := mockArgs.Get(0).(*DelegationRow)
+	return row, mockArgs.Error(1)
+}
+
+func (delegationsView *MockDelegationsView) ListByDelegatorAddress(delegatorAddress string) ([]DelegationRow, error) {
+	mockArgs := delegationsView.Called(delegatorAddress)
+	rows, _ := mockArgs.Get(0).([]DelegationRow)
+	return rows, mockArgs.Error(1)
+}
+
+func (delegationsView *MockDelegationsView) ListByValidatorAddress(
+	validatorAddress string,
+	pagination *pagination_interface.Pagination,
+) ([]DelegationRow, *pagination_interface.Result, error) {
+	mockArgs := delegationsView.Called(validatorAddress, pagination)
+	rows, _ := mockArgs.Get(0).([]DelegationRow)
+	paginationResult, _ := mockArgs.Get(1).(*pagination_interface.Result)
+	return rows, paginationResult, mockArgs.Error(2)
+}
@@ -0,0 +1,214 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/usecase/coin"
+)
+
+const REDELEGATIONS_TABLE_NAME = "view_redelegations"
+
+var redelegationColumns = []string{
+	"id",
+	"delegator_address",
+	"validator_src_address",
+	"validator_dst_address",
+	"creation_height",
+	"completion_time",
+	"balance",
+	"CAST(shares_dst AS VARCHAR)",
+	"completed_height",
+}
+
+type Redelegations interface {
+	Insert(*RedelegationRow) error
+	// CompleteMatured marks the pending entries maturing by blockTime as completed at completedHeight, and returns
+	// them
+	CompleteMatured(blockTime utctime.UTCTime, completedHeight int64) ([]RedelegationRow, error)
+	ListPendingByDelegatorAddress(delegatorAddress string) ([]RedelegationRow, error)
+	// ListSlashable returns the pending entries from the source validator created from infractionHeight and not
+	// mature by blockTime, which are slashed along with the validator for an infraction at infractionHeight
+	ListSlashable(
+		validatorSrcAddress string,
+		infractionHeight int64,
+		blockTime utctime.UTCTime,
+	) ([]RedelegationRow, error)
+}
+
+type RedelegationsView struct {
+	rdb *rdb.Handle
+}
+
+func NewRedelegationsView(handle *rdb.Handle) Redelegations {
+	return &RedelegationsView{
+		handle,
+	}
+}
+
+func (redelegationsView *RedelegationsView) Insert(row *RedelegationRow) error {
+	sql, sqlArgs, err := redelegationsView.rdb.StmtBuilder.
+		Insert(REDELEGATIONS_TABLE_NAME).
+		Columns(
+			"delegator_address",
+			"validator_src_address",
+			"validator_dst_address",
+			"creation_height",
+			"completion_time",
+			"balance",
+			"shares_dst",
+			"completed_height",
+		).
+		Values(
+			row.DelegatorAddress,
+			row.ValidatorSrcAddress,
+			row.ValidatorDstAddress,
+			row.CreationHeight,
+			redelegationsView.rdb.Tton(&row.CompletionTime),
+			redelegationsView.rdb.Bton(row.Balance.BigInt()),
+			row.SharesDst.String(),
+			row.MaybeCompletedHeight,
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building redelegation insertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := redelegationsView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error inserting redelegation into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error inserting redelegation into the table: no rows inserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (redelegationsView *RedelegationsView) CompleteMatured(
+	blockTime utctime.UTCTime,
+	completedHeight int64,
+) ([]RedelegationRow, error) {
+	sql, sqlArgs, err := redelegationsView.rdb.StmtBuilder.
+		Update(REDELEGATIONS_TABLE_NAME).
+		Set("completed_height", completedHeight).
+		Where(
+			"completed_height IS NULL AND completion_time <= ?",
+			redelegationsView.rdb.Tton(&blockTime),
+		).
+		Suffix("RETURNING " + strings.Join(redelegationColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building redelegations completion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	return redelegationsView.queryRows(sql, sqlArgs...)
+}
+
+func (redelegationsView *RedelegationsView) ListPendingByDelegatorAddress(
+	delegatorAddress string,
+) ([]RedelegationRow, error) {
+	sql, sqlArgs, err := redelegationsView.rdb.StmtBuilder.Select(
+		redelegationColumns...,
+	).From(
+		REDELEGATIONS_TABLE_NAME,
+	).Where(
+		"delegator_address = ? AND completed_height IS NULL", delegatorAddress,
+	).OrderBy(
+		"completion_time", "id",
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building redelegations select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	return redelegationsView.queryRows(sql, sqlArgs...)
+}
+
+func (redelegationsView *RedelegationsView) ListSlashable(
+	validatorSrcAddress string,
+	infractionHeight int64,
+	blockTime utctime.UTCTime,
+) ([]RedelegationRow, error) {
+	sql, sqlArgs, err := redelegationsView.rdb.StmtBuilder.Select(
+		redelegationColumns...,
+	).From(
+		REDELEGATIONS_TABLE_NAME,
+	).Where(
+		"validator_src_address = ? AND completed_height IS NULL AND creation_height >= ? AND completion_time > ?",
+		validatorSrcAddress,
+		infractionHeight,
+		redelegationsView.rdb.Tton(&blockTime),
+	).OrderBy(
+		"id",
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building redelegations select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	return redelegationsView.queryRows(sql, sqlArgs...)
+}
+
+func (redelegationsView *RedelegationsView) queryRows(sql string, sqlArgs ...interface{}) ([]RedelegationRow, error) {
+	rowsResult, err := redelegationsView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing redelegations SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	rows := make([]RedelegationRow, 0)
+	for rowsResult.Next() {
+		var row RedelegationRow
+		var sharesDst string
+		completionTimeReader := redelegationsView.rdb.NtotReader()
+		balanceReader := redelegationsView.rdb.NtobReader()
+		if err = rowsResult.Scan(
+			&row.Id,
+			&row.DelegatorAddress,
+			&row.ValidatorSrcAddress,
+			&row.ValidatorDstAddress,
+			&row.CreationHeight,
+			completionTimeReader.ScannableArg(),
+			balanceReader.ScannableArg(),
+			&sharesDst,
+			&row.MaybeCompletedHeight,
+		); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				return nil, rdb.ErrNoRows
+			}
+			return nil, fmt.Errorf("error scanning redelegation row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		completionTime, parseErr := completionTimeReader.Parse()
+		if parseErr != nil {
+			return nil, fmt.Errorf("error parsing redelegation completion time: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		row.CompletionTime = *completionTime
+		balance, parseErr := balanceReader.Parse()
+		if parseErr != nil {
+			return nil, fmt.Errorf("error parsing redelegation balance: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		row.Balance = coin.NewIntFromBigInt(balance)
+		if row.SharesDst, parseErr = coin.NewDecFromStr(sharesDst); parseErr != nil {
+			return nil, fmt.Errorf("error parsing redelegation destination shares: %v: %w", parseErr, rdb.ErrQuery)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+type RedelegationRow struct {
+	Id                   int64           `json:"-"`
+	DelegatorAddress     string          `json:"delegatorAddress"`
+	ValidatorSrcAddress  string          `json:"validatorSrcAddress"`
+	ValidatorDstAddress  string          `json:"validatorDstAddress"`
+	CreationHeight       int64           `json:"creationHeight"`
+	CompletionTime       utctime.UTCTime `json:"completionTime"`
+	Balance              coin.Int        `json:"balance"`
+	SharesDst            coin.Dec        `json:"sharesDst"`
+	MaybeCompletedHeight *int64          `json:"completedHeight"`
+}
@@ -0,0 +1,42 @@
+package view
+
+import (
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	testify_mock "github.com/stretchr/testify/mock"
+)
+
+type MockRedelegationsView struct {
+	testify_mock.Mock
+}
+
+func (redelegationsView *MockRedelegationsView) Insert(row *RedelegationRow) error {
+	mockArgs := redelegationsView.Called(row)
+	return mockArgs.Error(0)
+}
+
+func (redelegationsView *MockRedelegationsView) CompleteMatured(
+	blockTime utctime.UTCTime,
+	completedHeight int64,
+) ([]RedelegationRow, error) {
+	mockArgs := redelegationsView.Called(blockTime, completedHeight)
+	rows, _ := mockArgs.Get(0).([]RedelegationRow)
+	return rows, mockArgs.Error(1)
+}
+
+func (redelegationsView *MockRedelegationsView) ListPendingByDelegatorAddress(
+	delegatorAddress string,
+) ([]RedelegationRow, error) {
+	mockArgs := redelegationsView.Called(delegatorAddress)
+	rows, _ := mockArgs.Get(0).([]RedelegationRow)
+	return rows, mockArgs.Error(1)
+}
+
+func (redelegationsView *MockRedelegationsView) ListSlashable(
+	validatorSrcAddress string,
+	infractionHeight int64,
+	blockTime utctime.UTCTime,
+) ([]RedelegationRow, error) {
+	mockArgs := redelegationsView.Called(validatorSrcAddress, infractionHeight, blockTime)
+	rows, _ := mockArgs.Get(0).([]RedelegationRow)
+	return rows, mockArgs.Error(1)
+}
@@ -0,0 +1,116 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+)
+
+const SLASHING_PARAM_CHANGES_TABLE_NAME = "view_delegation_slashing_param_changes"
+
+// SlashingParamChanges keeps the slashing param changes of the submitted proposals until the proposals end
+type SlashingParamChanges interface {
+	InsertAll([]SlashingParamChangeRow) error
+	ListByProposalId(proposalId string) ([]SlashingParamChangeRow, error)
+	DeleteByProposalId(proposalId string) error
+}
+
+type SlashingParamChangesView struct {
+	rdb *rdb.Handle
+}
+
+func NewSlashingParamChangesView(handle *rdb.Handle) SlashingParamChanges {
+	return &SlashingParamChangesView{
+		handle,
+	}
+}
+
+func (slashingParamChangesView *SlashingParamChangesView) InsertAll(rows []SlashingParamChangeRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	stmtBuilder := slashingParamChangesView.rdb.StmtBuilder.
+		Insert(SLASHING_PARAM_CHANGES_TABLE_NAME).
+		Columns(
+			"proposal_id",
+			"key",
+			"value",
+		)
+	for _, row := range rows {
+		stmtBuilder = stmtBuilder.Values(row.ProposalId, row.Key, row.Value)
+	}
+
+	sql, sqlArgs, err := stmtBuilder.Suffix(
+		"ON CONFLICT(proposal_id, key) DO UPDATE SET value = EXCLUDED.value",
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building param changes insertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	if _, err = slashingParamChangesView.rdb.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error inserting param changes into the table: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (slashingParamChangesView *SlashingParamChangesView) ListByProposalId(proposalId string) ([]SlashingParamChangeRow, error) {
+	sql, sqlArgs, err := slashingParamChangesView.rdb.StmtBuilder.Select(
+		"proposal_id",
+		"key",
+		"value",
+	).From(
+		SLASHING_PARAM_CHANGES_TABLE_NAME,
+	).Where(
+		"proposal_id = ?", proposalId,
+	).OrderBy("key").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building param changes select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := slashingParamChangesView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing param changes select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	rows := make([]SlashingParamChangeRow, 0)
+	for rowsResult.Next() {
+		var row SlashingParamChangeRow
+		if err = rowsResult.Scan(&row.ProposalId, &row.Key, &row.Value); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				return nil, rdb.ErrNoRows
+			}
+			return nil, fmt.Errorf("error scanning param change row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (slashingParamChangesView *SlashingParamChangesView) DeleteByProposalId(proposalId string) error {
+	sql, sqlArgs, err := slashingParamChangesView.rdb.StmtBuilder.
+		Delete(SLASHING_PARAM_CHANGES_TABLE_NAME).
+		Where("proposal_id = ?", proposalId).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building param changes deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	if _, err = slashingParamChangesView.rdb.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error deleting param changes: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+// SlashingParamChangeRow is a change to a slash fraction, keyed by the param key of the projection params table
+type SlashingParamChangeRow struct {
+	ProposalId string
+	Key        string
+	Value      string
+}
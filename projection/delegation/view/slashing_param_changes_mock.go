@@ -0,0 +1,25 @@
+package view
+
+import (
+	testify_mock "github.com/stretchr/testify/mock"
+)
+
+type MockSlashingParamChangesView struct {
+	testify_mock.Mock
+}
+
+func (slashingParamChangesView *MockSlashingParamChangesView) InsertAll(rows []SlashingParamChangeRow) error {
+	mockArgs := slashingParamChangesView.Called(rows)
+	return mockArgs.Error(0)
+}
+
+func (slashingParamChangesView *MockSlashingParamChangesView) ListByProposalId(proposalId string) ([]SlashingParamChangeRow, error) {
+	mockArgs := slashingParamChangesView.Called(proposalId)
+	rows, _ := mockArgs.Get(0).([]SlashingParamChangeRow)
+	return rows, mockArgs.Error(1)
+}
+
+func (slashingParamChangesView *MockSlashingParamChangesView) DeleteByProposalId(proposalId string) error {
+	mockArgs := slashingParamChangesView.Called(proposalId)
+	return mockArgs.Error(0)
+}
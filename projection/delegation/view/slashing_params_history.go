@@ -0,0 +1,88 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+)
+
+const SLASHING_PARAMS_HISTORY_TABLE_NAME = "view_delegation_slashing_params_history"
+
+// SlashingParamsHistory keeps the slash fractions changed by the passed proposals at the heights they take effect
+type SlashingParamsHistory interface {
+	InsertAll([]SlashingParamsHistoryRow) error
+	// FindValueAt returns the value of the param at the height, or rdb.ErrNoRows when the param keeps its genesis
+	// value at the height
+	FindValueAt(key string, height int64) (string, error)
+}
+
+type SlashingParamsHistoryView struct {
+	rdb *rdb.Handle
+}
+
+func NewSlashingParamsHistoryView(handle *rdb.Handle) SlashingParamsHistory {
+	return &SlashingParamsHistoryView{
+		handle,
+	}
+}
+
+func (slashingParamsHistoryView *SlashingParamsHistoryView) InsertAll(rows []SlashingParamsHistoryRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	stmtBuilder := slashingParamsHistoryView.rdb.StmtBuilder.
+		Insert(SLASHING_PARAMS_HISTORY_TABLE_NAME).
+		Columns(
+			"key",
+			"block_height",
+			"value",
+		)
+	for _, row := range rows {
+		stmtBuilder = stmtBuilder.Values(row.Key, row.BlockHeight, row.Value)
+	}
+
+	sql, sqlArgs, err := stmtBuilder.Suffix(
+		"ON CONFLICT(key, block_height) DO UPDATE SET value = EXCLUDED.value",
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building slashing params history insertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	if _, err = slashingParamsHistoryView.rdb.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error inserting slashing params history into the table: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (slashingParamsHistoryView *SlashingParamsHistoryView) FindValueAt(key string, height int64) (string, error) {
+	// A change passed at a height applies from the next height
+	sql, sqlArgs, err := slashingParamsHistoryView.rdb.StmtBuilder.Select(
+		"value",
+	).From(
+		SLASHING_PARAMS_HISTORY_TABLE_NAME,
+	).Where(
+		"key = ? AND block_height < ?", key, height,
+	).OrderBy("block_height DESC").Limit(1).ToSql()
+	if err != nil {
+		return "", fmt.Errorf("error building slashing params history select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	var value string
+	if err = slashingParamsHistoryView.rdb.QueryRow(sql, sqlArgs...).Scan(&value); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return "", rdb.ErrNoRows
+		}
+		return "", fmt.Errorf("error scanning slashing params history row: %v: %w", err, rdb.ErrQuery)
+	}
+
+	return value, nil
+}
+
+type SlashingParamsHistoryRow struct {
+	Key         string
+	BlockHeight int64
+	Value       string
+}
@@ -0,0 +1,19 @@
+package view
+
+import (
+	testify_mock "github.com/stretchr/testify/mock"
+)
+
+type MockSlashingParamsHistoryView struct {
+	testify_mock.Mock
+}
+
+func (slashingParamsHistoryView *MockSlashingParamsHistoryView) InsertAll(rows []SlashingParamsHistoryRow) error {
+	mockArgs := slashingParamsHistoryView.Called(rows)
+	return mockArgs.Error(0)
+}
+
+func (slashingParamsHistoryView *MockSlashingParamsHistoryView) FindValueAt(key string, height int64) (string, error) {
+	mockArgs := slashingParamsHistoryView.Called(key, height)
+	return mockArgs.String(0), mockArgs.Error(1)
+}
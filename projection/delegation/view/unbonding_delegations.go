@@ -0,0 +1,246 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/usecase/coin"
+)
+
+const UNBONDING_DELEGATIONS_TABLE_NAME = "view_unbonding_delegations"
+
+var unbondingDelegationColumns = []string{
+	"id",
+	"delegator_address",
+	"validator_address",
+	"creation_height",
+	"completion_time",
+	"initial_balance",
+	"balance",
+	"completed_height",
+}
+
+type UnbondingDelegations interface {
+	Insert(*UnbondingDelegationRow) error
+	// CompleteMatured marks the pending entries of the delegator on the validator maturing by blockTime as completed
+	// at completedHeight, and returns them
+	CompleteMatured(
+		delegatorAddress string,
+		validatorAddress string,
+		blockTime utctime.UTCTime,
+		completedHeight int64,
+	) ([]UnbondingDelegationRow, error)
+	ListPendingByDelegatorAddress(delegatorAddress string) ([]UnbondingDelegationRow, error)
+	// ListSlashable returns the pending entries from the validator created from infractionHeight and not mature by
+	// blockTime, which are slashed along with the validator for an infraction at infractionHeight
+	ListSlashable(
+		validatorAddress string,
+		infractionHeight int64,
+		blockTime utctime.UTCTime,
+	) ([]UnbondingDelegationRow, error)
+	UpdateBalance(id int64, balance coin.Int) error
+}
+
+type UnbondingDelegationsView struct {
+	rdb *rdb.Handle
+}
+
+func NewUnbondingDelegationsView(handle *rdb.Handle) UnbondingDelegations {
+	return &UnbondingDelegationsView{
+		handle,
+	}
+}
+
+func (unbondingDelegationsView *UnbondingDelegationsView) Insert(row *UnbondingDelegationRow) error {
+	sql, sqlArgs, err := unbondingDelegationsView.rdb.StmtBuilder.
+		Insert(UNBONDING_DELEGATIONS_TABLE_NAME).
+		Columns(
+			"delegator_address",
+			"validator_address",
+			"creation_height",
+			"completion_time",
+			"initial_balance",
+			"balance",
+			"completed_height",
+		).
+		Values(
+			row.DelegatorAddress,
+			row.ValidatorAddress,
+			row.CreationHeight,
+			unbondingDelegationsView.rdb.Tton(&row.CompletionTime),
+			unbondingDelegationsView.rdb.Bton(row.InitialBalance.BigInt()),
+			unbondingDelegationsView.rdb.Bton(row.Balance.BigInt()),
+			row.MaybeCompletedHeight,
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building unbonding delegation insertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := unbondingDelegationsView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error inserting unbonding delegation into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error inserting unbonding delegation into the table: no rows inserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (unbondingDelegationsView *UnbondingDelegationsView) CompleteMatured(
+	delegatorAddress string,
+	validatorAddress string,
+	blockTime utctime.UTCTime,
+	completedHeight int64,
+) ([]UnbondingDelegationRow, error) {
+	sql, sqlArgs, err := unbondingDelegationsView.rdb.StmtBuilder.
+		Update(UNBONDING_DELEGATIONS_TABLE_NAME).
+		Set("completed_height", completedHeight).
+		Where(
+			"delegator_address = ? AND validator_address = ? AND completed_height IS NULL AND completion_time <= ?",
+			delegatorAddress,
+			validatorAddress,
+			unbondingDelegationsView.rdb.Tton(&blockTime),
+		).
+		Suffix("RETURNING " + strings.Join(unbondingDelegationColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building unbonding delegations completion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	return unbondingDelegationsView.queryRows(sql, sqlArgs...)
+}
+
+func (unbondingDelegationsView *UnbondingDelegationsView) ListPendingByDelegatorAddress(
+	delegatorAddress string,
+) ([]UnbondingDelegationRow, error) {
+	sql, sqlArgs, err := unbondingDelegationsView.rdb.StmtBuilder.Select(
+		unbondingDelegationColumns...,
+	).From(
+		UNBONDING_DELEGATIONS_TABLE_NAME,
+	).Where(
+		"delegator_address = ? AND completed_height IS NULL", delegatorAddress,
+	).OrderBy(
+		"completion_time", "id",
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building unbonding delegations select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	return unbondingDelegationsView.queryRows(sql, sqlArgs...)
+}
+
+func (unbondingDelegationsView *UnbondingDelegationsView) ListSlashable(
+	validatorAddress string,
+	infractionHeight int64,
+	blockTime utctime.UTCTime,
+) ([]UnbondingDelegationRow, error) {
+	sql, sqlArgs, err := unbondingDelegationsView.rdb.StmtBuilder.Select(
+		unbondingDelegationColumns...,
+	).From(
+		UNBONDING_DELEGATIONS_TABLE_NAME,
+	).Where(
+		"validator_address = ? AND completed_height IS NULL AND creation_height >= ? AND completion_time > ?",
+		validatorAddress,
+		infractionHeight,
+		unbondingDelegationsView.rdb.Tton(&blockTime),
+	).OrderBy(
+		"id",
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building unbonding delegations select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	return unbondingDelegationsView.queryRows(sql, sqlArgs...)
+}
+
+func (unbondingDelegationsView *UnbondingDelegationsView) UpdateBalance(id int64, balance coin.Int) error {
+	sql, sqlArgs, err := unbondingDelegationsView.rdb.StmtBuilder.
+		Update(UNBONDING_DELEGATIONS_TABLE_NAME).
+		Set("balance", unbondingDelegationsView.rdb.Bton(balance.BigInt())).
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building unbonding delegation balance update sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := unbondingDelegationsView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error updating unbonding delegation balance: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error updating unbonding delegation balance: no rows updated: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (unbondingDelegationsView *UnbondingDelegationsView) queryRows(
+	sql string,
+	sqlArgs ...interface{},
+) ([]UnbondingDelegationRow, error) {
+	rowsResult, err := unbondingDelegationsView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing unbonding delegations SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	rows := make([]UnbondingDelegationRow, 0)
+	for rowsResult.Next() {
+		var row UnbondingDelegationRow
+		completionTimeReader := unbondingDelegationsView.rdb.NtotReader()
+		initialBalanceReader := unbondingDelegationsView.rdb.NtobReader()
+		balanceReader := unbondingDelegationsView.rdb.NtobReader()
+		if err = rowsResult.Scan(
+			&row.Id,
+			&row.DelegatorAddress,
+			&row.ValidatorAddress,
+			&row.CreationHeight,
+			completionTimeReader.ScannableArg(),
+			initialBalanceReader.ScannableArg(),
+			balanceReader.ScannableArg(),
+			&row.MaybeCompletedHeight,
+		); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				return nil, rdb.ErrNoRows
+			}
+			return nil, fmt.Errorf("error scanning unbonding delegation row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		completionTime, parseErr := completionTimeReader.Parse()
+		if parseErr != nil {
+			return nil, fmt.Errorf("error parsing unbonding delegation completion time: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		row.CompletionTime = *completionTime
+		initialBalance, parseErr := initialBalanceReader.Parse()
+		if parseErr != nil {
+			return nil, fmt.Errorf("error parsing unbonding delegation initial balance: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		row.InitialBalance = coin.NewIntFromBigInt(initialBalance)
+		balance, parseErr := balanceReader.Parse()
+		if parseErr != nil {
+			return nil, fmt.Errorf("error parsing unbonding delegation balance: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		row.Balance = coin.NewIntFromBigInt(balance)
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+type UnbondingDelegationRow struct {
+	Id               int64           `json:"-"`
+	DelegatorAddress string          `json:"delegatorAddress"`
+	ValidatorAddress string          `json:"validatorAddress"`
+	CreationHeight   int64           `json:"creationHeight"`
+	CompletionTime   utctime.UTCTime `json:"completionTime"`
+	InitialBalance   coin.Int        `json:"initialBalance"`
+	// The initial balance less the slashes since the creation
+	Balance              coin.Int `json:"balance"`
+	MaybeCompletedHeight *int64   `json:"completedHeight"`
+}
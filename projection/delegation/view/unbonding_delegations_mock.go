@@ -0,0 +1,50 @@
+package view
+
+import (
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/usecase/coin"
+	testify_mock "github.com/stretchr/testify/mock"
+)
+
+type MockUnbondingDelegationsView struct {
+	testify_mock.Mock
+}
+
+func (unbondingDelegationsView *MockUnbondingDelegationsView) Insert(row *UnbondingDelegationRow) error {
+	mockArgs := unbondingDelegationsView.Called(row)
+	return mockArgs.Error(0)
+}
+
+func (unbondingDelegationsView *MockUnbondingDelegationsView) CompleteMatured(
+	delegatorAddress string,
+	validatorAddress string,
+	blockTime utctime.UTCTime,
+	completedHeight int64,
+) ([]UnbondingDelegationRow, error) {
+	mockArgs := unbondingDelegationsView.Called(delegatorAddress, validatorAddress, blockTime, completedHeight)
+	rows, _ := mockArgs.Get(0).([]UnbondingDelegationRow)
+	return rows, mockArgs.Error(1)
+}
+
+func (unbondingDelegationsView *MockUnbondingDelegationsView) ListPendingByDelegatorAddress(
+	delegatorAddress string,
+) ([]UnbondingDelegationRow, error) {
+	mockArgs := unbondingDelegationsView.Called(delegatorAddress)
+	rows, _ := mockArgs.Get(0).([]UnbondingDelegationRow)
+	return rows, mockArgs.Error(1)
+}
+
+func (unbondingDelegationsView *MockUnbondingDelegationsView) ListSlashable(
+	validatorAddress string,
+	infractionHeight int64,
+	blockTime utctime.UTCTime,
+) ([]UnbondingDelegationRow, error) {
+	mockArgs := unbondingDelegationsView.Called(validatorAddress, infractionHeight, blockTime)
+	rows, _ := mockArgs.Get(0).([]UnbondingDelegationRow)
+	return rows, mockArgs.Error(1)
+}
+
+func (unbondingDelegationsView *MockUnbondingDelegationsView) UpdateBalance(id int64, balance coin.Int) error {
+	mockArgs := unbondingDelegationsView.Called(id, balance)
+	return mockArgs.Error(0)
+}
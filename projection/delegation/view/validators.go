@@ -0,0 +1,134 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/usecase/coin"
+)
+
+const PARAMS_TABLE_NAME = "view_delegation_params"
+const VALIDATORS_TABLE_NAME = "view_delegation_validators"
+
+type Validators interface {
+	Upsert(*ValidatorRow) error
+	FindBy(ValidatorIdentity) (*ValidatorRow, error)
+}
+
+type ValidatorsView struct {
+	rdb *rdb.Handle
+}
+
+func NewValidatorsView(handle *rdb.Handle) Validators {
+	return &ValidatorsView{
+		handle,
+	}
+}
+
+func (validatorsView *ValidatorsView) Upsert(row *ValidatorRow) error {
+	sql, sqlArgs, err := validatorsView.rdb.StmtBuilder.
+		Insert(VALIDATORS_TABLE_NAME).
+		Columns(
+			"operator_address",
+			"consensus_node_address",
+			"tokens",
+			"delegator_shares",
+		).
+		Values(
+			row.OperatorAddress,
+			row.ConsensusNodeAddress,
+			validatorsView.rdb.Bton(row.Tokens.BigInt()),
+			row.DelegatorShares.String(),
+		).
+		Suffix(
+			"ON CONFLICT(operator_address) DO UPDATE SET " +
+				"consensus_node_address = EXCLUDED.consensus_node_address, " +
+				"tokens = EXCLUDED.tokens, " +
+				"delegator_shares = EXCLUDED.delegator_shares",
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building delegation validator upsertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := validatorsView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error upserting delegation validator into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("error upserting delegation validator into the table: no rows upserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (validatorsView *ValidatorsView) FindBy(identity ValidatorIdentity) (*ValidatorRow, error) {
+	stmtBuilder := validatorsView.rdb.StmtBuilder.Select(
+		"operator_address",
+		"consensus_node_address",
+		"tokens",
+		"CAST(delegator_shares AS VARCHAR)",
+	).From(
+		VALIDATORS_TABLE_NAME,
+	)
+	if identity.MaybeOperatorAddress != nil {
+		stmtBuilder = stmtBuilder.Where("operator_address = ?", *identity.MaybeOperatorAddress)
+	}
+	if identity.MaybeConsensusNodeAddress != nil {
+		stmtBuilder = stmtBuilder.Where("consensus_node_address = ?", *identity.MaybeConsensusNodeAddress)
+	}
+
+	sql, sqlArgs, err := stmtBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building delegation validator selection sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	var row ValidatorRow
+	var delegatorShares string
+	tokensReader := validatorsView.rdb.NtobReader()
+	if err = validatorsView.rdb.QueryRow(sql, sqlArgs...).Scan(
+		&row.OperatorAddress,
+		&row.ConsensusNodeAddress,
+		tokensReader.ScannableArg(),
+		&delegatorShares,
+	); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, rdb.ErrNoRows
+		}
+		return nil, fmt.Errorf("error scanning delegation validator row: %v: %w", err, rdb.ErrQuery)
+	}
+
+	tokens, err := tokensReader.Parse()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing delegation validator tokens: %v: %w", err, rdb.ErrQuery)
+	}
+	row.Tokens = coin.NewIntFromBigInt(tokens)
+	if row.DelegatorShares, err = coin.NewDecFromStr(delegatorShares); err != nil {
+		return nil, fmt.Errorf("error parsing delegation validator delegator shares: %v: %w", err, rdb.ErrQuery)
+	}
+
+	return &row, nil
+}
+
+type ValidatorIdentity struct {
+	MaybeOperatorAddress      *string
+	MaybeConsensusNodeAddress *string
+}
+
+// ValidatorRow holds the tokens bonded to a validator and the total shares issued to its delegators, from which the
+// tokens of a delegation are worth its shares times Tokens over DelegatorShares.
+type ValidatorRow struct {
+	OperatorAddress      string
+	ConsensusNodeAddress string
+	Tokens               coin.Int
+	DelegatorShares      coin.Dec
+}
+
+// TokensFromShares returns the tokens the shares are worth, truncated as the staking module does on unbonding
+func (row *ValidatorRow) TokensFromShares(shares coin.Dec) coin.Int {
+	if row.DelegatorShares.IsZero() {
+		return coin.ZeroInt()
+	}
+	return shares.MulInt(row.Tokens).Quo(row.DelegatorShares).TruncateInt()
+}
@@ -0,0 +1,20 @@
+package view
+
+import (
+	testify_mock "github.com/stretchr/testify/mock"
+)
+
+type MockValidatorsView struct {
+	testify_mock.Mock
+}
+
+func (validatorsView *MockValidatorsView) Upsert(row *ValidatorRow) error {
+	mockArgs := validatorsView.Called(row)
+	return mockArgs.Error(0)
+}
+
+func (validatorsView *MockValidatorsView) FindBy(identity ValidatorIdentity) (*ValidatorRow, error) {
+	mockArgs := validatorsView.Called(identity)
+	row, _ := mockArgs.Get(0).(*ValidatorRow)
+	return row, mockArgs.Error(1)
+}
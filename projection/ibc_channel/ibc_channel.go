@@ -23,6 +23,7 @@ import (
 )
 
 var _ entity_projection.Projection = &IBCChannel{}
+var _ entity_projection.RollbackableProjection = &IBCChannel{}
 
 var (
 	NewIBCChannels               = ibc_channel_view.NewIBCChannelsView
@@ -30,6 +31,7 @@ var (
 	NewIBCConnections            = ibc_channel_view.NewIBCConnectionsView
 	NewIBCDenomHashMapping       = ibc_channel_view.NewIBCDenomHashMappingView
 	NewIBCChannelTraces          = ibc_channel_view.NewIBCChannelTraces
+	JournalViews                 = (*IBCChannel).JournalViews
 	UpdateLastHandledEventHeight = (*IBCChannel).UpdateLastHandledEventHeight
 )
 
@@ -108,6 +110,20 @@ func (projection *IBCChannel) OnInit() error {
 	return nil
 }
 
+// Rollback undoes the journaled changes of the channels, the clients, the connections and the denom
+// hashes, whose states are accumulated over the IBC messages. The message traces of the heights are
+// removed when they are enabled.
+func (projection *IBCChannel) Rollback(fromHeight int64, toHeight int64) error {
+	heightViewTables := []string{}
+	if projection.config.EnableTxMsgTrace {
+		heightViewTables = append(heightViewTables, "view_ibc_channel_traces")
+	}
+	if _, err := projection.RollbackViews(projection.rdbConn, fromHeight, heightViewTables); err != nil {
+		return fmt.Errorf("error rolling back IBC channel views: %w", err)
+	}
+	return nil
+}
+
 func (projection *IBCChannel) HandleEvents(height int64, events []event_entity.Event) error {
 	rdbTx, err := projection.rdbConn.Begin()
 	if err != nil {
@@ -122,6 +138,9 @@ func (projection *IBCChannel) HandleEvents(height int64, events []event_entity.E
 	}()
 
 	rdbTxHandle := rdbTx.ToHandle()
+	if err = JournalViews(projection, rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error journaling views: %v", err)
+	}
 
 	ibcChannelsView := NewIBCChannels(rdbTxHandle)
 	ibcClientsView := NewIBCClients(rdbTxHandle)
@@ -1536,6 +1536,10 @@ func TestIBCChannel_HandleEvents(t *testing.T) {
 		mockRDbConn.On("Begin").Return(mockTx, nil)
 		mocks := tc.MockFunc()
 
+		ibc_channel.JournalViews = func(_ *ibc_channel.IBCChannel, _ *rdb.Handle, _ int64) error {
+			return nil
+		}
+
 		projection := NewIBCChannelProjection(mockRDbConn)
 		err := projection.HandleEvents(1, tc.Events)
 		assert.NoError(t, err)
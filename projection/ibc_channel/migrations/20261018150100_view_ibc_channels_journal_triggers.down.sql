@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS view_ibc_channels_journal ON view_ibc_channels;
+DROP TRIGGER IF EXISTS view_ibc_clients_journal ON view_ibc_clients;
+DROP TRIGGER IF EXISTS view_ibc_connections_journal ON view_ibc_connections;
+DROP TRIGGER IF EXISTS view_ibc_denom_hash_mapping_journal ON view_ibc_denom_hash_mapping;
@@ -0,0 +1,11 @@
+CREATE TRIGGER view_ibc_channels_journal AFTER INSERT OR UPDATE OR DELETE ON view_ibc_channels
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_ibc_clients_journal AFTER INSERT OR UPDATE OR DELETE ON view_ibc_clients
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_ibc_connections_journal AFTER INSERT OR UPDATE OR DELETE ON view_ibc_connections
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_ibc_denom_hash_mapping_journal AFTER INSERT OR UPDATE OR DELETE ON view_ibc_denom_hash_mapping
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
@@ -16,11 +16,14 @@ import (
 )
 
 var _ projection_entity.Projection = &IBCChannelMessage{}
+var _ projection_entity.RebuildableProjection = &IBCChannelMessage{}
+var _ projection_entity.RollbackableProjection = &IBCChannelMessage{}
 
 var (
 	NewIBCChannelMessages        = view.NewIBCChannelMessagesView
 	NewIBCChannelMessagesTotal   = view.NewIBCChannelMessagesTotalView
 	UpdateLastHandledEventHeight = (*IBCChannelMessage).UpdateLastHandledEventHeight
+	RewindLastHandledEventHeight = (*IBCChannelMessage).RewindLastHandledEventHeight
 )
 
 type IBCChannelMessage struct {
@@ -76,6 +79,57 @@ func (projection *IBCChannelMessage) OnInit() error {
 	return nil
 }
 
+func (projection *IBCChannelMessage) Reset(fromHeight int64) error {
+	return projection.ResetViews(projection.rdbConn, fromHeight, []string{
+		"view_ibc_channel_messages",
+		"view_ibc_channel_messages_total",
+	})
+}
+
+func (projection *IBCChannelMessage) Rollback(fromHeight int64, toHeight int64) error {
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	ibcChannelMessagesView := NewIBCChannelMessages(rdbTxHandle)
+	ibcChannelMessagesTotalView := NewIBCChannelMessagesTotal(rdbTxHandle)
+
+	removals, err := ibcChannelMessagesView.DeleteAllByBlockHeightRange(fromHeight, toHeight)
+	if err != nil {
+		return fmt.Errorf("error deleting IBCChannelMessages: %v", err)
+	}
+
+	totalMap := make(map[string]int64)
+	for _, removal := range removals {
+		totalMap[fmt.Sprintf("%s:-", removal.ChannelID)] += 1
+		totalMap[fmt.Sprintf("%s:%s", removal.ChannelID, removal.MessageType)] += 1
+	}
+	for identity, total := range totalMap {
+		if err := ibcChannelMessagesTotalView.DecrementAll([]string{identity}, total); err != nil {
+			return fmt.Errorf("error decrementing total message of IBCChannel: %v", err)
+		}
+	}
+
+	if err := RewindLastHandledEventHeight(projection, rdbTxHandle, fromHeight); err != nil {
+		return fmt.Errorf("error rewinding last handled event height: %v", err)
+	}
+
+	if err := rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
+
 func (projection *IBCChannelMessage) HandleEvents(height int64, events []event_entity.Event) error {
 	rdbTx, err := projection.rdbConn.Begin()
 	if err != nil {
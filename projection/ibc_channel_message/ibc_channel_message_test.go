@@ -1018,3 +1018,50 @@ func TestIBCChannelMessage_HandleEvents(t *testing.T) {
 		fmt.Println(tc.Name, "Passed")
 	}
 }
+
+func TestIBCChannelMessage_Rollback(t *testing.T) {
+	mockRDbConn := NewMockRDbConn()
+	mockTx := NewMockRDbTx()
+	mockRDbConn.On("Begin").Return(mockTx, nil)
+
+	mockIBCChannelMessageView := &view.MockIBCChannelMessageView{}
+	mockIBCChannelMessageView.On("DeleteAllByBlockHeightRange", int64(10), int64(12)).Return(
+		[]view.IBCChannelMessageRemoval{
+			{ChannelID: "channel-0", MessageType: "/ibc.core.channel.v1.MsgRecvPacket"},
+			{ChannelID: "channel-0", MessageType: "/ibc.core.channel.v1.MsgRecvPacket"},
+			{ChannelID: "channel-0", MessageType: "/ibc.core.channel.v1.MsgAcknowledgement"},
+		},
+		nil,
+	)
+	ibc_channel_message.NewIBCChannelMessages = func(handle *rdb.Handle) view.IBCChannelMessages {
+		return mockIBCChannelMessageView
+	}
+
+	mockIBCChannelMessageTotalView := &view.MockIBCChannelMessageTotalView{}
+	mockIBCChannelMessageTotalView.
+		On("DecrementAll", []string{"channel-0:-"}, int64(3)).
+		Return(nil)
+	mockIBCChannelMessageTotalView.
+		On("DecrementAll", []string{"channel-0:/ibc.core.channel.v1.MsgRecvPacket"}, int64(2)).
+		Return(nil)
+	mockIBCChannelMessageTotalView.
+		On("DecrementAll", []string{"channel-0:/ibc.core.channel.v1.MsgAcknowledgement"}, int64(1)).
+		Return(nil)
+	ibc_channel_message.NewIBCChannelMessagesTotal = func(handle *rdb.Handle) view.IBCChannelMessagesTotal {
+		return mockIBCChannelMessageTotalView
+	}
+
+	rewoundHeight := int64(-1)
+	ibc_channel_message.RewindLastHandledEventHeight = func(_ *ibc_channel_message.IBCChannelMessage, _ *rdb.Handle, fromHeight int64) error {
+		rewoundHeight = fromHeight
+		return nil
+	}
+
+	projection := NewIBCChannelMessageProjection(mockRDbConn)
+	assert.NoError(t, projection.Rollback(10, 12))
+
+	assert.Equal(t, int64(10), rewoundHeight)
+	mockIBCChannelMessageView.AssertExpectations(t)
+	mockIBCChannelMessageTotalView.AssertExpectations(t)
+	mockTx.AssertCalled(t, "Commit")
+}
@@ -14,6 +14,7 @@ import (
 
 type IBCChannelMessages interface {
 	Insert(*IBCChannelMessageRow) error
+	DeleteAllByBlockHeightRange(fromHeight int64, toHeight int64) ([]IBCChannelMessageRemoval, error)
 	ListByChannelID(
 		channelID string,
 		order IBCChannelMessagesListOrder,
@@ -91,6 +92,39 @@ func (ibcChannelMessagesView *IBCChannelMessagesView) Insert(ibcChannelMessage *
 	return nil
 }
 
+// DeleteAllByBlockHeightRange removes all IBC channel messages with block height within
+// [fromHeight, toHeight] and returns the removed channel id and message type pairs
+func (ibcChannelMessagesView *IBCChannelMessagesView) DeleteAllByBlockHeightRange(
+	fromHeight int64,
+	toHeight int64,
+) ([]IBCChannelMessageRemoval, error) {
+	sql, sqlArgs, err := ibcChannelMessagesView.rdb.StmtBuilder.Delete(
+		"view_ibc_channel_messages",
+	).Where(
+		"block_height >= ? AND block_height <= ?", fromHeight, toHeight,
+	).Suffix("RETURNING channel_id, message_type").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building IBC channel messages deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := ibcChannelMessagesView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting IBC channel messages from the table: %v: %w", err, rdb.ErrWrite)
+	}
+	defer rowsResult.Close()
+
+	removals := make([]IBCChannelMessageRemoval, 0)
+	for rowsResult.Next() {
+		var removal IBCChannelMessageRemoval
+		if err = rowsResult.Scan(&removal.ChannelID, &removal.MessageType); err != nil {
+			return nil, fmt.Errorf("error scanning deleted IBC channel message: %v: %w", err, rdb.ErrQuery)
+		}
+		removals = append(removals, removal)
+	}
+
+	return removals, nil
+}
+
 func (ibcChannelMessagesView *IBCChannelMessagesView) ListByChannelID(
 	channelID string,
 	order IBCChannelMessagesListOrder,
@@ -217,6 +251,11 @@ type IBCChannelMessagesListFilter struct {
 	MaybeMsgTypes []string
 }
 
+type IBCChannelMessageRemoval struct {
+	ChannelID   string
+	MessageType string
+}
+
 type IBCChannelMessageRow struct {
 	ChannelID       string          `json:"channelId"`
 	BlockHeight     int64           `json:"blockHeight"`
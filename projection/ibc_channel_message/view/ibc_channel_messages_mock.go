@@ -15,6 +15,15 @@ func (ibcChannelMessagesView *MockIBCChannelMessageView) Insert(ibcChannelMessag
 	return mockArgs.Error(0)
 }
 
+func (ibcChannelMessagesView *MockIBCChannelMessageView) DeleteAllByBlockHeightRange(
+	fromHeight int64,
+	toHeight int64,
+) ([]IBCChannelMessageRemoval, error) {
+	mockArgs := ibcChannelMessagesView.Called(fromHeight, toHeight)
+	removals, _ := mockArgs.Get(0).([]IBCChannelMessageRemoval)
+	return removals, mockArgs.Error(1)
+}
+
 func (ibcChannelMessagesView *MockIBCChannelMessageView) ListByChannelID(
 	channelID string,
 	order IBCChannelMessagesListOrder,
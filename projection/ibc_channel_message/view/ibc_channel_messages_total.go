@@ -7,6 +7,7 @@ import (
 
 type IBCChannelMessagesTotal interface {
 	Increment(identity string, total int64) error
+	DecrementAll(identities []string, total int64) error
 	SumBy(identities []string) (int64, error)
 }
 
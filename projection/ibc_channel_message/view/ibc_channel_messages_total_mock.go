@@ -13,6 +13,11 @@ func (totalView *MockIBCChannelMessageTotalView) Increment(identity string, tota
 	return mockArgs.Error(0)
 }
 
+func (totalView *MockIBCChannelMessageTotalView) DecrementAll(identities []string, total int64) error {
+	mockArgs := totalView.Called(identities, total)
+	return mockArgs.Error(0)
+}
+
 func (totalView *MockIBCChannelMessageTotalView) SumBy(identities []string) (int64, error) {
 	mockArgs := totalView.Called(identities)
 	total, _ := mockArgs.Get(0).(int64)
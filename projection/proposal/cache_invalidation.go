@@ -0,0 +1,63 @@
+package proposal
+
+import (
+	event_entity "github.com/AstraProtocol/astra-indexing/entity/event"
+	"github.com/AstraProtocol/astra-indexing/projection/proposal/view"
+	event_usecase "github.com/AstraProtocol/astra-indexing/usecase/event"
+)
+
+// invalidateCache evicts the cached values of the proposals changed by the events of the height, once
+// they are committed. The cache errors are not fatal, the values expire eventually.
+func (projection *Proposal) invalidateCache(height int64, events []event_entity.Event) {
+	projection.invalidateProposalsCache(height, changedProposalIds(events))
+}
+
+// invalidateProposalsCache evicts the cached values of the proposals changed from the height
+func (projection *Proposal) invalidateProposalsCache(height int64, proposalIds []string) {
+	if len(proposalIds) == 0 {
+		return
+	}
+
+	tags := []string{view.PROPOSALS_CACHE_TAG}
+	for _, proposalId := range proposalIds {
+		tags = append(tags, view.ProposalCacheTag(proposalId))
+	}
+	if err := projection.cache.Invalidate(tags...); err != nil {
+		projection.logger.Errorf("error invalidating cache of proposals changed at height %d: %v", height, err)
+	}
+}
+
+func changedProposalIds(events []event_entity.Event) []string {
+	proposalIds := make([]string, 0)
+	for _, event := range events {
+		var maybeProposalId *string
+		switch typedEvent := event.(type) {
+		case *event_usecase.MsgSubmitTextProposal:
+			maybeProposalId = typedEvent.MaybeProposalId
+		case *event_usecase.MsgSubmitParamChangeProposal:
+			maybeProposalId = typedEvent.MaybeProposalId
+		case *event_usecase.MsgSubmitCommunityPoolSpendProposal:
+			maybeProposalId = typedEvent.MaybeProposalId
+		case *event_usecase.MsgSubmitSoftwareUpgradeProposal:
+			maybeProposalId = typedEvent.MaybeProposalId
+		case *event_usecase.MsgSubmitCancelSoftwareUpgradeProposal:
+			maybeProposalId = typedEvent.MaybeProposalId
+		case *event_usecase.MsgSubmitUnknownProposal:
+			maybeProposalId = typedEvent.MaybeProposalId
+		case *event_usecase.ProposalVotingPeriodStarted:
+			maybeProposalId = &typedEvent.ProposalId
+		case *event_usecase.ProposalInactived:
+			maybeProposalId = &typedEvent.ProposalId
+		case *event_usecase.ProposalEnded:
+			maybeProposalId = &typedEvent.ProposalId
+		case *event_usecase.MsgDeposit:
+			maybeProposalId = &typedEvent.ProposalId
+		case *event_usecase.MsgVote:
+			maybeProposalId = &typedEvent.ProposalId
+		}
+		if maybeProposalId != nil {
+			proposalIds = append(proposalIds, *maybeProposalId)
+		}
+	}
+	return proposalIds
+}
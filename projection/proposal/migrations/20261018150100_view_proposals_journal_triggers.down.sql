@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS view_proposals_journal ON view_proposals;
+DROP TRIGGER IF EXISTS view_proposal_params_journal ON view_proposal_params;
+DROP TRIGGER IF EXISTS view_proposal_validators_journal ON view_proposal_validators;
+DROP TRIGGER IF EXISTS view_proposal_depositors_journal ON view_proposal_depositors;
+DROP TRIGGER IF EXISTS view_proposal_depositors_total_journal ON view_proposal_depositors_total;
+DROP TRIGGER IF EXISTS view_proposal_votes_journal ON view_proposal_votes;
+DROP TRIGGER IF EXISTS view_proposal_votes_total_journal ON view_proposal_votes_total;
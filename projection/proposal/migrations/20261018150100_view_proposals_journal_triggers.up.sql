@@ -0,0 +1,20 @@
+CREATE TRIGGER view_proposals_journal AFTER INSERT OR UPDATE OR DELETE ON view_proposals
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_proposal_params_journal AFTER INSERT OR UPDATE OR DELETE ON view_proposal_params
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_proposal_validators_journal AFTER INSERT OR UPDATE OR DELETE ON view_proposal_validators
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_proposal_depositors_journal AFTER INSERT OR UPDATE OR DELETE ON view_proposal_depositors
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_proposal_depositors_total_journal AFTER INSERT OR UPDATE OR DELETE ON view_proposal_depositors_total
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_proposal_votes_journal AFTER INSERT OR UPDATE OR DELETE ON view_proposal_votes
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_proposal_votes_total_journal AFTER INSERT OR UPDATE OR DELETE ON view_proposal_votes_total
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
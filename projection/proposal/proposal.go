@@ -17,6 +17,7 @@ import (
 	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
 	event_entity "github.com/AstraProtocol/astra-indexing/entity/event"
 	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
+	"github.com/AstraProtocol/astra-indexing/external/cache"
 	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
 	"github.com/AstraProtocol/astra-indexing/external/utctime"
 	"github.com/AstraProtocol/astra-indexing/infrastructure/pg/migrationhelper"
@@ -26,6 +27,8 @@ import (
 )
 
 var _ projection_entity.Projection = &Proposal{}
+var _ projection_entity.RebuildableProjection = &Proposal{}
+var _ projection_entity.RollbackableProjection = &Proposal{}
 
 var (
 	NewProposals       = view.NewProposalsView
@@ -34,6 +37,7 @@ var (
 	NewDepositors      = view.NewDepositorsView
 	NewDepositorsTotal = view.NewDepositorsTotalView
 
+	JournalViews                 = (*Proposal).JournalViews
 	UpdateLastHandledEventHeight = (*Proposal).UpdateLastHandledEventHeight
 
 	ParamBaseHandleEvents     = (*rdbparambase.Base).HandleEvents
@@ -50,6 +54,7 @@ type Proposal struct {
 	rdbConn      rdb.Conn
 	cosmosClient cosmosapp_interface.Client
 	logger       applogger.Logger
+	cache        cache.Cache
 
 	conNodeAddressPrefix string
 
@@ -90,6 +95,7 @@ func NewProposal(
 		rdbConn,
 		cosmosClient,
 		logger,
+		cache.NewCache(),
 		conNodeAddressPrefix,
 
 		migrationHelper,
@@ -132,6 +138,34 @@ func (projection *Proposal) OnInit() error {
 	return nil
 }
 
+func (projection *Proposal) Reset(fromHeight int64) error {
+	return projection.ResetViews(projection.rdbConn, fromHeight, []string{
+		view.PROPOSALS_TABLE_NAME,
+		view.PARAMS_TABLE_NAME,
+		view.VALIDATORS_TABLE_NAME,
+		view.DEPOSITORS_TABLE_NAME,
+		view.DEPOSITORS_TOTAL_TABLE_NAME,
+		view.VOTES_TABLE_NAME,
+		view.VOTES_TOTAL_TABLE_NAME,
+	})
+}
+
+// Rollback undoes the journaled proposal changes, the tallies and the proposal states are fetched
+// from the node and cannot be derived back from the events
+func (projection *Proposal) Rollback(fromHeight int64, toHeight int64) error {
+	entries, err := projection.RollbackViews(projection.rdbConn, fromHeight, []string{})
+	if err != nil {
+		return fmt.Errorf("error rolling back proposal views: %w", err)
+	}
+
+	proposalIds := make([]string, 0)
+	for _, entry := range entries {
+		proposalIds = append(proposalIds, entry.Values("proposal_id")...)
+	}
+	projection.invalidateProposalsCache(fromHeight, proposalIds)
+	return nil
+}
+
 func (projection *Proposal) HandleEvents(height int64, events []event_entity.Event) error {
 	rdbTx, err := projection.rdbConn.Begin()
 	if err != nil {
@@ -146,6 +180,9 @@ func (projection *Proposal) HandleEvents(height int64, events []event_entity.Eve
 	}()
 
 	rdbTxHandle := rdbTx.ToHandle()
+	if err := JournalViews(projection, rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error journaling views: %v", err)
+	}
 
 	if err := ParamBaseHandleEvents(projection.paramBase, rdbTxHandle, projection.logger, events); err != nil {
 		return fmt.Errorf("error handling event in param base: %v", err)
@@ -733,6 +770,8 @@ func (projection *Proposal) HandleEvents(height int64, events []event_entity.Eve
 		return fmt.Errorf("error committing changes: %v", err)
 	}
 	committed = true
+
+	projection.invalidateCache(height, events)
 	return nil
 }
 
@@ -0,0 +1,11 @@
+package view
+
+// Tag of the cached lists of proposals, invalidated by the Proposal projection whenever a proposal
+// changes
+const PROPOSALS_CACHE_TAG = "proposals"
+
+// ProposalCacheTag returns the tag of the cached values of a proposal, including its votes and
+// depositors, invalidated by the Proposal projection when the proposal changes
+func ProposalCacheTag(proposalId string) string {
+	return "proposal_" + proposalId
+}
@@ -15,7 +15,7 @@ import (
 
 type ReportDashboard struct {
 	rdbHandle          *rdb.Handle
-	astraCache         *cache.AstraCache
+	astraCache         cache.Cache
 	rdbReportDashboard *rdbreportdashboard.RDbReportDashboard
 	config             *config.Config
 }
@@ -0,0 +1,17 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE view_search_entries (
+    entry_type VARCHAR NOT NULL,
+    entry_key VARCHAR NOT NULL,
+    field VARCHAR NOT NULL,
+    term VARCHAR NOT NULL,
+    fuzzy BOOLEAN NOT NULL,
+    label VARCHAR NOT NULL,
+    block_height BIGINT NOT NULL,
+    block_time BIGINT,
+    attributes JSONB NOT NULL,
+    PRIMARY KEY (entry_type, entry_key, field)
+);
+
+CREATE INDEX view_search_entries_term_btree_index ON view_search_entries USING btree (term text_pattern_ops);
+CREATE INDEX view_search_entries_term_trgm_index ON view_search_entries USING gin (term gin_trgm_ops) WHERE fuzzy;
@@ -0,0 +1,11 @@
+CREATE TABLE view_search_validator_monikers (
+    operator_address VARCHAR NOT NULL,
+    block_height BIGINT NOT NULL,
+    block_time BIGINT,
+    moniker VARCHAR NOT NULL,
+    PRIMARY KEY (operator_address, block_height)
+);
+
+INSERT INTO view_search_validator_monikers (operator_address, block_height, block_time, moniker)
+SELECT entry_key, block_height, block_time, label FROM view_search_entries
+WHERE entry_type = 'validator' AND field = 'moniker';
@@ -0,0 +1,302 @@
+package search
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbprojectionbase"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	event_entity "github.com/AstraProtocol/astra-indexing/entity/event"
+	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/external/tmcosmosutils"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/pg/migrationhelper"
+	"github.com/AstraProtocol/astra-indexing/projection/search/view"
+	"github.com/AstraProtocol/astra-indexing/projection/validator"
+	event_usecase "github.com/AstraProtocol/astra-indexing/usecase/event"
+)
+
+var _ projection_entity.Projection = &Search{}
+var _ projection_entity.RebuildableProjection = &Search{}
+var _ projection_entity.RollbackableProjection = &Search{}
+
+var (
+	NewSearchEntries             = view.NewSearchEntriesView
+	UpdateLastHandledEventHeight = (*Search).UpdateLastHandledEventHeight
+)
+
+// Search indexes the block hashes, the transaction hashes and the validator monikers and addresses for the search
+// endpoint. The token addresses, names and symbols are recorded by the token transfers Kafka consumer.
+type Search struct {
+	*rdbprojectionbase.Base
+
+	rdbConn rdb.Conn
+	logger  applogger.Logger
+
+	conNodeAddressPrefix string
+
+	migrationHelper migrationhelper.MigrationHelper
+}
+
+func NewSearch(
+	logger applogger.Logger,
+	rdbConn rdb.Conn,
+	conNodeAddressPrefix string,
+	migrationHelper migrationhelper.MigrationHelper,
+) *Search {
+	return &Search{
+		rdbprojectionbase.NewRDbBase(
+			rdbConn.ToHandle(),
+			"Search",
+		),
+
+		rdbConn,
+		logger,
+
+		conNodeAddressPrefix,
+
+		migrationHelper,
+	}
+}
+
+func (_ *Search) GetEventsToListen() []string {
+	return []string{
+		event_usecase.BLOCK_CREATED,
+		event_usecase.TRANSACTION_CREATED,
+		event_usecase.TRANSACTION_FAILED,
+		event_usecase.MSG_ETHEREUM_TX_CREATED,
+		event_usecase.GENESIS_VALIDATOR_CREATED,
+		event_usecase.MSG_CREATE_VALIDATOR_CREATED,
+		event_usecase.MSG_EDIT_VALIDATOR_CREATED,
+	}
+}
+
+func (projection *Search) OnInit() error {
+	if projection.migrationHelper != nil {
+		projection.migrationHelper.Migrate()
+	}
+
+	return nil
+}
+
+// Reset keeps the token entries, which are not indexed from the events. The entries are replaced by the latest
+// events, so only the genesis is supported.
+func (projection *Search) Reset(fromHeight int64) error {
+	if fromHeight != 0 {
+		return fmt.Errorf(
+			"error resetting search entries from height %d: %w", fromHeight, projection_entity.ErrProjectionNotRebuildableFromHeight,
+		)
+	}
+
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	if err = NewSearchEntries(rdbTxHandle).DeleteIndexed(); err != nil {
+		return fmt.Errorf("error deleting search entries: %v", err)
+	}
+	if err = projection.RewindLastHandledEventHeight(rdbTxHandle, fromHeight); err != nil {
+		return fmt.Errorf("error rewinding last handled event height: %v", err)
+	}
+
+	if err = rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+
+	return nil
+}
+
+func (projection *Search) Rollback(fromHeight int64, toHeight int64) error {
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	if err = NewSearchEntries(rdbTxHandle).DeleteIndexedByHeightRange(fromHeight, toHeight); err != nil {
+		return fmt.Errorf("error deleting search entries: %v", err)
+	}
+	if err = projection.RewindLastHandledEventHeight(rdbTxHandle, fromHeight); err != nil {
+		return fmt.Errorf("error rewinding last handled event height: %v", err)
+	}
+
+	if err = rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
+
+func (projection *Search) HandleEvents(height int64, events []event_entity.Event) error {
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+
+	searchEntriesView := NewSearchEntries(rdbTxHandle)
+
+	var blockTime utctime.UTCTime
+	for _, event := range events {
+		if blockCreatedEvent, ok := event.(*event_usecase.BlockCreated); ok {
+			blockTime = blockCreatedEvent.Block.Time
+		}
+	}
+
+	evmHashes := make(map[string]string)
+	for _, event := range events {
+		if msgEthereumTxEvent, ok := event.(*event_usecase.MsgEthereumTx); ok {
+			evmHashes[msgEthereumTxEvent.TxHash()] = msgEthereumTxEvent.Params.Hash
+		}
+	}
+
+	entries := make([]view.SearchEntryRow, 0)
+	newEntry := func(entryType string, key string, field string, term string, fuzzy bool, label string) view.SearchEntryRow {
+		return view.SearchEntryRow{
+			Type:           entryType,
+			Key:            key,
+			Field:          field,
+			Term:           term,
+			Fuzzy:          fuzzy,
+			Label:          label,
+			BlockHeight:    height,
+			MaybeBlockTime: &blockTime,
+			Attributes:     map[string]string{},
+		}
+	}
+	addTransaction := func(txHash string) {
+		entries = append(entries, newEntry(
+			view.SEARCH_ENTRY_TRANSACTION, txHash, view.SEARCH_FIELD_HASH, txHash, false, txHash,
+		))
+		if evmHash, ok := evmHashes[txHash]; ok && evmHash != "" {
+			evmHashEntry := newEntry(
+				view.SEARCH_ENTRY_TRANSACTION, txHash, view.SEARCH_FIELD_EVM_HASH, evmHash, false, txHash,
+			)
+			evmHashEntry.Attributes["evmHash"] = evmHash
+			// Every field of the entry carries the same attributes, whichever field is matched
+			entries[len(entries)-1].Attributes["evmHash"] = evmHash
+			entries = append(entries, evmHashEntry)
+		}
+	}
+	monikers := make([]view.ValidatorMonikerRow, 0)
+	newMoniker := func(operatorAddress string, moniker string) view.ValidatorMonikerRow {
+		return view.ValidatorMonikerRow{
+			OperatorAddress: operatorAddress,
+			BlockHeight:     height,
+			MaybeBlockTime:  &blockTime,
+			Moniker:         moniker,
+		}
+	}
+	addValidator := func(operatorAddress string, moniker string, tendermintPubkey string) error {
+		pubkey, err := base64.StdEncoding.DecodeString(tendermintPubkey)
+		if err != nil {
+			return fmt.Errorf("error base64 decoding Tendermint node pubkey: %v", err)
+		}
+		consensusNodeAddress, err := tmcosmosutils.ConsensusNodeAddressFromTmPubKey(
+			projection.conNodeAddressPrefix, pubkey,
+		)
+		if err != nil {
+			return fmt.Errorf("error converting Tendermint node pubkey to address: %v", err)
+		}
+
+		entries = append(entries,
+			newEntry(view.SEARCH_ENTRY_VALIDATOR, operatorAddress, view.SEARCH_FIELD_MONIKER, moniker, true, moniker),
+			newEntry(view.SEARCH_ENTRY_VALIDATOR, operatorAddress, view.SEARCH_FIELD_OPERATOR_ADDRESS, operatorAddress, false, moniker),
+			newEntry(view.SEARCH_ENTRY_VALIDATOR, operatorAddress, view.SEARCH_FIELD_CONSENSUS_NODE_ADDRESS, consensusNodeAddress, false, moniker),
+		)
+		monikers = append(monikers, newMoniker(operatorAddress, moniker))
+		return nil
+	}
+
+	editedMonikers := make(map[string]string)
+	editedOperatorAddresses := make([]string, 0)
+	for _, event := range events {
+		switch typedEvent := event.(type) {
+		case *event_usecase.BlockCreated:
+			entries = append(entries, newEntry(
+				view.SEARCH_ENTRY_BLOCK, fmt.Sprint(height), view.SEARCH_FIELD_HASH, typedEvent.Block.Hash, false, typedEvent.Block.Hash,
+			))
+		case *event_usecase.TransactionCreated:
+			addTransaction(typedEvent.TxHash)
+		case *event_usecase.TransactionFailed:
+			addTransaction(typedEvent.TxHash)
+		case *event_usecase.CreateGenesisValidator:
+			if err := addValidator(
+				typedEvent.ValidatorAddress, typedEvent.Description.Moniker, typedEvent.TendermintPubkey,
+			); err != nil {
+				return err
+			}
+		case *event_usecase.MsgCreateValidator:
+			if err := addValidator(
+				typedEvent.ValidatorAddress, typedEvent.Description.Moniker, typedEvent.TendermintPubkey,
+			); err != nil {
+				return err
+			}
+		case *event_usecase.MsgEditValidator:
+			if typedEvent.Description.Moniker == validator.DO_NOT_MODIFY {
+				continue
+			}
+			entries = append(entries, newEntry(
+				view.SEARCH_ENTRY_VALIDATOR, typedEvent.ValidatorAddress, view.SEARCH_FIELD_MONIKER,
+				typedEvent.Description.Moniker, true, typedEvent.Description.Moniker,
+			))
+			monikers = append(monikers, newMoniker(typedEvent.ValidatorAddress, typedEvent.Description.Moniker))
+			if _, exist := editedMonikers[typedEvent.ValidatorAddress]; !exist {
+				editedOperatorAddresses = append(editedOperatorAddresses, typedEvent.ValidatorAddress)
+			}
+			editedMonikers[typedEvent.ValidatorAddress] = typedEvent.Description.Moniker
+		}
+	}
+
+	if err := searchEntriesView.Upsert(entries); err != nil {
+		return fmt.Errorf("error upserting search entries: %v", err)
+	}
+	if err := searchEntriesView.UpsertValidatorMonikers(monikers); err != nil {
+		return fmt.Errorf("error upserting validator monikers: %v", err)
+	}
+	// The address fields of an edited validator are labelled with its new moniker
+	for _, operatorAddress := range editedOperatorAddresses {
+		if err := searchEntriesView.UpdateLabel(
+			view.SEARCH_ENTRY_VALIDATOR, operatorAddress, editedMonikers[operatorAddress],
+		); err != nil {
+			return fmt.Errorf("error updating validator search entries label: %v", err)
+		}
+	}
+
+	if err := UpdateLastHandledEventHeight(projection, rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error updating last handled event height: %v", err)
+	}
+
+	if err := rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+
+	return nil
+}
@@ -0,0 +1,283 @@
+package search_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	testify_mock "github.com/stretchr/testify/mock"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb/test"
+	entity_event "github.com/AstraProtocol/astra-indexing/entity/event"
+	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
+	"github.com/AstraProtocol/astra-indexing/external/tmcosmosutils"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/pg"
+	"github.com/AstraProtocol/astra-indexing/projection/search"
+	"github.com/AstraProtocol/astra-indexing/projection/search/view"
+	"github.com/AstraProtocol/astra-indexing/projection/validator"
+	usecase_event "github.com/AstraProtocol/astra-indexing/usecase/event"
+	"github.com/AstraProtocol/astra-indexing/usecase/model"
+)
+
+const CON_NODE_ADDRESS_PREFIX = "astravalcons"
+
+func NewSearchProjection(rdbConn rdb.Conn) *search.Search {
+	return search.NewSearch(
+		nil,
+		rdbConn,
+		CON_NODE_ADDRESS_PREFIX,
+		nil,
+	)
+}
+
+func NewMockRDbConn() *test.MockRDbConn {
+	mock := test.NewMockRDbConn()
+	mock.On("ToHandle").Return(&rdb.Handle{
+		Runner:   mock,
+		TypeConv: &pg.PgxTypeConv{},
+		StmtBuilder: &rdb.StatementBuilder{
+			StatementBuilderType: sq.StatementBuilderType{},
+			PlaceholderFormat:    nil,
+		},
+	})
+
+	return mock
+}
+
+func NewMockRDbTx() *test.MockRDbTx {
+	mockTx := &test.MockRDbTx{}
+	mockTx.On("ToHandle").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+
+	return mockTx
+}
+
+func newMsgBase(msgName string) usecase_event.MsgBase {
+	return usecase_event.NewMsgBase(usecase_event.MsgBaseParams{
+		MsgName: msgName,
+		Version: 1,
+		MsgCommonParams: usecase_event.MsgCommonParams{
+			BlockHeight: 1,
+			TxHash:      "TXHASH",
+			TxSuccess:   true,
+			MsgIndex:    0,
+		},
+	})
+}
+
+func TestSearch_HandleEvents(t *testing.T) {
+	blockTime := utctime.FromUnixNano(1000)
+	tendermintPubkey := base64.StdEncoding.EncodeToString(make([]byte, 32))
+	consensusNodeAddress, err := tmcosmosutils.ConsensusNodeAddressFromTmPubKey(CON_NODE_ADDRESS_PREFIX, make([]byte, 32))
+	assert.NoError(t, err)
+
+	newEntry := func(entryType string, key string, field string, term string, fuzzy bool, label string) view.SearchEntryRow {
+		return view.SearchEntryRow{
+			Type:           entryType,
+			Key:            key,
+			Field:          field,
+			Term:           term,
+			Fuzzy:          fuzzy,
+			Label:          label,
+			BlockHeight:    1,
+			MaybeBlockTime: &blockTime,
+			Attributes:     map[string]string{},
+		}
+	}
+
+	newMoniker := func(operatorAddress string, moniker string) view.ValidatorMonikerRow {
+		return view.ValidatorMonikerRow{
+			OperatorAddress: operatorAddress,
+			BlockHeight:     1,
+			MaybeBlockTime:  &blockTime,
+			Moniker:         moniker,
+		}
+	}
+
+	testCases := []struct {
+		Name     string
+		Events   []entity_event.Event
+		MockFunc func() []*testify_mock.Mock
+	}{
+		{
+			Name: "HandleBlockAndTransactions",
+			Events: []entity_event.Event{
+				&usecase_event.BlockCreated{
+					Block: &model.Block{
+						Height: 1,
+						Hash:   "BLOCKHASH",
+						Time:   blockTime,
+					},
+				},
+				&usecase_event.TransactionCreated{
+					TxHash: "TXHASH",
+				},
+				&usecase_event.TransactionFailed{
+					TxHash: "FAILEDTXHASH",
+				},
+				&usecase_event.MsgEthereumTx{
+					MsgBase: newMsgBase(usecase_event.MSG_ETHEREUM_TX),
+					Params: model.MsgEthereumTxParams{
+						RawMsgEthereumTx: model.RawMsgEthereumTx{
+							Hash: "0xevmhash",
+						},
+					},
+				},
+			},
+			MockFunc: func() (mocks []*testify_mock.Mock) {
+				txEntry := newEntry(view.SEARCH_ENTRY_TRANSACTION, "TXHASH", view.SEARCH_FIELD_HASH, "TXHASH", false, "TXHASH")
+				txEntry.Attributes["evmHash"] = "0xevmhash"
+				evmTxEntry := newEntry(view.SEARCH_ENTRY_TRANSACTION, "TXHASH", view.SEARCH_FIELD_EVM_HASH, "0xevmhash", false, "TXHASH")
+				evmTxEntry.Attributes["evmHash"] = "0xevmhash"
+
+				mockSearchEntriesView := &view.MockSearchEntriesView{}
+				mocks = append(mocks, &mockSearchEntriesView.Mock)
+				mockSearchEntriesView.
+					On("Upsert", []view.SearchEntryRow{
+						newEntry(view.SEARCH_ENTRY_BLOCK, "1", view.SEARCH_FIELD_HASH, "BLOCKHASH", false, "BLOCKHASH"),
+						txEntry,
+						evmTxEntry,
+						newEntry(view.SEARCH_ENTRY_TRANSACTION, "FAILEDTXHASH", view.SEARCH_FIELD_HASH, "FAILEDTXHASH", false, "FAILEDTXHASH"),
+					}).
+					Return(nil)
+				mockSearchEntriesView.
+					On("UpsertValidatorMonikers", []view.ValidatorMonikerRow{}).
+					Return(nil)
+
+				search.NewSearchEntries = func(handle *rdb.Handle) view.SearchEntries {
+					return mockSearchEntriesView
+				}
+
+				search.UpdateLastHandledEventHeight = func(_ *search.Search, _ *rdb.Handle, _ int64) error {
+					return nil
+				}
+
+				return mocks
+			},
+		},
+		{
+			Name: "HandleCreatedAndEditedValidator",
+			Events: []entity_event.Event{
+				&usecase_event.BlockCreated{
+					Block: &model.Block{
+						Height: 1,
+						Hash:   "BLOCKHASH",
+						Time:   blockTime,
+					},
+				},
+				&usecase_event.MsgCreateValidator{
+					MsgBase: newMsgBase(usecase_event.MSG_CREATE_VALIDATOR),
+					Description: model.ValidatorDescription{
+						Moniker: "Astra Validator",
+					},
+					ValidatorAddress: "astravaloper1operator",
+					TendermintPubkey: tendermintPubkey,
+				},
+				&usecase_event.MsgEditValidator{
+					MsgBase: newMsgBase(usecase_event.MSG_EDIT_VALIDATOR),
+					Description: model.ValidatorDescription{
+						Moniker: "Astra Node",
+					},
+					ValidatorAddress: "astravaloper1operator",
+				},
+				&usecase_event.MsgEditValidator{
+					MsgBase: newMsgBase(usecase_event.MSG_EDIT_VALIDATOR),
+					Description: model.ValidatorDescription{
+						Moniker: validator.DO_NOT_MODIFY,
+					},
+					ValidatorAddress: "astravaloper1other",
+				},
+			},
+			MockFunc: func() (mocks []*testify_mock.Mock) {
+				mockSearchEntriesView := &view.MockSearchEntriesView{}
+				mocks = append(mocks, &mockSearchEntriesView.Mock)
+				mockSearchEntriesView.
+					On("Upsert", []view.SearchEntryRow{
+						newEntry(view.SEARCH_ENTRY_BLOCK, "1", view.SEARCH_FIELD_HASH, "BLOCKHASH", false, "BLOCKHASH"),
+						newEntry(view.SEARCH_ENTRY_VALIDATOR, "astravaloper1operator", view.SEARCH_FIELD_MONIKER, "Astra Validator", true, "Astra Validator"),
+						newEntry(view.SEARCH_ENTRY_VALIDATOR, "astravaloper1operator", view.SEARCH_FIELD_OPERATOR_ADDRESS, "astravaloper1operator", false, "Astra Validator"),
+						newEntry(view.SEARCH_ENTRY_VALIDATOR, "astravaloper1operator", view.SEARCH_FIELD_CONSENSUS_NODE_ADDRESS, consensusNodeAddress, false, "Astra Validator"),
+						newEntry(view.SEARCH_ENTRY_VALIDATOR, "astravaloper1operator", view.SEARCH_FIELD_MONIKER, "Astra Node", true, "Astra Node"),
+					}).
+					Return(nil)
+				mockSearchEntriesView.
+					On("UpsertValidatorMonikers", []view.ValidatorMonikerRow{
+						newMoniker("astravaloper1operator", "Astra Validator"),
+						newMoniker("astravaloper1operator", "Astra Node"),
+					}).
+					Return(nil)
+				mockSearchEntriesView.
+					On("UpdateLabel", view.SEARCH_ENTRY_VALIDATOR, "astravaloper1operator", "Astra Node").
+					Return(nil)
+
+				search.NewSearchEntries = func(handle *rdb.Handle) view.SearchEntries {
+					return mockSearchEntriesView
+				}
+
+				search.UpdateLastHandledEventHeight = func(_ *search.Search, _ *rdb.Handle, _ int64) error {
+					return nil
+				}
+
+				return mocks
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		mockRDbConn := NewMockRDbConn()
+		mockTx := NewMockRDbTx()
+		mockRDbConn.On("Begin").Return(mockTx, nil)
+
+		mocks := tc.MockFunc()
+		mocks = append(mocks, &mockRDbConn.Mock)
+		mocks = append(mocks, &mockTx.Mock)
+
+		projection := NewSearchProjection(mockRDbConn)
+		err := projection.HandleEvents(1, tc.Events)
+		assert.NoError(t, err)
+
+		for _, m := range mocks {
+			m.AssertExpectations(t)
+		}
+
+		fmt.Println(tc.Name, "Passed")
+	}
+}
+
+func TestSearch_ResetFromHeight(t *testing.T) {
+	mockRDbConn := NewMockRDbConn()
+
+	projection := NewSearchProjection(mockRDbConn)
+	err := projection.Reset(10)
+	assert.ErrorIs(t, err, projection_entity.ErrProjectionNotRebuildableFromHeight)
+
+	mockRDbConn.AssertNotCalled(t, "Begin")
+}
+
+func TestNewTokenSearchEntries(t *testing.T) {
+	entries := search.NewTokenSearchEntries("0xABC", "Astra Token", "ASA", 10)
+
+	assert.Equal(t, []view.SearchEntryRow{
+		{
+			Type: view.SEARCH_ENTRY_TOKEN, Key: "0xabc", Field: view.SEARCH_FIELD_ADDRESS, Term: "0xabc",
+			Label: "Astra Token", BlockHeight: 10, Attributes: map[string]string{"symbol": "ASA"},
+		},
+		{
+			Type: view.SEARCH_ENTRY_TOKEN, Key: "0xabc", Field: view.SEARCH_FIELD_NAME, Term: "Astra Token", Fuzzy: true,
+			Label: "Astra Token", BlockHeight: 10, Attributes: map[string]string{"symbol": "ASA"},
+		},
+		{
+			Type: view.SEARCH_ENTRY_TOKEN, Key: "0xabc", Field: view.SEARCH_FIELD_SYMBOL, Term: "ASA", Fuzzy: true,
+			Label: "Astra Token", BlockHeight: 10, Attributes: map[string]string{"symbol": "ASA"},
+		},
+	}, entries)
+
+	entries = search.NewTokenSearchEntries("0xabc", "", "", 10)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "0xabc", entries[0].Label)
+}
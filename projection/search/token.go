@@ -0,0 +1,46 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/AstraProtocol/astra-indexing/projection/search/view"
+)
+
+// NewTokenSearchEntries indexes a token by its address, name and symbol. The token entries are recorded from the
+// token transfers Kafka topic, they are kept when the projection is rolled back or reset.
+func NewTokenSearchEntries(addressHash string, name string, symbol string, blockHeight int64) []view.SearchEntryRow {
+	addressHash = strings.ToLower(addressHash)
+	label := name
+	if label == "" {
+		label = symbol
+	}
+	if label == "" {
+		label = addressHash
+	}
+	attributes := map[string]string{
+		"symbol": symbol,
+	}
+	newEntry := func(field string, term string, fuzzy bool) view.SearchEntryRow {
+		return view.SearchEntryRow{
+			Type:        view.SEARCH_ENTRY_TOKEN,
+			Key:         addressHash,
+			Field:       field,
+			Term:        term,
+			Fuzzy:       fuzzy,
+			Label:       label,
+			BlockHeight: blockHeight,
+			Attributes:  attributes,
+		}
+	}
+
+	entries := []view.SearchEntryRow{
+		newEntry(view.SEARCH_FIELD_ADDRESS, addressHash, false),
+	}
+	if name != "" {
+		entries = append(entries, newEntry(view.SEARCH_FIELD_NAME, name, true))
+	}
+	if symbol != "" {
+		entries = append(entries, newEntry(view.SEARCH_FIELD_SYMBOL, symbol, true))
+	}
+	return entries
+}
@@ -0,0 +1,503 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	sq "github.com/Masterminds/squirrel"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	SEARCH_ENTRY_VALIDATOR   = "validator"
+	SEARCH_ENTRY_TRANSACTION = "transaction"
+	SEARCH_ENTRY_BLOCK       = "block"
+	SEARCH_ENTRY_TOKEN       = "token"
+)
+
+const (
+	SEARCH_FIELD_MONIKER                = "moniker"
+	SEARCH_FIELD_OPERATOR_ADDRESS       = "operator_address"
+	SEARCH_FIELD_CONSENSUS_NODE_ADDRESS = "consensus_node_address"
+	SEARCH_FIELD_HASH                   = "hash"
+	SEARCH_FIELD_EVM_HASH               = "evm_hash"
+	SEARCH_FIELD_NAME                   = "name"
+	SEARCH_FIELD_SYMBOL                 = "symbol"
+	SEARCH_FIELD_ADDRESS                = "address"
+)
+
+// Entry types ranked first when a keyword matches entries of different types equally well
+var searchEntryTypeRanks = []string{
+	SEARCH_ENTRY_VALIDATOR,
+	SEARCH_ENTRY_TOKEN,
+	SEARCH_ENTRY_TRANSACTION,
+	SEARCH_ENTRY_BLOCK,
+}
+
+type SearchEntries interface {
+	Upsert([]SearchEntryRow) error
+	UpdateLabel(entryType string, entryKey string, label string) error
+	UpsertValidatorMonikers([]ValidatorMonikerRow) error
+	DeleteIndexed() error
+	DeleteIndexedByHeightRange(fromHeight int64, toHeight int64) error
+	FindKeys(entryType string, keys []string) ([]string, error)
+	Search(keyword string, limit uint64) ([]SearchEntryRow, error)
+}
+
+type SearchEntriesView struct {
+	rdb *rdb.Handle
+}
+
+func NewSearchEntriesView(handle *rdb.Handle) SearchEntries {
+	return &SearchEntriesView{
+		handle,
+	}
+}
+
+// Upsert inserts the search entries, or updates them when an entry of the same type, key and field exists.
+// Rows repeating the same type, key and field are collapsed to the last one.
+func (searchEntriesView *SearchEntriesView) Upsert(rows []SearchEntryRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	stmtBuilder := searchEntriesView.rdb.StmtBuilder.
+		Insert("view_search_entries").
+		Columns(
+			"entry_type",
+			"entry_key",
+			"field",
+			"term",
+			"fuzzy",
+			"label",
+			"block_height",
+			"block_time",
+			"attributes",
+		)
+
+	lastIndexes := make(map[string]int, len(rows))
+	for i, row := range rows {
+		lastIndexes[row.Type+"/"+row.Key+"/"+row.Field] = i
+	}
+	insertedCount := int64(0)
+	for i, row := range rows {
+		if lastIndexes[row.Type+"/"+row.Key+"/"+row.Field] != i {
+			continue
+		}
+
+		attributes := row.Attributes
+		if attributes == nil {
+			attributes = map[string]string{}
+		}
+		attributesJSON, err := jsoniter.MarshalToString(attributes)
+		if err != nil {
+			return fmt.Errorf("error JSON marshalling search entry attributes for insertion: %v: %w", err, rdb.ErrBuildSQLStmt)
+		}
+
+		stmtBuilder = stmtBuilder.Values(
+			row.Type,
+			row.Key,
+			row.Field,
+			strings.ToLower(row.Term),
+			row.Fuzzy,
+			row.Label,
+			row.BlockHeight,
+			searchEntriesView.rdb.Tton(row.MaybeBlockTime),
+			attributesJSON,
+		)
+		insertedCount += 1
+	}
+
+	sql, sqlArgs, err := stmtBuilder.Suffix(
+		"ON CONFLICT(entry_type, entry_key, field) DO UPDATE SET " +
+			"term = EXCLUDED.term, fuzzy = EXCLUDED.fuzzy, label = EXCLUDED.label, " +
+			"block_height = EXCLUDED.block_height, block_time = EXCLUDED.block_time, attributes = EXCLUDED.attributes",
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building search entries upsertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := searchEntriesView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error upserting search entries into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != insertedCount {
+		return fmt.Errorf("error upserting search entries into the table: mismatched number of rows upserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+// UpdateLabel updates the label of every field of an entry
+func (searchEntriesView *SearchEntriesView) UpdateLabel(entryType string, entryKey string, label string) error {
+	sql, sqlArgs, err := searchEntriesView.rdb.StmtBuilder.
+		Update("view_search_entries").
+		Set("label", label).
+		Where("entry_type = ? AND entry_key = ?", entryType, entryKey).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building search entries label update sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	if _, err = searchEntriesView.rdb.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error updating search entries label: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+// UpsertValidatorMonikers records the monikers of the validators at the heights they are set, from which the moniker
+// entries are restored when the heights of the edits are rolled back. Rows repeating the same validator and height are
+// collapsed to the last one.
+func (searchEntriesView *SearchEntriesView) UpsertValidatorMonikers(rows []ValidatorMonikerRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	stmtBuilder := searchEntriesView.rdb.StmtBuilder.
+		Insert("view_search_validator_monikers").
+		Columns(
+			"operator_address",
+			"block_height",
+			"block_time",
+			"moniker",
+		)
+
+	lastIndexes := make(map[string]int, len(rows))
+	for i, row := range rows {
+		lastIndexes[fmt.Sprintf("%s/%d", row.OperatorAddress, row.BlockHeight)] = i
+	}
+	insertedCount := int64(0)
+	for i, row := range rows {
+		if lastIndexes[fmt.Sprintf("%s/%d", row.OperatorAddress, row.BlockHeight)] != i {
+			continue
+		}
+
+		stmtBuilder = stmtBuilder.Values(
+			row.OperatorAddress,
+			row.BlockHeight,
+			searchEntriesView.rdb.Tton(row.MaybeBlockTime),
+			row.Moniker,
+		)
+		insertedCount += 1
+	}
+
+	sql, sqlArgs, err := stmtBuilder.Suffix(
+		"ON CONFLICT(operator_address, block_height) DO UPDATE SET " +
+			"block_time = EXCLUDED.block_time, moniker = EXCLUDED.moniker",
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building validator monikers upsertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := searchEntriesView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error upserting validator monikers into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != insertedCount {
+		return fmt.Errorf("error upserting validator monikers into the table: mismatched number of rows upserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+// DeleteIndexed deletes the entries indexed from the events, keeping the token entries which are recorded from the
+// token transfers Kafka topic.
+func (searchEntriesView *SearchEntriesView) DeleteIndexed() error {
+	sql, sqlArgs, err := searchEntriesView.rdb.StmtBuilder.
+		Delete("view_search_entries").
+		Where("entry_type <> ?", SEARCH_ENTRY_TOKEN).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building search entries deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	if _, err = searchEntriesView.rdb.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error deleting search entries: %v: %w", err, rdb.ErrWrite)
+	}
+
+	if _, err = searchEntriesView.rdb.Exec("DELETE FROM view_search_validator_monikers"); err != nil {
+		return fmt.Errorf("error deleting validator monikers: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+// DeleteIndexedByHeightRange deletes the entries indexed from the events within [fromHeight, toHeight]. The
+// validators created before the range and edited within it get back their moniker from before the range.
+func (searchEntriesView *SearchEntriesView) DeleteIndexedByHeightRange(fromHeight int64, toHeight int64) error {
+	sql, sqlArgs, err := searchEntriesView.rdb.StmtBuilder.Delete(
+		"view_search_validator_monikers",
+	).Where(
+		"block_height >= ? AND block_height <= ?", fromHeight, toHeight,
+	).Suffix("RETURNING operator_address").ToSql()
+	if err != nil {
+		return fmt.Errorf("error building validator monikers deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := searchEntriesView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error deleting validator monikers from the table: %v: %w", err, rdb.ErrWrite)
+	}
+	editedOperatorAddresses := make([]string, 0)
+	for rowsResult.Next() {
+		var operatorAddress string
+		if err = rowsResult.Scan(&operatorAddress); err != nil {
+			rowsResult.Close()
+			return fmt.Errorf("error scanning deleted validator moniker: %v: %w", err, rdb.ErrQuery)
+		}
+		editedOperatorAddresses = append(editedOperatorAddresses, operatorAddress)
+	}
+	rowsResult.Close()
+
+	sql, sqlArgs, err = searchEntriesView.rdb.StmtBuilder.Delete(
+		"view_search_entries",
+	).Where(
+		"entry_type <> ? AND block_height >= ? AND block_height <= ?", SEARCH_ENTRY_TOKEN, fromHeight, toHeight,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building search entries deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+	if _, err = searchEntriesView.rdb.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error deleting search entries: %v: %w", err, rdb.ErrWrite)
+	}
+
+	if len(editedOperatorAddresses) == 0 {
+		return nil
+	}
+	monikers, err := searchEntriesView.lastValidatorMonikers(editedOperatorAddresses)
+	if err != nil {
+		return err
+	}
+	entries := make([]SearchEntryRow, 0, len(monikers))
+	for _, moniker := range monikers {
+		entries = append(entries, SearchEntryRow{
+			Type:           SEARCH_ENTRY_VALIDATOR,
+			Key:            moniker.OperatorAddress,
+			Field:          SEARCH_FIELD_MONIKER,
+			Term:           moniker.Moniker,
+			Fuzzy:          true,
+			Label:          moniker.Moniker,
+			BlockHeight:    moniker.BlockHeight,
+			MaybeBlockTime: moniker.MaybeBlockTime,
+			Attributes:     map[string]string{},
+		})
+	}
+	if err = searchEntriesView.Upsert(entries); err != nil {
+		return err
+	}
+	for _, moniker := range monikers {
+		if err = searchEntriesView.UpdateLabel(SEARCH_ENTRY_VALIDATOR, moniker.OperatorAddress, moniker.Moniker); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindKeys returns the keys having entries of the type among the given keys
+func (searchEntriesView *SearchEntriesView) FindKeys(entryType string, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return []string{}, nil
+	}
+
+	sql, sqlArgs, err := searchEntriesView.rdb.StmtBuilder.Select(
+		"DISTINCT entry_key",
+	).From(
+		"view_search_entries",
+	).Where(
+		sq.Eq{"entry_type": entryType, "entry_key": keys},
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building search entry keys select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := searchEntriesView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing search entry keys select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	foundKeys := make([]string, 0)
+	for rowsResult.Next() {
+		var key string
+		if err = rowsResult.Scan(&key); err != nil {
+			return nil, fmt.Errorf("error scanning search entry key: %v: %w", err, rdb.ErrQuery)
+		}
+		foundKeys = append(foundKeys, key)
+	}
+
+	return foundKeys, nil
+}
+
+// lastValidatorMonikers returns the last recorded moniker of every validator having one
+func (searchEntriesView *SearchEntriesView) lastValidatorMonikers(operatorAddresses []string) ([]ValidatorMonikerRow, error) {
+	sql, sqlArgs, err := searchEntriesView.rdb.StmtBuilder.Select(
+		"DISTINCT ON (operator_address) operator_address",
+		"block_height",
+		"block_time",
+		"moniker",
+	).From(
+		"view_search_validator_monikers",
+	).Where(
+		sq.Eq{"operator_address": operatorAddresses},
+	).OrderBy(
+		"operator_address", "block_height DESC",
+	).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building validator monikers select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := searchEntriesView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing validator monikers select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	monikers := make([]ValidatorMonikerRow, 0)
+	for rowsResult.Next() {
+		var moniker ValidatorMonikerRow
+		blockTimeReader := searchEntriesView.rdb.NtotReader()
+		if err = rowsResult.Scan(
+			&moniker.OperatorAddress,
+			&moniker.BlockHeight,
+			blockTimeReader.ScannableArg(),
+			&moniker.Moniker,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning validator moniker row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		blockTime, parseErr := blockTimeReader.Parse()
+		if parseErr != nil {
+			return nil, fmt.Errorf("error parsing validator moniker block time: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		moniker.MaybeBlockTime = blockTime
+
+		monikers = append(monikers, moniker)
+	}
+
+	return monikers, nil
+}
+
+// Search returns up to `limit` entries whose term starts with the keyword, or is similar to it for the fuzzy
+// fields, one row per entry. Exact matches come first, then prefix matches, then fuzzy matches. Entries matching
+// equally well are ranked by type, then by similarity.
+func (searchEntriesView *SearchEntriesView) Search(keyword string, limit uint64) ([]SearchEntryRow, error) {
+	term := strings.ToLower(strings.TrimSpace(keyword))
+	if term == "" {
+		return []SearchEntryRow{}, nil
+	}
+	prefixPattern := rdb.EscapeLikePattern(term) + "%"
+
+	typeRank := "CASE entry_type"
+	typeRankArgs := make([]interface{}, 0, len(searchEntryTypeRanks))
+	for i, entryType := range searchEntryTypeRanks {
+		typeRank += fmt.Sprintf(" WHEN ? THEN %d", i)
+		typeRankArgs = append(typeRankArgs, entryType)
+	}
+	typeRank += fmt.Sprintf(" ELSE %d END AS type_rank", len(searchEntryTypeRanks))
+
+	// DISTINCT ON keeps the best matching field of every entry
+	matchesStmtBuilder := sq.Select(
+		"DISTINCT ON (entry_type, entry_key) entry_type",
+		"entry_key",
+		"field",
+		"label",
+		"block_height",
+		"block_time",
+		"attributes",
+	).Column(
+		"CASE WHEN term = ? THEN 0 WHEN term LIKE ? THEN 1 ELSE 2 END AS match_rank", term, prefixPattern,
+	).Column(
+		typeRank, typeRankArgs...,
+	).Column(
+		"similarity(term, ?) AS score", term,
+	).From(
+		"view_search_entries",
+	).Where(sq.Or{
+		sq.Expr("term LIKE ?", prefixPattern),
+		sq.Expr("(fuzzy AND term % ?)", term),
+	}).OrderBy(
+		"entry_type", "entry_key", "match_rank", "score DESC",
+	)
+
+	sql, sqlArgs, err := searchEntriesView.rdb.StmtBuilder.Select(
+		"entry_type",
+		"entry_key",
+		"field",
+		"label",
+		"block_height",
+		"block_time",
+		"attributes",
+	).FromSelect(
+		matchesStmtBuilder, "matches",
+	).OrderBy(
+		"match_rank", "type_rank", "score DESC", "block_height DESC",
+	).Limit(limit).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building search entries select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := searchEntriesView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing search entries select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	entries := make([]SearchEntryRow, 0)
+	for rowsResult.Next() {
+		var entry SearchEntryRow
+		var attributesJSON string
+		blockTimeReader := searchEntriesView.rdb.NtotReader()
+		if err = rowsResult.Scan(
+			&entry.Type,
+			&entry.Key,
+			&entry.Field,
+			&entry.Label,
+			&entry.BlockHeight,
+			blockTimeReader.ScannableArg(),
+			&attributesJSON,
+		); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				return nil, rdb.ErrNoRows
+			}
+			return nil, fmt.Errorf("error scanning search entry row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		blockTime, parseErr := blockTimeReader.Parse()
+		if parseErr != nil {
+			return nil, fmt.Errorf("error parsing search entry block time: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		entry.MaybeBlockTime = blockTime
+
+		if unmarshalErr := jsoniter.UnmarshalFromString(attributesJSON, &entry.Attributes); unmarshalErr != nil {
+			return nil, fmt.Errorf("error unmarshalling search entry attributes JSON: %v: %w", unmarshalErr, rdb.ErrQuery)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+type SearchEntryRow struct {
+	Type           string            `json:"type"`
+	Key            string            `json:"key"`
+	Field          string            `json:"field"`
+	Term           string            `json:"-"`
+	Fuzzy          bool              `json:"-"`
+	Label          string            `json:"label"`
+	BlockHeight    int64             `json:"blockHeight"`
+	MaybeBlockTime *utctime.UTCTime  `json:"blockTime"`
+	Attributes     map[string]string `json:"attributes"`
+}
+
+type ValidatorMonikerRow struct {
+	OperatorAddress string
+	BlockHeight     int64
+	MaybeBlockTime  *utctime.UTCTime
+	Moniker         string
+}
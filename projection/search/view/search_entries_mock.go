@@ -0,0 +1,46 @@
+package view
+
+import (
+	testify_mock "github.com/stretchr/testify/mock"
+)
+
+type MockSearchEntriesView struct {
+	testify_mock.Mock
+}
+
+func (searchEntriesView *MockSearchEntriesView) Upsert(rows []SearchEntryRow) error {
+	mockArgs := searchEntriesView.Called(rows)
+	return mockArgs.Error(0)
+}
+
+func (searchEntriesView *MockSearchEntriesView) UpdateLabel(entryType string, entryKey string, label string) error {
+	mockArgs := searchEntriesView.Called(entryType, entryKey, label)
+	return mockArgs.Error(0)
+}
+
+func (searchEntriesView *MockSearchEntriesView) UpsertValidatorMonikers(rows []ValidatorMonikerRow) error {
+	mockArgs := searchEntriesView.Called(rows)
+	return mockArgs.Error(0)
+}
+
+func (searchEntriesView *MockSearchEntriesView) DeleteIndexed() error {
+	mockArgs := searchEntriesView.Called()
+	return mockArgs.Error(0)
+}
+
+func (searchEntriesView *MockSearchEntriesView) DeleteIndexedByHeightRange(fromHeight int64, toHeight int64) error {
+	mockArgs := searchEntriesView.Called(fromHeight, toHeight)
+	return mockArgs.Error(0)
+}
+
+func (searchEntriesView *MockSearchEntriesView) FindKeys(entryType string, keys []string) ([]string, error) {
+	mockArgs := searchEntriesView.Called(entryType, keys)
+	foundKeys, _ := mockArgs.Get(0).([]string)
+	return foundKeys, mockArgs.Error(1)
+}
+
+func (searchEntriesView *MockSearchEntriesView) Search(keyword string, limit uint64) ([]SearchEntryRow, error) {
+	mockArgs := searchEntriesView.Called(keyword, limit)
+	entries, _ := mockArgs.Get(0).([]SearchEntryRow)
+	return entries, mockArgs.Error(1)
+}
@@ -20,7 +20,8 @@ import (
 	event_usecase "github.com/AstraProtocol/astra-indexing/usecase/event"
 )
 
-var _ projection_entity.Projection = &Transaction{}
+var _ projection_entity.RollbackableProjection = &Transaction{}
+var _ projection_entity.RebuildableProjection = &Transaction{}
 
 var (
 	NewTransactions              = transaction_view.NewTransactionsView
@@ -78,6 +79,13 @@ func (projection *Transaction) OnInit() error {
 	return nil
 }
 
+func (projection *Transaction) Reset(fromHeight int64) error {
+	return projection.ResetViews(projection.rdbConn, fromHeight, []string{
+		"view_transactions",
+		"view_transactions_total",
+	})
+}
+
 func (projection *Transaction) HandleEvents(height int64, events []event_entity.Event) error {
 	rdbTx, err := projection.rdbConn.Begin()
 	if err != nil {
@@ -265,3 +273,44 @@ func (projection *Transaction) HandleEvents(height int64, events []event_entity.
 	committed = true
 	return nil
 }
+
+func (projection *Transaction) Rollback(fromHeight int64, toHeight int64) error {
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	transactionsView := NewTransactions(rdbTxHandle)
+	transactionsTotalView := NewTransactionsTotal(rdbTxHandle)
+
+	deletedTxs, err := transactionsView.DeleteAllByBlockHeightRange(fromHeight, toHeight)
+	if err != nil {
+		return fmt.Errorf("error deleting transactions from view: %v", err)
+	}
+	if deletedTxs > 0 {
+		if err := transactionsTotalView.DecrementAll([]string{"-"}, deletedTxs); err != nil {
+			return fmt.Errorf("error decrementing total transactions: %w", err)
+		}
+	}
+	if err := transactionsTotalView.DeleteHeightsFrom(fromHeight); err != nil {
+		return fmt.Errorf("error deleting total block transactions: %w", err)
+	}
+
+	if err := projection.RewindLastHandledEventHeight(rdbTxHandle, fromHeight); err != nil {
+		return fmt.Errorf("error rewinding last handled event height: %v", err)
+	}
+
+	if err := rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+	return nil
+}
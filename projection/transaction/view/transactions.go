@@ -32,6 +32,7 @@ type BlockTransactions interface {
 	Search(keyword string) ([]TransactionRow, error)
 	Count() (int64, error)
 	UpdateAll([]map[string]interface{}) error
+	DeleteAllByBlockHeightRange(fromHeight int64, toHeight int64) (int64, error)
 }
 
 // BlockTransactions projection view implemented by relational database
@@ -467,16 +468,11 @@ func (transactionsView *BlockTransactionsView) List(
 		"timeout_height",
 		"messages",
 		"signers",
+		"id",
 	).From(
 		"view_transactions",
 	)
 
-	if order.Height == view.ORDER_DESC {
-		stmtBuilder = stmtBuilder.OrderBy("block_height DESC, id")
-	} else {
-		stmtBuilder = stmtBuilder.OrderBy("block_height, id")
-	}
-
 	if filter.MaybeBlockHeight != nil {
 		stmtBuilder = stmtBuilder.Where("block_height = ?", *filter.MaybeBlockHeight)
 	}
@@ -484,6 +480,9 @@ func (transactionsView *BlockTransactionsView) List(
 	rDbPagination := rdb.NewRDbPaginationBuilder(
 		pagination,
 		transactionsView.rdb,
+	).WithKeyset(
+		rdb.KeysetColumn{Column: "block_height", Desc: order.Height == view.ORDER_DESC},
+		rdb.KeysetColumn{Column: "id"},
 	).WithCustomTotalQueryFn(
 		func(rdbHandle *rdb.Handle, _ sq.SelectBuilder) (int64, error) {
 			identity := "-"
@@ -510,11 +509,13 @@ func (transactionsView *BlockTransactionsView) List(
 	defer rowsResult.Close()
 
 	transactions := make([]TransactionRow, 0)
+	ids := make([]int64, 0)
 	for rowsResult.Next() {
 		var transaction TransactionRow
 		var feeJSON *string
 		var messagesJSON *string
 		var signersJSON *string
+		var id int64
 		blockTimeReader := transactionsView.rdb.NtotReader()
 
 		if err = rowsResult.Scan(
@@ -534,6 +535,7 @@ func (transactionsView *BlockTransactionsView) List(
 			&transaction.TimeoutHeight,
 			&messagesJSON,
 			&signersJSON,
+			&id,
 		); err != nil {
 			if errors.Is(err, rdb.ErrNoRows) {
 				return nil, nil, rdb.ErrNoRows
@@ -565,9 +567,12 @@ func (transactionsView *BlockTransactionsView) List(
 		transaction.Signers = signers
 
 		transactions = append(transactions, transaction)
+		ids = append(ids, id)
 	}
 
-	paginationResult, err := rDbPagination.Result()
+	transactions, paginationResult, err := rdb.CursorPage(rDbPagination, transactions, func(i int) []interface{} {
+		return []interface{}{transactions[i].BlockHeight, ids[i]}
+	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("error preparing pagination result: %v", err)
 	}
@@ -718,6 +723,26 @@ func (transactionsView *BlockTransactionsView) Count() (int64, error) {
 	return count, nil
 }
 
+// DeleteAllByBlockHeightRange removes all transactions with block height within
+// [fromHeight, toHeight] and returns the number of removed transactions
+func (transactionsView *BlockTransactionsView) DeleteAllByBlockHeightRange(fromHeight int64, toHeight int64) (int64, error) {
+	sql, sqlArgs, err := transactionsView.rdb.StmtBuilder.Delete(
+		"view_transactions",
+	).Where(
+		"block_height >= ? AND block_height <= ?", fromHeight, toHeight,
+	).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("error building transactions deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := transactionsView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting transactions from the table: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return result.RowsAffected(), nil
+}
+
 func (transactionsView *BlockTransactionsView) UpdateAll(mapValues []map[string]interface{}) error {
 	tableName := "view_transactions"
 
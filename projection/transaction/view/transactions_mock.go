@@ -70,3 +70,9 @@ func (transactionsView *MockTransactionsView) UpdateAll(mapValues []map[string]i
 	mockArgs := transactionsView.Called(mapValues)
 	return mockArgs.Error(0)
 }
+
+func (transactionsView *MockTransactionsView) DeleteAllByBlockHeightRange(fromHeight int64, toHeight int64) (int64, error) {
+	mockArgs := transactionsView.Called(fromHeight, toHeight)
+	result, _ := mockArgs.Get(0).(int64)
+	return result, mockArgs.Error(1)
+}
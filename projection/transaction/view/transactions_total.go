@@ -1,10 +1,14 @@
 package view
 
 import (
+	"fmt"
+
 	"github.com/AstraProtocol/astra-indexing/appinterface/projection/view"
 	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
 )
 
+const TRANSACTIONS_TOTAL_TABLE_NAME = "view_transactions_total"
+
 type TransactionsTotal interface {
 	Set(string, int64) error
 	Increment(string, int64) error
@@ -12,14 +16,38 @@ type TransactionsTotal interface {
 	DecrementAll([]string, int64) error
 	FindBy(string) (int64, error)
 	SumBy([]string) (int64, error)
+	// DeleteHeightsFrom removes the totals of the heights from `fromHeight` onwards, keeping the overall total
+	DeleteHeightsFrom(fromHeight int64) error
 }
 
 type TransactionsTotalView struct {
 	*view.Total
+
+	rdbHandle *rdb.Handle
 }
 
 func NewTransactionsTotalView(rdbHandle *rdb.Handle) TransactionsTotal {
 	return &TransactionsTotalView{
-		view.NewTotal(rdbHandle, "view_transactions_total"),
+		view.NewTotal(rdbHandle, TRANSACTIONS_TOTAL_TABLE_NAME),
+
+		rdbHandle,
+	}
+}
+
+func (totalView *TransactionsTotalView) DeleteHeightsFrom(fromHeight int64) error {
+	// The identities are either the heights or "-" for the overall total
+	sql, sqlArgs, err := totalView.rdbHandle.StmtBuilder.Delete(
+		TRANSACTIONS_TOTAL_TABLE_NAME,
+	).Where(
+		"CASE WHEN identity ~ '^[0-9]+$' THEN identity::BIGINT >= ? ELSE FALSE END", fromHeight,
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building height totals deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
 	}
+
+	if _, err := totalView.rdbHandle.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error deleting height totals: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return nil
 }
@@ -44,3 +44,8 @@ func (view *MockTransactionsTotalView) SumBy(identities []string) (int64, error)
 	result, _ := mockArgs.Get(0).(int64)
 	return result, mockArgs.Error(1)
 }
+
+func (view *MockTransactionsTotalView) DeleteHeightsFrom(fromHeight int64) error {
+	mockArgs := view.Called(fromHeight)
+	return mockArgs.Error(0)
+}
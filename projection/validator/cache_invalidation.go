@@ -0,0 +1,30 @@
+package validator
+
+import (
+	"github.com/AstraProtocol/astra-indexing/projection/validator/view"
+)
+
+// validatorChanges collects the addresses of the validators updated while handling the events of a
+// height
+type validatorChanges map[string]struct{}
+
+func (changes validatorChanges) add(validator *view.ValidatorRow) {
+	changes[validator.OperatorAddress] = struct{}{}
+	changes[validator.ConsensusNodeAddress] = struct{}{}
+}
+
+// invalidateCache evicts the cached values of the changed validators, once they are committed. The
+// cache errors are not fatal, the values expire eventually.
+func (projection *Validator) invalidateCache(height int64, changes validatorChanges) {
+	if len(changes) == 0 {
+		return
+	}
+
+	tags := []string{view.VALIDATORS_CACHE_TAG}
+	for address := range changes {
+		tags = append(tags, view.ValidatorCacheTag(address))
+	}
+	if err := projection.cache.Invalidate(tags...); err != nil {
+		projection.logger.Errorf("error invalidating cache of validators changed at height %d: %v", height, err)
+	}
+}
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS view_validators_journal ON view_validators;
+DROP TRIGGER IF EXISTS view_validator_activities_total_journal ON view_validator_activities_total;
+DROP TRIGGER IF EXISTS view_validator_block_commitments_total_journal ON view_validator_block_commitments_total;
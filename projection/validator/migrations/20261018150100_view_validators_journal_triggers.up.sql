@@ -0,0 +1,8 @@
+CREATE TRIGGER view_validators_journal AFTER INSERT OR UPDATE OR DELETE ON view_validators
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_validator_activities_total_journal AFTER INSERT OR UPDATE OR DELETE ON view_validator_activities_total
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_validator_block_commitments_total_journal AFTER INSERT OR UPDATE OR DELETE ON view_validator_block_commitments_total
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
@@ -11,6 +11,7 @@ import (
 	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
 	event_entity "github.com/AstraProtocol/astra-indexing/entity/event"
 	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
+	"github.com/AstraProtocol/astra-indexing/external/cache"
 	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
 	"github.com/AstraProtocol/astra-indexing/external/tmcosmosutils"
 	"github.com/AstraProtocol/astra-indexing/external/utctime"
@@ -21,6 +22,8 @@ import (
 )
 
 var _ projection_entity.Projection = &Validator{}
+var _ projection_entity.RebuildableProjection = &Validator{}
+var _ projection_entity.RollbackableProjection = &Validator{}
 
 const DO_NOT_MODIFY = "[do-not-modify]"
 
@@ -29,6 +32,7 @@ type Validator struct {
 
 	rdbConn rdb.Conn
 	logger  applogger.Logger
+	cache   cache.Cache
 
 	conNodeAddressPrefix string
 
@@ -49,6 +53,7 @@ func NewValidator(
 
 		rdbConn,
 		logger,
+		cache.NewCache(),
 		conNodeAddressPrefix,
 		migrationHelper,
 	}
@@ -90,6 +95,43 @@ func (projection *Validator) OnInit() error {
 	return nil
 }
 
+func (projection *Validator) Reset(fromHeight int64) error {
+	return projection.ResetViews(projection.rdbConn, fromHeight, []string{
+		"view_validators",
+		"view_validator_activities",
+		"view_validator_activities_total",
+		"view_validator_block_commitments",
+		"view_validator_block_commitments_total",
+	})
+}
+
+// Rollback removes the validator activities of the heights and undoes the journaled changes of the
+// validators and the totals, the uptimes are accumulated over the blocks and cannot be derived back
+// from the remaining records
+func (projection *Validator) Rollback(fromHeight int64, toHeight int64) error {
+	entries, err := projection.RollbackViews(projection.rdbConn, fromHeight, []string{
+		"view_validator_activities",
+	})
+	if err != nil {
+		return fmt.Errorf("error rolling back validator views: %w", err)
+	}
+
+	changes := make(validatorChanges)
+	for _, entry := range entries {
+		if entry.TableName != "view_validators" {
+			continue
+		}
+		for _, address := range entry.Values("operator_address") {
+			changes[address] = struct{}{}
+		}
+		for _, address := range entry.Values("consensus_node_address") {
+			changes[address] = struct{}{}
+		}
+	}
+	projection.invalidateCache(fromHeight, changes)
+	return nil
+}
+
 func (projection *Validator) HandleEvents(height int64, events []event_entity.Event) error {
 	rdbTx, err := projection.rdbConn.Begin()
 	if err != nil {
@@ -104,6 +146,9 @@ func (projection *Validator) HandleEvents(height int64, events []event_entity.Ev
 	}()
 
 	rdbTxHandle := rdbTx.ToHandle()
+	if err := projection.JournalViews(rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error journaling views: %v", err)
+	}
 	validatorsView := view.NewValidators(rdbTxHandle)
 	validatorActivitiesView := view.NewValidatorActivities(rdbTxHandle)
 	validatorActivitiesTotalView := view.NewValidatorActivitiesTotal(rdbTxHandle)
@@ -120,7 +165,8 @@ func (projection *Validator) HandleEvents(height int64, events []event_entity.Ev
 		}
 	}
 
-	if projectErr := projection.projectValidatorView(validatorsView, height, events); projectErr != nil {
+	changes := make(validatorChanges)
+	if projectErr := projection.projectValidatorView(validatorsView, height, events, changes); projectErr != nil {
 		return fmt.Errorf("error projecting validator view: %v", projectErr)
 	}
 
@@ -188,6 +234,9 @@ func (projection *Validator) HandleEvents(height int64, events []event_entity.Ev
 			if activeValidatorUpdateErr := validatorsView.UpdateAllValidatorUpTime(mutActiveValidators); activeValidatorUpdateErr != nil {
 				return fmt.Errorf("error updating active validators up time data: %v", activeValidatorUpdateErr)
 			}
+			for i := range mutActiveValidators {
+				changes.add(&mutActiveValidators[i])
+			}
 
 		} else if votedEvent, ok := event.(*event_usecase.MsgVote); ok {
 			projection.logger.Debug("handling MsgVote event")
@@ -208,6 +257,7 @@ func (projection *Validator) HandleEvents(height int64, events []event_entity.Ev
 			if votedValidatorUpdateErr := validatorsView.Update(mutVotedValidator); votedValidatorUpdateErr != nil {
 				return fmt.Errorf("error updating voted validator: %v", votedValidatorUpdateErr)
 			}
+			changes.add(mutVotedValidator)
 		}
 	}
 	if err := projection.UpdateLastHandledEventHeight(rdbTxHandle, height); err != nil {
@@ -217,6 +267,8 @@ func (projection *Validator) HandleEvents(height int64, events []event_entity.Ev
 		return fmt.Errorf("error committing changes: %v", err)
 	}
 	committed = true
+
+	projection.invalidateCache(height, changes)
 	return nil
 }
 
@@ -224,6 +276,7 @@ func (projection *Validator) projectValidatorView(
 	validatorsView *view.Validators,
 	blockHeight int64,
 	events []event_entity.Event,
+	changes validatorChanges,
 ) error {
 	// MsgCreateValidator should be handled first
 	for _, event := range events {
@@ -286,6 +339,7 @@ func (projection *Validator) projectValidatorView(
 			if err := validatorsView.Upsert(&validatorRow); err != nil {
 				return fmt.Errorf("error inserting new validator into view: %v", err)
 			}
+			changes.add(&validatorRow)
 		} else if msgCreateValidatorEvent, ok := event.(*event_usecase.MsgCreateValidator); ok {
 			projection.logger.Debug("handling MsgCreateValidator event")
 			tendermintPubkey, err := base64.StdEncoding.DecodeString(msgCreateValidatorEvent.TendermintPubkey)
@@ -345,6 +399,7 @@ func (projection *Validator) projectValidatorView(
 			if err := validatorsView.Upsert(&validatorRow); err != nil {
 				return fmt.Errorf("error inserting new validator into view: %v", err)
 			}
+			changes.add(&validatorRow)
 		}
 	}
 
@@ -387,6 +442,7 @@ func (projection *Validator) projectValidatorView(
 			if err := validatorsView.Update(mutValidatorRow); err != nil {
 				return fmt.Errorf("error updating validator into view: %v", err)
 			}
+			changes.add(mutValidatorRow)
 		} else if validatorJailedEvent, ok := event.(*event_usecase.ValidatorJailed); ok {
 			projection.logger.Debug("handling ValidatorJailed event")
 
@@ -405,6 +461,7 @@ func (projection *Validator) projectValidatorView(
 			if err := validatorsView.Update(mutValidatorRow); err != nil {
 				return fmt.Errorf("error updating validator into view: %v", err)
 			}
+			changes.add(mutValidatorRow)
 		} else if msgUnjailEvent, ok := event.(*event_usecase.MsgUnjail); ok {
 			projection.logger.Debug("handling MsgUnjail event")
 
@@ -422,6 +479,7 @@ func (projection *Validator) projectValidatorView(
 			if err := validatorsView.Update(mutValidatorRow); err != nil {
 				return fmt.Errorf("error updating validator into view: %v", err)
 			}
+			changes.add(mutValidatorRow)
 		} else if powerChangedEvent, ok := event.(*event_usecase.PowerChanged); ok {
 			projection.logger.Debug("handling PowerChange event")
 
@@ -453,6 +511,7 @@ func (projection *Validator) projectValidatorView(
 			if err := validatorsView.Update(mutValidatorRow); err != nil {
 				return fmt.Errorf("error updating validator into view: %v", err)
 			}
+			changes.add(mutValidatorRow)
 		}
 
 	}
@@ -0,0 +1,11 @@
+package view
+
+// Tag of the cached lists of validators, invalidated by the Validator projection whenever a validator
+// changes
+const VALIDATORS_CACHE_TAG = "validators"
+
+// ValidatorCacheTag returns the tag of the cached values of a validator by its operator or consensus
+// node address, invalidated by the Validator projection when the validator changes
+func ValidatorCacheTag(address string) string {
+	return "validator_" + address
+}
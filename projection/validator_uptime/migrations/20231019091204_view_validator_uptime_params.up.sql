@@ -0,0 +1,6 @@
+CREATE TABLE view_validator_uptime_params (
+    module VARCHAR,
+    key VARCHAR,
+    value VARCHAR NOT NULL,
+    PRIMARY KEY (module, key)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS view_validator_uptime_validators_operator_address_btree_index;
+
+DROP TABLE IF EXISTS view_validator_uptime_validators;
@@ -0,0 +1,13 @@
+CREATE TABLE view_validator_uptime_validators (
+    id BIGSERIAL,
+    consensus_node_address VARCHAR NOT NULL,
+    operator_address VARCHAR NOT NULL,
+    initial_delegator_address VARCHAR NOT NULL,
+    tendermint_pubkey VARCHAR NOT NULL,
+    tendermint_address VARCHAR NOT NULL,
+    moniker VARCHAR NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE(consensus_node_address, operator_address)
+);
+
+CREATE INDEX view_validator_uptime_validators_operator_address_btree_index ON view_validator_uptime_validators USING btree (operator_address);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS view_validator_signing_infos_consensus_node_address_index;
+
+DROP TABLE IF EXISTS view_validator_signing_infos;
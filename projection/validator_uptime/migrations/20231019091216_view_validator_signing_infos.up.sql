@@ -0,0 +1,18 @@
+CREATE TABLE view_validator_signing_infos (
+    operator_address VARCHAR NOT NULL,
+    consensus_node_address VARCHAR NOT NULL,
+    tendermint_address VARCHAR NOT NULL,
+    active BOOLEAN NOT NULL,
+    active_from_height BIGINT NOT NULL,
+    start_height BIGINT NOT NULL,
+    index_offset BIGINT NOT NULL,
+    missed_blocks_counter BIGINT NOT NULL,
+    total_missed_blocks BIGINT NOT NULL,
+    missed_blocks_bit_array BYTEA NOT NULL,
+    jailed BOOLEAN NOT NULL,
+    tombstoned BOOLEAN NOT NULL,
+    last_missed_height BIGINT NULL,
+    PRIMARY KEY (operator_address)
+);
+
+CREATE UNIQUE INDEX view_validator_signing_infos_consensus_node_address_index ON view_validator_signing_infos USING btree (consensus_node_address);
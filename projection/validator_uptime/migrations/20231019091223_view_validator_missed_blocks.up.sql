@@ -0,0 +1,9 @@
+CREATE TABLE view_validator_missed_blocks (
+    id BIGSERIAL,
+    operator_address VARCHAR NOT NULL,
+    consensus_node_address VARCHAR NOT NULL,
+    block_height BIGINT NOT NULL,
+    block_time BIGINT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (operator_address, block_height)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS view_validator_slashing_events_operator_address_block_height_index;
+
+DROP TABLE IF EXISTS view_validator_slashing_events;
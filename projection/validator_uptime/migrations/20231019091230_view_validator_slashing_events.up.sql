@@ -0,0 +1,13 @@
+CREATE TABLE view_validator_slashing_events (
+    id BIGSERIAL,
+    operator_address VARCHAR NOT NULL,
+    consensus_node_address VARCHAR NOT NULL,
+    block_height BIGINT NOT NULL,
+    block_time BIGINT NULL,
+    type VARCHAR NOT NULL,
+    reason VARCHAR NULL,
+    slashed_power VARCHAR NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX view_validator_slashing_events_operator_address_block_height_index ON view_validator_slashing_events USING btree (operator_address, block_height);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS view_validator_signing_infos_active_index;
@@ -0,0 +1 @@
+CREATE INDEX view_validator_signing_infos_active_index ON view_validator_signing_infos USING btree (operator_address) WHERE active;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS view_validator_uptime_params_journal ON view_validator_uptime_params;
+DROP TRIGGER IF EXISTS view_validator_uptime_validators_journal ON view_validator_uptime_validators;
+DROP TRIGGER IF EXISTS view_validator_signing_infos_journal ON view_validator_signing_infos;
+DROP TRIGGER IF EXISTS view_validator_uptime_param_changes_journal ON view_validator_uptime_param_changes;
@@ -0,0 +1,11 @@
+CREATE TRIGGER view_validator_uptime_params_journal AFTER INSERT OR UPDATE OR DELETE ON view_validator_uptime_params
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_validator_uptime_validators_journal AFTER INSERT OR UPDATE OR DELETE ON view_validator_uptime_validators
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_validator_signing_infos_journal AFTER INSERT OR UPDATE OR DELETE ON view_validator_signing_infos
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
+
+CREATE TRIGGER view_validator_uptime_param_changes_journal AFTER INSERT OR UPDATE OR DELETE ON view_validator_uptime_param_changes
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
@@ -0,0 +1,53 @@
+package validator_uptime
+
+import (
+	"github.com/AstraProtocol/astra-indexing/projection/validator_uptime/view"
+)
+
+// handleValidatorSignature records whether the validator signed the next block of its signing window, following
+// HandleValidatorSignature of the slashing module
+func handleValidatorSignature(signingInfo *view.SigningInfoRow, signedBlocksWindow int64, missed bool) {
+	index := signingInfo.IndexOffset % signedBlocksWindow
+	signingInfo.IndexOffset += 1
+
+	// The bit array is never shrunk, the same as the bit array of the slashing module keeps the indexes beyond a
+	// reduced window
+	if byteLength := (signedBlocksWindow + 7) / 8; int64(len(signingInfo.MissedBlocksBitArray)) < byteLength {
+		bitArray := make([]byte, byteLength)
+		copy(bitArray, signingInfo.MissedBlocksBitArray)
+		signingInfo.MissedBlocksBitArray = bitArray
+	}
+
+	previous := getMissedBlockBit(signingInfo.MissedBlocksBitArray, index)
+	switch {
+	case !previous && missed:
+		setMissedBlockBit(signingInfo.MissedBlocksBitArray, index, true)
+		signingInfo.MissedBlocksCounter += 1
+	case previous && !missed:
+		setMissedBlockBit(signingInfo.MissedBlocksBitArray, index, false)
+		signingInfo.MissedBlocksCounter -= 1
+	}
+
+	if missed {
+		signingInfo.TotalMissedBlocks += 1
+	}
+}
+
+// resetSigningWindow starts a new signing window after the validator is jailed for downtime
+func resetSigningWindow(signingInfo *view.SigningInfoRow) {
+	signingInfo.IndexOffset = 0
+	signingInfo.MissedBlocksCounter = 0
+	signingInfo.MissedBlocksBitArray = make([]byte, len(signingInfo.MissedBlocksBitArray))
+}
+
+func getMissedBlockBit(bitArray []byte, index int64) bool {
+	return bitArray[index/8]&(1<<uint(index%8)) != 0
+}
+
+func setMissedBlockBit(bitArray []byte, index int64, missed bool) {
+	if missed {
+		bitArray[index/8] |= 1 << uint(index%8)
+	} else {
+		bitArray[index/8] &^= 1 << uint(index%8)
+	}
+}
@@ -0,0 +1,558 @@
+package validator_uptime
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbparambase"
+	rdbparambase_types "github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbparambase/types"
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbprojectionbase"
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbvalidatorbase"
+	validatorbase_view "github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbvalidatorbase/view"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	event_entity "github.com/AstraProtocol/astra-indexing/entity/event"
+	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/external/tmcosmosutils"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/pg/migrationhelper"
+	"github.com/AstraProtocol/astra-indexing/projection/validator/constants"
+	"github.com/AstraProtocol/astra-indexing/projection/validator_uptime/view"
+	event_usecase "github.com/AstraProtocol/astra-indexing/usecase/event"
+)
+
+var _ projection_entity.Projection = &ValidatorUptime{}
+var _ projection_entity.RebuildableProjection = &ValidatorUptime{}
+var _ projection_entity.RollbackableProjection = &ValidatorUptime{}
+
+var (
+	NewSigningInfos   = view.NewSigningInfosView
+	NewMissedBlocks   = view.NewMissedBlocksView
+	NewSlashingEvents = view.NewSlashingEventsView
+	NewParamChanges   = view.NewParamChangesView
+
+	JournalViews                 = (*ValidatorUptime).JournalViews
+	UpdateLastHandledEventHeight = (*ValidatorUptime).UpdateLastHandledEventHeight
+
+	ParamBaseHandleEvents     = (*rdbparambase.Base).HandleEvents
+	ValidatorBaseHandleEvents = (*rdbvalidatorbase.Base).HandleEvents
+	ParamBaseGetView          = (*rdbparambase.Base).GetView
+	ValidatorBaseGetView      = (*rdbvalidatorbase.Base).GetView
+)
+
+var (
+	SignedBlocksWindowParam = rdbparambase_types.ParamAccessor{
+		Module: "slashing",
+		Key:    "signed_blocks_window",
+	}
+	MinSignedPerWindowParam = rdbparambase_types.ParamAccessor{
+		Module: "slashing",
+		Key:    "min_signed_per_window",
+	}
+	DowntimeJailDurationParam = rdbparambase_types.ParamAccessor{
+		Module: "slashing",
+		Key:    "downtime_jail_duration",
+	}
+)
+
+// Param keys of the slashing subspace in param change proposals
+var slashingParamChangeKeys = map[string]rdbparambase_types.ParamAccessor{
+	"SignedBlocksWindow":   SignedBlocksWindowParam,
+	"MinSignedPerWindow":   MinSignedPerWindowParam,
+	"DowntimeJailDuration": DowntimeJailDurationParam,
+}
+
+const (
+	JAIL_REASON_MISSING_SIGNATURE = "missing_signature"
+	SLASH_REASON_DOUBLE_SIGN      = "double_sign"
+)
+
+// ValidatorUptime keeps the signing window of every validator in the active set the same way as the slashing
+// module does, together with the blocks missed and the jail and slash history of the validators.
+type ValidatorUptime struct {
+	*rdbprojectionbase.Base
+	paramBase     *rdbparambase.Base
+	validatorBase *rdbvalidatorbase.Base
+
+	rdbConn rdb.Conn
+	logger  applogger.Logger
+
+	conNodeAddressPrefix string
+
+	migrationHelper migrationhelper.MigrationHelper
+}
+
+func NewValidatorUptime(
+	logger applogger.Logger,
+	rdbConn rdb.Conn,
+	conNodeAddressPrefix string,
+	migrationHelper migrationhelper.MigrationHelper,
+) *ValidatorUptime {
+	return &ValidatorUptime{
+		rdbprojectionbase.NewRDbBase(
+			rdbConn.ToHandle(),
+			"ValidatorUptime",
+		),
+		rdbparambase.NewBase(view.PARAMS_TABLE_NAME, []rdbparambase_types.ParamAccessor{
+			SignedBlocksWindowParam,
+			MinSignedPerWindowParam,
+			DowntimeJailDurationParam,
+		}),
+		rdbvalidatorbase.NewBase(view.VALIDATORS_TABLE_NAME, conNodeAddressPrefix),
+
+		rdbConn,
+		logger,
+
+		conNodeAddressPrefix,
+
+		migrationHelper,
+	}
+}
+
+func (projection *ValidatorUptime) GetEventsToListen() []string {
+	return append(
+		append(
+			[]string{
+				event_usecase.BLOCK_CREATED,
+				event_usecase.POWER_CHANGED,
+				event_usecase.VALIDATOR_JAILED,
+				event_usecase.VALIDATOR_SLASHED,
+				event_usecase.MSG_UNJAIL_CREATED,
+				event_usecase.MSG_SUBMIT_PARAM_CHANGE_PROPOSAL_CREATED,
+				event_usecase.PROPOSAL_ENDED,
+			},
+			projection.paramBase.GetEventsToListen()...,
+		),
+		projection.validatorBase.GetEventsToListen()...,
+	)
+}
+
+func (projection *ValidatorUptime) OnInit() error {
+	if projection.migrationHelper != nil {
+		projection.migrationHelper.Migrate()
+	}
+
+	return nil
+}
+
+func (projection *ValidatorUptime) Reset(fromHeight int64) error {
+	return projection.ResetViews(projection.rdbConn, fromHeight, []string{
+		view.PARAMS_TABLE_NAME,
+		view.VALIDATORS_TABLE_NAME,
+		view.SIGNING_INFOS_TABLE_NAME,
+		view.MISSED_BLOCKS_TABLE_NAME,
+		view.SLASHING_EVENTS_TABLE_NAME,
+		view.PARAM_CHANGES_TABLE_NAME,
+	})
+}
+
+// Rollback undoes the journaled changes of the signing windows, the validators and the params, whose states are
+// accumulated over the blocks. The missed blocks and the slashing events of the heights are removed.
+func (projection *ValidatorUptime) Rollback(fromHeight int64, toHeight int64) error {
+	if _, err := projection.RollbackViews(projection.rdbConn, fromHeight, []string{
+		view.MISSED_BLOCKS_TABLE_NAME,
+		view.SLASHING_EVENTS_TABLE_NAME,
+	}); err != nil {
+		return fmt.Errorf("error rolling back validator uptime views: %w", err)
+	}
+	return nil
+}
+
+func (projection *ValidatorUptime) HandleEvents(height int64, events []event_entity.Event) error {
+	rdbTx, err := projection.rdbConn.Begin()
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %v", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = rdbTx.Rollback()
+		}
+	}()
+
+	rdbTxHandle := rdbTx.ToHandle()
+	if err = JournalViews(projection, rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error journaling views: %v", err)
+	}
+
+	if err := ParamBaseHandleEvents(projection.paramBase, rdbTxHandle, projection.logger, events); err != nil {
+		return fmt.Errorf("error handling event in param base: %v", err)
+	}
+	if err := ValidatorBaseHandleEvents(projection.validatorBase, rdbTxHandle, projection.logger, events); err != nil {
+		return fmt.Errorf("error handling event in validator base: %v", err)
+	}
+
+	signingInfosView := NewSigningInfos(rdbTxHandle)
+	paramsView := ParamBaseGetView(projection.paramBase, rdbTxHandle)
+
+	// Only the signing windows of the active set move, the other validators are loaded when an event refers to them
+	signingInfoRows, err := signingInfosView.ListActive()
+	if err != nil {
+		return fmt.Errorf("error listing active validator signing infos: %v", err)
+	}
+	signingInfos := newSigningInfoSet(signingInfosView, signingInfoRows)
+
+	var maybeBlockTime *utctime.UTCTime
+	for _, event := range events {
+		if blockCreatedEvent, ok := event.(*event_usecase.BlockCreated); ok {
+			blockTime := blockCreatedEvent.Block.Time
+			maybeBlockTime = &blockTime
+		}
+	}
+
+	missedBlockRows := make([]view.MissedBlockRow, 0)
+	slashingEventRows := make([]view.SlashingEventRow, 0)
+
+	// The signatures of the last commit are handled in the begin blocker, before the other events of the height
+	for _, event := range events {
+		blockCreatedEvent, ok := event.(*event_usecase.BlockCreated)
+		if !ok || height <= 1 {
+			continue
+		}
+
+		signedBlocksWindow, err := paramsView.FindInt64By(SignedBlocksWindowParam)
+		if err != nil {
+			return fmt.Errorf("error retrieving signed_blocks_window param: %v", err)
+		}
+
+		signedValidators := make(map[string]bool)
+		for _, signature := range blockCreatedEvent.Block.Signatures {
+			signedValidators[signature.ValidatorAddress] = true
+		}
+
+		commitHeight := height - 1
+		for _, signingInfo := range signingInfos.rows {
+			if !signingInfo.Active || signingInfo.ActiveFromHeight > commitHeight {
+				continue
+			}
+
+			missed := !signedValidators[signingInfo.TendermintAddress]
+			handleValidatorSignature(signingInfo, signedBlocksWindow, missed)
+			if missed {
+				signingInfo.MaybeLastMissedHeight = &commitHeight
+				missedBlockRows = append(missedBlockRows, view.MissedBlockRow{
+					OperatorAddress:      signingInfo.OperatorAddress,
+					ConsensusNodeAddress: signingInfo.ConsensusNodeAddress,
+					BlockHeight:          commitHeight,
+					MaybeBlockTime:       maybeBlockTime,
+				})
+			}
+			signingInfos.markUpdated(signingInfo)
+		}
+	}
+
+	validatorsView := ValidatorBaseGetView(projection.validatorBase, rdbTxHandle)
+	paramChangesView := NewParamChanges(rdbTxHandle)
+	for _, event := range events {
+		if createGenesisValidatorEvent, ok := event.(*event_usecase.CreateGenesisValidator); ok {
+			if createGenesisValidatorEvent.Status != constants.BONDED || createGenesisValidatorEvent.Jailed {
+				continue
+			}
+
+			consensusNodeAddress, tendermintAddress, err := projection.addressesFromTmPubKey(
+				createGenesisValidatorEvent.TendermintPubkey,
+			)
+			if err != nil {
+				return err
+			}
+			signingInfo, err := signingInfos.findByConsensusNodeAddress(consensusNodeAddress)
+			if err != nil {
+				return err
+			}
+			if signingInfo == nil {
+				signingInfo = signingInfos.add(view.SigningInfoRow{
+					OperatorAddress:      createGenesisValidatorEvent.ValidatorAddress,
+					ConsensusNodeAddress: consensusNodeAddress,
+					TendermintAddress:    tendermintAddress,
+					StartHeight:          height,
+					MissedBlocksBitArray: []byte{},
+				})
+			}
+			signingInfo.Active = true
+			signingInfo.ActiveFromHeight = height
+			signingInfos.markUpdated(signingInfo)
+
+		} else if powerChangedEvent, ok := event.(*event_usecase.PowerChanged); ok {
+			consensusNodeAddress, tendermintAddress, err := projection.addressesFromTmPubKey(
+				powerChangedEvent.TendermintPubkey,
+			)
+			if err != nil {
+				return err
+			}
+
+			signingInfo, err := signingInfos.findByConsensusNodeAddress(consensusNodeAddress)
+			if err != nil {
+				return err
+			}
+			if powerChangedEvent.Power == "0" {
+				if signingInfo != nil && signingInfo.Active {
+					signingInfo.Active = false
+					signingInfos.markUpdated(signingInfo)
+				}
+				continue
+			}
+			if signingInfo != nil && signingInfo.Active {
+				continue
+			}
+
+			// A validator update returned by the end blocker takes effect two heights later
+			activeFromHeight := height + 2
+			if signingInfo == nil {
+				validatorRow, err := validatorsView.FindLastBy(validatorbase_view.ValidatorIdentity{
+					MaybeConsensusNodeAddress: &consensusNodeAddress,
+				})
+				if err != nil {
+					return fmt.Errorf("error getting bonded validator %s from view: %v", consensusNodeAddress, err)
+				}
+				signingInfo = signingInfos.add(view.SigningInfoRow{
+					OperatorAddress:      validatorRow.OperatorAddress,
+					ConsensusNodeAddress: consensusNodeAddress,
+					TendermintAddress:    tendermintAddress,
+					StartHeight:          activeFromHeight,
+					MissedBlocksBitArray: []byte{},
+				})
+			}
+			signingInfo.Active = true
+			signingInfo.ActiveFromHeight = activeFromHeight
+			signingInfos.markUpdated(signingInfo)
+
+		} else if validatorJailedEvent, ok := event.(*event_usecase.ValidatorJailed); ok {
+			signingInfo, err := signingInfos.findByConsensusNodeAddress(validatorJailedEvent.ConsensusNodeAddress)
+			if err != nil {
+				return err
+			}
+			if signingInfo == nil {
+				return fmt.Errorf(
+					"error finding signing info of jailed validator %s", validatorJailedEvent.ConsensusNodeAddress,
+				)
+			}
+
+			signingInfo.Jailed = true
+			if validatorJailedEvent.Reason == JAIL_REASON_MISSING_SIGNATURE {
+				resetSigningWindow(signingInfo)
+			}
+			signingInfos.markUpdated(signingInfo)
+
+			reason := validatorJailedEvent.Reason
+			slashingEventRows = append(slashingEventRows, view.SlashingEventRow{
+				OperatorAddress:      signingInfo.OperatorAddress,
+				ConsensusNodeAddress: signingInfo.ConsensusNodeAddress,
+				BlockHeight:          height,
+				MaybeBlockTime:       maybeBlockTime,
+				Type:                 view.SLASHING_EVENT_TYPE_JAILED,
+				MaybeReason:          &reason,
+			})
+
+		} else if validatorSlashedEvent, ok := event.(*event_usecase.ValidatorSlashed); ok {
+			signingInfo, err := signingInfos.findByConsensusNodeAddress(validatorSlashedEvent.ConsensusNodeAddress)
+			if err != nil {
+				return err
+			}
+			if signingInfo == nil {
+				return fmt.Errorf(
+					"error finding signing info of slashed validator %s", validatorSlashedEvent.ConsensusNodeAddress,
+				)
+			}
+
+			if validatorSlashedEvent.Reason == SLASH_REASON_DOUBLE_SIGN {
+				signingInfo.Tombstoned = true
+				signingInfos.markUpdated(signingInfo)
+			}
+
+			reason := validatorSlashedEvent.Reason
+			slashedPower := validatorSlashedEvent.SlashedPower
+			slashingEventRows = append(slashingEventRows, view.SlashingEventRow{
+				OperatorAddress:      signingInfo.OperatorAddress,
+				ConsensusNodeAddress: signingInfo.ConsensusNodeAddress,
+				BlockHeight:          height,
+				MaybeBlockTime:       maybeBlockTime,
+				Type:                 view.SLASHING_EVENT_TYPE_SLASHED,
+				MaybeReason:          &reason,
+				MaybeSlashedPower:    &slashedPower,
+			})
+
+		} else if msgUnjailEvent, ok := event.(*event_usecase.MsgUnjail); ok {
+			signingInfo, err := signingInfos.findByOperatorAddress(msgUnjailEvent.ValidatorAddr)
+			if err != nil {
+				return err
+			}
+			if signingInfo == nil {
+				return fmt.Errorf("error finding signing info of unjailed validator %s", msgUnjailEvent.ValidatorAddr)
+			}
+
+			signingInfo.Jailed = false
+			signingInfos.markUpdated(signingInfo)
+
+			slashingEventRows = append(slashingEventRows, view.SlashingEventRow{
+				OperatorAddress:      signingInfo.OperatorAddress,
+				ConsensusNodeAddress: signingInfo.ConsensusNodeAddress,
+				BlockHeight:          height,
+				MaybeBlockTime:       maybeBlockTime,
+				Type:                 view.SLASHING_EVENT_TYPE_UNJAILED,
+			})
+
+		} else if paramChangeProposalEvent, ok := event.(*event_usecase.MsgSubmitParamChangeProposal); ok {
+			if paramChangeProposalEvent.MaybeProposalId == nil {
+				continue
+			}
+
+			paramChangeRows := make([]view.ParamChangeRow, 0)
+			for _, change := range paramChangeProposalEvent.Content.Changes {
+				param, ok := slashingParamChangeKeys[change.Key]
+				if change.Subspace != "slashing" || !ok {
+					continue
+				}
+
+				var value string
+				if err := json.Unmarshal(change.Value, &value); err != nil {
+					return fmt.Errorf("error parsing slashing param change value: %v", err)
+				}
+				if param == DowntimeJailDurationParam {
+					// Durations are changed in nanoseconds
+					if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+						value = value + "ns"
+					}
+				}
+
+				paramChangeRows = append(paramChangeRows, view.ParamChangeRow{
+					ProposalId: *paramChangeProposalEvent.MaybeProposalId,
+					Key:        param.Key,
+					Value:      value,
+				})
+			}
+			if err := paramChangesView.InsertAll(paramChangeRows); err != nil {
+				return fmt.Errorf("error inserting slashing param changes: %v", err)
+			}
+
+		} else if proposalEndedEvent, ok := event.(*event_usecase.ProposalEnded); ok {
+			if proposalEndedEvent.Result == "proposal_passed" {
+				paramChangeRows, err := paramChangesView.ListByProposalId(proposalEndedEvent.ProposalId)
+				if err != nil {
+					return fmt.Errorf("error listing slashing param changes of proposal: %v", err)
+				}
+				for _, paramChangeRow := range paramChangeRows {
+					if err := paramsView.Set(rdbparambase_types.ParamAccessor{
+						Module: "slashing",
+						Key:    paramChangeRow.Key,
+					}, paramChangeRow.Value); err != nil {
+						return fmt.Errorf("error updating slashing param %s: %v", paramChangeRow.Key, err)
+					}
+				}
+			}
+			if err := paramChangesView.DeleteByProposalId(proposalEndedEvent.ProposalId); err != nil {
+				return fmt.Errorf("error deleting slashing param changes of ended proposal: %v", err)
+			}
+		}
+	}
+
+	if err := signingInfosView.UpsertAll(signingInfos.updatedRows()); err != nil {
+		return fmt.Errorf("error upserting validator signing infos: %v", err)
+	}
+	if err := NewMissedBlocks(rdbTxHandle).InsertAll(missedBlockRows); err != nil {
+		return fmt.Errorf("error inserting validator missed blocks: %v", err)
+	}
+	if err := NewSlashingEvents(rdbTxHandle).InsertAll(slashingEventRows); err != nil {
+		return fmt.Errorf("error inserting validator slashing events: %v", err)
+	}
+
+	if err := UpdateLastHandledEventHeight(projection, rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error updating last handled event height: %v", err)
+	}
+
+	if err := rdbTx.Commit(); err != nil {
+		return fmt.Errorf("error committing changes: %v", err)
+	}
+	committed = true
+
+	return nil
+}
+
+func (projection *ValidatorUptime) addressesFromTmPubKey(tendermintPubkey string) (string, string, error) {
+	pubkey, err := base64.StdEncoding.DecodeString(tendermintPubkey)
+	if err != nil {
+		return "", "", fmt.Errorf("error base64 decoding Tendermint node pubkey: %v", err)
+	}
+	consensusNodeAddress, err := tmcosmosutils.ConsensusNodeAddressFromTmPubKey(
+		projection.conNodeAddressPrefix, pubkey,
+	)
+	if err != nil {
+		return "", "", fmt.Errorf("error converting Tendermint node pubkey to address: %v", err)
+	}
+
+	return consensusNodeAddress, tmcosmosutils.TmAddressFromTmPubKey(pubkey), nil
+}
+
+// signingInfoSet holds the signing infos of a height in insertion order, keeping track of the updated ones. It starts
+// with the active set and loads the other signing infos from the view on their first lookup.
+type signingInfoSet struct {
+	view    view.SigningInfos
+	rows    []*view.SigningInfoRow
+	updated map[*view.SigningInfoRow]bool
+}
+
+func newSigningInfoSet(signingInfosView view.SigningInfos, rows []view.SigningInfoRow) *signingInfoSet {
+	set := &signingInfoSet{
+		view:    signingInfosView,
+		rows:    make([]*view.SigningInfoRow, 0, len(rows)),
+		updated: make(map[*view.SigningInfoRow]bool),
+	}
+	for i := range rows {
+		set.rows = append(set.rows, &rows[i])
+	}
+
+	return set
+}
+
+func (set *signingInfoSet) add(row view.SigningInfoRow) *view.SigningInfoRow {
+	set.rows = append(set.rows, &row)
+	return &row
+}
+
+// findByConsensusNodeAddress returns nil when the validator has no signing info
+func (set *signingInfoSet) findByConsensusNodeAddress(consensusNodeAddress string) (*view.SigningInfoRow, error) {
+	for _, row := range set.rows {
+		if row.ConsensusNodeAddress == consensusNodeAddress {
+			return row, nil
+		}
+	}
+	return set.load(view.SigningInfoIdentity{MaybeConsensusNodeAddress: &consensusNodeAddress})
+}
+
+// findByOperatorAddress returns nil when the validator has no signing info
+func (set *signingInfoSet) findByOperatorAddress(operatorAddress string) (*view.SigningInfoRow, error) {
+	for _, row := range set.rows {
+		if row.OperatorAddress == operatorAddress {
+			return row, nil
+		}
+	}
+	return set.load(view.SigningInfoIdentity{MaybeOperatorAddress: &operatorAddress})
+}
+
+func (set *signingInfoSet) load(identity view.SigningInfoIdentity) (*view.SigningInfoRow, error) {
+	row, err := set.view.FindBy(identity)
+	if err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error finding validator signing info: %v", err)
+	}
+	return set.add(*row), nil
+}
+
+func (set *signingInfoSet) markUpdated(row *view.SigningInfoRow) {
+	set.updated[row] = true
+}
+
+func (set *signingInfoSet) updatedRows() []view.SigningInfoRow {
+	rows := make([]view.SigningInfoRow, 0, len(set.updated))
+	for _, row := range set.rows {
+		if set.updated[row] {
+			rows = append(rows, *row)
+		}
+	}
+	return rows
+}
@@ -0,0 +1,493 @@
+package validator_uptime_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	testify_mock "github.com/stretchr/testify/mock"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbparambase"
+	rdbparambase_view "github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbparambase/view"
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbvalidatorbase"
+	validatorbase_view "github.com/AstraProtocol/astra-indexing/appinterface/projection/rdbvalidatorbase/view"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb/test"
+	entity_event "github.com/AstraProtocol/astra-indexing/entity/event"
+	projection_entity "github.com/AstraProtocol/astra-indexing/entity/projection"
+	"github.com/AstraProtocol/astra-indexing/external/logger"
+	"github.com/AstraProtocol/astra-indexing/external/primptr"
+	"github.com/AstraProtocol/astra-indexing/external/tmcosmosutils"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+	"github.com/AstraProtocol/astra-indexing/infrastructure/pg"
+	"github.com/AstraProtocol/astra-indexing/projection/validator_uptime"
+	"github.com/AstraProtocol/astra-indexing/projection/validator_uptime/view"
+	usecase_event "github.com/AstraProtocol/astra-indexing/usecase/event"
+	"github.com/AstraProtocol/astra-indexing/usecase/model"
+)
+
+const CON_NODE_ADDRESS_PREFIX = "astravalcons"
+
+func NewValidatorUptimeProjection(rdbConn rdb.Conn) *validator_uptime.ValidatorUptime {
+	return validator_uptime.NewValidatorUptime(
+		nil,
+		rdbConn,
+		CON_NODE_ADDRESS_PREFIX,
+		nil,
+	)
+}
+
+func NewMockRDbConn() *test.MockRDbConn {
+	mock := test.NewMockRDbConn()
+	mock.On("ToHandle").Return(&rdb.Handle{
+		Runner:   mock,
+		TypeConv: &pg.PgxTypeConv{},
+		StmtBuilder: &rdb.StatementBuilder{
+			StatementBuilderType: sq.StatementBuilderType{},
+			PlaceholderFormat:    nil,
+		},
+	})
+
+	return mock
+}
+
+func NewMockRDbTx() *test.MockRDbTx {
+	mockTx := &test.MockRDbTx{}
+	mockTx.On("ToHandle").Return(nil).Maybe()
+	mockTx.On("Rollback").Return(nil).Maybe()
+	mockTx.On("Commit").Return(nil).Maybe()
+
+	return mockTx
+}
+
+func newMsgBase(msgName string, height int64) usecase_event.MsgBase {
+	return usecase_event.NewMsgBase(usecase_event.MsgBaseParams{
+		MsgName: msgName,
+		Version: 1,
+		MsgCommonParams: usecase_event.MsgCommonParams{
+			BlockHeight: height,
+			TxHash:      "TXHASH",
+			TxSuccess:   true,
+			MsgIndex:    0,
+		},
+	})
+}
+
+type mockViews struct {
+	signingInfos   *view.MockSigningInfosView
+	missedBlocks   *view.MockMissedBlocksView
+	slashingEvents *view.MockSlashingEventsView
+	paramChanges   *view.MockParamChangesView
+	params         *rdbparambase_view.MockParamsView
+	validators     *validatorbase_view.MockValidatorsView
+}
+
+func (views *mockViews) mocks() []*testify_mock.Mock {
+	return []*testify_mock.Mock{
+		&views.signingInfos.Mock,
+		&views.missedBlocks.Mock,
+		&views.slashingEvents.Mock,
+		&views.paramChanges.Mock,
+		&views.params.Mock,
+		&views.validators.Mock,
+	}
+}
+
+func newMockViews(activeSigningInfos []view.SigningInfoRow) *mockViews {
+	views := &mockViews{
+		signingInfos:   &view.MockSigningInfosView{},
+		missedBlocks:   &view.MockMissedBlocksView{},
+		slashingEvents: &view.MockSlashingEventsView{},
+		paramChanges:   &view.MockParamChangesView{},
+		params:         &rdbparambase_view.MockParamsView{},
+		validators:     &validatorbase_view.MockValidatorsView{},
+	}
+	views.signingInfos.On("ListActive").Return(activeSigningInfos, nil)
+
+	validator_uptime.NewSigningInfos = func(_ *rdb.Handle) view.SigningInfos {
+		return views.signingInfos
+	}
+	validator_uptime.NewMissedBlocks = func(_ *rdb.Handle) view.MissedBlocks {
+		return views.missedBlocks
+	}
+	validator_uptime.NewSlashingEvents = func(_ *rdb.Handle) view.SlashingEvents {
+		return views.slashingEvents
+	}
+	validator_uptime.NewParamChanges = func(_ *rdb.Handle) view.ParamChanges {
+		return views.paramChanges
+	}
+	validator_uptime.ParamBaseHandleEvents = func(
+		_ *rdbparambase.Base, _ *rdb.Handle, _ logger.Logger, _ []entity_event.Event,
+	) error {
+		return nil
+	}
+	validator_uptime.ValidatorBaseHandleEvents = func(
+		_ *rdbvalidatorbase.Base, _ *rdb.Handle, _ logger.Logger, _ []entity_event.Event,
+	) error {
+		return nil
+	}
+	validator_uptime.ParamBaseGetView = func(_ *rdbparambase.Base, _ *rdb.Handle) rdbparambase_view.Params {
+		return views.params
+	}
+	validator_uptime.ValidatorBaseGetView = func(
+		_ *rdbvalidatorbase.Base, _ *rdb.Handle,
+	) validatorbase_view.Validators {
+		return views.validators
+	}
+	validator_uptime.JournalViews = func(_ *validator_uptime.ValidatorUptime, _ *rdb.Handle, _ int64) error {
+		return nil
+	}
+	validator_uptime.UpdateLastHandledEventHeight = func(
+		_ *validator_uptime.ValidatorUptime, _ *rdb.Handle, _ int64,
+	) error {
+		return nil
+	}
+
+	return views
+}
+
+func TestValidatorUptime_HandleEvents(t *testing.T) {
+	blockTime := utctime.FromUnixNano(1000)
+	tendermintPubkey := make([]byte, 32)
+	consensusNodeAddress, err := tmcosmosutils.ConsensusNodeAddressFromTmPubKey(
+		CON_NODE_ADDRESS_PREFIX, tendermintPubkey,
+	)
+	assert.NoError(t, err)
+	tendermintAddress := tmcosmosutils.TmAddressFromTmPubKey(tendermintPubkey)
+
+	newSigningInfo := func(operatorAddress string, tendermintAddress string) view.SigningInfoRow {
+		return view.SigningInfoRow{
+			OperatorAddress:      operatorAddress,
+			ConsensusNodeAddress: operatorAddress + "cons",
+			TendermintAddress:    tendermintAddress,
+			Active:               true,
+			ActiveFromHeight:     0,
+			StartHeight:          0,
+			MissedBlocksBitArray: []byte{},
+		}
+	}
+
+	testCases := []struct {
+		Name     string
+		Height   int64
+		Events   []entity_event.Event
+		MockFunc func() []*testify_mock.Mock
+	}{
+		{
+			Name:   "HandleBlockSignatures",
+			Height: 10,
+			Events: []entity_event.Event{
+				&usecase_event.BlockCreated{
+					Block: &model.Block{
+						Height: 10,
+						Time:   blockTime,
+						Signatures: []model.BlockSignature{{
+							ValidatorAddress: "SIGNED",
+						}},
+					},
+				},
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				signed := newSigningInfo("signed", "SIGNED")
+				signed.IndexOffset = 5
+				missed := newSigningInfo("missed", "MISSED")
+				missed.IndexOffset = 5
+				missed.MissedBlocksBitArray = []byte{0x01}
+				missed.MissedBlocksCounter = 1
+				missed.TotalMissedBlocks = 1
+				notActiveYet := newSigningInfo("notactiveyet", "NOTACTIVEYET")
+				notActiveYet.ActiveFromHeight = 10
+
+				views := newMockViews([]view.SigningInfoRow{signed, missed, notActiveYet})
+				views.params.On("FindInt64By", validator_uptime.SignedBlocksWindowParam).Return(int64(4), nil)
+
+				updatedSigned := signed
+				updatedSigned.IndexOffset = 6
+				updatedSigned.MissedBlocksBitArray = []byte{0x00}
+				// Index 5 % 4 = 1 is missed in addition to index 0
+				updatedMissed := missed
+				updatedMissed.IndexOffset = 6
+				updatedMissed.MissedBlocksBitArray = []byte{0x03}
+				updatedMissed.MissedBlocksCounter = 2
+				updatedMissed.TotalMissedBlocks = 2
+				updatedMissed.MaybeLastMissedHeight = primptr.Int64(9)
+				views.signingInfos.On("UpsertAll", []view.SigningInfoRow{updatedSigned, updatedMissed}).Return(nil)
+				views.missedBlocks.On("InsertAll", []view.MissedBlockRow{{
+					OperatorAddress:      "missed",
+					ConsensusNodeAddress: "missedcons",
+					BlockHeight:          9,
+					MaybeBlockTime:       &blockTime,
+				}}).Return(nil)
+				views.slashingEvents.On("InsertAll", []view.SlashingEventRow{}).Return(nil)
+
+				return views.mocks()
+			},
+		},
+		{
+			Name:   "HandleSignedBlockClearingMissedBit",
+			Height: 10,
+			Events: []entity_event.Event{
+				&usecase_event.BlockCreated{
+					Block: &model.Block{
+						Height: 10,
+						Time:   blockTime,
+						Signatures: []model.BlockSignature{{
+							ValidatorAddress: "SIGNED",
+						}},
+					},
+				},
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				signed := newSigningInfo("signed", "SIGNED")
+				signed.IndexOffset = 4
+				signed.MissedBlocksBitArray = []byte{0x01}
+				signed.MissedBlocksCounter = 1
+				signed.TotalMissedBlocks = 1
+
+				views := newMockViews([]view.SigningInfoRow{signed})
+				views.params.On("FindInt64By", validator_uptime.SignedBlocksWindowParam).Return(int64(4), nil)
+
+				updatedSigned := signed
+				updatedSigned.IndexOffset = 5
+				updatedSigned.MissedBlocksBitArray = []byte{0x00}
+				updatedSigned.MissedBlocksCounter = 0
+				views.signingInfos.On("UpsertAll", []view.SigningInfoRow{updatedSigned}).Return(nil)
+				views.missedBlocks.On("InsertAll", []view.MissedBlockRow{}).Return(nil)
+				views.slashingEvents.On("InsertAll", []view.SlashingEventRow{}).Return(nil)
+
+				return views.mocks()
+			},
+		},
+		{
+			Name:   "HandleJailSlashAndUnjail",
+			Height: 10,
+			Events: []entity_event.Event{
+				&usecase_event.BlockCreated{
+					Block: &model.Block{
+						Height: 10,
+						Time:   blockTime,
+					},
+				},
+				usecase_event.NewValidatorSlashed(10, model.SlashValidatorParams{
+					ConsensusNodeAddress: "downtimecons",
+					SlashedPower:         "100",
+					Reason:               "missing_signature",
+				}),
+				usecase_event.NewValidatorJailed(10, "downtimecons", "missing_signature"),
+				usecase_event.NewValidatorSlashed(10, model.SlashValidatorParams{
+					ConsensusNodeAddress: "doublesigncons",
+					SlashedPower:         "200",
+					Reason:               "double_sign",
+				}),
+				usecase_event.NewValidatorJailed(10, "doublesigncons", "double_sign"),
+				&usecase_event.MsgUnjail{
+					MsgBase:       newMsgBase(usecase_event.MSG_UNJAIL, 10),
+					ValidatorAddr: "unjailed",
+				},
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				downtime := newSigningInfo("downtime", "DOWNTIME")
+				downtime.Active = false
+				downtime.IndexOffset = 10
+				downtime.MissedBlocksBitArray = []byte{0x0f}
+				downtime.MissedBlocksCounter = 4
+				downtime.TotalMissedBlocks = 6
+				doubleSign := newSigningInfo("doublesign", "DOUBLESIGN")
+				doubleSign.Active = false
+				unjailed := newSigningInfo("unjailed", "UNJAILED")
+				unjailed.Active = false
+				unjailed.Jailed = true
+
+				// Out of the active set, loaded when the events refer to them
+				views := newMockViews([]view.SigningInfoRow{})
+				views.params.On("FindInt64By", validator_uptime.SignedBlocksWindowParam).Return(int64(4), nil)
+				for _, signingInfo := range []view.SigningInfoRow{downtime, doubleSign} {
+					signingInfo := signingInfo
+					consensusNodeAddress := signingInfo.ConsensusNodeAddress
+					views.signingInfos.On("FindBy", view.SigningInfoIdentity{
+						MaybeConsensusNodeAddress: &consensusNodeAddress,
+					}).Return(&signingInfo, nil).Once()
+				}
+				views.signingInfos.On("FindBy", view.SigningInfoIdentity{
+					MaybeOperatorAddress: primptr.String("unjailed"),
+				}).Return(&unjailed, nil).Once()
+
+				jailedDowntime := downtime
+				jailedDowntime.Jailed = true
+				jailedDowntime.IndexOffset = 0
+				jailedDowntime.MissedBlocksBitArray = []byte{0x00}
+				jailedDowntime.MissedBlocksCounter = 0
+				jailedDoubleSign := doubleSign
+				jailedDoubleSign.Jailed = true
+				jailedDoubleSign.Tombstoned = true
+				unjailedUnjailed := unjailed
+				unjailedUnjailed.Jailed = false
+				views.signingInfos.
+					On("UpsertAll", []view.SigningInfoRow{jailedDowntime, jailedDoubleSign, unjailedUnjailed}).
+					Return(nil)
+				views.missedBlocks.On("InsertAll", []view.MissedBlockRow{}).Return(nil)
+				views.slashingEvents.On("InsertAll", []view.SlashingEventRow{{
+					OperatorAddress:      "downtime",
+					ConsensusNodeAddress: "downtimecons",
+					BlockHeight:          10,
+					MaybeBlockTime:       &blockTime,
+					Type:                 view.SLASHING_EVENT_TYPE_SLASHED,
+					MaybeReason:          primptr.String("missing_signature"),
+					MaybeSlashedPower:    primptr.String("100"),
+				}, {
+					OperatorAddress:      "downtime",
+					ConsensusNodeAddress: "downtimecons",
+					BlockHeight:          10,
+					MaybeBlockTime:       &blockTime,
+					Type:                 view.SLASHING_EVENT_TYPE_JAILED,
+					MaybeReason:          primptr.String("missing_signature"),
+				}, {
+					OperatorAddress:      "doublesign",
+					ConsensusNodeAddress: "doublesigncons",
+					BlockHeight:          10,
+					MaybeBlockTime:       &blockTime,
+					Type:                 view.SLASHING_EVENT_TYPE_SLASHED,
+					MaybeReason:          primptr.String("double_sign"),
+					MaybeSlashedPower:    primptr.String("200"),
+				}, {
+					OperatorAddress:      "doublesign",
+					ConsensusNodeAddress: "doublesigncons",
+					BlockHeight:          10,
+					MaybeBlockTime:       &blockTime,
+					Type:                 view.SLASHING_EVENT_TYPE_JAILED,
+					MaybeReason:          primptr.String("double_sign"),
+				}, {
+					OperatorAddress:      "unjailed",
+					ConsensusNodeAddress: "unjailedcons",
+					BlockHeight:          10,
+					MaybeBlockTime:       &blockTime,
+					Type:                 view.SLASHING_EVENT_TYPE_UNJAILED,
+				}}).Return(nil)
+
+				return views.mocks()
+			},
+		},
+		{
+			Name:   "HandlePowerChanged",
+			Height: 10,
+			Events: []entity_event.Event{
+				usecase_event.NewPowerChanged(10, model.PowerChangeParams{
+					TendermintPubkey: base64.StdEncoding.EncodeToString(tendermintPubkey),
+					Power:            "100",
+				}),
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				views := newMockViews([]view.SigningInfoRow{})
+				views.signingInfos.On("FindBy", view.SigningInfoIdentity{
+					MaybeConsensusNodeAddress: &consensusNodeAddress,
+				}).Return(nil, rdb.ErrNoRows)
+				views.validators.On("FindLastBy", validatorbase_view.ValidatorIdentity{
+					MaybeConsensusNodeAddress: &consensusNodeAddress,
+				}).Return(&validatorbase_view.ValidatorRow{
+					OperatorAddress:      "bonded",
+					ConsensusNodeAddress: consensusNodeAddress,
+				}, nil)
+
+				views.signingInfos.On("UpsertAll", []view.SigningInfoRow{{
+					OperatorAddress:      "bonded",
+					ConsensusNodeAddress: consensusNodeAddress,
+					TendermintAddress:    tendermintAddress,
+					Active:               true,
+					ActiveFromHeight:     12,
+					StartHeight:          12,
+					MissedBlocksBitArray: []byte{},
+				}}).Return(nil)
+				views.missedBlocks.On("InsertAll", []view.MissedBlockRow{}).Return(nil)
+				views.slashingEvents.On("InsertAll", []view.SlashingEventRow{}).Return(nil)
+
+				return views.mocks()
+			},
+		},
+		{
+			Name:   "HandlePassedParamChangeProposal",
+			Height: 10,
+			Events: []entity_event.Event{
+				&usecase_event.MsgSubmitParamChangeProposal{
+					MsgBase: newMsgBase(usecase_event.MSG_SUBMIT_PARAM_CHANGE_PROPOSAL, 10),
+					MsgSubmitParamChangeProposalParams: model.MsgSubmitParamChangeProposalParams{
+						MaybeProposalId: primptr.String("2"),
+						Content: model.MsgSubmitParamChangeProposalContent{
+							Changes: []model.MsgSubmitParamChangeProposalChange{{
+								Subspace: "slashing",
+								Key:      "SignedBlocksWindow",
+								Value:    json.RawMessage(`"200"`),
+							}, {
+								Subspace: "slashing",
+								Key:      "DowntimeJailDuration",
+								Value:    json.RawMessage(`"600000000000"`),
+							}, {
+								Subspace: "staking",
+								Key:      "MaxValidators",
+								Value:    json.RawMessage(`100`),
+							}},
+						},
+					},
+				},
+				usecase_event.NewProposalEnded(10, "1", "proposal_passed"),
+			},
+			MockFunc: func() []*testify_mock.Mock {
+				views := newMockViews([]view.SigningInfoRow{})
+				views.paramChanges.On("InsertAll", []view.ParamChangeRow{{
+					ProposalId: "2",
+					Key:        "signed_blocks_window",
+					Value:      "200",
+				}, {
+					ProposalId: "2",
+					Key:        "downtime_jail_duration",
+					Value:      "600000000000ns",
+				}}).Return(nil)
+				views.paramChanges.On("ListByProposalId", "1").Return([]view.ParamChangeRow{{
+					ProposalId: "1",
+					Key:        "min_signed_per_window",
+					Value:      "0.100000000000000000",
+				}}, nil)
+				views.paramChanges.On("DeleteByProposalId", "1").Return(nil)
+				views.params.On("Set", validator_uptime.MinSignedPerWindowParam, "0.100000000000000000").Return(nil)
+
+				views.signingInfos.On("UpsertAll", []view.SigningInfoRow{}).Return(nil)
+				views.missedBlocks.On("InsertAll", []view.MissedBlockRow{}).Return(nil)
+				views.slashingEvents.On("InsertAll", []view.SlashingEventRow{}).Return(nil)
+
+				return views.mocks()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		mockRDbConn := NewMockRDbConn()
+		mockTx := NewMockRDbTx()
+		mockRDbConn.On("Begin").Return(mockTx, nil)
+
+		mocks := tc.MockFunc()
+		mocks = append(mocks, &mockRDbConn.Mock)
+		mocks = append(mocks, &mockTx.Mock)
+
+		projection := NewValidatorUptimeProjection(mockRDbConn)
+		err := projection.HandleEvents(tc.Height, tc.Events)
+		assert.NoError(t, err)
+
+		for _, m := range mocks {
+			m.AssertExpectations(t)
+		}
+
+		fmt.Println(tc.Name, "Passed")
+	}
+}
+
+func TestValidatorUptime_ResetFromHeight(t *testing.T) {
+	mockRDbConn := NewMockRDbConn()
+
+	projection := NewValidatorUptimeProjection(mockRDbConn)
+	err := projection.Reset(10)
+	assert.ErrorIs(t, err, projection_entity.ErrProjectionNotRebuildableFromHeight)
+
+	mockRDbConn.AssertNotCalled(t, "Begin")
+}
@@ -0,0 +1,153 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/view"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+)
+
+const MISSED_BLOCKS_TABLE_NAME = "view_validator_missed_blocks"
+
+type MissedBlocks interface {
+	InsertAll([]MissedBlockRow) error
+	ListByOperatorAddress(
+		operatorAddress string,
+		order MissedBlocksListOrder,
+		pagination *pagination.Pagination,
+	) ([]MissedBlockRow, *pagination.Result, error)
+}
+
+type MissedBlocksView struct {
+	rdb *rdb.Handle
+}
+
+func NewMissedBlocksView(handle *rdb.Handle) MissedBlocks {
+	return &MissedBlocksView{
+		handle,
+	}
+}
+
+func (missedBlocksView *MissedBlocksView) InsertAll(rows []MissedBlockRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	stmtBuilder := missedBlocksView.rdb.StmtBuilder.
+		Insert(MISSED_BLOCKS_TABLE_NAME).
+		Columns(
+			"operator_address",
+			"consensus_node_address",
+			"block_height",
+			"block_time",
+		)
+	for i := range rows {
+		stmtBuilder = stmtBuilder.Values(
+			rows[i].OperatorAddress,
+			rows[i].ConsensusNodeAddress,
+			rows[i].BlockHeight,
+			missedBlocksView.rdb.Tton(rows[i].MaybeBlockTime),
+		)
+	}
+
+	sql, sqlArgs, err := stmtBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("error building missed blocks insertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := missedBlocksView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error inserting missed blocks into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != int64(len(rows)) {
+		return fmt.Errorf("error inserting missed blocks into the table: mismatched number of rows inserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (missedBlocksView *MissedBlocksView) ListByOperatorAddress(
+	operatorAddress string,
+	order MissedBlocksListOrder,
+	pagination *pagination.Pagination,
+) ([]MissedBlockRow, *pagination.Result, error) {
+	stmtBuilder := missedBlocksView.rdb.StmtBuilder.Select(
+		"operator_address",
+		"consensus_node_address",
+		"block_height",
+		"block_time",
+	).From(
+		MISSED_BLOCKS_TABLE_NAME,
+	).Where(
+		"operator_address = ?", operatorAddress,
+	)
+
+	if order.Height == view.ORDER_DESC {
+		stmtBuilder = stmtBuilder.OrderBy("block_height DESC")
+	} else {
+		stmtBuilder = stmtBuilder.OrderBy("block_height")
+	}
+
+	rDbPagination := rdb.NewRDbPaginationBuilder(
+		pagination,
+		missedBlocksView.rdb,
+	).BuildStmt(stmtBuilder)
+	sql, sqlArgs, err := rDbPagination.ToStmtBuilder().ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building missed blocks select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := missedBlocksView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error executing missed blocks select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	missedBlocks := make([]MissedBlockRow, 0)
+	for rowsResult.Next() {
+		var missedBlock MissedBlockRow
+		blockTimeReader := missedBlocksView.rdb.NtotReader()
+		if err = rowsResult.Scan(
+			&missedBlock.OperatorAddress,
+			&missedBlock.ConsensusNodeAddress,
+			&missedBlock.BlockHeight,
+			blockTimeReader.ScannableArg(),
+		); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				return nil, nil, rdb.ErrNoRows
+			}
+			return nil, nil, fmt.Errorf("error scanning missed block row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		blockTime, parseErr := blockTimeReader.Parse()
+		if parseErr != nil {
+			return nil, nil, fmt.Errorf("error parsing missed block time: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		missedBlock.MaybeBlockTime = blockTime
+
+		missedBlocks = append(missedBlocks, missedBlock)
+	}
+
+	paginationResult, err := rDbPagination.Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error preparing pagination result: %v", err)
+	}
+
+	return missedBlocks, paginationResult, nil
+}
+
+type MissedBlocksListOrder struct {
+	Height view.ORDER
+}
+
+// MissedBlockRow is a block the validator did not sign. BlockHeight is the height of the commit, which is one below
+// the height of the block carrying the signatures.
+type MissedBlockRow struct {
+	OperatorAddress      string           `json:"operatorAddress"`
+	ConsensusNodeAddress string           `json:"consensusNodeAddress"`
+	BlockHeight          int64            `json:"blockHeight"`
+	MaybeBlockTime       *utctime.UTCTime `json:"blockTime"`
+}
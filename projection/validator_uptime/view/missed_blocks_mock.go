@@ -0,0 +1,26 @@
+package view
+
+import (
+	pagination_interface "github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	testify_mock "github.com/stretchr/testify/mock"
+)
+
+type MockMissedBlocksView struct {
+	testify_mock.Mock
+}
+
+func (missedBlocksView *MockMissedBlocksView) InsertAll(rows []MissedBlockRow) error {
+	mockArgs := missedBlocksView.Called(rows)
+	return mockArgs.Error(0)
+}
+
+func (missedBlocksView *MockMissedBlocksView) ListByOperatorAddress(
+	operatorAddress string,
+	order MissedBlocksListOrder,
+	pagination *pagination_interface.Pagination,
+) ([]MissedBlockRow, *pagination_interface.Result, error) {
+	mockArgs := missedBlocksView.Called(operatorAddress, order, pagination)
+	rows, _ := mockArgs.Get(0).([]MissedBlockRow)
+	paginationResult, _ := mockArgs.Get(1).(*pagination_interface.Result)
+	return rows, paginationResult, mockArgs.Error(2)
+}
@@ -0,0 +1,117 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+)
+
+const PARAMS_TABLE_NAME = "view_validator_uptime_params"
+const PARAM_CHANGES_TABLE_NAME = "view_validator_uptime_param_changes"
+
+// ParamChanges keeps the slashing param changes of the submitted proposals until the proposals end
+type ParamChanges interface {
+	InsertAll([]ParamChangeRow) error
+	ListByProposalId(proposalId string) ([]ParamChangeRow, error)
+	DeleteByProposalId(proposalId string) error
+}
+
+type ParamChangesView struct {
+	rdb *rdb.Handle
+}
+
+func NewParamChangesView(handle *rdb.Handle) ParamChanges {
+	return &ParamChangesView{
+		handle,
+	}
+}
+
+func (paramChangesView *ParamChangesView) InsertAll(rows []ParamChangeRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	stmtBuilder := paramChangesView.rdb.StmtBuilder.
+		Insert(PARAM_CHANGES_TABLE_NAME).
+		Columns(
+			"proposal_id",
+			"key",
+			"value",
+		)
+	for _, row := range rows {
+		stmtBuilder = stmtBuilder.Values(row.ProposalId, row.Key, row.Value)
+	}
+
+	sql, sqlArgs, err := stmtBuilder.Suffix(
+		"ON CONFLICT(proposal_id, key) DO UPDATE SET value = EXCLUDED.value",
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building param changes insertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	if _, err = paramChangesView.rdb.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error inserting param changes into the table: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (paramChangesView *ParamChangesView) ListByProposalId(proposalId string) ([]ParamChangeRow, error) {
+	sql, sqlArgs, err := paramChangesView.rdb.StmtBuilder.Select(
+		"proposal_id",
+		"key",
+		"value",
+	).From(
+		PARAM_CHANGES_TABLE_NAME,
+	).Where(
+		"proposal_id = ?", proposalId,
+	).OrderBy("key").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building param changes select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := paramChangesView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing param changes select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	rows := make([]ParamChangeRow, 0)
+	for rowsResult.Next() {
+		var row ParamChangeRow
+		if err = rowsResult.Scan(&row.ProposalId, &row.Key, &row.Value); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				return nil, rdb.ErrNoRows
+			}
+			return nil, fmt.Errorf("error scanning param change row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (paramChangesView *ParamChangesView) DeleteByProposalId(proposalId string) error {
+	sql, sqlArgs, err := paramChangesView.rdb.StmtBuilder.
+		Delete(PARAM_CHANGES_TABLE_NAME).
+		Where("proposal_id = ?", proposalId).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building param changes deletion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	if _, err = paramChangesView.rdb.Exec(sql, sqlArgs...); err != nil {
+		return fmt.Errorf("error deleting param changes: %v: %w", err, rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+// ParamChangeRow is a change to a slashing param, keyed by the param key of the projection params table
+type ParamChangeRow struct {
+	ProposalId string
+	Key        string
+	Value      string
+}
@@ -0,0 +1,25 @@
+package view
+
+import (
+	testify_mock "github.com/stretchr/testify/mock"
+)
+
+type MockParamChangesView struct {
+	testify_mock.Mock
+}
+
+func (paramChangesView *MockParamChangesView) InsertAll(rows []ParamChangeRow) error {
+	mockArgs := paramChangesView.Called(rows)
+	return mockArgs.Error(0)
+}
+
+func (paramChangesView *MockParamChangesView) ListByProposalId(proposalId string) ([]ParamChangeRow, error) {
+	mockArgs := paramChangesView.Called(proposalId)
+	rows, _ := mockArgs.Get(0).([]ParamChangeRow)
+	return rows, mockArgs.Error(1)
+}
+
+func (paramChangesView *MockParamChangesView) DeleteByProposalId(proposalId string) error {
+	mockArgs := paramChangesView.Called(proposalId)
+	return mockArgs.Error(0)
+}
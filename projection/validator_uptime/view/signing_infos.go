@@ -0,0 +1,218 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	sq "github.com/Masterminds/squirrel"
+)
+
+const VALIDATORS_TABLE_NAME = "view_validator_uptime_validators"
+const SIGNING_INFOS_TABLE_NAME = "view_validator_signing_infos"
+
+type SigningInfos interface {
+	UpsertAll([]SigningInfoRow) error
+	FindBy(SigningInfoIdentity) (*SigningInfoRow, error)
+	ListActive() ([]SigningInfoRow, error)
+}
+
+type SigningInfosView struct {
+	rdb *rdb.Handle
+}
+
+func NewSigningInfosView(handle *rdb.Handle) SigningInfos {
+	return &SigningInfosView{
+		handle,
+	}
+}
+
+func (signingInfosView *SigningInfosView) UpsertAll(rows []SigningInfoRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	stmtBuilder := signingInfosView.rdb.StmtBuilder.
+		Insert(SIGNING_INFOS_TABLE_NAME).
+		Columns(
+			"operator_address",
+			"consensus_node_address",
+			"tendermint_address",
+			"active",
+			"active_from_height",
+			"start_height",
+			"index_offset",
+			"missed_blocks_counter",
+			"total_missed_blocks",
+			"missed_blocks_bit_array",
+			"jailed",
+			"tombstoned",
+			"last_missed_height",
+		)
+	for _, row := range rows {
+		bitArray := row.MissedBlocksBitArray
+		if bitArray == nil {
+			bitArray = []byte{}
+		}
+		stmtBuilder = stmtBuilder.Values(
+			row.OperatorAddress,
+			row.ConsensusNodeAddress,
+			row.TendermintAddress,
+			row.Active,
+			row.ActiveFromHeight,
+			row.StartHeight,
+			row.IndexOffset,
+			row.MissedBlocksCounter,
+			row.TotalMissedBlocks,
+			bitArray,
+			row.Jailed,
+			row.Tombstoned,
+			row.MaybeLastMissedHeight,
+		)
+	}
+
+	sql, sqlArgs, err := stmtBuilder.Suffix(
+		"ON CONFLICT(operator_address) DO UPDATE SET " +
+			"consensus_node_address = EXCLUDED.consensus_node_address, " +
+			"tendermint_address = EXCLUDED.tendermint_address, " +
+			"active = EXCLUDED.active, " +
+			"active_from_height = EXCLUDED.active_from_height, " +
+			"start_height = EXCLUDED.start_height, " +
+			"index_offset = EXCLUDED.index_offset, " +
+			"missed_blocks_counter = EXCLUDED.missed_blocks_counter, " +
+			"total_missed_blocks = EXCLUDED.total_missed_blocks, " +
+			"missed_blocks_bit_array = EXCLUDED.missed_blocks_bit_array, " +
+			"jailed = EXCLUDED.jailed, " +
+			"tombstoned = EXCLUDED.tombstoned, " +
+			"last_missed_height = EXCLUDED.last_missed_height",
+	).ToSql()
+	if err != nil {
+		return fmt.Errorf("error building signing infos upsertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := signingInfosView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error upserting signing infos into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != int64(len(rows)) {
+		return fmt.Errorf("error upserting signing infos into the table: mismatched number of rows upserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (signingInfosView *SigningInfosView) FindBy(identity SigningInfoIdentity) (*SigningInfoRow, error) {
+	stmtBuilder := signingInfosView.selectStmtBuilder()
+	if identity.MaybeOperatorAddress != nil {
+		stmtBuilder = stmtBuilder.Where("operator_address = ?", *identity.MaybeOperatorAddress)
+	}
+	if identity.MaybeConsensusNodeAddress != nil {
+		stmtBuilder = stmtBuilder.Where("consensus_node_address = ?", *identity.MaybeConsensusNodeAddress)
+	}
+
+	sql, sqlArgs, err := stmtBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building signing info selection sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	var row SigningInfoRow
+	if err = signingInfosView.scanRow(signingInfosView.rdb.QueryRow(sql, sqlArgs...), &row); err != nil {
+		if errors.Is(err, rdb.ErrNoRows) {
+			return nil, rdb.ErrNoRows
+		}
+		return nil, fmt.Errorf("error scanning signing info row: %v: %w", err, rdb.ErrQuery)
+	}
+
+	return &row, nil
+}
+
+// ListActive returns the signing infos of the validators in the active set, whose signing windows move at every height
+func (signingInfosView *SigningInfosView) ListActive() ([]SigningInfoRow, error) {
+	sql, sqlArgs, err := signingInfosView.selectStmtBuilder().Where("active").OrderBy("operator_address").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building signing infos select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := signingInfosView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing signing infos select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	rows := make([]SigningInfoRow, 0)
+	for rowsResult.Next() {
+		var row SigningInfoRow
+		if err = signingInfosView.scanRow(rowsResult, &row); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				return nil, rdb.ErrNoRows
+			}
+			return nil, fmt.Errorf("error scanning signing info row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (signingInfosView *SigningInfosView) selectStmtBuilder() sq.SelectBuilder {
+	return signingInfosView.rdb.StmtBuilder.Select(
+		"operator_address",
+		"consensus_node_address",
+		"tendermint_address",
+		"active",
+		"active_from_height",
+		"start_height",
+		"index_offset",
+		"missed_blocks_counter",
+		"total_missed_blocks",
+		"missed_blocks_bit_array",
+		"jailed",
+		"tombstoned",
+		"last_missed_height",
+	).From(
+		SIGNING_INFOS_TABLE_NAME,
+	)
+}
+
+func (signingInfosView *SigningInfosView) scanRow(scanner rdb.RowResult, row *SigningInfoRow) error {
+	return scanner.Scan(
+		&row.OperatorAddress,
+		&row.ConsensusNodeAddress,
+		&row.TendermintAddress,
+		&row.Active,
+		&row.ActiveFromHeight,
+		&row.StartHeight,
+		&row.IndexOffset,
+		&row.MissedBlocksCounter,
+		&row.TotalMissedBlocks,
+		&row.MissedBlocksBitArray,
+		&row.Jailed,
+		&row.Tombstoned,
+		&row.MaybeLastMissedHeight,
+	)
+}
+
+type SigningInfoIdentity struct {
+	MaybeOperatorAddress      *string
+	MaybeConsensusNodeAddress *string
+}
+
+// SigningInfoRow is the signing info of a validator kept the same way as the slashing module does. IndexOffset
+// counts the blocks the validator was expected to sign since it started signing or was last jailed for downtime,
+// and the missed blocks bit array is indexed by IndexOffset modulo the signed blocks window.
+type SigningInfoRow struct {
+	OperatorAddress       string
+	ConsensusNodeAddress  string
+	TendermintAddress     string
+	Active                bool
+	ActiveFromHeight      int64
+	StartHeight           int64
+	IndexOffset           int64
+	MissedBlocksCounter   int64
+	TotalMissedBlocks     int64
+	MissedBlocksBitArray  []byte
+	Jailed                bool
+	Tombstoned            bool
+	MaybeLastMissedHeight *int64
+}
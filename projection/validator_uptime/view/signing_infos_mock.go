@@ -0,0 +1,26 @@
+package view
+
+import (
+	testify_mock "github.com/stretchr/testify/mock"
+)
+
+type MockSigningInfosView struct {
+	testify_mock.Mock
+}
+
+func (signingInfosView *MockSigningInfosView) UpsertAll(rows []SigningInfoRow) error {
+	mockArgs := signingInfosView.Called(rows)
+	return mockArgs.Error(0)
+}
+
+func (signingInfosView *MockSigningInfosView) FindBy(identity SigningInfoIdentity) (*SigningInfoRow, error) {
+	mockArgs := signingInfosView.Called(identity)
+	row, _ := mockArgs.Get(0).(*SigningInfoRow)
+	return row, mockArgs.Error(1)
+}
+
+func (signingInfosView *MockSigningInfosView) ListActive() ([]SigningInfoRow, error) {
+	mockArgs := signingInfosView.Called()
+	rows, _ := mockArgs.Get(0).([]SigningInfoRow)
+	return rows, mockArgs.Error(1)
+}
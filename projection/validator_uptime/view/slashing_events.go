@@ -0,0 +1,172 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	"github.com/AstraProtocol/astra-indexing/appinterface/projection/view"
+	"github.com/AstraProtocol/astra-indexing/appinterface/rdb"
+	"github.com/AstraProtocol/astra-indexing/external/utctime"
+)
+
+const SLASHING_EVENTS_TABLE_NAME = "view_validator_slashing_events"
+
+const (
+	SLASHING_EVENT_TYPE_JAILED   = "jailed"
+	SLASHING_EVENT_TYPE_SLASHED  = "slashed"
+	SLASHING_EVENT_TYPE_UNJAILED = "unjailed"
+)
+
+type SlashingEvents interface {
+	InsertAll([]SlashingEventRow) error
+	ListByOperatorAddress(
+		operatorAddress string,
+		order SlashingEventsListOrder,
+		pagination *pagination.Pagination,
+	) ([]SlashingEventRow, *pagination.Result, error)
+}
+
+type SlashingEventsView struct {
+	rdb *rdb.Handle
+}
+
+func NewSlashingEventsView(handle *rdb.Handle) SlashingEvents {
+	return &SlashingEventsView{
+		handle,
+	}
+}
+
+func (slashingEventsView *SlashingEventsView) InsertAll(rows []SlashingEventRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	stmtBuilder := slashingEventsView.rdb.StmtBuilder.
+		Insert(SLASHING_EVENTS_TABLE_NAME).
+		Columns(
+			"operator_address",
+			"consensus_node_address",
+			"block_height",
+			"block_time",
+			"type",
+			"reason",
+			"slashed_power",
+		)
+	for i := range rows {
+		stmtBuilder = stmtBuilder.Values(
+			rows[i].OperatorAddress,
+			rows[i].ConsensusNodeAddress,
+			rows[i].BlockHeight,
+			slashingEventsView.rdb.Tton(rows[i].MaybeBlockTime),
+			rows[i].Type,
+			rows[i].MaybeReason,
+			rows[i].MaybeSlashedPower,
+		)
+	}
+
+	sql, sqlArgs, err := stmtBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("error building slashing events insertion sql: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	result, err := slashingEventsView.rdb.Exec(sql, sqlArgs...)
+	if err != nil {
+		return fmt.Errorf("error inserting slashing events into the table: %v: %w", err, rdb.ErrWrite)
+	}
+	if result.RowsAffected() != int64(len(rows)) {
+		return fmt.Errorf("error inserting slashing events into the table: mismatched number of rows inserted: %w", rdb.ErrWrite)
+	}
+
+	return nil
+}
+
+func (slashingEventsView *SlashingEventsView) ListByOperatorAddress(
+	operatorAddress string,
+	order SlashingEventsListOrder,
+	pagination *pagination.Pagination,
+) ([]SlashingEventRow, *pagination.Result, error) {
+	stmtBuilder := slashingEventsView.rdb.StmtBuilder.Select(
+		"operator_address",
+		"consensus_node_address",
+		"block_height",
+		"block_time",
+		"type",
+		"reason",
+		"slashed_power",
+	).From(
+		SLASHING_EVENTS_TABLE_NAME,
+	).Where(
+		"operator_address = ?", operatorAddress,
+	)
+
+	if order.Height == view.ORDER_DESC {
+		stmtBuilder = stmtBuilder.OrderBy("block_height DESC", "id DESC")
+	} else {
+		stmtBuilder = stmtBuilder.OrderBy("block_height", "id")
+	}
+
+	rDbPagination := rdb.NewRDbPaginationBuilder(
+		pagination,
+		slashingEventsView.rdb,
+	).BuildStmt(stmtBuilder)
+	sql, sqlArgs, err := rDbPagination.ToStmtBuilder().ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building slashing events select SQL: %v: %w", err, rdb.ErrBuildSQLStmt)
+	}
+
+	rowsResult, err := slashingEventsView.rdb.Query(sql, sqlArgs...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error executing slashing events select SQL: %v: %w", err, rdb.ErrQuery)
+	}
+	defer rowsResult.Close()
+
+	slashingEvents := make([]SlashingEventRow, 0)
+	for rowsResult.Next() {
+		var slashingEvent SlashingEventRow
+		blockTimeReader := slashingEventsView.rdb.NtotReader()
+		if err = rowsResult.Scan(
+			&slashingEvent.OperatorAddress,
+			&slashingEvent.ConsensusNodeAddress,
+			&slashingEvent.BlockHeight,
+			blockTimeReader.ScannableArg(),
+			&slashingEvent.Type,
+			&slashingEvent.MaybeReason,
+			&slashingEvent.MaybeSlashedPower,
+		); err != nil {
+			if errors.Is(err, rdb.ErrNoRows) {
+				return nil, nil, rdb.ErrNoRows
+			}
+			return nil, nil, fmt.Errorf("error scanning slashing event row: %v: %w", err, rdb.ErrQuery)
+		}
+
+		blockTime, parseErr := blockTimeReader.Parse()
+		if parseErr != nil {
+			return nil, nil, fmt.Errorf("error parsing slashing event block time: %v: %w", parseErr, rdb.ErrQuery)
+		}
+		slashingEvent.MaybeBlockTime = blockTime
+
+		slashingEvents = append(slashingEvents, slashingEvent)
+	}
+
+	paginationResult, err := rDbPagination.Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error preparing pagination result: %v", err)
+	}
+
+	return slashingEvents, paginationResult, nil
+}
+
+type SlashingEventsListOrder struct {
+	Height view.ORDER
+}
+
+type SlashingEventRow struct {
+	OperatorAddress      string           `json:"operatorAddress"`
+	ConsensusNodeAddress string           `json:"consensusNodeAddress"`
+	BlockHeight          int64            `json:"blockHeight"`
+	MaybeBlockTime       *utctime.UTCTime `json:"blockTime"`
+	Type                 string           `json:"type"`
+	MaybeReason          *string          `json:"reason"`
+	MaybeSlashedPower    *string          `json:"slashedPower"`
+}
@@ -0,0 +1,26 @@
+package view
+
+import (
+	pagination_interface "github.com/AstraProtocol/astra-indexing/appinterface/pagination"
+	testify_mock "github.com/stretchr/testify/mock"
+)
+
+type MockSlashingEventsView struct {
+	testify_mock.Mock
+}
+
+func (slashingEventsView *MockSlashingEventsView) InsertAll(rows []SlashingEventRow) error {
+	mockArgs := slashingEventsView.Called(rows)
+	return mockArgs.Error(0)
+}
+
+func (slashingEventsView *MockSlashingEventsView) ListByOperatorAddress(
+	operatorAddress string,
+	order SlashingEventsListOrder,
+	pagination *pagination_interface.Pagination,
+) ([]SlashingEventRow, *pagination_interface.Result, error) {
+	mockArgs := slashingEventsView.Called(operatorAddress, order, pagination)
+	rows, _ := mockArgs.Get(0).([]SlashingEventRow)
+	paginationResult, _ := mockArgs.Get(1).(*pagination_interface.Result)
+	return rows, paginationResult, mockArgs.Error(2)
+}
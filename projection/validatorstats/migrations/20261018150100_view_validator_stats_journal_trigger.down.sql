@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS view_validator_stats_journal ON view_validator_stats;
@@ -0,0 +1,2 @@
+CREATE TRIGGER view_validator_stats_journal AFTER INSERT OR UPDATE OR DELETE ON view_validator_stats
+    FOR EACH ROW EXECUTE PROCEDURE journal_projection_view();
@@ -17,6 +17,8 @@ import (
 )
 
 var _ entity_projection.Projection = &ValidatorStats{}
+var _ entity_projection.RebuildableProjection = &ValidatorStats{}
+var _ entity_projection.RollbackableProjection = &ValidatorStats{}
 
 const TOTAL_REWARD = "total_reward"
 const TOTAL_DELEGATE = "total_delegate"
@@ -73,6 +75,20 @@ func (projection *ValidatorStats) OnInit() error {
 	return nil
 }
 
+func (projection *ValidatorStats) Reset(fromHeight int64) error {
+	return projection.ResetViews(projection.rdbConn, fromHeight, []string{
+		"view_validator_stats",
+	})
+}
+
+// Rollback undoes the journaled changes of the stats accumulated over the blocks
+func (projection *ValidatorStats) Rollback(fromHeight int64, toHeight int64) error {
+	if _, err := projection.RollbackViews(projection.rdbConn, fromHeight, []string{}); err != nil {
+		return fmt.Errorf("error rolling back validator stats views: %w", err)
+	}
+	return nil
+}
+
 func (projection *ValidatorStats) HandleEvents(height int64, events []event_entity.Event) error {
 	rdbTx, err := projection.rdbConn.Begin()
 	if err != nil {
@@ -87,6 +103,9 @@ func (projection *ValidatorStats) HandleEvents(height int64, events []event_enti
 	}()
 
 	rdbTxHandle := rdbTx.ToHandle()
+	if err = projection.JournalViews(rdbTxHandle, height); err != nil {
+		return fmt.Errorf("error journaling views: %v", err)
+	}
 	validatorStatsView := view.NewValidatorStats(rdbTxHandle)
 
 	rawTotalReward, err := validatorStatsView.FindBy(TOTAL_REWARD)
@@ -0,0 +1,97 @@
+package syncstrategy
+
+import (
+	"sync"
+	"time"
+)
+
+// latencySmoothingFactor is the weight of a new sample in the moving average of the worker latency
+const latencySmoothingFactor = 0.2
+
+// adaptiveConcurrency adjusts the number of concurrent sync block workers in an AIMD manner.
+//
+// The limit starts from the minimum and doubles every round of workers (slow start) until the first
+// error or latency above the target. Afterwards it grows by one every round of successful workers
+// while the average latency is within the target and shrinks by one every round otherwise. Every
+// worker error halves the limit, so that the limit follows the error rate of the RPC nodes.
+type adaptiveConcurrency struct {
+	mutex sync.Mutex
+
+	minLimit      float64
+	maxLimit      float64
+	targetLatency time.Duration
+
+	limit          float64
+	isSlowStart    bool
+	averageLatency time.Duration
+}
+
+func newAdaptiveConcurrency(minLimit int, maxLimit int, targetLatency time.Duration) *adaptiveConcurrency {
+	return &adaptiveConcurrency{
+		minLimit:      float64(minLimit),
+		maxLimit:      float64(maxLimit),
+		targetLatency: targetLatency,
+
+		limit:       float64(minLimit),
+		isSlowStart: true,
+	}
+}
+
+// Limit returns the current number of workers allowed to run concurrently
+func (concurrency *adaptiveConcurrency) Limit() int {
+	concurrency.mutex.Lock()
+	defer concurrency.mutex.Unlock()
+
+	return int(concurrency.limit)
+}
+
+// AverageLatency returns the moving average of the successful worker latencies
+func (concurrency *adaptiveConcurrency) AverageLatency() time.Duration {
+	concurrency.mutex.Lock()
+	defer concurrency.mutex.Unlock()
+
+	return concurrency.averageLatency
+}
+
+// OnSuccess records the latency of a successful worker
+func (concurrency *adaptiveConcurrency) OnSuccess(latency time.Duration) {
+	concurrency.mutex.Lock()
+	defer concurrency.mutex.Unlock()
+
+	if concurrency.averageLatency == 0 {
+		concurrency.averageLatency = latency
+	} else {
+		concurrency.averageLatency += time.Duration(
+			latencySmoothingFactor * float64(latency-concurrency.averageLatency),
+		)
+	}
+
+	if concurrency.averageLatency > concurrency.targetLatency {
+		concurrency.isSlowStart = false
+		concurrency.setLimit(concurrency.limit - 1/concurrency.limit)
+		return
+	}
+	if concurrency.isSlowStart {
+		concurrency.setLimit(concurrency.limit + 1)
+	} else {
+		concurrency.setLimit(concurrency.limit + 1/concurrency.limit)
+	}
+}
+
+// OnFailure records a worker error
+func (concurrency *adaptiveConcurrency) OnFailure() {
+	concurrency.mutex.Lock()
+	defer concurrency.mutex.Unlock()
+
+	concurrency.isSlowStart = false
+	concurrency.setLimit(concurrency.limit / 2)
+}
+
+func (concurrency *adaptiveConcurrency) setLimit(limit float64) {
+	if limit < concurrency.minLimit {
+		limit = concurrency.minLimit
+	} else if limit > concurrency.maxLimit {
+		limit = concurrency.maxLimit
+	}
+	concurrency.limit = limit
+}
@@ -0,0 +1,213 @@
+package syncstrategy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/AstraProtocol/astra-indexing/entity/command"
+	applogger "github.com/AstraProtocol/astra-indexing/external/logger"
+)
+
+var _ Strategy = &Pipeline{}
+
+const DEFAULT_PIPELINE_MIN_CONCURRENCY = 1
+const DEFAULT_PIPELINE_MAX_CONCURRENCY = 10
+const DEFAULT_PIPELINE_BUFFER_SIZE = 100
+const DEFAULT_PIPELINE_TARGET_LATENCY = 2 * time.Second
+const DEFAULT_PIPELINE_MAX_RETRY_INTERVAL = 30 * time.Second
+const DEFAULT_PIPELINE_MAX_RETRY_TIME = 5 * time.Minute
+
+type PipelineConfig struct {
+	// Bounds of the adaptive number of concurrent sync block workers
+	MinConcurrency int
+	MaxConcurrency int
+	// Maximum number of blocks fetched ahead of the next block to hand over
+	BufferSize int
+	// Average worker latency above which the concurrency shrinks
+	TargetLatency time.Duration
+	// Retry of a single height, the synchronization fails once MaxRetryTime is elapsed
+	MaxRetryInterval time.Duration
+	MaxRetryTime     time.Duration
+}
+
+// Pipeline sync strategy keeps a sliding buffer of blocks synchronized ahead of the next block to
+// hand over. Failed heights are retried individually with backoff, and the number of concurrent
+// workers adapts to the worker latency and error rate. Blocks are handed over in order as soon as
+// the head of the buffer is synchronized.
+type Pipeline struct {
+	logger applogger.Logger
+
+	config PipelineConfig
+
+	// Kept across synchronizations so that the concurrency does not restart from the minimum
+	concurrency *adaptiveConcurrency
+}
+
+type pipelineResult struct {
+	height   int64
+	commands []command.Command
+	err      error
+}
+
+func NewPipeline(logger applogger.Logger, config PipelineConfig) *Pipeline {
+	if config.MinConcurrency <= 0 {
+		config.MinConcurrency = DEFAULT_PIPELINE_MIN_CONCURRENCY
+	}
+	if config.MaxConcurrency <= 0 {
+		config.MaxConcurrency = DEFAULT_PIPELINE_MAX_CONCURRENCY
+	}
+	if config.MaxConcurrency < config.MinConcurrency {
+		config.MaxConcurrency = config.MinConcurrency
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = DEFAULT_PIPELINE_BUFFER_SIZE
+	}
+	if config.BufferSize < config.MaxConcurrency {
+		config.BufferSize = config.MaxConcurrency
+	}
+	if config.TargetLatency <= 0 {
+		config.TargetLatency = DEFAULT_PIPELINE_TARGET_LATENCY
+	}
+	if config.MaxRetryInterval <= 0 {
+		config.MaxRetryInterval = DEFAULT_PIPELINE_MAX_RETRY_INTERVAL
+	}
+	if config.MaxRetryTime <= 0 {
+		config.MaxRetryTime = DEFAULT_PIPELINE_MAX_RETRY_TIME
+	}
+
+	return &Pipeline{
+		logger: logger.WithFields(applogger.LogFields{
+			"module":         "PipelineStrategy",
+			"minConcurrency": config.MinConcurrency,
+			"maxConcurrency": config.MaxConcurrency,
+			"bufferSize":     config.BufferSize,
+		}),
+
+		config: config,
+
+		concurrency: newAdaptiveConcurrency(config.MinConcurrency, config.MaxConcurrency, config.TargetLatency),
+	}
+}
+
+// Concurrency returns the current number of concurrent sync block workers
+func (pipeline *Pipeline) Concurrency() int {
+	return pipeline.concurrency.Limit()
+}
+
+// Sync synchronizes all the blocks from currentHeight to latestHeight. When the context is done, it
+// stops after handing over the current block and waits for the running workers.
+func (pipeline *Pipeline) Sync(
+	ctx context.Context,
+	currentHeight int64,
+	latestHeight int64,
+	worker SyncBlockWorker,
+	handler SyncedBlockHandler,
+) (SyncedHeight, error) {
+	workersCtx, cancelWorkers := context.WithCancel(ctx)
+	var workersWaitGroup sync.WaitGroup
+	defer func() {
+		cancelWorkers()
+		workersWaitGroup.Wait()
+	}()
+
+	// Running workers never exceed the buffer size, so sending the results never blocks
+	resultCh := make(chan pipelineResult, pipeline.config.BufferSize)
+	buffer := make(map[int64][]command.Command)
+	nextWorkerHeight := currentHeight
+	nextHandleHeight := currentHeight
+	runningWorkers := 0
+	// The lowest height failed after retries. The heights below it are still handed over.
+	var failedResult *pipelineResult
+
+	for nextHandleHeight <= latestHeight {
+		if failedResult != nil && nextHandleHeight == failedResult.height {
+			return nextHandleHeight - 1, failedResult.err
+		}
+
+		for failedResult == nil &&
+			nextWorkerHeight <= latestHeight &&
+			runningWorkers < pipeline.concurrency.Limit() &&
+			nextWorkerHeight-nextHandleHeight < int64(pipeline.config.BufferSize) {
+			height := nextWorkerHeight
+			workersWaitGroup.Add(1)
+			go func() {
+				defer workersWaitGroup.Done()
+
+				commands, err := pipeline.syncBlock(workersCtx, height, worker)
+				resultCh <- pipelineResult{height, commands, err}
+			}()
+			nextWorkerHeight += 1
+			runningWorkers += 1
+		}
+
+		var result pipelineResult
+		select {
+		case <-ctx.Done():
+		case result = <-resultCh:
+			runningWorkers -= 1
+		}
+		if ctx.Err() != nil {
+			pipeline.logger.Infof("stop synchronizing blocks before height %d", nextHandleHeight)
+			return nextHandleHeight - 1, nil
+		}
+		if result.err != nil {
+			if failedResult == nil || result.height < failedResult.height {
+				failedResult = &result
+			}
+			continue
+		}
+		buffer[result.height] = result.commands
+
+		for commands, ok := buffer[nextHandleHeight]; ok; commands, ok = buffer[nextHandleHeight] {
+			delete(buffer, nextHandleHeight)
+			if err := handler(nextHandleHeight, commands); err != nil {
+				return nextHandleHeight - 1, err
+			}
+			nextHandleHeight += 1
+		}
+	}
+
+	pipeline.logger.Debugf(
+		"synchronized blocks from %d to %d with concurrency %d and average latency %s",
+		currentHeight, latestHeight, pipeline.concurrency.Limit(), pipeline.concurrency.AverageLatency(),
+	)
+	return latestHeight, nil
+}
+
+// syncBlock runs the worker of the height, it is retried with exponential backoff until the
+// maximum retry time is elapsed or the context is done
+func (pipeline *Pipeline) syncBlock(
+	ctx context.Context,
+	height int64,
+	worker SyncBlockWorker,
+) ([]command.Command, error) {
+	var commands []command.Command
+	operation := func() error {
+		startTime := time.Now()
+		var err error
+		commands, err = worker(height)
+		if err != nil {
+			pipeline.concurrency.OnFailure()
+			return err
+		}
+		pipeline.concurrency.OnSuccess(time.Since(startTime))
+		return nil
+	}
+	notifyFn := func(err error, backoffDuration time.Duration) {
+		pipeline.logger.Errorf(
+			"error synchronizing block at height %d, retrying in %s: %v", height, backoffDuration.String(), err,
+		)
+	}
+
+	retryBackoff := backoff.NewExponentialBackOff()
+	retryBackoff.MaxInterval = pipeline.config.MaxRetryInterval
+	retryBackoff.MaxElapsedTime = pipeline.config.MaxRetryTime
+	if err := backoff.RetryNotify(operation, backoff.WithContext(retryBackoff, ctx), notifyFn); err != nil {
+		return nil, err
+	}
+
+	return commands, nil
+}
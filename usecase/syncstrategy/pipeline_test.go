@@ -0,0 +1,224 @@
+package syncstrategy_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/AstraProtocol/astra-indexing/entity/command"
+	"github.com/AstraProtocol/astra-indexing/external/logger/test"
+	"github.com/AstraProtocol/astra-indexing/usecase/syncstrategy"
+)
+
+var _ = Describe("Pipeline", func() {
+	var fakeLogger *test.FakeLogger
+
+	BeforeEach(func() {
+		fakeLogger = test.NewFakeLogger()
+	})
+
+	// Collects the heights handed over and counts the worker calls of every height
+	type recorder struct {
+		mutex         sync.Mutex
+		workerCalls   map[int64]int
+		handedHeights []int64
+	}
+	newRecorder := func() *recorder {
+		return &recorder{workerCalls: make(map[int64]int)}
+	}
+	handler := func(r *recorder) syncstrategy.SyncedBlockHandler {
+		return func(blockHeight int64, commands []command.Command) error {
+			r.handedHeights = append(r.handedHeights, blockHeight)
+			return nil
+		}
+	}
+	heightsBetween := func(from int64, to int64) []int64 {
+		heights := make([]int64, 0)
+		for height := from; height <= to; height += 1 {
+			heights = append(heights, height)
+		}
+		return heights
+	}
+
+	It("should hand over the blocks in order when the workers finish out of order", func() {
+		r := newRecorder()
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			time.Sleep(time.Duration(rand.Intn(3)) * time.Millisecond)
+			return []command.Command{}, nil
+		}
+
+		pipeline := syncstrategy.NewPipeline(fakeLogger, syncstrategy.PipelineConfig{
+			MaxConcurrency: 8,
+			BufferSize:     16,
+		})
+		syncedHeight, err := pipeline.Sync(context.Background(), 5, 104, worker, handler(r))
+
+		Expect(err).To(BeNil())
+		Expect(syncedHeight).To(Equal(int64(104)))
+		Expect(r.handedHeights).To(Equal(heightsBetween(5, 104)))
+	})
+
+	It("should retry the failed height only", func() {
+		r := newRecorder()
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			r.mutex.Lock()
+			r.workerCalls[blockHeight] += 1
+			calls := r.workerCalls[blockHeight]
+			r.mutex.Unlock()
+
+			if blockHeight == 3 && calls <= 2 {
+				return nil, errors.New("connection reset by peer")
+			}
+			return []command.Command{}, nil
+		}
+
+		pipeline := syncstrategy.NewPipeline(fakeLogger, syncstrategy.PipelineConfig{
+			MaxConcurrency:   4,
+			MaxRetryInterval: 10 * time.Millisecond,
+		})
+		syncedHeight, err := pipeline.Sync(context.Background(), 1, 10, worker, handler(r))
+
+		Expect(err).To(BeNil())
+		Expect(syncedHeight).To(Equal(int64(10)))
+		Expect(r.handedHeights).To(Equal(heightsBetween(1, 10)))
+		for height := int64(1); height <= 10; height += 1 {
+			if height == 3 {
+				Expect(r.workerCalls[height]).To(Equal(3))
+			} else {
+				Expect(r.workerCalls[height]).To(Equal(1))
+			}
+		}
+	})
+
+	It("should return the last handed height when a height keeps failing", func() {
+		r := newRecorder()
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			if blockHeight == 6 {
+				return nil, errors.New("block not found")
+			}
+			return []command.Command{}, nil
+		}
+
+		pipeline := syncstrategy.NewPipeline(fakeLogger, syncstrategy.PipelineConfig{
+			MaxConcurrency:   4,
+			MaxRetryInterval: 10 * time.Millisecond,
+			MaxRetryTime:     50 * time.Millisecond,
+		})
+		syncedHeight, err := pipeline.Sync(context.Background(), 1, 10, worker, handler(r))
+
+		Expect(err).To(MatchError("block not found"))
+		Expect(syncedHeight).To(Equal(int64(5)))
+		Expect(r.handedHeights).To(Equal(heightsBetween(1, 5)))
+	})
+
+	It("should stop at the first handler error", func() {
+		r := newRecorder()
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			return []command.Command{}, nil
+		}
+		failingHandler := func(blockHeight int64, commands []command.Command) error {
+			if blockHeight == 4 {
+				return errors.New("error handling events")
+			}
+			return handler(r)(blockHeight, commands)
+		}
+
+		pipeline := syncstrategy.NewPipeline(fakeLogger, syncstrategy.PipelineConfig{})
+		syncedHeight, err := pipeline.Sync(context.Background(), 1, 10, worker, failingHandler)
+
+		Expect(err).To(MatchError("error handling events"))
+		Expect(syncedHeight).To(Equal(int64(3)))
+		Expect(r.handedHeights).To(Equal(heightsBetween(1, 3)))
+	})
+
+	It("should stop handing over blocks when the context is done", func() {
+		r := newRecorder()
+		ctx, cancel := context.WithCancel(context.Background())
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			return []command.Command{}, nil
+		}
+		cancellingHandler := func(blockHeight int64, commands []command.Command) error {
+			if blockHeight == 3 {
+				cancel()
+			}
+			return handler(r)(blockHeight, commands)
+		}
+
+		pipeline := syncstrategy.NewPipeline(fakeLogger, syncstrategy.PipelineConfig{MaxConcurrency: 1})
+		syncedHeight, err := pipeline.Sync(ctx, 1, 10, worker, cancellingHandler)
+
+		Expect(err).To(BeNil())
+		Expect(syncedHeight).To(Equal(int64(3)))
+		Expect(r.handedHeights).To(Equal(heightsBetween(1, 3)))
+	})
+
+	It("should grow the concurrency up to the maximum when the workers are fast", func() {
+		r := newRecorder()
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			return []command.Command{}, nil
+		}
+
+		pipeline := syncstrategy.NewPipeline(fakeLogger, syncstrategy.PipelineConfig{
+			MinConcurrency: 1,
+			MaxConcurrency: 8,
+		})
+		Expect(pipeline.Concurrency()).To(Equal(1))
+
+		_, err := pipeline.Sync(context.Background(), 1, 100, worker, handler(r))
+
+		Expect(err).To(BeNil())
+		Expect(pipeline.Concurrency()).To(Equal(8))
+	})
+
+	It("should shrink the concurrency when the workers are slower than the target latency", func() {
+		r := newRecorder()
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			time.Sleep(5 * time.Millisecond)
+			return []command.Command{}, nil
+		}
+
+		pipeline := syncstrategy.NewPipeline(fakeLogger, syncstrategy.PipelineConfig{
+			MinConcurrency: 2,
+			MaxConcurrency: 8,
+			TargetLatency:  time.Millisecond,
+		})
+		_, err := pipeline.Sync(context.Background(), 1, 20, worker, handler(r))
+
+		Expect(err).To(BeNil())
+		Expect(pipeline.Concurrency()).To(Equal(2))
+	})
+
+	It("should shrink the concurrency on worker errors", func() {
+		r := newRecorder()
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			return []command.Command{}, nil
+		}
+
+		pipeline := syncstrategy.NewPipeline(fakeLogger, syncstrategy.PipelineConfig{
+			MinConcurrency:   1,
+			MaxConcurrency:   8,
+			MaxRetryInterval: time.Millisecond,
+		})
+		_, err := pipeline.Sync(context.Background(), 1, 100, worker, handler(r))
+		Expect(err).To(BeNil())
+		Expect(pipeline.Concurrency()).To(Equal(8))
+
+		failingWorker := func(blockHeight int64) ([]command.Command, error) {
+			r.mutex.Lock()
+			defer r.mutex.Unlock()
+			r.workerCalls[blockHeight] += 1
+			if r.workerCalls[blockHeight] <= 2 {
+				return nil, errors.New("too many requests")
+			}
+			return []command.Command{}, nil
+		}
+		_, err = pipeline.Sync(context.Background(), 101, 101, failingWorker, handler(r))
+		Expect(err).To(BeNil())
+		Expect(pipeline.Concurrency()).To(Equal(2))
+	})
+})
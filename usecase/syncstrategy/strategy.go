@@ -1,13 +1,26 @@
 package syncstrategy
 
 import (
+	"context"
+
 	"github.com/AstraProtocol/astra-indexing/entity/command"
 )
 
 type Strategy interface {
-	Sync(currentHeight int64, latestHeight int64, worker SyncBlockWorker) ([][]command.Command, SyncedHeight, error)
+	// Sync synchronizes the blocks from currentHeight up to latestHeight with the worker, and hands
+	// the commands of every block to the handler in height order. It returns the last height handed
+	// over successfully, the synchronization stops at the first handler error.
+	Sync(
+		ctx context.Context,
+		currentHeight int64,
+		latestHeight int64,
+		worker SyncBlockWorker,
+		handler SyncedBlockHandler,
+	) (SyncedHeight, error)
 }
 
 type SyncBlockWorker = func(blockHeight int64) ([]command.Command, error)
 
+type SyncedBlockHandler = func(blockHeight int64, commands []command.Command) error
+
 type SyncedHeight = int64
@@ -28,11 +28,15 @@ func NewWindow(logger applogger.Logger, size int) *Window {
 	}
 }
 
+// Sync synchronizes a single window starting from currentHeight, its blocks are handed over after
+// all of them are synchronized
 func (window *Window) Sync(
+	ctx context.Context,
 	currentHeight int64,
 	latestHeight int64,
 	worker SyncBlockWorker,
-) ([][]command.Command, SyncedHeight, error) {
+	handler SyncedBlockHandler,
+) (SyncedHeight, error) {
 	beginHeight := currentHeight
 	var endHeight int64
 	if (latestHeight - beginHeight + 1) < int64(window.size) {
@@ -47,7 +51,7 @@ func (window *Window) Sync(
 	})
 	logger.Debug("spawning goroutines for sync block workers")
 
-	workersErrGroup, _ := errgroup.WithContext(context.Background())
+	workersErrGroup, _ := errgroup.WithContext(ctx)
 
 	commandWindow := newUnsafeCommandWindow(beginHeight, endHeight)
 
@@ -68,10 +72,17 @@ func (window *Window) Sync(
 	}
 
 	if err := workersErrGroup.Wait(); err != nil {
-		return nil, beginHeight - 1, err
+		return beginHeight - 1, err
 	}
 
-	return commandWindow.Export(), endHeight, nil
+	for i, commands := range commandWindow.Export() {
+		height := beginHeight + int64(i)
+		if err := handler(height, commands); err != nil {
+			return height - 1, err
+		}
+	}
+
+	return endHeight, nil
 }
 
 // An concurrency-unsafe command window. Never use it in multiple goroutines.
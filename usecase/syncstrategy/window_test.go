@@ -0,0 +1,54 @@
+package syncstrategy_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/AstraProtocol/astra-indexing/entity/command"
+	"github.com/AstraProtocol/astra-indexing/external/logger/test"
+	"github.com/AstraProtocol/astra-indexing/usecase/syncstrategy"
+)
+
+var _ = Describe("Window", func() {
+	It("should hand over a window of blocks in order", func() {
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			return []command.Command{}, nil
+		}
+		handedHeights := make([]int64, 0)
+		handler := func(blockHeight int64, commands []command.Command) error {
+			handedHeights = append(handedHeights, blockHeight)
+			return nil
+		}
+
+		window := syncstrategy.NewWindow(test.NewFakeLogger(), 3)
+		syncedHeight, err := window.Sync(context.Background(), 1, 10, worker, handler)
+
+		Expect(err).To(BeNil())
+		Expect(syncedHeight).To(Equal(int64(3)))
+		Expect(handedHeights).To(Equal([]int64{1, 2, 3}))
+	})
+
+	It("should not hand over any block when a worker fails", func() {
+		worker := func(blockHeight int64) ([]command.Command, error) {
+			if blockHeight == 2 {
+				return nil, errors.New("block not found")
+			}
+			return []command.Command{}, nil
+		}
+		handedHeights := make([]int64, 0)
+		handler := func(blockHeight int64, commands []command.Command) error {
+			handedHeights = append(handedHeights, blockHeight)
+			return nil
+		}
+
+		window := syncstrategy.NewWindow(test.NewFakeLogger(), 3)
+		syncedHeight, err := window.Sync(context.Background(), 1, 10, worker, handler)
+
+		Expect(err).To(MatchError("block not found"))
+		Expect(syncedHeight).To(Equal(int64(0)))
+		Expect(handedHeights).To(BeEmpty())
+	})
+})